@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/actuator"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/analyzer"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"go.uber.org/zap"
+)
+
+// fakeDeployments is a DeploymentClient whose scales and restarts fail with
+// err, counting the restarts it was asked for
+type fakeDeployments struct {
+	restarts int
+	err      error
+}
+
+func (f *fakeDeployments) DeploymentReplicas(context.Context, string) (int32, error) { return 2, nil }
+func (f *fakeDeployments) ScaleDeployment(context.Context, string, int32) error      { return f.err }
+func (f *fakeDeployments) RestartDeployment(context.Context, string) error {
+	f.restarts++
+	return f.err
+}
+
+// oneActionSafeMode auto-remediates every service and allows a single
+// execution per window
+func oneActionSafeMode() *actuator.SafeMode {
+	cfg := &core.Config{}
+	cfg.ApplyDefaults()
+	cfg.Actuator.AutoRemediate = []string{"*"}
+	cfg.Actuator.MaxActions = 1
+	return actuator.NewSafeMode(core.NewConfigStore("", cfg))
+}
+
+func TestActuateChargesOnlyExecutedActions(t *testing.T) {
+	ctx := context.Background()
+	detection := &analyzer.Detection{Confidence: 90, DataSufficiency: analyzer.DataSufficiencyFull}
+	restart := &analyzer.ActuatorAction{ActionType: actuator.ActionRestart}
+	safeMode := oneActionSafeMode()
+
+	// Dry runs and failures leave the single slot free
+	dryRun := actuator.NewExecutor(&fakeDeployments{}, 10, true, zap.NewNop())
+	for i := 0; i < 3; i++ {
+		result, err := actuate(ctx, safeMode, dryRun, "checkout", "checkout", detection, restart)
+		if err != nil || result.Blocked || !result.DryRun || result.Executed {
+			t.Fatalf("dry run %d: result = %+v, err = %v, want an unblocked dry run", i, result, err)
+		}
+	}
+	failing := &fakeDeployments{err: errors.New("apiserver unavailable")}
+	for i := 0; i < 3; i++ {
+		if _, err := actuate(ctx, safeMode, actuator.NewExecutor(failing, 10, false, zap.NewNop()), "checkout", "checkout", detection, restart); err == nil {
+			t.Fatalf("failed restart %d: err = nil", i)
+		}
+	}
+	if failing.restarts != 3 {
+		t.Fatalf("restarts attempted = %d, want each failure to reach the cluster", failing.restarts)
+	}
+
+	// The first real execution uses it up
+	deployments := &fakeDeployments{}
+	executor := actuator.NewExecutor(deployments, 10, false, zap.NewNop())
+	result, err := actuate(ctx, safeMode, executor, "checkout", "checkout", detection, restart)
+	if err != nil || !result.Executed {
+		t.Fatalf("first execution: result = %+v, err = %v, want it executed", result, err)
+	}
+	result, err = actuate(ctx, safeMode, executor, "search", "search", detection, restart)
+	if err != nil || !result.Blocked || result.Executed {
+		t.Fatalf("second execution: result = %+v, err = %v, want it blocked by actuator.max_actions", result, err)
+	}
+	if deployments.restarts != 1 {
+		t.Errorf("restarts = %d, want the blocked action never to reach the cluster", deployments.restarts)
+	}
+}
+
+func TestActuateGuards(t *testing.T) {
+	ctx := context.Background()
+	restart := &analyzer.ActuatorAction{ActionType: actuator.ActionRestart}
+
+	tests := []struct {
+		name      string
+		detection *analyzer.Detection
+	}{
+		{"low confidence", &analyzer.Detection{Confidence: 40, DataSufficiency: analyzer.DataSufficiencyFull}},
+		{"partial data", &analyzer.Detection{Confidence: 90, DataSufficiency: analyzer.DataSufficiencyPartial}},
+		{"no detection", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployments := &fakeDeployments{}
+			executor := actuator.NewExecutor(deployments, 10, false, zap.NewNop())
+
+			result, err := actuate(ctx, oneActionSafeMode(), executor, "checkout", "checkout", tt.detection, restart)
+			if err != nil || !result.Blocked || result.Executed {
+				t.Fatalf("result = %+v, err = %v, want it blocked", result, err)
+			}
+			if deployments.restarts != 0 {
+				t.Errorf("restarts = %d, want none", deployments.restarts)
+			}
+		})
+	}
+}
+
+func TestActuateOnlyAutoRemediatesListedServices(t *testing.T) {
+	cfg := &core.Config{}
+	cfg.ApplyDefaults()
+	cfg.Actuator.AutoRemediate = []string{"checkout"}
+	safeMode := actuator.NewSafeMode(core.NewConfigStore("", cfg))
+
+	ctx := context.Background()
+	deployments := &fakeDeployments{}
+	executor := actuator.NewExecutor(deployments, 10, false, zap.NewNop())
+	restart := &analyzer.ActuatorAction{ActionType: actuator.ActionRestart, Confidence: 85, Reason: "memory climbing"}
+	diagnosis := &analyzer.UltimateDiagnosis{
+		PredictionID:     "pred-1",
+		PrimaryDetection: &analyzer.Detection{Type: analyzer.DetectionMemoryLeak, Confidence: 90, DataSufficiency: analyzer.DataSufficiencyFull},
+		HealthScore:      40,
+	}
+
+	listed, err := actuate(ctx, safeMode, executor, "checkout", "checkout", diagnosis.PrimaryDetection, restart)
+	if err != nil || !listed.Executed || listed.RecommendOnly {
+		t.Fatalf("listed service: result = %+v, err = %v, want it executed", listed, err)
+	}
+	unlisted, err := actuate(ctx, safeMode, executor, "search", "search", diagnosis.PrimaryDetection, restart)
+	if err != nil || unlisted.Executed || !unlisted.RecommendOnly {
+		t.Fatalf("unlisted service: result = %+v, err = %v, want a recommendation only", unlisted, err)
+	}
+	if deployments.restarts != 1 {
+		t.Errorf("restarts = %d, want only the listed service restarted", deployments.restarts)
+	}
+
+	executed := actionDecision("checkout", diagnosis, restart, listed)
+	if !executed.Executed || executed.ExecutedAt == nil || executed.ServiceName != "checkout" {
+		t.Errorf("listed decision = %+v, want it recorded as executed", executed)
+	}
+	recommended := actionDecision("search", diagnosis, restart, unlisted)
+	if recommended.Executed || recommended.ExecutedAt != nil || recommended.ServiceName != "search" ||
+		recommended.PatternDetected != "MEMORY_LEAK" || recommended.ActionType != actuator.ActionRestart {
+		t.Errorf("unlisted decision = %+v, want an unexecuted RESTART for MEMORY_LEAK on search", recommended)
+	}
+	if recommended.ExecutionResult != unlisted.Message {
+		t.Errorf("unlisted decision result = %q, want %q", recommended.ExecutionResult, unlisted.Message)
+	}
+}
+
+func TestActuateRecommendsByDefault(t *testing.T) {
+	cfg := &core.Config{}
+	cfg.ApplyDefaults()
+	deployments := &fakeDeployments{}
+
+	result, err := actuate(context.Background(), actuator.NewSafeMode(core.NewConfigStore("", cfg)),
+		actuator.NewExecutor(deployments, 10, false, zap.NewNop()), "checkout", "checkout",
+		&analyzer.Detection{Confidence: 90}, &analyzer.ActuatorAction{ActionType: actuator.ActionRestart})
+	if err != nil || !result.RecommendOnly || deployments.restarts != 0 {
+		t.Errorf("result = %+v, err = %v, restarts = %d, want recommend-only with an empty auto_remediate", result, err, deployments.restarts)
+	}
+}
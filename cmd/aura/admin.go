@@ -0,0 +1,116 @@
+package main
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/notify"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// alertChain builds the notifiers behind the maintenance gate: the log plus
+// the configured webhooks, annotated by the escalation policy
+func alertChain(cfg *core.Config) notify.Notifier {
+	return notify.NewEscalatingNotifier(
+		notify.NewMultiNotifier(append(
+			[]notify.Notifier{notify.NewLogNotifier(logger.Log)},
+			notify.NewWebhookNotifiers(cfg.Notifications.Webhooks, logger.Log)...,
+		)...),
+		notify.NewEscalationPolicy(cfg.Notifications.Escalation),
+	)
+}
+
+// transitionWebhooks builds the transition webhook notifier, or nil when none
+// are configured
+func transitionWebhooks(cfg *core.Config) notify.Notifier {
+	if webhooks := notify.NewWebhookNotifiers(cfg.Transitions.Webhooks, logger.Log); len(webhooks) > 0 {
+		return notify.NewMultiNotifier(webhooks...)
+	}
+	return nil
+}
+
+// requireAdminToken admits requests bearing the configured admin token. With
+// no token configured the admin API is disabled and answers 404.
+func requireAdminToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			respondError(c, http.StatusNotFound, errCodeNotFound, "route not found")
+			return
+		}
+
+		presented, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+			c.Header("WWW-Authenticate", `Bearer realm="aura-admin"`)
+			respondError(c, http.StatusUnauthorized, errCodeUnauthorized, "valid admin bearer token required")
+			return
+		}
+		c.Next()
+	}
+}
+
+// reloadConfigHandler re-reads the config file and swaps in its hot-reloadable
+// sections. An invalid file leaves the running config untouched.
+func reloadConfigHandler(store *core.ConfigStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result, err := store.Reload()
+		if err != nil {
+			logger.FromContext(c.Request.Context()).Warn("Config reload rejected", zap.Error(err))
+			respondError(c, http.StatusUnprocessableEntity, errCodeUnprocessable, err.Error())
+			return
+		}
+
+		logger.FromContext(c.Request.Context()).Info("🔄 Configuration reloaded",
+			zap.Strings("applied", result.Applied),
+			zap.Strings("restart_required", result.RestartRequired),
+		)
+
+		response := gin.H{
+			"applied":          result.Applied,
+			"restart_required": result.RestartRequired,
+			"timestamp":        time.Now().Format(time.RFC3339),
+		}
+		if len(result.RestartRequired) > 0 {
+			response["message"] = "changes to " + strings.Join(result.RestartRequired, ", ") +
+				" are read only at startup and were not applied; restart AURA to pick them up"
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// purgeServiceHandler deletes everything stored about a service, for test
+// cleanup and offboarding. The deletes share a transaction, so a failure
+// removes nothing.
+func purgeServiceHandler(db *storage.PostgresClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		serviceName := c.Param("service")
+
+		removed, err := db.PurgeService(c.Request.Context(), serviceName)
+		if err != nil {
+			logger.FromContext(c.Request.Context()).Error("Service purge failed", zap.String("service", serviceName), zap.Error(err))
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
+			return
+		}
+
+		var total int64
+		for _, n := range removed {
+			total += n
+		}
+		logger.FromContext(c.Request.Context()).Info("🗑️ Service data purged",
+			zap.String("service", serviceName),
+			zap.Int64("rows", total),
+		)
+
+		c.JSON(http.StatusOK, gin.H{
+			"service":   serviceName,
+			"removed":   removed,
+			"total":     total,
+			"timestamp": time.Now().Format(time.RFC3339),
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+)
+
+func TestRequireAdminToken(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         string
+		authorization string
+		wantStatus    int
+	}{
+		{name: "admin api disabled", token: "", authorization: "Bearer anything", wantStatus: http.StatusNotFound},
+		{name: "no credentials", token: "s3cret", wantStatus: http.StatusUnauthorized},
+		{name: "wrong token", token: "s3cret", authorization: "Bearer guess", wantStatus: http.StatusUnauthorized},
+		{name: "not a bearer token", token: "s3cret", authorization: "s3cret", wantStatus: http.StatusUnauthorized},
+		{name: "valid token", token: "s3cret", authorization: "Bearer s3cret", wantStatus: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.POST("/guarded", requireAdminToken(tt.token), func(c *gin.Context) {
+				c.Status(http.StatusNoContent)
+			})
+
+			headers := map[string]string{}
+			if tt.authorization != "" {
+				headers["Authorization"] = tt.authorization
+			}
+
+			w := serve(router, http.MethodPost, "/guarded", "", headers)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+				t.Error("401 without a WWW-Authenticate challenge")
+			}
+		})
+	}
+}
+
+func TestReloadConfigHandler(t *testing.T) {
+	t.Setenv("AURA_ADMIN_TOKEN", "")
+	path := filepath.Join(t.TempDir(), "aura.yaml")
+	writeFile := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	const base = "app:\n  name: AURA\n  version: test\n  log_level: info\ndatabase:\n  host: localhost\n  user: aura\n  dbname: aura_db\nprometheus:\n  url: http://localhost:9090\n"
+
+	writeFile(base)
+	cfg, err := core.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	store := core.NewConfigStore(path, cfg)
+
+	router := gin.New()
+	router.POST("/api/v1/admin/reload", requireAdminToken("s3cret"), reloadConfigHandler(store))
+	auth := map[string]string{"Authorization": "Bearer s3cret"}
+
+	writeFile(base + "analyzer:\n  cpu_threshold: 70\n")
+	w := serve(router, http.MethodPost, "/api/v1/admin/reload", "", auth)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Applied         []string `json:"applied"`
+		RestartRequired []string `json:"restart_required"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Applied) != 1 || resp.Applied[0] != "analyzer" || len(resp.RestartRequired) != 0 {
+		t.Errorf("response = %+v, want analyzer applied", resp)
+	}
+	if store.Get().Analyzer.CPUThreshold != 70 {
+		t.Errorf("cpu_threshold = %v, want 70 after reload", store.Get().Analyzer.CPUThreshold)
+	}
+
+	writeFile(base + "analyzer:\n  cpu_threshold: 150\n")
+	w = serve(router, http.MethodPost, "/api/v1/admin/reload", "", auth)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("invalid file: status = %d, want 422", w.Code)
+	}
+	if apiErr := decodeAPIError(t, w); apiErr.Code != errCodeUnprocessable {
+		t.Errorf("code = %s, want %s", apiErr.Code, errCodeUnprocessable)
+	}
+	if store.Get().Analyzer.CPUThreshold != 70 {
+		t.Error("an invalid file changed the running config")
+	}
+}
+
+func TestPurgeServiceHandler(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+
+	end := time.Now().Add(-time.Minute).Truncate(time.Second)
+	storagetest.Seed(t, db, storagetest.Series(service, "cpu_usage", end, 15*time.Second, 40, 42, 41))
+	if err := db.SaveEvent(context.Background(), &storage.Event{
+		Timestamp: end, EventType: storage.EventOOMKilled, PodName: service + "-7d9f-abc12", Namespace: "default",
+	}); err != nil {
+		t.Fatalf("SaveEvent: %v", err)
+	}
+
+	router := gin.New()
+	router.DELETE("/api/v1/services/:service", requireAdminToken("s3cret"), purgeServiceHandler(db))
+
+	if w := serve(router, http.MethodDelete, "/api/v1/services/"+service, "", nil); w.Code != http.StatusUnauthorized {
+		t.Fatalf("status without a token = %d, want 401", w.Code)
+	}
+
+	w := serve(router, http.MethodDelete, "/api/v1/services/"+service, "", map[string]string{"Authorization": "Bearer s3cret"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	var body struct {
+		Removed map[string]int64 `json:"removed"`
+		Total   int64            `json:"total"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.Removed["metrics"] != 3 || body.Removed["events"] != 1 || body.Removed["services"] != 1 {
+		t.Errorf("removed = %v, want 3 metrics, the pod's event and the service", body.Removed)
+	}
+	if body.Total != 5 {
+		t.Errorf("total = %d, want 5", body.Total)
+	}
+}
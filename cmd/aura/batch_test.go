@@ -0,0 +1,41 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/analyzer"
+)
+
+func TestAnalyzeBatchRejectsBadRequests(t *testing.T) {
+	router := gin.New()
+	router.POST("/api/v1/analyze/batch", analyzeBatchHandler(nil, nil))
+
+	tooMany := make([]string, analyzer.MaxBatchServices+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("svc-%d", i)
+	}
+	tooManyBody, _ := json.Marshal(map[string][]string{"services": tooMany})
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"empty list", `{"services":[]}`},
+		{"blank names", `{"services":["  ",""]}`},
+		{"too many services", string(tooManyBody)},
+		{"malformed json", `{"services":`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(router, http.MethodPost, "/api/v1/analyze/batch", tt.body, nil)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400: %s", w.Code, w.Body.String())
+			}
+			decodeAPIError(t, w)
+		})
+	}
+}
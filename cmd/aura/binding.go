@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+// fieldError describes a single problem with a request body field
+type fieldError struct {
+	Field string `json:"field"`
+	Error string `json:"error"`
+}
+
+// limitRequestBody wraps the request body of every route in
+// http.MaxBytesReader so oversized payloads fail while decoding instead of
+// being buffered in full. Routes in routeLimits, keyed by their path pattern,
+// get their own limit instead of the default one.
+func limitRequestBody(limit int64, routeLimits map[string]int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body != nil && c.Request.Body != http.NoBody {
+			routeLimit, ok := routeLimits[c.FullPath()]
+			if !ok {
+				routeLimit = limit
+			}
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, routeLimit)
+		}
+		c.Next()
+	}
+}
+
+// routeBodyLimits are the routes whose bodies may exceed http.max_body_bytes
+func routeBodyLimits(config *core.Config) map[string]int64 {
+	return map[string]int64{
+		"/api/v1/metrics/ingest":          ingestBodyLimit(config),
+		"/api/v1/prometheus/remote_write": max(maxRemoteWriteBodyBytes, config.HTTP.MaxBodyBytes),
+	}
+}
+
+// bindJSON strictly decodes the request body into T, rejecting unknown fields
+// and trailing data, then runs the struct's binding validation tags. On failure
+// it writes a 400 (or 413 for oversized bodies) and returns false.
+func bindJSON[T any](c *gin.Context) (T, bool) {
+	var req T
+
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&req); err != nil {
+		status, errs := describeDecodeError(err)
+		code := errCodeBadRequest
+		if status == http.StatusRequestEntityTooLarge {
+			code = errCodePayloadTooLarge
+		}
+		respondErrorDetails(c, status, code, "invalid request body", errs)
+		return req, false
+	}
+
+	if _, err := decoder.Token(); err != io.EOF {
+		respondError(c, http.StatusBadRequest, errCodeBadRequest, "request body must contain a single JSON object")
+		return req, false
+	}
+
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		respondErrorDetails(c, http.StatusBadRequest, errCodeValidation, "request validation failed", describeValidationError(err))
+		return req, false
+	}
+
+	return req, true
+}
+
+func describeDecodeError(err error) (int, []fieldError) {
+	var maxBytesErr *http.MaxBytesError
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return http.StatusRequestEntityTooLarge, []fieldError{{
+			Error: fmt.Sprintf("body exceeds %d bytes", maxBytesErr.Limit),
+		}}
+	case errors.As(err, &syntaxErr):
+		return http.StatusBadRequest, []fieldError{{
+			Error: fmt.Sprintf("malformed JSON at offset %d", syntaxErr.Offset),
+		}}
+	case errors.As(err, &typeErr):
+		return http.StatusBadRequest, []fieldError{{
+			Field: typeErr.Field,
+			Error: fmt.Sprintf("must be of type %s", typeErr.Type),
+		}}
+	case errors.Is(err, io.EOF):
+		return http.StatusBadRequest, []fieldError{{Error: "body is empty"}}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return http.StatusBadRequest, []fieldError{{Field: field, Error: "unknown field"}}
+	default:
+		return http.StatusBadRequest, []fieldError{{Error: err.Error()}}
+	}
+}
+
+func describeValidationError(err error) []fieldError {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return []fieldError{{Error: err.Error()}}
+	}
+
+	errs := make([]fieldError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		msg := fmt.Sprintf("failed on '%s'", fe.Tag())
+		if fe.Param() != "" {
+			msg = fmt.Sprintf("failed on '%s=%s'", fe.Tag(), fe.Param())
+		}
+		errs = append(errs, fieldError{Field: fe.Field(), Error: msg})
+	}
+	return errs
+}
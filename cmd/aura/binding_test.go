@@ -0,0 +1,95 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type bindTestRequest struct {
+	Service string  `json:"service" binding:"required"`
+	Value   float64 `json:"value"`
+}
+
+func newBindTestRouter(limit int64) *gin.Engine {
+	router := gin.New()
+	router.Use(limitRequestBody(limit, nil))
+	router.POST("/bind", func(c *gin.Context) {
+		req, ok := bindJSON[bindTestRequest](c)
+		if !ok {
+			return
+		}
+		c.JSON(http.StatusOK, req)
+	})
+	return router
+}
+
+func TestBindJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantCode   string
+		wantField  string
+	}{
+		{name: "valid", body: `{"service":"checkout","value":1.5}`, wantStatus: http.StatusOK},
+		{
+			name:       "oversized",
+			body:       `{"service":"` + strings.Repeat("a", 256) + `"}`,
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantCode:   errCodePayloadTooLarge,
+		},
+		{
+			name:       "unknown field",
+			body:       `{"service":"checkout","sevrity":"high"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   errCodeBadRequest,
+			wantField:  "sevrity",
+		},
+		{
+			name:       "wrong type",
+			body:       `{"service":"checkout","value":"high"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   errCodeBadRequest,
+			wantField:  "value",
+		},
+		{
+			name:       "trailing data",
+			body:       `{"service":"checkout"}{"service":"cart"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   errCodeBadRequest,
+		},
+		{
+			name:       "missing required field",
+			body:       `{"value":2}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   errCodeValidation,
+		},
+	}
+
+	router := newBindTestRouter(128)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(router, http.MethodPost, "/bind", tt.body, nil)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantCode == "" {
+				return
+			}
+
+			apiErr := decodeAPIError(t, w)
+			if apiErr.Code != tt.wantCode {
+				t.Errorf("code = %q, want %q", apiErr.Code, tt.wantCode)
+			}
+			if tt.wantField != "" {
+				details, _ := apiErr.Details.([]fieldError)
+				if len(details) == 0 || details[0].Field != tt.wantField {
+					t.Errorf("details = %+v, want field %q", apiErr.Details, tt.wantField)
+				}
+			}
+		})
+	}
+}
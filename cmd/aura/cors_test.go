@@ -0,0 +1,95 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newCORSRouter(origins ...string) *gin.Engine {
+	router := gin.New()
+	router.Use(cors(origins))
+	router.GET("/api/v1/services", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.DELETE("/api/v1/services/:service", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func TestCORSAllowedOrigin(t *testing.T) {
+	router := newCORSRouter("https://dash.example.com/")
+
+	w := serve(router, http.MethodGet, "/api/v1/services", "", map[string]string{"Origin": "https://Dash.example.com"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://Dash.example.com" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want the request origin", got)
+	}
+	if got := w.Header().Get("Access-Control-Expose-Headers"); got != corsExposeHeaders {
+		t.Errorf("Access-Control-Expose-Headers = %q, want %q", got, corsExposeHeaders)
+	}
+	if got := w.Header().Get("Vary"); got != "Origin" {
+		t.Errorf("Vary = %q, want Origin", got)
+	}
+}
+
+func TestCORSDisallowedOrigin(t *testing.T) {
+	router := newCORSRouter("https://dash.example.com")
+
+	w := serve(router, http.MethodGet, "/api/v1/services", "", map[string]string{"Origin": "https://evil.example.com"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d; a simple request is still served, only without CORS headers", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Access-Control-Allow-Origin = %q for a disallowed origin, want none", got)
+	}
+
+	w = serve(router, http.MethodOptions, "/api/v1/services", "", map[string]string{
+		"Origin":                        "https://evil.example.com",
+		"Access-Control-Request-Method": http.MethodGet,
+	})
+	if w.Code != http.StatusForbidden {
+		t.Errorf("preflight from a disallowed origin = %d, want 403", w.Code)
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	router := newCORSRouter("https://dash.example.com")
+
+	w := serve(router, http.MethodOptions, "/api/v1/services/checkout", "", map[string]string{
+		"Origin":                         "https://dash.example.com",
+		"Access-Control-Request-Method":  http.MethodDelete,
+		"Access-Control-Request-Headers": "authorization",
+	})
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://dash.example.com" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want the origin", got)
+	}
+	methods := w.Header().Get("Access-Control-Allow-Methods")
+	for _, m := range []string{http.MethodGet, http.MethodPost, http.MethodPatch, http.MethodDelete} {
+		if !strings.Contains(methods, m) {
+			t.Errorf("Access-Control-Allow-Methods = %q, want it to include %s", methods, m)
+		}
+	}
+	if got := w.Header().Get("Access-Control-Allow-Headers"); !strings.Contains(got, "Authorization") {
+		t.Errorf("Access-Control-Allow-Headers = %q, want it to include Authorization", got)
+	}
+	if w.Header().Get("Access-Control-Max-Age") == "" {
+		t.Error("preflight response lacks Access-Control-Max-Age")
+	}
+}
+
+func TestCORSWildcardIsOptIn(t *testing.T) {
+	w := serve(newCORSRouter(), http.MethodGet, "/api/v1/services", "", map[string]string{"Origin": "https://dash.example.com"})
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Access-Control-Allow-Origin = %q with no origins configured, want none", got)
+	}
+
+	w = serve(newCORSRouter("*"), http.MethodGet, "/api/v1/services", "", map[string]string{"Origin": "https://dash.example.com"})
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Access-Control-Allow-Origin = %q with \"*\" configured, want *", got)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetDecisionsRejectsBadFilters(t *testing.T) {
+	router := gin.New()
+	router.GET("/api/v1/decisions", getDecisionsHandler(nil))
+
+	for _, query := range []string{"executed=maybe", "window=soon", "window=-1h", "limit=0", "limit=501", "limit=ten"} {
+		w := serve(router, http.MethodGet, "/api/v1/decisions?"+query, "", nil)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", query, w.Code)
+			continue
+		}
+		if apiErr := decodeAPIError(t, w); apiErr.Code != errCodeBadRequest {
+			t.Errorf("%s: code = %s, want %s", query, apiErr.Code, errCodeBadRequest)
+		}
+	}
+}
+
+func TestUpdateDecisionValidation(t *testing.T) {
+	router := gin.New()
+	router.PATCH("/api/v1/decisions/:id", updateDecisionHandler(nil))
+
+	tests := []struct {
+		name string
+		path string
+		body string
+		code string
+	}{
+		{"non-numeric id", "/api/v1/decisions/abc", `{"executed":true}`, errCodeBadRequest},
+		{"executed missing", "/api/v1/decisions/7", `{"result":"done"}`, errCodeValidation},
+		{"malformed body", "/api/v1/decisions/7", `{"executed":`, errCodeBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(router, http.MethodPatch, tt.path, tt.body, nil)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400: %s", w.Code, w.Body.String())
+			}
+			if apiErr := decodeAPIError(t, w); apiErr.Code != tt.code {
+				t.Errorf("code = %s, want %s", apiErr.Code, tt.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/analyzer"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// staleDiagnosis is a last-known diagnosis served while the database is down
+type staleDiagnosis struct {
+	*analyzer.UltimateDiagnosis
+	Stale    bool      `json:"stale"`
+	CachedAt time.Time `json:"cached_at"`
+}
+
+// staleComposite is the composite of a cached diagnosis, marked like
+// staleDiagnosis
+type staleComposite struct {
+	*analyzer.CompositeDiagnosis
+	Stale    bool      `json:"stale"`
+	CachedAt time.Time `json:"cached_at"`
+}
+
+// lastKnownOnOutage returns the cached diagnosis of a service after a live
+// diagnosis failed, but only when the failure is the database being
+// unreachable; any other error is reported as-is
+func lastKnownOnOutage(ctx context.Context, db *storage.PostgresClient, ua *analyzer.UltimateAnalyzer, serviceName string) (*analyzer.LastKnownDiagnosis, bool) {
+	// The request context may already be spent by the failed diagnosis
+	if err := db.Health(context.WithoutCancel(ctx)); err == nil {
+		return nil, false
+	}
+
+	lastKnown, ok := ua.LastKnownDiagnosis(serviceName)
+	if ok {
+		logger.FromContext(ctx).Warn("Database unavailable, serving last-known diagnosis",
+			zap.String("service", serviceName),
+			zap.Time("cached_at", lastKnown.CachedAt),
+		)
+	}
+	return lastKnown, ok
+}
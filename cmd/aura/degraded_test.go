@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/analyzer"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+	"go.uber.org/zap"
+)
+
+// TestDiagnoseServesLastKnownWhileDatabaseDown diagnoses a service, takes
+// the database away and checks the last diagnosis is served marked stale
+func TestDiagnoseServesLastKnownWhileDatabaseDown(t *testing.T) {
+	service := storagetest.Service(t, storagetest.NewClient(t))
+
+	// A client of its own, closed mid-test to simulate the outage
+	db, err := storage.NewPostgresClient(storagetest.URL(t), zap.NewNop())
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(db.Close)
+
+	end := time.Now().Add(-time.Minute)
+	values := make([]float64, 30)
+	for i := range values {
+		values[i] = 50
+	}
+	storagetest.Seed(t, db, storagetest.Series(service, "cpu_usage", end, 30*time.Second, values...))
+	storagetest.Seed(t, db, storagetest.Series(service, "memory_usage", end, 30*time.Second, values...))
+
+	config := &core.Config{}
+	config.Analyzer.DiagnosisCacheTTL = "1ns" // every request diagnoses afresh
+	config.ApplyDefaults()
+	ua := analyzer.NewUltimateAnalyzer(db, core.NewConfigStore("", config))
+
+	router := gin.New()
+	router.GET("/api/v1/ai/diagnose/:service", aiDiagnoseServiceHandler(ua, db))
+
+	type diagnosisResponse struct {
+		Service  string `json:"service"`
+		Stale    bool   `json:"stale"`
+		CachedAt string `json:"cached_at"`
+	}
+	diagnose := func(service string) (int, diagnosisResponse) {
+		w := serve(router, http.MethodGet, "/api/v1/ai/diagnose/"+service, "", nil)
+		var resp diagnosisResponse
+		if w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+		}
+		return w.Code, resp
+	}
+
+	code, live := diagnose(service)
+	if code != http.StatusOK || live.Stale || live.CachedAt != "" {
+		t.Fatalf("live diagnosis: status %d, %+v, want a fresh 200", code, live)
+	}
+
+	db.Close()
+
+	code, stale := diagnose(service)
+	if code != http.StatusOK {
+		t.Fatalf("status = %d while the database is down, want 200 from the last-known diagnosis", code)
+	}
+	if !stale.Stale || stale.Service != service {
+		t.Errorf("response = %+v, want the last-known diagnosis marked stale", stale)
+	}
+	if _, err := time.Parse(time.RFC3339, stale.CachedAt); err != nil {
+		t.Errorf("cached_at %q is not a timestamp: %v", stale.CachedAt, err)
+	}
+
+	// Nothing cached for a service never diagnosed: the outage is an error
+	if code, _ := diagnose(service + "-unseen"); code != http.StatusInternalServerError {
+		t.Errorf("unseen service: status = %d, want 500", code)
+	}
+}
+
+func TestStaleCompositeMarksTheComposite(t *testing.T) {
+	cachedAt := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	body, err := json.Marshal(staleComposite{
+		CompositeDiagnosis: &analyzer.CompositeDiagnosis{
+			ServiceName: "checkout",
+			Root:        &analyzer.CompositeIssue{Type: analyzer.DetectionMemoryLeak, Confidence: 81},
+			Narrative:   "Likely root cause: MEMORY_LEAK (81.0% confidence).",
+		},
+		Stale:    true,
+		CachedAt: cachedAt,
+	})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	var resp struct {
+		ServiceName string `json:"service_name"`
+		Root        struct {
+			Type string `json:"type"`
+		} `json:"root"`
+		Stale    bool      `json:"stale"`
+		CachedAt time.Time `json:"cached_at"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if resp.ServiceName != "checkout" || resp.Root.Type != "MEMORY_LEAK" || !resp.Stale || !resp.CachedAt.Equal(cachedAt) {
+		t.Errorf("response = %s, want the composite's fields alongside stale and cached_at", body)
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/analyzer"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+func TestGetDetectorsCountsEnabled(t *testing.T) {
+	cfg := &core.Config{}
+	cfg.Analyzer.DisabledDetectors = []string{"external_failure"}
+	cfg.ApplyDefaults()
+	ua := analyzer.NewUltimateAnalyzer(nil, core.NewConfigStore("", cfg))
+
+	router := gin.New()
+	router.GET("/api/v1/detectors", getDetectorsHandler(ua))
+
+	w := serve(router, http.MethodGet, "/api/v1/detectors?service=checkout", "", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Service   string                  `json:"service"`
+		Detectors []analyzer.DetectorInfo `json:"detectors"`
+		Count     int                     `json:"count"`
+		Enabled   int                     `json:"enabled"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Service != "checkout" || resp.Count != len(resp.Detectors) {
+		t.Errorf("service/count = %q/%d, want checkout/%d", resp.Service, resp.Count, len(resp.Detectors))
+	}
+	if resp.Enabled != resp.Count-1 {
+		t.Errorf("enabled = %d, want %d with one detector disabled", resp.Enabled, resp.Count-1)
+	}
+	for _, d := range resp.Detectors {
+		if d.Name == "external_failure" && d.Enabled {
+			t.Error("external_failure listed as enabled")
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetDiagnosesRejectsBadLimit(t *testing.T) {
+	router := gin.New()
+	router.GET("/api/v1/diagnoses/:service", getDiagnosesHandler(nil))
+
+	for _, limit := range []string{"0", "-5", "501", "all"} {
+		w := serve(router, http.MethodGet, "/api/v1/diagnoses/checkout?version=1&limit="+limit, "", nil)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("limit=%s: status = %d, want 400", limit, w.Code)
+			continue
+		}
+		if apiErr := decodeAPIError(t, w); apiErr.Code != errCodeBadRequest {
+			t.Errorf("limit=%s: code = %s, want %s", limit, apiErr.Code, errCodeBadRequest)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Error codes carried in APIError.Code. Clients should branch on these rather
+// than on messages.
+const (
+	errCodeBadRequest          = "BAD_REQUEST"
+	errCodeUnauthorized        = "UNAUTHORIZED"
+	errCodeValidation          = "VALIDATION_FAILED"
+	errCodePayloadTooLarge     = "PAYLOAD_TOO_LARGE"
+	errCodeNotFound            = "NOT_FOUND"
+	errCodeConflict            = "CONFLICT"
+	errCodeUnprocessable       = "UNPROCESSABLE"
+	errCodeInternal            = "INTERNAL_ERROR"
+	errCodeUpstreamError       = "UPSTREAM_ERROR"
+	errCodeUpstreamUnavailable = "UPSTREAM_UNAVAILABLE"
+	errCodeUpstreamTimeout     = "UPSTREAM_TIMEOUT"
+)
+
+// APIError is the body of every error response. The message stays under the
+// "error" key so clients reading the old ad-hoc responses keep working.
+type APIError struct {
+	Code      string      `json:"code"`
+	Message   string      `json:"error"`
+	Details   interface{} `json:"details,omitempty"`
+	RequestID string      `json:"request_id,omitempty"`
+}
+
+// respondError aborts the request with an APIError
+func respondError(c *gin.Context, status int, code, message string) {
+	respondErrorDetails(c, status, code, message, nil)
+}
+
+// respondErrorDetails aborts the request with an APIError carrying details
+func respondErrorDetails(c *gin.Context, status int, code, message string, details interface{}) {
+	c.AbortWithStatusJSON(status, APIError{
+		Code:      code,
+		Message:   message,
+		Details:   details,
+		RequestID: c.GetString("request_id"),
+	})
+}
+
+// recoverPanics turns a handler panic into a 500 APIError. It runs after
+// ginLogger so the request id is already assigned and the 500 is logged.
+func recoverPanics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.FromContext(c.Request.Context()).Error("Handler panic",
+					zap.String("path", c.Request.URL.Path),
+					zap.Any("panic", r),
+					zap.ByteString("stack", debug.Stack()),
+				)
+				if c.Writer.Written() {
+					c.Abort()
+					return
+				}
+				respondError(c, http.StatusInternalServerError, errCodeInternal,
+					fmt.Sprintf("internal error (request id %s)", c.GetString("request_id")))
+			}
+		}()
+		c.Next()
+	}
+}
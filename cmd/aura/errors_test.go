@@ -0,0 +1,122 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/pkg/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRecoverPanics(t *testing.T) {
+	router := gin.New()
+	router.Use(ginLogger(), recoverPanics())
+	router.GET("/boom", func(c *gin.Context) {
+		var m map[string]int
+		m["boom"]++ // assignment to a nil map
+	})
+
+	w := serve(router, http.MethodGet, "/boom", "", map[string]string{"X-Request-ID": "req-123"})
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", w.Code)
+	}
+	apiErr := decodeAPIError(t, w)
+	if apiErr.Code != errCodeInternal {
+		t.Errorf("code = %s, want %s", apiErr.Code, errCodeInternal)
+	}
+	if apiErr.RequestID != "req-123" || !strings.Contains(apiErr.Message, "req-123") {
+		t.Errorf("request id = %q, message = %q; want both to carry req-123", apiErr.RequestID, apiErr.Message)
+	}
+	if got := w.Header().Get("X-Request-ID"); got != "req-123" {
+		t.Errorf("X-Request-ID = %q, want req-123", got)
+	}
+}
+
+func TestHandlerLogsCarryRequestID(t *testing.T) {
+	observed, logs := observer.New(zapcore.DebugLevel)
+	previous := logger.Log
+	logger.Log = zap.New(observed)
+	t.Cleanup(func() { logger.Log = previous })
+
+	config := &core.Config{}
+	config.ApplyDefaults()
+	router := gin.New()
+	router.Use(ginLogger())
+	router.POST("/api/v1/metrics/ingest", ingestMetricsHandler(storage.NewMetricBuffer(nil, 100, 100, time.Minute), config))
+
+	body := `[{"service":"checkout","metric":"cpu_usage","value":42}]`
+	w := serve(router, http.MethodPost, "/api/v1/metrics/ingest", body, map[string]string{"X-Request-ID": "req-456"})
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202: %s", w.Code, w.Body.String())
+	}
+
+	for _, msg := range []string{"Ingested pushed metrics", "HTTP Request"} {
+		entries := logs.FilterMessage(msg).All()
+		if len(entries) != 1 {
+			t.Errorf("%d %q lines logged, want 1", len(entries), msg)
+			continue
+		}
+		if got := entries[0].ContextMap()["request_id"]; got != "req-456" {
+			t.Errorf("%q request_id = %v, want req-456", msg, got)
+		}
+	}
+}
+
+func TestRecoverPanicsAfterWrite(t *testing.T) {
+	router := gin.New()
+	router.Use(recoverPanics())
+	router.GET("/partial", func(c *gin.Context) {
+		c.String(http.StatusOK, "partial")
+		panic("late failure")
+	})
+
+	w := serve(router, http.MethodGet, "/partial", "", nil)
+	if w.Code != http.StatusOK || w.Body.String() != "partial" {
+		t.Errorf("response = %d %q; a panic after the response is written must leave it alone", w.Code, w.Body.String())
+	}
+}
+
+func TestErrorResponses(t *testing.T) {
+	router := gin.New()
+	router.Use(ginLogger(), recoverPanics())
+	router.GET("/metrics/:service/history", getMetricHistoryHandler(nil))
+	router.GET("/missing", func(c *gin.Context) {
+		respondError(c, http.StatusNotFound, errCodeNotFound, "service checkout not found")
+	})
+	router.GET("/upstream", func(c *gin.Context) {
+		respondError(c, http.StatusServiceUnavailable, errCodeUpstreamUnavailable, "database unavailable")
+	})
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantCode   string
+	}{
+		{"bad input", "/metrics/checkout/history?duration=forever", http.StatusBadRequest, errCodeBadRequest},
+		{"not found", "/missing", http.StatusNotFound, errCodeNotFound},
+		{"upstream down", "/upstream", http.StatusServiceUnavailable, errCodeUpstreamUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(router, http.MethodGet, tt.path, "", nil)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			apiErr := decodeAPIError(t, w)
+			if apiErr.Code != tt.wantCode {
+				t.Errorf("code = %s, want %s", apiErr.Code, tt.wantCode)
+			}
+			if apiErr.RequestID == "" {
+				t.Error("error response lacks a request id")
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/analyzer"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// diagnosisETag identifies what a diagnosis of the service would be computed
+// from: its newest recorded sample and the detector version. It is "" when
+// the service isn't registered or the lookup fails, which disables caching.
+func diagnosisETag(ctx context.Context, db *storage.PostgresClient, serviceName string) string {
+	lastSeen, err := db.GetServiceLastSeen(ctx, serviceName)
+	if err != nil {
+		logger.FromContext(ctx).Debug("Skipping diagnosis ETag", zap.String("service", serviceName), zap.Error(err))
+		return ""
+	}
+	if lastSeen.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf(`W/"%s-%d-%s"`, serviceName, lastSeen.UnixNano(), analyzer.DetectorVersion)
+}
+
+// checkNotModified sets the ETag header and, when the request's
+// If-None-Match already names it, answers 304 so the caller can skip the
+// diagnosis. Comparison is weak, as for any GET.
+func checkNotModified(c *gin.Context, etag string) bool {
+	if etag == "" {
+		return false
+	}
+	c.Header("ETag", etag)
+
+	for _, candidate := range strings.Split(c.GetHeader("If-None-Match"), ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
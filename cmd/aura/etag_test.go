@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/analyzer"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+)
+
+func TestCheckNotModified(t *testing.T) {
+	const etag = `W/"checkout-1700000000000000000-4"`
+	tests := []struct {
+		name        string
+		etag        string
+		ifNoneMatch string
+		want        bool
+	}{
+		{"no If-None-Match", etag, "", false},
+		{"matching", etag, etag, true},
+		{"strong form of the weak tag", etag, `"checkout-1700000000000000000-4"`, true},
+		{"one of several", etag, `W/"old", ` + etag, true},
+		{"wildcard", etag, "*", true},
+		{"stale tag", etag, `W/"checkout-1600000000000000000-4"`, false},
+		{"no etag disables caching", "", "*", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/diagnose", func(c *gin.Context) {
+				if checkNotModified(c, tt.etag) {
+					return
+				}
+				c.Status(http.StatusOK)
+			})
+
+			headers := map[string]string{}
+			if tt.ifNoneMatch != "" {
+				headers["If-None-Match"] = tt.ifNoneMatch
+			}
+			w := serve(router, http.MethodGet, "/diagnose", "", headers)
+
+			wantStatus := http.StatusOK
+			if tt.want {
+				wantStatus = http.StatusNotModified
+			}
+			if w.Code != wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, wantStatus)
+			}
+			if got := w.Header().Get("ETag"); got != tt.etag {
+				t.Errorf("ETag header = %q, want %q", got, tt.etag)
+			}
+		})
+	}
+}
+
+func TestDiagnosisETagFollowsNewSamples(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+
+	if etag := diagnosisETag(ctx, db, service); etag != "" {
+		t.Errorf("ETag of an unregistered service = %q, want none", etag)
+	}
+
+	end := time.Now().Add(-time.Minute).Truncate(time.Second)
+	storagetest.Seed(t, db, storagetest.Series(service, "cpu_usage", end, 15*time.Second, 40, 41))
+	first := diagnosisETag(ctx, db, service)
+	if !strings.HasPrefix(first, `W/"`+service) || !strings.HasSuffix(first, "-"+analyzer.DetectorVersion+`"`) {
+		t.Errorf("ETag = %q, want a weak tag of the service and detector version", first)
+	}
+	if again := diagnosisETag(ctx, db, service); again != first {
+		t.Errorf("ETag changed without new samples: %q then %q", first, again)
+	}
+
+	storagetest.Seed(t, db, storagetest.Series(service, "cpu_usage", end.Add(15*time.Second), time.Second, 42))
+	if next := diagnosisETag(ctx, db, service); next == first {
+		t.Error("ETag did not change after a newer sample")
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestEventHandlersRejectBadQueries(t *testing.T) {
+	router := gin.New()
+	router.GET("/api/v1/kubernetes/events", getEventsHandler(nil))
+	router.GET("/api/v1/kubernetes/events/:podname", getPodEventsHandler(nil))
+	router.GET("/api/v1/anomalies", getAnomaliesHandler(nil))
+
+	queries := []string{
+		"duration=soon",
+		"duration=-1h",
+		"limit=0",
+		"limit=501",
+		"limit=ten",
+		"cursor=not-a-cursor",
+	}
+	for _, path := range []string{"/api/v1/kubernetes/events", "/api/v1/kubernetes/events/web-7d9f", "/api/v1/anomalies"} {
+		for _, query := range queries {
+			t.Run(path+"?"+query, func(t *testing.T) {
+				w := serve(router, http.MethodGet, path+"?"+query, "", nil)
+				if w.Code != http.StatusBadRequest {
+					t.Fatalf("status = %d, want 400", w.Code)
+				}
+				if apiErr := decodeAPIError(t, w); apiErr.Code != errCodeBadRequest {
+					t.Errorf("code = %q, want %q", apiErr.Code, errCodeBadRequest)
+				}
+			})
+		}
+	}
+}
+
+func TestHealthTimelineRejectsBadDuration(t *testing.T) {
+	router := gin.New()
+	router.GET("/api/v1/health/:service/timeline", healthTimelineHandler(nil))
+
+	for _, duration := range []string{"soon", "-1h", "0s", "169h"} {
+		w := serve(router, http.MethodGet, "/api/v1/health/checkout/timeline?duration="+duration, "", nil)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("duration=%s: status = %d, want 400", duration, w.Code)
+		}
+	}
+}
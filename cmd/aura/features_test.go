@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/analyzer"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+)
+
+func newFeaturesRouter(db *storage.PostgresClient) *gin.Engine {
+	config := &core.Config{}
+	config.ApplyDefaults()
+	ua := analyzer.NewUltimateAnalyzer(db, core.NewConfigStore("", config))
+
+	router := gin.New()
+	router.GET("/api/v1/features/:service", getFeaturesHandler(ua))
+	return router
+}
+
+func TestGetFeaturesRejectsBadWindow(t *testing.T) {
+	router := newFeaturesRouter(nil)
+
+	for _, window := range []string{"soon", "-5m", "0s"} {
+		w := serve(router, http.MethodGet, "/api/v1/features/checkout?window="+window, "", nil)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("window %q: status = %d, want 400", window, w.Code)
+			continue
+		}
+		if apiErr := decodeAPIError(t, w); apiErr.Code != errCodeBadRequest {
+			t.Errorf("window %q: code = %s, want %s", window, apiErr.Code, errCodeBadRequest)
+		}
+	}
+}
+
+// TestGetFeaturesSeededSeries seeds a climbing CPU and a steady memory series
+// and checks the key fields of the vector land in plausible ranges
+func TestGetFeaturesSeededSeries(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	router := newFeaturesRouter(db)
+
+	end := time.Now().Add(-time.Minute)
+	cpu := make([]float64, 40)
+	memory := make([]float64, 40)
+	for i := range cpu {
+		cpu[i] = 20 + 1.5*float64(i) // 3%/min over 20 minutes
+		memory[i] = 60 + float64(i%2)
+	}
+	storagetest.Seed(t, db, storagetest.Series(service, "cpu_usage", end, 30*time.Second, cpu...))
+	storagetest.Seed(t, db, storagetest.Series(service, "memory_usage", end, 30*time.Second, memory...))
+
+	w := serve(router, http.MethodGet, "/api/v1/features/"+service+"?window=30m", "", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Service        string                    `json:"service"`
+		Window         string                    `json:"window"`
+		Features       *analyzer.ServiceFeatures `json:"features"`
+		PeriodLength   string                    `json:"period_length"`
+		TrendDirection string                    `json:"trend_direction"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Service != service || resp.Window != "30m0s" || resp.Features == nil {
+		t.Fatalf("response = %+v, want features for %s over 30m0s", resp, service)
+	}
+
+	f := resp.Features
+	if f.CPUMin != 20 || f.CPUMax != 78.5 {
+		t.Errorf("cpu min/max = %.1f/%.1f, want 20/78.5", f.CPUMin, f.CPUMax)
+	}
+	if f.CPUTrend < 2.5 || f.CPUTrend > 3.5 {
+		t.Errorf("cpu trend = %.2f%%/min, want ~3", f.CPUTrend)
+	}
+	if f.MemoryMean < 60 || f.MemoryMean > 61 {
+		t.Errorf("memory mean = %.2f, want within [60, 61]", f.MemoryMean)
+	}
+	if resp.TrendDirection != "increasing" || !f.HasTrend {
+		t.Errorf("trend = %q (has_trend %v), want increasing", resp.TrendDirection, f.HasTrend)
+	}
+	if _, err := time.ParseDuration(resp.PeriodLength); err != nil {
+		t.Errorf("period_length %q is not a duration: %v", resp.PeriodLength, err)
+	}
+	for name, score := range map[string]float64{
+		"health_score":         f.HealthScore,
+		"system_stress":        f.SystemStress,
+		"predictability_score": f.PredictabilityScore,
+	} {
+		if score < 0 || score > 100 {
+			t.Errorf("%s = %.1f, want within [0, 100]", name, score)
+		}
+	}
+	if f.CPUMemoryCorr < -1 || f.CPUMemoryCorr > 1 {
+		t.Errorf("cpu_memory_corr = %.2f, want within [-1, 1]", f.CPUMemoryCorr)
+	}
+}
+
+func TestFeatureDriftRejectsBadDays(t *testing.T) {
+	router := gin.New()
+	router.GET("/api/v1/features/:service/drift", getFeatureDriftHandler(nil))
+
+	for _, days := range []string{"0", "366", "two"} {
+		w := serve(router, http.MethodGet, "/api/v1/features/checkout/drift?days="+days, "", nil)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("days=%s: status = %d, want 400", days, w.Code)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// maxIngestClockSkew is how far in the future a pushed timestamp may be
+const maxIngestClockSkew = 5 * time.Minute
+
+// ingestItemBytes is the body size budgeted per item of an ingest batch,
+// room for a sample with a dozen labels
+const ingestItemBytes = 2 << 10 // 2 KiB
+
+// ingestBodyLimit caps ingest bodies so a batch of ingest.max_items fits,
+// never below http.max_body_bytes
+func ingestBodyLimit(config *core.Config) int64 {
+	return max(int64(config.Ingest.MaxItems)*ingestItemBytes, config.HTTP.MaxBodyBytes)
+}
+
+// ingestItem is one pushed sample. Timestamp defaults to the time of receipt.
+type ingestItem struct {
+	Service   string            `json:"service" binding:"required,max=100"`
+	Metric    string            `json:"metric" binding:"required,max=100"`
+	Value     *float64          `json:"value" binding:"required"`
+	Timestamp *time.Time        `json:"timestamp"`
+	Labels    map[string]string `json:"labels" binding:"max=32"`
+}
+
+// ingestItemError is a problem with one item of an ingest batch
+type ingestItemError struct {
+	Index int    `json:"index"`
+	Field string `json:"field,omitempty"`
+	Error string `json:"error"`
+}
+
+// ingestMetricsHandler accepts a JSON array of samples from push-based
+// sources. Valid items are queued on the metric buffer; invalid ones are
+// reported by index, so one bad item doesn't reject the whole batch.
+func ingestMetricsHandler(buffer *storage.MetricBuffer, config *core.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var items []json.RawMessage
+		decoder := json.NewDecoder(c.Request.Body)
+		if err := decoder.Decode(&items); err != nil {
+			status, errs := describeDecodeError(err)
+			code := errCodeBadRequest
+			if status == http.StatusRequestEntityTooLarge {
+				code = errCodePayloadTooLarge
+			}
+			respondErrorDetails(c, status, code, "request body must be a JSON array of metrics", errs)
+			return
+		}
+		if _, err := decoder.Token(); err != io.EOF {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "request body must contain a single JSON array")
+			return
+		}
+		if len(items) == 0 {
+			respondError(c, http.StatusBadRequest, errCodeValidation, "request body contains no metrics")
+			return
+		}
+		if len(items) > config.Ingest.MaxItems {
+			respondError(c, http.StatusRequestEntityTooLarge, errCodePayloadTooLarge,
+				fmt.Sprintf("batch has %d items, at most %d are accepted per request", len(items), config.Ingest.MaxItems))
+			return
+		}
+
+		now := time.Now()
+		metrics := make([]*storage.Metric, 0, len(items))
+		var itemErrs []ingestItemError
+		for i, raw := range items {
+			metric, errs := parseIngestItem(raw, now)
+			for _, fe := range errs {
+				itemErrs = append(itemErrs, ingestItemError{Index: i, Field: fe.Field, Error: fe.Error})
+			}
+			if metric != nil {
+				metrics = append(metrics, metric)
+			}
+		}
+
+		if len(metrics) == 0 {
+			respondErrorDetails(c, http.StatusBadRequest, errCodeValidation, "no valid metrics in batch", itemErrs)
+			return
+		}
+
+		if err := buffer.Add(metrics); err != nil {
+			if errors.Is(err, storage.ErrBufferFull) {
+				c.Header("Retry-After", "1")
+			}
+			respondError(c, http.StatusServiceUnavailable, errCodeUpstreamUnavailable, err.Error())
+			return
+		}
+
+		logger.FromContext(c.Request.Context()).Debug("Ingested pushed metrics",
+			zap.Int("accepted", len(metrics)),
+			zap.Int("rejected", len(items)-len(metrics)),
+		)
+
+		c.JSON(http.StatusAccepted, gin.H{
+			"accepted":  len(metrics),
+			"rejected":  len(items) - len(metrics),
+			"errors":    itemErrs,
+			"timestamp": now.Format(time.RFC3339),
+		})
+	}
+}
+
+// parseIngestItem strictly decodes and validates one item. It returns nil
+// and the problems found when the item can't be accepted.
+func parseIngestItem(raw json.RawMessage, now time.Time) (*storage.Metric, []fieldError) {
+	var item ingestItem
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&item); err != nil {
+		_, errs := describeDecodeError(err)
+		return nil, errs
+	}
+	if err := binding.Validator.ValidateStruct(&item); err != nil {
+		return nil, describeValidationError(err)
+	}
+
+	timestamp := now
+	if item.Timestamp != nil {
+		if item.Timestamp.After(now.Add(maxIngestClockSkew)) {
+			return nil, []fieldError{{Field: "timestamp", Error: "is in the future"}}
+		}
+		timestamp = *item.Timestamp
+	}
+
+	labels := map[string]string{"source": "ingest"}
+	for k, v := range item.Labels {
+		labels[k] = v
+	}
+	encoded, err := json.Marshal(labels)
+	if err != nil {
+		return nil, []fieldError{{Field: "labels", Error: err.Error()}}
+	}
+
+	return &storage.Metric{
+		Timestamp:   timestamp,
+		ServiceName: item.Service,
+		MetricName:  item.Metric,
+		MetricValue: *item.Value,
+		Labels:      encoded,
+	}, nil
+}
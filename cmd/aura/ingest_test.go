@@ -0,0 +1,214 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+)
+
+type ingestResponse struct {
+	Accepted int               `json:"accepted"`
+	Rejected int               `json:"rejected"`
+	Errors   []ingestItemError `json:"errors"`
+}
+
+// newIngestRouter serves the ingest endpoint over an unflushed buffer, so
+// accepted metrics stay pending for inspection
+func newIngestRouter(capacity int) (*gin.Engine, *storage.MetricBuffer) {
+	config := &core.Config{}
+	config.ApplyDefaults()
+	config.Ingest.MaxItems = 10
+
+	buffer := storage.NewMetricBuffer(nil, 100, capacity, time.Minute)
+	router := gin.New()
+	router.POST("/api/v1/metrics/ingest", ingestMetricsHandler(buffer, config))
+	return router, buffer
+}
+
+func decodeIngestResponse(t *testing.T, body []byte) ingestResponse {
+	t.Helper()
+
+	var resp ingestResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		t.Fatalf("decode response %q: %v", body, err)
+	}
+	return resp
+}
+
+func TestIngestValidBatch(t *testing.T) {
+	router, buffer := newIngestRouter(100)
+
+	body := `[
+		{"service":"checkout","metric":"cpu_usage","value":42.5,"timestamp":"2026-01-01T12:00:00Z"},
+		{"service":"checkout","metric":"memory_usage","value":61,"labels":{"pod":"checkout-7d9f"}},
+		{"service":"payments","metric":"error_rate","value":0}
+	]`
+	w := serve(router, http.MethodPost, "/api/v1/metrics/ingest", body, nil)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202: %s", w.Code, w.Body.String())
+	}
+	if resp := decodeIngestResponse(t, w.Body.Bytes()); resp.Accepted != 3 || resp.Rejected != 0 || len(resp.Errors) != 0 {
+		t.Errorf("response = %+v, want all 3 accepted", resp)
+	}
+	if pending := buffer.Stats().Pending; pending != 3 {
+		t.Errorf("buffer holds %d metrics, want 3", pending)
+	}
+}
+
+func TestIngestPartialFailure(t *testing.T) {
+	router, buffer := newIngestRouter(100)
+
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	body := `[
+		{"service":"checkout","metric":"cpu_usage","value":42.5},
+		{"service":"checkout","metric":"cpu_usage"},
+		{"service":"checkout","metric":"cpu_usage","value":1,"colour":"red"},
+		{"service":"checkout","metric":"cpu_usage","value":1,"timestamp":"` + future + `"},
+		{"service":"` + strings.Repeat("s", 101) + `","metric":"cpu_usage","value":1}
+	]`
+	w := serve(router, http.MethodPost, "/api/v1/metrics/ingest", body, nil)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202: %s", w.Code, w.Body.String())
+	}
+
+	resp := decodeIngestResponse(t, w.Body.Bytes())
+	if resp.Accepted != 1 || resp.Rejected != 4 {
+		t.Errorf("accepted %d, rejected %d; want 1 and 4", resp.Accepted, resp.Rejected)
+	}
+	wantFields := map[int]string{1: "value", 3: "timestamp", 4: "service"}
+	rejected := make(map[int]bool)
+	for _, e := range resp.Errors {
+		rejected[e.Index] = true
+		if want, ok := wantFields[e.Index]; ok && !strings.EqualFold(e.Field, want) {
+			t.Errorf("item %d: error on %q, want %q", e.Index, e.Field, want)
+		}
+	}
+	for _, i := range []int{1, 2, 3, 4} {
+		if !rejected[i] {
+			t.Errorf("item %d has no error reported", i)
+		}
+	}
+	if pending := buffer.Stats().Pending; pending != 1 {
+		t.Errorf("buffer holds %d metrics, want only the valid one", pending)
+	}
+}
+
+func TestIngestRejectsBatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		code   string
+	}{
+		{"not an array", `{"service":"checkout"}`, http.StatusBadRequest, errCodeBadRequest},
+		{"trailing data", `[{"service":"checkout","metric":"cpu_usage","value":1}] []`, http.StatusBadRequest, errCodeBadRequest},
+		{"empty", `[]`, http.StatusBadRequest, errCodeValidation},
+		{"every item invalid", `[{"metric":"cpu_usage","value":1}]`, http.StatusBadRequest, errCodeValidation},
+		{"too many items", "[" + strings.TrimSuffix(strings.Repeat(`{"service":"a","metric":"b","value":1},`, 11), ",") + "]", http.StatusRequestEntityTooLarge, errCodePayloadTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, buffer := newIngestRouter(100)
+			w := serve(router, http.MethodPost, "/api/v1/metrics/ingest", tt.body, nil)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.status, w.Body.String())
+			}
+			var apiErr APIError
+			if err := json.Unmarshal(w.Body.Bytes(), &apiErr); err != nil || apiErr.Code != tt.code {
+				t.Errorf("code = %q (%v), want %s", apiErr.Code, err, tt.code)
+			}
+			if pending := buffer.Stats().Pending; pending != 0 {
+				t.Errorf("buffer holds %d metrics after a rejected batch", pending)
+			}
+		})
+	}
+}
+
+func TestIngestBufferFull(t *testing.T) {
+	router, _ := newIngestRouter(2)
+
+	body := fmt.Sprintf("[%s]", strings.TrimSuffix(strings.Repeat(`{"service":"a","metric":"b","value":1},`, 3), ","))
+	w := serve(router, http.MethodPost, "/api/v1/metrics/ingest", body, nil)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("no Retry-After on a full buffer")
+	}
+}
+
+func TestIngestAcceptsFullBatchOverDefaultBodyLimit(t *testing.T) {
+	config := &core.Config{}
+	config.ApplyDefaults()
+
+	buffer := storage.NewMetricBuffer(nil, 100, config.Ingest.BufferCapacity, time.Minute)
+	router := gin.New()
+	router.Use(limitRequestBody(config.HTTP.MaxBodyBytes, routeBodyLimits(config)))
+	router.POST("/api/v1/metrics/ingest", ingestMetricsHandler(buffer, config))
+	router.POST("/other", func(c *gin.Context) {
+		if _, ok := bindJSON[bindTestRequest](c); ok {
+			c.Status(http.StatusOK)
+		}
+	})
+
+	items := make([]string, config.Ingest.MaxItems)
+	for i := range items {
+		items[i] = fmt.Sprintf(`{"service":"checkout-service","metric":"http_request_duration_seconds","value":%d.25,`+
+			`"timestamp":"2026-01-01T12:00:00Z","labels":{"pod":"checkout-service-7d9f8c6b5-x2k4q","namespace":"production",`+
+			`"container":"checkout","instance":"10.244.3.17:8080","job":"kubernetes-pods","region":"eu-west-1"}}`, i)
+	}
+	body := "[" + strings.Join(items, ",") + "]"
+	if int64(len(body)) <= config.HTTP.MaxBodyBytes {
+		t.Fatalf("body of %d bytes fits http.max_body_bytes; the test needs a larger one", len(body))
+	}
+
+	w := serve(router, http.MethodPost, "/api/v1/metrics/ingest", body, nil)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202: %.200s", w.Code, w.Body.String())
+	}
+	if resp := decodeIngestResponse(t, w.Body.Bytes()); resp.Accepted != config.Ingest.MaxItems {
+		t.Errorf("accepted = %d, want all %d", resp.Accepted, config.Ingest.MaxItems)
+	}
+
+	// Other routes keep the default limit
+	if w := serve(router, http.MethodPost, "/other", body, nil); w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status on another route = %d, want 413", w.Code)
+	}
+}
+
+func TestParseIngestItemTimestamp(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	metric, errs := parseIngestItem([]byte(`{"service":"checkout","metric":"cpu_usage","value":3}`), now)
+	if len(errs) != 0 {
+		t.Fatalf("errors = %v", errs)
+	}
+	if !metric.Timestamp.Equal(now) {
+		t.Errorf("timestamp = %v, want the time of receipt %v", metric.Timestamp, now)
+	}
+
+	metric, errs = parseIngestItem([]byte(`{"service":"checkout","metric":"cpu_usage","value":3,"timestamp":"2026-01-01T11:59:00Z","labels":{"pod":"a"}}`), now)
+	if len(errs) != 0 {
+		t.Fatalf("errors = %v", errs)
+	}
+	if want := now.Add(-time.Minute); !metric.Timestamp.Equal(want) {
+		t.Errorf("timestamp = %v, want the pushed %v", metric.Timestamp, want)
+	}
+	var labels map[string]string
+	if err := json.Unmarshal(metric.Labels, &labels); err != nil || labels["source"] != "ingest" || labels["pod"] != "a" {
+		t.Errorf("labels = %s, want the pushed labels plus source=ingest", metric.Labels)
+	}
+
+	// A little clock skew is tolerated
+	skewed := now.Add(maxIngestClockSkew - time.Second).Format(time.RFC3339)
+	if _, errs := parseIngestItem([]byte(`{"service":"checkout","metric":"cpu_usage","value":3,"timestamp":"`+skewed+`"}`), now); len(errs) != 0 {
+		t.Errorf("timestamp within the allowed skew rejected: %v", errs)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+)
+
+func newInjectRouter(db *storage.PostgresClient, enabled bool) *gin.Engine {
+	config := &core.Config{}
+	config.Testing.Enabled = enabled
+
+	router := gin.New()
+	router.POST("/api/v1/test/inject", injectMetricsHandler(db, config))
+	return router
+}
+
+func TestInjectMetricsDisabled(t *testing.T) {
+	router := newInjectRouter(nil, false)
+
+	body := `{"service":"checkout","metric":"memory_usage","points":[{"ts":"2026-01-01T12:00:00Z","value":50}]}`
+	w := serve(router, http.MethodPost, "/api/v1/test/inject", body, nil)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404 while testing mode is off", w.Code)
+	}
+	if apiErr := decodeAPIError(t, w); apiErr.Code != errCodeNotFound {
+		t.Errorf("code = %s, want %s", apiErr.Code, errCodeNotFound)
+	}
+}
+
+func TestInjectMetricsValidation(t *testing.T) {
+	router := newInjectRouter(nil, true)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"no points", `{"service":"checkout","metric":"memory_usage","points":[]}`},
+		{"missing service", `{"metric":"memory_usage","points":[{"ts":"2026-01-01T12:00:00Z","value":50}]}`},
+		{"missing value", `{"service":"checkout","metric":"memory_usage","points":[{"ts":"2026-01-01T12:00:00Z"}]}`},
+		{"missing timestamp", `{"service":"checkout","metric":"memory_usage","points":[{"value":50}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(router, http.MethodPost, "/api/v1/test/inject", tt.body, nil)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400", w.Code)
+			}
+			if apiErr := decodeAPIError(t, w); apiErr.Code != errCodeValidation {
+				t.Errorf("code = %s, want %s", apiErr.Code, errCodeValidation)
+			}
+		})
+	}
+}
+
+// TestInjectMetricsStoresCurve injects a memory climb and reads it back
+func TestInjectMetricsStoresCurve(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	router := newInjectRouter(db, true)
+
+	end := time.Now().Add(-time.Minute).Truncate(time.Second)
+	points := make([]string, 20)
+	for i := range points {
+		ts := end.Add(time.Duration(i-len(points)+1) * 30 * time.Second)
+		points[i] = fmt.Sprintf(`{"ts":%q,"value":%d}`, ts.Format(time.RFC3339), 50+2*i)
+	}
+	body := fmt.Sprintf(`{"service":%q,"metric":"memory_usage","points":[%s]}`, service, strings.Join(points, ","))
+
+	w := serve(router, http.MethodPost, "/api/v1/test/inject", body, nil)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201: %s", w.Code, w.Body.String())
+	}
+
+	stored, err := db.GetRecentMetrics(context.Background(), service, "memory_usage", time.Hour)
+	if err != nil {
+		t.Fatalf("GetRecentMetrics: %v", err)
+	}
+	if len(stored) != len(points) {
+		t.Fatalf("stored %d samples, want %d", len(stored), len(points))
+	}
+	for i, m := range stored {
+		if want := float64(50 + 2*i); m.MetricValue != want {
+			t.Errorf("sample %d = %v, want %v", i, m.MetricValue, want)
+		}
+	}
+}
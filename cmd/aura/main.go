@@ -2,19 +2,31 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/actuator"
 	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/analyzer"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/analyzer/backtest"
 	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/eventbus"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/notify"
 	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/observer"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/report"
 	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
 	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 )
@@ -32,17 +44,29 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err := logger.Initialize(config.App.LogLevel); err != nil {
+	if err := logger.Initialize(config.App.LogLevel, config.App.LogFormat, config.App.LogSampling); err != nil {
 		fmt.Printf("Logger init failed: %v\n", err)
 		os.Exit(1)
 	}
 	defer logger.Sync()
 
-	db, err := storage.NewPostgresClient(config.GetDatabaseURL(), logger.Log)
+	db, err := storage.NewPostgresClientWithPool(config.GetDatabaseURL(), poolOptions(config), logger.Log)
 	if err != nil {
 		logger.Fatal("Database connection failed", zap.Error(err))
 	}
 	defer db.Close()
+	if config.Database.ReadReplicaURL != "" {
+		if err := db.ConnectReadReplica(config.Database.ReadReplicaURL, poolOptions(config)); err != nil {
+			logger.Fatal("Read replica connection failed", zap.Error(err))
+		}
+		logger.Info("Read queries routed to the read replica")
+	}
+	db.SetLabelLimits(storage.LabelLimits{
+		MaxKeys:  config.Storage.MaxLabelKeys,
+		MaxBytes: config.Storage.MaxLabelBytes,
+		Allowed:  config.Storage.LabelAllowlist,
+	})
+	prometheus.MustRegister(db.PoolCollectors()...)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -51,29 +75,122 @@ func main() {
 		logger.Fatal("Database health check failed", zap.Error(err))
 	}
 
-	k8sNamespace := config.Kubernetes.Namespace
-	if k8sNamespace == "" {
-		k8sNamespace = "default"
-	}
+	k8sNamespaces := config.WatchedNamespaces()
 
+	scrapeInterval, _ := time.ParseDuration(config.Prometheus.ScrapeInterval) // validated in LoadConfig
 	metricsObserver, err := observer.NewMetricsObserver(
 		config.Prometheus.URL,
-		10*time.Second,
-		k8sNamespace,
+		scrapeInterval,
+		k8sNamespaces,
 		db,
 		logger.Log,
 	) //metriObserver start kardiya here
 	if err != nil {
 		logger.Fatal("Metrics observer init failed", zap.Error(err))
 	}
+	if len(config.Prometheus.MetricIntervals) > 0 {
+		intervals := make(map[string]time.Duration, len(config.Prometheus.MetricIntervals))
+		for metric, interval := range config.Prometheus.MetricIntervals {
+			intervals[metric], _ = time.ParseDuration(interval) // validated in LoadConfig
+		}
+		metricsObserver.Prometheus().SetMetricIntervals(intervals)
+	}
+	scrapeTimeout, _ := time.ParseDuration(config.Prometheus.ScrapeTimeout) // validated in LoadConfig
+	queryTimeout, _ := time.ParseDuration(config.Prometheus.QueryTimeout)
+	metricsObserver.Prometheus().SetTimeouts(scrapeTimeout, queryTimeout)
+
+	if config.Kubernetes.ResourceMetricsInterval != "" {
+		interval, _ := time.ParseDuration(config.Kubernetes.ResourceMetricsInterval) // validated in LoadConfig
+		for _, watcher := range metricsObserver.KubernetesWatchers() {
+			watcher.SetResourceMetricsInterval(interval)
+		}
+	}
 
 	// Initialize AI-Level Ultimate Analyzer
-	ultimateAnalyzer := analyzer.NewUltimateAnalyzer(db)
+	// Analyzer components read the config through the store so that
+	// POST /api/v1/admin/reload reaches them
+	configStore := core.NewConfigStore(configPath, config)
+	ultimateAnalyzer := analyzer.NewUltimateAnalyzer(db, configStore)
+	ultimateAnalyzer.SetDependencyQuerier(metricsObserver.Prometheus())
+	ensembleAnalyzer := analyzer.NewEnsembleAnalyzer(ultimateAnalyzer, configStore)
+
+	// Actuator executes SCALE_UP/RESTART against the cluster; a nil client
+	// makes every execution fail cleanly when Kubernetes is unavailable
+	var deploymentClient actuator.DeploymentClient
+	if watcher := metricsObserver.Kubernetes(); watcher != nil {
+		deploymentClient = watcher
+	}
+	executor := actuator.NewExecutor(deploymentClient, config.Kubernetes.MaxReplicas, config.Decision.DryRun, logger.Log)
 	logger.Info("🤖 AI-Level Ultimate Analyzer initialized successfully")
 
 	observerCtx, observerCancel := context.WithCancel(context.Background())
 	defer observerCancel()
 
+	// CrashLoop events flow watcher -> bus -> responder so the observer never imports the analyzer
+	bus := eventbus.New(logger.Log)
+	// Maintenance windows are checked first so suppressed notifications never escalate
+	alerts := notify.NewSwappableNotifier(alertChain(config))
+	maintenanceGate := notify.NewMaintenanceGate(alerts, config.Maintenance, logger.Log)
+	var notifier notify.Notifier = maintenanceGate
+	crashLoopResponder := analyzer.NewCrashLoopResponder(ultimateAnalyzer, notifier)
+	for _, watcher := range metricsObserver.KubernetesWatchers() {
+		watcher.SetEventBus(bus)
+	}
+	go crashLoopResponder.Run(observerCtx, bus.Subscribe(eventbus.EventCrashLoop, 32))
+
+	stalenessMonitor := analyzer.NewStalenessMonitor(db, notifier, configStore)
+	go stalenessMonitor.Run(observerCtx)
+
+	// Continuous outlier monitoring, independent of the pattern detectors
+	anomalyScanner := analyzer.NewAnomalyScanner(db, notifier, configStore)
+	go anomalyScanner.Run(observerCtx)
+
+	incidentTracker := analyzer.NewIncidentTracker(db, notifier, configStore)
+	if err := incidentTracker.Load(observerCtx); err != nil {
+		logger.Warn("Failed to load open incidents", zap.Error(err))
+	}
+	go incidentTracker.Run(observerCtx)
+
+	// Periodic feature snapshots feed the drift endpoint
+	go ultimateAnalyzer.RunFeatureSnapshots(observerCtx)
+
+	// Daily summaries of incidents, MTTR, health and SLO burn
+	reportGenerator := analyzer.NewReportGenerator(db, notifier, configStore)
+	go reportGenerator.Run(observerCtx)
+
+	// Executed decisions are re-checked to confirm the problem cleared
+	recoveryVerifier := analyzer.NewRecoveryVerifier(ultimateAnalyzer, configStore)
+	go recoveryVerifier.Run(observerCtx)
+
+	// Transition webhooks bypass the notifier chain: they are callbacks into
+	// other systems, not alerts for people
+	transitionNotifier := notify.NewSwappableNotifier(transitionWebhooks(config))
+	transitionTracker := analyzer.NewTransitionTracker(db, transitionNotifier, configStore)
+
+	// Notifier chains are built from the config, so rebuild them on reload
+	configStore.OnReload(func(cfg *core.Config) {
+		alerts.Swap(alertChain(cfg))
+		maintenanceGate.SetConfigured(cfg.Maintenance)
+		transitionNotifier.Swap(transitionWebhooks(cfg))
+	})
+	if err := transitionTracker.Load(observerCtx); err != nil {
+		logger.Warn("Failed to load service severities", zap.Error(err))
+	}
+
+	flushInterval, _ := time.ParseDuration(config.Ingest.FlushInterval) // validated in LoadConfig
+	metricBuffer := storage.NewMetricBuffer(db, config.Ingest.BatchSize, config.Ingest.BufferCapacity, flushInterval)
+	if config.Storage.WriteResolution != "" {
+		writeResolution, _ := time.ParseDuration(config.Storage.WriteResolution) // validated in LoadConfig
+		metricBuffer.SetWriteResolution(writeResolution)
+	}
+	go metricBuffer.Run(observerCtx)
+
+	probeCtx, cancelProbe := context.WithTimeout(observerCtx, 5*time.Second)
+	if status := metricsObserver.Probe(probeCtx); !status.Degraded() {
+		logger.Info("Prometheus reachable", zap.String("url", config.Prometheus.URL))
+	}
+	cancelProbe()
+
 	// Start metrics observer which internally starts both Prometheus and Kubernetes watchers
 	go func() {
 		if err := metricsObserver.Start(observerCtx); err != nil && err != context.Canceled {
@@ -83,7 +200,7 @@ func main() {
 
 	// Log Kubernetes watcher status
 	if config.Kubernetes.Enabled {
-		logger.Info("Kubernetes watcher initialized and started", zap.String("namespace", k8sNamespace))
+		logger.Info("Kubernetes watcher initialized and started", zap.Strings("namespaces", k8sNamespaces))
 	} else {
 		logger.Info("Kubernetes watcher disabled in config")
 	}
@@ -95,29 +212,103 @@ func main() {
 	}
 
 	router := gin.New()
-	router.Use(gin.Recovery(), ginLogger())
+	router.Use(ginLogger(), recoverPanics(), cors(config.HTTP.AllowedOrigins), limitRequestBody(config.HTTP.MaxBodyBytes, routeBodyLimits(config)))
+	router.NoRoute(func(c *gin.Context) {
+		respondError(c, http.StatusNotFound, errCodeNotFound, "route not found")
+	})
 
 	router.GET("/health", healthHandler(db, config))
-	router.GET("/ready", readyHandler(db))
+	router.GET("/ready", readyHandler(db, metricsObserver, metricBuffer, config))
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
 	v1 := router.Group("/api/v1")
 	{
-		v1.GET("/status", statusHandler(config))
+		v1.GET("/status", statusHandler(config, ultimateAnalyzer))
+		v1.GET("/status/system", systemStatusHandler(db))
+		v1.GET("/storage/pool", poolStatsHandler(db))
+		v1.GET("/services", servicesOverviewHandler(db, ultimateAnalyzer))
+		v1.GET("/trend/:service/:metric", getTrendHandler(db, ultimateAnalyzer))
+
+		// Persisted ultimate diagnoses
+		v1.GET("/ultimate/diagnose/:service", ultimateDiagnoseHandler(ultimateAnalyzer, db, incidentTracker, transitionTracker))
+		v1.GET("/ultimate/:prediction_id", getUltimateDiagnosisHandler(db))
+		v1.GET("/diagnoses/:service", getDiagnosesHandler(db))
+		v1.GET("/groups/:group/analyze", analyzeGroupHandler(ultimateAnalyzer))
+		v1.GET("/triage", triageHandler(db, ultimateAnalyzer))
+		v1.POST("/analyze/batch", analyzeBatchHandler(db, ultimateAnalyzer))
+		v1.GET("/analyze/:service", ultimateDiagnoseHandler(ultimateAnalyzer, db, incidentTracker, transitionTracker))
+
+		// Advanced diagnosis
+		v1.GET("/advanced/compare/full", compareServicesFullHandler(ultimateAnalyzer))
+		v1.GET("/advanced/health/:service", healthScoreHandler(ultimateAnalyzer, db, incidentTracker, transitionTracker))
+		v1.GET("/health/:service/timeline", healthTimelineHandler(ultimateAnalyzer))
+
+		// Repeated diagnoses grouped into incidents
+		v1.GET("/incidents", getIncidentsHandler(db))
+		v1.GET("/incidents/:id/postmortem", postmortemHandler(analyzer.NewPostmortemBuilder(db, configStore)))
+		v1.GET("/transitions", getTransitionsHandler(db))
+		v1.GET("/reports/daily", dailyReportHandler(reportGenerator))
+
+		// Raw feature vector behind every detection
+		v1.GET("/features/:service", getFeaturesHandler(ultimateAnalyzer))
+		v1.GET("/features/:service/drift", getFeatureDriftHandler(ultimateAnalyzer))
+
+		// Chart overlay: deployments, incidents and high-severity diagnoses
+		v1.GET("/annotations/:service", getAnnotationsHandler(db))
+		v1.POST("/deployments", recordDeploymentHandler(db))
+
+		// Operator notes, returned with every diagnosis while active
+		v1.GET("/services/:service/notes", getServiceNotesHandler(db))
+		v1.POST("/services/:service/notes", createServiceNoteHandler(db))
+		v1.DELETE("/services/:service/notes/:id", deleteServiceNoteHandler(db))
+
+		// Maintenance windows (notification suppression)
+		v1.GET("/maintenance", getMaintenanceHandler(maintenanceGate))
+		v1.POST("/maintenance", requireAdminToken(config.Admin.Token), createMaintenanceHandler(maintenanceGate))
+
+		// Synthetic metric injection for integration tests (404 unless testing.enabled)
+		v1.POST("/test/inject", injectMetricsHandler(db, config))
+
+		// Services that stopped reporting metrics
+		v1.GET("/detect/stale", getStaleServicesHandler(stalenessMonitor))
+
+		// Outliers recorded by the background anomaly scan
+		v1.GET("/anomalies", getAnomaliesHandler(db))
+
+		// Detector catalog with the thresholds in effect
+		v1.GET("/detectors", getDetectorsHandler(ultimateAnalyzer))
+
+		// GC pause behaviour from latency bimodality
+		v1.GET("/detect/gc-pressure/:service", detectGCPressureHandler(ultimateAnalyzer))
+
+		// Classic + enhanced detector ensemble
+		v1.GET("/ensemble/:service", ensembleHandler(ensembleAnalyzer))
+
+		// Replay a diagnosis over historical data
+		v1.GET("/replay/:service", replayHandler(ultimateAnalyzer))
+
+		// Detector backtesting against labeled cases
+		v1.POST("/backtest", runBacktestHandler(backtest.NewRunner(ultimateAnalyzer), db))
+		v1.GET("/backtest/runs", getBacktestRunsHandler(db))
+
+		// Actuator endpoints
+		v1.POST("/actuator/:service/execute", requireAdminToken(config.Admin.Token), executeActionHandler(ultimateAnalyzer, executor, actuator.NewSafeMode(configStore), db))
 
 		// Metrics endpoints
-		v1.GET("/metrics/:service", getServiceMetricsHandler(db))
+		v1.GET("/metrics/:service", getServiceMetricsHandler(ultimateAnalyzer.FeatureExtractor().Resolver()))
 		v1.GET("/metrics/:service/:metric/stats", getMetricStatsHandler(db))
 		v1.GET("/metrics/:service/history", getMetricHistoryHandler(db))
 		v1.GET("/metrics/services", getAllServicesHandler(db))
+		v1.POST("/metrics/ingest", ingestMetricsHandler(metricBuffer, config))
 
 		// Decision endpoints
 		v1.GET("/decisions", getDecisionsHandler(db))
 		v1.GET("/decisions/stats", getDecisionStatsHandler(db))
 		v1.GET("/decisions/:id", getDecisionByIdHandler(db))
+		v1.PATCH("/decisions/:id", updateDecisionHandler(db))
 
 		// Observer endpoints
-		v1.GET("/observer/health", observerHealthHandler())
+		v1.GET("/observer/health", observerHealthHandler(metricsObserver))
 		v1.GET("/observer/metrics", observerMetricsHandler(metricsObserver))
 
 		// Kubernetes endpoints
@@ -127,18 +318,27 @@ func main() {
 		v1.GET("/kubernetes/events", getEventsHandler(db))
 		v1.GET("/kubernetes/events/:podname", getPodEventsHandler(db))
 		v1.GET("/kubernetes/namespace/summary", getNamespaceSummaryHandler(metricsObserver, db))
+		v1.GET("/kubernetes/crashloops", getCrashLoopsHandler(crashLoopResponder))
 
 		// Prometheus endpoints
 		v1.GET("/prometheus/health", prometheusHealthHandler(metricsObserver))
 		v1.GET("/prometheus/targets", prometheusTargetsHandler(metricsObserver))
 		v1.GET("/prometheus/query", prometheusQueryHandler(metricsObserver))
 		v1.GET("/prometheus/metrics/summary", prometheusMetricsSummaryHandler(db))
+		v1.POST("/prometheus/remote_write", remoteWriteHandler(metricBuffer, config))
+
+		// Administration (404 unless admin.token is set)
+		admin := v1.Group("/admin", requireAdminToken(config.Admin.Token))
+		{
+			admin.POST("/reload", reloadConfigHandler(configStore))
+		}
+		v1.DELETE("/services/:service", requireAdminToken(config.Admin.Token), purgeServiceHandler(db))
 
 		// 🤖 AI-Level Ultimate Analyzer Endpoints (The ONLY analyzer - production ready!)
 		ai := v1.Group("/ai")
 		{
 			// Ultimate diagnosis - comprehensive AI analysis
-			ai.GET("/diagnose/:service", aiDiagnoseServiceHandler(ultimateAnalyzer))
+			ai.GET("/diagnose/:service", aiDiagnoseServiceHandler(ultimateAnalyzer, db))
 
 			// Feature extraction - see all 60+ features
 			ai.GET("/features/:service", aiGetFeaturesHandler(ultimateAnalyzer))
@@ -180,6 +380,7 @@ func main() {
 
 	srv.Shutdown(shutdownCtx)
 	observerCancel()
+	metricBuffer.Flush(shutdownCtx)
 	db.Close()
 }
 
@@ -229,7 +430,11 @@ func healthHandler(db *storage.PostgresClient, config *core.Config) gin.HandlerF
 	}
 }
 
-func readyHandler(db *storage.PostgresClient) gin.HandlerFunc {
+// readyHandler gates traffic on AURA being able to serve data: the database
+// answers, the observer has attempted its first scrape (and reached
+// Prometheus, when prometheus.required is set), and pushed metrics can be
+// buffered. Kubernetes being unavailable never fails readiness.
+func readyHandler(db *storage.PostgresClient, metricsObserver *observer.MetricsObserver, buffer *storage.MetricBuffer, config *core.Config) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
 		defer cancel()
@@ -242,71 +447,241 @@ func readyHandler(db *storage.PostgresClient) gin.HandlerFunc {
 			return
 		}
 
+		scraper := metricsObserver.Prometheus().Readiness()
+		if reason := notReadyReason(scraper, config.Prometheus.Required, buffer.Accepting()); reason != "" {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"status":   "not_ready",
+				"reason":   reason,
+				"observer": scraper,
+			})
+			return
+		}
+
 		c.JSON(http.StatusOK, gin.H{
 			"status":    "ready",
+			"observer":  scraper,
 			"timestamp": time.Now().Format(time.RFC3339),
 		})
 	}
 }
 
-func statusHandler(config *core.Config) gin.HandlerFunc {
+// notReadyReason explains why AURA can't serve data yet, or returns "" when
+// it can. An observer that never attempted a scrape isn't ready; Prometheus
+// being unreachable only blocks readiness when prometheus.required is set.
+func notReadyReason(scraper observer.ReadinessStatus, prometheusRequired, bufferAccepting bool) string {
+	switch {
+	case !scraper.Initialized:
+		return "observer has not completed its first scrape"
+	case prometheusRequired && !scraper.Scraped:
+		return "prometheus has not been scraped successfully"
+	case !bufferAccepting:
+		return "metric buffer full"
+	}
+	return ""
+}
+
+func statusHandler(config *core.Config, ua *analyzer.UltimateAnalyzer) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
-			"service":   config.App.Name,
-			"version":   config.App.Version,
-			"timestamp": time.Now().Format(time.RFC3339),
+			"service":         config.App.Name,
+			"version":         config.App.Version,
+			"diagnosis_dedup": ua.DedupStats(),
+			"timestamp":       time.Now().Format(time.RFC3339),
 		})
 	}
 }
 
-func getServiceMetricsHandler(db *storage.PostgresClient) gin.HandlerFunc {
+// systemStatusHandler summarizes every service for wall displays. It only
+// reads stored state (committed severities, open incidents and the latest
+// recorded health scores) and runs no diagnosis, so it is cheap to poll.
+func systemStatusHandler(db *storage.PostgresClient) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		serviceName := c.Param("service")
+		window := time.Hour
+		if w := c.Query("window"); w != "" {
+			d, err := time.ParseDuration(w)
+			if err != nil || d <= 0 || d > 24*time.Hour {
+				respondError(c, http.StatusBadRequest, errCodeBadRequest, "window must be a positive duration of at most 24h")
+				return
+			}
+			window = d
+		}
+
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 		defer cancel()
 
-		// Try both metric name formats (with and without _total suffix)
-		metricVariants := map[string][]string{
-			"cpu_usage":     {"cpu_usage", "cpu_usage_percent"},
-			"memory_usage":  {"memory_usage", "memory_usage_percent"},
-			"http_requests": {"http_requests", "http_requests_total"},
+		services, err := db.GetAllServices(ctx, 24*time.Hour)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, errCodeInternal, "Failed to retrieve services")
+			return
+		}
+		severities, err := db.GetCurrentSeverities(ctx)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
+			return
+		}
+		latest, err := db.GetLatestHealth(ctx, window)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
+			return
+		}
+		openIncidents, err := db.CountIncidents(ctx, storage.IncidentOpen)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
+			return
+		}
+
+		summary := summarizeSystem(services, severities, latest)
+		summary["open_incidents"] = openIncidents
+		summary["window"] = window.String()
+		summary["timestamp"] = time.Now().Format(time.RFC3339)
+		c.JSON(http.StatusOK, summary)
+	}
+}
+
+// summarizeSystem counts services per committed severity and finds the
+// worst and mean of their latest health scores. Services that recorded a
+// health score count even when the registry hasn't seen their metrics for a
+// day; services without a transition count as NONE.
+func summarizeSystem(services []string, severities map[string]string, latest map[string]storage.HealthPoint) gin.H {
+	known := make(map[string]bool, len(services))
+	all := make([]string, 0, len(services)+len(latest))
+	for _, s := range services {
+		if !known[s] {
+			known[s] = true
+			all = append(all, s)
+		}
+	}
+	for s := range latest {
+		if !known[s] {
+			known[s] = true
+			all = append(all, s)
+		}
+	}
+	sort.Strings(all)
+
+	bySeverity := map[string]int{
+		analyzer.SeverityNone:     0,
+		analyzer.SeverityLow:      0,
+		analyzer.SeverityMedium:   0,
+		analyzer.SeverityHigh:     0,
+		analyzer.SeverityCritical: 0,
+	}
+	var worst gin.H
+	var sum, minHealth float64
+	for _, s := range all {
+		severity, ok := severities[s]
+		if !ok {
+			severity = analyzer.SeverityNone
+		}
+		bySeverity[severity]++
+
+		point, ok := latest[s]
+		if !ok {
+			continue
+		}
+		sum += point.HealthScore
+		if worst == nil || point.HealthScore < minHealth {
+			minHealth = point.HealthScore
+			worst = gin.H{
+				"service":      s,
+				"health_score": point.HealthScore,
+				"severity":     severity,
+				"recorded_at":  point.Timestamp.Format(time.RFC3339),
+			}
+		}
+	}
+
+	health := gin.H{"reporting": len(latest)}
+	if len(latest) > 0 {
+		health["min"] = minHealth
+		health["mean"] = sum / float64(len(latest))
+	}
+
+	return gin.H{
+		"total_services": len(all),
+		"by_severity":    bySeverity,
+		"worst_service":  worst,
+		"health":         health,
+	}
+}
+
+// poolOptions converts database.pool into storage pool settings
+func poolOptions(config *core.Config) storage.PoolOptions {
+	pool := config.Database.Pool
+	// Durations are validated in LoadConfig; unset ones parse to 0 and keep
+	// the storage defaults
+	lifetime, _ := time.ParseDuration(pool.MaxConnLifetime)
+	idle, _ := time.ParseDuration(pool.MaxConnIdleTime)
+	healthCheck, _ := time.ParseDuration(pool.HealthCheckPeriod)
+	connectTimeout, _ := time.ParseDuration(pool.ConnectTimeout)
+
+	return storage.PoolOptions{
+		MaxConns:          int32(pool.MaxConns),
+		MinConns:          int32(pool.MinConns),
+		MaxConnLifetime:   lifetime,
+		MaxConnIdleTime:   idle,
+		HealthCheckPeriod: healthCheck,
+		ConnectTimeout:    connectTimeout,
+
+		QueryExecMode:          pool.QueryExecMode,
+		StatementCacheCapacity: pool.StatementCacheCapacity,
+	}
+}
+
+func poolStatsHandler(db *storage.PostgresClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		response := gin.H{
+			"pool":      db.GetPoolStats(),
+			"timestamp": time.Now().Format(time.RFC3339),
+		}
+		if replica, ok := db.GetReplicaPoolStats(); ok {
+			response["replica_pool"] = replica
 		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// serviceMetricsWindow is how far back GET /metrics/:service looks for each
+// metric's latest value
+const serviceMetricsWindow = 15 * time.Minute
+
+func getServiceMetricsHandler(resolver *analyzer.MetricResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		serviceName := c.Param("service")
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		// Metrics are read as the analyzer reads them: through the configured
+		// aliases, with counters as per-minute rates
+		canonicals := []string{analyzer.MetricCPU, analyzer.MetricMemory, analyzer.MetricRequests}
 
 		currentMetrics := make(map[string]float64)
 		failedMetrics := []string{}
 
-		for displayName, variants := range metricVariants {
-			metricFound := false
-
-			for _, metricType := range variants {
-				metric, err := db.GetLatestMetric(ctx, serviceName, metricType)
-				if err == nil && metric != nil {
-					currentMetrics[displayName] = metric.MetricValue
-					metricFound = true
-					logger.Debug("Metric found",
-						zap.String("service", serviceName),
-						zap.String("display_name", displayName),
-						zap.String("actual_name", metricType),
-						zap.Float64("value", metric.MetricValue),
-					)
-					break
-				}
-			}
-
-			if !metricFound {
-				failedMetrics = append(failedMetrics, displayName)
-				logger.Debug("No metric data found",
+		for _, canonical := range canonicals {
+			series, actualName := resolver.ResolveSeries(ctx, serviceName, canonical, serviceMetricsWindow)
+			if len(series) == 0 {
+				failedMetrics = append(failedMetrics, canonical)
+				logger.FromContext(ctx).Debug("No metric data found",
 					zap.String("service", serviceName),
-					zap.String("metric", displayName),
-					zap.Strings("tried_variants", variants),
+					zap.String("metric", canonical),
+					zap.Strings("tried_variants", resolver.Aliases(canonical)),
 				)
+				continue
 			}
+
+			latest := series[len(series)-1]
+			currentMetrics[canonical] = latest.MetricValue
+			logger.FromContext(ctx).Debug("Metric found",
+				zap.String("service", serviceName),
+				zap.String("metric", canonical),
+				zap.String("actual_name", actualName),
+				zap.Float64("value", latest.MetricValue),
+			)
 		}
 
 		if len(currentMetrics) == 0 {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "no metrics found for service",
-			})
+			respondError(c, http.StatusNotFound, errCodeNotFound, "no recent metrics found for service")
 			return
 		}
 
@@ -335,9 +710,7 @@ func getMetricStatsHandler(db *storage.PostgresClient) gin.HandlerFunc {
 
 		stats, err := db.GetMetricStatistics(ctx, serviceName, metricName, 1*time.Hour)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": err.Error(),
-			})
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
 			return
 		}
 
@@ -347,14 +720,41 @@ func getMetricStatsHandler(db *storage.PostgresClient) gin.HandlerFunc {
 
 func getDecisionsHandler(db *storage.PostgresClient) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		filter := storage.DecisionFilter{
+			ActionType: strings.ToUpper(c.Query("action_type")),
+			Limit:      20,
+		}
+		if s := c.Query("executed"); s != "" {
+			executed, err := strconv.ParseBool(s)
+			if err != nil {
+				respondError(c, http.StatusBadRequest, errCodeBadRequest, "executed must be true or false")
+				return
+			}
+			filter.Executed = &executed
+		}
+		if s := c.Query("window"); s != "" {
+			window, err := time.ParseDuration(s)
+			if err != nil || window <= 0 {
+				respondError(c, http.StatusBadRequest, errCodeBadRequest, "Invalid window format. Use format like: 1h, 30m, 24h")
+				return
+			}
+			filter.Since = time.Now().Add(-window)
+		}
+		if s := c.Query("limit"); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n <= 0 || n > 500 {
+				respondError(c, http.StatusBadRequest, errCodeBadRequest, "limit must be between 1 and 500")
+				return
+			}
+			filter.Limit = n
+		}
+
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 		defer cancel()
 
-		decisions, err := db.GetRecentDecisions(ctx, 20)
+		decisions, err := db.GetDecisions(ctx, filter)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": err.Error(),
-			})
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
 			return
 		}
 
@@ -372,9 +772,7 @@ func getDecisionStatsHandler(db *storage.PostgresClient) gin.HandlerFunc {
 
 		stats, err := db.GetDecisionStats(ctx, 24*time.Hour)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": err.Error(),
-			})
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
 			return
 		}
 
@@ -382,66 +780,205 @@ func getDecisionStatsHandler(db *storage.PostgresClient) gin.HandlerFunc {
 	}
 }
 
-func observerHealthHandler() gin.HandlerFunc {
+func observerHealthHandler(observer *observer.MetricsObserver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		prometheus := observer.Prometheus()
+		metricIntervals := make(map[string]string)
+		for metric, interval := range prometheus.MetricIntervals() {
+			metricIntervals[metric] = interval.String()
+		}
+
+		scraper := prometheus.Readiness()
+		response := gin.H{
+			"status":           "running",
+			"interval":         prometheus.Interval().String(),
+			"metric_intervals": metricIntervals,
+			"prometheus":       scraper,
+		}
+		if scraper.Degraded() {
+			response["status"] = "degraded"
+		}
+		if watcher := observer.Kubernetes(); watcher != nil {
+			response["pod_watch"] = watcher.WatchStatus()
+			watches := gin.H{}
+			for _, w := range observer.KubernetesWatchers() {
+				status := w.WatchStatus()
+				watches[w.Namespace()] = status
+				if !status.Connected && status.ConsecutiveFailures > 0 {
+					response["status"] = "degraded"
+				}
+			}
+			response["pod_watches"] = watches
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func getPodsHandler(metricsObserver *observer.MetricsObserver) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		namespace := c.Query("namespace")
+		pods, err := metricsObserver.GetKubernetesPods(ctx, namespace)
+		if errors.Is(err, observer.ErrNamespaceNotWatched) {
+			respondError(c, http.StatusNotFound, errCodeNotFound, fmt.Sprintf("namespace %q is not watched", namespace))
+			return
+		}
+		if err != nil {
+			respondError(c, http.StatusServiceUnavailable, errCodeUpstreamUnavailable, fmt.Sprintf("Kubernetes not available: %v", err))
+			return
+		}
+
 		c.JSON(http.StatusOK, gin.H{
-			"status":   "running",
-			"interval": "10s",
+			"pods":       pods,
+			"count":      len(pods),
+			"namespaces": metricsObserver.WatchedNamespaces(),
 		})
 	}
 }
 
-func getPodsHandler(observer *observer.MetricsObserver) gin.HandlerFunc {
+// getEventsHandler lists recorded Kubernetes events across all namespaces, or
+// one with ?namespace, newest first
+func getEventsHandler(db *storage.PostgresClient) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		filter, ok := parseEventFilter(c)
+		if !ok {
+			return
+		}
+		filter.Namespace = c.Query("namespace")
+
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 		defer cancel()
 
-		pods, err := observer.GetKubernetesPods(ctx)
+		events, nextCursor, err := getEventPage(ctx, db, filter)
 		if err != nil {
-			c.JSON(http.StatusServiceUnavailable, gin.H{
-				"error": fmt.Sprintf("Kubernetes not available: %v", err),
-			})
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
 			return
 		}
 
 		c.JSON(http.StatusOK, gin.H{
-			"pods":  pods,
-			"count": len(pods),
+			"namespace":   filter.Namespace,
+			"duration":    c.DefaultQuery("duration", "1h"),
+			"events":      events,
+			"count":       len(events),
+			"next_cursor": nextCursor,
+			"timestamp":   time.Now().Format(time.RFC3339),
 		})
 	}
 }
 
-func getEventsHandler(db *storage.PostgresClient) gin.HandlerFunc {
+// getAnomaliesHandler lists the Anomaly events recorded by the anomaly scan,
+// newest first, for one service with ?service. It pages like the events
+// endpoints.
+func getAnomaliesHandler(db *storage.PostgresClient) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		filter, ok := parseEventFilter(c)
+		if !ok {
+			return
+		}
+		filter.EventType = storage.EventAnomaly
+		filter.PodName = c.Query("service")
+
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 		defer cancel()
 
-		events, err := db.GetRecentEvents(ctx, "default", 1*time.Hour)
+		anomalies, nextCursor, err := getEventPage(ctx, db, filter)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": err.Error(),
-			})
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
 			return
 		}
 
 		c.JSON(http.StatusOK, gin.H{
-			"events": events,
-			"count":  len(events),
+			"service":     filter.PodName,
+			"duration":    c.DefaultQuery("duration", "1h"),
+			"anomalies":   anomalies,
+			"count":       len(anomalies),
+			"next_cursor": nextCursor,
+			"timestamp":   time.Now().Format(time.RFC3339),
 		})
 	}
 }
 
+// parseEventFilter reads the query parameters shared by the event endpoints:
+// ?duration (default 1h), ?event_type, ?limit (default 100) and ?cursor, the
+// next_cursor of a previous page
+func parseEventFilter(c *gin.Context) (storage.EventFilter, bool) {
+	filter := storage.EventFilter{
+		EventType: c.Query("event_type"),
+		Limit:     100,
+	}
+
+	duration, err := time.ParseDuration(c.DefaultQuery("duration", "1h"))
+	if err != nil || duration <= 0 {
+		respondError(c, http.StatusBadRequest, errCodeBadRequest, "Invalid duration format")
+		return filter, false
+	}
+	filter.Since = time.Now().Add(-duration)
+
+	if s := c.Query("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 || n > 500 {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "limit must be between 1 and 500")
+			return filter, false
+		}
+		filter.Limit = n
+	}
+
+	if s := c.Query("cursor"); s != "" {
+		cursor, err := storage.ParseEventCursor(s)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "Invalid cursor")
+			return filter, false
+		}
+		filter.Cursor = cursor
+	}
+
+	return filter, true
+}
+
+// getEventPage fetches one page of events. It asks for one extra row to learn
+// whether another page follows, and returns that page's cursor or "".
+func getEventPage(ctx context.Context, db *storage.PostgresClient, filter storage.EventFilter) ([]*storage.Event, string, error) {
+	limit := filter.Limit
+	filter.Limit++
+
+	events, err := db.GetEvents(ctx, filter)
+	if err != nil {
+		return nil, "", err
+	}
+	if events == nil {
+		events = []*storage.Event{}
+	}
+	if len(events) <= limit {
+		return events, "", nil
+	}
+	events = events[:limit]
+	return events, storage.EventCursorFor(events[limit-1]).Encode(), nil
+}
+
 func ginLogger() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
 		path := c.Request.URL.Path
 		query := c.Request.URL.RawQuery
 
+		// Honor an upstream request ID so traces line up across proxies
+		requestID := c.GetHeader("X-Request-ID")
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+		c.Set("request_id", requestID)
+		c.Header("X-Request-ID", requestID)
+		c.Request = c.Request.WithContext(logger.WithRequestID(c.Request.Context(), requestID))
+
 		c.Next()
 
 		duration := time.Since(start)
 
 		logger.Info("HTTP Request",
+			zap.String("request_id", requestID),
 			zap.String("method", c.Request.Method),
 			zap.String("path", path),
 			zap.String("query", query),
@@ -462,9 +999,7 @@ func getMetricHistoryHandler(db *storage.PostgresClient) gin.HandlerFunc {
 
 		duration, err := time.ParseDuration(durationStr)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Invalid duration format. Use format like: 1h, 30m, 24h",
-			})
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "Invalid duration format. Use format like: 1h, 30m, 24h")
 			return
 		}
 
@@ -473,16 +1008,12 @@ func getMetricHistoryHandler(db *storage.PostgresClient) gin.HandlerFunc {
 
 		metrics, err := db.GetRecentMetrics(ctx, serviceName, metricType, duration)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to retrieve metric history",
-			})
+			respondError(c, http.StatusInternalServerError, errCodeInternal, "Failed to retrieve metric history")
 			return
 		}
 
 		if len(metrics) == 0 {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "No metrics found for the specified parameters",
-			})
+			respondError(c, http.StatusNotFound, errCodeNotFound, "No metrics found for the specified parameters")
 			return
 		}
 
@@ -502,11 +1033,9 @@ func getAllServicesHandler(db *storage.PostgresClient) gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 		defer cancel()
 
-		services, err := db.GetAllServices(ctx)
+		services, err := db.GetAllServices(ctx, 24*time.Hour)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to retrieve services",
-			})
+			respondError(c, http.StatusInternalServerError, errCodeInternal, "Failed to retrieve services")
 			return
 		}
 
@@ -518,393 +1047,1837 @@ func getAllServicesHandler(db *storage.PostgresClient) gin.HandlerFunc {
 	}
 }
 
-func getDecisionByIdHandler(db *storage.PostgresClient) gin.HandlerFunc {
+func servicesOverviewHandler(db *storage.PostgresClient, ua *analyzer.UltimateAnalyzer) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		idStr := c.Param("id")
-
-		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
 		defer cancel()
 
-		decision, err := db.GetDecisionById(ctx, idStr)
+		services, err := db.GetAllServices(ctx, 24*time.Hour)
 		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": fmt.Sprintf("Decision with ID %s not found", idStr),
-			})
+			// Keep the overview up through a database outage with whatever
+			// was last diagnosed
+			if db.Health(context.WithoutCancel(ctx)) != nil {
+				if summaries := ua.LastKnownComparisons(); len(summaries) > 0 {
+					c.JSON(http.StatusOK, gin.H{
+						"services":  summaries,
+						"count":     len(summaries),
+						"stale":     true,
+						"timestamp": time.Now().Format(time.RFC3339),
+					})
+					return
+				}
+			}
+			respondError(c, http.StatusInternalServerError, errCodeInternal, "Failed to retrieve services")
+			return
+		}
+
+		summaries, err := ua.SummarizeServices(ctx, services)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
 			return
 		}
 
 		c.JSON(http.StatusOK, gin.H{
-			"decision":  decision,
+			"services":  summaries,
+			"count":     len(summaries),
 			"timestamp": time.Now().Format(time.RFC3339),
 		})
 	}
 }
 
-func observerMetricsHandler(observer *observer.MetricsObserver) gin.HandlerFunc {
+func getTrendHandler(db *storage.PostgresClient, ua *analyzer.UltimateAnalyzer) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		serviceName := c.DefaultQuery("service", "sample-app")
+		serviceName := c.Param("service")
+		metricName := c.Param("metric")
+		durationStr := c.DefaultQuery("duration", "30m")
+
+		duration, err := time.ParseDuration(durationStr)
+		if err != nil || duration <= 0 {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "Invalid duration format. Use format like: 1h, 30m, 24h")
+			return
+		}
 
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 		defer cancel()
 
-		metrics, err := observer.GetCurrentMetrics(ctx, serviceName)
+		metrics, err := db.GetRecentMetrics(ctx, serviceName, metricName, duration)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to retrieve observer metrics",
-			})
+			respondError(c, http.StatusInternalServerError, errCodeInternal, "Failed to retrieve metric history")
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"service":   serviceName,
-			"metrics":   metrics,
-			"timestamp": time.Now().Format(time.RFC3339),
-		})
+		if len(metrics) < analyzer.MinTrendDataPoints {
+			respondErrorDetails(c, http.StatusUnprocessableEntity, errCodeUnprocessable,
+				fmt.Sprintf("insufficient data: need at least %d points", analyzer.MinTrendDataPoints),
+				gin.H{"data_points": len(metrics)})
+			return
+		}
+
+		response := gin.H{
+			"service":     serviceName,
+			"metric":      metricName,
+			"duration":    durationStr,
+			"data_points": len(metrics),
+			"timestamp":   time.Now().Format(time.RFC3339),
+		}
+
+		trend := ua.PatternMatcher().DetectTrend(metrics)
+		if trend == nil {
+			// No significant trend; still report the fitted line for context
+			slope, _, rSquared, _ := analyzer.PerformLinearRegression(metrics)
+			response["direction"] = "stable"
+			response["slope"] = slope
+			response["r_squared"] = rSquared
+		} else {
+			response["direction"] = trend.Direction
+			response["slope"] = trend.Slope
+			response["r_squared"] = trend.RSquared
+			response["growth_rate_percent"] = trend.GrowthRatePercent
+		}
+
+		c.JSON(http.StatusOK, response)
 	}
 }
 
-// Kubernetes Handlers
-
-func getPodDetailHandler(observer *observer.MetricsObserver) gin.HandlerFunc {
+func getDecisionByIdHandler(db *storage.PostgresClient) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		podName := c.Param("name")
+		idStr := c.Param("id")
 
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 		defer cancel()
 
-		pods, err := observer.GetKubernetesPods(ctx)
+		decision, err := db.GetDecisionById(ctx, idStr)
 		if err != nil {
-			c.JSON(http.StatusServiceUnavailable, gin.H{
-				"error": "Kubernetes not available or connection failed",
-			})
-			return
-		}
-
-		for _, pod := range pods {
-			if pod.Name == podName {
-				c.JSON(http.StatusOK, gin.H{
-					"pod":       pod,
-					"timestamp": time.Now().Format(time.RFC3339),
-				})
+			if errors.Is(err, storage.ErrNotFound) {
+				respondError(c, http.StatusNotFound, errCodeNotFound, fmt.Sprintf("Decision with ID %s not found", idStr))
 				return
 			}
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
+			return
 		}
 
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": fmt.Sprintf("Pod %s not found", podName),
+		c.JSON(http.StatusOK, gin.H{
+			"decision":  decision,
+			"timestamp": time.Now().Format(time.RFC3339),
 		})
 	}
 }
 
-func getPodMetricsHandler(db *storage.PostgresClient) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		podName := c.Param("name")
-		durationStr := c.DefaultQuery("duration", "1h")
+type decisionUpdateRequest struct {
+	Executed *bool  `json:"executed" binding:"required"`
+	Result   string `json:"result" binding:"max=2000"`
+}
+
+// updateDecisionHandler records that a decision was (or was not) acted on
+func updateDecisionHandler(db *storage.PostgresClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		if _, err := strconv.ParseInt(idStr, 10, 64); err != nil {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "decision id must be an integer")
+			return
+		}
+
+		req, ok := bindJSON[decisionUpdateRequest](c)
+		if !ok {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		if err := db.UpdateDecisionExecution(ctx, idStr, *req.Executed, req.Result); err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				respondError(c, http.StatusNotFound, errCodeNotFound, fmt.Sprintf("Decision with ID %s not found", idStr))
+				return
+			}
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
+			return
+		}
+
+		// Read back from the primary; a replica may not have the update yet
+		decision, err := db.GetDecisionById(storage.WithPrimary(ctx), idStr)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"decision":  decision,
+			"timestamp": time.Now().Format(time.RFC3339),
+		})
+	}
+}
+
+func observerMetricsHandler(observer *observer.MetricsObserver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		serviceName := c.DefaultQuery("service", "sample-app")
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+		defer cancel()
+
+		metrics, err := observer.GetCurrentMetrics(ctx, serviceName)
+		if err != nil {
+			respondError(c, http.StatusBadGateway, errCodeUpstreamError, "Failed to retrieve observer metrics")
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"service":   serviceName,
+			"metrics":   metrics,
+			"timestamp": time.Now().Format(time.RFC3339),
+		})
+	}
+}
+
+// Kubernetes Handlers
+
+func getPodDetailHandler(observer *observer.MetricsObserver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		podName := c.Param("name")
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		pods, err := observer.GetKubernetesPods(ctx, "")
+		if err != nil {
+			respondError(c, http.StatusServiceUnavailable, errCodeUpstreamUnavailable, "Kubernetes not available or connection failed")
+			return
+		}
+
+		for _, pod := range pods {
+			if pod.Name == podName {
+				c.JSON(http.StatusOK, gin.H{
+					"pod":       pod,
+					"timestamp": time.Now().Format(time.RFC3339),
+				})
+				return
+			}
+		}
+
+		respondError(c, http.StatusNotFound, errCodeNotFound, fmt.Sprintf("Pod %s not found", podName))
+	}
+}
+
+func getPodMetricsHandler(db *storage.PostgresClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		podName := c.Param("name")
+		durationStr := c.DefaultQuery("duration", "1h")
 
 		duration, err := time.ParseDuration(durationStr)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Invalid duration format",
-			})
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "Invalid duration format")
 			return
 		}
 
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 		defer cancel()
 
-		metricTypes := []string{"pod_status", "pod_restarts"}
 		podMetrics := make(map[string]interface{})
 
-		for _, metricType := range metricTypes {
+		for _, metricType := range storage.PodMetricNames {
 			metrics, err := db.GetRecentMetrics(ctx, podName, metricType, duration)
 			if err != nil {
 				continue
 			}
-			podMetrics[metricType] = metrics
+			podMetrics[metricType] = metrics
+		}
+
+		if len(podMetrics) == 0 {
+			respondError(c, http.StatusNotFound, errCodeNotFound, fmt.Sprintf("No metrics found for pod %s", podName))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"pod":       podName,
+			"duration":  durationStr,
+			"metrics":   podMetrics,
+			"timestamp": time.Now().Format(time.RFC3339),
+		})
+	}
+}
+
+func getCrashLoopsHandler(responder *analyzer.CrashLoopResponder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		diagnoses := responder.Recent()
+
+		c.JSON(http.StatusOK, gin.H{
+			"crashloops": diagnoses,
+			"count":      len(diagnoses),
+			"timestamp":  time.Now().Format(time.RFC3339),
+		})
+	}
+}
+
+type maintenanceRequest struct {
+	Service  string     `json:"service"`
+	Start    *time.Time `json:"start"`
+	End      *time.Time `json:"end"`
+	Duration string     `json:"duration"`
+	Reason   string     `json:"reason" binding:"required"`
+}
+
+func getMaintenanceHandler(gate *notify.MaintenanceGate) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		service := c.Query("service")
+		now := time.Now()
+
+		windows := gate.Windows()
+		result := make([]gin.H, 0, len(windows))
+		for _, w := range windows {
+			if service != "" && w.Window.Service != "" && w.Window.Service != service {
+				continue
+			}
+			result = append(result, gin.H{
+				"id":     w.ID,
+				"source": w.Source,
+				"window": w.Window,
+				"active": w.Covers(firstNonEmpty(service, w.Window.Service), now),
+			})
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"windows":   result,
+			"count":     len(result),
+			"timestamp": now.Format(time.RFC3339),
+		})
+	}
+}
+
+func createMaintenanceHandler(gate *notify.MaintenanceGate) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req, ok := bindJSON[maintenanceRequest](c)
+		if !ok {
+			return
+		}
+
+		start := time.Now()
+		if req.Start != nil {
+			start = *req.Start
+		}
+
+		var end time.Time
+		switch {
+		case req.End != nil && req.Duration != "":
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "set either end or duration, not both")
+			return
+		case req.End != nil:
+			end = *req.End
+		case req.Duration != "":
+			d, err := time.ParseDuration(req.Duration)
+			if err != nil || d <= 0 {
+				respondError(c, http.StatusBadRequest, errCodeBadRequest, "Invalid duration format")
+				return
+			}
+			end = start.Add(d)
+		default:
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "end or duration is required")
+			return
+		}
+
+		window, err := gate.Add(core.MaintenanceWindow{
+			Service: req.Service,
+			Start:   start.Format(time.RFC3339),
+			End:     end.Format(time.RFC3339),
+			Reason:  req.Reason,
+		})
+		if err != nil {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, err.Error())
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"id":        window.ID,
+			"source":    window.Source,
+			"window":    window.Window,
+			"timestamp": time.Now().Format(time.RFC3339),
+		})
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+type injectPoint struct {
+	Timestamp time.Time `json:"ts" binding:"required"`
+	Value     *float64  `json:"value" binding:"required"`
+}
+
+type injectRequest struct {
+	Service string        `json:"service" binding:"required"`
+	Metric  string        `json:"metric" binding:"required"`
+	Points  []injectPoint `json:"points" binding:"required,min=1,max=10000,dive"` // bounded per request
+}
+
+func injectMetricsHandler(db *storage.PostgresClient, config *core.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !config.Testing.Enabled {
+			respondError(c, http.StatusNotFound, errCodeNotFound, "Not found")
+			return
+		}
+
+		req, ok := bindJSON[injectRequest](c)
+		if !ok {
+			return
+		}
+
+		metrics := make([]*storage.Metric, 0, len(req.Points))
+		for _, p := range req.Points {
+			metrics = append(metrics, &storage.Metric{
+				Timestamp:   p.Timestamp,
+				ServiceName: req.Service,
+				MetricName:  req.Metric,
+				MetricValue: *p.Value,
+				Labels:      json.RawMessage(`{"source":"test-inject"}`),
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		if err := db.BatchSaveMetrics(ctx, metrics); err != nil {
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
+			return
+		}
+
+		logger.FromContext(ctx).Info("🧪 Injected synthetic metrics",
+			zap.String("service", req.Service),
+			zap.String("metric", req.Metric),
+			zap.Int("points", len(metrics)),
+		)
+
+		c.JSON(http.StatusCreated, gin.H{
+			"service":   req.Service,
+			"metric":    req.Metric,
+			"inserted":  len(metrics),
+			"timestamp": time.Now().Format(time.RFC3339),
+		})
+	}
+}
+
+func getStaleServicesHandler(monitor *analyzer.StalenessMonitor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+		defer cancel()
+
+		stale, err := monitor.Check(ctx)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"stale_services": formatDetections(stale),
+			"count":          len(stale),
+			"stale_after":    monitor.StaleAfter().String(),
+			"timestamp":      time.Now().Format(time.RFC3339),
+		})
+	}
+}
+
+func getPodEventsHandler(db *storage.PostgresClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		podName := c.Param("podname")
+
+		filter, ok := parseEventFilter(c)
+		if !ok {
+			return
+		}
+		filter.PodName = podName
+		filter.Namespace = c.Query("namespace")
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		events, nextCursor, err := getEventPage(ctx, db, filter)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, errCodeInternal, "Failed to retrieve pod events")
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"pod":         podName,
+			"duration":    c.DefaultQuery("duration", "1h"),
+			"events":      events,
+			"count":       len(events),
+			"next_cursor": nextCursor,
+			"timestamp":   time.Now().Format(time.RFC3339),
+		})
+	}
+}
+
+// getNamespaceSummaryHandler summarizes one watched namespace, or all of them
+// when ?namespace is omitted, with a per-namespace breakdown
+func getNamespaceSummaryHandler(metricsObserver *observer.MetricsObserver, db *storage.PostgresClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		namespace := c.Query("namespace")
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+		defer cancel()
+
+		pods, err := metricsObserver.GetKubernetesPods(ctx, namespace)
+		if errors.Is(err, observer.ErrNamespaceNotWatched) {
+			respondError(c, http.StatusNotFound, errCodeNotFound, fmt.Sprintf("namespace %q is not watched", namespace))
+			return
+		}
+		if err != nil {
+			respondError(c, http.StatusServiceUnavailable, errCodeUpstreamUnavailable, "Kubernetes not available")
+			return
+		}
+
+		namespaces := metricsObserver.WatchedNamespaces()
+		if namespace != "" {
+			namespaces = []string{namespace}
+		}
+		podsByNamespace := make(map[string][]observer.PodMetric, len(namespaces))
+		for _, pod := range pods {
+			podsByNamespace[pod.Namespace] = append(podsByNamespace[pod.Namespace], pod)
+		}
+
+		perNamespace := make(map[string]interface{}, len(namespaces))
+		recentEvents := 0
+		for _, ns := range namespaces {
+			nsSummary := summarizePods(podsByNamespace[ns])
+			events, _ := db.GetRecentEvents(ctx, ns, 1*time.Hour)
+			nsSummary["recent_events"] = len(events)
+			recentEvents += len(events)
+			perNamespace[ns] = nsSummary
+		}
+
+		summary := summarizePods(pods)
+		summary["namespaces"] = namespaces
+		summary["recent_events"] = recentEvents
+		if namespace != "" {
+			summary["namespace"] = namespace
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"summary":      summary,
+			"by_namespace": perNamespace,
+			"pods":         pods,
+			"timestamp":    time.Now().Format(time.RFC3339),
+		})
+	}
+}
+
+// summarizePods counts pods by phase and totals their restarts
+func summarizePods(pods []observer.PodMetric) map[string]interface{} {
+	var running, pending, failed, restarts int
+	for _, pod := range pods {
+		switch pod.Phase {
+		case "Running":
+			running++
+		case "Pending":
+			pending++
+		case "Failed":
+			failed++
+		}
+		restarts += int(pod.Restarts)
+	}
+
+	return map[string]interface{}{
+		"total_pods":     len(pods),
+		"running_pods":   running,
+		"pending_pods":   pending,
+		"failed_pods":    failed,
+		"total_restarts": restarts,
+	}
+}
+
+// Prometheus Handlers
+
+func prometheusHealthHandler(observer *observer.MetricsObserver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		scraper := observer.Prometheus().Readiness()
+
+		err := observer.Health(ctx)
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"status":         "unhealthy",
+				"error":          err.Error(),
+				"scraper_state":  scraper.State,
+				"degraded_since": scraper.DegradedSince,
+				"timestamp":      time.Now().Format(time.RFC3339),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"status":         "healthy",
+			"message":        "Prometheus is reachable",
+			"scraper_state":  scraper.State,
+			"degraded_since": scraper.DegradedSince,
+			"timestamp":      time.Now().Format(time.RFC3339),
+		})
+	}
+}
+
+func prometheusTargetsHandler(observer *observer.MetricsObserver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		err := observer.Health(ctx)
+		if err != nil {
+			respondError(c, http.StatusServiceUnavailable, errCodeUpstreamUnavailable, "Prometheus not available")
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"targets": []map[string]interface{}{
+				{
+					"name":   "sample-app",
+					"url":    "http://sample-app:8080/metrics",
+					"status": "up",
+				},
+			},
+			"timestamp": time.Now().Format(time.RFC3339),
+		})
+	}
+}
+
+func prometheusQueryHandler(observer *observer.MetricsObserver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := c.Query("query")
+
+		if query == "" {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "Query parameter is required. Example: ?query=cpu_usage")
+			return
+		}
+
+		// The client applies prometheus.query_timeout itself
+		prom := observer.Prometheus()
+		result, err := prom.Query(c.Request.Context(), query)
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				respondError(c, http.StatusGatewayTimeout, errCodeUpstreamTimeout,
+					fmt.Sprintf("Query exceeded the %s Prometheus query timeout", prom.QueryTimeout()))
+				return
+			}
+			respondError(c, http.StatusBadGateway, errCodeUpstreamError, "Failed to execute query: "+err.Error())
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"query":     query,
+			"result":    result,
+			"timeout":   prom.QueryTimeout().String(),
+			"timestamp": time.Now().Format(time.RFC3339),
+		})
+	}
+}
+
+func prometheusMetricsSummaryHandler(db *storage.PostgresClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		durationStr := c.DefaultQuery("duration", "1h")
+
+		duration, err := time.ParseDuration(durationStr)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "Invalid duration format")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+		defer cancel()
+
+		services := []string{"sample-app"}
+		metricTypes := []string{"cpu_usage", "memory_usage", "http_requests"}
+
+		summary := make(map[string]map[string]interface{})
+
+		for _, service := range services {
+			summary[service] = make(map[string]interface{})
+			for _, metricType := range metricTypes {
+				stats, err := db.GetMetricStatistics(ctx, service, metricType, duration)
+				if err != nil {
+					continue
+				}
+				summary[service][metricType] = stats
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"duration":  durationStr,
+			"summary":   summary,
+			"timestamp": time.Now().Format(time.RFC3339),
+		})
+	}
+}
+
+// ==================== AI-LEVEL ANALYZER HANDLERS ====================
+// The ONLY analyzer - All endpoints use the AI-Level Ultimate Analyzer
+
+func aiDiagnoseServiceHandler(ua *analyzer.UltimateAnalyzer, db *storage.PostgresClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		serviceName := c.Param("service")
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		logger.FromContext(ctx).Info("🤖 AI diagnosis requested",
+			zap.String("service", serviceName),
+			zap.String("client_ip", c.ClientIP()),
+		)
+
+		etag := diagnosisETag(ctx, db, serviceName)
+		if etag != "" && legacyEvidence(c) {
+			etag = strings.TrimSuffix(etag, `"`) + "-" + analyzer.EvidenceFormatLegacy + `"`
+		}
+		if checkNotModified(c, etag) {
+			return
+		}
+
+		diagnosis, err := ua.DiagnoseService(ctx, serviceName)
+		var lastKnown *analyzer.LastKnownDiagnosis
+		if err != nil {
+			lk, ok := lastKnownOnOutage(ctx, db, ua, serviceName)
+			if !ok {
+				logger.FromContext(ctx).Error("AI diagnosis failed", zap.Error(err))
+				respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
+				return
+			}
+			lastKnown, diagnosis = lk, lk.Diagnosis
+		}
+		if legacyEvidence(c) {
+			diagnosis = diagnosis.Legacy()
+		}
+
+		response := gin.H{
+			"service":              diagnosis.ServiceName,
+			"timestamp":            diagnosis.Timestamp.Format(time.RFC3339),
+			"analysis_duration_ms": diagnosis.AnalysisDuration.Milliseconds(),
+
+			"primary_detection": gin.H{
+				"problem":              diagnosis.PrimaryDetection.Type,
+				"detected":             diagnosis.PrimaryDetection.Detected,
+				"confidence":           fmt.Sprintf("%.2f%%", diagnosis.PrimaryDetection.Confidence),
+				"aggregate_confidence": fmt.Sprintf("%.2f%%", diagnosis.PrimaryDetection.AggregateConfidence),
+				"dampening":            diagnosis.PrimaryDetection.Dampening,
+				"severity":             diagnosis.PrimaryDetection.Severity,
+				"evidence":             diagnosis.PrimaryDetection.Evidence,
+				"recommendation":       diagnosis.PrimaryDetection.Recommendation,
+			},
+
+			"health_metrics": gin.H{
+				"health_score":         fmt.Sprintf("%.2f/100", diagnosis.HealthScore),
+				"stability_index":      fmt.Sprintf("%.2f/10", diagnosis.StabilityIndex),
+				"predictability_score": fmt.Sprintf("%.2f/100", diagnosis.PredictabilityScore),
+				"system_stress":        fmt.Sprintf("%.2f/100", diagnosis.SystemStress),
+			},
+
+			"assessment": gin.H{
+				"risk_level":          diagnosis.RiskLevel,
+				"action_required":     diagnosis.ActionRequired,
+				"predictive_insights": diagnosis.PredictiveInsights,
+			},
+
+			// Enhanced actuator-ready outputs
+			"root_cause": diagnosis.RootCause,
+
+			"actuator_actions": diagnosis.ActuatorActions,
+
+			"impact_assessment": diagnosis.ImpactAssessment,
+
+			"recommendation": diagnosis.Recommendation,
+			"prediction_id":  diagnosis.PredictionID,
+
+			"all_detections": formatDetections(diagnosis.AllDetections),
+
+			// 🌟 NEW: Comprehensive Enhanced Diagnostics
+			"enhanced_data": diagnosis.EnhancedData,
+		}
+		if lastKnown != nil {
+			response["stale"] = true
+			response["cached_at"] = lastKnown.CachedAt.Format(time.RFC3339)
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func ultimateDiagnoseHandler(ua *analyzer.UltimateAnalyzer, db *storage.PostgresClient, incidents *analyzer.IncidentTracker, transitions *analyzer.TransitionTracker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		serviceName := c.Param("service")
+		format := c.Query("format")
+		legacy := format == analyzer.EvidenceFormatLegacy
+		if format != "" && !legacy && !report.ValidFormat(format) {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "format must be one of: json, markdown, html, legacy")
+			return
+		}
+		composite := c.Query("composite") == "true"
+		if composite && format != "" {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "composite cannot be combined with format")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		etag := diagnosisETag(ctx, db, serviceName)
+		if etag != "" && format != "" {
+			etag = strings.TrimSuffix(etag, `"`) + "-" + format + `"`
+		}
+		if etag != "" && composite {
+			etag = strings.TrimSuffix(etag, `"`) + "-composite" + `"`
+		}
+		if checkNotModified(c, etag) {
+			return
+		}
+		if legacy {
+			format = "" // the full diagnosis, with legacy evidence
+		}
+
+		diagnosis, err := ua.DiagnoseService(ctx, serviceName)
+		if err != nil {
+			if lastKnown, ok := lastKnownOnOutage(ctx, db, ua, serviceName); ok {
+				if format != "" {
+					respondRecommendation(c, lastKnown.Diagnosis, format)
+					return
+				}
+				if composite {
+					c.JSON(http.StatusOK, staleComposite{CompositeDiagnosis: ua.Composite(lastKnown.Diagnosis), Stale: true, CachedAt: lastKnown.CachedAt})
+					return
+				}
+				stale := lastKnown.Diagnosis
+				if legacy {
+					stale = stale.Legacy()
+				}
+				c.JSON(http.StatusOK, staleDiagnosis{UltimateDiagnosis: stale, Stale: true, CachedAt: lastKnown.CachedAt})
+				return
+			}
+			logger.FromContext(ctx).Error("Ultimate diagnosis failed", zap.Error(err))
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
+			return
+		}
+
+		// Persistence is best-effort: the caller still gets the diagnosis
+		if err := db.SaveUltimateDiagnosis(ctx, diagnosis.Record()); err != nil {
+			logger.FromContext(ctx).Warn("Failed to persist ultimate diagnosis",
+				zap.String("service", serviceName),
+				zap.String("prediction_id", diagnosis.PredictionID),
+				zap.Error(err),
+			)
+		}
+		incidents.Observe(ctx, diagnosis)
+		transitions.Observe(ctx, diagnosis)
+
+		if format != "" {
+			respondRecommendation(c, diagnosis, format)
+			return
+		}
+		if composite {
+			c.JSON(http.StatusOK, ua.Composite(diagnosis))
+			return
+		}
+		if legacy {
+			diagnosis = diagnosis.Legacy()
+		}
+		c.JSON(http.StatusOK, diagnosis)
+	}
+}
+
+func healthScoreHandler(ua *analyzer.UltimateAnalyzer, db *storage.PostgresClient, incidents *analyzer.IncidentTracker, transitions *analyzer.TransitionTracker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		serviceName := c.Param("service")
+
+		window := analyzer.DefaultHealthTrendWindow
+		if w := c.Query("window"); w != "" {
+			d, err := time.ParseDuration(w)
+			if err != nil || d <= 0 {
+				respondError(c, http.StatusBadRequest, errCodeBadRequest, "Invalid window format")
+				return
+			}
+			window = d
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		var trend *analyzer.HealthTrend
+		var diagnosis *analyzer.UltimateDiagnosis
+		var err error
+		if c.Query("trend") == "true" {
+			trend, diagnosis, err = ua.HealthTrend(ctx, serviceName, window)
+		} else {
+			diagnosis, err = ua.DiagnoseService(ctx, serviceName)
+		}
+		if err != nil {
+			if lastKnown, ok := lastKnownOnOutage(ctx, db, ua, serviceName); ok {
+				c.JSON(http.StatusOK, gin.H{
+					"service":       serviceName,
+					"health_score":  lastKnown.Diagnosis.HealthScore,
+					"risk_level":    lastKnown.Diagnosis.RiskLevel,
+					"prediction_id": lastKnown.Diagnosis.PredictionID,
+					"stale":         true,
+					"cached_at":     lastKnown.CachedAt.Format(time.RFC3339),
+					"timestamp":     time.Now().Format(time.RFC3339),
+				})
+				return
+			}
+			logger.FromContext(ctx).Error("Health score failed", zap.Error(err))
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
+			return
+		}
+
+		// Stored diagnoses are the history later trends are computed from
+		if err := db.SaveUltimateDiagnosis(ctx, diagnosis.Record()); err != nil {
+			logger.FromContext(ctx).Warn("Failed to persist ultimate diagnosis",
+				zap.String("service", serviceName),
+				zap.String("prediction_id", diagnosis.PredictionID),
+				zap.Error(err),
+			)
+		}
+		incidents.Observe(ctx, diagnosis)
+		transitions.Observe(ctx, diagnosis)
+
+		response := gin.H{
+			"service":       serviceName,
+			"health_score":  diagnosis.HealthScore,
+			"risk_level":    diagnosis.RiskLevel,
+			"prediction_id": diagnosis.PredictionID,
+			"timestamp":     time.Now().Format(time.RFC3339),
+		}
+		if trend != nil {
+			response["trend"] = trend
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func healthTimelineHandler(ua *analyzer.UltimateAnalyzer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		serviceName := c.Param("service")
+
+		window := 6 * time.Hour
+		if d := c.Query("duration"); d != "" {
+			parsed, err := time.ParseDuration(d)
+			if err != nil || parsed <= 0 || parsed > analyzer.MaxHealthTimelineWindow {
+				respondError(c, http.StatusBadRequest, errCodeBadRequest,
+					fmt.Sprintf("duration must be a positive duration of at most %s", analyzer.MaxHealthTimelineWindow))
+				return
+			}
+			window = parsed
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		timeline, err := ua.HealthTimeline(ctx, serviceName, window)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"timeline":  timeline,
+			"timestamp": time.Now().Format(time.RFC3339),
+		})
+	}
+}
+
+func getUltimateDiagnosisHandler(db *storage.PostgresClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		predictionID := c.Param("prediction_id")
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		record, err := db.GetUltimateDiagnosisByID(ctx, predictionID)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
+			return
+		}
+		if record == nil {
+			respondError(c, http.StatusNotFound, errCodeNotFound, fmt.Sprintf("Diagnosis with prediction ID %s not found", predictionID))
+			return
+		}
+
+		if record.Diagnosis != nil {
+			c.JSON(http.StatusOK, record.Diagnosis)
+			return
+		}
+
+		// Rows written before the full document was stored only have the indexed columns
+		c.JSON(http.StatusOK, gin.H{
+			"prediction_id":      record.PredictionID,
+			"service_name":       record.ServiceName,
+			"timestamp":          record.Timestamp.Format(time.RFC3339),
+			"primary_problem":    record.PrimaryProblem,
+			"primary_confidence": record.PrimaryConfidence,
+			"primary_severity":   record.PrimarySeverity,
+			"health_score":       record.HealthScore,
+			"risk_level":         record.RiskLevel,
+			"recommendation":     record.Recommendation,
+		})
+	}
+}
+
+func getDiagnosesHandler(db *storage.PostgresClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		serviceName := c.Param("service")
+		version := c.Query("version")
+
+		limit := 50
+		if l := c.Query("limit"); l != "" {
+			n, err := strconv.Atoi(l)
+			if err != nil || n <= 0 || n > 500 {
+				respondError(c, http.StatusBadRequest, errCodeBadRequest, "limit must be between 1 and 500")
+				return
+			}
+			limit = n
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		diagnoses, err := db.GetDiagnosisSummaries(ctx, serviceName, version, limit)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"service":                  serviceName,
+			"diagnoses":                diagnoses,
+			"count":                    len(diagnoses),
+			"current_detector_version": analyzer.DetectorVersion,
+			"timestamp":                time.Now().Format(time.RFC3339),
+		})
+	}
+}
+
+func triageHandler(db *storage.PostgresClient, ua *analyzer.UltimateAnalyzer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		minSeverity := ua.TriageMinSeverity()
+		if s := c.Query("min_severity"); s != "" {
+			minSeverity = strings.ToUpper(s)
+			if !analyzer.ValidSeverity(minSeverity) {
+				respondError(c, http.StatusBadRequest, errCodeBadRequest, "min_severity must be one of: LOW, MEDIUM, HIGH, CRITICAL")
+				return
+			}
+		}
+
+		limit := 50
+		if l := c.Query("limit"); l != "" {
+			n, err := strconv.Atoi(l)
+			if err != nil || n <= 0 || n > 500 {
+				respondError(c, http.StatusBadRequest, errCodeBadRequest, "limit must be between 1 and 500")
+				return
+			}
+			limit = n
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+		defer cancel()
+
+		services, err := db.GetAllServices(ctx, 24*time.Hour)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, errCodeInternal, "Failed to retrieve services")
+			return
+		}
+
+		items, err := ua.Triage(ctx, services, minSeverity)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
+			return
+		}
+
+		total := len(items)
+		if len(items) > limit {
+			items = items[:limit]
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"services":     items,
+			"count":        len(items),
+			"total":        total,
+			"checked":      len(services),
+			"min_severity": minSeverity,
+			"timestamp":    time.Now().Format(time.RFC3339),
+		})
+	}
+}
+
+type batchAnalyzeRequest struct {
+	Services []string `json:"services"`
+}
+
+// analyzeBatchHandler diagnoses an explicit list of services. Names missing
+// from the services registry are skipped and listed as unknown.
+func analyzeBatchHandler(db *storage.PostgresClient, ua *analyzer.UltimateAnalyzer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req, ok := bindJSON[batchAnalyzeRequest](c)
+		if !ok {
+			return
+		}
+
+		seen := make(map[string]bool)
+		requested := make([]string, 0, len(req.Services))
+		for _, name := range req.Services {
+			if name = strings.TrimSpace(name); name != "" && !seen[name] {
+				seen[name] = true
+				requested = append(requested, name)
+			}
+		}
+		if len(requested) == 0 {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "services must list at least one service")
+			return
+		}
+		if len(requested) > analyzer.MaxBatchServices {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest,
+				fmt.Sprintf("at most %d services can be analyzed at once", analyzer.MaxBatchServices))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+		defer cancel()
+
+		records, err := db.GetServiceRecords(ctx, 0)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, errCodeInternal, "Failed to retrieve services")
+			return
+		}
+		known := make(map[string]bool, len(records))
+		for _, r := range records {
+			known[r.Name] = true
+		}
+
+		services := make([]string, 0, len(requested))
+		unknown := make([]string, 0)
+		for _, name := range requested {
+			if known[name] {
+				services = append(services, name)
+			} else {
+				unknown = append(unknown, name)
+			}
+		}
+
+		batch := ua.DiagnoseServices(ctx, services)
+
+		c.JSON(http.StatusOK, gin.H{
+			"diagnoses": batch.Diagnoses,
+			"failed":    batch.Failed,
+			"unknown":   unknown,
+			"count":     len(batch.Diagnoses),
+			"timestamp": time.Now().Format(time.RFC3339),
+		})
+	}
+}
+
+func analyzeGroupHandler(ua *analyzer.UltimateAnalyzer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		group := c.Param("group")
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+		defer cancel()
+
+		services, err := ua.GroupMembers(ctx, group)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
+			return
+		}
+		if len(services) == 0 {
+			respondError(c, http.StatusNotFound, errCodeNotFound, fmt.Sprintf("no services in group %s", group))
+			return
+		}
+
+		analysis, err := ua.AnalyzeGroup(ctx, group, services)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"analysis":  analysis,
+			"timestamp": time.Now().Format(time.RFC3339),
+		})
+	}
+}
+
+func compareServicesFullHandler(ua *analyzer.UltimateAnalyzer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		services := make([]string, 0)
+		for _, name := range strings.Split(c.Query("services"), ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				services = append(services, name)
+			}
+		}
+
+		if len(services) == 0 {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "services parameter is required. Example: ?services=a,b,c")
+			return
+		}
+		if len(services) > analyzer.MaxCompareServices {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, fmt.Sprintf("at most %d services can be compared at once", analyzer.MaxCompareServices))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+		defer cancel()
+
+		diagnoses, err := ua.CompareServicesFull(ctx, services)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"services":  diagnoses,
+			"count":     len(diagnoses),
+			"timestamp": time.Now().Format(time.RFC3339),
+		})
+	}
+}
+
+type backtestRequest struct {
+	Cases []backtest.Case `json:"cases" binding:"required,min=1"`
+}
+
+func runBacktestHandler(runner *backtest.Runner, db *storage.PostgresClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req, ok := bindJSON[backtestRequest](c)
+		if !ok {
+			return
+		}
+		if err := backtest.Validate(req.Cases); err != nil {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, err.Error())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Minute)
+		defer cancel()
+
+		report, err := runner.Run(ctx, req.Cases)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
+			return
+		}
+
+		run := &storage.BacktestRun{
+			RunAt:     report.RunAt,
+			CaseCount: len(report.Cases),
+			Accuracy:  report.Accuracy,
+			MacroF1:   report.MacroF1,
+
+			DetectorVersion: report.DetectorVersion,
+		}
+		if err := db.SaveBacktestRun(ctx, run, report); err != nil {
+			logger.FromContext(ctx).Warn("Failed to persist backtest run", zap.Error(err))
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"run_id":    run.ID,
+			"report":    report,
+			"timestamp": time.Now().Format(time.RFC3339),
+		})
+	}
+}
+
+func getBacktestRunsHandler(db *storage.PostgresClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		runs, err := db.GetRecentBacktestRuns(ctx, 20)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"runs":      runs,
+			"count":     len(runs),
+			"scenarios": backtest.Scenarios(),
+			"timestamp": time.Now().Format(time.RFC3339),
+		})
+	}
+}
+
+func getIncidentsHandler(db *storage.PostgresClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		status := c.Query("status")
+		switch status {
+		case "", storage.IncidentOpen, storage.IncidentClosed:
+		default:
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "status must be open or closed")
+			return
+		}
+
+		limit := 50
+		if l := c.Query("limit"); l != "" {
+			n, err := strconv.Atoi(l)
+			if err != nil || n <= 0 || n > 500 {
+				respondError(c, http.StatusBadRequest, errCodeBadRequest, "limit must be between 1 and 500")
+				return
+			}
+			limit = n
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		incidents, err := db.GetIncidents(ctx, status, c.Query("service"), limit)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"incidents": incidents,
+			"count":     len(incidents),
+			"timestamp": time.Now().Format(time.RFC3339),
+		})
+	}
+}
+
+// dailyReportHandler returns the report for ?date= (YYYY-MM-DD, UTC),
+// yesterday by default. Today's report is partial and compiled on request.
+func dailyReportHandler(reports *analyzer.ReportGenerator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		day := analyzer.ReportDay(time.Now()).AddDate(0, 0, -1)
+		if d := c.Query("date"); d != "" {
+			parsed, err := time.Parse("2006-01-02", d)
+			if err != nil {
+				respondError(c, http.StatusBadRequest, errCodeBadRequest, "date must be YYYY-MM-DD")
+				return
+			}
+			if parsed.After(analyzer.ReportDay(time.Now())) {
+				respondError(c, http.StatusBadRequest, errCodeBadRequest, "date must not be in the future")
+				return
+			}
+			day = parsed
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		report, err := reports.Report(ctx, day)
+		if err != nil {
+			logger.FromContext(ctx).Error("Daily report failed", zap.String("date", day.Format("2006-01-02")), zap.Error(err))
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"report":    report,
+			"timestamp": time.Now().Format(time.RFC3339),
+		})
+	}
+}
+
+// postmortemHandler exports everything recorded about one incident, as JSON
+// or, with ?format=markdown, as a Markdown document
+func postmortemHandler(builder *analyzer.PostmortemBuilder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		format := c.DefaultQuery("format", report.FormatJSON)
+		if format != report.FormatJSON && format != report.FormatMarkdown {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "format must be json or markdown")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		pm, err := builder.Build(ctx, c.Param("id"))
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				respondError(c, http.StatusNotFound, errCodeNotFound, "incident not found")
+				return
+			}
+			logger.FromContext(ctx).Error("Postmortem failed", zap.String("incident", c.Param("id")), zap.Error(err))
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
+			return
+		}
+
+		if format == report.FormatMarkdown {
+			c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(pm.Markdown()))
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"postmortem": pm,
+			"timestamp":  time.Now().Format(time.RFC3339),
+		})
+	}
+}
+
+func getTransitionsHandler(db *storage.PostgresClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := 50
+		if l := c.Query("limit"); l != "" {
+			n, err := strconv.Atoi(l)
+			if err != nil || n <= 0 || n > 500 {
+				respondError(c, http.StatusBadRequest, errCodeBadRequest, "limit must be between 1 and 500")
+				return
+			}
+			limit = n
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		transitions, err := db.GetStatusTransitions(ctx, c.Query("service"), limit)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"transitions": transitions,
+			"count":       len(transitions),
+			"timestamp":   time.Now().Format(time.RFC3339),
+		})
+	}
+}
+
+// defaultAnnotationRange is used when an annotations request omits from
+const defaultAnnotationRange = 24 * time.Hour
+
+func getAnnotationsHandler(db *storage.PostgresClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		serviceName := c.Param("service")
+
+		to := time.Now()
+		if s := c.Query("to"); s != "" {
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				respondError(c, http.StatusBadRequest, errCodeBadRequest, "Invalid to format, expected RFC3339")
+				return
+			}
+			to = t
+		}
+		from := to.Add(-defaultAnnotationRange)
+		if s := c.Query("from"); s != "" {
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				respondError(c, http.StatusBadRequest, errCodeBadRequest, "Invalid from format, expected RFC3339")
+				return
+			}
+			from = t
+		}
+		if !from.Before(to) {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "from must be before to")
+			return
 		}
 
-		if len(podMetrics) == 0 {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": fmt.Sprintf("No metrics found for pod %s", podName),
-			})
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+		defer cancel()
+
+		annotations, err := db.GetAnnotations(ctx, serviceName, from, to)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
 			return
 		}
 
 		c.JSON(http.StatusOK, gin.H{
-			"pod":       podName,
-			"duration":  durationStr,
-			"metrics":   podMetrics,
-			"timestamp": time.Now().Format(time.RFC3339),
+			"service":     serviceName,
+			"from":        from.Format(time.RFC3339),
+			"to":          to.Format(time.RFC3339),
+			"annotations": annotations,
+			"count":       len(annotations),
+			"timestamp":   time.Now().Format(time.RFC3339),
 		})
 	}
 }
 
-func getPodEventsHandler(db *storage.PostgresClient) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		podName := c.Param("podname")
-		durationStr := c.DefaultQuery("duration", "1h")
+type deploymentRequest struct {
+	Service     string     `json:"service" binding:"required"`
+	Version     string     `json:"version" binding:"required,max=100"`
+	Description string     `json:"description"`
+	DeployedAt  *time.Time `json:"deployed_at"` // defaults to now
+}
 
-		duration, err := time.ParseDuration(durationStr)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Invalid duration format",
-			})
+func recordDeploymentHandler(db *storage.PostgresClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req, ok := bindJSON[deploymentRequest](c)
+		if !ok {
 			return
 		}
 
+		deployment := &storage.Deployment{
+			ServiceName: req.Service,
+			Version:     req.Version,
+			Description: req.Description,
+			DeployedAt:  time.Now(),
+		}
+		if req.DeployedAt != nil {
+			deployment.DeployedAt = *req.DeployedAt
+		}
+
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 		defer cancel()
 
-		events, err := db.GetPodEvents(ctx, podName, duration)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to retrieve pod events",
-			})
+		if err := db.SaveDeployment(ctx, deployment); err != nil {
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"pod":       podName,
-			"duration":  durationStr,
-			"events":    events,
-			"count":     len(events),
-			"timestamp": time.Now().Format(time.RFC3339),
-		})
+		c.JSON(http.StatusCreated, deployment)
 	}
 }
 
-func getNamespaceSummaryHandler(observer *observer.MetricsObserver, db *storage.PostgresClient) gin.HandlerFunc {
+// getServiceNotesHandler lists the notes in effect for a service, or every
+// note when all=true
+func getServiceNotesHandler(db *storage.PostgresClient) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		namespace := c.DefaultQuery("namespace", "default")
+		serviceName := c.Param("service")
+		includeExpired := c.Query("all") == "true"
 
-		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 		defer cancel()
 
-		pods, err := observer.GetKubernetesPods(ctx)
+		notes, err := db.GetServiceNotes(ctx, serviceName, time.Now(), includeExpired)
 		if err != nil {
-			c.JSON(http.StatusServiceUnavailable, gin.H{
-				"error": "Kubernetes not available",
-			})
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
 			return
 		}
-
-		summary := map[string]interface{}{
-			"namespace":      namespace,
-			"total_pods":     len(pods),
-			"running_pods":   0,
-			"pending_pods":   0,
-			"failed_pods":    0,
-			"total_restarts": 0,
-		}
-
-		for _, pod := range pods {
-			switch pod.Phase {
-			case "Running":
-				summary["running_pods"] = summary["running_pods"].(int) + 1
-			case "Pending":
-				summary["pending_pods"] = summary["pending_pods"].(int) + 1
-			case "Failed":
-				summary["failed_pods"] = summary["failed_pods"].(int) + 1
-			}
-			summary["total_restarts"] = summary["total_restarts"].(int) + int(pod.Restarts)
+		if notes == nil {
+			notes = []*storage.ServiceNote{}
 		}
 
-		events, _ := db.GetRecentEvents(ctx, namespace, 1*time.Hour)
-		summary["recent_events"] = len(events)
-
 		c.JSON(http.StatusOK, gin.H{
-			"summary":   summary,
-			"pods":      pods,
+			"service":   serviceName,
+			"notes":     notes,
+			"count":     len(notes),
 			"timestamp": time.Now().Format(time.RFC3339),
 		})
 	}
 }
 
-// Prometheus Handlers
+type serviceNoteRequest struct {
+	Note      string     `json:"note" binding:"required,max=2000"`
+	Author    string     `json:"author" binding:"max=100"`
+	ExpiresAt *time.Time `json:"expires_at"` // never expires when omitted
+}
 
-func prometheusHealthHandler(observer *observer.MetricsObserver) gin.HandlerFunc {
+func createServiceNoteHandler(db *storage.PostgresClient) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		req, ok := bindJSON[serviceNoteRequest](c)
+		if !ok {
+			return
+		}
+		if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "expires_at must be in the future")
+			return
+		}
+
+		note := &storage.ServiceNote{
+			ServiceName: c.Param("service"),
+			Note:        req.Note,
+			Author:      req.Author,
+			ExpiresAt:   req.ExpiresAt,
+		}
+
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 		defer cancel()
 
-		err := observer.Health(ctx)
-		if err != nil {
-			c.JSON(http.StatusServiceUnavailable, gin.H{
-				"status":    "unhealthy",
-				"error":     err.Error(),
-				"timestamp": time.Now().Format(time.RFC3339),
-			})
+		if err := db.SaveServiceNote(ctx, note); err != nil {
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"status":    "healthy",
-			"message":   "Prometheus is reachable",
-			"timestamp": time.Now().Format(time.RFC3339),
-		})
+		c.JSON(http.StatusCreated, note)
 	}
 }
 
-func prometheusTargetsHandler(observer *observer.MetricsObserver) gin.HandlerFunc {
+func deleteServiceNoteHandler(db *storage.PostgresClient) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		serviceName := c.Param("service")
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "note id must be an integer")
+			return
+		}
+
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 		defer cancel()
 
-		err := observer.Health(ctx)
-		if err != nil {
-			c.JSON(http.StatusServiceUnavailable, gin.H{
-				"error": "Prometheus not available",
-			})
+		if err := db.DeleteServiceNote(ctx, serviceName, id); err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				respondError(c, http.StatusNotFound, errCodeNotFound, fmt.Sprintf("Note %d not found for service %s", id, serviceName))
+				return
+			}
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
 			return
 		}
 
 		c.JSON(http.StatusOK, gin.H{
-			"targets": []map[string]interface{}{
-				{
-					"name":   "sample-app",
-					"url":    "http://sample-app:8080/metrics",
-					"status": "up",
-				},
-			},
+			"deleted":   id,
+			"service":   serviceName,
 			"timestamp": time.Now().Format(time.RFC3339),
 		})
 	}
 }
 
-func prometheusQueryHandler(observer *observer.MetricsObserver) gin.HandlerFunc {
+func replayHandler(ua *analyzer.UltimateAnalyzer) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		query := c.Query("query")
-		service := c.DefaultQuery("service", "sample-app")
+		serviceName := c.Param("service")
 
-		if query == "" {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Query parameter is required. Example: ?query=cpu_usage",
-			})
+		atStr := c.Query("at")
+		if atStr == "" {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "at parameter is required (RFC3339). Example: ?at=2024-01-02T15:04:05Z&window=30m")
+			return
+		}
+		at, err := time.Parse(time.RFC3339, atStr)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "Invalid at timestamp, expected RFC3339")
+			return
+		}
+		if at.After(time.Now()) {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "at must not be in the future")
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+		window, err := time.ParseDuration(c.DefaultQuery("window", "30m"))
+		if err != nil || window <= 0 {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "Invalid window duration")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
 		defer cancel()
 
-		metrics, err := observer.GetCurrentMetrics(ctx, service)
+		diagnosis, err := ua.AnalyzeAtTime(ctx, serviceName, at, window)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to execute query",
-			})
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
 			return
 		}
+		if legacyEvidence(c) {
+			diagnosis = diagnosis.Legacy()
+		}
 
 		c.JSON(http.StatusOK, gin.H{
-			"query":     query,
-			"service":   service,
-			"result":    metrics,
+			"service":   serviceName,
+			"as_of":     at.Format(time.RFC3339),
+			"window":    window.String(),
+			"diagnosis": diagnosis,
 			"timestamp": time.Now().Format(time.RFC3339),
 		})
 	}
 }
 
-func prometheusMetricsSummaryHandler(db *storage.PostgresClient) gin.HandlerFunc {
+func ensembleHandler(ea *analyzer.EnsembleAnalyzer) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		durationStr := c.DefaultQuery("duration", "1h")
+		serviceName := c.Param("service")
 
-		duration, err := time.ParseDuration(durationStr)
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		result, err := ea.Analyze(ctx, serviceName)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Invalid duration format",
-			})
+			logger.FromContext(ctx).Error("Ensemble analysis failed", zap.Error(err))
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
 			return
 		}
+		if legacyEvidence(c) {
+			result = result.Legacy()
+		}
 
-		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+type executeActionRequest struct {
+	ActionType string `json:"action_type" binding:"required,oneof=SCALE_UP RESTART"`
+	Deployment string `json:"deployment"` // defaults to the service name
+}
+
+func executeActionHandler(ua *analyzer.UltimateAnalyzer, executor *actuator.Executor, safeMode *actuator.SafeMode, db *storage.PostgresClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		serviceName := c.Param("service")
+
+		req, ok := bindJSON[executeActionRequest](c)
+		if !ok {
+			return
+		}
+		if req.Deployment == "" {
+			req.Deployment = serviceName
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
 		defer cancel()
 
-		services := []string{"sample-app"}
-		metricTypes := []string{"cpu_usage", "memory_usage", "http_requests"}
+		diagnosis, err := ua.DiagnoseService(ctx, serviceName)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
+			return
+		}
 
-		summary := make(map[string]map[string]interface{})
+		var action *analyzer.ActuatorAction
+		for _, a := range diagnosis.ActuatorActions {
+			if a.ActionType == req.ActionType {
+				action = a
+				break
+			}
+		}
+		if action == nil {
+			respondErrorDetails(c, http.StatusConflict, errCodeConflict,
+				fmt.Sprintf("current diagnosis does not recommend %s for %s", req.ActionType, serviceName),
+				gin.H{"prediction_id": diagnosis.PredictionID})
+			return
+		}
 
-		for _, service := range services {
-			summary[service] = make(map[string]interface{})
-			for _, metricType := range metricTypes {
-				stats, err := db.GetMetricStatistics(ctx, service, metricType, duration)
-				if err != nil {
-					continue
-				}
-				summary[service][metricType] = stats
+		result, err := actuate(ctx, safeMode, executor, serviceName, req.Deployment, diagnosis.PrimaryDetection, action)
+		if err != nil {
+			switch {
+			case errors.Is(err, observer.ErrDeploymentNotFound):
+				respondError(c, http.StatusNotFound, errCodeNotFound, err.Error())
+			case errors.Is(err, actuator.ErrReplicaBounds):
+				respondError(c, http.StatusUnprocessableEntity, errCodeUnprocessable, err.Error())
+			default:
+				respondError(c, http.StatusBadGateway, errCodeUpstreamError, err.Error())
 			}
+			return
+		}
+
+		if err := db.SaveDecision(ctx, actionDecision(serviceName, diagnosis, action, result)); err != nil {
+			logger.FromContext(ctx).Warn("Failed to record actuator decision", zap.Error(err))
 		}
 
 		c.JSON(http.StatusOK, gin.H{
-			"duration":  durationStr,
-			"summary":   summary,
-			"timestamp": time.Now().Format(time.RFC3339),
+			"service":       serviceName,
+			"prediction_id": diagnosis.PredictionID,
+			"action":        action,
+			"result":        result,
+			"timestamp":     time.Now().Format(time.RFC3339),
 		})
 	}
 }
 
-// ==================== AI-LEVEL ANALYZER HANDLERS ====================
-// The ONLY analyzer - All endpoints use the AI-Level Ultimate Analyzer
+// actuate runs an action past safe mode and, if it's allowed, the executor.
+// Only an action that actually executed counts towards actuator.max_actions:
+// recommendations, blocked actions, dry runs and failures don't.
+func actuate(ctx context.Context, safeMode *actuator.SafeMode, executor *actuator.Executor, serviceName, deployment string, detection *analyzer.Detection, action *analyzer.ActuatorAction) (*actuator.ExecutionResult, error) {
+	if !safeMode.AutoRemediates(serviceName) {
+		return actuator.Recommended(deployment, action.ActionType), nil
+	}
+	if reason := safeMode.Admit(detection, time.Now()); reason != "" {
+		logger.FromContext(ctx).Warn("Actuator action blocked",
+			zap.String("service", serviceName),
+			zap.String("action", action.ActionType),
+			zap.String("reason", reason))
+		return actuator.Blocked(deployment, action.ActionType, reason), nil
+	}
+
+	result, err := executor.Execute(ctx, deployment, action)
+	if err != nil {
+		safeMode.Release()
+		return nil, err
+	}
+	if result.Executed {
+		safeMode.Record(result.Timestamp)
+	} else {
+		safeMode.Release()
+	}
+	return result, nil
+}
+
+// actionDecision is the decision recorded for an actuator result, whether
+// the action executed or was only recommended or blocked
+func actionDecision(serviceName string, diagnosis *analyzer.UltimateDiagnosis, action *analyzer.ActuatorAction, result *actuator.ExecutionResult) *storage.Decision {
+	// severity and health_score are the baseline the recovery check compares against
+	params, _ := json.Marshal(gin.H{
+		"service":       serviceName,
+		"prediction_id": diagnosis.PredictionID,
+		"result":        result,
+		"severity":      diagnosis.PrimaryDetection.Severity,
+		"health_score":  diagnosis.HealthScore,
+	})
+	return &storage.Decision{
+		Timestamp:       result.Timestamp,
+		PatternDetected: string(diagnosis.PrimaryDetection.Type),
+		ActionType:      action.ActionType,
+		Confidence:      action.Confidence,
+		Reason:          action.Reason,
+		Parameters:      params,
+		Executed:        result.Executed,
+		ExecutedAt:      executedAt(result),
+		ExecutionResult: result.Message,
+		ServiceName:     serviceName,
+	}
+}
 
-func aiDiagnoseServiceHandler(ua *analyzer.UltimateAnalyzer) gin.HandlerFunc {
+// executedAt is the execution time to record for an actuator result
+func executedAt(result *actuator.ExecutionResult) *time.Time {
+	if !result.Executed {
+		return nil
+	}
+	return &result.Timestamp
+}
+
+// getFeaturesHandler returns the full ServiceFeatures vector for debugging
+// detections or feeding external models
+func getFeaturesHandler(ua *analyzer.UltimateAnalyzer) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		serviceName := c.Param("service")
 
-		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
-		defer cancel()
+		window, err := time.ParseDuration(c.DefaultQuery("window", "30m"))
+		if err != nil || window <= 0 {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "Invalid window format. Use format like: 15m, 30m, 1h")
+			return
+		}
 
-		logger.Info("🤖 AI diagnosis requested",
-			zap.String("service", serviceName),
-			zap.String("client_ip", c.ClientIP()),
-		)
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+		defer cancel()
 
-		diagnosis, err := ua.DiagnoseService(ctx, serviceName)
+		features, err := ua.FeatureExtractor().ExtractFeatures(ctx, serviceName, window)
 		if err != nil {
-			logger.Error("AI diagnosis failed", zap.Error(err))
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
 			return
 		}
 
 		c.JSON(http.StatusOK, gin.H{
-			"service":              diagnosis.ServiceName,
-			"timestamp":            diagnosis.Timestamp.Format(time.RFC3339),
-			"analysis_duration_ms": diagnosis.AnalysisDuration.Milliseconds(),
-
-			"primary_detection": gin.H{
-				"problem":        diagnosis.PrimaryDetection.Type,
-				"detected":       diagnosis.PrimaryDetection.Detected,
-				"confidence":     fmt.Sprintf("%.2f%%", diagnosis.PrimaryDetection.Confidence),
-				"severity":       diagnosis.PrimaryDetection.Severity,
-				"evidence":       diagnosis.PrimaryDetection.Evidence,
-				"recommendation": diagnosis.PrimaryDetection.Recommendation,
-			},
-
-			"health_metrics": gin.H{
-				"health_score":         fmt.Sprintf("%.2f/100", diagnosis.HealthScore),
-				"stability_index":      fmt.Sprintf("%.2f/10", diagnosis.StabilityIndex),
-				"predictability_score": fmt.Sprintf("%.2f/100", diagnosis.PredictabilityScore),
-				"system_stress":        fmt.Sprintf("%.2f/100", diagnosis.SystemStress),
-			},
-
-			"assessment": gin.H{
-				"risk_level":          diagnosis.RiskLevel,
-				"action_required":     diagnosis.ActionRequired,
-				"predictive_insights": diagnosis.PredictiveInsights,
-			},
+			"service":         serviceName,
+			"window":          window.String(),
+			"features":        features,
+			"period_length":   features.PeriodLength.String(),
+			"trend_direction": features.TrendDirection,
+			"timestamp":       time.Now().Format(time.RFC3339),
+		})
+	}
+}
 
-			// Enhanced actuator-ready outputs
-			"root_cause": diagnosis.RootCause,
+func getFeatureDriftHandler(ua *analyzer.UltimateAnalyzer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		serviceName := c.Param("service")
 
-			"actuator_actions": diagnosis.ActuatorActions,
+		days := 14
+		if d := c.Query("days"); d != "" {
+			n, err := strconv.Atoi(d)
+			if err != nil || n <= 0 || n > 365 {
+				respondError(c, http.StatusBadRequest, errCodeBadRequest, "days must be between 1 and 365")
+				return
+			}
+			days = n
+		}
 
-			"impact_assessment": diagnosis.ImpactAssessment,
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+		defer cancel()
 
-			"recommendation": diagnosis.Recommendation,
-			"prediction_id":  diagnosis.PredictionID,
+		drift, err := ua.FeatureDrift(ctx, serviceName, days)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
+			return
+		}
 
-			"all_detections": formatDetections(diagnosis.AllDetections),
+		if drift.Snapshots < analyzer.MinDriftSnapshots {
+			respondErrorDetails(c, http.StatusUnprocessableEntity, errCodeUnprocessable,
+				fmt.Sprintf("insufficient data: need at least %d feature snapshots", analyzer.MinDriftSnapshots),
+				gin.H{"snapshots": drift.Snapshots, "snapshot_interval": ua.SnapshotInterval().String()})
+			return
+		}
 
-			// 🌟 NEW: Comprehensive Enhanced Diagnostics
-			"enhanced_data": diagnosis.EnhancedData,
+		c.JSON(http.StatusOK, gin.H{
+			"drift":     drift,
+			"timestamp": time.Now().Format(time.RFC3339),
 		})
 	}
 }
@@ -918,7 +2891,7 @@ func aiGetFeaturesHandler(ua *analyzer.UltimateAnalyzer) gin.HandlerFunc {
 
 		features, err := ua.FeatureExtractor().ExtractFeatures(ctx, serviceName, 30*time.Minute)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
 			return
 		}
 
@@ -991,11 +2964,11 @@ func aiDetectMemoryLeakHandler(ua *analyzer.UltimateAnalyzer) gin.HandlerFunc {
 
 		detection, err := ua.EnhancedDetector().DetectMemoryLeakEnhanced(ctx, serviceName)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
 			return
 		}
 
-		c.JSON(http.StatusOK, formatDetection(detection))
+		c.JSON(http.StatusOK, formatDetection(withEvidenceFormat(c, detection)))
 	}
 }
 
@@ -1007,11 +2980,11 @@ func aiDetectResourceExhaustionHandler(ua *analyzer.UltimateAnalyzer) gin.Handle
 
 		detection, err := ua.EnhancedDetector().DetectResourceExhaustionEnhanced(ctx, serviceName)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
 			return
 		}
 
-		c.JSON(http.StatusOK, formatDetection(detection))
+		c.JSON(http.StatusOK, formatDetection(withEvidenceFormat(c, detection)))
 	}
 }
 
@@ -1023,11 +2996,11 @@ func aiDetectDeploymentBugHandler(ua *analyzer.UltimateAnalyzer) gin.HandlerFunc
 
 		detection, err := ua.EnhancedDetector().DetectDeploymentBugEnhanced(ctx, serviceName)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
 			return
 		}
 
-		c.JSON(http.StatusOK, formatDetection(detection))
+		c.JSON(http.StatusOK, formatDetection(withEvidenceFormat(c, detection)))
 	}
 }
 
@@ -1039,11 +3012,11 @@ func aiDetectExternalFailureHandler(ua *analyzer.UltimateAnalyzer) gin.HandlerFu
 
 		detection, err := ua.EnhancedDetector().DetectExternalFailureEnhanced(ctx, serviceName)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
 			return
 		}
 
-		c.JSON(http.StatusOK, formatDetection(detection))
+		c.JSON(http.StatusOK, formatDetection(withEvidenceFormat(c, detection)))
 	}
 }
 
@@ -1055,12 +3028,67 @@ func aiDetectCascadeHandler(ua *analyzer.UltimateAnalyzer) gin.HandlerFunc {
 
 		detection, err := ua.EnhancedDetector().DetectCascadeFailureEnhanced(ctx, serviceName)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
+			return
+		}
+
+		c.JSON(http.StatusOK, formatDetection(withEvidenceFormat(c, detection)))
+	}
+}
+
+func detectGCPressureHandler(ua *analyzer.UltimateAnalyzer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		serviceName := c.Param("service")
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+		defer cancel()
+
+		detection, err := ua.EnhancedDetector().DetectGCPressure(ctx, serviceName)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
 			return
 		}
 
-		c.JSON(http.StatusOK, formatDetection(detection))
+		c.JSON(http.StatusOK, formatDetection(withEvidenceFormat(c, detection)))
+	}
+}
+
+// getDetectorsHandler lists the detectors a diagnosis runs, whether each is
+// enabled, and the thresholds in effect; ?service applies that service's
+// per-service overrides
+func getDetectorsHandler(ua *analyzer.UltimateAnalyzer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		serviceName := c.Query("service")
+		detectors := ua.Detectors(serviceName)
+
+		enabled := 0
+		for _, d := range detectors {
+			if d.Enabled {
+				enabled++
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"service":   serviceName,
+			"detectors": detectors,
+			"count":     len(detectors),
+			"enabled":   enabled,
+			"timestamp": time.Now().Format(time.RFC3339),
+		})
+	}
+}
+
+// legacyEvidence reports whether the request asks for ?format=legacy, which
+// renders numeric evidence as the formatted strings older clients parse
+func legacyEvidence(c *gin.Context) bool {
+	return c.Query("format") == analyzer.EvidenceFormatLegacy
+}
+
+// withEvidenceFormat applies ?format=legacy to a detection
+func withEvidenceFormat(c *gin.Context, d *analyzer.Detection) *analyzer.Detection {
+	if legacyEvidence(c) {
+		return d.Legacy()
 	}
+	return d
 }
 
 // Helper functions for AI endpoints
@@ -1073,7 +3101,13 @@ func formatDetection(d *analyzer.Detection) gin.H {
 		"severity":       d.Severity,
 		"evidence":       d.Evidence,
 		"recommendation": d.Recommendation,
+		"status":         d.Status,
 		"timestamp":      d.Timestamp.Format(time.RFC3339),
+
+		// Confidence before the quality gates, and the factor they applied
+		"aggregate_confidence": fmt.Sprintf("%.2f%%", d.AggregateConfidence),
+		"dampening":            d.Dampening,
+		"dampening_reasons":    d.DampeningReasons,
 	}
 }
 
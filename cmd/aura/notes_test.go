@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+)
+
+// notesRouter serves the service notes endpoints over db
+func notesRouter(db *storage.PostgresClient) *gin.Engine {
+	router := gin.New()
+	router.GET("/api/v1/services/:service/notes", getServiceNotesHandler(db))
+	router.POST("/api/v1/services/:service/notes", createServiceNoteHandler(db))
+	router.DELETE("/api/v1/services/:service/notes/:id", deleteServiceNoteHandler(db))
+	return router
+}
+
+func TestServiceNoteHandlersRejectBadRequests(t *testing.T) {
+	router := notesRouter(nil)
+	past := time.Now().Add(-time.Hour).Format(time.RFC3339)
+
+	tests := []struct {
+		name, method, path, body string
+		code                     string
+	}{
+		{"missing note", http.MethodPost, "/api/v1/services/checkout/notes", `{"author":"oncall"}`, errCodeValidation},
+		{"note too long", http.MethodPost, "/api/v1/services/checkout/notes", `{"note":"` + strings.Repeat("x", 2001) + `"}`, errCodeValidation},
+		{"expiry in the past", http.MethodPost, "/api/v1/services/checkout/notes", `{"note":"noisy","expires_at":"` + past + `"}`, errCodeBadRequest},
+		{"non-integer id", http.MethodDelete, "/api/v1/services/checkout/notes/latest", "", errCodeBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(router, tt.method, tt.path, tt.body, nil)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400: %s", w.Code, w.Body.String())
+			}
+			if apiErr := decodeAPIError(t, w); apiErr.Code != tt.code {
+				t.Errorf("code = %s, want %s", apiErr.Code, tt.code)
+			}
+		})
+	}
+}
+
+func TestServiceNoteHandlersCRUD(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	router := notesRouter(db)
+	path := "/api/v1/services/" + service + "/notes"
+
+	soon := time.Now().Add(2 * time.Second).UTC().Format(time.RFC3339Nano)
+	w := serve(router, http.MethodPost, path, `{"note":"deploy freeze","author":"oncall","expires_at":"`+soon+`"}`, nil)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("POST expiring note: status = %d, want 201: %s", w.Code, w.Body.String())
+	}
+	w = serve(router, http.MethodPost, path, `{"note":"known noisy before 9am"}`, nil)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("POST note: status = %d, want 201: %s", w.Code, w.Body.String())
+	}
+	var created storage.ServiceNote
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil || created.ID == 0 || created.ServiceName != service {
+		t.Fatalf("created note = %s (err %v), want it with an id", w.Body.String(), err)
+	}
+
+	list := func(query string) []*storage.ServiceNote {
+		t.Helper()
+		w := serve(router, http.MethodGet, path+query, "", nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET%s: status = %d, want 200", query, w.Code)
+		}
+		var body struct {
+			Notes []*storage.ServiceNote `json:"notes"`
+			Count int                    `json:"count"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body.Count != len(body.Notes) {
+			t.Fatalf("GET%s: body = %s (err %v)", query, w.Body.String(), err)
+		}
+		return body.Notes
+	}
+
+	if notes := list(""); len(notes) != 2 {
+		t.Fatalf("notes in effect = %d, want both", len(notes))
+	}
+	time.Sleep(3 * time.Second)
+	if notes := list(""); len(notes) != 1 || notes[0].ID != created.ID {
+		t.Fatalf("notes in effect after the expiry = %+v, want the permanent one", notes)
+	}
+	if notes := list("?all=true"); len(notes) != 2 {
+		t.Fatalf("all notes = %d, want the expired one too", len(notes))
+	}
+
+	notePath := path + "/" + strconv.FormatInt(created.ID, 10)
+	if w := serve(router, http.MethodDelete, notePath, "", nil); w.Code != http.StatusOK {
+		t.Fatalf("DELETE: status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	if notes := list(""); len(notes) != 0 {
+		t.Errorf("notes in effect after delete = %+v, want none", notes)
+	}
+	w = serve(router, http.MethodDelete, notePath, "", nil)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("second DELETE: status = %d, want 404", w.Code)
+	}
+	if apiErr := decodeAPIError(t, w); apiErr.Code != errCodeNotFound {
+		t.Errorf("second DELETE: code = %s, want %s", apiErr.Code, errCodeNotFound)
+	}
+}
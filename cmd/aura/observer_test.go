@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/observer"
+	"go.uber.org/zap"
+)
+
+// newTestObserver returns an observer scraping prometheusURL every interval,
+// kept off any real cluster
+func newTestObserver(t *testing.T, prometheusURL string, interval time.Duration) *observer.MetricsObserver {
+	t.Helper()
+
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "missing"))
+
+	metricsObserver, err := observer.NewMetricsObserver(prometheusURL, interval, []string{"default"}, nil, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewMetricsObserver: %v", err)
+	}
+	return metricsObserver
+}
+
+func TestObserverHealthReportsConfiguredIntervals(t *testing.T) {
+	metricsObserver := newTestObserver(t, "http://localhost:9090", 15*time.Second)
+	metricsObserver.Prometheus().SetMetricIntervals(map[string]time.Duration{"response_time_p99_ms": time.Minute})
+
+	router := gin.New()
+	router.GET("/api/v1/observer/health", observerHealthHandler(metricsObserver))
+
+	w := serve(router, http.MethodGet, "/api/v1/observer/health", "", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+
+	var resp struct {
+		Interval        string            `json:"interval"`
+		MetricIntervals map[string]string `json:"metric_intervals"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Interval != "15s" {
+		t.Errorf("interval = %q, want the configured 15s", resp.Interval)
+	}
+	if got := resp.MetricIntervals["response_time_p99_ms"]; got != "1m0s" {
+		t.Errorf("response_time_p99_ms interval = %q, want 1m0s", got)
+	}
+	if got := resp.MetricIntervals["cpu_usage"]; got != "15s" {
+		t.Errorf("cpu_usage interval = %q, want the base 15s", got)
+	}
+}
+
+func TestObserverDegradedWhenPrometheusUnreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	metricsObserver := newTestObserver(t, srv.URL, 15*time.Second)
+
+	status := metricsObserver.Probe(context.Background())
+	if !status.Degraded() || status.DegradedSince == nil {
+		t.Fatalf("Probe = %+v, want degraded", status)
+	}
+
+	router := gin.New()
+	router.GET("/api/v1/observer/health", observerHealthHandler(metricsObserver))
+	router.GET("/api/v1/prometheus/health", prometheusHealthHandler(metricsObserver))
+
+	w := serve(router, http.MethodGet, "/api/v1/observer/health", "", nil)
+	var health struct {
+		Status     string                   `json:"status"`
+		Prometheus observer.ReadinessStatus `json:"prometheus"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &health); err != nil {
+		t.Fatalf("decode observer health: %v", err)
+	}
+	if w.Code != http.StatusOK || health.Status != "degraded" || health.Prometheus.State != observer.StateDegraded {
+		t.Errorf("observer health = %d %+v, want 200 and degraded", w.Code, health)
+	}
+
+	w = serve(router, http.MethodGet, "/api/v1/prometheus/health", "", nil)
+	var prom struct {
+		Status        string     `json:"status"`
+		ScraperState  string     `json:"scraper_state"`
+		DegradedSince *time.Time `json:"degraded_since"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &prom); err != nil {
+		t.Fatalf("decode prometheus health: %v", err)
+	}
+	if w.Code != http.StatusServiceUnavailable || prom.ScraperState != observer.StateDegraded || prom.DegradedSince == nil {
+		t.Errorf("prometheus health = %d %+v, want 503 with the degraded scraper", w.Code, prom)
+	}
+}
+
+func TestPrometheusQueryHandler(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("query") == "slow" {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"job":"api"},"value":[%d,"1"]}]}}`, time.Now().Unix())
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(release) })
+
+	metricsObserver := newTestObserver(t, srv.URL, 15*time.Second)
+	metricsObserver.Prometheus().SetTimeouts(0, 50*time.Millisecond)
+
+	router := gin.New()
+	router.GET("/api/v1/prometheus/query", prometheusQueryHandler(metricsObserver))
+
+	w := serve(router, http.MethodGet, "/api/v1/prometheus/query?query=up", "", nil)
+	var resp struct {
+		Query   string            `json:"query"`
+		Result  []json.RawMessage `json:"result"`
+		Timeout string            `json:"timeout"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if w.Code != http.StatusOK || resp.Query != "up" || len(resp.Result) != 1 || resp.Timeout != "50ms" {
+		t.Errorf("query = %d %+v, want 200 with the one series and the 50ms timeout", w.Code, resp)
+	}
+
+	w = serve(router, http.MethodGet, "/api/v1/prometheus/query?query=slow", "", nil)
+	if w.Code != http.StatusGatewayTimeout {
+		t.Fatalf("slow query: status = %d, want 504", w.Code)
+	}
+	if apiErr := decodeAPIError(t, w); apiErr.Code != errCodeUpstreamTimeout {
+		t.Errorf("slow query: code = %s, want %s", apiErr.Code, errCodeUpstreamTimeout)
+	}
+
+	if w = serve(router, http.MethodGet, "/api/v1/prometheus/query", "", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("missing query: status = %d, want 400", w.Code)
+	}
+}
+
+func TestSummarizePods(t *testing.T) {
+	summary := summarizePods([]observer.PodMetric{
+		{Name: "checkout-1", Namespace: "shop", Phase: "Running", Restarts: 2},
+		{Name: "cart-1", Namespace: "shop", Phase: "Pending"},
+		{Name: "invoices-1", Namespace: "billing", Phase: "Failed", Restarts: 5},
+		{Name: "report-1", Namespace: "billing", Phase: "Succeeded"},
+	})
+
+	want := map[string]int{"total_pods": 4, "running_pods": 1, "pending_pods": 1, "failed_pods": 1, "total_restarts": 7}
+	for key, n := range want {
+		if summary[key] != n {
+			t.Errorf("%s = %v, want %d", key, summary[key], n)
+		}
+	}
+}
+
+func TestKubernetesHandlersWithoutKubernetes(t *testing.T) {
+	metricsObserver := newTestObserver(t, "http://localhost:9090", 15*time.Second)
+
+	router := gin.New()
+	router.GET("/api/v1/kubernetes/pods", getPodsHandler(metricsObserver))
+	router.GET("/api/v1/kubernetes/namespace/summary", getNamespaceSummaryHandler(metricsObserver, nil))
+
+	for _, path := range []string{"/api/v1/kubernetes/pods", "/api/v1/kubernetes/namespace/summary?namespace=shop"} {
+		w := serve(router, http.MethodGet, path, "", nil)
+		if w.Code != http.StatusServiceUnavailable {
+			t.Errorf("%s: status = %d, want 503", path, w.Code)
+			continue
+		}
+		if apiErr := decodeAPIError(t, w); apiErr.Code != errCodeUpstreamUnavailable {
+			t.Errorf("%s: code = %s, want %s", path, apiErr.Code, errCodeUpstreamUnavailable)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+)
+
+func TestPoolOptionsFromConfig(t *testing.T) {
+	config := &core.Config{}
+	config.Database.Pool.MaxConns = 40
+	config.Database.Pool.MinConns = 8
+	config.Database.Pool.MaxConnLifetime = "20m"
+	config.Database.Pool.ConnectTimeout = "4s"
+	config.Database.Pool.QueryExecMode = "exec"
+	config.Database.Pool.StatementCacheCapacity = 128
+	config.ApplyDefaults()
+
+	opts := poolOptions(config)
+	if opts.MaxConns != 40 || opts.MinConns != 8 || opts.MaxConnLifetime != 20*time.Minute || opts.ConnectTimeout != 4*time.Second {
+		t.Errorf("poolOptions = %+v, want the configured sizes and durations", opts)
+	}
+	if opts.QueryExecMode != storage.QueryExecExec || opts.StatementCacheCapacity != 128 {
+		t.Errorf("poolOptions mode %q, cache %d, want exec and 128", opts.QueryExecMode, opts.StatementCacheCapacity)
+	}
+	// Unset durations stay zero so storage keeps its defaults
+	if opts.MaxConnIdleTime != 0 || opts.HealthCheckPeriod != 0 {
+		t.Errorf("unset durations = %v, %v, want 0", opts.MaxConnIdleTime, opts.HealthCheckPeriod)
+	}
+
+	// max_conns falls back to max_connections
+	legacy := &core.Config{}
+	legacy.Database.MaxConnections = 12
+	legacy.ApplyDefaults()
+	if got := poolOptions(legacy).MaxConns; got != 12 {
+		t.Errorf("MaxConns = %d, want max_connections' 12", got)
+	}
+}
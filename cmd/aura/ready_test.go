@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/observer"
+)
+
+func TestNotReadyReason(t *testing.T) {
+	starting := observer.ReadinessStatus{State: observer.StateStarting}
+	unreachable := observer.ReadinessStatus{State: observer.StateDegraded, Initialized: true}
+	scraped := observer.ReadinessStatus{State: observer.StateHealthy, Initialized: true, Scraped: true}
+
+	tests := []struct {
+		name      string
+		scraper   observer.ReadinessStatus
+		required  bool
+		accepting bool
+		ready     bool
+	}{
+		{name: "before the first scrape", scraper: starting, accepting: true},
+		{name: "before the first scrape, prometheus required", scraper: starting, required: true, accepting: true},
+		{name: "prometheus optional and unreachable", scraper: unreachable, accepting: true, ready: true},
+		{name: "prometheus required and unreachable", scraper: unreachable, required: true, accepting: true},
+		{name: "scraped", scraper: scraped, required: true, accepting: true, ready: true},
+		{name: "buffer full", scraper: scraped, accepting: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason := notReadyReason(tt.scraper, tt.required, tt.accepting)
+			if ready := reason == ""; ready != tt.ready {
+				t.Errorf("ready = %v (reason %q), want %v", ready, reason, tt.ready)
+			}
+		})
+	}
+}
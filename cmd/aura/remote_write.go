@@ -0,0 +1,266 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/snappy"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/pkg/logger"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	// maxRemoteWriteBodyBytes bounds a compressed remote_write body. Shards
+	// with many series or long label values run past http.max_body_bytes.
+	maxRemoteWriteBodyBytes = 16 << 20 // 16 MiB
+
+	// maxRemoteWriteDecodedBytes bounds a remote_write body once decompressed
+	maxRemoteWriteDecodedBytes = 32 << 20 // 32 MiB
+
+	// maxRemoteWriteNameLength matches the metrics table's service_name and
+	// metric_name columns; longer names would fail the whole batch
+	maxRemoteWriteNameLength = 100
+)
+
+// promSeries is one TimeSeries of a remote_write WriteRequest
+type promSeries struct {
+	labels  map[string]string
+	samples []promSample
+}
+
+type promSample struct {
+	value       float64
+	timestampMs int64
+}
+
+// remoteWriteHandler accepts Prometheus remote_write (protocol 1.0): a
+// snappy-compressed protobuf WriteRequest. Series are mapped to services and
+// metrics by prometheus.remote_write and queued on the metric buffer. A body
+// that doesn't decompress or decode is answered 400, which Prometheus does
+// not retry; a full buffer is answered 503, which it does.
+func remoteWriteHandler(buffer *storage.MetricBuffer, config *core.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if strings.Contains(c.GetHeader("Content-Type"), "io.prometheus.write.v2") {
+			respondError(c, http.StatusUnsupportedMediaType, errCodeBadRequest, "only remote_write protocol 1.0 (prometheus.WriteRequest) is supported")
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				respondError(c, http.StatusRequestEntityTooLarge, errCodePayloadTooLarge, fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit))
+				return
+			}
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, "failed to read request body")
+			return
+		}
+
+		series, err := decodeWriteRequest(body)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, errCodeBadRequest, err.Error())
+			return
+		}
+
+		now := time.Now()
+		metrics, dropped := remoteWriteMetrics(series, config, now)
+		if len(metrics) > 0 {
+			if err := buffer.Add(metrics); err != nil {
+				if errors.Is(err, storage.ErrBufferFull) {
+					c.Header("Retry-After", "1")
+				}
+				respondError(c, http.StatusServiceUnavailable, errCodeUpstreamUnavailable, err.Error())
+				return
+			}
+		}
+
+		logger.FromContext(c.Request.Context()).Debug("Received remote_write samples",
+			zap.Int("series", len(series)),
+			zap.Int("accepted", len(metrics)),
+			zap.Int("dropped", dropped),
+		)
+
+		c.JSON(http.StatusAccepted, gin.H{
+			"accepted":  len(metrics),
+			"dropped":   dropped,
+			"timestamp": now.Format(time.RFC3339),
+		})
+	}
+}
+
+// decodeWriteRequest decompresses a snappy block and decodes the
+// WriteRequest inside it
+func decodeWriteRequest(compressed []byte) ([]promSeries, error) {
+	size, err := snappy.DecodedLen(compressed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snappy-compressed body: %w", err)
+	}
+	if size > maxRemoteWriteDecodedBytes {
+		return nil, fmt.Errorf("decompressed body of %d bytes exceeds %d", size, maxRemoteWriteDecodedBytes)
+	}
+	raw, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snappy-compressed body: %w", err)
+	}
+
+	series, err := parseWriteRequest(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid WriteRequest protobuf: %w", err)
+	}
+	return series, nil
+}
+
+// parseWriteRequest reads the fields of prometheus.WriteRequest AURA uses:
+// timeseries (1), each with labels (1: name, value) and samples (2: value,
+// timestamp in ms). Metadata, exemplars, histograms and unknown fields are
+// skipped.
+func parseWriteRequest(b []byte) ([]promSeries, error) {
+	var series []promSeries
+	err := walkProtoFields(b, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		if num != 1 || typ != protowire.BytesType {
+			return nil
+		}
+		body, _ := protowire.ConsumeBytes(value)
+
+		s := promSeries{labels: make(map[string]string)}
+		err := walkProtoFields(body, func(num protowire.Number, typ protowire.Type, value []byte) error {
+			if typ != protowire.BytesType {
+				return nil
+			}
+			body, _ := protowire.ConsumeBytes(value)
+			switch num {
+			case 1:
+				var name, val string
+				err := walkProtoFields(body, func(num protowire.Number, typ protowire.Type, value []byte) error {
+					if typ != protowire.BytesType {
+						return nil
+					}
+					str, _ := protowire.ConsumeBytes(value)
+					switch num {
+					case 1:
+						name = string(str)
+					case 2:
+						val = string(str)
+					}
+					return nil
+				})
+				if err != nil {
+					return fmt.Errorf("label: %w", err)
+				}
+				s.labels[name] = val
+			case 2:
+				var sample promSample
+				err := walkProtoFields(body, func(num protowire.Number, typ protowire.Type, value []byte) error {
+					switch {
+					case num == 1 && typ == protowire.Fixed64Type:
+						bits, _ := protowire.ConsumeFixed64(value)
+						sample.value = math.Float64frombits(bits)
+					case num == 2 && typ == protowire.VarintType:
+						ts, _ := protowire.ConsumeVarint(value)
+						sample.timestampMs = int64(ts)
+					}
+					return nil
+				})
+				if err != nil {
+					return fmt.Errorf("sample: %w", err)
+				}
+				s.samples = append(s.samples, sample)
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("timeseries %d: %w", len(series), err)
+		}
+		series = append(series, s)
+		return nil
+	})
+	return series, err
+}
+
+// walkProtoFields calls fn with each field of an encoded message: its
+// number, wire type and encoded value
+func walkProtoFields(b []byte, fn func(num protowire.Number, typ protowire.Type, value []byte) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		m := protowire.ConsumeFieldValue(num, typ, b)
+		if m < 0 {
+			return protowire.ParseError(m)
+		}
+		if err := fn(num, typ, b[:m]); err != nil {
+			return err
+		}
+		b = b[m:]
+	}
+	return nil
+}
+
+// remoteWriteMetrics maps received series onto metrics. It returns the
+// metrics and how many samples were dropped: those of series without a
+// service or name, of unmapped series under drop_unmapped, stale markers
+// (NaN) and samples too far in the future.
+func remoteWriteMetrics(series []promSeries, config *core.Config, now time.Time) ([]*storage.Metric, int) {
+	relabel := config.Prometheus.RemoteWrite
+
+	var metrics []*storage.Metric
+	dropped := 0
+	for _, s := range series {
+		name := s.labels["__name__"]
+		var service string
+		for _, label := range relabel.ServiceLabels {
+			if service = s.labels[label]; service != "" {
+				break
+			}
+		}
+		metricName, mapped := relabel.MetricNames[name]
+		if !mapped {
+			metricName = name
+		}
+		if service == "" || metricName == "" || (!mapped && relabel.DropUnmapped) ||
+			len(service) > maxRemoteWriteNameLength || len(metricName) > maxRemoteWriteNameLength {
+			dropped += len(s.samples)
+			continue
+		}
+
+		labels := map[string]string{"source": "remote_write"}
+		for k, v := range s.labels {
+			if k != "__name__" {
+				labels[k] = v
+			}
+		}
+		encoded, err := json.Marshal(labels)
+		if err != nil {
+			dropped += len(s.samples)
+			continue
+		}
+
+		for _, sample := range s.samples {
+			timestamp := time.UnixMilli(sample.timestampMs)
+			if math.IsNaN(sample.value) || math.IsInf(sample.value, 0) || timestamp.After(now.Add(maxIngestClockSkew)) {
+				dropped++
+				continue
+			}
+			metrics = append(metrics, &storage.Metric{
+				Timestamp:   timestamp,
+				ServiceName: service,
+				MetricName:  metricName,
+				MetricValue: sample.value,
+				Labels:      encoded,
+			})
+		}
+	}
+	return metrics, dropped
+}
@@ -0,0 +1,375 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/snappy"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// encodeWriteRequest encodes series as a snappy-compressed
+// prometheus.WriteRequest, the body remote_write sends
+func encodeWriteRequest(series ...promSeries) []byte {
+	var req []byte
+	for _, s := range series {
+		var ts []byte
+		for name, value := range s.labels {
+			var label []byte
+			label = protowire.AppendTag(label, 1, protowire.BytesType)
+			label = protowire.AppendString(label, name)
+			label = protowire.AppendTag(label, 2, protowire.BytesType)
+			label = protowire.AppendString(label, value)
+			ts = protowire.AppendTag(ts, 1, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, label)
+		}
+		for _, sample := range s.samples {
+			var encoded []byte
+			encoded = protowire.AppendTag(encoded, 1, protowire.Fixed64Type)
+			encoded = protowire.AppendFixed64(encoded, math.Float64bits(sample.value))
+			encoded = protowire.AppendTag(encoded, 2, protowire.VarintType)
+			encoded = protowire.AppendVarint(encoded, uint64(sample.timestampMs))
+			ts = protowire.AppendTag(ts, 2, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, encoded)
+		}
+		req = protowire.AppendTag(req, 1, protowire.BytesType)
+		req = protowire.AppendBytes(req, ts)
+	}
+	return snappy.Encode(nil, req)
+}
+
+func remoteWriteConfig() *core.Config {
+	config := &core.Config{}
+	config.ApplyDefaults()
+	config.Prometheus.RemoteWrite.MetricNames = map[string]string{"container_cpu_percent": "cpu_usage"}
+	return config
+}
+
+// newRemoteWriteRouter serves remote_write into buffer behind the body
+// limits main installs; without a database behind it, accepted samples stay
+// pending for inspection
+func newRemoteWriteRouter(config *core.Config, buffer *storage.MetricBuffer) *gin.Engine {
+	router := gin.New()
+	router.Use(limitRequestBody(config.HTTP.MaxBodyBytes, routeBodyLimits(config)))
+	router.POST("/api/v1/prometheus/remote_write", remoteWriteHandler(buffer, config))
+	return router
+}
+
+type remoteWriteResponse struct {
+	Accepted int `json:"accepted"`
+	Dropped  int `json:"dropped"`
+}
+
+func TestDecodeWriteRequestRoundTrip(t *testing.T) {
+	body := encodeWriteRequest(promSeries{
+		labels:  map[string]string{"__name__": "container_cpu_percent", "job": "checkout", "pod": "checkout-1"},
+		samples: []promSample{{value: 41.5, timestampMs: 1767268800000}, {value: 43, timestampMs: 1767268815000}},
+	})
+
+	series, err := decodeWriteRequest(body)
+	if err != nil {
+		t.Fatalf("decodeWriteRequest: %v", err)
+	}
+	if len(series) != 1 || len(series[0].labels) != 3 || series[0].labels["pod"] != "checkout-1" {
+		t.Fatalf("series = %+v, want the one series with its 3 labels", series)
+	}
+	if s := series[0].samples; len(s) != 2 || s[0].value != 41.5 || s[1].timestampMs != 1767268815000 {
+		t.Errorf("samples = %+v, want both decoded", s)
+	}
+}
+
+func TestParseWriteRequestMalformed(t *testing.T) {
+	// field 1 (timeseries) wrapping the given message bytes
+	timeseries := func(body []byte) []byte {
+		b := protowire.AppendTag(nil, 1, protowire.BytesType)
+		return protowire.AppendBytes(b, body)
+	}
+
+	tests := []struct {
+		name string
+		raw  []byte
+		want string
+	}{
+		{"truncated tag", []byte{0x80}, ""},
+		{"missing length", []byte{0x0a}, ""},
+		{"length past the end", []byte{0x0a, 0x05, 0x01, 0x02}, ""},
+		{"truncated varint field", []byte{0x08, 0xff}, ""},
+		{"reserved wire type", []byte{0x0f, 0x00}, ""},
+		// a label whose name claims 5 bytes but carries 2
+		{"truncated label", timeseries([]byte{0x0a, 0x04, 0x0a, 0x05, 'a', 'b'}), "label"},
+		// a sample whose fixed64 value is cut short
+		{"truncated sample value", timeseries([]byte{0x12, 0x03, 0x09, 0x00, 0x00}), "sample"},
+		// a sample whose timestamp varint never terminates
+		{"truncated sample timestamp", timeseries([]byte{0x12, 0x02, 0x10, 0xff}), "sample"},
+		// a valid series followed by a cut-off one
+		{"truncated second series", append(timeseries([]byte{0x0a, 0x04, 0x0a, 0x01, 'a', 0x12, 0x00}), 0x0a, 0x09, 0x0a), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series, err := parseWriteRequest(tt.raw)
+			if err == nil {
+				t.Fatalf("parseWriteRequest = %+v, want an error", series)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+
+	// Every prefix of a valid request either parses or errors, never panics
+	body, err := snappy.Decode(nil, encodeWriteRequest(promSeries{
+		labels:  map[string]string{"__name__": "container_cpu_percent", "job": "checkout"},
+		samples: []promSample{{value: 41.5, timestampMs: 1767268800000}},
+	}))
+	if err != nil {
+		t.Fatalf("snappy.Decode: %v", err)
+	}
+	for i := range body {
+		_, _ = parseWriteRequest(body[:i])
+	}
+}
+
+func TestParseWriteRequestSkipsUnknownFields(t *testing.T) {
+	var sample []byte
+	sample = protowire.AppendTag(sample, 1, protowire.Fixed64Type)
+	sample = protowire.AppendFixed64(sample, math.Float64bits(7))
+	sample = protowire.AppendTag(sample, 9, protowire.VarintType) // unknown
+	sample = protowire.AppendVarint(sample, 1)
+
+	var label []byte
+	label = protowire.AppendTag(label, 1, protowire.BytesType)
+	label = protowire.AppendString(label, "job")
+	label = protowire.AppendTag(label, 2, protowire.BytesType)
+	label = protowire.AppendString(label, "checkout")
+
+	var ts []byte
+	ts = protowire.AppendTag(ts, 1, protowire.BytesType)
+	ts = protowire.AppendBytes(ts, label)
+	ts = protowire.AppendTag(ts, 2, protowire.BytesType)
+	ts = protowire.AppendBytes(ts, sample)
+	ts = protowire.AppendTag(ts, 3, protowire.BytesType) // exemplars
+	ts = protowire.AppendBytes(ts, []byte{0x01})
+
+	var req []byte
+	req = protowire.AppendTag(req, 1, protowire.BytesType)
+	req = protowire.AppendBytes(req, ts)
+	req = protowire.AppendTag(req, 3, protowire.BytesType) // metadata
+	req = protowire.AppendBytes(req, []byte{0x08, 0x01})
+
+	series, err := parseWriteRequest(req)
+	if err != nil {
+		t.Fatalf("parseWriteRequest: %v", err)
+	}
+	if len(series) != 1 || series[0].labels["job"] != "checkout" || len(series[0].samples) != 1 || series[0].samples[0].value != 7 {
+		t.Errorf("series = %+v, want the one series with its label and sample", series)
+	}
+}
+
+func TestRemoteWriteMetrics(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := now.Add(-time.Minute).UnixMilli()
+	series := []promSeries{
+		// Mapped name, service from job
+		{labels: map[string]string{"__name__": "container_cpu_percent", "job": "checkout", "pod": "checkout-1"},
+			samples: []promSample{{41.5, at}, {math.NaN(), at}, {50, now.Add(time.Hour).UnixMilli()}}},
+		// service wins over job; unmapped name kept as is
+		{labels: map[string]string{"__name__": "queue_depth", "service": "payments", "job": "batch"},
+			samples: []promSample{{7, at}}},
+		// No service label
+		{labels: map[string]string{"__name__": "up"}, samples: []promSample{{1, at}}},
+	}
+
+	metrics, dropped := remoteWriteMetrics(series, remoteWriteConfig(), now)
+
+	if dropped != 3 {
+		t.Errorf("dropped = %d, want the stale marker, the future sample and the unlabelled series", dropped)
+	}
+	if len(metrics) != 2 {
+		t.Fatalf("got %d metrics, want 2", len(metrics))
+	}
+	cpu := metrics[0]
+	if cpu.ServiceName != "checkout" || cpu.MetricName != "cpu_usage" || cpu.MetricValue != 41.5 || !cpu.Timestamp.Equal(time.UnixMilli(at)) {
+		t.Errorf("cpu = %+v, want checkout cpu_usage 41.5", cpu)
+	}
+	var labels map[string]string
+	if err := json.Unmarshal(cpu.Labels, &labels); err != nil {
+		t.Fatalf("labels %s: %v", cpu.Labels, err)
+	}
+	if labels["source"] != "remote_write" || labels["pod"] != "checkout-1" || labels["__name__"] != "" {
+		t.Errorf("labels = %v, want pod and source without __name__", labels)
+	}
+	if queue := metrics[1]; queue.ServiceName != "payments" || queue.MetricName != "queue_depth" {
+		t.Errorf("queue = %+v, want payments queue_depth", queue)
+	}
+
+	config := remoteWriteConfig()
+	config.Prometheus.RemoteWrite.DropUnmapped = true
+	if metrics, dropped := remoteWriteMetrics(series[1:2], config, now); len(metrics) != 0 || dropped != 1 {
+		t.Errorf("with drop_unmapped: %d metrics, %d dropped, want the unmapped series dropped", len(metrics), dropped)
+	}
+
+	long := []promSeries{{labels: map[string]string{"__name__": string(make([]byte, maxRemoteWriteNameLength+1)), "job": "checkout"}, samples: []promSample{{1, at}}}}
+	if metrics, dropped := remoteWriteMetrics(long, remoteWriteConfig(), now); len(metrics) != 0 || dropped != 1 {
+		t.Errorf("overlong name: %d metrics, %d dropped, want it dropped", len(metrics), dropped)
+	}
+}
+
+func TestRemoteWriteHandlerQueuesSamples(t *testing.T) {
+	buffer := storage.NewMetricBuffer(nil, 100, 100, time.Minute)
+	router := newRemoteWriteRouter(remoteWriteConfig(), buffer)
+
+	at := time.Now().Add(-time.Minute).UnixMilli()
+	body := encodeWriteRequest(
+		promSeries{labels: map[string]string{"__name__": "container_cpu_percent", "job": "checkout"},
+			samples: []promSample{{41.5, at}, {43, at + 15000}, {math.NaN(), at + 30000}}},
+		promSeries{labels: map[string]string{"__name__": "up"}, samples: []promSample{{1, at}}},
+	)
+
+	w := serve(router, http.MethodPost, "/api/v1/prometheus/remote_write", string(body), map[string]string{"Content-Type": "application/x-protobuf"})
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202: %s", w.Code, w.Body.String())
+	}
+	var resp remoteWriteResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Accepted != 2 || resp.Dropped != 2 {
+		t.Errorf("response = %s (err %v), want 2 accepted and 2 dropped", w.Body.String(), err)
+	}
+	if pending := buffer.Stats().Pending; pending != 2 {
+		t.Errorf("pending = %d, want the 2 accepted samples queued", pending)
+	}
+}
+
+func TestRemoteWriteHandlerRejectsBadBodies(t *testing.T) {
+	router := newRemoteWriteRouter(remoteWriteConfig(), storage.NewMetricBuffer(nil, 100, 100, time.Minute))
+
+	tests := []struct {
+		name        string
+		body        []byte
+		contentType string
+		status      int
+	}{
+		{"not snappy", []byte("plain text, not compressed"), "application/x-protobuf", http.StatusBadRequest},
+		{"not a WriteRequest", snappy.Encode(nil, []byte{0x0a, 0xff}), "application/x-protobuf", http.StatusBadRequest},
+		{"protocol 2.0", encodeWriteRequest(), "application/x-protobuf;proto=io.prometheus.write.v2.Request", http.StatusUnsupportedMediaType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(router, http.MethodPost, "/api/v1/prometheus/remote_write", string(tt.body), map[string]string{"Content-Type": tt.contentType})
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.status, w.Body.String())
+			}
+			if apiErr := decodeAPIError(t, w); apiErr.Code != errCodeBadRequest {
+				t.Errorf("code = %s, want %s", apiErr.Code, errCodeBadRequest)
+			}
+		})
+	}
+}
+
+func TestRemoteWriteHandlerBodyLimit(t *testing.T) {
+	config := remoteWriteConfig()
+	buffer := storage.NewMetricBuffer(nil, 100, 10000, time.Minute)
+	router := newRemoteWriteRouter(config, buffer)
+	headers := map[string]string{"Content-Type": "application/x-protobuf"}
+
+	// Random label values barely compress, so the shard runs past the
+	// default body limit
+	at := time.Now().Add(-time.Minute).UnixMilli()
+	series := make([]promSeries, 2000)
+	for i := range series {
+		noise := make([]byte, 768)
+		if _, err := rand.Read(noise); err != nil {
+			t.Fatalf("rand.Read: %v", err)
+		}
+		series[i] = promSeries{
+			labels:  map[string]string{"__name__": "container_cpu_percent", "job": "checkout", "trace": base64.StdEncoding.EncodeToString(noise)},
+			samples: []promSample{{float64(i), at}},
+		}
+	}
+	body := encodeWriteRequest(series...)
+	if int64(len(body)) <= config.HTTP.MaxBodyBytes {
+		t.Fatalf("compressed body of %d bytes fits http.max_body_bytes; the test needs a larger one", len(body))
+	}
+
+	w := serve(router, http.MethodPost, "/api/v1/prometheus/remote_write", string(body), headers)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202: %s", w.Code, w.Body.String())
+	}
+	if pending := buffer.Stats().Pending; pending != len(series) {
+		t.Errorf("pending = %d, want all %d samples queued", pending, len(series))
+	}
+
+	w = serve(router, http.MethodPost, "/api/v1/prometheus/remote_write", strings.Repeat("x", maxRemoteWriteBodyBytes+1), headers)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status = %d, want 413", w.Code)
+	}
+	if apiErr := decodeAPIError(t, w); apiErr.Code != errCodePayloadTooLarge {
+		t.Errorf("code = %s, want %s", apiErr.Code, errCodePayloadTooLarge)
+	}
+}
+
+func TestRemoteWriteHandlerBufferFull(t *testing.T) {
+	router := newRemoteWriteRouter(remoteWriteConfig(), storage.NewMetricBuffer(nil, 100, 1, time.Minute))
+
+	at := time.Now().Add(-time.Minute).UnixMilli()
+	body := encodeWriteRequest(promSeries{
+		labels:  map[string]string{"__name__": "container_cpu_percent", "job": "checkout"},
+		samples: []promSample{{41.5, at}, {43, at + 15000}},
+	})
+
+	w := serve(router, http.MethodPost, "/api/v1/prometheus/remote_write", string(body), nil)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("no Retry-After on a full buffer")
+	}
+}
+
+func TestRemoteWriteSamplesLand(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+
+	buffer := storage.NewMetricBuffer(db, 100, 100, time.Minute)
+	router := newRemoteWriteRouter(remoteWriteConfig(), buffer)
+
+	start := time.Now().Add(-5 * time.Minute).Truncate(time.Second)
+	values := []float64{41.5, 43, 47.25}
+	samples := make([]promSample, len(values))
+	for i, v := range values {
+		samples[i] = promSample{value: v, timestampMs: start.Add(time.Duration(i) * 15 * time.Second).UnixMilli()}
+	}
+	body := encodeWriteRequest(promSeries{
+		labels:  map[string]string{"__name__": "container_cpu_percent", "job": service, "pod": service + "-1"},
+		samples: samples,
+	})
+
+	w := serve(router, http.MethodPost, "/api/v1/prometheus/remote_write", string(body), map[string]string{"Content-Type": "application/x-protobuf"})
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202: %s", w.Code, w.Body.String())
+	}
+	buffer.Flush(ctx)
+
+	stored, err := db.GetRecentMetrics(ctx, service, "cpu_usage", time.Hour)
+	if err != nil {
+		t.Fatalf("GetRecentMetrics: %v", err)
+	}
+	if len(stored) != len(values) {
+		t.Fatalf("stored %d samples, want %d", len(stored), len(values))
+	}
+	for i, m := range stored {
+		if m.MetricValue != values[i] || !m.Timestamp.Equal(time.UnixMilli(samples[i].timestampMs)) {
+			t.Errorf("sample %d = %v at %v, want %v at %v", i, m.MetricValue, m.Timestamp, values[i], time.UnixMilli(samples[i].timestampMs))
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/analyzer"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/report"
+)
+
+// respondRecommendation answers ?format= on a diagnosis endpoint with just
+// the recommendation: the structured report as JSON, or rendered Markdown
+// or HTML
+func respondRecommendation(c *gin.Context, diagnosis *analyzer.UltimateDiagnosis, format string) {
+	rec := diagnosis.Report
+	if rec == nil {
+		respondError(c, http.StatusInternalServerError, errCodeInternal, "diagnosis has no structured recommendation")
+		return
+	}
+
+	if format == report.FormatJSON {
+		c.JSON(http.StatusOK, gin.H{
+			"service":        diagnosis.ServiceName,
+			"prediction_id":  diagnosis.PredictionID,
+			"recommendation": rec,
+			"timestamp":      time.Now().Format(time.RFC3339),
+		})
+		return
+	}
+
+	body, err := report.Render(rec, format)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, errCodeInternal, err.Error())
+		return
+	}
+	contentType := "text/markdown; charset=utf-8"
+	if format == report.FormatHTML {
+		contentType = "text/html; charset=utf-8"
+	}
+	c.Data(http.StatusOK, contentType, []byte(body))
+}
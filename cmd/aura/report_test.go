@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/analyzer"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/report"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+)
+
+func TestRespondRecommendationFormats(t *testing.T) {
+	diagnosis := &analyzer.UltimateDiagnosis{
+		ServiceName:  "checkout",
+		PredictionID: "pred-1",
+		Report: &report.Recommendation{
+			ServiceName: "checkout",
+			RiskLevel:   "HIGH",
+			Problem:     "MEMORY_LEAK",
+			Confidence:  82,
+			Insights:    []string{"heap <growing>"},
+			DiagnosisID: "pred-1",
+			GeneratedAt: time.Now(),
+		},
+	}
+	router := gin.New()
+	router.GET("/recommendation", func(c *gin.Context) {
+		respondRecommendation(c, diagnosis, c.Query("format"))
+	})
+
+	tests := []struct {
+		format      string
+		contentType string
+		contains    string
+	}{
+		{report.FormatMarkdown, "text/markdown", "⚠️ **URGENT ACTION REQUIRED**"},
+		{report.FormatHTML, "text/html", "heap &lt;growing&gt;"},
+		{report.FormatJSON, "application/json", `"problem":"MEMORY_LEAK"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			w := serve(router, http.MethodGet, "/recommendation?format="+tt.format, "", nil)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", w.Code)
+			}
+			if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, tt.contentType) {
+				t.Errorf("Content-Type = %q, want %s", ct, tt.contentType)
+			}
+			if !strings.Contains(w.Body.String(), tt.contains) {
+				t.Errorf("body lacks %q:\n%s", tt.contains, w.Body.String())
+			}
+		})
+	}
+
+	w := serve(router, http.MethodGet, "/recommendation?format=json", "", nil)
+	var body struct {
+		Service        string                `json:"service"`
+		PredictionID   string                `json:"prediction_id"`
+		Recommendation report.Recommendation `json:"recommendation"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("json body: %v", err)
+	}
+	if body.Service != "checkout" || body.PredictionID != "pred-1" || body.Recommendation.Confidence != 82 {
+		t.Errorf("json body = %+v", body)
+	}
+
+	diagnosis.Report = nil
+	if w := serve(router, http.MethodGet, "/recommendation?format=html", "", nil); w.Code != http.StatusInternalServerError {
+		t.Errorf("diagnosis without a report: status = %d, want 500", w.Code)
+	}
+}
+
+func TestUltimateDiagnoseRejectsUnknownFormat(t *testing.T) {
+	router := gin.New()
+	router.GET("/api/v1/ultimate/diagnose/:service", ultimateDiagnoseHandler(nil, nil, nil, nil))
+
+	w := serve(router, http.MethodGet, "/api/v1/ultimate/diagnose/checkout?format=pdf", "", nil)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", w.Code)
+	}
+	if apiErr := decodeAPIError(t, w); apiErr.Code != errCodeBadRequest {
+		t.Errorf("code = %s, want %s", apiErr.Code, errCodeBadRequest)
+	}
+}
+
+func TestWithEvidenceFormat(t *testing.T) {
+	detection := &analyzer.Detection{
+		Type:     analyzer.DetectionResourceExhaustion,
+		Evidence: map[string]interface{}{"cpu_mean": analyzer.Quantity{Value: 91.5, Format: "%.2f%%"}},
+	}
+	router := gin.New()
+	router.GET("/detect", func(c *gin.Context) {
+		c.JSON(http.StatusOK, formatDetection(withEvidenceFormat(c, detection)))
+	})
+
+	tests := []struct {
+		query string
+		want  interface{}
+	}{
+		{"", 91.5},
+		{"?format=legacy", "91.50%"},
+	}
+	for _, tt := range tests {
+		w := serve(router, http.MethodGet, "/detect"+tt.query, "", nil)
+		var body struct {
+			Evidence map[string]interface{} `json:"evidence"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if got := body.Evidence["cpu_mean"]; got != tt.want {
+			t.Errorf("GET /detect%s cpu_mean = %#v, want %#v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestFormatDetectionReportsDampening(t *testing.T) {
+	body := formatDetection(&analyzer.Detection{
+		Confidence:          42,
+		AggregateConfidence: 60,
+		Dampening:           0.7,
+		DampeningReasons:    []string{"weak_signals"},
+	})
+
+	if body["confidence"] != "42.00%" || body["aggregate_confidence"] != "60.00%" {
+		t.Errorf("confidence = %v, aggregate_confidence = %v, want 42.00%% and 60.00%%", body["confidence"], body["aggregate_confidence"])
+	}
+	if body["dampening"] != 0.7 {
+		t.Errorf("dampening = %v, want 0.7", body["dampening"])
+	}
+}
+
+func TestDailyReportRejectsBadDates(t *testing.T) {
+	router := gin.New()
+	router.GET("/api/v1/reports/daily", dailyReportHandler(nil))
+
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1).Format("2006-01-02")
+	for _, date := range []string{"yesterday", "2026-13-01", "10/03/2026", tomorrow} {
+		w := serve(router, http.MethodGet, "/api/v1/reports/daily?date="+date, "", nil)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("date=%s: status = %d, want 400", date, w.Code)
+			continue
+		}
+		if apiErr := decodeAPIError(t, w); apiErr.Code != errCodeBadRequest {
+			t.Errorf("date=%s: code = %s, want %s", date, apiErr.Code, errCodeBadRequest)
+		}
+	}
+}
+
+func TestPostmortemRejectsBadFormat(t *testing.T) {
+	router := gin.New()
+	router.GET("/api/v1/incidents/:id/postmortem", postmortemHandler(nil))
+
+	w := serve(router, http.MethodGet, "/api/v1/incidents/inc-1/postmortem?format=pdf", "", nil)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", w.Code)
+	}
+	if apiErr := decodeAPIError(t, w); apiErr.Code != errCodeBadRequest {
+		t.Errorf("code = %s, want %s", apiErr.Code, errCodeBadRequest)
+	}
+}
+
+func TestPostmortemHandler(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+
+	closedAt := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	inc := &storage.Incident{
+		ID:           service + "-MEMORY_LEAK",
+		ServiceName:  service,
+		ProblemType:  "MEMORY_LEAK",
+		Status:       storage.IncidentClosed,
+		FirstSeen:    closedAt.Add(-time.Hour),
+		LastSeen:     closedAt,
+		ClosedAt:     &closedAt,
+		Occurrences:  3,
+		PeakSeverity: analyzer.SeverityHigh,
+	}
+	if err := db.SaveIncident(context.Background(), inc); err != nil {
+		t.Fatalf("SaveIncident: %v", err)
+	}
+
+	cfg := &core.Config{}
+	cfg.ApplyDefaults()
+	router := gin.New()
+	router.GET("/api/v1/incidents/:id/postmortem", postmortemHandler(analyzer.NewPostmortemBuilder(db, core.NewConfigStore("", cfg))))
+
+	w := serve(router, http.MethodGet, "/api/v1/incidents/"+inc.ID+"/postmortem?format=markdown", "", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/markdown") {
+		t.Errorf("Content-Type = %q, want text/markdown", ct)
+	}
+	for _, want := range []string{"# Postmortem: " + service + " MEMORY_LEAK", "Closed after 1h0m0s, with 0 of 0 proposed actions executed."} {
+		if !strings.Contains(w.Body.String(), want) {
+			t.Errorf("markdown lacks %q:\n%s", want, w.Body.String())
+		}
+	}
+
+	w = serve(router, http.MethodGet, "/api/v1/incidents/"+service+"-missing/postmortem", "", nil)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("unknown incident: status = %d, want 404", w.Code)
+	}
+	if apiErr := decodeAPIError(t, w); apiErr.Code != errCodeNotFound {
+		t.Errorf("unknown incident: code = %s, want %s", apiErr.Code, errCodeNotFound)
+	}
+}
+
+func TestDiagnoseRejectsCompositeWithFormat(t *testing.T) {
+	router := gin.New()
+	router.GET("/api/v1/analyze/:service", ultimateDiagnoseHandler(nil, nil, nil, nil))
+
+	w := serve(router, http.MethodGet, "/api/v1/analyze/checkout?composite=true&format=markdown", "", nil)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", w.Code)
+	}
+	if apiErr := decodeAPIError(t, w); apiErr.Code != errCodeBadRequest {
+		t.Errorf("code = %s, want %s", apiErr.Code, errCodeBadRequest)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/analyzer"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+)
+
+func TestSummarizeSystem(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	services := []string{"api", "cart", "checkout", "search"}
+	severities := map[string]string{
+		"api":      analyzer.SeverityCritical,
+		"cart":     analyzer.SeverityHigh,
+		"checkout": analyzer.SeverityLow,
+		"payments": analyzer.SeverityHigh,
+	}
+	latest := map[string]storage.HealthPoint{
+		"api":      {Timestamp: now, HealthScore: 20},
+		"cart":     {Timestamp: now, HealthScore: 55},
+		"checkout": {Timestamp: now, HealthScore: 90},
+		// payments reports health but dropped out of the service registry
+		"payments": {Timestamp: now, HealthScore: 35},
+	}
+
+	summary := summarizeSystem(services, severities, latest)
+
+	if got := summary["total_services"]; got != 5 {
+		t.Errorf("total_services = %v, want 5", got)
+	}
+	wantSeverity := map[string]int{
+		analyzer.SeverityNone:     1, // search has no transition
+		analyzer.SeverityLow:      1,
+		analyzer.SeverityMedium:   0,
+		analyzer.SeverityHigh:     2,
+		analyzer.SeverityCritical: 1,
+	}
+	bySeverity := summary["by_severity"].(map[string]int)
+	for severity, want := range wantSeverity {
+		if bySeverity[severity] != want {
+			t.Errorf("by_severity[%s] = %d, want %d", severity, bySeverity[severity], want)
+		}
+	}
+
+	worst := summary["worst_service"].(gin.H)
+	if worst["service"] != "api" || worst["health_score"] != 20.0 || worst["severity"] != analyzer.SeverityCritical {
+		t.Errorf("worst_service = %v, want api at 20 (CRITICAL)", worst)
+	}
+
+	health := summary["health"].(gin.H)
+	if health["reporting"] != 4 || health["min"] != 20.0 || health["mean"] != 50.0 {
+		t.Errorf("health = %v, want 4 reporting, min 20, mean 50", health)
+	}
+}
+
+func TestSummarizeSystemWithoutHealth(t *testing.T) {
+	summary := summarizeSystem([]string{"checkout"}, nil, nil)
+
+	if worst := summary["worst_service"].(gin.H); worst != nil {
+		t.Errorf("worst_service = %v, want null without health scores", worst)
+	}
+	health := summary["health"].(gin.H)
+	if _, ok := health["min"]; ok || health["reporting"] != 0 {
+		t.Errorf("health = %v, want only reporting: 0", health)
+	}
+	if got := summary["by_severity"].(map[string]int)[analyzer.SeverityNone]; got != 1 {
+		t.Errorf("by_severity[NONE] = %d, want 1", got)
+	}
+}
+
+func TestSystemStatusRejectsBadWindow(t *testing.T) {
+	router := gin.New()
+	router.GET("/api/v1/status/system", systemStatusHandler(nil))
+
+	for _, window := range []string{"soon", "0s", "-1h", "25h"} {
+		w := serve(router, http.MethodGet, "/api/v1/status/system?window="+window, "", nil)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("window=%s: status = %d, want 400", window, w.Code)
+			continue
+		}
+		if apiErr := decodeAPIError(t, w); apiErr.Code != errCodeBadRequest {
+			t.Errorf("window=%s: code = %s, want %s", window, apiErr.Code, errCodeBadRequest)
+		}
+	}
+}
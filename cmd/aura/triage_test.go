@@ -0,0 +1,28 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/analyzer"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+func TestTriageRejectsBadQuery(t *testing.T) {
+	cfg := &core.Config{}
+	cfg.ApplyDefaults()
+	router := gin.New()
+	router.GET("/api/v1/triage", triageHandler(nil, analyzer.NewUltimateAnalyzer(nil, core.NewConfigStore("", cfg))))
+
+	for _, query := range []string{"min_severity=severe", "min_severity=none", "limit=0", "limit=501", "limit=all"} {
+		w := serve(router, http.MethodGet, "/api/v1/triage?"+query, "", nil)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", query, w.Code)
+			continue
+		}
+		if apiErr := decodeAPIError(t, w); apiErr.Code != errCodeBadRequest {
+			t.Errorf("%s: code = %s, want %s", query, apiErr.Code, errCodeBadRequest)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"os/signal"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -24,10 +25,11 @@ var (
 	scenarioMutex     sync.RWMutex
 	currentScenario   = "normal"
 	scenarioStartTime time.Time
-	memoryLeakRate    = 0.0
-	accumulatedMemory = 50.0
 )
 
+// pendingErrors counts errors since the last simulation tick
+var pendingErrors atomic.Int64
+
 var (
 	requestCounter = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -64,6 +66,22 @@ var (
 			Help: "Total number of application errors",
 		},
 	)
+
+	// Gauges in the units AURA's detectors reason in, so scenarios don't
+	// depend on rate() or histogram_quantile() being applied when scraping
+	errorRatePerMin = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "error_rate_per_min",
+			Help: "Application errors per minute over the last minute",
+		},
+	)
+
+	responseTimeP95 = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "response_time_p95_ms",
+			Help: "Simulated 95th percentile response time in milliseconds",
+		},
+	)
 )
 
 func init() {
@@ -72,10 +90,12 @@ func init() {
 	prometheus.MustRegister(cpuUsage)
 	prometheus.MustRegister(memoryUsage)
 	prometheus.MustRegister(errorRate)
+	prometheus.MustRegister(errorRatePerMin)
+	prometheus.MustRegister(responseTimeP95)
 }
 
 func main() {
-	go simulateMetrics()
+	go simulateMetrics(newSimulationRand())
 
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
@@ -180,7 +200,7 @@ func handleReady(c *gin.Context) {
 }
 
 func handleError(c *gin.Context) {
-	errorRate.Inc()
+	recordErrors(1)
 	c.JSON(http.StatusInternalServerError, gin.H{
 		"error":     "Simulated error",
 		"timestamp": time.Now().Format(time.RFC3339),
@@ -216,18 +236,10 @@ func setScenario(c *gin.Context) {
 		return
 	}
 
+	// Scenario curves (e.g. the memory-leak growth) are measured from here
 	currentScenario = scenarioName
 	scenarioStartTime = time.Now()
 
-	// Reset accumulated memory for new scenario
-	if scenarioName == "memory-leak" {
-		accumulatedMemory = 60.0 // Start at 60%
-		memoryLeakRate = 0.021   // 0.021% per 5-sec tick = ~0.25%/min
-	} else {
-		accumulatedMemory = 50.0
-		memoryLeakRate = 0.0
-	}
-
 	log.Printf("✅ Scenario activated: %s (started at %s)", scenarioName, scenarioStartTime.Format(time.RFC3339))
 
 	c.JSON(http.StatusOK, gin.H{
@@ -259,142 +271,216 @@ func getCurrentScenario() string {
 	return currentScenario
 }
 
-func simulateMetrics() {
+// simulateMetrics drives the gauges for the active scenario every 5s. All
+// randomness comes from rng, so a fixed APP_RAND_SEED replays the same series.
+func simulateMetrics(rng *rand.Rand) {
 	ticker := time.NewTicker(5 * time.Second)
 	defer ticker.Stop()
 
+	sim := &simulation{rng: rng}
 	for range ticker.C {
 		scenarioMutex.RLock()
 		scenario := currentScenario
+		startedAt := scenarioStartTime
 		scenarioMutex.RUnlock()
 
-		var cpu, mem float64
-
-		switch scenario {
-		case "normal":
-			// ✅ FALSE: No detection should trigger
-			// Memory stable, CPU normal, minimal errors
-			cpu = 35.0 + rand.Float64()*15.0 // 35-50%
-			mem = 45.0 + rand.Float64()*15.0 // 45-60%
-			// Very rare errors (< 1/min)
-			if rand.Float64() < 0.02 {
-				errorRate.Inc()
-			}
-
-		case "memory-leak":
-			// ✅ DETECT: Sustained memory growth >0.15%/min, low volatility, no CPU correlation
-			// Detector needs: trend >0.15%/min, volatility <0.15, autocorr >0.8, 2+ quality signals
-			elapsedTicks := time.Since(scenarioStartTime).Seconds() / 5.0
-			// Leak rate: 0.25%/min = 0.02% per 5-sec tick (above 0.15%/min threshold)
-			accumulatedMemory = 60.0 + (elapsedTicks * 0.021) + rand.Float64()*0.5 // Consistent growth
-			if accumulatedMemory > 95.0 {
-				accumulatedMemory = 95.0
-			}
-			cpu = 40.0 + rand.Float64()*10.0 // 40-50% - NO correlation with memory
-			mem = accumulatedMemory
-			// Minimal errors
-			if rand.Float64() < 0.03 {
-				errorRate.Inc()
-			}
-
-		case "cpu-spike":
-			// ✅ FALSE: Only CPU high, but memory normal
-			// Detector needs BOTH CPU >80% AND Memory >85% for resource exhaustion
-			cpu = 88.0 + rand.Float64()*8.0  // 88-96% (high)
-			mem = 55.0 + rand.Float64()*15.0 // 55-70% (normal) - NOT BOTH HIGH
-			// Some stress errors but not enough for other detections
-			if rand.Float64() < 0.25 {
-				for i := 0; i < rand.Intn(3)+1; i++ {
-					errorRate.Inc()
-				}
-			}
-
-		case "error-storm":
-			// ✅ FALSE: Just errors, but not enough signals for deployment bug
-			// Deployment bug needs: spikiness >2.0 AND error rate >5/min AND 2+ quality signals
-			// This creates errors but resources are high (fails cross-validation)
-			cpu = 75.0 + rand.Float64()*15.0 // 75-90% - HIGH (fails normal resources check)
-			mem = 70.0 + rand.Float64()*15.0 // 70-85% - HIGH
-			// Generate errors but not extreme spikiness
-			errorCount := rand.Intn(8) + 4 // 4-12 errors per 5sec = ~50-144/min
-			for i := 0; i < errorCount; i++ {
-				errorRate.Inc()
-			}
-
-		case "resource-exhaustion":
-			// ✅ DETECT: BOTH CPU >80% AND Memory >85%
-			// Detector needs: CPU >80%, Memory >85%, both high bonus, 2+ quality signals
-			cpu = 87.0 + rand.Float64()*10.0 // 87-97% (above 80%)
-			mem = 89.0 + rand.Float64()*8.0  // 89-97% (above 85%)
-			// Resource starvation errors
-			if rand.Float64() < 0.5 {
-				for i := 0; i < rand.Intn(6)+3; i++ {
-					errorRate.Inc()
-				}
-			}
-
-		case "deployment-bug":
-			// ✅ DETECT: High errors + spikiness, normal resources, independent of CPU
-			// Detector needs: spikiness >2.0, error rate >15/min, CPU <70%, Memory <70%, 2+ quality signals
-			cpu = 45.0 + rand.Float64()*15.0 // 45-60% (below 70% - normal resources)
-			mem = 50.0 + rand.Float64()*15.0 // 50-65% (below 70% - normal resources)
-			// MASSIVE error bursts for high spikiness
-			// Generate 20-40 errors per 5sec = 240-480 errors/min (very high rate)
-			if rand.Float64() < 0.9 { // 90% of ticks have bursts
-				errorCount := rand.Intn(21) + 20 // 20-40 errors
-				for i := 0; i < errorCount; i++ {
-					errorRate.Inc()
-				}
-			}
-
-		case "external-failure":
-			// ✅ DETECT: High errors + LOW CPU/Memory (external dependency issue)
-			// Detector needs: errors >10/min, CPU <65%, latency-error corr >0.6, 3+ quality signals
-			cpu = 35.0 + rand.Float64()*20.0 // 35-55% (well below 65%)
-			mem = 45.0 + rand.Float64()*20.0 // 45-65% (normal)
-			// Consistent moderate-high errors simulating external timeout/failures
-			// 12-20 errors per 5sec = 144-240/min
-			if rand.Float64() < 0.8 {
-				errorCount := rand.Intn(9) + 12 // 12-20 errors
-				for i := 0; i < errorCount; i++ {
-					errorRate.Inc()
-				}
-			}
-
-		case "cascade":
-			// ✅ DETECT: 3+ degraded metrics (CPU, Memory, Errors, Latency)
-			// Detector needs: 3+ degraded (CPU >85%, Memory >88%, Errors >15/min, Latency >2s), 2+ quality signals
-			elapsedMinutes := time.Since(scenarioStartTime).Minutes()
-			// Gradual degradation reaching critical levels
-			degradationFactor := math.Min(elapsedMinutes*15, 50.0)
-
-			cpu = 60.0 + degradationFactor + rand.Float64()*10.0 // Grows to 90-110%
-			mem = 60.0 + degradationFactor + rand.Float64()*10.0 // Grows to 90-110%
-
-			if cpu > 98.0 {
-				cpu = 98.0
-			}
-			if mem > 98.0 {
-				mem = 98.0
-			}
-
-			// Increasing errors as system degrades
-			errorProbability := math.Min(0.6+elapsedMinutes*0.2, 0.95)
-			if rand.Float64() < errorProbability {
-				errorCount := rand.Intn(12) + int(elapsedMinutes*3) + 8 // Grows to 20-30 errors/5sec
-				for i := 0; i < errorCount; i++ {
-					errorRate.Inc()
-				}
-			}
-
-		default:
-			cpu = 30.0 + rand.Float64()*20.0
-			mem = 40.0 + rand.Float64()*20.0
+		sim.tick(scenario, startedAt)
+	}
+}
+
+// simulation is the state simulateMetrics carries between ticks
+type simulation struct {
+	rng    *rand.Rand
+	window errorWindow
+	tickOf time.Time // start time of the scenario ticks counts for
+	ticks  int
+}
+
+// tick advances scenario, started at startedAt, by one 5s step and sets the
+// gauges
+func (s *simulation) tick(scenario string, startedAt time.Time) {
+	rng := s.rng
+
+	// Curves advance by whole ticks rather than wall time, so a seeded
+	// run is reproducible sample for sample
+	if !startedAt.Equal(s.tickOf) {
+		s.tickOf, s.ticks = startedAt, 0
+	}
+	elapsed := time.Duration(s.ticks) * 5 * time.Second
+	s.ticks++
+
+	var cpu, mem, latency float64
+
+	switch scenario {
+	case "normal":
+		// ✅ FALSE: No detection should trigger
+		// Memory stable, CPU normal, minimal errors
+		cpu = 35.0 + rng.Float64()*15.0     // 35-50%
+		mem = 45.0 + rng.Float64()*15.0     // 45-60%
+		latency = 80.0 + rng.Float64()*70.0 // 80-150ms
+		// Very rare errors (< 1/min)
+		if rng.Float64() < 0.02 {
+			recordErrors(1)
+		}
+
+	case "memory-leak":
+		// ✅ DETECT: Sustained memory growth >0.15%/min, low volatility, no CPU correlation
+		// Detector needs: trend >0.15%/min, volatility <0.15, autocorr >0.8, 2+ quality signals
+		elapsedTicks := elapsed.Seconds() / 5.0
+		// Leak rate: 0.25%/min = 0.02% per 5-sec tick (above 0.15%/min threshold)
+		mem = 60.0 + (elapsedTicks * 0.021) + rng.Float64()*0.5 // Consistent growth
+		if mem > 95.0 {
+			mem = 95.0
+		}
+		cpu = 40.0 + rng.Float64()*10.0      // 40-50% - NO correlation with memory
+		latency = 100.0 + rng.Float64()*80.0 // 100-180ms
+		// Minimal errors
+		if rng.Float64() < 0.03 {
+			recordErrors(1)
+		}
+
+	case "cpu-spike":
+		// ✅ FALSE: Only CPU high, but memory normal
+		// Detector needs BOTH CPU >80% AND Memory >85% for resource exhaustion
+		cpu = 88.0 + rng.Float64()*8.0        // 88-96% (high)
+		mem = 55.0 + rng.Float64()*15.0       // 55-70% (normal) - NOT BOTH HIGH
+		latency = 200.0 + rng.Float64()*200.0 // 200-400ms, slowed by CPU contention
+		// Some stress errors but not enough for other detections
+		if rng.Float64() < 0.25 {
+			recordErrors(rng.Intn(3) + 1)
+		}
+
+	case "error-storm":
+		// ✅ FALSE: Just errors, but not enough signals for deployment bug
+		// Deployment bug needs: spikiness >2.0 AND error rate >5/min AND 2+ quality signals
+		// This creates errors but resources are high (fails cross-validation)
+		cpu = 75.0 + rng.Float64()*15.0       // 75-90% - HIGH (fails normal resources check)
+		mem = 70.0 + rng.Float64()*15.0       // 70-85% - HIGH
+		latency = 300.0 + rng.Float64()*300.0 // 300-600ms
+		// Generate errors but not extreme spikiness
+		recordErrors(rng.Intn(8) + 4) // 4-12 errors per 5sec = ~50-144/min
+
+	case "resource-exhaustion":
+		// ✅ DETECT: BOTH CPU >80% AND Memory >85%
+		// Detector needs: CPU >80%, Memory >85%, both high bonus, 2+ quality signals
+		cpu = 87.0 + rng.Float64()*10.0       // 87-97% (above 80%)
+		mem = 89.0 + rng.Float64()*8.0        // 89-97% (above 85%)
+		latency = 800.0 + rng.Float64()*700.0 // 800-1500ms
+		// Resource starvation errors
+		if rng.Float64() < 0.5 {
+			recordErrors(rng.Intn(6) + 3)
+		}
+
+	case "deployment-bug":
+		// ✅ DETECT: High errors + spikiness, normal resources, independent of CPU
+		// Detector needs: spikiness >2.0, error rate >15/min, CPU <70%, Memory <70%, 2+ quality signals
+		cpu = 45.0 + rng.Float64()*15.0       // 45-60% (below 70% - normal resources)
+		mem = 50.0 + rng.Float64()*15.0       // 50-65% (below 70% - normal resources)
+		latency = 150.0 + rng.Float64()*150.0 // 150-300ms - failing fast, not slow
+		// MASSIVE error bursts for high spikiness
+		// Generate 20-40 errors per 5sec = 240-480 errors/min (very high rate)
+		if rng.Float64() < 0.9 { // 90% of ticks have bursts
+			recordErrors(rng.Intn(21) + 20) // 20-40 errors
+		}
+
+	case "external-failure":
+		// ✅ DETECT: High errors + LOW CPU/Memory (external dependency issue)
+		// Detector needs: errors >10/min, CPU <65%, latency-error corr >0.6, 3+ quality signals
+		cpu = 35.0 + rng.Float64()*20.0 // 35-55% (well below 65%)
+		mem = 45.0 + rng.Float64()*20.0 // 45-65% (normal)
+		// Consistent moderate-high errors simulating external timeout/failures
+		// 12-20 errors per 5sec = 144-240/min
+		errorCount := 0
+		if rng.Float64() < 0.8 {
+			errorCount = rng.Intn(9) + 12 // 12-20 errors
+			recordErrors(errorCount)
+		}
+		// Requests wait on the failing dependency, so latency tracks the
+		// errors: ~1.5s baseline, 2.7-3.5s on failing ticks
+		latency = 1500.0 + float64(errorCount)*100.0 + rng.Float64()*300.0
+
+	case "cascade":
+		// ✅ DETECT: 3+ degraded metrics (CPU, Memory, Errors, Latency)
+		// Detector needs: 3+ degraded (CPU >85%, Memory >88%, Errors >15/min, Latency >2s), 2+ quality signals
+		elapsedMinutes := elapsed.Minutes()
+		// Gradual degradation reaching critical levels
+		degradationFactor := math.Min(elapsedMinutes*15, 50.0)
+
+		cpu = 60.0 + degradationFactor + rng.Float64()*10.0 // Grows to 90-110%
+		mem = 60.0 + degradationFactor + rng.Float64()*10.0 // Grows to 90-110%
+
+		if cpu > 98.0 {
+			cpu = 98.0
+		}
+		if mem > 98.0 {
+			mem = 98.0
+		}
+
+		latency = 300.0 + degradationFactor*50.0 + rng.Float64()*200.0 // Grows to 2.8-3s
+
+		// Increasing errors as system degrades
+		errorProbability := math.Min(0.6+elapsedMinutes*0.2, 0.95)
+		if rng.Float64() < errorProbability {
+			recordErrors(rng.Intn(12) + int(elapsedMinutes*3) + 8) // Grows to 20-30 errors/5sec
 		}
 
-		cpuUsage.Set(cpu)
-		memoryUsage.Set(mem)
+	default:
+		cpu = 30.0 + rng.Float64()*20.0
+		mem = 40.0 + rng.Float64()*20.0
+		latency = 80.0 + rng.Float64()*70.0
+	}
+
+	cpuUsage.Set(cpu)
+	memoryUsage.Set(mem)
+	responseTimeP95.Set(latency)
+	errorRatePerMin.Set(s.window.observe(pendingErrors.Swap(0)))
+}
+
+// recordErrors counts n application errors. Scenario ticks and /error
+// requests call it concurrently; both feed app_errors_total and the
+// error_rate_per_min window.
+func recordErrors(n int) {
+	errorRate.Add(float64(n))
+	pendingErrors.Add(int64(n))
+}
+
+// errorWindow keeps the error counts of the last minute of 5s ticks
+type errorWindow struct {
+	counts [12]int64
+	next   int
+	filled int
+}
+
+// observe records one tick's errors and returns the errors per minute over
+// the ticks seen so far, at most the last minute
+func (w *errorWindow) observe(errors int64) float64 {
+	w.counts[w.next] = errors
+	w.next = (w.next + 1) % len(w.counts)
+	if w.filled < len(w.counts) {
+		w.filled++
+	}
+
+	var total int64
+	for _, c := range w.counts {
+		total += c
+	}
+	minutes := float64(w.filled) * 5.0 / 60.0
+	return float64(total) / minutes
+}
+
+// newSimulationRand seeds the simulation from APP_RAND_SEED, or from the
+// clock when it is unset. The seed is logged so any run can be replayed.
+func newSimulationRand() *rand.Rand {
+	seed := time.Now().UnixNano()
+	if value := os.Getenv("APP_RAND_SEED"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			log.Fatalf("Invalid APP_RAND_SEED %q: %v", value, err)
+		}
+		seed = parsed
 	}
+	log.Printf("Simulation seed: %d (set APP_RAND_SEED to replay)", seed)
+	return rand.New(rand.NewSource(seed))
 }
 
 func getEnv(key, fallback string) string {
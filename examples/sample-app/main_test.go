@@ -0,0 +1,143 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestErrorWindow(t *testing.T) {
+	var w errorWindow
+
+	// One 5s tick of 3 errors extrapolates to 36/min
+	if got := w.observe(3); got != 36 {
+		t.Errorf("first tick = %.1f/min, want 36", got)
+	}
+
+	// A full minute of ticks averages over exactly that minute
+	for i := 0; i < 11; i++ {
+		w.observe(3)
+	}
+	if got := w.observe(0); got != 33 {
+		t.Errorf("after a quiet tick = %.1f/min, want 33", got)
+	}
+	for i := 0; i < 12; i++ {
+		w.observe(0)
+	}
+	if got := w.observe(0); got != 0 {
+		t.Errorf("after a quiet minute = %.1f/min, want 0", got)
+	}
+}
+
+func TestSimulationGaugesPerScenario(t *testing.T) {
+	tests := []struct {
+		scenario                   string
+		minLatency, maxLatency     float64
+		minErrorRate, maxErrorRate float64
+	}{
+		{"normal", 80, 150, 0, 12},
+		{"memory-leak", 100, 180, 0, 12},
+		{"error-storm", 300, 600, 48, 144},
+		{"resource-exhaustion", 800, 1500, 0, 96},
+		{"deployment-bug", 150, 300, 100, 480},
+		{"external-failure", 1500, 3800, 50, 240},
+	}
+	startedAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			pendingErrors.Store(0)
+			sim := &simulation{rng: rand.New(rand.NewSource(1))}
+
+			// A minute of ticks fills the error window
+			for i := 0; i < 12; i++ {
+				sim.tick(tt.scenario, startedAt)
+
+				if latency := testutil.ToFloat64(responseTimeP95); latency < tt.minLatency || latency > tt.maxLatency {
+					t.Fatalf("tick %d: response_time_p95_ms = %.1f, want %.0f-%.0f", i, latency, tt.minLatency, tt.maxLatency)
+				}
+			}
+			if rate := testutil.ToFloat64(errorRatePerMin); rate < tt.minErrorRate || rate > tt.maxErrorRate {
+				t.Errorf("error_rate_per_min = %.1f, want %.0f-%.0f", rate, tt.minErrorRate, tt.maxErrorRate)
+			}
+		})
+	}
+}
+
+func TestSimulationCascadeDegrades(t *testing.T) {
+	pendingErrors.Store(0)
+	sim := &simulation{rng: rand.New(rand.NewSource(1))}
+	startedAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	sim.tick("cascade", startedAt)
+	first := testutil.ToFloat64(responseTimeP95)
+
+	// After four minutes the degradation has saturated
+	for i := 0; i < 48; i++ {
+		sim.tick("cascade", startedAt)
+	}
+	if last := testutil.ToFloat64(responseTimeP95); last < 2500 || last <= first {
+		t.Errorf("cascade latency went %.0f -> %.0fms, want growth past 2.5s", first, last)
+	}
+	if rate := testutil.ToFloat64(errorRatePerMin); rate < 100 {
+		t.Errorf("cascade error_rate_per_min = %.1f, want above 100", rate)
+	}
+
+	// A new scenario start resets the curve
+	sim.tick("cascade", startedAt.Add(time.Hour))
+	if latency := testutil.ToFloat64(responseTimeP95); latency > 500 {
+		t.Errorf("restarted cascade latency = %.0fms, want the initial ~300-500ms", latency)
+	}
+}
+
+// simulatedSeries runs ticks of scenario from a fresh simulation seeded with
+// seed and returns the gauge values of every tick
+func simulatedSeries(seed int64, scenario string, ticks int) [][4]float64 {
+	pendingErrors.Store(0)
+	sim := &simulation{rng: rand.New(rand.NewSource(seed))}
+	startedAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	series := make([][4]float64, ticks)
+	for i := range series {
+		sim.tick(scenario, startedAt)
+		series[i] = [4]float64{
+			testutil.ToFloat64(cpuUsage),
+			testutil.ToFloat64(memoryUsage),
+			testutil.ToFloat64(responseTimeP95),
+			testutil.ToFloat64(errorRatePerMin),
+		}
+	}
+	return series
+}
+
+func TestSameSeedReplaysSeries(t *testing.T) {
+	for _, scenario := range []string{"memory-leak", "cascade", "external-failure"} {
+		t.Run(scenario, func(t *testing.T) {
+			first := simulatedSeries(42, scenario, 120)
+			second := simulatedSeries(42, scenario, 120)
+			for i := range first {
+				if first[i] != second[i] {
+					t.Fatalf("tick %d: %v then %v, want identical runs", i, first[i], second[i])
+				}
+			}
+
+			other := simulatedSeries(43, scenario, 120)
+			same := true
+			for i := range first {
+				same = same && first[i] == other[i]
+			}
+			if same {
+				t.Error("seeds 42 and 43 produced identical series")
+			}
+		})
+	}
+}
+
+func TestNewSimulationRandUsesSeed(t *testing.T) {
+	t.Setenv("APP_RAND_SEED", "42")
+	if got, want := newSimulationRand().Int63(), rand.New(rand.NewSource(42)).Int63(); got != want {
+		t.Errorf("APP_RAND_SEED=42 drew %d, want %d", got, want)
+	}
+}
@@ -1 +1,131 @@
 package actuator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/analyzer"
+	"go.uber.org/zap"
+)
+
+// Action types the executor can carry out against the cluster
+const (
+	ActionScaleUp = "SCALE_UP"
+	ActionRestart = "RESTART"
+)
+
+// DeploymentClient is the subset of the Kubernetes watcher the executor needs
+type DeploymentClient interface {
+	DeploymentReplicas(ctx context.Context, name string) (int32, error)
+	ScaleDeployment(ctx context.Context, name string, replicas int32) error
+	RestartDeployment(ctx context.Context, name string) error
+}
+
+// ExecutionResult describes what the executor did (or would have done in
+// dry-run), or why the action was only recorded
+type ExecutionResult struct {
+	Deployment    string    `json:"deployment"`
+	ActionType    string    `json:"action_type"`
+	DryRun        bool      `json:"dry_run"`
+	Executed      bool      `json:"executed"`
+	Blocked       bool      `json:"blocked,omitempty"`        // refused by safe mode
+	RecommendOnly bool      `json:"recommend_only,omitempty"` // service not in actuator.auto_remediate
+	FromReplica   int32     `json:"from_replicas,omitempty"`
+	ToReplica     int32     `json:"to_replicas,omitempty"`
+	Message       string    `json:"message"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// Executor maps analyzer actuator actions onto real Kubernetes operations
+type Executor struct {
+	client      DeploymentClient
+	maxReplicas int32
+	dryRun      bool
+	logger      *zap.Logger
+}
+
+func NewExecutor(client DeploymentClient, maxReplicas int32, dryRun bool, logger *zap.Logger) *Executor {
+	if maxReplicas <= 0 {
+		maxReplicas = defaultMaxReplicas
+	}
+	return &Executor{
+		client:      client,
+		maxReplicas: maxReplicas,
+		dryRun:      dryRun,
+		logger:      logger,
+	}
+}
+
+// Supports reports whether the executor has a backend for the action type
+func Supports(actionType string) bool {
+	return actionType == ActionScaleUp || actionType == ActionRestart
+}
+
+// Execute carries out a SCALE_UP or RESTART action on the deployment
+func (e *Executor) Execute(ctx context.Context, deployment string, action *analyzer.ActuatorAction) (*ExecutionResult, error) {
+	if e.client == nil {
+		return nil, fmt.Errorf("kubernetes is not available")
+	}
+
+	result := &ExecutionResult{
+		Deployment: deployment,
+		ActionType: action.ActionType,
+		DryRun:     e.dryRun,
+		Timestamp:  time.Now(),
+	}
+
+	switch action.ActionType {
+	case ActionScaleUp:
+		return result, e.scaleUp(ctx, deployment, action, result)
+	case ActionRestart:
+		return result, e.restart(ctx, deployment, result)
+	default:
+		return nil, fmt.Errorf("action type %s is not supported by the executor", action.ActionType)
+	}
+}
+
+func (e *Executor) scaleUp(ctx context.Context, deployment string, action *analyzer.ActuatorAction, result *ExecutionResult) error {
+	current, err := e.client.DeploymentReplicas(ctx, deployment)
+	if err != nil {
+		return err
+	}
+
+	target, err := scaleTarget(current, action.TargetValue, e.maxReplicas)
+	if err != nil {
+		return err
+	}
+
+	result.FromReplica = current
+	result.ToReplica = target
+
+	if e.dryRun {
+		result.Message = fmt.Sprintf("dry-run: would scale %s from %d to %d replicas", deployment, current, target)
+		e.logger.Info("Dry-run scale", zap.String("deployment", deployment), zap.Int32("from", current), zap.Int32("to", target))
+		return nil
+	}
+
+	if err := e.client.ScaleDeployment(ctx, deployment, target); err != nil {
+		return err
+	}
+
+	result.Executed = true
+	result.Message = fmt.Sprintf("scaled %s from %d to %d replicas", deployment, current, target)
+	return nil
+}
+
+func (e *Executor) restart(ctx context.Context, deployment string, result *ExecutionResult) error {
+	if e.dryRun {
+		result.Message = fmt.Sprintf("dry-run: would restart %s", deployment)
+		e.logger.Info("Dry-run restart", zap.String("deployment", deployment))
+		return nil
+	}
+
+	if err := e.client.RestartDeployment(ctx, deployment); err != nil {
+		return err
+	}
+
+	result.Executed = true
+	result.Message = fmt.Sprintf("triggered rolling restart of %s", deployment)
+	return nil
+}
@@ -0,0 +1,125 @@
+package actuator
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/analyzer"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+// defaultSafeModeWindow is used when actuator.window is unset or invalid
+const defaultSafeModeWindow = 10 * time.Minute
+
+// SafeMode decides whether an action may execute, from the actuator config
+// in effect. A blocked action is reported instead of carried out.
+type SafeMode struct {
+	config *core.ConfigStore
+
+	mu       sync.Mutex
+	executed []time.Time // executions recorded within the window, oldest first
+	pending  int         // admitted actions not yet recorded or released
+}
+
+func NewSafeMode(config *core.ConfigStore) *SafeMode {
+	return &SafeMode{config: config}
+}
+
+// Admit returns why an action for the detection must not execute, or ""
+// when it may. An admitted action holds a slot of actuator.max_actions until
+// Record or Release settles it, so concurrent actions can't overrun the
+// limit between admission and execution.
+func (s *SafeMode) Admit(detection *analyzer.Detection, now time.Time) string {
+	cfg := s.config.Get()
+	if cfg == nil {
+		return ""
+	}
+	guard := cfg.Actuator
+
+	if detection == nil {
+		return "no detection backs the action"
+	}
+	if guard.MinConfidence >= 0 && detection.Confidence < guard.MinConfidence {
+		return fmt.Sprintf("detection confidence %.1f is below actuator.min_confidence %.1f", detection.Confidence, guard.MinConfidence)
+	}
+	if !guard.AllowPartialData && detection.DataSufficiency == analyzer.DataSufficiencyPartial {
+		return "detection was made on partial data"
+	}
+
+	window := defaultSafeModeWindow
+	if d, err := time.ParseDuration(guard.Window); err == nil && d > 0 {
+		window = d
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.executed[:0]
+	for _, t := range s.executed {
+		if now.Sub(t) < window {
+			kept = append(kept, t)
+		}
+	}
+	s.executed = kept
+
+	if guard.MaxActions >= 0 && len(s.executed)+s.pending >= guard.MaxActions {
+		return fmt.Sprintf("%d actions already executed or in progress within %s (actuator.max_actions)", len(s.executed)+s.pending, window)
+	}
+	s.pending++
+	return ""
+}
+
+// Record counts an admitted action that executed towards
+// actuator.max_actions, in place of the slot Admit reserved for it
+func (s *SafeMode) Record(at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settle()
+	s.executed = append(s.executed, at)
+}
+
+// Release frees the slot Admit reserved for an action that didn't execute.
+// Dry runs and failed executions are released, so they don't use up the
+// budget.
+func (s *SafeMode) Release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settle()
+}
+
+// settle drops one reservation; s.mu must be held
+func (s *SafeMode) settle() {
+	if s.pending > 0 {
+		s.pending--
+	}
+}
+
+// AutoRemediates reports whether actions for the service may execute at
+// all. Actions for other services are only recorded as recommendations.
+func (s *SafeMode) AutoRemediates(serviceName string) bool {
+	return s.config.Get().AutoRemediates(serviceName)
+}
+
+// Recommended is the result reported for an action on a service that is not
+// in actuator.auto_remediate
+func Recommended(deployment, actionType string) *ExecutionResult {
+	return &ExecutionResult{
+		Deployment:    deployment,
+		ActionType:    actionType,
+		RecommendOnly: true,
+		Message:       "recommendation only: service is not in actuator.auto_remediate",
+		Timestamp:     time.Now(),
+	}
+}
+
+// Blocked is the result reported for an action safe mode refused
+func Blocked(deployment, actionType, reason string) *ExecutionResult {
+	return &ExecutionResult{
+		Deployment: deployment,
+		ActionType: actionType,
+		Blocked:    true,
+		Message:    "blocked: " + reason,
+		Timestamp:  time.Now(),
+	}
+}
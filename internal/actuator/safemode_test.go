@@ -0,0 +1,165 @@
+package actuator
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/analyzer"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+var safeModeEpoch = time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+// newSafeMode is a safe mode over the default actuator config, adjusted by
+// configure
+func newSafeMode(configure func(cfg *core.Config)) *SafeMode {
+	cfg := &core.Config{}
+	cfg.ApplyDefaults()
+	if configure != nil {
+		configure(cfg)
+	}
+	return NewSafeMode(core.NewConfigStore("", cfg))
+}
+
+func confidentDetection() *analyzer.Detection {
+	return &analyzer.Detection{Confidence: 90, DataSufficiency: analyzer.DataSufficiencyFull}
+}
+
+func TestSafeModeConfidenceGuard(t *testing.T) {
+	tests := []struct {
+		name          string
+		minConfidence float64
+		confidence    float64
+		blocked       bool
+	}{
+		{"below", 70, 69.9, true},
+		{"at the minimum", 70, 70, false},
+		{"above", 70, 90, false},
+		{"disabled", -1, 5, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			safeMode := newSafeMode(func(cfg *core.Config) { cfg.Actuator.MinConfidence = tt.minConfidence })
+			detection := confidentDetection()
+			detection.Confidence = tt.confidence
+
+			reason := safeMode.Admit(detection, safeModeEpoch)
+			if blocked := reason != ""; blocked != tt.blocked {
+				t.Fatalf("blocked = %v (%q), want %v", blocked, reason, tt.blocked)
+			}
+			if tt.blocked && !strings.Contains(reason, "actuator.min_confidence") {
+				t.Errorf("reason = %q, want it to name actuator.min_confidence", reason)
+			}
+		})
+	}
+}
+
+func TestSafeModePartialDataGuard(t *testing.T) {
+	detection := confidentDetection()
+	detection.DataSufficiency = analyzer.DataSufficiencyPartial
+
+	if reason := newSafeMode(nil).Admit(detection, safeModeEpoch); !strings.Contains(reason, "partial data") {
+		t.Errorf("reason = %q, want partial data blocked", reason)
+	}
+	allowed := newSafeMode(func(cfg *core.Config) { cfg.Actuator.AllowPartialData = true })
+	if reason := allowed.Admit(detection, safeModeEpoch); reason != "" {
+		t.Errorf("with allow_partial_data: blocked with %q", reason)
+	}
+}
+
+func TestSafeModeRequiresDetection(t *testing.T) {
+	if reason := newSafeMode(nil).Admit(nil, safeModeEpoch); reason == "" {
+		t.Error("an action without a detection was admitted")
+	}
+}
+
+func TestSafeModeMaxActionsGuard(t *testing.T) {
+	safeMode := newSafeMode(func(cfg *core.Config) {
+		cfg.Actuator.MaxActions = 2
+		cfg.Actuator.Window = "10m"
+	})
+	detection := confidentDetection()
+
+	// Each admitted action holds a slot until it settles
+	for i := 0; i < 2; i++ {
+		if reason := safeMode.Admit(detection, safeModeEpoch); reason != "" {
+			t.Fatalf("admit %d of 2: blocked with %q", i, reason)
+		}
+	}
+	if reason := safeMode.Admit(detection, safeModeEpoch); !strings.Contains(reason, "actuator.max_actions") {
+		t.Fatalf("with 2 of 2 in progress: reason = %q, want actuator.max_actions", reason)
+	}
+
+	// One didn't execute and gives its slot back; the other executed
+	safeMode.Release()
+	safeMode.Record(safeModeEpoch)
+	if reason := safeMode.Admit(detection, safeModeEpoch.Add(time.Minute)); reason != "" {
+		t.Fatalf("after 1 of 2 executions: blocked with %q", reason)
+	}
+	safeMode.Record(safeModeEpoch.Add(time.Minute))
+
+	reason := safeMode.Admit(detection, safeModeEpoch.Add(2*time.Minute))
+	if !strings.Contains(reason, "actuator.max_actions") {
+		t.Fatalf("after 2 of 2 executions: reason = %q, want actuator.max_actions", reason)
+	}
+
+	// The first execution leaves the window, freeing one slot
+	if reason := safeMode.Admit(detection, safeModeEpoch.Add(10*time.Minute)); reason != "" {
+		t.Errorf("once the first execution left the window: blocked with %q", reason)
+	}
+}
+
+func TestSafeModeMaxActionsConcurrent(t *testing.T) {
+	safeMode := newSafeMode(func(cfg *core.Config) {
+		cfg.Actuator.MaxActions = 3
+		cfg.Actuator.Window = "10m"
+	})
+	detection := confidentDetection()
+
+	// Every action is admitted before any of them executes
+	var admitted atomic.Int32
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			if safeMode.Admit(detection, safeModeEpoch) == "" {
+				admitted.Add(1)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if got := admitted.Load(); got != 3 {
+		t.Fatalf("admitted %d concurrent actions, want actuator.max_actions = 3", got)
+	}
+	for i := 0; i < 3; i++ {
+		safeMode.Record(safeModeEpoch)
+	}
+	if reason := safeMode.Admit(detection, safeModeEpoch.Add(time.Minute)); reason == "" {
+		t.Error("admitted a 4th action after 3 executions")
+	}
+}
+
+func TestSafeModeMaxActionsDisabled(t *testing.T) {
+	safeMode := newSafeMode(func(cfg *core.Config) { cfg.Actuator.MaxActions = -1 })
+	for i := 0; i < 10; i++ {
+		safeMode.Record(safeModeEpoch)
+	}
+	if reason := safeMode.Admit(confidentDetection(), safeModeEpoch); reason != "" {
+		t.Errorf("with max_actions disabled: blocked with %q", reason)
+	}
+}
+
+func TestBlocked(t *testing.T) {
+	result := Blocked("checkout", ActionRestart, "detection was made on partial data")
+	if !result.Blocked || result.Executed || result.Message != "blocked: detection was made on partial data" {
+		t.Errorf("result = %+v, want a blocked, unexecuted result with the reason", result)
+	}
+}
@@ -1 +1,38 @@
 package actuator
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrReplicaBounds is returned when a scale target falls outside the allowed range
+var ErrReplicaBounds = errors.New("replica count out of bounds")
+
+// defaultMaxReplicas caps scaling when kubernetes.max_replicas is not configured
+const defaultMaxReplicas int32 = 10
+
+// scaleTarget resolves the replica count for a SCALE_UP action. The analyzer's
+// target is used when it is numeric, otherwise the deployment grows by one.
+// Targets above maxReplicas are rejected rather than silently clamped.
+func scaleTarget(current int32, target interface{}, maxReplicas int32) (int32, error) {
+	desired := current + 1
+	switch v := target.(type) {
+	case int:
+		desired = int32(v)
+	case int32:
+		desired = v
+	case int64:
+		desired = int32(v)
+	case float64:
+		desired = int32(v)
+	}
+
+	if desired <= current {
+		return 0, fmt.Errorf("%w: scale up target %d must exceed current replicas %d", ErrReplicaBounds, desired, current)
+	}
+	if desired > maxReplicas {
+		return 0, fmt.Errorf("%w: scale up target %d exceeds max replicas %d", ErrReplicaBounds, desired, maxReplicas)
+	}
+
+	return desired, nil
+}
@@ -0,0 +1,193 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+// MaxCompareServices caps how many services a full comparison may request
+const MaxCompareServices = 10
+
+// scoringWeights returns the configured impact and priority weights
+func (ua *UltimateAnalyzer) scoringWeights() core.ScoringWeights {
+	if cfg := ua.cfg(); cfg != nil {
+		return cfg.Scoring.WithDefaults()
+	}
+	return core.DefaultScoringWeights()
+}
+
+// AnalyzeServiceAdvanced condenses an ultimate diagnosis into the advanced
+// triage view: root cause, impact, per-metric trends and detector correlations
+func (ua *UltimateAnalyzer) AnalyzeServiceAdvanced(ctx context.Context, serviceName string) (*AdvancedDiagnosis, error) {
+	diag, err := ua.DiagnoseService(ctx, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	impactScore := ua.calculateImpactScore(diag)
+
+	return &AdvancedDiagnosis{
+		BasicDiagnosis: basicDiagnosis(diag),
+		RootCause:      describeRootCause(diag.RootCause),
+		ImpactScore:    impactScore,
+		TrendAnalysis:  analyzeTrends(diag.Features),
+		Correlations:   ua.correlateDetections(diag),
+		PriorityScore:  ua.calculatePriorityScore(diag, impactScore),
+	}, nil
+}
+
+// CompareServicesFull runs AnalyzeServiceAdvanced for each service concurrently
+func (ua *UltimateAnalyzer) CompareServicesFull(ctx context.Context, services []string) (map[string]*AdvancedDiagnosis, error) {
+	if len(services) > MaxCompareServices {
+		return nil, fmt.Errorf("too many services: %d (max %d)", len(services), MaxCompareServices)
+	}
+
+	results := make(map[string]*AdvancedDiagnosis, len(services))
+	errs := make(map[string]error)
+	var mu sync.Mutex
+
+	ua.forEachService(ctx, services, func(ctx context.Context, _ int, serviceName string) {
+		advanced, err := ua.AnalyzeServiceAdvanced(ctx, serviceName)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs[serviceName] = err
+			return
+		}
+		results[serviceName] = advanced
+	})
+
+	if len(results) == 0 && len(errs) > 0 {
+		return nil, fmt.Errorf("failed to analyze any of %d services", len(services))
+	}
+
+	return results, nil
+}
+
+// calculateImpactScore (0-100) weighs the primary issue's confidence by severity
+// and problem type, plus a breadth bonus for each additional detected issue
+func (ua *UltimateAnalyzer) calculateImpactScore(diag *UltimateDiagnosis) float64 {
+	primary := diag.PrimaryDetection
+	if primary == nil || !primary.Detected {
+		return 0
+	}
+
+	weights := ua.scoringWeights()
+	multiplier, ok := weights.TypeMultipliers[string(primary.Type)]
+	if !ok {
+		multiplier = 1.0
+	}
+
+	score := primary.Confidence * weights.SeverityWeights[primary.Severity] * multiplier
+
+	for _, d := range diag.AllDetections {
+		if d.Detected && d != primary {
+			score += weights.BreadthBonus
+		}
+	}
+
+	return math.Min(score, 100)
+}
+
+// calculatePriorityScore (0-100) blends impact with how unhealthy the service is
+// and whether immediate action is required
+func (ua *UltimateAnalyzer) calculatePriorityScore(diag *UltimateDiagnosis, impactScore float64) float64 {
+	weights := ua.scoringWeights()
+	score := impactScore*weights.ImpactWeight + (100-diag.HealthScore)*weights.HealthWeight
+	if diag.ActionRequired {
+		score += weights.ActionBonus
+	}
+	return math.Max(0, math.Min(score, 100))
+}
+
+func (ua *UltimateAnalyzer) correlateDetections(diag *UltimateDiagnosis) []CorrelationInsight {
+	insights := make([]CorrelationInsight, 0)
+	primary := diag.PrimaryDetection
+	if primary == nil || !primary.Detected {
+		return insights
+	}
+
+	for _, d := range diag.AllDetections {
+		if !d.Detected || d == primary {
+			continue
+		}
+		insights = append(insights, CorrelationInsight{
+			Detector1:   string(primary.Type),
+			Detector2:   string(d.Type),
+			Correlation: math.Min(primary.Confidence, d.Confidence) / 100,
+			Explanation: ua.determineIssueRelationship(primary.Type, d.Type),
+			Causality:   fmt.Sprintf("%s → %s", primary.Type, d.Type),
+		})
+	}
+
+	return insights
+}
+
+func basicDiagnosis(diag *UltimateDiagnosis) *Diagnosis {
+	primary := diag.PrimaryDetection
+	basic := &Diagnosis{
+		ServiceName:    diag.ServiceName,
+		Problem:        primary.Type,
+		Confidence:     primary.Confidence,
+		Timestamp:      diag.Timestamp,
+		Evidence:       primary.Evidence,
+		Recommendation: primary.Recommendation,
+		Severity:       primary.Severity,
+		AllDetections:  make([]Detection, 0, len(diag.AllDetections)),
+	}
+
+	detectedCount := 0
+	for _, d := range diag.AllDetections {
+		basic.AllDetections = append(basic.AllDetections, *d)
+		if d.Detected {
+			detectedCount++
+			if d.Confidence >= 80 {
+				basic.HighConfidenceCount++
+			}
+		}
+	}
+	basic.MultipleProblems = detectedCount > 1
+
+	return basic
+}
+
+func describeRootCause(rca *RootCauseAnalysis) string {
+	if rca == nil {
+		return ""
+	}
+	if len(rca.ContributingIssues) == 0 {
+		return rca.PrimaryIssue
+	}
+	return fmt.Sprintf("%s (contributing: %s)", rca.PrimaryIssue, strings.Join(rca.ContributingIssues, "; "))
+}
+
+// analyzeTrends maps each core metric to increasing, decreasing or stable
+func analyzeTrends(features *ServiceFeatures) map[string]string {
+	trends := make(map[string]string)
+	if features == nil {
+		return trends
+	}
+
+	classify := func(slope float64) string {
+		switch {
+		case slope > 0.1:
+			return "increasing"
+		case slope < -0.1:
+			return "decreasing"
+		default:
+			return "stable"
+		}
+	}
+
+	trends["cpu"] = classify(features.CPUTrend)
+	trends["memory"] = classify(features.MemoryTrend)
+	trends["error_rate"] = classify(features.ErrorRateTrend)
+
+	return trends
+}
@@ -0,0 +1,74 @@
+package analyzer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+func newScoringAnalyzer(scoring core.ScoringWeights) *UltimateAnalyzer {
+	cfg := &core.Config{Scoring: scoring}
+	cfg.ApplyDefaults()
+	return NewUltimateAnalyzer(nil, core.NewConfigStore("", cfg))
+}
+
+// scoredDiagnosis has a HIGH memory leak at 80% plus one other detected issue
+func scoredDiagnosis() *UltimateDiagnosis {
+	primary := &Detection{Type: DetectionMemoryLeak, Detected: true, Confidence: 80, Severity: SeverityHigh}
+	return &UltimateDiagnosis{
+		PrimaryDetection: primary,
+		AllDetections: []*Detection{
+			primary,
+			{Type: DetectionExternalFailure, Detected: true, Confidence: 60},
+			{Type: DetectionDeploymentBug, Detected: false, Confidence: 20},
+		},
+		HealthScore:    40,
+		ActionRequired: true,
+	}
+}
+
+func TestImpactAndPriorityDefaults(t *testing.T) {
+	ua := newScoringAnalyzer(core.ScoringWeights{})
+	diag := scoredDiagnosis()
+
+	// 80 x 0.75 (HIGH) x 1.2 (MEMORY_LEAK) + 10 for the other issue
+	impact := ua.calculateImpactScore(diag)
+	assertScore(t, "impact", impact, 82)
+
+	// 82 x 0.6 + (100-40) x 0.3 + 10
+	assertScore(t, "priority", ua.calculatePriorityScore(diag, impact), 82*0.6+60*0.3+10)
+	diag.ActionRequired = false
+	assertScore(t, "priority without action", ua.calculatePriorityScore(diag, impact), 82*0.6+60*0.3)
+}
+
+func TestImpactAndPriorityConfigured(t *testing.T) {
+	ua := newScoringAnalyzer(core.ScoringWeights{
+		TypeMultipliers: map[string]float64{string(DetectionMemoryLeak): 0.5},
+		SeverityWeights: map[string]float64{SeverityHigh: 1.0},
+		BreadthBonus:    2,
+		ImpactWeight:    0.5,
+		HealthWeight:    0.1,
+		ActionBonus:     5,
+	})
+	diag := scoredDiagnosis()
+
+	impact := ua.calculateImpactScore(diag)
+	assertScore(t, "impact", impact, 80*1.0*0.5+2)
+	assertScore(t, "priority", ua.calculatePriorityScore(diag, impact), 42*0.5+60*0.1+5)
+}
+
+func TestImpactNothingDetected(t *testing.T) {
+	ua := newScoringAnalyzer(core.ScoringWeights{})
+	diag := &UltimateDiagnosis{PrimaryDetection: &Detection{Type: DetectionMemoryLeak, Confidence: 90, Severity: SeverityCritical}}
+	if got := ua.calculateImpactScore(diag); got != 0 {
+		t.Errorf("impact of an undetected primary = %v, want 0", got)
+	}
+}
+
+func assertScore(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("%s = %v, want %v", name, got, want)
+	}
+}
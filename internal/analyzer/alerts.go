@@ -0,0 +1,44 @@
+package analyzer
+
+import (
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/notify"
+)
+
+// defaultNotificationActions is how many actuator actions a notification
+// carries when notifications.max_actions is unset
+const defaultNotificationActions = 3
+
+func notificationMaxActions(cfg *core.Config) int {
+	if cfg == nil || cfg.Notifications.MaxActions == 0 {
+		return defaultNotificationActions
+	}
+	if cfg.Notifications.MaxActions < 0 {
+		return 0
+	}
+	return cfg.Notifications.MaxActions
+}
+
+// notificationActions returns the diagnosis' first actuator actions, in the
+// order generateActuatorActions ranks them, for embedding in a notification
+func notificationActions(cfg *core.Config, diag *UltimateDiagnosis) []notify.Action {
+	n := notificationMaxActions(cfg)
+	if n > len(diag.ActuatorActions) {
+		n = len(diag.ActuatorActions)
+	}
+	if n == 0 {
+		return nil
+	}
+
+	actions := make([]notify.Action, n)
+	for i, a := range diag.ActuatorActions[:n] {
+		actions[i] = notify.Action{
+			Type:        a.ActionType,
+			Priority:    a.Priority,
+			Target:      a.TargetMetric,
+			TargetValue: a.TargetValue,
+			Reason:      a.Reason,
+		}
+	}
+	return actions
+}
@@ -0,0 +1,117 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/notify"
+	"go.uber.org/zap"
+)
+
+// actionDiagnosis is a HIGH diagnosis of the given type with its actuator
+// actions generated as a live diagnosis would
+func actionDiagnosis(ua *UltimateAnalyzer, typ DetectionType, features *ServiceFeatures) *UltimateDiagnosis {
+	diag := &UltimateDiagnosis{
+		ServiceName:      "checkout",
+		RiskLevel:        "HIGH",
+		Features:         features,
+		PrimaryDetection: &Detection{Type: typ, Detected: true, Confidence: 80},
+	}
+	diag.ActuatorActions = ua.generateActuatorActions(diag)
+	return diag
+}
+
+func TestNotificationActionsLimit(t *testing.T) {
+	cfg := &core.Config{}
+	cfg.ApplyDefaults()
+	ua := NewUltimateAnalyzer(nil, core.NewConfigStore("", cfg))
+	// CPU, memory and volatility all call for action
+	diag := actionDiagnosis(ua, DetectionResourceExhaustion, &ServiceFeatures{CPUMean: 95, CPUVolatility: 35, MemoryMean: 92})
+	if len(diag.ActuatorActions) < 3 {
+		t.Fatalf("diagnosis has %d actions, want at least 3", len(diag.ActuatorActions))
+	}
+
+	tests := []struct {
+		name       string
+		maxActions int
+		want       int
+	}{
+		{"default", 0, defaultNotificationActions},
+		{"one", 1, 1},
+		{"more than available", 20, len(diag.ActuatorActions)},
+		{"omitted", -1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &core.Config{}
+			cfg.Notifications.MaxActions = tt.maxActions
+			actions := notificationActions(cfg, diag)
+			if len(actions) != tt.want {
+				t.Fatalf("got %d actions, want %d", len(actions), tt.want)
+			}
+			for i, a := range actions {
+				if a.Type != diag.ActuatorActions[i].ActionType {
+					t.Errorf("action %d = %s, want %s in ranked order", i, a.Type, diag.ActuatorActions[i].ActionType)
+				}
+			}
+		})
+	}
+}
+
+func TestWebhookPayloadCarriesActions(t *testing.T) {
+	cfg := &core.Config{}
+	cfg.ApplyDefaults()
+	ua := NewUltimateAnalyzer(nil, core.NewConfigStore("", cfg))
+
+	// A Slack-style template embedding the actions
+	webhook, err := notify.NewWebhookNotifier(core.WebhookConfig{
+		URL:      "http://localhost:1",
+		Template: `{"text": {{json .Title}}, "actions": {{json .Actions}}}`,
+	}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		typ      DetectionType
+		features *ServiceFeatures
+		action   string
+		target   string
+		value    interface{}
+	}{
+		{"resource exhaustion", DetectionResourceExhaustion, &ServiceFeatures{CPUMean: 95}, "SCALE_UP", "replicas", 2.0},
+		{"deployment bug", DetectionDeploymentBug, &ServiceFeatures{ErrorRateMean: 40, ErrorRateSpikiness: 3}, "ROLLBACK", "deployment", "previous_stable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diag := actionDiagnosis(ua, tt.typ, tt.features)
+			body, err := webhook.Render(notify.Notification{
+				Service: diag.ServiceName,
+				Title:   string(tt.typ),
+				Actions: notificationActions(cfg, diag),
+			})
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+
+			var payload struct {
+				Actions []notify.Action `json:"actions"`
+			}
+			if err := json.Unmarshal(body, &payload); err != nil {
+				t.Fatalf("payload %s is not JSON: %v", body, err)
+			}
+			if len(payload.Actions) == 0 {
+				t.Fatalf("payload %s carries no actions", body)
+			}
+			first := payload.Actions[0]
+			if first.Type != tt.action || first.Target != tt.target || first.TargetValue != tt.value {
+				t.Errorf("first action = %+v, want %s of %s to %v", first, tt.action, tt.target, tt.value)
+			}
+			if first.Priority != "HIGH" || first.Reason == "" {
+				t.Errorf("first action priority/reason = %q/%q, want HIGH with a reason", first.Priority, first.Reason)
+			}
+		})
+	}
+}
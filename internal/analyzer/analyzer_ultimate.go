@@ -2,32 +2,68 @@ package analyzer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/report"
 	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
 	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/pkg/logger"
 	"go.uber.org/zap"
 )
 
+// DetectorVersion identifies the detection logic that produced a diagnosis.
+// Bump it whenever detector scoring, thresholds or defaults change, so stored
+// diagnoses and backtests from before and after the change can be told apart.
+const DetectorVersion = "4"
+
 // UltimateAnalyzer integrates all AI-level components
 type UltimateAnalyzer struct {
 	featureExtractor *FeatureExtractor
 	enhancedDetector *EnhancedDetector
+	patternMatcher   *PatternMatcher
+	risk             *RiskClassifier
 	db               *storage.PostgresClient
+	config           *core.ConfigStore
+
+	dedup     diagnosisDedup
+	lastKnown *lastKnownCache
 }
 
-func NewUltimateAnalyzer(db *storage.PostgresClient) *UltimateAnalyzer {
-	fe := NewFeatureExtractor(db)
-	ed := NewEnhancedDetector(fe)
+func NewUltimateAnalyzer(db *storage.PostgresClient, config *core.ConfigStore) *UltimateAnalyzer {
+	fe := NewFeatureExtractor(db, config)
+	ed := NewEnhancedDetector(fe, config)
 
 	return &UltimateAnalyzer{
 		featureExtractor: fe,
 		enhancedDetector: ed,
+		patternMatcher:   NewPatternMatcher(),
+		risk:             NewRiskClassifier(config),
 		db:               db,
+		config:           config,
+		dedup:            diagnosisDedup{cache: make(map[diagnosisKey]diagnosisCacheEntry)},
+		lastKnown:        newLastKnownCache(maxLastKnownDiagnoses),
+	}
+}
+
+func (ua *UltimateAnalyzer) cfg() *core.Config {
+	return ua.config.Get()
+}
+
+// perDetectorTimeout bounds a single detector so a slow one can't starve the
+// rest of the diagnosis
+func (ua *UltimateAnalyzer) perDetectorTimeout() time.Duration {
+	if cfg := ua.cfg(); cfg != nil {
+		if d, err := time.ParseDuration(cfg.Analyzer.PerDetectorTimeout); err == nil && d > 0 {
+			return d
+		}
 	}
+	return defaultPerDetectorTimeout
 }
 
 // ActuatorAction represents a concrete action for the actuator
@@ -53,30 +89,36 @@ type RootCauseAnalysis struct {
 
 // UltimateDiagnosis represents comprehensive AI-level diagnosis
 type UltimateDiagnosis struct {
-	ServiceName      string
-	Timestamp        time.Time
-	AnalysisDuration time.Duration
+	ServiceName      string        `json:"service_name"`
+	Timestamp        time.Time     `json:"timestamp"`
+	AnalysisDuration time.Duration `json:"analysis_duration"`
 
 	// Extracted features
-	Features *ServiceFeatures
+	Features *ServiceFeatures `json:"features"`
 
 	// Primary detection (highest confidence)
-	PrimaryDetection *Detection
+	PrimaryDetection *Detection `json:"primary_detection"`
 
 	// All detections
-	AllDetections []*Detection
+	AllDetections []*Detection `json:"all_detections"`
 
 	// Composite metrics
-	HealthScore         float64 // 0-100
-	StabilityIndex      float64 // 0-10
-	PredictabilityScore float64 // 0-100
-	SystemStress        float64 // 0-100
+	HealthScore         float64 `json:"health_score"`         // 0-100
+	StabilityIndex      float64 `json:"stability_index"`      // 0-10
+	PredictabilityScore float64 `json:"predictability_score"` // 0-100
+	SystemStress        float64 `json:"system_stress"`        // 0-100
 
 	// Decision support
-	RiskLevel          string // LOW, NORMAL, MEDIUM, HIGH, CRITICAL
-	ActionRequired     bool
-	PredictiveInsights []string
-	Recommendation     string
+	RiskLevel          string   `json:"risk_level"` // LOW, NORMAL, MEDIUM, HIGH, CRITICAL
+	ActionRequired     bool     `json:"action_required"`
+	PredictiveInsights []string `json:"predictive_insights"`
+	Recommendation     string   `json:"recommendation"` // Report rendered as Markdown
+
+	// Report is the structured form of Recommendation
+	Report *report.Recommendation `json:"report,omitempty"`
+
+	// Notes are the operator notes on the service in effect at diagnosis time
+	Notes []*storage.ServiceNote `json:"notes,omitempty"`
 
 	// Actuator-ready outputs
 	RootCause        *RootCauseAnalysis     `json:"root_cause"`
@@ -84,74 +126,74 @@ type UltimateDiagnosis struct {
 	ImpactAssessment map[string]interface{} `json:"impact_assessment"`
 
 	// Traceability
-	PredictionID string
+	PredictionID    string `json:"prediction_id"`
+	DetectorVersion string `json:"detector_version"`
 
 	// ✨ ENHANCED DIAGNOSTIC DATA ✨
 	EnhancedData *EnhancedDiagnosticData `json:"enhanced_data,omitempty"`
 }
 
-// DiagnoseService performs ultimate comprehensive diagnosis
-func (ua *UltimateAnalyzer) DiagnoseService(ctx context.Context, serviceName string) (*UltimateDiagnosis, error) {
+// Record converts the diagnosis into its persisted form
+func (d *UltimateDiagnosis) Record() *storage.UltimateDiagnosisRecord {
+	return &storage.UltimateDiagnosisRecord{
+		PredictionID:        d.PredictionID,
+		ServiceName:         d.ServiceName,
+		Timestamp:           d.Timestamp,
+		AnalysisDuration:    d.AnalysisDuration,
+		Features:            d.Features,
+		PrimaryProblem:      string(d.PrimaryDetection.Type),
+		PrimaryDetected:     d.PrimaryDetection.Detected,
+		PrimaryConfidence:   d.PrimaryDetection.Confidence,
+		PrimarySeverity:     d.PrimaryDetection.Severity,
+		PrimaryEvidence:     d.PrimaryDetection.Evidence,
+		AllDetections:       d.AllDetections,
+		HealthScore:         d.HealthScore,
+		StabilityIndex:      d.StabilityIndex,
+		PredictabilityScore: d.PredictabilityScore,
+		SystemStress:        d.SystemStress,
+		RiskLevel:           d.RiskLevel,
+		ActionRequired:      d.ActionRequired,
+		PredictiveInsights:  d.PredictiveInsights,
+		Recommendation:      d.Recommendation,
+		DetectorVersion:     d.DetectorVersion,
+		Diagnosis:           d,
+	}
+}
+
+// diagnose performs ultimate comprehensive diagnosis. Callers go through
+// DiagnoseService, which de-duplicates concurrent requests.
+func (ua *UltimateAnalyzer) diagnose(ctx context.Context, serviceName string) (*UltimateDiagnosis, error) {
 	startTime := time.Now()
 
-	logger.Info("🔍 Starting AI-level diagnosis",
+	logger.FromContext(ctx).Info("🔍 Starting AI-level diagnosis",
 		zap.String("service", serviceName),
 	)
 
 	diagnosis := &UltimateDiagnosis{
-		ServiceName:  serviceName,
-		Timestamp:    time.Now(),
-		PredictionID: uuid.New().String(),
+		ServiceName:     serviceName,
+		Timestamp:       storage.AsOf(ctx),
+		PredictionID:    uuid.New().String(),
+		DetectorVersion: DetectorVersion,
 	}
 
 	// Step 1: Extract comprehensive features
-	features, err := ua.featureExtractor.ExtractFeatures(ctx, serviceName, 30*time.Minute)
+	features, err := ua.featureExtractor.ExtractFeatures(ctx, serviceName, analysisWindow(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("feature extraction failed: %w", err)
 	}
 	diagnosis.Features = features
 
 	// Step 2: Run all enhanced detectors
-	detections := make([]*Detection, 0, 5)
-
-	// Memory leak detection
-	if d, err := ua.enhancedDetector.DetectMemoryLeakEnhanced(ctx, serviceName); err == nil {
-		detections = append(detections, d)
-	}
+	detections := ua.runDetectors(ctx, serviceName, ua.detectors())
 
-	// Resource exhaustion detection
-	if d, err := ua.enhancedDetector.DetectResourceExhaustionEnhanced(ctx, serviceName); err == nil {
-		detections = append(detections, d)
-	}
-
-	// Deployment bug detection
-	if d, err := ua.enhancedDetector.DetectDeploymentBugEnhanced(ctx, serviceName); err == nil {
-		detections = append(detections, d)
-	}
-
-	// External failure detection
-	if d, err := ua.enhancedDetector.DetectExternalFailureEnhanced(ctx, serviceName); err == nil {
-		detections = append(detections, d)
-	}
-
-	// Cascade failure detection
-	if d, err := ua.enhancedDetector.DetectCascadeFailureEnhanced(ctx, serviceName); err == nil {
-		detections = append(detections, d)
-	}
+	// Pod events (OOMKilled, CrashLoop, FailedScheduling) corroborate the
+	// metric-based detections
+	ua.corroborateWithEvents(ctx, serviceName, detections)
 
 	diagnosis.AllDetections = detections
 
 	// Step 3: Determine primary detection (highest confidence among detected issues)
-	var primaryDetection *Detection
-	maxConfidence := 0.0
-
-	for _, d := range detections {
-		if d.Detected && d.Confidence > maxConfidence {
-			maxConfidence = d.Confidence
-			primaryDetection = d
-		}
-	}
-
+	primaryDetection := primaryOf(detections)
 	if primaryDetection == nil {
 		// No issues detected - create healthy detection
 		primaryDetection = &Detection{
@@ -176,7 +218,7 @@ func (ua *UltimateAnalyzer) DiagnoseService(ctx context.Context, serviceName str
 
 	// Step 5: Determine risk level
 	diagnosis.RiskLevel = ua.determineRiskLevel(diagnosis)
-	diagnosis.ActionRequired = diagnosis.RiskLevel == "CRITICAL" || diagnosis.RiskLevel == "HIGH"
+	diagnosis.ActionRequired = diagnosis.RiskLevel == RiskCritical || diagnosis.RiskLevel == RiskHigh
 
 	// Step 6: Generate predictive insights
 	diagnosis.PredictiveInsights = ua.generatePredictiveInsights(features, detections)
@@ -191,14 +233,21 @@ func (ua *UltimateAnalyzer) DiagnoseService(ctx context.Context, serviceName str
 	diagnosis.ImpactAssessment = ua.assessImpact(diagnosis)
 
 	// Step 10: Generate actionable recommendation
-	diagnosis.Recommendation = ua.generateRecommendation(diagnosis)
+	diagnosis.Report = ua.buildRecommendation(diagnosis)
+	diagnosis.Recommendation = report.Markdown(diagnosis.Report)
 
 	// Step 11: 🌟 Generate Enhanced Diagnostic Data 🌟
-	diagnosis.EnhancedData = ua.generateEnhancedData(diagnosis)
+	diagnosis.EnhancedData = ua.generateEnhancedData(ctx, diagnosis)
+
+	// Step 12: Record the health score for trends and the health timeline
+	ua.recordHealth(ctx, diagnosis)
+
+	// Step 13: Attach operator notes so their context travels with alerts
+	diagnosis.Notes = ua.serviceNotes(ctx, serviceName)
 
 	diagnosis.AnalysisDuration = time.Since(startTime)
 
-	logger.Info("✅ AI-level diagnosis complete",
+	logger.FromContext(ctx).Info("✅ AI-level diagnosis complete",
 		zap.String("service", serviceName),
 		zap.String("primary_problem", string(primaryDetection.Type)),
 		zap.Float64("confidence", primaryDetection.Confidence),
@@ -209,29 +258,138 @@ func (ua *UltimateAnalyzer) DiagnoseService(ctx context.Context, serviceName str
 	return diagnosis, nil
 }
 
-func (ua *UltimateAnalyzer) determineRiskLevel(diag *UltimateDiagnosis) string {
-	// CRITICAL: Severity is critical OR health score < 30
-	if diag.PrimaryDetection.Severity == SeverityCritical || diag.HealthScore < 30 {
-		return "CRITICAL"
+// defaultPerDetectorTimeout applies when analyzer.per_detector_timeout is unset
+const defaultPerDetectorTimeout = 10 * time.Second
+
+// namedDetector pairs an enhanced detector with the name used in logs and the
+// detection type reported when it fails to produce a result
+type namedDetector struct {
+	name   string
+	typ    DetectionType
+	detect func(ctx context.Context, serviceName string) (*Detection, error)
+}
+
+// detectors returns the enabled detectors a diagnosis runs
+func (ua *UltimateAnalyzer) detectors() []namedDetector {
+	ed := ua.enhancedDetector
+	all := []namedDetector{
+		{"memory_leak", DetectionMemoryLeak, ed.DetectMemoryLeakEnhanced},
+		{"resource_exhaustion", DetectionResourceExhaustion, ed.DetectResourceExhaustionEnhanced},
+		{"deployment_bug", DetectionDeploymentBug, ed.DetectDeploymentBugEnhanced},
+		{"external_failure", DetectionExternalFailure, ed.DetectExternalFailureEnhanced},
+		{"cascade_failure", DetectionCascadingFailure, ed.DetectCascadeFailureEnhanced},
+	}
+
+	cfg := ua.cfg()
+	enabled := all[:0]
+	for _, det := range all {
+		if detectorEnabled(cfg, det.name) {
+			enabled = append(enabled, det)
+		}
+	}
+	return enabled
+}
+
+type detectorOutcome struct {
+	detection *Detection
+	err       error
+}
+
+// runDetectors fans the detectors out concurrently, each under its own
+// timeout, and returns one detection per detector in detector order. A
+// detector that errors, panics or times out is reported with a non-ok Status
+// and Detected=false, so the diagnosis is built from the ones that finished.
+func (ua *UltimateAnalyzer) runDetectors(ctx context.Context, serviceName string, detectors []namedDetector) []*Detection {
+	timeout := ua.perDetectorTimeout()
+	detections := make([]*Detection, len(detectors))
+
+	var wg sync.WaitGroup
+	for i, det := range detectors {
+		wg.Add(1)
+		go func(i int, det namedDetector) {
+			defer wg.Done()
+
+			detCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			// Buffered so a detector that ignores its context can finish
+			// later without leaking a blocked goroutine
+			done := make(chan detectorOutcome, 1)
+			go func() {
+				defer func() {
+					if r := recover(); r != nil {
+						done <- detectorOutcome{err: fmt.Errorf("detector %s panicked: %v", det.name, r)}
+					}
+				}()
+				d, err := det.detect(detCtx, serviceName)
+				done <- detectorOutcome{detection: d, err: err}
+			}()
+
+			var outcome detectorOutcome
+			select {
+			case outcome = <-done:
+			case <-detCtx.Done():
+				outcome = detectorOutcome{err: detCtx.Err()}
+			}
+			detections[i] = ua.detectorResult(ctx, serviceName, det, outcome, timeout)
+		}(i, det)
+	}
+	wg.Wait()
+
+	return detections
+}
+
+// primaryOf returns the detected issue with the highest confidence, or nil
+// when nothing was detected. Detectors that didn't finish are never detected.
+func primaryOf(detections []*Detection) *Detection {
+	var primary *Detection
+	maxConfidence := 0.0
+	for _, d := range detections {
+		if d.Detected && d.Confidence > maxConfidence {
+			maxConfidence = d.Confidence
+			primary = d
+		}
 	}
+	return primary
+}
 
-	// HIGH: Severity is high OR health score < 50 OR system stress > 80
-	if diag.PrimaryDetection.Severity == SeverityHigh || diag.HealthScore < 50 || diag.SystemStress > 80 {
-		return "HIGH"
+func (ua *UltimateAnalyzer) detectorResult(ctx context.Context, serviceName string, det namedDetector, outcome detectorOutcome, timeout time.Duration) *Detection {
+	if outcome.err == nil && outcome.detection != nil {
+		outcome.detection.Status = DetectionStatusOK
+		ua.calibrate(outcome.detection)
+		return outcome.detection
 	}
 
-	// MEDIUM: Severity is medium OR health score < 70
-	if diag.PrimaryDetection.Severity == SeverityMedium || diag.HealthScore < 70 {
-		return "MEDIUM"
+	status := DetectionStatusError
+	if errors.Is(outcome.err, context.DeadlineExceeded) {
+		status = DetectionStatusTimeout
+	}
+	if outcome.err == nil {
+		outcome.err = fmt.Errorf("detector %s returned no result", det.name)
 	}
 
-	// LOW: Any detection with low severity
-	if diag.PrimaryDetection.Severity == SeverityLow {
-		return "LOW"
+	logger.FromContext(ctx).Warn("Detector did not complete",
+		zap.String("service", serviceName),
+		zap.String("detector", det.name),
+		zap.String("status", status),
+		zap.Duration("timeout", timeout),
+		zap.Error(outcome.err),
+	)
+
+	return &Detection{
+		Type:        det.typ,
+		ServiceName: serviceName,
+		Detected:    false,
+		Severity:    SeverityNone,
+		Status:      status,
+		Error:       outcome.err.Error(),
+		Timestamp:   time.Now(),
+		Evidence:    map[string]interface{}{},
 	}
+}
 
-	// NORMAL: No issues
-	return "NORMAL"
+func (ua *UltimateAnalyzer) determineRiskLevel(diag *UltimateDiagnosis) string {
+	return ua.risk.RiskLevel(diag.PrimaryDetection.Severity, diag.HealthScore, diag.SystemStress)
 }
 
 func (ua *UltimateAnalyzer) generatePredictiveInsights(features *ServiceFeatures, detections []*Detection) []string {
@@ -290,150 +448,110 @@ func (ua *UltimateAnalyzer) generatePredictiveInsights(features *ServiceFeatures
 	return insights
 }
 
-func (ua *UltimateAnalyzer) generateRecommendation(diag *UltimateDiagnosis) string {
-	var recommendation string
-
-	// Build urgent action header based on risk level
-	switch diag.RiskLevel {
-	case "CRITICAL":
-		recommendation = "🚨 **CRITICAL ACTION REQUIRED**\n\n"
-		recommendation += fmt.Sprintf("**Primary Issue:** %s (%.1f%% confidence)\n", diag.PrimaryDetection.Type, diag.PrimaryDetection.Confidence)
-		recommendation += fmt.Sprintf("**Time to Impact:** %s\n\n", diag.RootCause.TimeToImpact)
-	case "HIGH":
-		recommendation = "⚠️ **URGENT ACTION REQUIRED**\n\n"
-		recommendation += fmt.Sprintf("**Primary Issue:** %s (%.1f%% confidence)\n", diag.PrimaryDetection.Type, diag.PrimaryDetection.Confidence)
-		recommendation += fmt.Sprintf("**Time to Impact:** %s\n\n", diag.RootCause.TimeToImpact)
-	case "MEDIUM":
-		recommendation = "⚡ **ATTENTION REQUIRED**\n\n"
-		recommendation += fmt.Sprintf("**Detected Issue:** %s (%.1f%% confidence)\n\n", diag.PrimaryDetection.Type, diag.PrimaryDetection.Confidence)
-	case "LOW":
-		recommendation = "📊 **ADVISORY NOTICE**\n\n"
-		recommendation += fmt.Sprintf("**Monitoring:** %s (%.1f%% confidence)\n\n", diag.PrimaryDetection.Type, diag.PrimaryDetection.Confidence)
-	default:
-		recommendation = "✅ **SYSTEM HEALTHY**\n\n"
-		recommendation += fmt.Sprintf("No critical issues detected. Health Score: %.0f/100\n\n", diag.HealthScore)
-	}
-
-	// Add immediate actions
-	if len(diag.ActuatorActions) > 0 {
-		recommendation += "**IMMEDIATE ACTIONS:**\n"
-		immediateCount := 0
-		for _, action := range diag.ActuatorActions {
-			if action.Priority == "IMMEDIATE" {
-				immediateCount++
-				recommendation += fmt.Sprintf("%d. **%s**: %s\n   - Current: %v → Target: %v\n   - Reason: %s\n",
-					immediateCount, action.ActionType, action.TargetMetric,
-					action.CurrentValue, action.TargetValue, action.Reason)
-			}
-		}
-
-		// Add high priority actions
-		highCount := 0
-		for _, action := range diag.ActuatorActions {
-			if action.Priority == "HIGH" {
-				if highCount == 0 {
-					recommendation += "\n**HIGH PRIORITY ACTIONS:**\n"
-				}
-				highCount++
-				recommendation += fmt.Sprintf("%d. **%s**: %s\n",
-					highCount, action.ActionType, action.Reason)
-			}
-		}
-		recommendation += "\n"
+// buildRecommendation collects the structured advice for a diagnosis;
+// package report renders it
+func (ua *UltimateAnalyzer) buildRecommendation(diag *UltimateDiagnosis) *report.Recommendation {
+	rec := &report.Recommendation{
+		ServiceName:         diag.ServiceName,
+		RiskLevel:           diag.RiskLevel,
+		Problem:             string(diag.PrimaryDetection.Type),
+		Confidence:          diag.PrimaryDetection.Confidence,
+		ImmediateActions:    []report.Action{},
+		HighPriorityActions: []report.Action{},
+		AffectedMetrics:     diag.RootCause.AffectedMetrics,
+		Insights:            diag.PredictiveInsights,
+		Health: report.Health{
+			Score:          diag.HealthScore,
+			Rating:         report.HealthRating(diag.HealthScore),
+			SystemStress:   diag.SystemStress,
+			StabilityIndex: diag.StabilityIndex,
+			Predictability: diag.PredictabilityScore,
+		},
+		NextSteps:   nextSteps(diag),
+		DiagnosisID: diag.PredictionID,
+		GeneratedAt: diag.Timestamp,
 	}
-
-	// Add root cause explanation
-	if len(diag.RootCause.ContributingIssues) > 0 {
-		recommendation += "**ROOT CAUSE ANALYSIS:**\n"
-		recommendation += fmt.Sprintf("- Primary Issue: %s\n", diag.RootCause.PrimaryIssue)
-		recommendation += "- Contributing Factors:\n"
-		for _, issue := range diag.RootCause.ContributingIssues {
-			recommendation += fmt.Sprintf("  • %s\n", issue)
-		}
-		recommendation += "\n"
+	if diag.RiskLevel == RiskCritical || diag.RiskLevel == RiskHigh {
+		rec.TimeToImpact = diag.RootCause.TimeToImpact
 	}
 
-	// Add affected metrics with severity
-	if len(diag.RootCause.AffectedMetrics) > 0 {
-		recommendation += "**AFFECTED METRICS:**\n"
-		for _, metric := range diag.RootCause.AffectedMetrics {
-			recommendation += fmt.Sprintf("- %s\n", metric)
+	for _, action := range diag.ActuatorActions {
+		a := report.Action{
+			Type:         action.ActionType,
+			TargetMetric: action.TargetMetric,
+			CurrentValue: action.CurrentValue,
+			TargetValue:  action.TargetValue,
+			Reason:       action.Reason,
+		}
+		switch action.Priority {
+		case "IMMEDIATE":
+			rec.ImmediateActions = append(rec.ImmediateActions, a)
+		case "HIGH":
+			rec.HighPriorityActions = append(rec.HighPriorityActions, a)
 		}
-		recommendation += "\n"
 	}
 
-	// Add predictive insights
-	if len(diag.PredictiveInsights) > 0 {
-		recommendation += "**PREDICTIVE INSIGHTS:**\n"
-		for _, insight := range diag.PredictiveInsights {
-			recommendation += fmt.Sprintf("• %s\n", insight)
+	if len(diag.RootCause.ContributingIssues) > 0 {
+		rec.RootCause = &report.RootCause{
+			PrimaryIssue:        diag.RootCause.PrimaryIssue,
+			ContributingFactors: diag.RootCause.ContributingIssues,
 		}
-		recommendation += "\n"
 	}
 
-	// Add health assessment
-	recommendation += "**HEALTH ASSESSMENT:**\n"
-	recommendation += fmt.Sprintf("- Overall Health: %.0f/100 ", diag.HealthScore)
-	if diag.HealthScore < 30 {
-		recommendation += "(CRITICAL)\n"
-	} else if diag.HealthScore < 50 {
-		recommendation += "(POOR)\n"
-	} else if diag.HealthScore < 70 {
-		recommendation += "(FAIR)\n"
-	} else if diag.HealthScore < 90 {
-		recommendation += "(GOOD)\n"
-	} else {
-		recommendation += "(EXCELLENT)\n"
-	}
-	recommendation += fmt.Sprintf("- System Stress: %.0f/100\n", diag.SystemStress)
-	recommendation += fmt.Sprintf("- Stability Index: %.1f/10\n", diag.StabilityIndex)
-	recommendation += fmt.Sprintf("- Predictability: %.0f/100\n", diag.PredictabilityScore)
-	recommendation += "\n"
+	return rec
+}
 
-	// Add contextual recommendations based on issue type
-	recommendation += "**NEXT STEPS:**\n"
+// nextSteps suggests follow-up work for the primary issue
+func nextSteps(diag *UltimateDiagnosis) []string {
 	switch diag.PrimaryDetection.Type {
 	case DetectionResourceExhaustion:
-		recommendation += "1. Execute scaling actions immediately\n"
-		recommendation += "2. Monitor resource utilization post-scaling\n"
-		recommendation += "3. Investigate root cause of resource spike\n"
-		recommendation += "4. Consider implementing auto-scaling policies\n"
+		return []string{
+			"Execute scaling actions immediately",
+			"Monitor resource utilization post-scaling",
+			"Investigate root cause of resource spike",
+			"Consider implementing auto-scaling policies",
+		}
 	case DetectionMemoryLeak:
-		recommendation += "1. Execute rolling restart to reclaim memory\n"
-		recommendation += "2. Capture heap dump for analysis\n"
-		recommendation += "3. Review recent code changes for memory allocation patterns\n"
-		recommendation += "4. Implement memory profiling in staging environment\n"
+		return []string{
+			"Execute rolling restart to reclaim memory",
+			"Capture heap dump for analysis",
+			"Review recent code changes for memory allocation patterns",
+			"Implement memory profiling in staging environment",
+		}
 	case DetectionDeploymentBug:
-		recommendation += "1. Execute rollback immediately\n"
-		recommendation += "2. Verify error rate reduction post-rollback\n"
-		recommendation += "3. Analyze error logs to identify bug\n"
-		recommendation += "4. Fix and test in staging before redeployment\n"
+		return []string{
+			"Execute rollback immediately",
+			"Verify error rate reduction post-rollback",
+			"Analyze error logs to identify bug",
+			"Fix and test in staging before redeployment",
+		}
 	case DetectionCascadingFailure:
-		recommendation += "1. Enable circuit breaker to prevent cascade\n"
-		recommendation += "2. Scale up affected services\n"
-		recommendation += "3. Identify and isolate root cause service\n"
-		recommendation += "4. Implement bulkhead pattern for isolation\n"
+		return []string{
+			"Enable circuit breaker to prevent cascade",
+			"Scale up affected services",
+			"Identify and isolate root cause service",
+			"Implement bulkhead pattern for isolation",
+		}
 	case DetectionExternalFailure:
-		recommendation += "1. Enable fallback/cache mechanisms\n"
-		recommendation += "2. Implement retry with exponential backoff\n"
-		recommendation += "3. Contact external service provider\n"
-		recommendation += "4. Review SLA and failover strategies\n"
-	default:
-		if diag.HealthScore < 80 {
-			recommendation += "1. Continue monitoring key metrics\n"
-			recommendation += "2. Review recent changes and deployments\n"
-			recommendation += "3. Verify alert thresholds are appropriate\n"
-		} else {
-			recommendation += "1. Maintain current monitoring\n"
-			recommendation += "2. No immediate action required\n"
+		return []string{
+			"Enable fallback/cache mechanisms",
+			"Implement retry with exponential backoff",
+			"Contact external service provider",
+			"Review SLA and failover strategies",
 		}
 	}
 
-	recommendation += "\n"
-	recommendation += fmt.Sprintf("**Diagnosis ID:** %s\n", diag.PredictionID)
-	recommendation += fmt.Sprintf("**Generated:** %s\n", diag.Timestamp.Format(time.RFC3339))
-
-	return recommendation
+	if diag.HealthScore < 80 {
+		return []string{
+			"Continue monitoring key metrics",
+			"Review recent changes and deployments",
+			"Verify alert thresholds are appropriate",
+		}
+	}
+	return []string{
+		"Maintain current monitoring",
+		"No immediate action required",
+	}
 }
 
 // analyzeRootCause performs deep root cause analysis with evidence
@@ -496,6 +614,10 @@ func (ua *UltimateAnalyzer) analyzeRootCause(diag *UltimateDiagnosis) *RootCause
 	return rca
 }
 
+// unrelatedIssues is determineIssueRelationship's answer for a pair it knows
+// no relationship between
+const unrelatedIssues = "may be related"
+
 // determineIssueRelationship explains how two issues relate to each other
 func (ua *UltimateAnalyzer) determineIssueRelationship(primary, secondary DetectionType) string {
 	relationships := map[string]map[DetectionType]string{
@@ -531,18 +653,28 @@ func (ua *UltimateAnalyzer) determineIssueRelationship(primary, secondary Detect
 		}
 	}
 
-	return "may be related"
+	return unrelatedIssues
 }
 
 // calculateTimeToImpact provides detailed time-to-impact analysis
 func (ua *UltimateAnalyzer) calculateTimeToImpact(diag *UltimateDiagnosis, features *ServiceFeatures) string {
 	// Already critical
-	if diag.RiskLevel == "CRITICAL" && diag.HealthScore < 30 {
+	if diag.RiskLevel == RiskCritical && diag.HealthScore < ua.risk.Thresholds().CriticalHealth {
 		return "⚠️ IMMEDIATE - Service already in critical state, action required NOW"
 	}
 
-	// Memory exhaustion prediction
-	if features.MemoryTrend > 0.5 {
+	// Memory exhaustion prediction, against the container limit when the
+	// memory leak detector could project one
+	if oom := oomProjectionOf(diag); oom != nil && oom.Basis == OOMBasisMemoryLimit {
+		minutesToOOM := oom.MinutesToOOM
+		if minutesToOOM < 5 {
+			return fmt.Sprintf("⚠️ IMMEDIATE - OOM at %.0fMi memory limit in %.0f minutes", oom.LimitMB, minutesToOOM)
+		} else if minutesToOOM < 15 {
+			return fmt.Sprintf("🔴 CRITICAL - OOM at %.0fMi memory limit in %.0f minutes", oom.LimitMB, minutesToOOM)
+		} else if minutesToOOM < 60 {
+			return fmt.Sprintf("🟠 HIGH - OOM at %.0fMi memory limit in %.0f minutes", oom.LimitMB, minutesToOOM)
+		}
+	} else if features.MemoryTrend > 0.5 {
 		minutesToFull := (100 - features.MemoryMean) / features.MemoryTrend
 		if minutesToFull > 0 && minutesToFull < 5 {
 			return fmt.Sprintf("⚠️ IMMEDIATE - Memory exhaustion in %.0f minutes", minutesToFull)
@@ -752,7 +884,7 @@ func (ua *UltimateAnalyzer) generateActuatorActions(diag *UltimateDiagnosis) []*
 
 	case DetectionMemoryLeak:
 		// Immediate mitigation
-		actions = append(actions, &ActuatorAction{
+		restart := &ActuatorAction{
 			ActionType:   "RESTART",
 			Priority:     priority,
 			TargetMetric: "pods",
@@ -769,7 +901,14 @@ func (ua *UltimateAnalyzer) generateActuatorActions(diag *UltimateDiagnosis) []*
 				"grace_period":     "30s",
 				"restart_interval": "2m",
 			},
-		})
+		}
+		if oom := oomProjectionOf(diag); oom != nil {
+			restart.Reason = fmt.Sprintf("Memory leak detected (%s) - rolling restart to reclaim memory", oom.Describe())
+			restart.Parameters["estimated_oom"] = oom.EstimatedOOM.Format(time.RFC3339)
+			restart.Parameters["minutes_to_oom"] = oom.MinutesToOOM
+			restart.Parameters["oom_basis"] = oom.Basis
+		}
+		actions = append(actions, restart)
 
 		// Long-term fix
 		actions = append(actions, &ActuatorAction{
@@ -984,6 +1123,11 @@ func (ua *UltimateAnalyzer) FeatureExtractor() *FeatureExtractor {
 	return ua.featureExtractor
 }
 
+// PatternMatcher returns the pattern matcher
+func (ua *UltimateAnalyzer) PatternMatcher() *PatternMatcher {
+	return ua.patternMatcher
+}
+
 // EnhancedDetector returns the enhanced detector
 func (ua *UltimateAnalyzer) EnhancedDetector() *EnhancedDetector {
 	return ua.enhancedDetector
@@ -994,7 +1138,7 @@ func (ua *UltimateAnalyzer) EnhancedDetector() *EnhancedDetector {
 // ================================================================================
 
 // generateEnhancedData creates comprehensive enhanced diagnostic data
-func (ua *UltimateAnalyzer) generateEnhancedData(diag *UltimateDiagnosis) *EnhancedDiagnosticData {
+func (ua *UltimateAnalyzer) generateEnhancedData(ctx context.Context, diag *UltimateDiagnosis) *EnhancedDiagnosticData {
 	enhanced := &EnhancedDiagnosticData{}
 
 	// 1. Executive Summary
@@ -1010,7 +1154,7 @@ func (ua *UltimateAnalyzer) generateEnhancedData(diag *UltimateDiagnosis) *Enhan
 	enhanced.EnhancedActions = ua.buildEnhancedActions(diag)
 
 	// 5. Health Intelligence
-	enhanced.HealthIntelligence = ua.buildHealthIntelligence(diag)
+	enhanced.HealthIntelligence = ua.buildHealthIntelligence(ctx, diag)
 
 	// 6. SLA Compliance
 	enhanced.SLACompliance = ua.buildSLACompliance(diag)
@@ -1468,22 +1612,36 @@ func (ua *UltimateAnalyzer) buildEnhancedActions(diag *UltimateDiagnosis) []*Enh
 	return enhanced
 }
 
-// buildHealthIntelligence creates health intelligence
-func (ua *UltimateAnalyzer) buildHealthIntelligence(diag *UltimateDiagnosis) *HealthIntelligence {
-	// Calculate health history
+// buildHealthIntelligence creates health intelligence. History, trend and
+// degradation rate come from the recorded health scores; the trend is the
+// time-weighted slope HealthTrend uses, over its default window.
+func (ua *UltimateAnalyzer) buildHealthIntelligence(ctx context.Context, diag *UltimateDiagnosis) *HealthIntelligence {
+	now := diag.Timestamp
+	points, err := ua.db.GetHealthHistory(ctx, diag.ServiceName, healthIntelligenceLookback)
+	if err != nil {
+		logger.FromContext(ctx).Debug("Health history unavailable", zap.String("service", diag.ServiceName), zap.Error(err))
+	}
+
 	history := &HealthHistory{
-		Last5Minutes:  diag.HealthScore + 5, // Estimate
-		Last15Minutes: diag.HealthScore + 10,
-		Last30Minutes: diag.HealthScore + 15,
-		Last1Hour:     diag.HealthScore + 20,
+		Last5Minutes:  healthAsOf(points, now.Add(-5*time.Minute)),
+		Last15Minutes: healthAsOf(points, now.Add(-15*time.Minute)),
+		Last30Minutes: healthAsOf(points, now.Add(-30*time.Minute)),
+		Last1Hour:     healthAsOf(points, now.Add(-time.Hour)),
 	}
 
-	// Determine trend
-	trend := "STABLE"
-	degradationRate := 0.0
-	if diag.HealthScore < 70 {
-		trend = "DEGRADING"
-		degradationRate = -0.5 // points per minute
+	var recent []storage.HealthPoint
+	for _, p := range points {
+		if now.Sub(p.Timestamp) <= DefaultHealthTrendWindow {
+			recent = append(recent, p)
+		}
+	}
+	recent = append(recent, storage.HealthPoint{Timestamp: now, HealthScore: diag.HealthScore})
+
+	trend := strings.ToUpper(TrajectoryUnknown)
+	degradationRate := 0.0 // health points per minute; negative while degrading
+	if len(recent) >= minHealthTrendSamples {
+		degradationRate = weightedHealthSlope(recent, now, DefaultHealthTrendWindow/3)
+		trend = strings.ToUpper(classifyTrajectory(degradationRate))
 	}
 
 	return &HealthIntelligence{
@@ -1520,6 +1678,17 @@ func (ua *UltimateAnalyzer) calculateAnomalyScore(diag *UltimateDiagnosis) float
 	return math.Min(score, 100)
 }
 
+// availabilityPercent estimates availability from the mean error rate
+func availabilityPercent(errorRateMean float64) float64 {
+	return 100.0 - (errorRateMean / 10.0)
+}
+
+// errorBudgetBurnRate is the unavailability over the budget the 99.9%
+// availability target allows
+func errorBudgetBurnRate(availPct float64) float64 {
+	return math.Max(100-availPct, 0) / (100 - 99.9)
+}
+
 // buildSLACompliance creates SLA compliance data
 func (ua *UltimateAnalyzer) buildSLACompliance(diag *UltimateDiagnosis) *SLACompliance {
 	features := diag.Features
@@ -1528,7 +1697,7 @@ func (ua *UltimateAnalyzer) buildSLACompliance(diag *UltimateDiagnosis) *SLAComp
 	}
 
 	// Availability SLA
-	availPct := 100.0 - (features.ErrorRateMean / 10.0)
+	availPct := availabilityPercent(features.ErrorRateMean)
 	availStatus := "GOOD"
 	if availPct < 99.0 {
 		availStatus = "CRITICAL"
@@ -1544,6 +1713,7 @@ func (ua *UltimateAnalyzer) buildSLACompliance(diag *UltimateDiagnosis) *SLAComp
 		Margin:  availPct - 99.9,
 		Trend:   "STABLE",
 	}
+	compliance.ErrorBudgetBurnRate = errorBudgetBurnRate(availPct)
 
 	// Error rate SLA
 	errorStatus := "GOOD"
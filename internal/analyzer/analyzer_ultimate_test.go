@@ -0,0 +1,183 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+func TestRunDetectorsSurvivesFailingDetectors(t *testing.T) {
+	cfg := &core.Config{}
+	cfg.Analyzer.PerDetectorTimeout = "50ms"
+	cfg.ApplyDefaults()
+	ua := NewUltimateAnalyzer(nil, core.NewConfigStore("", cfg))
+
+	detected := func(ctx context.Context, serviceName string) (*Detection, error) {
+		return &Detection{Type: DetectionMemoryLeak, ServiceName: serviceName, Detected: true, Confidence: 80, Severity: SeverityHigh}, nil
+	}
+	detectors := []namedDetector{
+		{"memory_leak", DetectionMemoryLeak, detected},
+		{"resource_exhaustion", DetectionResourceExhaustion, func(context.Context, string) (*Detection, error) {
+			panic("index out of range")
+		}},
+		{"deployment_bug", DetectionDeploymentBug, func(context.Context, string) (*Detection, error) {
+			return nil, errors.New("query failed")
+		}},
+		{"external_failure", DetectionExternalFailure, func(ctx context.Context, _ string) (*Detection, error) {
+			<-ctx.Done()
+			time.Sleep(10 * time.Millisecond) // ignores its context for a while
+			return nil, ctx.Err()
+		}},
+	}
+
+	detections := ua.runDetectors(context.Background(), "checkout", detectors)
+	if len(detections) != len(detectors) {
+		t.Fatalf("got %d detections, want one per detector", len(detections))
+	}
+
+	want := []struct {
+		typ    DetectionType
+		status string
+		err    string
+	}{
+		{DetectionMemoryLeak, DetectionStatusOK, ""},
+		{DetectionResourceExhaustion, DetectionStatusError, "panicked"},
+		{DetectionDeploymentBug, DetectionStatusError, "query failed"},
+		{DetectionExternalFailure, DetectionStatusTimeout, "deadline exceeded"},
+	}
+	for i, w := range want {
+		d := detections[i]
+		if d.Type != w.typ || d.Status != w.status {
+			t.Errorf("detections[%d] = %s/%s, want %s/%s", i, d.Type, d.Status, w.typ, w.status)
+		}
+		if !strings.Contains(d.Error, w.err) {
+			t.Errorf("detections[%d].Error = %q, want it to mention %q", i, d.Error, w.err)
+		}
+		if w.status != DetectionStatusOK && d.Detected {
+			t.Errorf("detections[%d] from a failed detector is marked detected", i)
+		}
+	}
+	if !detections[0].Detected {
+		t.Error("the healthy detector's detection was lost")
+	}
+}
+
+func TestRunDetectorsSlowDetector(t *testing.T) {
+	cfg := &core.Config{}
+	cfg.Analyzer.PerDetectorTimeout = "100ms"
+	cfg.ApplyDefaults()
+	ua := NewUltimateAnalyzer(nil, core.NewConfigStore("", cfg))
+	if got := ua.perDetectorTimeout(); got != 100*time.Millisecond {
+		t.Fatalf("perDetectorTimeout = %v, want the configured 100ms", got)
+	}
+
+	finished := func(typ DetectionType, confidence float64) namedDetector {
+		return namedDetector{string(typ), typ, func(_ context.Context, serviceName string) (*Detection, error) {
+			return &Detection{Type: typ, ServiceName: serviceName, Detected: true, Confidence: confidence, Severity: SeverityMedium}, nil
+		}}
+	}
+	detectors := []namedDetector{
+		finished(DetectionMemoryLeak, 60),
+		{"cascade_failure", DetectionCascadingFailure, func(ctx context.Context, _ string) (*Detection, error) {
+			select {
+			case <-time.After(5 * time.Second):
+				return &Detection{Type: DetectionCascadingFailure, Detected: true, Confidence: 99}, nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}},
+		finished(DetectionDeploymentBug, 75),
+	}
+
+	start := time.Now()
+	detections := ua.runDetectors(context.Background(), "checkout", detectors)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("runDetectors took %v; the slow detector should have been cut off at its timeout", elapsed)
+	}
+
+	if d := detections[1]; d.Status != DetectionStatusTimeout || d.Detected {
+		t.Errorf("slow detector = %s detected=%v, want a %s that isn't detected", d.Status, d.Detected, DetectionStatusTimeout)
+	}
+	primary := primaryOf(detections)
+	if primary == nil || primary.Type != DetectionDeploymentBug {
+		t.Errorf("primary detection = %v, want %s from the detectors that finished", primary, DetectionDeploymentBug)
+	}
+}
+
+func TestPrimaryOfNothingDetected(t *testing.T) {
+	detections := []*Detection{
+		{Type: DetectionMemoryLeak, Confidence: 90},
+		{Type: DetectionCascadingFailure, Status: DetectionStatusTimeout},
+	}
+	if primary := primaryOf(detections); primary != nil {
+		t.Errorf("primaryOf = %s, want nil when nothing is detected", primary.Type)
+	}
+}
+
+func TestRecordCarriesDetectorVersion(t *testing.T) {
+	d := &UltimateDiagnosis{
+		ServiceName:      "checkout",
+		Timestamp:        testEpoch,
+		PredictionID:     "prediction-1",
+		DetectorVersion:  DetectorVersion,
+		PrimaryDetection: &Detection{Type: DetectionMemoryLeak, Detected: true, Confidence: 80, Severity: SeverityHigh},
+	}
+
+	record := d.Record()
+	if record.DetectorVersion != DetectorVersion {
+		t.Errorf("record.DetectorVersion = %q, want %q", record.DetectorVersion, DetectorVersion)
+	}
+	if record.PredictionID != d.PredictionID || record.PrimaryProblem != string(DetectionMemoryLeak) {
+		t.Errorf("record = %s/%s, want %s/%s", record.PredictionID, record.PrimaryProblem, d.PredictionID, DetectionMemoryLeak)
+	}
+}
+
+func TestBuildRecommendation(t *testing.T) {
+	ua := NewUltimateAnalyzer(nil, nil)
+	diag := &UltimateDiagnosis{
+		ServiceName:      "checkout",
+		PredictionID:     "pred-1",
+		Timestamp:        testEpoch,
+		RiskLevel:        RiskHigh,
+		HealthScore:      45,
+		PrimaryDetection: &Detection{Type: DetectionMemoryLeak, Detected: true, Confidence: 82},
+		RootCause: &RootCauseAnalysis{
+			PrimaryIssue:       "Heap growth",
+			ContributingIssues: []string{"GC pauses rising"},
+			TimeToImpact:       "~20 minutes",
+			AffectedMetrics:    []string{MetricMemory},
+		},
+		ActuatorActions: []*ActuatorAction{
+			{ActionType: "RESTART", Priority: "IMMEDIATE", Reason: "reclaim memory"},
+			{ActionType: "SCALE_UP", Priority: "HIGH", Reason: "spread the load"},
+			{ActionType: "MONITOR", Priority: "LOW", Reason: "watch the heap"},
+		},
+	}
+
+	rec := ua.buildRecommendation(diag)
+	if rec.Problem != string(DetectionMemoryLeak) || rec.Confidence != 82 || rec.DiagnosisID != "pred-1" {
+		t.Errorf("recommendation = %+v, want the primary detection and diagnosis id", rec)
+	}
+	if len(rec.ImmediateActions) != 1 || rec.ImmediateActions[0].Type != "RESTART" ||
+		len(rec.HighPriorityActions) != 1 || rec.HighPriorityActions[0].Type != "SCALE_UP" {
+		t.Errorf("actions = %v / %v, want RESTART immediate and SCALE_UP high, LOW left out", rec.ImmediateActions, rec.HighPriorityActions)
+	}
+	if rec.TimeToImpact != "~20 minutes" || rec.RootCause == nil || rec.RootCause.PrimaryIssue != "Heap growth" {
+		t.Errorf("time to impact %q, root cause %+v", rec.TimeToImpact, rec.RootCause)
+	}
+	if rec.Health.Rating != "POOR" || len(rec.NextSteps) == 0 {
+		t.Errorf("health %+v with %d next steps", rec.Health, len(rec.NextSteps))
+	}
+
+	// Time to impact is only called out at high and critical risk
+	diag.RiskLevel = RiskMedium
+	diag.RootCause.ContributingIssues = nil
+	rec = ua.buildRecommendation(diag)
+	if rec.TimeToImpact != "" || rec.RootCause != nil {
+		t.Errorf("medium risk: time to impact %q, root cause %+v, want neither", rec.TimeToImpact, rec.RootCause)
+	}
+}
@@ -0,0 +1,257 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/notify"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultAnomalyInterval  = time.Minute
+	defaultAnomalyWindow    = 30 * time.Minute
+	defaultAnomalyThreshold = 3.5
+
+	// minAnomalyBaseline is the fewest baseline samples a score is trusted from
+	minAnomalyBaseline = 10
+)
+
+// Anomaly is one sample the scan found far outside its baseline
+type Anomaly struct {
+	ServiceName string    `json:"service_name"`
+	Metric      string    `json:"metric"` // canonical name
+	Value       float64   `json:"value"`
+	Baseline    float64   `json:"baseline"` // mean (zscore) or median (mad) of the window
+	Score       float64   `json:"score"`
+	Method      string    `json:"method"`
+	Timestamp   time.Time `json:"timestamp"` // of the anomalous sample
+}
+
+// AnomalyScanner periodically scores the newest sample of each configured
+// metric of every active service against the samples before it. Unlike the
+// pattern detectors it needs no particular combination of signals: any
+// single outlier is recorded as an Anomaly event.
+type AnomalyScanner struct {
+	db       *storage.PostgresClient
+	resolver *MetricResolver
+	notifier notify.Notifier
+	config   *core.ConfigStore
+
+	mu      sync.Mutex
+	flagged map[string]time.Time // service/metric -> newest sample already recorded
+}
+
+func NewAnomalyScanner(db *storage.PostgresClient, notifier notify.Notifier, config *core.ConfigStore) *AnomalyScanner {
+	return &AnomalyScanner{
+		db:       db,
+		resolver: NewMetricResolver(db, config),
+		notifier: notifier,
+		config:   config,
+		flagged:  make(map[string]time.Time),
+	}
+}
+
+// interval is how often Run scans
+func (s *AnomalyScanner) interval() time.Duration {
+	if cfg := s.config.Get(); cfg != nil {
+		if d, err := time.ParseDuration(cfg.Anomaly.Interval); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultAnomalyInterval
+}
+
+// Run scans until the context is cancelled. anomaly.enabled and the interval
+// are re-read before every scan so a config reload takes effect.
+func (s *AnomalyScanner) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ticker.Reset(s.interval())
+		}
+
+		if cfg := s.config.Get(); cfg == nil || !cfg.Anomaly.Enabled {
+			continue
+		}
+		if _, err := s.Scan(ctx); err != nil && ctx.Err() == nil {
+			logger.FromContext(ctx).Warn("Anomaly scan failed", zap.Error(err))
+		}
+	}
+}
+
+// Scan scores every service active within the window once and records the
+// anomalies found. A sample is only reported the first time it is scanned.
+func (s *AnomalyScanner) Scan(ctx context.Context) ([]Anomaly, error) {
+	cfg := s.config.Get()
+	window := defaultAnomalyWindow
+	method := core.AnomalyZScore
+	threshold := defaultAnomalyThreshold
+	metrics := []string{MetricCPU, MetricMemory, MetricErrors, MetricLatency}
+	if cfg != nil {
+		if d, err := time.ParseDuration(cfg.Anomaly.Window); err == nil && d > 0 {
+			window = d
+		}
+		if cfg.Anomaly.Method != "" {
+			method = cfg.Anomaly.Method
+		}
+		if cfg.Anomaly.Threshold > 0 {
+			threshold = cfg.Anomaly.Threshold
+		}
+		if len(cfg.Anomaly.Metrics) > 0 {
+			metrics = cfg.Anomaly.Metrics
+		}
+	}
+
+	services, err := s.db.GetActiveServicesFromMetrics(ctx, window)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []Anomaly
+	for _, service := range services {
+		series, _, err := s.resolver.ResolveSeriesMulti(ctx, service, metrics, window)
+		if err != nil {
+			logger.FromContext(ctx).Warn("Anomaly scan skipped service", zap.String("service", service), zap.Error(err))
+			continue
+		}
+
+		for _, metric := range metrics {
+			anomaly, ok := scoreNewest(series[metric], method, threshold)
+			if !ok || !s.markFlagged(service, metric, anomaly.Timestamp) {
+				continue
+			}
+			anomaly.ServiceName = service
+			anomaly.Metric = metric
+
+			s.record(ctx, &anomaly, threshold, cfg != nil && cfg.Anomaly.Notify)
+			found = append(found, anomaly)
+		}
+	}
+	return found, nil
+}
+
+// markFlagged reports whether the sample at ts is newer than the last one
+// recorded for the service's metric, and remembers it if so
+func (s *AnomalyScanner) markFlagged(service, metric string, ts time.Time) bool {
+	key := service + "/" + metric
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !ts.After(s.flagged[key]) {
+		return false
+	}
+	s.flagged[key] = ts
+	return true
+}
+
+// scoreNewest scores the last sample of a series against the ones before it
+// and returns it as an anomaly when the score exceeds threshold
+func scoreNewest(series []*storage.Metric, method string, threshold float64) (Anomaly, bool) {
+	if len(series) < minAnomalyBaseline+1 {
+		return Anomaly{}, false
+	}
+
+	newest := series[len(series)-1]
+	baseline := extractMetricValues(series[:len(series)-1])
+	center, score := outlierScore(baseline, newest.MetricValue, method)
+	if score <= threshold {
+		return Anomaly{}, false
+	}
+
+	return Anomaly{
+		Value:     newest.MetricValue,
+		Baseline:  center,
+		Score:     score,
+		Method:    method,
+		Timestamp: newest.Timestamp,
+	}, true
+}
+
+// outlierScore returns the baseline's center and how far value lies from it.
+// A baseline with no spread scores 0: with nothing to compare against, any
+// change would otherwise be infinitely anomalous.
+func outlierScore(baseline []float64, value float64, method string) (center, score float64) {
+	if method == core.AnomalyMAD {
+		median := CalculatePercentile(baseline, 50, core.PercentileInterpolated)
+		deviations := make([]float64, len(baseline))
+		for i, v := range baseline {
+			deviations[i] = math.Abs(v - median)
+		}
+		mad := CalculatePercentile(deviations, 50, core.PercentileInterpolated)
+		if mad == 0 {
+			return median, 0
+		}
+		// 0.6745 scales the MAD to a standard deviation for normal data
+		return median, 0.6745 * math.Abs(value-median) / mad
+	}
+
+	mean := CalculateMean(baseline)
+	stdDev := CalculateStdDev(baseline)
+	if stdDev == 0 {
+		return mean, 0
+	}
+	return mean, math.Abs(value-mean) / stdDev
+}
+
+// record stores the anomaly as an event and, with notify set, alerts on it
+func (s *AnomalyScanner) record(ctx context.Context, a *Anomaly, threshold float64, notifyOn bool) {
+	message := fmt.Sprintf("%s %.2f is %.1f (%s) from its baseline %.2f",
+		a.Metric, a.Value, a.Score, a.Method, a.Baseline)
+
+	logger.FromContext(ctx).Info("📈 Metric anomaly",
+		zap.String("service", a.ServiceName),
+		zap.String("metric", a.Metric),
+		zap.Float64("value", a.Value),
+		zap.Float64("score", a.Score),
+	)
+
+	event := &storage.Event{
+		Timestamp: a.Timestamp,
+		EventType: storage.EventAnomaly,
+		PodName:   a.ServiceName,
+		Message:   message,
+	}
+	if err := s.db.SaveEvent(ctx, event); err != nil {
+		logger.FromContext(ctx).Warn("Failed to record anomaly event", zap.String("service", a.ServiceName), zap.Error(err))
+	}
+
+	if !notifyOn || s.notifier == nil {
+		return
+	}
+
+	// Twice the threshold and beyond is worth a closer look
+	severity := SeverityLow
+	if a.Score > 2*threshold {
+		severity = SeverityMedium
+	}
+	err := s.notifier.Notify(ctx, notify.Notification{
+		Service:  a.ServiceName,
+		Severity: severity,
+		Title:    fmt.Sprintf("%s anomaly on %s", a.Metric, a.ServiceName),
+		Message:  message,
+		Details: map[string]interface{}{
+			"metric":   a.Metric,
+			"value":    a.Value,
+			"baseline": a.Baseline,
+			"score":    a.Score,
+			"method":   a.Method,
+		},
+		Timestamp: a.Timestamp,
+	})
+	if err != nil && !errors.Is(err, notify.ErrSuppressed) {
+		logger.FromContext(ctx).Warn("Anomaly notification failed", zap.String("service", a.ServiceName), zap.Error(err))
+	}
+}
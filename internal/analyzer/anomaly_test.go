@@ -0,0 +1,148 @@
+package analyzer
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+)
+
+// noisyBaseline alternates around 50 with a standard deviation of 1
+func noisyBaseline(n int) []float64 {
+	return generate(n, func(i int) float64 { return 50 + float64(i%2*2-1) })
+}
+
+func TestScoreNewest(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		method string
+		flag   bool
+	}{
+		{"zscore outlier", append(noisyBaseline(20), 60), core.AnomalyZScore, true},
+		{"mad outlier", append(noisyBaseline(20), 60), core.AnomalyMAD, true},
+		{"within the noise", append(noisyBaseline(20), 52), core.AnomalyZScore, false},
+		{"flat baseline", append(generate(20, func(int) float64 { return 50 }), 90), core.AnomalyZScore, false},
+		{"short baseline", append(noisyBaseline(minAnomalyBaseline-1), 90), core.AnomalyZScore, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series := seriesOf(time.Minute, tt.values...)
+			a, ok := scoreNewest(series, tt.method, defaultAnomalyThreshold)
+			if ok != tt.flag {
+				t.Fatalf("flagged = %v, want %v (%+v)", ok, tt.flag, a)
+			}
+			if ok && (a.Value != tt.values[len(tt.values)-1] || !a.Timestamp.Equal(series[len(series)-1].Timestamp) || a.Method != tt.method) {
+				t.Errorf("anomaly = %+v, want the newest sample scored by %s", a, tt.method)
+			}
+		})
+	}
+}
+
+func TestOutlierScoreMethods(t *testing.T) {
+	baseline := noisyBaseline(20)
+
+	center, score := outlierScore(baseline, 60, core.AnomalyZScore)
+	if center != 50 || math.Abs(score-10/CalculateStdDev(baseline)) > 1e-9 {
+		t.Errorf("zscore = %.2f around %.2f, want %.2f around 50", score, center, 10/CalculateStdDev(baseline))
+	}
+
+	// The median of 49s and 51s is 50 and every deviation is 1
+	center, score = outlierScore(baseline, 60, core.AnomalyMAD)
+	if center != 50 || math.Abs(score-6.745) > 1e-9 {
+		t.Errorf("mad score = %.3f around %.2f, want 6.745 around 50", score, center)
+	}
+
+	// A few earlier outliers inflate the standard deviation, not the MAD
+	spiky := append(noisyBaseline(20), 90, 95, 10)
+	_, z := outlierScore(spiky, 70, core.AnomalyZScore)
+	_, mad := outlierScore(spiky, 70, core.AnomalyMAD)
+	if z > defaultAnomalyThreshold || mad <= defaultAnomalyThreshold {
+		t.Errorf("with earlier outliers zscore = %.2f and mad = %.2f, want only mad above %.1f", z, mad, defaultAnomalyThreshold)
+	}
+}
+
+func TestAnomalyScannerFlagsEachSampleOnce(t *testing.T) {
+	s := NewAnomalyScanner(nil, nil, core.NewConfigStore("", &core.Config{}))
+
+	if !s.markFlagged("checkout", MetricCPU, testEpoch) {
+		t.Error("first sample not flagged")
+	}
+	if s.markFlagged("checkout", MetricCPU, testEpoch) {
+		t.Error("same sample flagged twice")
+	}
+	if !s.markFlagged("checkout", MetricMemory, testEpoch) {
+		t.Error("another metric's sample not flagged")
+	}
+	if !s.markFlagged("checkout", MetricCPU, testEpoch.Add(time.Minute)) {
+		t.Error("newer sample not flagged")
+	}
+}
+
+func TestAnomalyScanRecordsEvent(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+
+	end := time.Now().Add(-30 * time.Second)
+	storagetest.Seed(t, db, storagetest.Series(service, MetricMemory, end, 30*time.Second, append(noisyBaseline(30), 80)...))
+
+	cfg := &core.Config{}
+	cfg.Anomaly.Metrics = []string{MetricMemory}
+	cfg.Anomaly.Notify = true
+	cfg.ApplyDefaults()
+	notifier := &recordingNotifier{}
+	s := NewAnomalyScanner(db, notifier, core.NewConfigStore("", cfg))
+
+	ownAnomalies := func(found []Anomaly) []Anomaly {
+		var own []Anomaly
+		for _, a := range found {
+			if a.ServiceName == service {
+				own = append(own, a)
+			}
+		}
+		return own
+	}
+
+	found, err := s.Scan(ctx)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	own := ownAnomalies(found)
+	if len(own) != 1 || own[0].Metric != MetricMemory || own[0].Value != 80 {
+		t.Fatalf("anomalies = %+v, want memory at 80", own)
+	}
+
+	events, err := db.GetEvents(ctx, storage.EventFilter{
+		PodName: service, EventType: storage.EventAnomaly, Since: end.Add(-time.Hour), Limit: 10,
+	})
+	if err != nil {
+		t.Fatalf("GetEvents: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("recorded %d anomaly events, want 1", len(events))
+	}
+
+	sent := 0
+	for _, n := range notifier.notifications() {
+		if n.Service == service {
+			sent++
+		}
+	}
+	if sent != 1 {
+		t.Errorf("sent %d notifications, want 1", sent)
+	}
+
+	// Scanning again finds nothing new
+	found, err = s.Scan(ctx)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if own := ownAnomalies(found); len(own) != 0 {
+		t.Errorf("rescan reported %+v again", own)
+	}
+}
@@ -0,0 +1,254 @@
+// Package backtest measures detector precision and recall against labeled
+// scenarios, such as the ones the sample app can be switched into.
+package backtest
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/analyzer"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+// MaxCases bounds a single run so one request can't monopolize the analyzer
+const MaxCases = 50
+
+//go:embed scenarios.json
+var scenariosJSON []byte
+
+// scenarioLabels maps sample-app scenario names to the detection they should produce
+var scenarioLabels = mustLoadScenarios()
+
+func mustLoadScenarios() map[string]analyzer.DetectionType {
+	labels := make(map[string]analyzer.DetectionType)
+	if err := json.Unmarshal(scenariosJSON, &labels); err != nil {
+		panic(fmt.Sprintf("backtest: invalid embedded scenarios.json: %v", err))
+	}
+	return labels
+}
+
+// Scenarios returns the embedded sample-app scenario labels
+func Scenarios() map[string]analyzer.DetectionType {
+	out := make(map[string]analyzer.DetectionType, len(scenarioLabels))
+	for k, v := range scenarioLabels {
+		out[k] = v
+	}
+	return out
+}
+
+// Case is one labeled window. Either ExpectedType or a sample-app Scenario
+// name must be set. When At is set the window ending at At is replayed.
+type Case struct {
+	Service      string     `json:"service"`
+	ExpectedType string     `json:"expected_type,omitempty"`
+	Scenario     string     `json:"scenario,omitempty"`
+	Window       string     `json:"window,omitempty"`
+	At           *time.Time `json:"at,omitempty"`
+}
+
+// CaseResult is the outcome of a single case
+type CaseResult struct {
+	Case       Case                   `json:"case"`
+	Expected   analyzer.DetectionType `json:"expected"`
+	Predicted  analyzer.DetectionType `json:"predicted"`
+	Confidence float64                `json:"confidence"`
+	Correct    bool                   `json:"correct"`
+	Error      string                 `json:"error,omitempty"`
+
+	// RawConfidences holds every completed detector's uncalibrated
+	// confidence, the input to DeriveCalibration
+	RawConfidences map[analyzer.DetectionType]float64 `json:"raw_confidences,omitempty"`
+}
+
+// TypeMetrics is precision/recall/F1 for one detection type
+type TypeMetrics struct {
+	TruePositives  int     `json:"true_positives"`
+	FalsePositives int     `json:"false_positives"`
+	FalseNegatives int     `json:"false_negatives"`
+	Precision      float64 `json:"precision"`
+	Recall         float64 `json:"recall"`
+	F1             float64 `json:"f1"`
+}
+
+// Report summarizes a backtest run
+type Report struct {
+	Cases    []CaseResult                            `json:"cases"`
+	PerType  map[analyzer.DetectionType]*TypeMetrics `json:"per_type"`
+	Accuracy float64                                 `json:"accuracy"`
+	MacroF1  float64                                 `json:"macro_f1"`
+	Errors   int                                     `json:"errors"`
+	Duration time.Duration                           `json:"duration"`
+	RunAt    time.Time                               `json:"run_at"`
+
+	DetectorVersion string `json:"detector_version"`
+
+	// SuggestedCalibration is a calibration.detectors table derived from the
+	// run's raw confidences, for types with enough cases
+	SuggestedCalibration map[string]core.CalibrationTable `json:"suggested_calibration,omitempty"`
+}
+
+// Runner executes cases against the ultimate analyzer
+type Runner struct {
+	ua *analyzer.UltimateAnalyzer
+}
+
+func NewRunner(ua *analyzer.UltimateAnalyzer) *Runner {
+	return &Runner{ua: ua}
+}
+
+// Validate resolves labels and windows, returning the first invalid case
+func Validate(cases []Case) error {
+	if len(cases) == 0 {
+		return fmt.Errorf("at least one case is required")
+	}
+	if len(cases) > MaxCases {
+		return fmt.Errorf("at most %d cases can be run at once", MaxCases)
+	}
+	for i, c := range cases {
+		if c.Service == "" {
+			return fmt.Errorf("cases[%d].service is required", i)
+		}
+		if _, err := expectedType(c); err != nil {
+			return fmt.Errorf("cases[%d]: %w", i, err)
+		}
+		if c.Window != "" {
+			if d, err := time.ParseDuration(c.Window); err != nil || d <= 0 {
+				return fmt.Errorf("cases[%d].window is not a valid duration", i)
+			}
+		}
+	}
+	return nil
+}
+
+func expectedType(c Case) (analyzer.DetectionType, error) {
+	if c.ExpectedType != "" {
+		return analyzer.DetectionType(c.ExpectedType), nil
+	}
+	if t, ok := scenarioLabels[c.Scenario]; ok {
+		return t, nil
+	}
+	if c.Scenario != "" {
+		return "", fmt.Errorf("unknown scenario %q", c.Scenario)
+	}
+	return "", fmt.Errorf("expected_type or scenario is required")
+}
+
+// Run diagnoses every case sequentially and scores the predictions. Cases
+// that fail to run are reported but excluded from the scores.
+func (r *Runner) Run(ctx context.Context, cases []Case) (*Report, error) {
+	if err := Validate(cases); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	report := &Report{
+		Cases:   make([]CaseResult, 0, len(cases)),
+		PerType: make(map[analyzer.DetectionType]*TypeMetrics),
+		RunAt:   start,
+
+		DetectorVersion: analyzer.DetectorVersion,
+	}
+
+	for _, c := range cases {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		report.Cases = append(report.Cases, r.runCase(ctx, c))
+	}
+
+	score(report)
+	report.SuggestedCalibration = DeriveCalibration(report.Cases)
+	report.Duration = time.Since(start)
+	return report, nil
+}
+
+func (r *Runner) runCase(ctx context.Context, c Case) CaseResult {
+	expected, _ := expectedType(c) // checked by Validate
+	result := CaseResult{Case: c, Expected: expected}
+
+	window, _ := time.ParseDuration(c.Window)
+
+	var diag *analyzer.UltimateDiagnosis
+	var err error
+	if c.At != nil {
+		diag, err = r.ua.AnalyzeAtTime(ctx, c.Service, *c.At, window)
+	} else {
+		if window > 0 {
+			ctx = analyzer.WithAnalysisWindow(ctx, window)
+		}
+		diag, err = r.ua.DiagnoseService(ctx, c.Service)
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Predicted = diag.PrimaryDetection.Type
+	result.Confidence = diag.PrimaryDetection.Confidence
+	result.Correct = result.Predicted == expected
+	for _, d := range diag.AllDetections {
+		if d.Status != analyzer.DetectionStatusOK {
+			continue
+		}
+		if result.RawConfidences == nil {
+			result.RawConfidences = make(map[analyzer.DetectionType]float64)
+		}
+		result.RawConfidences[d.Type] = d.RawConfidence
+	}
+	return result
+}
+
+func score(report *Report) {
+	metricsFor := func(t analyzer.DetectionType) *TypeMetrics {
+		m, ok := report.PerType[t]
+		if !ok {
+			m = &TypeMetrics{}
+			report.PerType[t] = m
+		}
+		return m
+	}
+
+	scored, correct := 0, 0
+	for _, cr := range report.Cases {
+		if cr.Error != "" {
+			report.Errors++
+			continue
+		}
+		scored++
+		if cr.Correct {
+			correct++
+			metricsFor(cr.Expected).TruePositives++
+			continue
+		}
+		metricsFor(cr.Expected).FalseNegatives++
+		metricsFor(cr.Predicted).FalsePositives++
+	}
+
+	if scored > 0 {
+		report.Accuracy = float64(correct) / float64(scored)
+	}
+
+	types := make([]string, 0, len(report.PerType))
+	for t := range report.PerType {
+		types = append(types, string(t))
+	}
+	sort.Strings(types)
+
+	f1Sum := 0.0
+	for _, t := range types {
+		m := report.PerType[analyzer.DetectionType(t)]
+		if tp := float64(m.TruePositives); tp > 0 {
+			m.Precision = tp / (tp + float64(m.FalsePositives))
+			m.Recall = tp / (tp + float64(m.FalseNegatives))
+			m.F1 = 2 * m.Precision * m.Recall / (m.Precision + m.Recall)
+		}
+		f1Sum += m.F1
+	}
+	if len(types) > 0 {
+		report.MacroF1 = f1Sum / float64(len(types))
+	}
+}
@@ -0,0 +1,121 @@
+package backtest
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/analyzer"
+)
+
+func TestScenariosAreKnownTypes(t *testing.T) {
+	known := map[analyzer.DetectionType]bool{
+		analyzer.DetectionMemoryLeak:         true,
+		analyzer.DetectionDeploymentBug:      true,
+		analyzer.DetectionCascadingFailure:   true,
+		analyzer.DetectionExternalFailure:    true,
+		analyzer.DetectionResourceExhaustion: true,
+		analyzer.DetectionHealthy:            true,
+	}
+	scenarios := Scenarios()
+	if len(scenarios) == 0 {
+		t.Fatal("no embedded scenarios")
+	}
+	for name, typ := range scenarios {
+		if !known[typ] {
+			t.Errorf("scenario %q is labeled %q, not a detection type", name, typ)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tooMany := make([]Case, MaxCases+1)
+	for i := range tooMany {
+		tooMany[i] = Case{Service: "checkout", Scenario: "normal"}
+	}
+
+	tests := []struct {
+		name    string
+		cases   []Case
+		wantErr string
+	}{
+		{"valid scenario", []Case{{Service: "checkout", Scenario: "memory-leak", Window: "15m"}}, ""},
+		{"valid expected type", []Case{{Service: "checkout", ExpectedType: "MEMORY_LEAK"}}, ""},
+		{"no cases", nil, "at least one case"},
+		{"too many cases", tooMany, "at most"},
+		{"missing service", []Case{{Scenario: "normal"}}, "cases[0].service"},
+		{"unknown scenario", []Case{{Service: "checkout", Scenario: "meteor-strike"}}, "unknown scenario"},
+		{"no label", []Case{{Service: "checkout"}}, "expected_type or scenario"},
+		{"bad window", []Case{{Service: "checkout", Scenario: "normal", Window: "soon"}}, "cases[0].window"},
+		{"negative window", []Case{{Service: "checkout", Scenario: "normal", Window: "-5m"}}, "cases[0].window"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.cases)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestScore scores the recorded run in testdata/run.json: five scored
+// cases, three of them correct, and one that failed to run.
+func TestScore(t *testing.T) {
+	data, err := os.ReadFile("testdata/run.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := &Report{PerType: make(map[analyzer.DetectionType]*TypeMetrics)}
+	if err := json.Unmarshal(data, &report.Cases); err != nil {
+		t.Fatalf("invalid testdata/run.json: %v", err)
+	}
+
+	score(report)
+
+	if report.Errors != 1 {
+		t.Errorf("errors = %d, want 1", report.Errors)
+	}
+	assertClose(t, "accuracy", report.Accuracy, 0.6)
+
+	want := map[analyzer.DetectionType]TypeMetrics{
+		analyzer.DetectionMemoryLeak:         {TruePositives: 1, FalsePositives: 1, FalseNegatives: 1, Precision: 0.5, Recall: 0.5, F1: 0.5},
+		analyzer.DetectionHealthy:            {TruePositives: 1, FalsePositives: 1, Precision: 0.5, Recall: 1, F1: 2.0 / 3},
+		analyzer.DetectionResourceExhaustion: {FalseNegatives: 1},
+		analyzer.DetectionCascadingFailure:   {TruePositives: 1, Precision: 1, Recall: 1, F1: 1},
+	}
+	if len(report.PerType) != len(want) {
+		t.Errorf("scored %d types, want %d: errored cases must not be scored", len(report.PerType), len(want))
+	}
+	for typ, w := range want {
+		got, ok := report.PerType[typ]
+		if !ok {
+			t.Errorf("%s: not scored", typ)
+			continue
+		}
+		if got.TruePositives != w.TruePositives || got.FalsePositives != w.FalsePositives || got.FalseNegatives != w.FalseNegatives {
+			t.Errorf("%s: tp/fp/fn = %d/%d/%d, want %d/%d/%d", typ,
+				got.TruePositives, got.FalsePositives, got.FalseNegatives,
+				w.TruePositives, w.FalsePositives, w.FalseNegatives)
+		}
+		assertClose(t, string(typ)+" precision", got.Precision, w.Precision)
+		assertClose(t, string(typ)+" recall", got.Recall, w.Recall)
+		assertClose(t, string(typ)+" F1", got.F1, w.F1)
+	}
+	assertClose(t, "macro F1", report.MacroF1, (0.5+2.0/3+0+1)/4)
+}
+
+func assertClose(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("%s = %v, want %v", name, got, want)
+	}
+}
@@ -0,0 +1,112 @@
+package backtest
+
+import (
+	"math"
+	"sort"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/analyzer"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+// calibrationBinWidth groups raw confidences into bins of this many points
+const calibrationBinWidth = 10.0
+
+// minCalibrationSamples is the fewest scored detections a type needs before
+// a table is suggested for it
+const minCalibrationSamples = 5
+
+type calibrationBin struct {
+	rawSum  float64
+	hits    float64
+	samples float64
+}
+
+// DeriveCalibration suggests a calibration table per detection type from a
+// run's raw detector confidences. Each detector's confidences are binned,
+// the share of cases in each bin whose expected type matched the detector
+// becomes the calibrated confidence, and the bins are pooled until that
+// share never falls as raw confidence rises (isotonic regression), so the
+// result can be pasted into config.calibration.detectors.
+func DeriveCalibration(cases []CaseResult) map[string]core.CalibrationTable {
+	bins := make(map[analyzer.DetectionType]map[int]*calibrationBin)
+	for _, cr := range cases {
+		if cr.Error != "" {
+			continue
+		}
+		for typ, raw := range cr.RawConfidences {
+			if bins[typ] == nil {
+				bins[typ] = make(map[int]*calibrationBin)
+			}
+			idx := int(math.Min(raw, 99.999) / calibrationBinWidth)
+			b := bins[typ][idx]
+			if b == nil {
+				b = &calibrationBin{}
+				bins[typ][idx] = b
+			}
+			b.rawSum += raw
+			b.samples++
+			if cr.Expected == typ {
+				b.hits++
+			}
+		}
+	}
+
+	tables := make(map[string]core.CalibrationTable)
+	for typ, byIndex := range bins {
+		indexes := make([]int, 0, len(byIndex))
+		total := 0.0
+		for idx, b := range byIndex {
+			indexes = append(indexes, idx)
+			total += b.samples
+		}
+		if total < minCalibrationSamples {
+			continue
+		}
+		sort.Ints(indexes)
+
+		ordered := make([]calibrationBin, 0, len(indexes))
+		for _, idx := range indexes {
+			ordered = append(ordered, *byIndex[idx])
+		}
+		tables[string(typ)] = core.CalibrationTable{Points: isotonicPoints(ordered)}
+	}
+	return tables
+}
+
+// isotonicPoints runs pool-adjacent-violators over bins ordered by raw
+// confidence and returns one point per bin at its mean raw confidence
+func isotonicPoints(bins []calibrationBin) []core.CalibrationPoint {
+	type block struct {
+		hits, samples float64
+		size          int
+	}
+	rate := func(b block) float64 { return b.hits / b.samples }
+
+	var blocks []block
+	for _, b := range bins {
+		blocks = append(blocks, block{hits: b.hits, samples: b.samples, size: 1})
+		for n := len(blocks); n > 1 && rate(blocks[n-2]) > rate(blocks[n-1]); n = len(blocks) {
+			last := blocks[n-1]
+			blocks = blocks[:n-1]
+			blocks[n-2].hits += last.hits
+			blocks[n-2].samples += last.samples
+			blocks[n-2].size += last.size
+		}
+	}
+
+	points := make([]core.CalibrationPoint, 0, len(bins))
+	i := 0
+	for _, blk := range blocks {
+		calibrated := math.Round(rate(blk)*1000) / 10
+		for j := 0; j < blk.size; j++ {
+			b := bins[i]
+			i++
+			raw := math.Round(b.rawSum/b.samples*10) / 10
+			if n := len(points); n > 0 && raw <= points[n-1].Raw {
+				continue // rounding merged it with the previous bin
+			}
+			points = append(points, core.CalibrationPoint{Raw: raw, Calibrated: calibrated})
+		}
+	}
+	return points
+}
@@ -0,0 +1,73 @@
+package backtest
+
+import (
+	"testing"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/analyzer"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+// leakCase is a case whose memory leak detector reported raw
+func leakCase(expected analyzer.DetectionType, raw float64) CaseResult {
+	return CaseResult{
+		Expected:       expected,
+		RawConfidences: map[analyzer.DetectionType]float64{analyzer.DetectionMemoryLeak: raw},
+	}
+}
+
+func TestDeriveCalibration(t *testing.T) {
+	leak, healthy := analyzer.DetectionMemoryLeak, analyzer.DetectionHealthy
+	cases := []CaseResult{
+		// 20-30: half are leaks
+		leakCase(leak, 24), leakCase(healthy, 26),
+		// 50-60: none are, which would make the curve fall, so it is pooled
+		// with the bin below
+		leakCase(healthy, 54), leakCase(healthy, 56),
+		// 80-90: all are
+		leakCase(leak, 84), leakCase(leak, 86),
+		// Errored cases are ignored
+		{Expected: leak, Error: "no data", RawConfidences: map[analyzer.DetectionType]float64{leak: 10}},
+		// Too few samples for a table
+		{Expected: healthy, RawConfidences: map[analyzer.DetectionType]float64{analyzer.DetectionDeploymentBug: 70}},
+	}
+
+	tables := DeriveCalibration(cases)
+	if _, ok := tables[string(analyzer.DetectionDeploymentBug)]; ok {
+		t.Error("suggested a table for a type with too few samples")
+	}
+
+	got := tables[string(leak)].Points
+	want := []core.CalibrationPoint{{Raw: 25, Calibrated: 25}, {Raw: 55, Calibrated: 25}, {Raw: 85, Calibrated: 100}}
+	if len(got) != len(want) {
+		t.Fatalf("points = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("points[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestIsotonicPointsNonDecreasing(t *testing.T) {
+	bins := []calibrationBin{
+		{rawSum: 15, hits: 1, samples: 1},
+		{rawSum: 35, hits: 0, samples: 1},
+		{rawSum: 110, hits: 1, samples: 2},
+		{rawSum: 75, hits: 0, samples: 1},
+		{rawSum: 95, hits: 1, samples: 1},
+	}
+	points := isotonicPoints(bins)
+	if len(points) != len(bins) {
+		t.Fatalf("got %d points, want one per bin", len(points))
+	}
+	for i := 1; i < len(points); i++ {
+		if points[i].Raw <= points[i-1].Raw || points[i].Calibrated < points[i-1].Calibrated {
+			t.Errorf("points %v are not monotone at %d", points, i)
+		}
+	}
+	for _, p := range points {
+		if p.Calibrated < 0 || p.Calibrated > 100 {
+			t.Errorf("point %v is outside 0-100", p)
+		}
+	}
+}
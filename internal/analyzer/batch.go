@@ -0,0 +1,49 @@
+package analyzer
+
+import (
+	"context"
+	"sync"
+)
+
+// MaxBatchServices caps how many services one batch analysis may request
+const MaxBatchServices = 100
+
+// BatchDiagnosis is the outcome of diagnosing an explicit list of services
+type BatchDiagnosis struct {
+	Diagnoses map[string]*UltimateDiagnosis `json:"diagnoses"`
+	Failed    map[string]string             `json:"failed,omitempty"` // service -> error
+}
+
+// DiagnoseServices diagnoses each service with at most maxConcurrentAnalyses
+// in flight. A failing service is reported in Failed instead of failing the
+// batch.
+func (ua *UltimateAnalyzer) DiagnoseServices(ctx context.Context, services []string) *BatchDiagnosis {
+	batch := &BatchDiagnosis{
+		Diagnoses: make(map[string]*UltimateDiagnosis, len(services)),
+		Failed:    make(map[string]string),
+	}
+	var mu sync.Mutex
+
+	ua.forEachService(ctx, services, func(ctx context.Context, _ int, serviceName string) {
+		diag, err := ua.DiagnoseService(ctx, serviceName)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			batch.Failed[serviceName] = err.Error()
+			return
+		}
+		batch.Diagnoses[serviceName] = diag
+	})
+
+	// forEachService stops starting services once the context ends
+	if err := ctx.Err(); err != nil {
+		for _, serviceName := range services {
+			_, done := batch.Diagnoses[serviceName]
+			if _, failed := batch.Failed[serviceName]; !done && !failed {
+				batch.Failed[serviceName] = err.Error()
+			}
+		}
+	}
+	return batch
+}
@@ -0,0 +1,30 @@
+package analyzer
+
+// calibrate maps an ok detection's raw confidence through the configured
+// table for its type, so confidences from different detectors mean the same
+// thing to the risk thresholds. A calibrated detection takes its severity
+// from those thresholds, and one that falls below calibration.min_confidence
+// is cleared. Without configuration the detection is left as reported.
+func (ua *UltimateAnalyzer) calibrate(d *Detection) {
+	d.RawConfidence = d.Confidence
+	cfg := ua.cfg()
+	if cfg == nil {
+		return
+	}
+
+	if table, ok := cfg.Calibration.Detectors[string(d.Type)]; ok {
+		d.Confidence = table.Apply(d.Confidence)
+		if d.Detected {
+			d.Severity = ua.risk.SeverityForConfidence(d.Confidence)
+		}
+	}
+
+	if min := cfg.Calibration.MinConfidence; d.Detected && min > 0 && d.Confidence < min {
+		d.Detected = false
+		d.Severity = SeverityNone
+		if d.Evidence == nil {
+			d.Evidence = map[string]interface{}{}
+		}
+		d.Evidence["below_min_confidence"] = min
+	}
+}
@@ -0,0 +1,101 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+func newCalibratedAnalyzer(minConfidence float64, tables map[string]core.CalibrationTable) *UltimateAnalyzer {
+	cfg := &core.Config{}
+	cfg.Calibration.Detectors = tables
+	cfg.Calibration.MinConfidence = minConfidence
+	cfg.ApplyDefaults()
+	return NewUltimateAnalyzer(nil, core.NewConfigStore("", cfg))
+}
+
+func TestCalibrate(t *testing.T) {
+	ua := newCalibratedAnalyzer(40, map[string]core.CalibrationTable{
+		string(DetectionMemoryLeak): {Multiplier: 0.5},
+		string(DetectionCascadingFailure): {Points: []core.CalibrationPoint{
+			{Raw: 40, Calibrated: 30},
+			{Raw: 80, Calibrated: 90},
+		}},
+	})
+
+	tests := []struct {
+		name         string
+		detection    Detection
+		wantConf     float64
+		wantSeverity string
+		wantDetected bool
+	}{
+		{
+			name:         "multiplier",
+			detection:    Detection{Type: DetectionMemoryLeak, Detected: true, Confidence: 90, Severity: SeverityCritical},
+			wantConf:     45,
+			wantSeverity: SeverityLow,
+			wantDetected: true,
+		},
+		{
+			name:         "points interpolate and regrade severity",
+			detection:    Detection{Type: DetectionCascadingFailure, Detected: true, Confidence: 70, Severity: SeverityMedium},
+			wantConf:     75,
+			wantSeverity: SeverityHigh,
+			wantDetected: true,
+		},
+		{
+			name:         "below min confidence is cleared",
+			detection:    Detection{Type: DetectionMemoryLeak, Detected: true, Confidence: 60, Severity: SeverityMedium},
+			wantConf:     30,
+			wantSeverity: SeverityNone,
+			wantDetected: false,
+		},
+		{
+			name:         "type without a table passes through",
+			detection:    Detection{Type: DetectionDeploymentBug, Detected: true, Confidence: 72, Severity: SeverityMedium},
+			wantConf:     72,
+			wantSeverity: SeverityMedium,
+			wantDetected: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := tt.detection
+			ua.calibrate(&d)
+			if d.RawConfidence != tt.detection.Confidence {
+				t.Errorf("RawConfidence = %v, want the detector's %v", d.RawConfidence, tt.detection.Confidence)
+			}
+			if d.Confidence != tt.wantConf || d.Severity != tt.wantSeverity || d.Detected != tt.wantDetected {
+				t.Errorf("calibrated = %v %s detected=%v, want %v %s detected=%v",
+					d.Confidence, d.Severity, d.Detected, tt.wantConf, tt.wantSeverity, tt.wantDetected)
+			}
+		})
+	}
+}
+
+func TestCalibrateWithoutConfigIsIdentity(t *testing.T) {
+	ua := newCalibratedAnalyzer(0, nil)
+	d := &Detection{Type: DetectionMemoryLeak, Detected: true, Confidence: 63, Severity: SeverityMedium}
+	ua.calibrate(d)
+	if d.Confidence != 63 || d.RawConfidence != 63 || d.Severity != SeverityMedium || !d.Detected {
+		t.Errorf("detection = %+v, want it unchanged", d)
+	}
+}
+
+func TestRunDetectorsCalibrates(t *testing.T) {
+	ua := newCalibratedAnalyzer(0, map[string]core.CalibrationTable{
+		string(DetectionMemoryLeak): {Multiplier: 0.5},
+	})
+	detectors := []namedDetector{
+		{"memory_leak", DetectionMemoryLeak, func(_ context.Context, serviceName string) (*Detection, error) {
+			return &Detection{Type: DetectionMemoryLeak, ServiceName: serviceName, Detected: true, Confidence: 80, Severity: SeverityHigh}, nil
+		}},
+	}
+
+	d := ua.runDetectors(context.Background(), "checkout", detectors)[0]
+	if d.Confidence != 40 || d.RawConfidence != 80 {
+		t.Errorf("confidence = %v (raw %v), want 40 calibrated from 80", d.Confidence, d.RawConfidence)
+	}
+}
@@ -0,0 +1,197 @@
+package analyzer
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultCascadeCandidates = 5
+	defaultCascadeCacheTTL   = 2 * time.Minute
+)
+
+// CascadeLink is the error-rate correlation between a service and one of its
+// cascade candidates
+type CascadeLink struct {
+	Service     string            `json:"service"`
+	Declared    bool              `json:"declared"` // from cascade.dependencies rather than ranking
+	Correlation CorrelationResult `json:"correlation"`
+}
+
+type cascadePair struct {
+	a, b   string
+	window time.Duration
+}
+
+type cascadeCacheEntry struct {
+	result  CorrelationResult
+	expires time.Time
+}
+
+// CascadeCorrelator correlates a service's errors with a bounded candidate set
+// instead of every service in the fleet, caching pair results briefly since
+// the same pairs come up across back-to-back analyses
+type CascadeCorrelator struct {
+	db       *storage.PostgresClient
+	resolver *MetricResolver
+	config   *core.ConfigStore
+
+	mu    sync.Mutex
+	cache map[cascadePair]cascadeCacheEntry
+}
+
+func NewCascadeCorrelator(db *storage.PostgresClient, resolver *MetricResolver, config *core.ConfigStore) *CascadeCorrelator {
+	return &CascadeCorrelator{
+		db:       db,
+		resolver: resolver,
+		config:   config,
+		cache:    make(map[cascadePair]cascadeCacheEntry),
+	}
+}
+
+func (cc *CascadeCorrelator) cfg() *core.Config {
+	return cc.config.Get()
+}
+
+func (cc *CascadeCorrelator) maxCandidates() int {
+	if cfg := cc.cfg(); cfg != nil && cfg.Cascade.MaxCandidates > 0 {
+		return cfg.Cascade.MaxCandidates
+	}
+	return defaultCascadeCandidates
+}
+
+func (cc *CascadeCorrelator) cacheTTL() time.Duration {
+	if cfg := cc.cfg(); cfg != nil {
+		if d, err := time.ParseDuration(cfg.Cascade.CacheTTL); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultCascadeCacheTTL
+}
+
+// Candidates returns the services to correlate against, capped at
+// cascade.max_candidates: declared dependencies when present, otherwise the
+// services with the highest recent error rate
+func (cc *CascadeCorrelator) Candidates(ctx context.Context, serviceName string, window time.Duration) ([]string, bool, error) {
+	limit := cc.maxCandidates()
+
+	if cfg := cc.cfg(); cfg != nil {
+		if deps := cfg.Cascade.Dependencies[serviceName]; len(deps) > 0 {
+			if len(deps) > limit {
+				deps = deps[:limit]
+			}
+			return deps, true, nil
+		}
+	}
+
+	services, err := cc.db.GetTopServicesByMetric(ctx, cc.resolver.Aliases(MetricErrors), window, limit, serviceName)
+	return services, false, err
+}
+
+// Correlate returns the error-rate correlation with each candidate, strongest
+// first. Candidates without enough overlapping samples are left out.
+func (cc *CascadeCorrelator) Correlate(ctx context.Context, serviceName string, window time.Duration) ([]CascadeLink, error) {
+	candidates, declared, err := cc.Candidates(ctx, serviceName, window)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	// Replays query historical windows, which the live cache must not answer
+	useCache := !storage.HasAsOf(ctx)
+
+	// Fetched lazily: a fully cached pass never touches the service's own series
+	var own []*storage.Metric
+	ownLoaded := false
+
+	links := make([]CascadeLink, 0, len(candidates))
+	for _, candidate := range candidates {
+		var result CorrelationResult
+		ok := false
+		if useCache {
+			result, ok = cc.cached(serviceName, candidate, window)
+		}
+		if !ok {
+			if !ownLoaded {
+				own, _ = cc.resolver.ResolveSeries(ctx, serviceName, MetricErrors, window)
+				ownLoaded = true
+			}
+			other, _ := cc.resolver.ResolveSeries(ctx, candidate, MetricErrors, window)
+			result = CorrelatePearson(own, other, cc.minSamples())
+			if useCache {
+				cc.store(serviceName, candidate, window, result)
+			}
+		}
+		if !result.Sufficient() {
+			continue
+		}
+		links = append(links, CascadeLink{Service: candidate, Declared: declared, Correlation: result})
+	}
+
+	sort.Slice(links, func(i, j int) bool {
+		return math.Abs(links[i].Correlation.Coefficient) > math.Abs(links[j].Correlation.Coefficient)
+	})
+
+	logger.FromContext(ctx).Debug("Cascade candidates correlated",
+		zap.String("service", serviceName),
+		zap.Int("candidates", len(candidates)),
+		zap.Int("links", len(links)),
+		zap.Bool("declared", declared),
+	)
+	return links, nil
+}
+
+func (cc *CascadeCorrelator) minSamples() int {
+	if cfg := cc.cfg(); cfg != nil && cfg.Analyzer.MinCorrelationSamples > 0 {
+		return cfg.Analyzer.MinCorrelationSamples
+	}
+	return DefaultMinCorrelationSamples
+}
+
+// pairKey orders the names so A-B and B-A share a cache entry
+func pairKey(a, b string, window time.Duration) cascadePair {
+	if b < a {
+		a, b = b, a
+	}
+	return cascadePair{a: a, b: b, window: window}
+}
+
+func (cc *CascadeCorrelator) cached(a, b string, window time.Duration) (CorrelationResult, bool) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	key := pairKey(a, b, window)
+	entry, ok := cc.cache[key]
+	if !ok {
+		return CorrelationResult{}, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(cc.cache, key)
+		return CorrelationResult{}, false
+	}
+	return entry.result, true
+}
+
+func (cc *CascadeCorrelator) store(a, b string, window time.Duration, result CorrelationResult) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	now := time.Now()
+	// Sweep expired pairs so the cache stays proportional to active pairs
+	for key, entry := range cc.cache {
+		if now.After(entry.expires) {
+			delete(cc.cache, key)
+		}
+	}
+	cc.cache[pairKey(a, b, window)] = cascadeCacheEntry{result: result, expires: now.Add(cc.cacheTTL())}
+}
@@ -0,0 +1,116 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+)
+
+func newTestCorrelator(db *storage.PostgresClient, cfg *core.Config) *CascadeCorrelator {
+	cfg.ApplyDefaults()
+	store := core.NewConfigStore("", cfg)
+	return NewCascadeCorrelator(db, NewMetricResolver(db, store), store)
+}
+
+func TestCascadeCandidatesDeclared(t *testing.T) {
+	cfg := &core.Config{}
+	cfg.Cascade.MaxCandidates = 2
+	cfg.Cascade.Dependencies = map[string][]string{"checkout": {"payments", "inventory", "shipping"}}
+	cc := newTestCorrelator(nil, cfg)
+
+	candidates, declared, err := cc.Candidates(context.Background(), "checkout", time.Hour)
+	if err != nil {
+		t.Fatalf("Candidates: %v", err)
+	}
+	if !declared {
+		t.Error("declared = false for a service with declared dependencies")
+	}
+	if want := []string{"payments", "inventory"}; !slices.Equal(candidates, want) {
+		t.Errorf("candidates = %v, want %v capped at max_candidates", candidates, want)
+	}
+}
+
+func TestCascadeCache(t *testing.T) {
+	cc := newTestCorrelator(nil, &core.Config{})
+	result := CorrelationResult{Coefficient: 0.9, Samples: 30}
+
+	cc.store("checkout", "payments", time.Hour, result)
+	if got, ok := cc.cached("payments", "checkout", time.Hour); !ok || got != result {
+		t.Errorf("cached(payments, checkout) = %v, %v; want the pair stored the other way round", got, ok)
+	}
+	if _, ok := cc.cached("checkout", "payments", 30*time.Minute); ok {
+		t.Error("a pair cached for 1h answered a 30m window")
+	}
+
+	// Expired entries are not served and are swept on the next store
+	key := pairKey("checkout", "payments", time.Hour)
+	cc.cache[key] = cascadeCacheEntry{result: result, expires: time.Now().Add(-time.Second)}
+	if _, ok := cc.cached("checkout", "payments", time.Hour); ok {
+		t.Error("an expired pair was served")
+	}
+	cc.cache[key] = cascadeCacheEntry{result: result, expires: time.Now().Add(-time.Second)}
+	cc.store("checkout", "inventory", time.Hour, result)
+	if _, ok := cc.cache[key]; ok {
+		t.Error("store didn't sweep the expired pair")
+	}
+}
+
+// BenchmarkCascadeCorrelation compares correlating against every service in
+// the fleet with the bounded candidate set
+func BenchmarkCascadeCorrelation(b *testing.B) {
+	db := storagetest.NewClient(b)
+	ctx := context.Background()
+	const fleet = 40
+	window := 30 * time.Minute
+	end := time.Now().Add(-time.Minute).Truncate(time.Second)
+
+	services := make([]string, fleet)
+	for i := range services {
+		services[i] = storagetest.Service(b, db)
+		values := generate(60, func(j int) float64 { return 1 + math.Sin(float64(i+j)/5) })
+		storagetest.Seed(b, db, storagetest.Series(services[i], MetricErrors, end, 30*time.Second, values...))
+	}
+	target := services[0]
+
+	cc := newTestCorrelator(db, &core.Config{})
+	minSamples := cc.minSamples()
+
+	b.Run(fmt.Sprintf("full-scan-%d", fleet), func(b *testing.B) {
+		for b.Loop() {
+			own, err := db.GetRecentMetrics(ctx, target, MetricErrors, window)
+			if err != nil {
+				b.Fatal(err)
+			}
+			for _, other := range services[1:] {
+				series, err := db.GetRecentMetrics(ctx, other, MetricErrors, window)
+				if err != nil {
+					b.Fatal(err)
+				}
+				CorrelatePearson(own, series, minSamples)
+			}
+		}
+	})
+	// Replaying at now bypasses the pair cache, so every pass queries
+	b.Run(fmt.Sprintf("bounded-%d", cc.maxCandidates()), func(b *testing.B) {
+		replay := storage.WithAsOf(ctx, time.Now())
+		for b.Loop() {
+			if _, err := cc.Correlate(replay, target, window); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("bounded-cached", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := cc.Correlate(ctx, target, window); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,185 @@
+package analyzer
+
+import (
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+// DetectorInfo describes one detector as a diagnosis would run it under the
+// live configuration
+type DetectorInfo struct {
+	Name        string                 `json:"name"`
+	Type        DetectionType          `json:"type"`
+	Description string                 `json:"description"`
+	Signals     []string               `json:"signals"`
+	Enabled     bool                   `json:"enabled"`
+	Thresholds  map[string]interface{} `json:"thresholds"`
+}
+
+// detectorSpec is the static part of a catalog entry. thresholds reports the
+// detector-specific settings in effect for a service ("" for the defaults).
+type detectorSpec struct {
+	name        string
+	typ         DetectionType
+	description string
+	signals     []string
+	thresholds  func(cfg *core.Config, serviceName string) map[string]interface{}
+}
+
+var detectorCatalog = []detectorSpec{
+	{
+		name:        "memory_leak",
+		typ:         DetectionMemoryLeak,
+		description: "Steady, low-volatility memory growth that is independent of CPU, confirmed across several windows",
+		signals:     []string{"trend", "low_volatility", "level", "range", "autocorr", "independent_growth", "gc_bimodality"},
+		thresholds: func(cfg *core.Config, _ string) map[string]interface{} {
+			_, memory, _, _ := classicThresholds(cfg)
+			return map[string]interface{}{
+				"min_confidence":   memoryLeakCutoff,
+				"min_signals":      2,
+				"memory_threshold": memory,
+			}
+		},
+	},
+	{
+		name:        "resource_exhaustion",
+		typ:         DetectionResourceExhaustion,
+		description: "Sustained CPU and memory saturation, or one saturated resource for services that opt out of requiring both",
+		signals:     []string{"cpu_high", "memory_high", "errors", "stress", "both_resources_high", "single_resource_saturated"},
+		thresholds: func(cfg *core.Config, serviceName string) map[string]interface{} {
+			cpu, memory, _, _ := classicThresholds(cfg)
+			overrides := cfg.ThresholdsFor(serviceName)
+			return map[string]interface{}{
+				"min_confidence":            resourceExhaustionCutoff,
+				"min_signals":               2,
+				"cpu_threshold":             cpu,
+				"memory_threshold":          memory,
+				"require_both_resources":    overrides.BothResourcesRequired(),
+				"single_resource_threshold": overrides.SingleResourceLimit(),
+			}
+		},
+	},
+	{
+		name:        "deployment_bug",
+		typ:         DetectionDeploymentBug,
+		description: "Error spikes and instability while CPU and memory stay normal, the signature of a bad release",
+		signals:     []string{"error_spike", "error_rate", "independent_errors", "instability", "normal_resources_high_errors"},
+		thresholds: func(cfg *core.Config, serviceName string) map[string]interface{} {
+			_, _, errorRate, _ := classicThresholds(cfg)
+			return withErrorRatio(cfg, serviceName, map[string]interface{}{
+				"min_confidence":       deploymentBugCutoff,
+				"min_signals":          2,
+				"error_rate_threshold": errorRate,
+			})
+		},
+	},
+	{
+		name:        "external_failure",
+		typ:         DetectionExternalFailure,
+		description: "Latency and errors that track each other without local resource pressure, or failing declared dependencies",
+		signals:     []string{"latency", "latency_error_corr", "external_pattern", "error_spikes", "no_memory_correlation", "dependency_unhealthy"},
+		thresholds: func(cfg *core.Config, serviceName string) map[string]interface{} {
+			_, _, errorRate, latency := classicThresholds(cfg)
+			thresholds := map[string]interface{}{
+				"min_confidence":       externalFailureCutoff,
+				"error_rate_threshold": errorRate,
+				"latency_threshold":    latency,
+			}
+			if slo := cfg.ThresholdsFor(serviceName).LatencySLOMs; slo > 0 {
+				thresholds["latency_slo_ms"] = slo
+			}
+			return withErrorRatio(cfg, serviceName, thresholds)
+		},
+	},
+	{
+		name:        "cascade_failure",
+		typ:         DetectionCascadingFailure,
+		description: "Several metrics degrading together with errors that correlate with other services",
+		signals:     []string{"multi_degradation", "system_stress", "health", "trends", "instability", "correlated_services", "propagation"},
+		thresholds: func(cfg *core.Config, serviceName string) map[string]interface{} {
+			_, _, errorRate, latency := classicThresholds(cfg)
+			return withErrorRatio(cfg, serviceName, map[string]interface{}{
+				"min_confidence":       cascadeFailureCutoff,
+				"min_signals":          2,
+				"min_degraded_metrics": 3,
+				"error_rate_threshold": errorRate,
+				"latency_threshold":    latency,
+			})
+		},
+	},
+}
+
+// withErrorRatio adds the service's error ratio settings, when it has them
+func withErrorRatio(cfg *core.Config, serviceName string, thresholds map[string]interface{}) map[string]interface{} {
+	t := cfg.ThresholdsFor(serviceName)
+	if t.ErrorRatioThreshold <= 0 {
+		return thresholds
+	}
+	minRate := t.ErrorRatioMinRate
+	if minRate <= 0 {
+		minRate = defaultErrorRatioMinRate
+	}
+	thresholds["error_ratio_threshold"] = t.ErrorRatioThreshold
+	thresholds["error_ratio_min_rate"] = minRate
+	return thresholds
+}
+
+// detectorEnabled reports whether analyzer.disabled_detectors leaves the
+// named detector on
+func detectorEnabled(cfg *core.Config, name string) bool {
+	if cfg == nil {
+		return true
+	}
+	for _, disabled := range cfg.Analyzer.DisabledDetectors {
+		if disabled == name {
+			return false
+		}
+	}
+	return true
+}
+
+// typeEnabled reports whether the detector reporting typ is enabled
+func typeEnabled(cfg *core.Config, typ DetectionType) bool {
+	for _, spec := range detectorCatalog {
+		if spec.typ == typ {
+			return detectorEnabled(cfg, spec.name)
+		}
+	}
+	return true
+}
+
+// Detectors describes every detector a diagnosis can run, with its enabled
+// state and the thresholds in effect. With serviceName set, that service's
+// per-service overrides are applied. Besides each detector's own settings,
+// every entry carries its feature window, confidence dampening, the
+// calibration floor and the per-detector timeout.
+func (ua *UltimateAnalyzer) Detectors(serviceName string) []DetectorInfo {
+	cfg := ua.cfg()
+	minCalibrated := 0.0
+	if cfg != nil {
+		minCalibrated = cfg.Calibration.MinConfidence
+	}
+	timeout := ua.perDetectorTimeout().String()
+	dampening := ua.enhancedDetector.dampening()
+
+	infos := make([]DetectorInfo, 0, len(detectorCatalog))
+	for _, spec := range detectorCatalog {
+		thresholds := spec.thresholds(cfg, serviceName)
+		thresholds["calibrated_min_confidence"] = minCalibrated
+		thresholds["timeout"] = timeout
+		thresholds["window"] = windowLabel(ua.enhancedDetector.window(spec.name))
+		thresholds["weak_signal_dampening"] = dampening.For(spec.name)
+		if spec.name == "memory_leak" {
+			thresholds["unconfirmed_trend_dampening"] = dampening.UnconfirmedTrend
+		}
+
+		infos = append(infos, DetectorInfo{
+			Name:        spec.name,
+			Type:        spec.typ,
+			Description: spec.description,
+			Signals:     spec.signals,
+			Enabled:     detectorEnabled(cfg, spec.name),
+			Thresholds:  thresholds,
+		})
+	}
+	return infos
+}
@@ -0,0 +1,93 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+// detectorNamed returns the catalog entry called name
+func detectorNamed(t *testing.T, infos []DetectorInfo, name string) DetectorInfo {
+	t.Helper()
+	for _, info := range infos {
+		if info.Name == name {
+			return info
+		}
+	}
+	t.Fatalf("catalog has no %q detector", name)
+	return DetectorInfo{}
+}
+
+func TestDetectorCatalogReflectsConfig(t *testing.T) {
+	requireBoth := false
+	cfg := &core.Config{
+		Thresholds: map[string]core.ServiceThresholds{
+			"checkout": {RequireBothResources: &requireBoth, SingleResourceThreshold: 95, LatencySLOMs: 300},
+		},
+	}
+	cfg.Analyzer.CPUThreshold = 70
+	cfg.Analyzer.DisabledDetectors = []string{"cascade_failure"}
+	cfg.ApplyDefaults()
+	ua := NewUltimateAnalyzer(nil, core.NewConfigStore("", cfg))
+
+	infos := ua.Detectors("")
+	if len(infos) != len(detectorCatalog) {
+		t.Fatalf("catalog lists %d detectors, want %d", len(infos), len(detectorCatalog))
+	}
+	for _, info := range infos {
+		if want := info.Name != "cascade_failure"; info.Enabled != want {
+			t.Errorf("%s enabled = %v, want %v", info.Name, info.Enabled, want)
+		}
+		if info.Description == "" || len(info.Signals) == 0 {
+			t.Errorf("%s lacks a description or signals", info.Name)
+		}
+	}
+
+	// The configured CPU threshold replaces the default of 85
+	exhaustion := detectorNamed(t, infos, "resource_exhaustion")
+	if got := exhaustion.Thresholds["cpu_threshold"]; got != 70.0 {
+		t.Errorf("cpu_threshold = %v, want 70", got)
+	}
+	if got := exhaustion.Thresholds["require_both_resources"]; got != true {
+		t.Errorf("require_both_resources = %v, want the default true", got)
+	}
+
+	// ?service applies that service's overrides
+	checkout := ua.Detectors("checkout")
+	exhaustion = detectorNamed(t, checkout, "resource_exhaustion")
+	if got := exhaustion.Thresholds["require_both_resources"]; got != false {
+		t.Errorf("checkout require_both_resources = %v, want false", got)
+	}
+	if got := exhaustion.Thresholds["single_resource_threshold"]; got != 95.0 {
+		t.Errorf("checkout single_resource_threshold = %v, want 95", got)
+	}
+	if got := detectorNamed(t, checkout, "external_failure").Thresholds["latency_slo_ms"]; got != 300.0 {
+		t.Errorf("checkout latency_slo_ms = %v, want 300", got)
+	}
+	if _, ok := detectorNamed(t, infos, "external_failure").Thresholds["latency_slo_ms"]; ok {
+		t.Error("latency_slo_ms listed without a service SLO")
+	}
+}
+
+func TestDisabledDetectorsAreSkipped(t *testing.T) {
+	cfg := &core.Config{}
+	cfg.Analyzer.DisabledDetectors = []string{"memory_leak", "deployment_bug"}
+	cfg.ApplyDefaults()
+	store := core.NewConfigStore("", cfg)
+
+	ua := NewUltimateAnalyzer(nil, store)
+	for _, det := range ua.detectors() {
+		if det.name == "memory_leak" || det.name == "deployment_bug" {
+			t.Errorf("diagnosis runs disabled detector %s", det.name)
+		}
+	}
+	if got, want := len(ua.detectors()), len(detectorCatalog)-2; got != want {
+		t.Errorf("diagnosis runs %d detectors, want %d", got, want)
+	}
+
+	for _, d := range NewClassicDetector(store).DetectAll(&ServiceFeatures{}) {
+		if d.Type == DetectionMemoryLeak || d.Type == DetectionDeploymentBug {
+			t.Errorf("classic detectors report disabled type %s", d.Type)
+		}
+	}
+}
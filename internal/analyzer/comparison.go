@@ -0,0 +1,158 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// maxConcurrentAnalyses bounds how many services are diagnosed in parallel
+// when analyzing a fleet, so one request can't flood the database.
+const maxConcurrentAnalyses = 5
+
+// severityRank orders severities so comparisons can break health-score ties
+var severityRank = map[string]int{
+	SeverityNone:     0,
+	SeverityLow:      1,
+	SeverityMedium:   2,
+	SeverityHigh:     3,
+	SeverityCritical: 4,
+}
+
+// CompareServices diagnoses every service and returns them sorted worst-first
+func (ua *UltimateAnalyzer) CompareServices(ctx context.Context, services []string) ([]ServiceComparison, error) {
+	results := make([]*ServiceComparison, len(services))
+
+	ua.forEachService(ctx, services, func(ctx context.Context, i int, serviceName string) {
+		comparison, err := ua.compareService(ctx, serviceName)
+		if err != nil {
+			logger.FromContext(ctx).Warn("Service comparison failed",
+				zap.String("service", serviceName),
+				zap.Error(err),
+			)
+			return
+		}
+		results[i] = comparison
+	})
+
+	comparisons := make([]ServiceComparison, 0, len(services))
+	for _, r := range results {
+		if r != nil {
+			comparisons = append(comparisons, *r)
+		}
+	}
+
+	if len(services) > 0 && len(comparisons) == 0 {
+		return nil, fmt.Errorf("failed to analyze any of %d services", len(services))
+	}
+
+	sortWorstFirst(comparisons)
+	return comparisons, nil
+}
+
+// SummarizeServices is CompareServices plus the timestamp of each service's latest metric
+func (ua *UltimateAnalyzer) SummarizeServices(ctx context.Context, services []string) ([]ServiceComparison, error) {
+	comparisons, err := ua.CompareServices(ctx, services)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := ua.cfg()
+	for i := range comparisons {
+		comparisons[i].LastSeen = ua.lastSeen(ctx, comparisons[i].ServiceName)
+		comparisons[i].RemediationMode = remediationMode(cfg, comparisons[i].ServiceName)
+	}
+
+	return comparisons, nil
+}
+
+func remediationMode(cfg *core.Config, serviceName string) string {
+	if cfg.AutoRemediates(serviceName) {
+		return RemediationAuto
+	}
+	return RemediationRecommend
+}
+
+// forEachService runs fn for every service with at most maxConcurrentAnalyses in flight
+func (ua *UltimateAnalyzer) forEachService(ctx context.Context, services []string, fn func(ctx context.Context, i int, serviceName string)) {
+	sem := make(chan struct{}, maxConcurrentAnalyses)
+	var wg sync.WaitGroup
+
+	for i, serviceName := range services {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		}
+
+		wg.Add(1)
+		go func(i int, serviceName string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(ctx, i, serviceName)
+		}(i, serviceName)
+	}
+
+	wg.Wait()
+}
+
+func (ua *UltimateAnalyzer) compareService(ctx context.Context, serviceName string) (*ServiceComparison, error) {
+	diagnosis, err := ua.DiagnoseService(ctx, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	return comparisonOf(diagnosis), nil
+}
+
+// comparisonOf condenses a diagnosis into its overview row
+func comparisonOf(diagnosis *UltimateDiagnosis) *ServiceComparison {
+	issueCount := 0
+	for _, d := range diagnosis.AllDetections {
+		if d.Detected {
+			issueCount++
+		}
+	}
+
+	return &ServiceComparison{
+		ServiceName:       diagnosis.ServiceName,
+		HealthScore:       diagnosis.HealthScore,
+		PrimaryIssue:      string(diagnosis.PrimaryDetection.Type),
+		IssueCount:        issueCount,
+		Severity:          diagnosis.PrimaryDetection.Severity,
+		RequiresAttention: diagnosis.HealthScore < 80,
+	}
+}
+
+// lastSeen returns the service registry's newest sample time for the
+// service, or nil when it was never seen or the lookup fails
+func (ua *UltimateAnalyzer) lastSeen(ctx context.Context, serviceName string) *time.Time {
+	latest, err := ua.db.GetServiceLastSeen(ctx, serviceName)
+	if err != nil {
+		logger.FromContext(ctx).Warn("Failed to get service last seen",
+			zap.String("service", serviceName),
+			zap.Error(err),
+		)
+		return nil
+	}
+	if latest.IsZero() {
+		return nil
+	}
+	return &latest
+}
+
+// sortWorstFirst orders by ascending health, then by descending severity
+func sortWorstFirst(comparisons []ServiceComparison) {
+	sort.SliceStable(comparisons, func(i, j int) bool {
+		if comparisons[i].HealthScore != comparisons[j].HealthScore {
+			return comparisons[i].HealthScore < comparisons[j].HealthScore
+		}
+		return severityRank[comparisons[i].Severity] > severityRank[comparisons[j].Severity]
+	})
+}
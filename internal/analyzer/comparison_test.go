@@ -0,0 +1,84 @@
+package analyzer
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+)
+
+func TestForEachServiceBoundsConcurrency(t *testing.T) {
+	ua := NewUltimateAnalyzer(nil, nil)
+	services := make([]string, 4*maxConcurrentAnalyses)
+	for i := range services {
+		services[i] = string(rune('a' + i))
+	}
+
+	var inFlight, peak atomic.Int32
+	var mu sync.Mutex
+	seen := make(map[int]string)
+	ua.forEachService(context.Background(), services, func(_ context.Context, i int, serviceName string) {
+		n := inFlight.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		inFlight.Add(-1)
+
+		mu.Lock()
+		seen[i] = serviceName
+		mu.Unlock()
+	})
+
+	if len(seen) != len(services) {
+		t.Errorf("ran %d services, want %d", len(seen), len(services))
+	}
+	for i, name := range services {
+		if seen[i] != name {
+			t.Errorf("index %d ran %q, want %q", i, seen[i], name)
+		}
+	}
+	if p := peak.Load(); p > maxConcurrentAnalyses {
+		t.Errorf("%d ran at once, want at most %d", p, maxConcurrentAnalyses)
+	}
+}
+
+func TestDiagnoseServicesReportsFailures(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+
+	end := time.Now().Add(-time.Minute)
+	values := generate(30, func(int) float64 { return 50 })
+	storagetest.Seed(t, db, storagetest.Series(service, MetricCPU, end, 30*time.Second, values...))
+	storagetest.Seed(t, db, storagetest.Series(service, MetricMemory, end, 30*time.Second, values...))
+
+	cfg := &core.Config{}
+	cfg.ApplyDefaults()
+	ua := NewUltimateAnalyzer(db, core.NewConfigStore("", cfg))
+
+	batch := ua.DiagnoseServices(context.Background(), []string{service})
+	if d := batch.Diagnoses[service]; d == nil || d.ServiceName != service {
+		t.Errorf("diagnoses = %v, want one for %s", batch.Diagnoses, service)
+	}
+	if len(batch.Failed) != 0 {
+		t.Errorf("failed = %v, want none", batch.Failed)
+	}
+
+	// A batch whose context has ended lists every service it didn't finish
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	others := []string{service + "-a", service + "-b"}
+	batch = ua.DiagnoseServices(ctx, others)
+	for _, name := range others {
+		if _, ok := batch.Failed[name]; !ok {
+			t.Errorf("failed = %v, want %s listed", batch.Failed, name)
+		}
+	}
+}
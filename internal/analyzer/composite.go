@@ -0,0 +1,150 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultCompositeMinConfidence is used when analyzer.composite_min_confidence
+// is unset
+const defaultCompositeMinConfidence = 60
+
+// causalRank orders the problem types determineIssueRelationship relates,
+// from upstream cause to downstream effect: a bad deployment breaks
+// dependencies and spikes resources, a leak or external pressure exhausts
+// resources, and exhaustion cascades. Types of equal rank don't cause one
+// another.
+var causalRank = map[DetectionType]int{
+	DetectionDeploymentBug:      0,
+	DetectionMemoryLeak:         1,
+	DetectionExternalFailure:    1,
+	DetectionResourceExhaustion: 2,
+	DetectionCascadingFailure:   3,
+}
+
+// CompositeIssue is one problem folded into a composite diagnosis
+type CompositeIssue struct {
+	Type       DetectionType `json:"type"`
+	Confidence float64       `json:"confidence"`
+	Severity   string        `json:"severity"`
+
+	// Relationship explains how the issue follows from the root; empty on
+	// the root and on unrelated issues
+	Relationship string `json:"relationship,omitempty"`
+}
+
+// CompositeDiagnosis synthesizes the problems detected together on a service
+// into one narrative: the likely root cause, the downstream effects it
+// explains, and any concurrent problems it doesn't.
+type CompositeDiagnosis struct {
+	ServiceName  string    `json:"service_name"`
+	PredictionID string    `json:"prediction_id"`
+	Timestamp    time.Time `json:"timestamp"`
+	HealthScore  float64   `json:"health_score"`
+	RiskLevel    string    `json:"risk_level"`
+
+	// MinConfidence is the confidence a detection needed to be folded in
+	MinConfidence float64 `json:"min_confidence"`
+
+	// Root is nil when no problem reached MinConfidence
+	Root       *CompositeIssue  `json:"root"`
+	Effects    []CompositeIssue `json:"effects"`
+	Concurrent []CompositeIssue `json:"concurrent"`
+
+	Narrative      string `json:"narrative"`
+	Recommendation string `json:"recommendation,omitempty"` // the root's
+}
+
+// compositeMinConfidence returns analyzer.composite_min_confidence
+func (ua *UltimateAnalyzer) compositeMinConfidence() float64 {
+	if cfg := ua.cfg(); cfg != nil && cfg.Analyzer.CompositeMinConfidence > 0 {
+		return cfg.Analyzer.CompositeMinConfidence
+	}
+	return defaultCompositeMinConfidence
+}
+
+// Composite folds the diagnosis' detections of at least
+// analyzer.composite_min_confidence into a composite diagnosis. The most
+// upstream problem, by causalRank and then confidence, is taken as the root;
+// problems it explains are its effects and the rest are concurrent.
+func (ua *UltimateAnalyzer) Composite(diag *UltimateDiagnosis) *CompositeDiagnosis {
+	composite := &CompositeDiagnosis{
+		ServiceName:   diag.ServiceName,
+		PredictionID:  diag.PredictionID,
+		Timestamp:     diag.Timestamp,
+		HealthScore:   diag.HealthScore,
+		RiskLevel:     diag.RiskLevel,
+		MinConfidence: ua.compositeMinConfidence(),
+		Effects:       []CompositeIssue{},
+		Concurrent:    []CompositeIssue{},
+	}
+
+	var chained, unranked []*Detection
+	for _, d := range diag.AllDetections {
+		if !d.Detected || d.Confidence < composite.MinConfidence {
+			continue
+		}
+		if _, ok := causalRank[d.Type]; ok {
+			chained = append(chained, d)
+		} else {
+			unranked = append(unranked, d)
+		}
+	}
+	sortIssues := func(ds []*Detection, rank func(*Detection) int) {
+		sort.SliceStable(ds, func(i, j int) bool {
+			if ri, rj := rank(ds[i]), rank(ds[j]); ri != rj {
+				return ri < rj
+			}
+			return ds[i].Confidence > ds[j].Confidence
+		})
+	}
+	sortIssues(chained, func(d *Detection) int { return causalRank[d.Type] })
+	sortIssues(unranked, func(*Detection) int { return 0 })
+
+	candidates := append(chained, unranked...)
+	if len(candidates) == 0 {
+		composite.Narrative = fmt.Sprintf("No problems detected with at least %.0f%% confidence.", composite.MinConfidence)
+		return composite
+	}
+
+	root := candidates[0]
+	composite.Root = &CompositeIssue{Type: root.Type, Confidence: root.Confidence, Severity: root.Severity}
+	composite.Recommendation = root.Recommendation
+	for _, d := range candidates[1:] {
+		issue := CompositeIssue{Type: d.Type, Confidence: d.Confidence, Severity: d.Severity}
+		if causalRank[d.Type] > causalRank[root.Type] {
+			if rel := ua.determineIssueRelationship(root.Type, d.Type); rel != unrelatedIssues {
+				issue.Relationship = rel
+				composite.Effects = append(composite.Effects, issue)
+				continue
+			}
+		}
+		composite.Concurrent = append(composite.Concurrent, issue)
+	}
+
+	composite.Narrative = compositeNarrative(composite)
+	return composite
+}
+
+// compositeNarrative tells the root, its effects and the concurrent problems
+// as one story
+func compositeNarrative(c *CompositeDiagnosis) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Likely root cause: %s (%.1f%% confidence).", c.Root.Type, c.Root.Confidence)
+	for _, e := range c.Effects {
+		fmt.Fprintf(&b, " Downstream effect: %s (%.1f%% confidence) - %s.", e.Type, e.Confidence, e.Relationship)
+	}
+	if len(c.Concurrent) > 0 {
+		names := make([]string, 0, len(c.Concurrent))
+		for _, i := range c.Concurrent {
+			names = append(names, fmt.Sprintf("%s (%.1f%%)", i.Type, i.Confidence))
+		}
+		fmt.Fprintf(&b, " Also detected, not explained by the root: %s.", strings.Join(names, ", "))
+	}
+	if len(c.Effects) > 0 {
+		b.WriteString(" Resolving the root cause should relieve its effects.")
+	}
+	return b.String()
+}
@@ -0,0 +1,115 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+func newCompositeAnalyzer(minConfidence float64) *UltimateAnalyzer {
+	cfg := &core.Config{}
+	cfg.Analyzer.CompositeMinConfidence = minConfidence
+	cfg.ApplyDefaults()
+	return NewUltimateAnalyzer(nil, core.NewConfigStore("", cfg))
+}
+
+func compositeDiagnosis(detections ...*Detection) *UltimateDiagnosis {
+	return &UltimateDiagnosis{
+		ServiceName:   "checkout",
+		PredictionID:  "pred-1",
+		Timestamp:     testEpoch,
+		HealthScore:   35,
+		RiskLevel:     RiskHigh,
+		AllDetections: detections,
+	}
+}
+
+func detected(typ DetectionType, confidence float64) *Detection {
+	return &Detection{
+		Type:           typ,
+		Detected:       true,
+		Confidence:     confidence,
+		Severity:       SeverityHigh,
+		Recommendation: "fix " + string(typ),
+	}
+}
+
+func TestCompositeMemoryLeakChain(t *testing.T) {
+	ua := newCompositeAnalyzer(0)
+
+	// Exhaustion is the more confident detection, but the leak explains it
+	composite := ua.Composite(compositeDiagnosis(
+		detected(DetectionResourceExhaustion, 92),
+		detected(DetectionMemoryLeak, 81),
+	))
+
+	if composite.Root == nil || composite.Root.Type != DetectionMemoryLeak {
+		t.Fatalf("root = %+v, want the memory leak", composite.Root)
+	}
+	if len(composite.Effects) != 1 || composite.Effects[0].Type != DetectionResourceExhaustion {
+		t.Fatalf("effects = %+v, want resource exhaustion only", composite.Effects)
+	}
+	if rel := composite.Effects[0].Relationship; rel != "leading to resource exhaustion" {
+		t.Errorf("relationship = %q, want the leak's", rel)
+	}
+	if len(composite.Concurrent) != 0 {
+		t.Errorf("concurrent = %+v, want none", composite.Concurrent)
+	}
+	if composite.Recommendation != "fix MEMORY_LEAK" {
+		t.Errorf("recommendation = %q, want the root's", composite.Recommendation)
+	}
+
+	want := "Likely root cause: MEMORY_LEAK (81.0% confidence). " +
+		"Downstream effect: RESOURCE_EXHAUSTION (92.0% confidence) - leading to resource exhaustion. " +
+		"Resolving the root cause should relieve its effects."
+	if composite.Narrative != want {
+		t.Errorf("narrative = %q, want %q", composite.Narrative, want)
+	}
+	if composite.ServiceName != "checkout" || composite.PredictionID != "pred-1" || composite.MinConfidence != 60 {
+		t.Errorf("composite = %+v, want it to carry the diagnosis and the default min confidence", composite)
+	}
+}
+
+func TestCompositeConcurrentProblems(t *testing.T) {
+	ua := newCompositeAnalyzer(0)
+
+	// Leak and external failure are equally upstream; neither explains the other
+	composite := ua.Composite(compositeDiagnosis(
+		detected(DetectionExternalFailure, 70),
+		detected(DetectionMemoryLeak, 85),
+		detected(DetectionResourceExhaustion, 75),
+	))
+
+	if composite.Root == nil || composite.Root.Type != DetectionMemoryLeak {
+		t.Fatalf("root = %+v, want the more confident of the upstream problems", composite.Root)
+	}
+	if len(composite.Effects) != 1 || composite.Effects[0].Type != DetectionResourceExhaustion {
+		t.Errorf("effects = %+v, want resource exhaustion", composite.Effects)
+	}
+	if len(composite.Concurrent) != 1 || composite.Concurrent[0].Type != DetectionExternalFailure || composite.Concurrent[0].Relationship != "" {
+		t.Errorf("concurrent = %+v, want the external failure without a relationship", composite.Concurrent)
+	}
+	if !strings.Contains(composite.Narrative, "Also detected, not explained by the root: EXTERNAL_FAILURE (70.0%).") {
+		t.Errorf("narrative = %q, want the external failure listed as concurrent", composite.Narrative)
+	}
+}
+
+func TestCompositeMinConfidence(t *testing.T) {
+	leak := detected(DetectionMemoryLeak, 55)
+	undetected := detected(DetectionResourceExhaustion, 95)
+	undetected.Detected = false
+
+	composite := newCompositeAnalyzer(0).Composite(compositeDiagnosis(leak, undetected))
+	if composite.Root != nil || len(composite.Effects) != 0 || len(composite.Concurrent) != 0 {
+		t.Fatalf("composite = %+v, want nothing folded in below 60%% or undetected", composite)
+	}
+	if composite.Narrative != "No problems detected with at least 60% confidence." {
+		t.Errorf("narrative = %q", composite.Narrative)
+	}
+
+	composite = newCompositeAnalyzer(50).Composite(compositeDiagnosis(leak, undetected))
+	if composite.Root == nil || composite.Root.Type != DetectionMemoryLeak || composite.MinConfidence != 50 {
+		t.Errorf("with composite_min_confidence 50: composite = %+v, want the leak as root", composite)
+	}
+}
@@ -0,0 +1,69 @@
+package analyzer
+
+import (
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+)
+
+// defaultMetricTypes lists the stored metrics known to be monotonic counters.
+// The scraper stores app_errors_total as error_count and http_requests_total
+// as http_requests without applying rate().
+var defaultMetricTypes = map[string]string{
+	"error_count":         core.MetricTypeCounter,
+	"app_errors_total":    core.MetricTypeCounter,
+	"http_requests":       core.MetricTypeCounter,
+	"http_requests_total": core.MetricTypeCounter,
+}
+
+// IsCounter reports whether a stored metric is a monotonic counter. Entries
+// in config.metric_types take precedence over the built-in list.
+func (r *MetricResolver) IsCounter(name string) bool {
+	if cfg := r.config.Get(); cfg != nil {
+		if kind, ok := cfg.MetricTypes[name]; ok {
+			return kind == core.MetricTypeCounter
+		}
+	}
+	return defaultMetricTypes[name] == core.MetricTypeCounter
+}
+
+// normalize converts a resolved counter series to per-minute rates, then
+// applies the metric's configured unit conversion
+func (r *MetricResolver) normalize(serviceName, name string, metrics []*storage.Metric) []*storage.Metric {
+	if r.IsCounter(name) {
+		metrics = counterRate(metrics)
+	}
+	if t := r.TransformFor(serviceName, name); t != nil {
+		metrics = transformSeries(metrics, t)
+	}
+	return metrics
+}
+
+// counterRate differences successive samples of a counter into per-minute
+// rates, each stamped with the later sample's time, so n samples yield at
+// most n-1 rates. A decrease is read as a counter reset: the new value is
+// taken as the increase since the reset, as Prometheus' rate() does.
+// Samples sharing a timestamp are skipped.
+func counterRate(metrics []*storage.Metric) []*storage.Metric {
+	if len(metrics) < 2 {
+		return nil
+	}
+
+	rates := make([]*storage.Metric, 0, len(metrics)-1)
+	for i := 1; i < len(metrics); i++ {
+		prev, cur := metrics[i-1], metrics[i]
+		minutes := cur.Timestamp.Sub(prev.Timestamp).Minutes()
+		if minutes <= 0 {
+			continue
+		}
+
+		increase := cur.MetricValue - prev.MetricValue
+		if increase < 0 {
+			increase = cur.MetricValue
+		}
+
+		rate := *cur
+		rate.MetricValue = increase / minutes
+		rates = append(rates, &rate)
+	}
+	return rates
+}
@@ -0,0 +1,95 @@
+package analyzer
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+)
+
+func TestCounterRate(t *testing.T) {
+	tests := []struct {
+		name   string
+		series []*storage.Metric
+		want   []float64
+	}{
+		{"steady", seriesOf(30*time.Second, 0, 1, 2, 3), []float64{2, 2, 2}},
+		{"reset", seriesOf(time.Minute, 10, 15, 3, 8), []float64{5, 3, 5}},
+		{"one sample", seriesOf(time.Minute, 10), nil},
+		{"repeated timestamp", seriesOf(0, 1, 2, 3), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractMetricValues(counterRate(tt.series))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("rates = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Rates are stamped with the later sample's time
+	rates := counterRate(seriesOf(time.Minute, 0, 4))
+	if !rates[0].Timestamp.Equal(testEpoch.Add(time.Minute)) {
+		t.Errorf("rate stamped %v, want %v", rates[0].Timestamp, testEpoch.Add(time.Minute))
+	}
+}
+
+func TestMetricResolverIsCounter(t *testing.T) {
+	builtIn := newTestResolver(nil)
+	configured := newTestResolver(&core.Config{MetricTypes: map[string]string{
+		"error_count":    core.MetricTypeGauge,
+		"requests_total": core.MetricTypeCounter,
+	}})
+
+	tests := []struct {
+		name     string
+		resolver *MetricResolver
+		metric   string
+		want     bool
+	}{
+		{"built-in counter", builtIn, "error_count", true},
+		{"built-in gauge", builtIn, "cpu_usage", false},
+		{"configured gauge overrides built-in", configured, "error_count", false},
+		{"configured counter", configured, "requests_total", true},
+		{"other built-ins kept", configured, "app_errors_total", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.resolver.IsCounter(tt.metric); got != tt.want {
+				t.Errorf("IsCounter(%q) = %v, want %v", tt.metric, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCounterVersusGaugeHandling(t *testing.T) {
+	// An error counter growing 3 errors/min over 30 minutes
+	cumulative := generate(31, func(i int) float64 { return 1000 + 3*float64(i) })
+	series := seriesOf(time.Minute, cumulative...)
+
+	asCounter := newTestResolver(&core.Config{MetricTypes: map[string]string{"errors": core.MetricTypeCounter}})
+	asGauge := newTestResolver(&core.Config{MetricTypes: map[string]string{"errors": core.MetricTypeGauge}})
+
+	rates := extractMetricValues(asCounter.normalize("checkout", "errors", series))
+	if len(rates) != len(series)-1 {
+		t.Fatalf("counter gave %d samples, want %d", len(rates), len(series)-1)
+	}
+	if mean := CalculateMean(rates); math.Abs(mean-3) > 1e-9 {
+		t.Errorf("counter mean = %.3f errors/min, want 3", mean)
+	}
+	if sd := CalculateStdDev(rates); sd > 1e-9 {
+		t.Errorf("counter stddev = %.3f, want 0 for a steady rate", sd)
+	}
+
+	// Read as a gauge the same data looks like a huge, steadily rising error level
+	raw := extractMetricValues(asGauge.normalize("checkout", "errors", series))
+	if len(raw) != len(series) {
+		t.Fatalf("gauge gave %d samples, want %d unchanged", len(raw), len(series))
+	}
+	if mean := CalculateMean(raw); math.Abs(mean-1045) > 1e-9 {
+		t.Errorf("gauge mean = %.3f, want the raw 1045", mean)
+	}
+}
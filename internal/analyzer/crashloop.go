@@ -0,0 +1,156 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/eventbus"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/notify"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// maxCrashLoopHistory bounds how many crash-triggered diagnoses are kept in memory
+const maxCrashLoopHistory = 50
+
+// CrashLoopDiagnosis is a diagnosis triggered by a CrashLoop event
+type CrashLoopDiagnosis struct {
+	Event        eventbus.Event `json:"event"`
+	PredictionID string         `json:"prediction_id,omitempty"`
+	Problem      DetectionType  `json:"problem,omitempty"`
+	Severity     string         `json:"severity,omitempty"`
+	Confidence   float64        `json:"confidence"`
+	HealthScore  float64        `json:"health_score"`
+	RiskLevel    string         `json:"risk_level,omitempty"`
+	Notified     bool           `json:"notified"`
+	Suppressed   bool           `json:"suppressed"` // notification held back by a maintenance window
+	Error        string         `json:"error,omitempty"`
+	Timestamp    time.Time      `json:"timestamp"`
+}
+
+// CrashLoopResponder diagnoses services whose pods start crash-looping and
+// notifies when the diagnosis is high severity
+type CrashLoopResponder struct {
+	ua       *UltimateAnalyzer
+	notifier notify.Notifier
+
+	mu     sync.RWMutex
+	recent []CrashLoopDiagnosis // oldest first
+}
+
+func NewCrashLoopResponder(ua *UltimateAnalyzer, notifier notify.Notifier) *CrashLoopResponder {
+	return &CrashLoopResponder{
+		ua:       ua,
+		notifier: notifier,
+		recent:   make([]CrashLoopDiagnosis, 0, maxCrashLoopHistory),
+	}
+}
+
+// Run handles CrashLoop events until the context is cancelled or the channel closes
+func (r *CrashLoopResponder) Run(ctx context.Context, events <-chan eventbus.Event) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			r.handle(ctx, event)
+		}
+	}
+}
+
+// Recent returns crash-triggered diagnoses, newest first
+func (r *CrashLoopResponder) Recent() []CrashLoopDiagnosis {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]CrashLoopDiagnosis, len(r.recent))
+	for i, d := range r.recent {
+		out[len(r.recent)-1-i] = d
+	}
+	return out
+}
+
+func (r *CrashLoopResponder) handle(ctx context.Context, event eventbus.Event) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	logger.FromContext(ctx).Info("💥 CrashLoop detected, diagnosing service",
+		zap.String("service", event.Service),
+		zap.String("pod", event.Pod),
+	)
+
+	result := CrashLoopDiagnosis{
+		Event:     event,
+		Timestamp: time.Now(),
+	}
+
+	diagnosis, err := r.ua.DiagnoseService(ctx, event.Service)
+	if err != nil {
+		logger.FromContext(ctx).Error("CrashLoop diagnosis failed",
+			zap.String("service", event.Service),
+			zap.Error(err),
+		)
+		result.Error = err.Error()
+		r.record(result)
+		return
+	}
+
+	result.PredictionID = diagnosis.PredictionID
+	result.Problem = diagnosis.PrimaryDetection.Type
+	result.Severity = diagnosis.PrimaryDetection.Severity
+	result.Confidence = diagnosis.PrimaryDetection.Confidence
+	result.HealthScore = diagnosis.HealthScore
+	result.RiskLevel = diagnosis.RiskLevel
+
+	if r.notifier != nil && (result.Severity == SeverityHigh || result.Severity == SeverityCritical) {
+		err := r.notifier.Notify(ctx, notify.Notification{
+			Service:      event.Service,
+			Severity:     result.Severity,
+			Title:        fmt.Sprintf("%s crash-looping: %s", event.Service, result.Problem),
+			Message:      diagnosis.Recommendation,
+			PredictionID: diagnosis.PredictionID,
+			BurnRate:     burnRate(diagnosis),
+			Actions:      notificationActions(r.ua.cfg(), diagnosis),
+			Details: map[string]interface{}{
+				"pod":        event.Pod,
+				"restarts":   event.Payload["restarts"],
+				"confidence": result.Confidence,
+			},
+			Timestamp: time.Now(),
+		})
+		switch {
+		case errors.Is(err, notify.ErrSuppressed):
+			result.Suppressed = true
+		case err != nil:
+			logger.FromContext(ctx).Warn("CrashLoop notification failed", zap.String("service", event.Service), zap.Error(err))
+		default:
+			result.Notified = true
+		}
+	}
+
+	r.record(result)
+}
+
+func (r *CrashLoopResponder) record(d CrashLoopDiagnosis) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.recent) == maxCrashLoopHistory {
+		r.recent = append(r.recent[:0], r.recent[1:]...)
+	}
+	r.recent = append(r.recent, d)
+}
+
+// burnRate extracts the error-budget burn rate from a diagnosis, if computed
+func burnRate(diag *UltimateDiagnosis) float64 {
+	if diag.EnhancedData == nil || diag.EnhancedData.SLACompliance == nil {
+		return 0
+	}
+	return diag.EnhancedData.SLACompliance.ErrorBudgetBurnRate
+}
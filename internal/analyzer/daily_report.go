@@ -0,0 +1,337 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/notify"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultReportTopServices = 5
+
+	// reportDelay is how long after midnight UTC the previous day's report
+	// is compiled, so incidents closed and snapshots taken at midnight count
+	reportDelay = 5 * time.Minute
+
+	// maxReportIncidents bounds the incidents a report is compiled from
+	maxReportIncidents = 10000
+)
+
+// DailyReport summarizes one UTC day for people who don't read diagnoses
+type DailyReport struct {
+	Date        string    `json:"date"` // YYYY-MM-DD
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	GeneratedAt time.Time `json:"generated_at"`
+
+	// Partial is set on a report of the current day, which is compiled on
+	// request and not stored
+	Partial bool `json:"partial,omitempty"`
+
+	Incidents IncidentSummary `json:"incidents"`
+
+	// MTTRSeconds is the mean time from an incident opening to closing,
+	// over the incidents closed during the day; nil when none closed
+	MTTRSeconds *float64 `json:"mttr_seconds"`
+	MTTR        string   `json:"mttr,omitempty"`
+
+	TopUnhealthy []UnhealthyService `json:"top_unhealthy"`
+	SLOBurn      []SLOBurn          `json:"slo_burn"`
+}
+
+// IncidentSummary counts the incidents active during a report's day
+type IncidentSummary struct {
+	Active     int            `json:"active"` // open at some point during the day
+	Opened     int            `json:"opened"`
+	Closed     int            `json:"closed"`
+	ByType     map[string]int `json:"by_type"`
+	BySeverity map[string]int `json:"by_severity"` // by peak severity
+}
+
+// UnhealthyService is one of the services with the lowest mean health
+type UnhealthyService struct {
+	storage.HealthSummary
+	Incidents int `json:"incidents"`
+}
+
+// SLOBurn is a service's error budget burn over the day, from its feature
+// snapshots. A burn rate above 1 spends the budget faster than the 99.9%
+// availability target allows.
+type SLOBurn struct {
+	ServiceName  string  `json:"service_name"`
+	Snapshots    int     `json:"snapshots"`
+	MeanBurnRate float64 `json:"mean_burn_rate"`
+	MaxBurnRate  float64 `json:"max_burn_rate"`
+
+	// LatencySLOMs and LatencyBreaches are set for services with a latency
+	// SLO: how many snapshots had a P99 above it
+	LatencySLOMs    float64 `json:"latency_slo_ms,omitempty"`
+	LatencyBreaches int     `json:"latency_breaches,omitempty"`
+}
+
+// ReportGenerator compiles and stores the daily reports, and sends each one
+// through the notifier when reports.notify is set
+type ReportGenerator struct {
+	db       *storage.PostgresClient
+	notifier notify.Notifier
+	config   *core.ConfigStore
+}
+
+func NewReportGenerator(db *storage.PostgresClient, notifier notify.Notifier, config *core.ConfigStore) *ReportGenerator {
+	return &ReportGenerator{
+		db:       db,
+		notifier: notifier,
+		config:   config,
+	}
+}
+
+// ReportDay returns the start of t's UTC day
+func ReportDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+func (g *ReportGenerator) topServices() int {
+	if cfg := g.config.Get(); cfg != nil && cfg.Reports.TopServices > 0 {
+		return cfg.Reports.TopServices
+	}
+	return defaultReportTopServices
+}
+
+// Run compiles, stores and announces the previous day's report shortly
+// after each midnight UTC until the context is cancelled. reports.enabled
+// is checked each time, so a config reload takes effect.
+func (g *ReportGenerator) Run(ctx context.Context) {
+	for {
+		next := ReportDay(time.Now()).Add(24*time.Hour + reportDelay)
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		cfg := g.config.Get()
+		if cfg == nil || !cfg.Reports.Enabled {
+			continue
+		}
+		report, err := g.Generate(ctx, ReportDay(next).AddDate(0, 0, -1))
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.FromContext(ctx).Warn("Daily report failed", zap.Error(err))
+			}
+			continue
+		}
+		logger.FromContext(ctx).Info("📰 Daily report generated",
+			zap.String("date", report.Date),
+			zap.Int("incidents", report.Incidents.Active))
+		if cfg.Reports.Notify {
+			g.notify(ctx, report)
+		}
+	}
+}
+
+// Report returns the report for the UTC day containing day. A past day's
+// stored report is returned, or compiled and stored if there is none yet;
+// the current day is compiled as a partial report and not stored.
+func (g *ReportGenerator) Report(ctx context.Context, day time.Time) (*DailyReport, error) {
+	day = ReportDay(day)
+	if !day.Before(ReportDay(time.Now())) {
+		report, err := g.Compile(ctx, day)
+		if err != nil {
+			return nil, err
+		}
+		report.Partial = true
+		return report, nil
+	}
+
+	stored, err := g.db.GetDailyReport(ctx, day)
+	switch {
+	case err == nil:
+		var report DailyReport
+		if err := json.Unmarshal(stored.Report, &report); err != nil {
+			return nil, fmt.Errorf("failed to decode daily report: %w", err)
+		}
+		return &report, nil
+	case errors.Is(err, storage.ErrNotFound):
+		return g.Generate(ctx, day)
+	default:
+		return nil, err
+	}
+}
+
+// Generate compiles the day's report and stores it, replacing any earlier one
+func (g *ReportGenerator) Generate(ctx context.Context, day time.Time) (*DailyReport, error) {
+	report, err := g.Compile(ctx, day)
+	if err != nil {
+		return nil, err
+	}
+	err = g.db.SaveDailyReport(ctx, &storage.DailyReport{
+		Date:        report.From,
+		GeneratedAt: report.GeneratedAt,
+	}, report)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// Compile builds the report for the UTC day containing day without storing it
+func (g *ReportGenerator) Compile(ctx context.Context, day time.Time) (*DailyReport, error) {
+	from := ReportDay(day)
+	to := from.Add(24 * time.Hour)
+
+	incidents, err := g.db.GetIncidentsBetween(ctx, from, to, maxReportIncidents)
+	if err != nil {
+		return nil, err
+	}
+	health, err := g.db.GetHealthSummaries(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	snapshots, err := g.db.GetFeatureSnapshotsBetween(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DailyReport{
+		Date:        from.Format("2006-01-02"),
+		From:        from,
+		To:          to,
+		GeneratedAt: time.Now().UTC(),
+	}
+	report.Incidents, report.MTTRSeconds = summarizeIncidents(incidents, from, to)
+	if report.MTTRSeconds != nil {
+		report.MTTR = (time.Duration(*report.MTTRSeconds) * time.Second).String()
+	}
+
+	top := g.topServices()
+	perService := make(map[string]int)
+	for _, inc := range incidents {
+		perService[inc.ServiceName]++
+	}
+	report.TopUnhealthy = make([]UnhealthyService, 0, top)
+	for _, s := range health {
+		if len(report.TopUnhealthy) == top {
+			break
+		}
+		report.TopUnhealthy = append(report.TopUnhealthy, UnhealthyService{HealthSummary: s, Incidents: perService[s.ServiceName]})
+	}
+
+	report.SLOBurn = sloBurn(g.config.Get(), snapshots)
+	if len(report.SLOBurn) > top {
+		report.SLOBurn = report.SLOBurn[:top]
+	}
+	return report, nil
+}
+
+// summarizeIncidents counts the incidents active in [from, to) and returns
+// the mean time to resolve, in seconds, of those closed in it
+func summarizeIncidents(incidents []*storage.Incident, from, to time.Time) (IncidentSummary, *float64) {
+	summary := IncidentSummary{
+		ByType:     make(map[string]int),
+		BySeverity: make(map[string]int),
+	}
+	var resolved float64
+	for _, inc := range incidents {
+		summary.Active++
+		summary.ByType[inc.ProblemType]++
+		summary.BySeverity[inc.PeakSeverity]++
+		if !inc.FirstSeen.Before(from) {
+			summary.Opened++
+		}
+		if inc.ClosedAt != nil && !inc.ClosedAt.Before(from) && inc.ClosedAt.Before(to) {
+			summary.Closed++
+			resolved += inc.ClosedAt.Sub(inc.FirstSeen).Seconds()
+		}
+	}
+	if summary.Closed == 0 {
+		return summary, nil
+	}
+	mttr := math.Round(resolved / float64(summary.Closed))
+	return summary, &mttr
+}
+
+// sloBurn computes each service's burn rate over its snapshots, fastest
+// burning first
+func sloBurn(cfg *core.Config, snapshots []*storage.FeatureSnapshot) []SLOBurn {
+	burns := make(map[string]*SLOBurn)
+	for _, s := range snapshots {
+		b, ok := burns[s.ServiceName]
+		if !ok {
+			b = &SLOBurn{ServiceName: s.ServiceName}
+			if cfg != nil {
+				b.LatencySLOMs = cfg.ThresholdsFor(s.ServiceName).LatencySLOMs
+			}
+			burns[s.ServiceName] = b
+		}
+		rate := errorBudgetBurnRate(availabilityPercent(s.Features["error_rate_mean"]))
+		b.Snapshots++
+		b.MeanBurnRate += rate
+		b.MaxBurnRate = math.Max(b.MaxBurnRate, rate)
+		if b.LatencySLOMs > 0 && s.Features["latency_p99"] > b.LatencySLOMs {
+			b.LatencyBreaches++
+		}
+	}
+
+	result := make([]SLOBurn, 0, len(burns))
+	for _, b := range burns {
+		b.MeanBurnRate = math.Round(b.MeanBurnRate/float64(b.Snapshots)*100) / 100
+		b.MaxBurnRate = math.Round(b.MaxBurnRate*100) / 100
+		result = append(result, *b)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].MeanBurnRate != result[j].MeanBurnRate {
+			return result[i].MeanBurnRate > result[j].MeanBurnRate
+		}
+		return result[i].ServiceName < result[j].ServiceName
+	})
+	return result
+}
+
+func (g *ReportGenerator) notify(ctx context.Context, report *DailyReport) {
+	if g.notifier == nil {
+		return
+	}
+
+	mttr := "n/a"
+	if report.MTTR != "" {
+		mttr = report.MTTR
+	}
+	var worst []string
+	for _, s := range report.TopUnhealthy {
+		worst = append(worst, fmt.Sprintf("%s (%.0f)", s.ServiceName, s.Mean))
+	}
+	message := "No health scores were recorded."
+	if len(worst) > 0 {
+		message = "Least healthy: " + strings.Join(worst, ", ")
+	}
+
+	err := g.notifier.Notify(ctx, notify.Notification{
+		Severity: SeverityNone,
+		Title:    fmt.Sprintf("Daily report %s: %d incidents, MTTR %s", report.Date, report.Incidents.Active, mttr),
+		Message:  message,
+		Details: map[string]interface{}{
+			"date":             report.Date,
+			"incidents_opened": report.Incidents.Opened,
+			"incidents_closed": report.Incidents.Closed,
+			"by_severity":      report.Incidents.BySeverity,
+			"mttr_seconds":     report.MTTRSeconds,
+		},
+		Timestamp: report.GeneratedAt,
+	})
+	if err != nil && !errors.Is(err, notify.ErrSuppressed) {
+		logger.FromContext(ctx).Warn("Daily report notification failed", zap.String("date", report.Date), zap.Error(err))
+	}
+}
@@ -0,0 +1,174 @@
+package analyzer
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+)
+
+var reportFrom = time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+// reportIncident is an incident of the report day, with times as offsets
+// from its midnight; a zero closed leaves it open
+func reportIncident(service, problem, severity string, opened, closed time.Duration) *storage.Incident {
+	inc := &storage.Incident{
+		ID:           service + "-" + problem + "-" + opened.String(),
+		ServiceName:  service,
+		ProblemType:  problem,
+		Status:       storage.IncidentOpen,
+		FirstSeen:    reportFrom.Add(opened),
+		LastSeen:     reportFrom.Add(opened),
+		Occurrences:  1,
+		PeakSeverity: severity,
+	}
+	if closed != 0 {
+		at := reportFrom.Add(closed)
+		inc.Status, inc.ClosedAt, inc.LastSeen = storage.IncidentClosed, &at, at
+	}
+	return inc
+}
+
+// seededReportIncidents open and close around the report day: the first
+// carries over from the day before, the last closes the day after
+func seededReportIncidents(service string) []*storage.Incident {
+	return []*storage.Incident{
+		reportIncident(service, "MEMORY_LEAK", SeverityHigh, -2*time.Hour, time.Hour),         // 3h to resolve
+		reportIncident(service, "MEMORY_LEAK", SeverityCritical, 2*time.Hour, 3*time.Hour),    // 1h to resolve
+		reportIncident(service, "DEPLOYMENT_BUG", SeverityMedium, 5*time.Hour, 0),             // still open
+		reportIncident(service, "EXTERNAL_FAILURE", SeverityHigh, 23*time.Hour, 25*time.Hour), // closes tomorrow
+	}
+}
+
+func TestSummarizeIncidents(t *testing.T) {
+	summary, mttr := summarizeIncidents(seededReportIncidents("checkout"), reportFrom, reportFrom.Add(24*time.Hour))
+
+	if summary.Active != 4 || summary.Opened != 3 || summary.Closed != 2 {
+		t.Errorf("active/opened/closed = %d/%d/%d, want 4/3/2", summary.Active, summary.Opened, summary.Closed)
+	}
+	if mttr == nil || *mttr != (2*time.Hour).Seconds() {
+		t.Errorf("MTTR = %v, want the mean of 3h and 1h, 7200s", mttr)
+	}
+	wantTypes := map[string]int{"MEMORY_LEAK": 2, "DEPLOYMENT_BUG": 1, "EXTERNAL_FAILURE": 1}
+	for typ, want := range wantTypes {
+		if summary.ByType[typ] != want {
+			t.Errorf("by_type[%s] = %d, want %d", typ, summary.ByType[typ], want)
+		}
+	}
+	wantSeverities := map[string]int{SeverityCritical: 1, SeverityHigh: 2, SeverityMedium: 1}
+	for severity, want := range wantSeverities {
+		if summary.BySeverity[severity] != want {
+			t.Errorf("by_severity[%s] = %d, want %d", severity, summary.BySeverity[severity], want)
+		}
+	}
+
+	// Without an incident closed during the day there is no MTTR
+	if _, mttr := summarizeIncidents(seededReportIncidents("checkout")[2:3], reportFrom, reportFrom.Add(24*time.Hour)); mttr != nil {
+		t.Errorf("MTTR without closed incidents = %v, want nil", *mttr)
+	}
+}
+
+func TestSLOBurn(t *testing.T) {
+	cfg := &core.Config{Thresholds: map[string]core.ServiceThresholds{"checkout": {LatencySLOMs: 300}}}
+	snapshot := func(service string, errorRate, p99 float64) *storage.FeatureSnapshot {
+		return &storage.FeatureSnapshot{
+			ServiceName: service,
+			Timestamp:   reportFrom,
+			Features:    map[string]float64{"error_rate_mean": errorRate, "latency_p99": p99},
+		}
+	}
+
+	// An error rate of e loses e/10% availability, e times the 0.1% budget
+	burns := sloBurn(cfg, []*storage.FeatureSnapshot{
+		snapshot("search", 0.5, 900),
+		snapshot("checkout", 1, 250),
+		snapshot("checkout", 3, 400),
+	})
+
+	if len(burns) != 2 || burns[0].ServiceName != "checkout" || burns[1].ServiceName != "search" {
+		t.Fatalf("burns = %+v, want checkout then search, fastest burning first", burns)
+	}
+	checkout := burns[0]
+	if checkout.Snapshots != 2 || checkout.MeanBurnRate != 2 || checkout.MaxBurnRate != 3 {
+		t.Errorf("checkout burn = %+v, want 2 snapshots, mean 2, max 3", checkout)
+	}
+	if checkout.LatencySLOMs != 300 || checkout.LatencyBreaches != 1 {
+		t.Errorf("checkout latency = %v ms SLO, %d breaches, want 300 and 1", checkout.LatencySLOMs, checkout.LatencyBreaches)
+	}
+	if search := burns[1]; search.MeanBurnRate != 0.5 || search.LatencyBreaches != 0 {
+		t.Errorf("search burn = %+v, want 0.5 and no latency SLO", search)
+	}
+}
+
+func TestReportDay(t *testing.T) {
+	est := time.FixedZone("EST", -5*3600)
+	if got := ReportDay(time.Date(2026, 3, 9, 22, 30, 0, 0, est)); !got.Equal(reportFrom) {
+		t.Errorf("ReportDay(22:30 EST on March 9) = %v, want %v, the UTC day", got, reportFrom)
+	}
+}
+
+func TestDailyReportNotification(t *testing.T) {
+	recorder := &recordingNotifier{}
+	cfg := &core.Config{}
+	g := NewReportGenerator(nil, recorder, core.NewConfigStore("", cfg))
+
+	mttr := 7200.0
+	g.notify(context.Background(), &DailyReport{
+		Date:        "2026-03-10",
+		Incidents:   IncidentSummary{Active: 4, Opened: 3, Closed: 2},
+		MTTRSeconds: &mttr,
+		MTTR:        "2h0m0s",
+		TopUnhealthy: []UnhealthyService{
+			{HealthSummary: storage.HealthSummary{ServiceName: "checkout", Mean: 41.6}},
+			{HealthSummary: storage.HealthSummary{ServiceName: "search", Mean: 77}},
+		},
+	})
+
+	sent := recorder.notifications()
+	if len(sent) != 1 {
+		t.Fatalf("sent %d notifications, want 1", len(sent))
+	}
+	if want := "Daily report 2026-03-10: 4 incidents, MTTR 2h0m0s"; sent[0].Title != want {
+		t.Errorf("title = %q, want %q", sent[0].Title, want)
+	}
+	if !strings.Contains(sent[0].Message, "checkout (42), search (77)") {
+		t.Errorf("message = %q, want the least healthy services", sent[0].Message)
+	}
+}
+
+func TestCompileDailyReportFromSeededIncidents(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+
+	seeded := seededReportIncidents(service)
+	for _, inc := range seeded {
+		if err := db.SaveIncident(ctx, inc); err != nil {
+			t.Fatalf("SaveIncident: %v", err)
+		}
+	}
+
+	cfg := &core.Config{}
+	cfg.ApplyDefaults()
+	report, err := NewReportGenerator(db, nil, core.NewConfigStore("", cfg)).Compile(ctx, reportFrom.Add(12*time.Hour))
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if report.Date != "2026-03-10" || !report.From.Equal(reportFrom) {
+		t.Errorf("report date = %s from %v, want 2026-03-10", report.Date, report.From)
+	}
+
+	// Other incidents of that day may exist; ours must all be counted
+	if report.Incidents.Active < len(seeded) || report.Incidents.Closed < 2 {
+		t.Errorf("incidents = %+v, want at least the 4 seeded, 2 closed", report.Incidents)
+	}
+	if report.Incidents.Active == len(seeded) {
+		if report.MTTRSeconds == nil || *report.MTTRSeconds != 7200 || report.MTTR != "2h0m0s" {
+			t.Errorf("MTTR = %v (%s), want 7200s (2h0m0s)", report.MTTRSeconds, report.MTTR)
+		}
+	}
+}
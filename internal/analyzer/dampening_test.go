@@ -0,0 +1,98 @@
+package analyzer
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+func TestDampener(t *testing.T) {
+	d := newDampener(80)
+	confidence := d.apply(80, 0.8, "unconfirmed_trend")
+	confidence = d.apply(confidence, 0.7, "weak_signals")
+
+	det := d.annotate(&Detection{Confidence: confidence})
+	if det.AggregateConfidence != 80 {
+		t.Errorf("AggregateConfidence = %v, want 80", det.AggregateConfidence)
+	}
+	if math.Abs(det.Dampening-0.56) > 1e-9 {
+		t.Errorf("Dampening = %v, want 0.8 x 0.7 = 0.56", det.Dampening)
+	}
+	if math.Abs(det.Confidence-det.AggregateConfidence*det.Dampening) > 1e-9 {
+		t.Errorf("Confidence = %v, want aggregate x dampening = %v", det.Confidence, det.AggregateConfidence*det.Dampening)
+	}
+	if !slices.Equal(det.DampeningReasons, []string{"unconfirmed_trend", "weak_signals"}) {
+		t.Errorf("DampeningReasons = %v", det.DampeningReasons)
+	}
+
+	undamped := newDampener(40).annotate(&Detection{Confidence: 40})
+	if undamped.Dampening != 1 || undamped.DampeningReasons != nil {
+		t.Errorf("undamped = %v %v, want a factor of 1 and no reasons", undamped.Dampening, undamped.DampeningReasons)
+	}
+}
+
+func TestResourceExhaustionReportsDampening(t *testing.T) {
+	// CPU at 88% is one signal worth (88-80)/20 x 30 = 12, short of quality
+	weak := &ServiceFeatures{CPUMean: 88, MemoryMean: 50}
+
+	tests := []struct {
+		name           string
+		factor         float64
+		features       *ServiceFeatures
+		wantAggregate  float64
+		wantDampening  float64
+		wantConfidence float64
+		wantReasons    []string
+	}{
+		{name: "default factor", features: weak, wantAggregate: 12, wantDampening: 0.75, wantConfidence: 9, wantReasons: []string{"weak_signals"}},
+		{name: "configured factor", factor: 0.5, features: weak, wantAggregate: 12, wantDampening: 0.5, wantConfidence: 6, wantReasons: []string{"weak_signals"}},
+		{name: "factor of 1", factor: 1, features: weak, wantAggregate: 12, wantDampening: 1, wantConfidence: 12, wantReasons: []string{"weak_signals"}},
+		// 30 + 30 + the 20 bonus for both resources; no gate fails
+		{name: "strong signals", features: &ServiceFeatures{CPUMean: 100, MemoryMean: 100}, wantAggregate: 80, wantDampening: 1, wantConfidence: 80},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &core.Config{}
+			cfg.Analyzer.Dampening.ResourceExhaustion = tt.factor
+			det := newTestDetector(cfg).resourceExhaustion("checkout", tt.features)
+
+			if math.Abs(det.AggregateConfidence-tt.wantAggregate) > 1e-9 {
+				t.Errorf("AggregateConfidence = %v, want %v", det.AggregateConfidence, tt.wantAggregate)
+			}
+			if det.Dampening != tt.wantDampening {
+				t.Errorf("Dampening = %v, want %v", det.Dampening, tt.wantDampening)
+			}
+			if math.Abs(det.Confidence-tt.wantConfidence) > 1e-9 {
+				t.Errorf("Confidence = %v, want %v", det.Confidence, tt.wantConfidence)
+			}
+			if math.Abs(det.Confidence-det.AggregateConfidence*det.Dampening) > 1e-9 {
+				t.Errorf("Confidence %v != aggregate %v x dampening %v", det.Confidence, det.AggregateConfidence, det.Dampening)
+			}
+			if !slices.Equal(det.DampeningReasons, tt.wantReasons) {
+				t.Errorf("DampeningReasons = %v, want %v", det.DampeningReasons, tt.wantReasons)
+			}
+		})
+	}
+}
+
+func TestDetectorCatalogListsDampening(t *testing.T) {
+	cfg := &core.Config{}
+	cfg.Analyzer.Dampening.UnconfirmedTrend = 0.9
+	cfg.Analyzer.Dampening.ExternalFailure = 0.5
+	cfg.ApplyDefaults()
+	infos := NewUltimateAnalyzer(nil, core.NewConfigStore("", cfg)).Detectors("")
+
+	memory := detectorNamed(t, infos, "memory_leak")
+	if got := memory.Thresholds["unconfirmed_trend_dampening"]; got != 0.9 {
+		t.Errorf("memory_leak unconfirmed_trend_dampening = %v, want 0.9", got)
+	}
+	if got := memory.Thresholds["weak_signal_dampening"]; got != 0.7 {
+		t.Errorf("memory_leak weak_signal_dampening = %v, want the default 0.7", got)
+	}
+	if got := detectorNamed(t, infos, "external_failure").Thresholds["weak_signal_dampening"]; got != 0.5 {
+		t.Errorf("external_failure weak_signal_dampening = %v, want 0.5", got)
+	}
+}
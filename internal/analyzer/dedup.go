@@ -0,0 +1,158 @@
+package analyzer
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	defaultDiagnosisCacheTTL = 5 * time.Second
+
+	// diagnosisFlightTimeout bounds a shared diagnosis. It runs detached from
+	// the caller that started it, so one caller giving up doesn't fail the
+	// others waiting on the same result.
+	diagnosisFlightTimeout = 60 * time.Second
+)
+
+type diagnosisKey struct {
+	service string
+	window  time.Duration
+}
+
+func (k diagnosisKey) String() string {
+	return k.service + "|" + k.window.String()
+}
+
+type diagnosisCacheEntry struct {
+	diag    *UltimateDiagnosis
+	expires time.Time
+}
+
+// diagnosisDedup collapses concurrent diagnoses of the same service into one
+// run and reuses the result briefly, so a dashboard, an alert responder and
+// an operator hitting the same service don't each run every detector
+type diagnosisDedup struct {
+	flight singleflight.Group
+
+	mu    sync.Mutex
+	cache map[diagnosisKey]diagnosisCacheEntry
+
+	executions atomic.Int64
+	shared     atomic.Int64
+	cacheHits  atomic.Int64
+}
+
+// DiagnosisDedupStats counts how diagnosis requests were answered
+type DiagnosisDedupStats struct {
+	Executions int64 `json:"executions"` // diagnoses actually run
+	Shared     int64 `json:"shared"`     // requests that joined an in-flight diagnosis
+	CacheHits  int64 `json:"cache_hits"` // requests answered from a recent result
+}
+
+// DedupStats returns the diagnosis de-duplication counters
+func (ua *UltimateAnalyzer) DedupStats() DiagnosisDedupStats {
+	return DiagnosisDedupStats{
+		Executions: ua.dedup.executions.Load(),
+		Shared:     ua.dedup.shared.Load(),
+		CacheHits:  ua.dedup.cacheHits.Load(),
+	}
+}
+
+func (ua *UltimateAnalyzer) diagnosisCacheTTL() time.Duration {
+	if cfg := ua.cfg(); cfg != nil {
+		if d, err := time.ParseDuration(cfg.Analyzer.DiagnosisCacheTTL); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultDiagnosisCacheTTL
+}
+
+// DiagnoseService performs ultimate comprehensive diagnosis. Concurrent
+// calls for the same service and window share one run, and a result is
+// reused for analyzer.diagnosis_cache_ttl. Replays always run on their own.
+// The returned diagnosis may be shared and must be treated as read-only.
+func (ua *UltimateAnalyzer) DiagnoseService(ctx context.Context, serviceName string) (*UltimateDiagnosis, error) {
+	if storage.HasAsOf(ctx) {
+		ua.dedup.executions.Add(1)
+		return ua.diagnose(ctx, serviceName)
+	}
+
+	key := diagnosisKey{service: serviceName, window: analysisWindow(ctx)}
+	return ua.dedup.do(ctx, key, ua.diagnosisCacheTTL(), func(ctx context.Context) (*UltimateDiagnosis, error) {
+		diag, err := ua.diagnose(ctx, serviceName)
+		if err == nil && key.window == defaultAnalysisWindow {
+			ua.lastKnown.put(diag)
+		}
+		return diag, err
+	})
+}
+
+// do answers from a cached result for key, or joins the in-flight run for
+// it, or starts one with run. A successful result is cached for ttl.
+func (d *diagnosisDedup) do(ctx context.Context, key diagnosisKey, ttl time.Duration, run func(context.Context) (*UltimateDiagnosis, error)) (*UltimateDiagnosis, error) {
+	if diag, ok := d.cached(key); ok {
+		d.cacheHits.Add(1)
+		return diag, nil
+	}
+
+	led := false
+	ch := d.flight.DoChan(key.String(), func() (interface{}, error) {
+		led = true
+		flightCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), diagnosisFlightTimeout)
+		defer cancel()
+
+		d.executions.Add(1)
+		diag, err := run(flightCtx)
+		if err == nil {
+			d.store(key, diag, ttl)
+		}
+		return diag, err
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if !led {
+			d.shared.Add(1)
+		}
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.(*UltimateDiagnosis), nil
+	}
+}
+
+func (d *diagnosisDedup) cached(key diagnosisKey) (*UltimateDiagnosis, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	entry, ok := d.cache[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(d.cache, key)
+		return nil, false
+	}
+	return entry.diag, true
+}
+
+func (d *diagnosisDedup) store(key diagnosisKey, diag *UltimateDiagnosis, ttl time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	// Sweep expired results so the cache stays proportional to active services
+	for k, entry := range d.cache {
+		if now.After(entry.expires) {
+			delete(d.cache, k)
+		}
+	}
+	d.cache[key] = diagnosisCacheEntry{diag: diag, expires: now.Add(ttl)}
+}
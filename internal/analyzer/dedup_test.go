@@ -0,0 +1,153 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestDedup() *diagnosisDedup {
+	return &diagnosisDedup{cache: make(map[diagnosisKey]diagnosisCacheEntry)}
+}
+
+func TestDiagnosisDedupSharesOneRun(t *testing.T) {
+	d := newTestDedup()
+	key := diagnosisKey{service: "checkout", window: defaultAnalysisWindow}
+
+	var runs atomic.Int64
+	release := make(chan struct{})
+	run := func(context.Context) (*UltimateDiagnosis, error) {
+		runs.Add(1)
+		<-release
+		return &UltimateDiagnosis{ServiceName: "checkout"}, nil
+	}
+
+	const callers = 20
+	results := make([]*UltimateDiagnosis, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			diag, err := d.do(context.Background(), key, time.Minute, run)
+			if err != nil {
+				t.Errorf("caller %d: %v", i, err)
+			}
+			results[i] = diag
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	// Callers that arrive after the run finished hit the cache instead of
+	// joining it; either way only one run happens
+	if n := runs.Load(); n != 1 {
+		t.Errorf("ran %d diagnoses for %d concurrent callers, want 1", n, callers)
+	}
+	for i, diag := range results {
+		if diag != results[0] {
+			t.Errorf("caller %d got a different diagnosis", i)
+		}
+	}
+	if got := d.executions.Load(); got != 1 {
+		t.Errorf("executions = %d, want 1", got)
+	}
+	if got := d.shared.Load() + d.cacheHits.Load(); got != callers-1 {
+		t.Errorf("shared + cache hits = %d, want %d", got, callers-1)
+	}
+}
+
+func TestDiagnosisDedupCacheExpires(t *testing.T) {
+	d := newTestDedup()
+	key := diagnosisKey{service: "checkout", window: defaultAnalysisWindow}
+
+	runs := 0
+	run := func(context.Context) (*UltimateDiagnosis, error) {
+		runs++
+		return &UltimateDiagnosis{}, nil
+	}
+
+	ctx := context.Background()
+	_, _ = d.do(ctx, key, 30*time.Millisecond, run)
+	_, _ = d.do(ctx, key, 30*time.Millisecond, run)
+	if runs != 1 || d.cacheHits.Load() != 1 {
+		t.Fatalf("runs = %d, cache hits = %d, want the repeat answered from cache", runs, d.cacheHits.Load())
+	}
+
+	// Another window is another key
+	_, _ = d.do(ctx, diagnosisKey{service: "checkout", window: time.Hour}, time.Minute, run)
+	if runs != 2 {
+		t.Errorf("runs = %d after a different window, want 2", runs)
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	_, _ = d.do(ctx, key, 30*time.Millisecond, run)
+	if runs != 3 {
+		t.Errorf("runs = %d after expiry, want 3", runs)
+	}
+}
+
+func TestDiagnosisDedupDoesNotCacheErrors(t *testing.T) {
+	d := newTestDedup()
+	key := diagnosisKey{service: "checkout", window: defaultAnalysisWindow}
+
+	errDown := errors.New("database unavailable")
+	runs := 0
+	run := func(context.Context) (*UltimateDiagnosis, error) {
+		runs++
+		return nil, errDown
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := d.do(context.Background(), key, time.Minute, run); !errors.Is(err, errDown) {
+			t.Fatalf("err = %v, want %v", err, errDown)
+		}
+	}
+	if runs != 2 {
+		t.Errorf("runs = %d, want a failed diagnosis retried rather than cached", runs)
+	}
+}
+
+func TestDiagnosisDedupCallerCancelDoesNotFailOthers(t *testing.T) {
+	d := newTestDedup()
+	key := diagnosisKey{service: "checkout", window: defaultAnalysisWindow}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	run := func(ctx context.Context) (*UltimateDiagnosis, error) {
+		close(started)
+		select {
+		case <-release:
+			return &UltimateDiagnosis{}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := d.do(ctx, key, time.Minute, run)
+		leaderErr <- err
+	}()
+	<-started
+
+	follower := make(chan error, 1)
+	go func() {
+		_, err := d.do(context.Background(), key, time.Minute, run)
+		follower <- err
+	}()
+
+	cancel()
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled caller: err = %v, want context.Canceled", err)
+	}
+	close(release)
+	if err := <-follower; err != nil {
+		t.Errorf("other caller: err = %v, want the shared result", err)
+	}
+}
@@ -0,0 +1,75 @@
+package analyzer
+
+import (
+	"context"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/pkg/logger"
+	"github.com/prometheus/common/model"
+	"go.uber.org/zap"
+)
+
+// DependencyQuerier runs the PromQL health queries declared for a service's
+// dependencies. observer.PrometheusClient implements it.
+type DependencyQuerier interface {
+	Query(ctx context.Context, query string) (model.Vector, error)
+}
+
+// DependencyStatus is the outcome of one declared dependency check
+type DependencyStatus struct {
+	Name    string `json:"name"`
+	Healthy bool   `json:"healthy"`
+	Series  int    `json:"series,omitempty"` // series returned by a failing check
+	Error   string `json:"error,omitempty"`  // the query itself failed; health is unknown
+}
+
+// SetDependencyQuerier enables the declared dependency checks. Call it before
+// diagnoses start; without a querier the checks are skipped.
+func (ua *UltimateAnalyzer) SetDependencyQuerier(q DependencyQuerier) {
+	ua.enhancedDetector.dependencies = q
+}
+
+// checkDependencies runs the service's declared dependency health queries.
+// It returns nil when none are declared, no querier is set, or the diagnosis
+// replays historical data, since the queries only see the present.
+func (ed *EnhancedDetector) checkDependencies(ctx context.Context, serviceName string) []DependencyStatus {
+	cfg := ed.cfg()
+	if cfg == nil || ed.dependencies == nil || storage.HasAsOf(ctx) {
+		return nil
+	}
+	checks := cfg.Dependencies[serviceName]
+	if len(checks) == 0 {
+		return nil
+	}
+
+	statuses := make([]DependencyStatus, 0, len(checks))
+	for _, check := range checks {
+		status := DependencyStatus{Name: check.Name}
+		vector, err := ed.dependencies.Query(ctx, check.HealthQuery)
+		switch {
+		case err != nil:
+			status.Error = err.Error()
+			logger.FromContext(ctx).Warn("Dependency health query failed",
+				zap.String("service", serviceName),
+				zap.String("dependency", check.Name),
+				zap.Error(err))
+		case len(vector) > 0:
+			status.Series = len(vector)
+		default:
+			status.Healthy = true
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// unhealthyDependencies names the dependencies whose checks are failing
+func unhealthyDependencies(statuses []DependencyStatus) []string {
+	var names []string
+	for _, s := range statuses {
+		if !s.Healthy && s.Error == "" {
+			names = append(names, s.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,99 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/prometheus/common/model"
+)
+
+// fakeQuerier answers each query from a fixed table
+type fakeQuerier struct {
+	results map[string]model.Vector
+	errs    map[string]error
+	queries []string
+}
+
+func (f *fakeQuerier) Query(_ context.Context, query string) (model.Vector, error) {
+	f.queries = append(f.queries, query)
+	if err := f.errs[query]; err != nil {
+		return nil, err
+	}
+	return f.results[query], nil
+}
+
+func newDependencyDetector(checks ...core.DependencyCheck) (*EnhancedDetector, *fakeQuerier) {
+	cfg := &core.Config{}
+	cfg.Dependencies = map[string][]core.DependencyCheck{"checkout": checks}
+	ed := newTestDetector(cfg)
+	q := &fakeQuerier{results: map[string]model.Vector{}, errs: map[string]error{}}
+	ed.dependencies = q
+	return ed, q
+}
+
+func TestCheckDependencies(t *testing.T) {
+	ed, q := newDependencyDetector(
+		core.DependencyCheck{Name: "postgres", HealthQuery: `up{job="postgres"} == 0`},
+		core.DependencyCheck{Name: "payments", HealthQuery: `payments_errors > 1`},
+		core.DependencyCheck{Name: "cache", HealthQuery: `up{job="cache"} == 0`},
+	)
+	q.results[`up{job="postgres"} == 0`] = model.Vector{{Value: 0}, {Value: 0}}
+	q.errs[`up{job="cache"} == 0`] = errors.New("bad_data: parse error")
+
+	statuses := ed.checkDependencies(context.Background(), "checkout")
+	want := []DependencyStatus{
+		{Name: "postgres", Healthy: false, Series: 2},
+		{Name: "payments", Healthy: true},
+		{Name: "cache", Healthy: false, Error: "bad_data: parse error"},
+	}
+	if len(statuses) != len(want) {
+		t.Fatalf("statuses = %+v, want %+v", statuses, want)
+	}
+	for i := range want {
+		if statuses[i] != want[i] {
+			t.Errorf("statuses[%d] = %+v, want %+v", i, statuses[i], want[i])
+		}
+	}
+
+	// A failed query leaves health unknown, so only postgres counts
+	unhealthy := unhealthyDependencies(statuses)
+	if len(unhealthy) != 1 || unhealthy[0] != "postgres" {
+		t.Errorf("unhealthyDependencies = %v, want [postgres]", unhealthy)
+	}
+}
+
+func TestCheckDependenciesSkipped(t *testing.T) {
+	check := core.DependencyCheck{Name: "postgres", HealthQuery: `up{job="postgres"} == 0`}
+
+	t.Run("none declared", func(t *testing.T) {
+		ed, q := newDependencyDetector(check)
+		if statuses := ed.checkDependencies(context.Background(), "search"); statuses != nil {
+			t.Errorf("statuses = %+v, want nil for a service without checks", statuses)
+		}
+		if len(q.queries) != 0 {
+			t.Errorf("ran %v, want no queries", q.queries)
+		}
+	})
+
+	t.Run("no querier", func(t *testing.T) {
+		ed, _ := newDependencyDetector(check)
+		ed.dependencies = nil
+		if statuses := ed.checkDependencies(context.Background(), "checkout"); statuses != nil {
+			t.Errorf("statuses = %+v, want nil without a querier", statuses)
+		}
+	})
+
+	t.Run("historical replay", func(t *testing.T) {
+		ed, q := newDependencyDetector(check)
+		ctx := storage.WithAsOf(context.Background(), testEpoch)
+		if statuses := ed.checkDependencies(ctx, "checkout"); statuses != nil {
+			t.Errorf("statuses = %+v, want nil during a replay", statuses)
+		}
+		if len(q.queries) != 0 {
+			t.Errorf("ran %v during a replay, want no queries", q.queries)
+		}
+	})
+}
@@ -0,0 +1,188 @@
+package analyzer
+
+import (
+	"math"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+// ClassicDetector applies the static analyzer thresholds from the config to
+// extracted features. It is deliberately simple so it can act as a second
+// opinion next to the EnhancedDetector.
+type ClassicDetector struct {
+	config *core.ConfigStore
+	risk   *RiskClassifier
+}
+
+func NewClassicDetector(config *core.ConfigStore) *ClassicDetector {
+	return &ClassicDetector{config: config, risk: NewRiskClassifier(config)}
+}
+
+// DetectAll evaluates every classic rule against the features, skipping the
+// types of detectors disabled in analyzer.disabled_detectors
+func (cd *ClassicDetector) DetectAll(features *ServiceFeatures) []*Detection {
+	all := []*Detection{
+		cd.detectMemoryLeak(features),
+		cd.detectResourceExhaustion(features),
+		cd.detectDeploymentBug(features),
+		cd.detectExternalFailure(features),
+		cd.detectCascadeFailure(features),
+	}
+
+	cfg := cd.config.Get()
+	detections := all[:0]
+	for _, d := range all {
+		if typeEnabled(cfg, d.Type) {
+			detections = append(detections, d)
+		}
+	}
+	return detections
+}
+
+func (cd *ClassicDetector) thresholds() (cpu, memory, errorRate, latency float64) {
+	return classicThresholds(cd.config.Get())
+}
+
+// classicThresholds returns the analyzer.*_threshold settings the classic
+// rules compare against, or their defaults without a config
+func classicThresholds(cfg *core.Config) (cpu, memory, errorRate, latency float64) {
+	if cfg == nil {
+		return 85, 90, 15, 2000
+	}
+	a := cfg.Analyzer
+	return a.CPUThreshold, a.MemoryThreshold, a.ErrorRateThreshold, a.LatencyThreshold
+}
+
+func (cd *ClassicDetector) detectMemoryLeak(f *ServiceFeatures) *Detection {
+	_, memThreshold, _, _ := cd.thresholds()
+
+	confidence := 0.0
+	if f.MemoryTrend > 0.05 {
+		confidence += 40
+	}
+	if f.MemoryMean > memThreshold*0.8 {
+		confidence += 30
+	}
+	if f.MemoryMax > memThreshold {
+		confidence += 30
+	}
+
+	return cd.detection(f, DetectionMemoryLeak, confidence, map[string]interface{}{
+		"memory_trend":     quantity("%.3f%%/min", f.MemoryTrend),
+		"memory_mean":      quantity("%.2f%%", f.MemoryMean),
+		"memory_threshold": quantity("%.2f%%", memThreshold),
+	})
+}
+
+func (cd *ClassicDetector) detectResourceExhaustion(f *ServiceFeatures) *Detection {
+	cpuThreshold, memThreshold, _, _ := cd.thresholds()
+
+	confidence := 0.0
+	if f.CPUMean > cpuThreshold {
+		confidence += 50
+	}
+	if f.MemoryMean > memThreshold {
+		confidence += 50
+	}
+
+	return cd.detection(f, DetectionResourceExhaustion, confidence, map[string]interface{}{
+		"cpu_mean":         quantity("%.2f%%", f.CPUMean),
+		"cpu_threshold":    quantity("%.2f%%", cpuThreshold),
+		"memory_mean":      quantity("%.2f%%", f.MemoryMean),
+		"memory_threshold": quantity("%.2f%%", memThreshold),
+	})
+}
+
+func (cd *ClassicDetector) detectDeploymentBug(f *ServiceFeatures) *Detection {
+	_, _, errThreshold, _ := cd.thresholds()
+
+	confidence := 0.0
+	if f.ScoredErrorRate > errThreshold {
+		confidence += 50
+	}
+	if f.ErrorRateTrend > 0 && f.ErrorRateSpikiness > 2 {
+		confidence += 30
+	}
+	// Errors without resource pressure point at the code rather than capacity
+	cpuThreshold, memThreshold, _, _ := cd.thresholds()
+	if confidence > 0 && f.CPUMean < cpuThreshold && f.MemoryMean < memThreshold {
+		confidence += 20
+	}
+
+	evidence := map[string]interface{}{
+		"error_rate_mean":      quantity("%.2f", f.ErrorRateMean),
+		"error_rate_threshold": quantity("%.2f", errThreshold),
+		"error_spikiness":      quantity("%.2f", f.ErrorRateSpikiness),
+	}
+	addErrorEvidence(evidence, f)
+	return cd.detection(f, DetectionDeploymentBug, confidence, evidence)
+}
+
+func (cd *ClassicDetector) detectExternalFailure(f *ServiceFeatures) *Detection {
+	cpuThreshold, _, errThreshold, latThreshold := cd.thresholds()
+
+	confidence := 0.0
+	if f.LatencyP95 > latThreshold {
+		confidence += 50
+	}
+	if f.ScoredErrorRate > errThreshold*0.5 {
+		confidence += 20
+	}
+	// Slow responses with an idle CPU means the time is spent waiting elsewhere
+	if f.LatencyP95 > latThreshold && f.CPUMean < cpuThreshold*0.5 {
+		confidence += 30
+	}
+
+	evidence := map[string]interface{}{
+		"latency_p95":       quantity("%.2fms", f.LatencyP95),
+		"latency_threshold": quantity("%.2fms", latThreshold),
+		"cpu_mean":          quantity("%.2f%%", f.CPUMean),
+	}
+	addErrorEvidence(evidence, f)
+	return cd.detection(f, DetectionExternalFailure, confidence, evidence)
+}
+
+func (cd *ClassicDetector) detectCascadeFailure(f *ServiceFeatures) *Detection {
+	_, _, errThreshold, latThreshold := cd.thresholds()
+
+	confidence := 0.0
+	if f.ScoredErrorRate > errThreshold {
+		confidence += 35
+	}
+	if f.LatencyP95 > latThreshold {
+		confidence += 35
+	}
+	if f.LatencyErrorCorr > 0.7 {
+		confidence += 30
+	}
+
+	evidence := map[string]interface{}{
+		"error_rate_mean":    quantity("%.2f", f.ErrorRateMean),
+		"latency_p95":        quantity("%.2fms", f.LatencyP95),
+		"latency_error_corr": quantity("%.3f", f.LatencyErrorCorr),
+	}
+	addErrorEvidence(evidence, f)
+	return cd.detection(f, DetectionCascadingFailure, confidence, evidence)
+}
+
+// detection builds a Detection, treating 50% confidence as the firing point
+func (cd *ClassicDetector) detection(f *ServiceFeatures, t DetectionType, confidence float64, evidence map[string]interface{}) *Detection {
+	confidence = math.Min(confidence, 100)
+	detected := confidence >= 50
+
+	severity := SeverityNone
+	if detected {
+		severity = cd.risk.SeverityForConfidence(confidence)
+	}
+
+	return &Detection{
+		Type:        t,
+		ServiceName: f.ServiceName,
+		Detected:    detected,
+		Confidence:  confidence,
+		Timestamp:   time.Now(),
+		Evidence:    evidence,
+		Severity:    severity,
+	}
+}
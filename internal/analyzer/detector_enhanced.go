@@ -4,30 +4,251 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"strings"
 	"time"
 
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
 	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/pkg/logger"
 	"go.uber.org/zap"
 )
 
+// Confidence each enhanced detector's signals must sum past to report a
+// detection
+const (
+	memoryLeakCutoff         = 65.0
+	resourceExhaustionCutoff = 60.0
+	deploymentBugCutoff      = 55.0
+	externalFailureCutoff    = 55.0
+	cascadeFailureCutoff     = 60.0
+)
+
 // EnhancedDetector uses feature-based multi-signal detection
 type EnhancedDetector struct {
 	featureExtractor *FeatureExtractor
+	cascade          *CascadeCorrelator
+	dependencies     DependencyQuerier // runs declared dependency checks; nil skips them
+	config           *core.ConfigStore
 }
 
-func NewEnhancedDetector(fe *FeatureExtractor) *EnhancedDetector {
+func NewEnhancedDetector(fe *FeatureExtractor, config *core.ConfigStore) *EnhancedDetector {
 	return &EnhancedDetector{
 		featureExtractor: fe,
+		cascade:          NewCascadeCorrelator(fe.db, fe.Resolver(), config),
+		config:           config,
+	}
+}
+
+// cfg returns the configuration the detectors consult (may be nil)
+func (ed *EnhancedDetector) cfg() *core.Config {
+	return ed.config.Get()
+}
+
+// window returns the feature window analyzer.windows sets for the named
+// detector
+func (ed *EnhancedDetector) window(detector string) time.Duration {
+	windows := core.DefaultDetectorWindows()
+	if cfg := ed.cfg(); cfg != nil {
+		windows = cfg.Analyzer.Windows.WithDefaults()
+	}
+	d, _ := time.ParseDuration(windows.For(detector))
+	return d
+}
+
+// dampening returns the confidence factors analyzer.dampening sets
+func (ed *EnhancedDetector) dampening() core.DampeningFactors {
+	if cfg := ed.cfg(); cfg != nil {
+		return cfg.Analyzer.Dampening.WithDefaults()
+	}
+	return core.DefaultDampeningFactors()
+}
+
+// dampener tracks the factors a detector's failed quality gates multiply its
+// confidence by, so the detection can report the confidence before them
+type dampener struct {
+	aggregate float64
+	factor    float64
+	reasons   []string
+}
+
+func newDampener(aggregate float64) *dampener {
+	return &dampener{aggregate: aggregate, factor: 1}
+}
+
+// apply dampens confidence by factor and records the gate that failed
+func (d *dampener) apply(confidence, factor float64, reason string) float64 {
+	d.factor *= factor
+	d.reasons = append(d.reasons, reason)
+	return confidence * factor
+}
+
+// annotate records the aggregate confidence and dampening on the detection
+func (d *dampener) annotate(det *Detection) *Detection {
+	det.AggregateConfidence = d.aggregate
+	det.Dampening = d.factor
+	det.DampeningReasons = d.reasons
+	return det
+}
+
+// windowLabel formats a window without zero trailing units: 30m, 1h30m, 45s
+func windowLabel(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// renormalizeSignals rescales the named weighted signals by scale. Detectors
+// use it when a metric is missing entirely: the signals that could be
+// measured then carry the weight of those that couldn't, instead of the
+// missing ones counting as a healthy zero.
+func renormalizeSignals(signals map[string]float64, names []string, scale float64) {
+	for _, name := range names {
+		if v, ok := signals[name]; ok {
+			signals[name] = v * scale
+		}
 	}
 }
 
 // DetectMemoryLeakEnhanced uses improved 6-signal approach with quality gating
 func (ed *EnhancedDetector) DetectMemoryLeakEnhanced(ctx context.Context, serviceName string) (*Detection, error) {
-	features, err := ed.featureExtractor.ExtractFeatures(ctx, serviceName, 30*time.Minute)
+	window := ed.window("memory_leak")
+	features, err := ed.featureExtractor.ExtractFeatures(ctx, serviceName, window)
 	if err != nil {
 		return nil, err
 	}
 
+	// Latency is only read when memory grows, for the GC bimodality signal
+	var bimodality *LatencyBimodality
+	if features.MemoryTrend > 0.05 {
+		b := ed.latencyBimodalityFor(ctx, serviceName, window)
+		bimodality = &b
+	}
+	memory, _ := ed.featureExtractor.Resolver().ResolveSeries(ctx, serviceName, MetricMemory, window)
+
+	det := ed.memoryLeak(ctx, serviceName, features, memory, bimodality)
+
+	logger.FromContext(ctx).Info("Memory leak detection complete",
+		zap.String("service", serviceName),
+		zap.Bool("detected", det.Detected),
+		zap.Float64("confidence", det.Confidence),
+		zap.Any("signal_quality", det.Evidence["signal_quality"]))
+
+	return det, nil
+}
+
+// memoryLeak scores a memory leak from the service's features and its memory
+// series over the detector's window
+func (ed *EnhancedDetector) memoryLeak(ctx context.Context, serviceName string, features *ServiceFeatures, memory []*storage.Metric, bimodality *LatencyBimodality) *Detection {
+	window := ed.window("memory_leak")
+	signals, signalQuality := memoryLeakSignals(features)
+
+	// Signal 7: GC pauses show as bimodal latency while memory grows, often
+	// before the memory level itself looks high (bonus)
+	if bimodality != nil && bimodality.Bimodal {
+		signals["gc_bimodality"] = 15.0
+		signalQuality++
+	}
+
+	// Aggregate confidence with quality gating
+	totalConfidence := 0.0
+	for _, conf := range signals {
+		totalConfidence += conf
+	}
+
+	// Multi-window confirmation: a single fit can be fooled by GC sawtooth, so
+	// the growth must also show up in at least 2 of the trailing thirds of the
+	// window (10/20/30m by default)
+	damping := ed.dampening()
+	damp := newDampener(totalConfidence)
+	windowSlopes, weightedSlope, trendConfirmed := memoryTrendConfirmation(ed.cfg(), memory, window)
+	if !trendConfirmed && features.MemoryTrend > 0 {
+		totalConfidence = damp.apply(totalConfidence, damping.UnconfirmedTrend, "unconfirmed_trend")
+	}
+
+	// IMPROVED: Require at least 2 high-quality signals AND minimum confidence
+	detected := totalConfidence > memoryLeakCutoff && signalQuality >= 2
+
+	// Dampen confidence if we have weak signals
+	if signalQuality < 2 {
+		totalConfidence = damp.apply(totalConfidence, damping.MemoryLeak, "weak_signals")
+	}
+
+	severity := SeverityNone
+	if detected {
+		if totalConfidence > 90 && signalQuality >= 3 && trendConfirmed {
+			severity = SeverityCritical
+		} else if totalConfidence > 80 {
+			severity = SeverityHigh
+		} else if totalConfidence > 70 {
+			severity = SeverityMedium
+		} else {
+			severity = SeverityLow
+		}
+	}
+
+	evidence := map[string]interface{}{
+		"window":                   windowLabel(features.Window),
+		"memory_trend":             quantity("%.4f%%/min", features.MemoryTrend),
+		"current_memory":           quantity("%.2f%%", features.MemoryMean),
+		"memory_range":             quantity("%.2f%%", features.MemoryRange),
+		"autocorrelation":          quantity("%.3f", features.MemoryAutocorrelation),
+		"volatility":               quantity("%.3f", features.MemoryVolatility),
+		"cpu_memory_corr":          quantity("%.3f", features.CPUMemoryCorr),
+		"cpu_memory_corr_strength": features.Correlations[CorrCPUMemory].Strength,
+		"signals":                  signals,
+		"signal_quality":           signalQuality,
+		"total_signals":            len(signals),
+		"quality_gate_pass":        signalQuality >= 2,
+		"window_slopes":            windowSlopes,
+		"weighted_slope":           quantity("%.4f%%/min", weightedSlope),
+		"trend_confirmed":          trendConfirmed,
+	}
+	addTransformEvidence(evidence, features)
+	if bimodality != nil {
+		evidence["latency_bimodality"] = bimodality
+	}
+
+	if detected {
+		if oom := ed.projectOOM(ctx, serviceName, features, window); oom != nil {
+			evidence["time_to_exhaustion_min"] = quantity("%.1f", oom.MinutesToOOM)
+			evidence["estimated_oom"] = oom.EstimatedOOM.Format(time.RFC3339)
+			evidence["oom_basis"] = oom.Basis
+			evidence["oom_projection"] = oom
+		}
+	}
+
+	recommendation := "No action required"
+	if detected {
+		switch severity {
+		case SeverityCritical:
+			recommendation = "🚨 IMMEDIATE: Memory leak confirmed. Restart pod or increase memory limit NOW."
+		case SeverityHigh:
+			recommendation = "⚠️  URGENT: Strong memory leak indicators. Monitor closely and prepare restart."
+		default:
+			recommendation = "📊 Possible memory leak pattern. Continue monitoring for 15 more minutes."
+		}
+	}
+
+	return damp.annotate(&Detection{
+		Type:           DetectionMemoryLeak,
+		ServiceName:    serviceName,
+		Detected:       detected,
+		Confidence:     totalConfidence,
+		Severity:       severity,
+		Evidence:       evidence,
+		Recommendation: recommendation,
+		Timestamp:      time.Now(),
+	})
+}
+
+// memoryLeakSignals scores signals 1-6 of DetectMemoryLeakEnhanced from the
+// features alone and counts the high-quality ones among them
+func memoryLeakSignals(features *ServiceFeatures) (map[string]float64, int) {
 	signals := make(map[string]float64)
 	signalQuality := 0 // Count of high-quality signals
 
@@ -84,99 +305,101 @@ func (ed *EnhancedDetector) DetectMemoryLeakEnhanced(ctx context.Context, servic
 
 	// NEW Signal 6: Cross-validation - NO correlation with CPU (bonus)
 	// Real memory leaks don't correlate with CPU usage
-	if math.Abs(features.CPUMemoryCorr) < 0.3 && features.MemoryTrend > 0.1 {
+	// Skipped when there were too few samples to tell "no correlation" from "no data"
+	if features.CorrelationKnown(CorrCPUMemory) && math.Abs(features.CPUMemoryCorr) < 0.3 && features.MemoryTrend > 0.1 {
 		signals["independent_growth"] = 15.0 // Bonus signal
 		signalQuality++
 		logger.Debug("Memory leak signal: independent growth detected",
 			zap.Float64("cpu_memory_corr", features.CPUMemoryCorr))
 	}
 
-	// Aggregate confidence with quality gating
-	totalConfidence := 0.0
-	for _, conf := range signals {
-		totalConfidence += conf
-	}
+	return signals, signalQuality
+}
 
-	// IMPROVED: Require at least 2 high-quality signals AND minimum confidence
-	detected := totalConfidence > 65 && signalQuality >= 2
+// memoryConfirmationWindows are the trailing sub-windows checked for sustained
+// growth, as thirds of the detector's window, weighted toward the longer spans
+// which are less sensitive to GC cycles
+var memoryConfirmationWindows = []struct {
+	thirds int
+	weight float64
+}{
+	{1, 0.2},
+	{2, 0.3},
+	{3, 0.5},
+}
 
-	// Dampen confidence if we have weak signals
-	if signalQuality < 2 {
-		totalConfidence *= 0.7 // Reduce confidence by 30%
-	}
+// memoryTrendConfirmation fits a regression per sub-window (ending at the
+// newest sample) and reports whether at least two of them agree on a positive
+// slope. Slopes are keyed by sub-window length.
+func memoryTrendConfirmation(cfg *core.Config, metrics []*storage.Metric, window time.Duration) (map[string]float64, float64, bool) {
+	slopes := make(map[string]float64)
+	if len(metrics) < 3 {
+		return slopes, 0, false
+	}
+
+	end := metrics[len(metrics)-1].Timestamp
+	positive := 0
+	weightedSlope := 0.0
+	totalWeight := 0.0
+
+	for _, w := range memoryConfirmationWindows {
+		span := window * time.Duration(w.thirds) / 3
+		start := end.Add(-span)
+		windowed := make([]*storage.Metric, 0, len(metrics))
+		for _, m := range metrics {
+			if !m.Timestamp.Before(start) {
+				windowed = append(windowed, m)
+			}
+		}
+		if len(windowed) < 3 {
+			continue
+		}
 
-	severity := SeverityNone
-	if detected {
-		if totalConfidence > 90 && signalQuality >= 3 {
-			severity = SeverityCritical
-		} else if totalConfidence > 80 {
-			severity = SeverityHigh
-		} else if totalConfidence > 70 {
-			severity = SeverityMedium
-		} else {
-			severity = SeverityLow
+		slope := trendSlope(cfg, windowed)
+		slopes[windowLabel(span)] = slope
+		weightedSlope += slope * w.weight
+		totalWeight += w.weight
+		if slope > 0.05 {
+			positive++
 		}
 	}
 
-	evidence := map[string]interface{}{
-		"memory_trend":      fmt.Sprintf("%.4f%%/min", features.MemoryTrend),
-		"current_memory":    fmt.Sprintf("%.2f%%", features.MemoryMean),
-		"memory_range":      fmt.Sprintf("%.2f%%", features.MemoryRange),
-		"autocorrelation":   fmt.Sprintf("%.3f", features.MemoryAutocorrelation),
-		"volatility":        fmt.Sprintf("%.3f", features.MemoryVolatility),
-		"cpu_memory_corr":   fmt.Sprintf("%.3f", features.CPUMemoryCorr),
-		"signals":           signals,
-		"signal_quality":    signalQuality,
-		"total_signals":     len(signals),
-		"quality_gate_pass": signalQuality >= 2,
+	if totalWeight > 0 {
+		weightedSlope /= totalWeight
 	}
 
-	if detected && features.MemoryTrend > 0 {
-		remainingCapacity := 100 - features.MemoryMean
-		minutesToFull := remainingCapacity / features.MemoryTrend
-		evidence["time_to_exhaustion_min"] = fmt.Sprintf("%.1f", minutesToFull)
-		evidence["estimated_oom"] = time.Now().Add(time.Duration(minutesToFull) * time.Minute).Format(time.RFC3339)
-	}
+	return slopes, weightedSlope, positive >= 2 && weightedSlope > 0
+}
 
-	recommendation := "No action required"
-	if detected {
-		switch severity {
-		case SeverityCritical:
-			recommendation = "🚨 IMMEDIATE: Memory leak confirmed. Restart pod or increase memory limit NOW."
-		case SeverityHigh:
-			recommendation = "⚠️  URGENT: Strong memory leak indicators. Monitor closely and prepare restart."
-		default:
-			recommendation = "📊 Possible memory leak pattern. Continue monitoring for 15 more minutes."
-		}
+// percentileSource reports where the latency percentiles in the features came from
+func percentileSource(features *ServiceFeatures) string {
+	if features.HistogramPercentiles {
+		return "histogram"
 	}
-
-	logger.Info("Memory leak detection complete",
-		zap.String("service", serviceName),
-		zap.Bool("detected", detected),
-		zap.Float64("confidence", totalConfidence),
-		zap.Int("signal_quality", signalQuality))
-
-	return &Detection{
-		Type:           DetectionMemoryLeak,
-		ServiceName:    serviceName,
-		Detected:       detected,
-		Confidence:     totalConfidence,
-		Severity:       severity,
-		Evidence:       evidence,
-		Recommendation: recommendation,
-		Timestamp:      time.Now(),
-	}, nil
+	return "samples"
 }
 
 // DetectResourceExhaustionEnhanced with improved thresholds
 func (ed *EnhancedDetector) DetectResourceExhaustionEnhanced(ctx context.Context, serviceName string) (*Detection, error) {
-	features, err := ed.featureExtractor.ExtractFeatures(ctx, serviceName, 15*time.Minute)
+	features, err := ed.featureExtractor.ExtractFeatures(ctx, serviceName, ed.window("resource_exhaustion"))
 	if err != nil {
 		return nil, err
 	}
+	det := ed.resourceExhaustion(serviceName, features)
 
+	logger.FromContext(ctx).Info("Resource exhaustion detection complete",
+		zap.String("service", serviceName),
+		zap.Bool("detected", det.Detected),
+		zap.Float64("confidence", det.Confidence),
+		zap.Any("both_resources_high", det.Evidence["both_high"]))
+
+	return det, nil
+}
+
+// resourceExhaustion scores resource exhaustion from the service's features
+func (ed *EnhancedDetector) resourceExhaustion(serviceName string, features *ServiceFeatures) *Detection {
 	signals := make(map[string]float64)
-	signalQuality := 0
+	signalQuality := 0 // Count of high-quality signals
 
 	// Signal 1: High CPU (30% weight)
 	// IMPROVED: Require sustained high CPU (> 80%)
@@ -200,10 +423,10 @@ func (ed *EnhancedDetector) DetectResourceExhaustionEnhanced(ctx context.Context
 
 	// Signal 3: Rising errors (25% weight)
 	// IMPROVED: Require error rate AND error trend
-	if features.ErrorRateMean > 8 || features.ErrorRateTrend > 2 {
-		errorScore := math.Min((features.ErrorRateMean/15)*100, 100) * 0.25
+	if features.ScoredErrorRate > 8 || features.ErrorRateTrend > 2 {
+		errorScore := math.Min((features.ScoredErrorRate/15)*100, 100) * 0.25
 		signals["errors"] = errorScore
-		if features.ErrorRateMean > 15 {
+		if features.ScoredErrorRate > 15 {
 			signalQuality++
 		}
 	}
@@ -225,21 +448,48 @@ func (ed *EnhancedDetector) DetectResourceExhaustionEnhanced(ctx context.Context
 		signalQuality++
 	}
 
+	// Services opted out of the both-resources rule (e.g. CPU-bound batch jobs)
+	// may fire on one resource alone, but only above a stricter threshold
+	thresholds := ed.cfg().ThresholdsFor(serviceName)
+	requireBoth := thresholds.BothResourcesRequired()
+	singleLimit := thresholds.SingleResourceLimit()
+	saturatedResource := ""
+	saturatedValue := 0.0
+	if !requireBoth {
+		if features.CPUMean > singleLimit && features.CPUMean >= features.MemoryMean {
+			saturatedResource, saturatedValue = "cpu", features.CPUMean
+		} else if features.MemoryMean > singleLimit {
+			saturatedResource, saturatedValue = "memory", features.MemoryMean
+		}
+	}
+	singleSaturated := saturatedResource != ""
+	if singleSaturated && !bothHigh {
+		signals["single_resource_saturated"] = 20.0 // Bonus
+		signalQuality++
+	}
+
 	totalConfidence := 0.0
 	for _, conf := range signals {
 		totalConfidence += conf
 	}
 
 	// IMPROVED: Higher threshold and require quality signals
-	detected := totalConfidence > 60 && (signalQuality >= 2 || bothHigh)
+	detected := totalConfidence > resourceExhaustionCutoff && (signalQuality >= 2 || bothHigh)
+
+	damp := newDampener(totalConfidence)
+	if signalQuality < 2 && !bothHigh && !singleSaturated {
+		totalConfidence = damp.apply(totalConfidence, ed.dampening().ResourceExhaustion, "weak_signals")
+	}
 
-	if signalQuality < 2 && !bothHigh {
-		totalConfidence *= 0.75
+	// A sustained single-resource saturation is a finding on its own
+	if singleSaturated && !detected {
+		detected = true
+		totalConfidence = math.Max(totalConfidence, 65)
 	}
 
 	severity := SeverityNone
 	if detected {
-		if totalConfidence > 85 && bothHigh {
+		if totalConfidence > 85 && (bothHigh || saturatedValue >= 98) {
 			severity = SeverityCritical
 		} else if totalConfidence > 75 {
 			severity = SeverityHigh
@@ -251,21 +501,32 @@ func (ed *EnhancedDetector) DetectResourceExhaustionEnhanced(ctx context.Context
 	}
 
 	evidence := map[string]interface{}{
-		"cpu_usage":      fmt.Sprintf("%.2f%%", features.CPUMean),
-		"memory_usage":   fmt.Sprintf("%.2f%%", features.MemoryMean),
-		"error_rate":     fmt.Sprintf("%.2f/min", features.ErrorRateMean),
-		"system_stress":  fmt.Sprintf("%.2f/100", features.SystemStress),
-		"health_score":   fmt.Sprintf("%.2f/100", features.HealthScore),
+		"window":         windowLabel(features.Window),
+		"cpu_usage":      quantity("%.2f%%", features.CPUMean),
+		"memory_usage":   quantity("%.2f%%", features.MemoryMean),
+		"error_rate":     quantity("%.2f/min", features.ErrorRateMean),
+		"system_stress":  quantity("%.2f/100", features.SystemStress),
+		"health_score":   quantity("%.2f/100", features.HealthScore),
 		"both_high":      bothHigh,
 		"signals":        signals,
 		"signal_quality": signalQuality,
+		// How the both-resources rule was applied for this service
+		"require_both_resources":    requireBoth,
+		"single_resource_threshold": quantity("%.0f%%", singleLimit),
+		"single_resource_saturated": saturatedResource,
+		"single_resource_detection": singleSaturated && !bothHigh,
 	}
+	addErrorEvidence(evidence, features)
+	addTransformEvidence(evidence, features)
 
 	recommendation := "No action required"
 	if detected {
 		switch severity {
 		case SeverityCritical:
 			recommendation = "🚨 CRITICAL: Both CPU and Memory exhausted. Scale up immediately or increase limits."
+			if !bothHigh {
+				recommendation = fmt.Sprintf("🚨 CRITICAL: %s saturated. Scale up immediately or increase limits.", strings.ToUpper(saturatedResource))
+			}
 		case SeverityHigh:
 			recommendation = "⚠️  Scale horizontally (add replicas) or vertically (increase limits) soon."
 		default:
@@ -273,13 +534,7 @@ func (ed *EnhancedDetector) DetectResourceExhaustionEnhanced(ctx context.Context
 		}
 	}
 
-	logger.Info("Resource exhaustion detection complete",
-		zap.String("service", serviceName),
-		zap.Bool("detected", detected),
-		zap.Float64("confidence", totalConfidence),
-		zap.Bool("both_resources_high", bothHigh))
-
-	return &Detection{
+	return damp.annotate(&Detection{
 		Type:           DetectionResourceExhaustion,
 		ServiceName:    serviceName,
 		Detected:       detected,
@@ -288,22 +543,22 @@ func (ed *EnhancedDetector) DetectResourceExhaustionEnhanced(ctx context.Context
 		Evidence:       evidence,
 		Recommendation: recommendation,
 		Timestamp:      time.Now(),
-	}, nil
+	})
 }
 
 // DetectDeploymentBugEnhanced with better correlation analysis
 func (ed *EnhancedDetector) DetectDeploymentBugEnhanced(ctx context.Context, serviceName string) (*Detection, error) {
-	features, err := ed.featureExtractor.ExtractFeatures(ctx, serviceName, 20*time.Minute)
+	features, err := ed.featureExtractor.ExtractFeatures(ctx, serviceName, ed.window("deployment_bug"))
 	if err != nil {
 		return nil, err
 	}
 
 	signals := make(map[string]float64)
-	signalQuality := 0
+	signalQuality := 0 // Count of high-quality signals
 
 	// Signal 1: Sudden error spike (40% weight)
 	// IMPROVED: Require BOTH high spikiness AND high error rate
-	if features.ErrorRateSpikiness > 2.0 && features.ErrorRateMean > 5 {
+	if features.ErrorRateSpikiness > 2.0 && features.ScoredErrorRate > 5 {
 		spikeScore := math.Min(features.ErrorRateSpikiness*20, 100) * 0.40
 		signals["error_spike"] = spikeScore
 		if features.ErrorRateSpikiness > 3.0 {
@@ -312,17 +567,17 @@ func (ed *EnhancedDetector) DetectDeploymentBugEnhanced(ctx context.Context, ser
 	}
 
 	// Signal 2: High error rate (25% weight)
-	if features.ErrorRateMean > 15 {
-		rateScore := math.Min((features.ErrorRateMean/40)*100, 100) * 0.25
+	if features.ScoredErrorRate > 15 {
+		rateScore := math.Min((features.ScoredErrorRate/40)*100, 100) * 0.25
 		signals["error_rate"] = rateScore
-		if features.ErrorRateMean > 25 {
+		if features.ScoredErrorRate > 25 {
 			signalQuality++
 		}
 	}
 
 	// Signal 3: Errors independent of load (20% weight)
 	// IMPROVED: Stricter threshold for independence
-	if math.Abs(features.CPUErrorCorr) < 0.25 && features.ErrorRateMean > 10 {
+	if features.CorrelationKnown(CorrCPUError) && math.Abs(features.CPUErrorCorr) < 0.25 && features.ScoredErrorRate > 10 {
 		indepScore := (1 - math.Abs(features.CPUErrorCorr)) * 100 * 0.20
 		signals["independent_errors"] = indepScore
 		signalQuality++
@@ -337,7 +592,7 @@ func (ed *EnhancedDetector) DetectDeploymentBugEnhanced(ctx context.Context, ser
 	// NEW: Cross-validate with resource usage
 	// Deployment bugs typically DON'T cause high resource usage
 	normalResources := features.CPUMean < 70 && features.MemoryMean < 70
-	if normalResources && features.ErrorRateMean > 10 {
+	if normalResources && features.ScoredErrorRate > 10 {
 		signals["normal_resources_high_errors"] = 15.0 // Bonus
 		signalQuality++
 	}
@@ -348,10 +603,11 @@ func (ed *EnhancedDetector) DetectDeploymentBugEnhanced(ctx context.Context, ser
 	}
 
 	// IMPROVED: Require minimum signal quality
-	detected := totalConfidence > 55 && signalQuality >= 2
+	detected := totalConfidence > deploymentBugCutoff && signalQuality >= 2
 
+	damp := newDampener(totalConfidence)
 	if signalQuality < 2 {
-		totalConfidence *= 0.70
+		totalConfidence = damp.apply(totalConfidence, ed.dampening().DeploymentBug, "weak_signals")
 	}
 
 	severity := SeverityNone
@@ -366,16 +622,20 @@ func (ed *EnhancedDetector) DetectDeploymentBugEnhanced(ctx context.Context, ser
 	}
 
 	evidence := map[string]interface{}{
-		"error_rate":       fmt.Sprintf("%.2f/min", features.ErrorRateMean),
-		"error_spikiness":  fmt.Sprintf("%.2f", features.ErrorRateSpikiness),
-		"cpu_error_corr":   fmt.Sprintf("%.3f", features.CPUErrorCorr),
-		"stability_index":  fmt.Sprintf("%.2f/10", features.StabilityIndex),
-		"cpu_mean":         fmt.Sprintf("%.2f%%", features.CPUMean),
-		"memory_mean":      fmt.Sprintf("%.2f%%", features.MemoryMean),
-		"normal_resources": normalResources,
-		"signals":          signals,
-		"signal_quality":   signalQuality,
-	}
+		"window":                  windowLabel(features.Window),
+		"error_rate":              quantity("%.2f/min", features.ErrorRateMean),
+		"error_spikiness":         quantity("%.2f", features.ErrorRateSpikiness),
+		"cpu_error_corr":          quantity("%.3f", features.CPUErrorCorr),
+		"cpu_error_corr_strength": features.Correlations[CorrCPUError].Strength,
+		"stability_index":         quantity("%.2f/10", features.StabilityIndex),
+		"cpu_mean":                quantity("%.2f%%", features.CPUMean),
+		"memory_mean":             quantity("%.2f%%", features.MemoryMean),
+		"normal_resources":        normalResources,
+		"signals":                 signals,
+		"signal_quality":          signalQuality,
+	}
+	addErrorEvidence(evidence, features)
+	addTransformEvidence(evidence, features)
 
 	recommendation := "No action required"
 	if detected {
@@ -389,13 +649,13 @@ func (ed *EnhancedDetector) DetectDeploymentBugEnhanced(ctx context.Context, ser
 		}
 	}
 
-	logger.Info("Deployment bug detection complete",
+	logger.FromContext(ctx).Info("Deployment bug detection complete",
 		zap.String("service", serviceName),
 		zap.Bool("detected", detected),
 		zap.Float64("confidence", totalConfidence),
 		zap.Bool("normal_resources", normalResources))
 
-	return &Detection{
+	return damp.annotate(&Detection{
 		Type:           DetectionDeploymentBug,
 		ServiceName:    serviceName,
 		Detected:       detected,
@@ -404,25 +664,67 @@ func (ed *EnhancedDetector) DetectDeploymentBugEnhanced(ctx context.Context, ser
 		Evidence:       evidence,
 		Recommendation: recommendation,
 		Timestamp:      time.Now(),
-	}, nil
+	}), nil
+}
+
+// P99-to-SLO ratios at which a breached latency SLO reaches each severity
+const (
+	sloMediumRatio   = 1.5
+	sloHighRatio     = 3.0
+	sloCriticalRatio = 5.0
+)
+
+// sloSeverity grades P99 latency as a multiple of the SLO target
+func sloSeverity(ratio float64) string {
+	switch {
+	case ratio >= sloCriticalRatio:
+		return SeverityCritical
+	case ratio >= sloHighRatio:
+		return SeverityHigh
+	case ratio >= sloMediumRatio:
+		return SeverityMedium
+	case ratio > 1:
+		return SeverityLow
+	default:
+		return SeverityNone
+	}
+}
+
+// externalLatencySignal scores P99 latency for external failure detection:
+// against the SLO when one is set (sloRatio is P99 / SLO), otherwise against
+// the fixed 3000ms cutoff. strong reports a high-quality signal.
+func externalLatencySignal(p99, sloMs float64) (score, sloRatio float64, strong bool) {
+	if sloMs > 0 {
+		sloRatio = p99 / sloMs
+		if sloRatio <= 1 {
+			return 0, sloRatio, false
+		}
+		return math.Min((sloRatio-1)/(sloCriticalRatio-1)*100, 100) * 0.35, sloRatio, sloRatio >= sloMediumRatio
+	}
+	if p99 <= 3000 {
+		return 0, 0, false
+	}
+	return math.Min((p99-3000)/10000*100, 100) * 0.35, 0, p99 > 5000
 }
 
 // DetectExternalFailureEnhanced with better pattern matching
 func (ed *EnhancedDetector) DetectExternalFailureEnhanced(ctx context.Context, serviceName string) (*Detection, error) {
-	features, err := ed.featureExtractor.ExtractFeatures(ctx, serviceName, 15*time.Minute)
+	features, err := ed.featureExtractor.ExtractFeatures(ctx, serviceName, ed.window("external_failure"))
 	if err != nil {
 		return nil, err
 	}
 
 	signals := make(map[string]float64)
-	signalQuality := 0
+	signalQuality := 0 // Count of high-quality signals
 
 	// Signal 1: High latency (35% weight)
-	// IMPROVED: Use P99 instead of P95 for external failures
-	if features.LatencyP99 > 3000 {
-		latencyScore := math.Min((features.LatencyP99-3000)/10000*100, 100) * 0.35
+	// IMPROVED: Use P99 instead of P95 for external failures. Services with a
+	// latency SLO are judged against it rather than the absolute cutoffs.
+	sloMs := ed.cfg().ThresholdsFor(serviceName).LatencySLOMs
+	latencyScore, sloRatio, strongLatency := externalLatencySignal(features.LatencyP99, sloMs)
+	if latencyScore > 0 {
 		signals["latency"] = latencyScore
-		if features.LatencyP99 > 5000 {
+		if strongLatency {
 			signalQuality++
 		}
 	}
@@ -437,8 +739,8 @@ func (ed *EnhancedDetector) DetectExternalFailureEnhanced(ctx context.Context, s
 
 	// Signal 3: High errors with LOW internal resource usage (20% weight)
 	// IMPROVED: This is the KEY signal for external failures
-	if features.ErrorRateMean > 10 && features.CPUMean < 65 && features.MemoryMean < 70 {
-		externalScore := (features.ErrorRateMean / 30) * 100 * 0.20
+	if features.ScoredErrorRate > 10 && features.CPUMean < 65 && features.MemoryMean < 70 {
+		externalScore := (features.ScoredErrorRate / 30) * 100 * 0.20
 		signals["external_pattern"] = externalScore
 		signalQuality++
 	}
@@ -451,49 +753,92 @@ func (ed *EnhancedDetector) DetectExternalFailureEnhanced(ctx context.Context, s
 
 	// NEW: Cross-validation - Memory-Error correlation should be LOW
 	// External failures don't correlate with memory
-	if math.Abs(features.MemoryErrorCorr) < 0.3 && features.ErrorRateMean > 8 {
+	if features.CorrelationKnown(CorrMemoryError) && math.Abs(features.MemoryErrorCorr) < 0.3 && features.ScoredErrorRate > 8 {
 		signals["no_memory_correlation"] = 10.0 // Bonus
 		signalQuality++
 	}
 
+	// Without latency samples, latency and its error correlation (65% of the
+	// weight) can't be measured. Re-normalize the remaining 35% so "no
+	// latency data" doesn't read as "no external failure".
+	dataSufficiency := DataSufficiencyFull
+	if !features.HasMetric(MetricLatency) {
+		dataSufficiency = DataSufficiencyPartial
+		renormalizeSignals(signals, []string{"external_pattern", "error_spikes"}, 1/0.35)
+	}
+
+	// Signal 5: A declared dependency's own health check is failing. This is
+	// direct evidence, so it stands in for the inferred external pattern;
+	// the service still needs symptoms of its own to be diagnosed.
+	dependencyChecks := ed.checkDependencies(ctx, serviceName)
+	unhealthy := unhealthyDependencies(dependencyChecks)
+	if len(unhealthy) > 0 {
+		signals["dependency_unhealthy"] = 35.0
+		signalQuality++
+	}
+
 	totalConfidence := 0.0
 	for _, conf := range signals {
 		totalConfidence += conf
 	}
 
 	// IMPROVED: Require the "external pattern" signal for detection
-	hasExternalPattern := features.ErrorRateMean > 10 && features.CPUMean < 65
-	detected := totalConfidence > 55 && (hasExternalPattern || signalQuality >= 3)
+	hasExternalPattern := features.ScoredErrorRate > 10 && features.CPUMean < 65
+	directEvidence := len(unhealthy) > 0
+	detected := totalConfidence > externalFailureCutoff && (hasExternalPattern || directEvidence || signalQuality >= 3)
 
-	if !hasExternalPattern && signalQuality < 3 {
-		totalConfidence *= 0.65
+	damp := newDampener(totalConfidence)
+	if !hasExternalPattern && !directEvidence && signalQuality < 3 {
+		totalConfidence = damp.apply(totalConfidence, ed.dampening().ExternalFailure, "no_external_pattern")
 	}
 
 	severity := SeverityNone
 	if detected {
-		if totalConfidence > 85 && hasExternalPattern {
+		if totalConfidence > 85 && (hasExternalPattern || directEvidence) {
 			severity = SeverityCritical
 		} else if totalConfidence > 75 {
 			severity = SeverityHigh
 		} else {
 			severity = SeverityMedium
 		}
+		// A breached SLO sets severity by how far P99 is over the target
+		if sloRatio > 1 {
+			severity = sloSeverity(sloRatio)
+		}
 	}
 
 	evidence := map[string]interface{}{
-		"latency_p99":        fmt.Sprintf("%.2fms", features.LatencyP99),
-		"latency_p95":        fmt.Sprintf("%.2fms", features.LatencyP95),
-		"error_rate":         fmt.Sprintf("%.2f/min", features.ErrorRateMean),
-		"latency_error_corr": fmt.Sprintf("%.3f", features.LatencyErrorCorr),
-		"cpu_usage":          fmt.Sprintf("%.2f%%", features.CPUMean),
-		"memory_usage":       fmt.Sprintf("%.2f%%", features.MemoryMean),
-		"external_pattern":   hasExternalPattern,
-		"signals":            signals,
-		"signal_quality":     signalQuality,
+		"window":                      windowLabel(features.Window),
+		"latency_p99":                 quantity("%.2fms", features.LatencyP99),
+		"latency_p95":                 quantity("%.2fms", features.LatencyP95),
+		"error_rate":                  quantity("%.2f/min", features.ErrorRateMean),
+		"latency_error_corr":          quantity("%.3f", features.LatencyErrorCorr),
+		"latency_error_corr_strength": features.Correlations[CorrLatencyError].Strength,
+		"cpu_usage":                   quantity("%.2f%%", features.CPUMean),
+		"memory_usage":                quantity("%.2f%%", features.MemoryMean),
+		"external_pattern":            hasExternalPattern,
+		"percentile_source":           percentileSource(features),
+		"signals":                     signals,
+		"signal_quality":              signalQuality,
+	}
+	addErrorEvidence(evidence, features)
+	addTransformEvidence(evidence, features)
+	if sloMs > 0 {
+		evidence["latency_slo_ms"] = sloMs
+		evidence["latency_slo_ratio"] = quantity("%.2f", sloRatio)
+	}
+	if dependencyChecks != nil {
+		evidence["dependency_checks"] = dependencyChecks
+		evidence["unhealthy_dependencies"] = unhealthy
+	}
+	if len(features.MissingMetrics) > 0 {
+		evidence["missing_metrics"] = features.MissingMetrics
 	}
 
 	recommendation := "No action required"
-	if detected {
+	if detected && directEvidence {
+		recommendation = fmt.Sprintf("🚨 Dependency health check failing: %s. Restore the dependency or fail over; enable fallbacks meanwhile.", strings.Join(unhealthy, ", "))
+	} else if detected {
 		switch severity {
 		case SeverityCritical:
 			recommendation = "🚨 External dependency failure detected. Check databases, APIs, and network. Enable fallbacks."
@@ -504,33 +849,35 @@ func (ed *EnhancedDetector) DetectExternalFailureEnhanced(ctx context.Context, s
 		}
 	}
 
-	logger.Info("External failure detection complete",
+	logger.FromContext(ctx).Info("External failure detection complete",
 		zap.String("service", serviceName),
 		zap.Bool("detected", detected),
 		zap.Float64("confidence", totalConfidence),
 		zap.Bool("external_pattern", hasExternalPattern))
 
-	return &Detection{
-		Type:           DetectionExternalFailure,
-		ServiceName:    serviceName,
-		Detected:       detected,
-		Confidence:     totalConfidence,
-		Severity:       severity,
-		Evidence:       evidence,
-		Recommendation: recommendation,
-		Timestamp:      time.Now(),
-	}, nil
+	return damp.annotate(&Detection{
+		Type:            DetectionExternalFailure,
+		ServiceName:     serviceName,
+		Detected:        detected,
+		Confidence:      totalConfidence,
+		Severity:        severity,
+		Evidence:        evidence,
+		Recommendation:  recommendation,
+		Timestamp:       time.Now(),
+		DataSufficiency: dataSufficiency,
+	}), nil
 }
 
 // DetectCascadeFailureEnhanced with system-wide analysis
 func (ed *EnhancedDetector) DetectCascadeFailureEnhanced(ctx context.Context, serviceName string) (*Detection, error) {
-	features, err := ed.featureExtractor.ExtractFeatures(ctx, serviceName, 20*time.Minute)
+	window := ed.window("cascade_failure")
+	features, err := ed.featureExtractor.ExtractFeatures(ctx, serviceName, window)
 	if err != nil {
 		return nil, err
 	}
 
 	signals := make(map[string]float64)
-	signalQuality := 0
+	signalQuality := 0 // Count of high-quality signals
 
 	// Signal 1: Multiple resource degradation (35% weight)
 	// IMPROVED: Count degraded resources more carefully
@@ -545,9 +892,9 @@ func (ed *EnhancedDetector) DetectCascadeFailureEnhanced(ctx context.Context, se
 		degradedCount++
 		degradationSeverity += (features.MemoryMean - 88) / 12
 	}
-	if features.ErrorRateMean > 15 {
+	if features.ScoredErrorRate > 15 {
 		degradedCount++
-		degradationSeverity += features.ErrorRateMean / 50
+		degradationSeverity += features.ScoredErrorRate / 50
 	}
 	if features.LatencyP95 > 2000 {
 		degradedCount++
@@ -603,16 +950,54 @@ func (ed *EnhancedDetector) DetectCascadeFailureEnhanced(ctx context.Context, se
 		}
 	}
 
+	// Errors moving together with other services (10% weight). Only a bounded
+	// candidate set is checked, see CascadeCorrelator.
+	links, err := ed.cascade.Correlate(ctx, serviceName, window)
+	if err != nil {
+		logger.FromContext(ctx).Debug("Cascade candidate correlation failed", zap.String("service", serviceName), zap.Error(err))
+	}
+	correlatedServices := make([]string, 0, len(links))
+	for _, link := range links {
+		if link.Correlation.Coefficient >= 0.7 {
+			correlatedServices = append(correlatedServices, link.Service)
+		}
+	}
+	if len(correlatedServices) > 0 {
+		signals["correlated_services"] = math.Min(float64(len(correlatedServices))/2, 1) * 100 * 0.10
+		signalQuality++
+	}
+
+	// Latency rising intervals before errors follow is the signature of a
+	// slow dependency propagating downstream (10% weight)
+	propagation := features.Correlations[CorrLatencyError]
+	propagating := propagation.Lag > 0 && propagation.LagCoefficient >= 0.7
+	if propagating {
+		signals["propagation"] = propagation.LagCoefficient * 100 * 0.10
+		signalQuality++
+	}
+
+	// Without latency samples propagation can't be measured and latency
+	// can't count as a degraded metric. Re-normalize the other weights
+	// (130% in total) over the 120% that remains.
+	dataSufficiency := DataSufficiencyFull
+	if !features.HasMetric(MetricLatency) {
+		dataSufficiency = DataSufficiencyPartial
+		renormalizeSignals(signals, []string{
+			"multi_degradation", "system_stress", "health", "trends", "instability", "correlated_services",
+		}, 1.30/1.20)
+	}
+
 	totalConfidence := 0.0
 	for _, conf := range signals {
 		totalConfidence += conf
 	}
 
 	// IMPROVED: Require multiple degraded resources AND quality signals
-	detected := totalConfidence > 60 && degradedCount >= 3 && signalQuality >= 2
+	detected := totalConfidence > cascadeFailureCutoff && degradedCount >= 3 && signalQuality >= 2
 
+	damp := newDampener(totalConfidence)
 	if degradedCount < 3 || signalQuality < 2 {
-		totalConfidence *= 0.75
+		totalConfidence = damp.apply(totalConfidence, ed.dampening().CascadeFailure, "weak_signals")
 	}
 
 	severity := SeverityNone
@@ -627,16 +1012,27 @@ func (ed *EnhancedDetector) DetectCascadeFailureEnhanced(ctx context.Context, se
 	}
 
 	evidence := map[string]interface{}{
-		"degraded_metrics": degradedCount,
-		"system_stress":    fmt.Sprintf("%.2f/100", features.SystemStress),
-		"health_score":     fmt.Sprintf("%.2f/100", features.HealthScore),
-		"stability_index":  fmt.Sprintf("%.2f/10", features.StabilityIndex),
-		"cpu_trend":        fmt.Sprintf("%.4f%%/min", features.CPUTrend),
-		"memory_trend":     fmt.Sprintf("%.4f%%/min", features.MemoryTrend),
-		"error_trend":      fmt.Sprintf("%.4f/min", features.ErrorRateTrend),
-		"trending_metrics": trendCount,
-		"signals":          signals,
-		"signal_quality":   signalQuality,
+		"window":              windowLabel(features.Window),
+		"degraded_metrics":    degradedCount,
+		"system_stress":       quantity("%.2f/100", features.SystemStress),
+		"health_score":        quantity("%.2f/100", features.HealthScore),
+		"stability_index":     quantity("%.2f/10", features.StabilityIndex),
+		"cpu_trend":           quantity("%.4f%%/min", features.CPUTrend),
+		"memory_trend":        quantity("%.4f%%/min", features.MemoryTrend),
+		"error_trend":         quantity("%.4f/min", features.ErrorRateTrend),
+		"trending_metrics":    trendCount,
+		"signals":             signals,
+		"signal_quality":      signalQuality,
+		"cascade_links":       links,
+		"correlated_services": correlatedServices,
+		"propagation_lag":     propagation.Lag,
+		"propagation_lag_r":   propagation.LagCoefficient,
+		"propagating":         propagating,
+	}
+	addErrorEvidence(evidence, features)
+	addTransformEvidence(evidence, features)
+	if len(features.MissingMetrics) > 0 {
+		evidence["missing_metrics"] = features.MissingMetrics
 	}
 
 	recommendation := "No action required"
@@ -651,21 +1047,22 @@ func (ed *EnhancedDetector) DetectCascadeFailureEnhanced(ctx context.Context, se
 		}
 	}
 
-	logger.Info("Cascade failure detection complete",
+	logger.FromContext(ctx).Info("Cascade failure detection complete",
 		zap.String("service", serviceName),
 		zap.Bool("detected", detected),
 		zap.Float64("confidence", totalConfidence),
 		zap.Int("degraded_count", degradedCount),
 		zap.Int("signal_quality", signalQuality))
 
-	return &Detection{
-		Type:           DetectionCascadingFailure,
-		ServiceName:    serviceName,
-		Detected:       detected,
-		Confidence:     totalConfidence,
-		Severity:       severity,
-		Evidence:       evidence,
-		Recommendation: recommendation,
-		Timestamp:      time.Now(),
-	}, nil
+	return damp.annotate(&Detection{
+		Type:            DetectionCascadingFailure,
+		ServiceName:     serviceName,
+		Detected:        detected,
+		Confidence:      totalConfidence,
+		Severity:        severity,
+		Evidence:        evidence,
+		Recommendation:  recommendation,
+		Timestamp:       time.Now(),
+		DataSufficiency: dataSufficiency,
+	}), nil
 }
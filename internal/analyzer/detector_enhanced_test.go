@@ -0,0 +1,159 @@
+package analyzer
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+func TestMemoryTrendConfirmation(t *testing.T) {
+	const window = 30 * time.Minute
+
+	tests := []struct {
+		name   string
+		values []float64
+		want   bool
+	}{
+		{
+			// GC sawtooth: climbs 50% -> 70% over 7 minutes, then collects;
+			// the series ends shortly after a collection
+			name:   "sawtooth",
+			values: generate(60, func(i int) float64 { return 50 + float64(i%14)*20/14 }),
+			want:   false,
+		},
+		{
+			name:   "steady climb",
+			values: generate(60, func(i int) float64 { return 40 + float64(i)*0.25 }),
+			want:   true,
+		},
+		{
+			name:   "flat",
+			values: generate(60, func(i int) float64 { return 55 }),
+			want:   false,
+		},
+		{
+			name:   "too few samples",
+			values: []float64{40, 60},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slopes, weighted, confirmed := memoryTrendConfirmation(nil, seriesOf(30*time.Second, tt.values...), window)
+			if confirmed != tt.want {
+				t.Errorf("confirmed = %v, want %v (slopes %v, weighted %.4f)", confirmed, tt.want, slopes, weighted)
+			}
+		})
+	}
+}
+
+func TestMemoryTrendConfirmationSlopeKeys(t *testing.T) {
+	slopes, _, _ := memoryTrendConfirmation(nil, seriesOf(30*time.Second, generate(60, func(i int) float64 { return float64(i) })...), 30*time.Minute)
+
+	for _, label := range []string{"10m", "20m", "30m"} {
+		if _, ok := slopes[label]; !ok {
+			t.Errorf("slopes %v missing the %s sub-window", slopes, label)
+		}
+	}
+}
+
+func TestMemoryLeakRequiresMultiWindowTrend(t *testing.T) {
+	ed := newTestDetector(&core.Config{})
+	gcPauses := &LatencyBimodality{Bimodal: true}
+
+	detect := func(values []float64) *Detection {
+		memory := seriesOf(30*time.Second, values...)
+		features := &ServiceFeatures{ServiceName: "checkout", Window: 30 * time.Minute}
+		ed.featureExtractor.extractMemoryFeatures(memory, features)
+		return ed.memoryLeak(context.Background(), "checkout", features, memory, gcPauses)
+	}
+
+	// Memory climbs 80% -> 98% over the first 10 minutes, then holds: only
+	// the full 30m fit sees growth
+	spike := detect(generate(60, func(i int) float64 {
+		if i < 20 {
+			return 80 + float64(i)*0.9
+		}
+		return 98
+	}))
+	if spike.Evidence["trend_confirmed"] != false {
+		t.Errorf("spike trend_confirmed = %v (slopes %v), want false", spike.Evidence["trend_confirmed"], spike.Evidence["window_slopes"])
+	}
+	if spike.AggregateConfidence <= memoryLeakCutoff {
+		t.Fatalf("spike aggregate confidence = %.1f, want it above the %.0f cutoff so confirmation decides", spike.AggregateConfidence, memoryLeakCutoff)
+	}
+	if spike.Detected || !slices.Contains(spike.DampeningReasons, "unconfirmed_trend") {
+		t.Errorf("spike detected = %v at %.1f (dampening %v), want it dampened below the cutoff",
+			spike.Detected, spike.Confidence, spike.DampeningReasons)
+	}
+
+	// A steady 0.6%/min climb over the whole window is confirmed in every third
+	steady := detect(generate(60, func(i int) float64 { return 82 + float64(i)*0.3 }))
+	if steady.Evidence["trend_confirmed"] != true {
+		t.Errorf("steady trend_confirmed = %v (slopes %v), want true", steady.Evidence["trend_confirmed"], steady.Evidence["window_slopes"])
+	}
+	if !steady.Detected || steady.Severity == SeverityNone {
+		t.Errorf("steady detected = %v, severity %s at %.1f; want a leak", steady.Detected, steady.Severity, steady.Confidence)
+	}
+	if slices.Contains(steady.DampeningReasons, "unconfirmed_trend") {
+		t.Errorf("steady climb dampened as unconfirmed: %v", steady.DampeningReasons)
+	}
+}
+
+func TestResourceExhaustionBothResourcesToggle(t *testing.T) {
+	cpuOnly := &ServiceFeatures{CPUMean: 96, MemoryMean: 40}
+	bothHigh := &ServiceFeatures{CPUMean: 95, MemoryMean: 94, SystemStress: 90}
+
+	requireBoth := newTestDetector(&core.Config{})
+
+	singleAllowed := false
+	single := newTestDetector(&core.Config{
+		Thresholds: map[string]core.ServiceThresholds{
+			"batch-worker": {RequireBothResources: &singleAllowed, SingleResourceThreshold: 92},
+		},
+	})
+
+	tests := []struct {
+		name     string
+		detector *EnhancedDetector
+		features *ServiceFeatures
+		want     bool
+	}{
+		{name: "cpu only, both required", detector: requireBoth, features: cpuOnly, want: false},
+		{name: "cpu only, single allowed", detector: single, features: cpuOnly, want: true},
+		{name: "both high, both required", detector: requireBoth, features: bothHigh, want: true},
+		{name: "both high, single allowed", detector: single, features: bothHigh, want: true},
+		{name: "cpu below single threshold", detector: single, features: &ServiceFeatures{CPUMean: 90, MemoryMean: 40}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			det := tt.detector.resourceExhaustion("batch-worker", tt.features)
+			if det.Detected != tt.want {
+				t.Errorf("Detected = %v, want %v (confidence %.1f, evidence %v)", det.Detected, tt.want, det.Confidence, det.Evidence)
+			}
+		})
+	}
+}
+
+func TestResourceExhaustionSingleResourceEvidence(t *testing.T) {
+	singleAllowed := false
+	ed := newTestDetector(&core.Config{
+		Thresholds: map[string]core.ServiceThresholds{
+			"batch-worker": {RequireBothResources: &singleAllowed},
+		},
+	})
+
+	det := ed.resourceExhaustion("batch-worker", &ServiceFeatures{CPUMean: 40, MemoryMean: 97})
+
+	if got := det.Evidence["single_resource_saturated"]; got != "memory" {
+		t.Errorf("single_resource_saturated = %v, want memory", got)
+	}
+	if got := det.Evidence["require_both_resources"]; got != false {
+		t.Errorf("require_both_resources = %v, want false", got)
+	}
+}
@@ -0,0 +1,190 @@
+package analyzer
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultSnapshotInterval = time.Hour
+
+	// MinDriftSnapshots is the fewest snapshots drift is computed from
+	MinDriftSnapshots = 2
+)
+
+// snapshotFeatures picks the features worth tracking over weeks. Snapshots
+// only keep these so the write stays small.
+func snapshotFeatures(f *ServiceFeatures) map[string]float64 {
+	return map[string]float64{
+		"cpu_mean":         f.CPUMean,
+		"cpu_trend":        f.CPUTrend,
+		"cpu_volatility":   f.CPUVolatility,
+		"memory_mean":      f.MemoryMean,
+		"memory_trend":     f.MemoryTrend,
+		"error_rate_mean":  f.ErrorRateMean,
+		"error_rate_trend": f.ErrorRateTrend,
+		"latency_mean":     f.LatencyMean,
+		"latency_p95":      f.LatencyP95,
+		"latency_p99":      f.LatencyP99,
+		"health_score":     f.HealthScore,
+		"system_stress":    f.SystemStress,
+	}
+}
+
+// SnapshotInterval is how often feature snapshots are taken; each snapshot
+// covers the interval since the previous one
+func (ua *UltimateAnalyzer) SnapshotInterval() time.Duration {
+	if cfg := ua.cfg(); cfg != nil {
+		if d, err := time.ParseDuration(cfg.Analyzer.SnapshotInterval); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultSnapshotInterval
+}
+
+// RunFeatureSnapshots snapshots every active service's features until the
+// context is cancelled. The interval is re-read after every run so a config
+// reload takes effect.
+func (ua *UltimateAnalyzer) RunFeatureSnapshots(ctx context.Context) {
+	ticker := time.NewTicker(ua.SnapshotInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := ua.SnapshotFeatures(ctx); err != nil && ctx.Err() == nil {
+				logger.FromContext(ctx).Warn("Feature snapshot failed", zap.Error(err))
+			}
+			ticker.Reset(ua.SnapshotInterval())
+		}
+	}
+}
+
+// SnapshotFeatures records the key features of every service that reported
+// during the last snapshot interval and returns how many were saved
+func (ua *UltimateAnalyzer) SnapshotFeatures(ctx context.Context) (int, error) {
+	interval := ua.SnapshotInterval()
+	services, err := ua.db.GetAllServices(ctx, interval)
+	if err != nil {
+		return 0, err
+	}
+
+	var saved atomic.Int64
+	ua.forEachService(ctx, services, func(ctx context.Context, _ int, serviceName string) {
+		features, err := ua.featureExtractor.ExtractFeatures(ctx, serviceName, interval)
+		if err == nil {
+			err = ua.db.SaveFeatureSnapshot(ctx, &storage.FeatureSnapshot{
+				ServiceName: serviceName,
+				Timestamp:   features.Timestamp,
+				Features:    snapshotFeatures(features),
+			})
+		}
+		if err != nil {
+			logger.FromContext(ctx).Debug("Skipping feature snapshot", zap.String("service", serviceName), zap.Error(err))
+			return
+		}
+		saved.Add(1)
+	})
+
+	return int(saved.Load()), nil
+}
+
+// FeatureDrift describes how a service's key features moved across the
+// snapshots in a period
+type FeatureDrift struct {
+	Service   string                      `json:"service"`
+	Days      int                         `json:"days"`
+	Snapshots int                         `json:"snapshots"`
+	From      *time.Time                  `json:"from,omitempty"`
+	To        *time.Time                  `json:"to,omitempty"`
+	Features  map[string]FeatureDriftStat `json:"features"`
+}
+
+// FeatureDriftStat compares the earliest and latest quarter of the snapshots
+// and fits a line through all of them
+type FeatureDriftStat struct {
+	Baseline      float64  `json:"baseline"` // mean of the earliest quarter
+	Recent        float64  `json:"recent"`   // mean of the latest quarter
+	Change        float64  `json:"change"`
+	ChangePercent *float64 `json:"change_percent,omitempty"` // unset when the baseline is 0
+	SlopePerDay   float64  `json:"slope_per_day"`
+}
+
+// FeatureDrift loads the service's snapshots from the last days and measures
+// how each feature has shifted. With fewer than MinDriftSnapshots snapshots
+// only the count is filled in.
+func (ua *UltimateAnalyzer) FeatureDrift(ctx context.Context, serviceName string, days int) (*FeatureDrift, error) {
+	since := time.Now().AddDate(0, 0, -days)
+	snapshots, err := ua.db.GetFeatureSnapshots(ctx, serviceName, since)
+	if err != nil {
+		return nil, err
+	}
+
+	drift := computeDrift(snapshots)
+	drift.Service = serviceName
+	drift.Days = days
+	return drift, nil
+}
+
+// computeDrift measures drift over snapshots ordered oldest first
+func computeDrift(snapshots []*storage.FeatureSnapshot) *FeatureDrift {
+	drift := &FeatureDrift{
+		Snapshots: len(snapshots),
+		Features:  make(map[string]FeatureDriftStat),
+	}
+	if len(snapshots) < MinDriftSnapshots {
+		return drift
+	}
+
+	first, last := snapshots[0].Timestamp, snapshots[len(snapshots)-1].Timestamp
+	drift.From, drift.To = &first, &last
+	quarter := int(math.Max(1, float64(len(snapshots)/4)))
+
+	names := make(map[string]bool)
+	for _, s := range snapshots {
+		for name := range s.Features {
+			names[name] = true
+		}
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	for _, name := range sorted {
+		var days, values []float64
+		for _, s := range snapshots {
+			if v, ok := s.Features[name]; ok {
+				days = append(days, s.Timestamp.Sub(first).Hours()/24)
+				values = append(values, v)
+			}
+		}
+		if len(values) < MinDriftSnapshots {
+			continue
+		}
+
+		q := int(math.Min(float64(quarter), float64(len(values))))
+		stat := FeatureDriftStat{
+			Baseline: CalculateMean(values[:q]),
+			Recent:   CalculateMean(values[len(values)-q:]),
+		}
+		stat.Change = stat.Recent - stat.Baseline
+		if stat.Baseline != 0 {
+			pct := stat.Change / math.Abs(stat.Baseline) * 100
+			stat.ChangePercent = &pct
+		}
+		stat.SlopePerDay, _, _ = PerformLinearRegressionOnValues(days, values)
+		drift.Features[name] = stat
+	}
+	return drift
+}
@@ -0,0 +1,81 @@
+package analyzer
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+)
+
+// dailySnapshots returns one snapshot per day from testEpoch with the given
+// memory_mean values
+func dailySnapshots(memory ...float64) []*storage.FeatureSnapshot {
+	snapshots := make([]*storage.FeatureSnapshot, len(memory))
+	for i, v := range memory {
+		snapshots[i] = &storage.FeatureSnapshot{
+			ServiceName: "checkout",
+			Timestamp:   testEpoch.Add(time.Duration(i) * 24 * time.Hour),
+			Features:    map[string]float64{"memory_mean": v, "error_rate_mean": 0},
+		}
+	}
+	return snapshots
+}
+
+func TestComputeDrift(t *testing.T) {
+	// Memory climbs 2 points a day over eight days
+	drift := computeDrift(dailySnapshots(40, 42, 44, 46, 48, 50, 52, 54))
+
+	if drift.Snapshots != 8 || drift.From == nil || !drift.To.Equal(testEpoch.Add(7*24*time.Hour)) {
+		t.Errorf("drift = %d snapshots from %v to %v, want 8 over a week", drift.Snapshots, drift.From, drift.To)
+	}
+
+	memory := drift.Features["memory_mean"]
+	// Quarters are the first and last two snapshots
+	if memory.Baseline != 41 || memory.Recent != 53 || memory.Change != 12 {
+		t.Errorf("memory = %+v, want baseline 41, recent 53, change 12", memory)
+	}
+	if memory.ChangePercent == nil || math.Abs(*memory.ChangePercent-12.0/41*100) > 1e-9 {
+		t.Errorf("change_percent = %v, want %v", memory.ChangePercent, 12.0/41*100)
+	}
+	if math.Abs(memory.SlopePerDay-2) > 1e-9 {
+		t.Errorf("slope_per_day = %v, want 2", memory.SlopePerDay)
+	}
+
+	if errRate := drift.Features["error_rate_mean"]; errRate.ChangePercent != nil {
+		t.Errorf("error_rate_mean change_percent = %v, want unset for a zero baseline", *errRate.ChangePercent)
+	}
+}
+
+func TestComputeDriftNeedsSnapshots(t *testing.T) {
+	drift := computeDrift(dailySnapshots(40))
+	if drift.Snapshots != 1 || len(drift.Features) != 0 || drift.From != nil {
+		t.Errorf("drift = %+v, want only the count below %d snapshots", drift, MinDriftSnapshots)
+	}
+
+	// A feature present in only one snapshot is skipped
+	snapshots := dailySnapshots(40, 41)
+	snapshots[1].Features["gc_pause"] = 12
+	if _, ok := computeDrift(snapshots).Features["gc_pause"]; ok {
+		t.Error("drift computed for a feature with a single value")
+	}
+}
+
+func TestSnapshotInterval(t *testing.T) {
+	for _, tt := range []struct {
+		configured string
+		want       time.Duration
+	}{
+		{"", defaultSnapshotInterval},
+		{"15m", 15 * time.Minute},
+		{"soon", defaultSnapshotInterval},
+	} {
+		cfg := &core.Config{}
+		cfg.Analyzer.SnapshotInterval = tt.configured
+		ua := NewUltimateAnalyzer(nil, core.NewConfigStore("", cfg))
+		if got := ua.SnapshotInterval(); got != tt.want {
+			t.Errorf("SnapshotInterval with %q = %v, want %v", tt.configured, got, tt.want)
+		}
+	}
+}
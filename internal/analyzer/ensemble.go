@@ -0,0 +1,168 @@
+package analyzer
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+// Ensemble merge strategies
+const (
+	EnsembleAgreement = "agreement" // fire only when both stacks fire
+	EnsembleMax       = "max"       // take the stronger verdict, penalized on disagreement
+	EnsembleAverage   = "average"   // average both confidences
+)
+
+const defaultDisagreementPenalty = 0.8
+
+// EnsembleVerdict is the reconciled result for one detection type
+type EnsembleVerdict struct {
+	Type     DetectionType `json:"type"`
+	Classic  *Detection    `json:"classic"`
+	Enhanced *Detection    `json:"enhanced"`
+	Merged   *Detection    `json:"merged"`
+	Agree    bool          `json:"agree"`
+}
+
+// EnsembleResult holds every per-type verdict for a service
+type EnsembleResult struct {
+	ServiceName    string             `json:"service_name"`
+	Strategy       string             `json:"strategy"`
+	Verdicts       []*EnsembleVerdict `json:"verdicts"`
+	AgreementScore float64            `json:"agreement_score"` // 0-100, share of types both stacks agree on
+	Primary        *Detection         `json:"primary"`
+	Timestamp      time.Time          `json:"timestamp"`
+}
+
+// EnsembleAnalyzer runs the classic and enhanced detectors side by side and
+// reconciles their verdicts per detection type
+type EnsembleAnalyzer struct {
+	ultimate *UltimateAnalyzer
+	classic  *ClassicDetector
+	config   *core.ConfigStore
+}
+
+func NewEnsembleAnalyzer(ua *UltimateAnalyzer, config *core.ConfigStore) *EnsembleAnalyzer {
+	return &EnsembleAnalyzer{
+		ultimate: ua,
+		classic:  NewClassicDetector(config),
+		config:   config,
+	}
+}
+
+// Strategy returns the configured merge strategy
+func (ea *EnsembleAnalyzer) Strategy() string {
+	cfg := ea.config.Get()
+	if cfg == nil || cfg.Analyzer.EnsembleStrategy == "" {
+		return EnsembleMax
+	}
+	return cfg.Analyzer.EnsembleStrategy
+}
+
+func (ea *EnsembleAnalyzer) penalty() float64 {
+	cfg := ea.config.Get()
+	if cfg == nil || cfg.Analyzer.DisagreementPenalty == 0 {
+		return defaultDisagreementPenalty
+	}
+	return cfg.Analyzer.DisagreementPenalty
+}
+
+// Analyze diagnoses the service with both stacks and merges the results
+func (ea *EnsembleAnalyzer) Analyze(ctx context.Context, serviceName string) (*EnsembleResult, error) {
+	diagnosis, err := ea.ultimate.DiagnoseService(ctx, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	enhanced := make(map[DetectionType]*Detection, len(diagnosis.AllDetections))
+	for _, d := range diagnosis.AllDetections {
+		enhanced[d.Type] = d
+	}
+
+	strategy := ea.Strategy()
+	result := &EnsembleResult{
+		ServiceName: serviceName,
+		Strategy:    strategy,
+		Timestamp:   time.Now(),
+	}
+
+	agreed := 0
+	for _, classic := range ea.classic.DetectAll(diagnosis.Features) {
+		verdict := ea.merge(strategy, classic, enhanced[classic.Type])
+		if verdict.Agree {
+			agreed++
+		}
+		if verdict.Merged.Detected && (result.Primary == nil || verdict.Merged.Confidence > result.Primary.Confidence) {
+			result.Primary = verdict.Merged
+		}
+		result.Verdicts = append(result.Verdicts, verdict)
+	}
+
+	if len(result.Verdicts) > 0 {
+		result.AgreementScore = float64(agreed) / float64(len(result.Verdicts)) * 100
+	}
+
+	return result, nil
+}
+
+// merge reconciles one detection type. A missing enhanced verdict (detector
+// error) is treated as "not detected".
+func (ea *EnsembleAnalyzer) merge(strategy string, classic, enhanced *Detection) *EnsembleVerdict {
+	enhancedDetected, enhancedConfidence := false, 0.0
+	if enhanced != nil {
+		enhancedDetected, enhancedConfidence = enhanced.Detected, enhanced.Confidence
+	}
+
+	agree := classic.Detected == enhancedDetected
+
+	var confidence float64
+	var detected bool
+	switch strategy {
+	case EnsembleAgreement:
+		detected = classic.Detected && enhancedDetected
+		confidence = math.Min(classic.Confidence, enhancedConfidence)
+	case EnsembleAverage:
+		confidence = (classic.Confidence + enhancedConfidence) / 2
+		detected = (classic.Detected || enhancedDetected) && confidence >= 50
+	default:
+		confidence = math.Max(classic.Confidence, enhancedConfidence)
+		if !agree {
+			confidence *= ea.penalty()
+		}
+		detected = (classic.Detected || enhancedDetected) && confidence >= 50
+	}
+
+	merged := &Detection{
+		Type:        classic.Type,
+		ServiceName: classic.ServiceName,
+		Detected:    detected,
+		Confidence:  confidence,
+		Timestamp:   time.Now(),
+		Severity:    SeverityNone,
+		Evidence: map[string]interface{}{
+			"strategy":            strategy,
+			"classic_confidence":  classic.Confidence,
+			"enhanced_confidence": enhancedConfidence,
+			"stacks_agree":        agree,
+		},
+	}
+	if enhanced != nil {
+		merged.DataSufficiency = enhanced.DataSufficiency
+	}
+	if detected {
+		merged.Severity = ea.classic.risk.SeverityForConfidence(confidence)
+		if enhanced != nil {
+			merged.Recommendation = enhanced.Recommendation
+		}
+	}
+
+	return &EnsembleVerdict{
+		Type:     classic.Type,
+		Classic:  classic,
+		Enhanced: enhanced,
+		Merged:   merged,
+		Agree:    agree,
+	}
+}
@@ -0,0 +1,65 @@
+package analyzer
+
+import (
+	"math"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+)
+
+// How detectors judge a service's errors
+const (
+	ErrorBasisAbsolute = "absolute" // errors/min against fixed cutoffs
+	ErrorBasisRatio    = "ratio"    // errors/requests against error_ratio_threshold
+)
+
+const defaultErrorRatioMinRate = 1.0 // errors/min
+
+// applyErrorRatio sets the request rate and error ratio, and the error rate
+// detectors score. Absolute error rates mislead across traffic volumes: 15
+// errors/min is an outage for a service taking 20 requests/min and noise
+// for one taking 20000. With thresholds.<service>.error_ratio_threshold set
+// and request data present, the ratio is rescaled so the threshold lands on
+// analyzer.error_rate_threshold, which keeps every detector's cutoffs
+// meaningful. Below error_ratio_min_rate the absolute rate is scored.
+func (fe *FeatureExtractor) applyErrorRatio(serviceName string, requests []*storage.Metric, features *ServiceFeatures) {
+	features.ErrorBasis = ErrorBasisAbsolute
+	features.ScoredErrorRate = features.ErrorRateMean
+
+	if len(requests) == 0 {
+		return
+	}
+	features.RequestRateMean = CalculateMean(extractMetricValues(requests))
+	if features.RequestRateMean <= 0 {
+		return
+	}
+	features.ErrorRatio = math.Min(features.ErrorRateMean/features.RequestRateMean, 1)
+
+	cfg := fe.cfg()
+	t := cfg.ThresholdsFor(serviceName)
+	if t.ErrorRatioThreshold <= 0 {
+		return
+	}
+	minRate := t.ErrorRatioMinRate
+	if minRate <= 0 {
+		minRate = defaultErrorRatioMinRate
+	}
+	if features.ErrorRateMean < minRate {
+		return
+	}
+
+	_, _, errorRateThreshold, _ := classicThresholds(cfg)
+	features.ErrorBasis = ErrorBasisRatio
+	features.ScoredErrorRate = features.ErrorRatio / t.ErrorRatioThreshold * errorRateThreshold
+}
+
+// addErrorEvidence records the error ratio and the basis errors were judged on
+func addErrorEvidence(evidence map[string]interface{}, f *ServiceFeatures) {
+	evidence["error_basis"] = f.ErrorBasis
+	if f.RequestRateMean > 0 {
+		evidence["request_rate"] = quantity("%.2f/min", f.RequestRateMean)
+		evidence["error_ratio"] = quantity("%.2f%%", f.ErrorRatio*100)
+	}
+	if f.ErrorBasis == ErrorBasisRatio {
+		evidence["scored_error_rate"] = quantity("%.2f/min", f.ScoredErrorRate)
+	}
+}
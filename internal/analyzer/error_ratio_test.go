@@ -0,0 +1,171 @@
+package analyzer
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+)
+
+// newErrorRatioConfig judges "low-volume" and "high-volume" by error ratio,
+// with 5% of requests failing worth the 10 errors/min absolute threshold
+func newErrorRatioConfig() *core.Config {
+	cfg := &core.Config{
+		Thresholds: map[string]core.ServiceThresholds{
+			"low-volume":  {ErrorRatioThreshold: 0.05},
+			"high-volume": {ErrorRatioThreshold: 0.05},
+			"floored":     {ErrorRatioThreshold: 0.05, ErrorRatioMinRate: 2},
+		},
+	}
+	cfg.Analyzer.ErrorRateThreshold = 10
+	cfg.ApplyDefaults()
+	return cfg
+}
+
+// ratioFeatures returns features with errorRate errors/min out of
+// requestRate requests/min, scored for the service
+func ratioFeatures(t *testing.T, fe *FeatureExtractor, service string, errorRate, requestRate float64) *ServiceFeatures {
+	t.Helper()
+
+	features := &ServiceFeatures{ServiceName: service, ErrorRateMean: errorRate, CPUMean: 30, MemoryMean: 40}
+	var requests []float64
+	if requestRate > 0 {
+		requests = []float64{requestRate, requestRate, requestRate}
+	}
+	fe.applyErrorRatio(service, seriesOf(time.Minute, requests...), features)
+	return features
+}
+
+func TestErrorRatioSameErrorsDifferentVolumes(t *testing.T) {
+	cfg := newErrorRatioConfig()
+	store := core.NewConfigStore("", cfg)
+	fe := NewFeatureExtractor(nil, store)
+	classic := NewClassicDetector(store)
+
+	// 15 errors/min is 75% of 20 requests but 0.075% of 20000
+	low := ratioFeatures(t, fe, "low-volume", 15, 20)
+	high := ratioFeatures(t, fe, "high-volume", 15, 20000)
+
+	if low.ErrorBasis != ErrorBasisRatio || high.ErrorBasis != ErrorBasisRatio {
+		t.Fatalf("error basis = %s/%s, want ratio for both", low.ErrorBasis, high.ErrorBasis)
+	}
+	if math.Abs(low.ErrorRatio-0.75) > 1e-9 || math.Abs(high.ErrorRatio-0.00075) > 1e-9 {
+		t.Errorf("error ratios = %v/%v, want 0.75 and 0.00075", low.ErrorRatio, high.ErrorRatio)
+	}
+	// The ratio over the 5% threshold, times the 10 errors/min threshold
+	if math.Abs(low.ScoredErrorRate-150) > 1e-9 || math.Abs(high.ScoredErrorRate-0.15) > 1e-9 {
+		t.Errorf("scored error rates = %v/%v, want 150 and 0.15", low.ScoredErrorRate, high.ScoredErrorRate)
+	}
+
+	lowDet, highDet := classic.detectDeploymentBug(low), classic.detectDeploymentBug(high)
+	if lowDet.Confidence <= highDet.Confidence {
+		t.Errorf("deployment bug confidence low-volume %v <= high-volume %v, want the low-volume service judged worse",
+			lowDet.Confidence, highDet.Confidence)
+	}
+
+	// Judged by absolute rate, as without error_ratio_threshold, both
+	// services score the same
+	absolute := &core.Config{}
+	absolute.Analyzer.ErrorRateThreshold = 10
+	absolute.ApplyDefaults()
+	absFE := NewFeatureExtractor(nil, core.NewConfigStore("", absolute))
+	absLow, absHigh := ratioFeatures(t, absFE, "low-volume", 15, 20), ratioFeatures(t, absFE, "high-volume", 15, 20000)
+	if a, b := classic.detectDeploymentBug(absLow).Confidence, classic.detectDeploymentBug(absHigh).Confidence; a != b {
+		t.Errorf("absolute confidences = %v/%v, want them equal", a, b)
+	}
+}
+
+func TestErrorRatioFallsBackToAbsolute(t *testing.T) {
+	fe := NewFeatureExtractor(nil, core.NewConfigStore("", newErrorRatioConfig()))
+
+	tests := []struct {
+		name        string
+		service     string
+		errorRate   float64
+		requestRate float64
+		wantRatio   float64
+	}{
+		{name: "no ratio threshold", service: "checkout", errorRate: 15, requestRate: 20, wantRatio: 0.75},
+		{name: "no request data", service: "low-volume", errorRate: 15},
+		{name: "below the default min rate", service: "low-volume", errorRate: 0.5, requestRate: 1, wantRatio: 0.5},
+		{name: "below a configured min rate", service: "floored", errorRate: 1.5, requestRate: 3, wantRatio: 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := ratioFeatures(t, fe, tt.service, tt.errorRate, tt.requestRate)
+			if f.ErrorBasis != ErrorBasisAbsolute || f.ScoredErrorRate != tt.errorRate {
+				t.Errorf("basis %s scoring %v, want absolute scoring %v", f.ErrorBasis, f.ScoredErrorRate, tt.errorRate)
+			}
+			if math.Abs(f.ErrorRatio-tt.wantRatio) > 1e-9 {
+				t.Errorf("ErrorRatio = %v, want %v", f.ErrorRatio, tt.wantRatio)
+			}
+		})
+	}
+}
+
+func TestErrorRatioEvidence(t *testing.T) {
+	fe := NewFeatureExtractor(nil, core.NewConfigStore("", newErrorRatioConfig()))
+
+	ratio := map[string]interface{}{}
+	addErrorEvidence(ratio, ratioFeatures(t, fe, "low-volume", 15, 20))
+	if ratio["error_basis"] != ErrorBasisRatio {
+		t.Errorf("error_basis = %v, want ratio", ratio["error_basis"])
+	}
+	for key, want := range map[string]string{"request_rate": "20.00/min", "error_ratio": "75.00%", "scored_error_rate": "150.00/min"} {
+		q, ok := ratio[key].(Quantity)
+		if !ok || q.String() != want {
+			t.Errorf("evidence[%s] = %v, want %s", key, ratio[key], want)
+		}
+	}
+
+	absolute := map[string]interface{}{}
+	addErrorEvidence(absolute, ratioFeatures(t, fe, "low-volume", 15, 0))
+	if absolute["error_basis"] != ErrorBasisAbsolute {
+		t.Errorf("error_basis = %v, want absolute", absolute["error_basis"])
+	}
+	for _, key := range []string{"request_rate", "error_ratio", "scored_error_rate"} {
+		if v, ok := absolute[key]; ok {
+			t.Errorf("evidence[%s] = %v without request data", key, v)
+		}
+	}
+}
+
+func TestDetectorCatalogListsErrorRatio(t *testing.T) {
+	ua := NewUltimateAnalyzer(nil, core.NewConfigStore("", newErrorRatioConfig()))
+
+	bug := detectorNamed(t, ua.Detectors("low-volume"), "deployment_bug")
+	if bug.Thresholds["error_ratio_threshold"] != 0.05 || bug.Thresholds["error_ratio_min_rate"] != defaultErrorRatioMinRate {
+		t.Errorf("low-volume thresholds = %v, want the ratio settings", bug.Thresholds)
+	}
+	if _, ok := detectorNamed(t, ua.Detectors("checkout"), "deployment_bug").Thresholds["error_ratio_threshold"]; ok {
+		t.Error("a service without error_ratio_threshold lists one")
+	}
+}
+
+func TestExtractedErrorRatioFromRequestCounter(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+
+	// 6 errors/min against an http_requests counter growing 120/min
+	end := time.Now().Add(-30 * time.Second)
+	storagetest.Seed(t, db, storagetest.Series(service, MetricErrors, end, time.Minute, generate(15, func(int) float64 { return 6 })...))
+	storagetest.Seed(t, db, storagetest.Series(service, "http_requests", end, time.Minute, generate(15, func(i int) float64 { return 1000 + 120*float64(i) })...))
+
+	cfg := newErrorRatioConfig()
+	cfg.Thresholds[service] = core.ServiceThresholds{ErrorRatioThreshold: 0.05}
+	features, err := NewFeatureExtractor(db, core.NewConfigStore("", cfg)).ExtractFeatures(ctx, service, 20*time.Minute)
+	if err != nil {
+		t.Fatalf("ExtractFeatures: %v", err)
+	}
+
+	if math.Abs(features.RequestRateMean-120) > 0.5 {
+		t.Errorf("request_rate_mean = %v, want 120/min from the counter", features.RequestRateMean)
+	}
+	if math.Abs(features.ErrorRatio-0.05) > 0.001 || features.ErrorBasis != ErrorBasisRatio {
+		t.Errorf("error ratio = %v (%s), want 0.05 by ratio", features.ErrorRatio, features.ErrorBasis)
+	}
+}
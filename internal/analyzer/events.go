@@ -0,0 +1,126 @@
+package analyzer
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// eventCorroboration is how many confidence points a kind of pod event adds
+// to each detection it corroborates. A kind counts once however often it
+// occurred in the window.
+var eventCorroboration = map[string]map[DetectionType]float64{
+	storage.EventOOMKilled: {
+		DetectionMemoryLeak:         20,
+		DetectionResourceExhaustion: 10,
+	},
+	storage.EventCrashLoop: {
+		DetectionDeploymentBug: 10,
+		DetectionMemoryLeak:    5,
+	},
+	storage.EventFailedScheduling: {
+		DetectionResourceExhaustion: 10,
+	},
+}
+
+// corroboratingEventTypes lists the keys of eventCorroboration in a fixed order
+var corroboratingEventTypes = []string{
+	storage.EventOOMKilled,
+	storage.EventCrashLoop,
+	storage.EventFailedScheduling,
+}
+
+const (
+	// maxEventBoost caps the confidence events can add to one detection
+	maxEventBoost = 25.0
+
+	// maxEvidenceEvents bounds how many events are listed in a detection's
+	// evidence; the counts cover the rest
+	maxEvidenceEvents = 5
+)
+
+// CorroboratingEvent is a pod event listed in a detection's evidence
+type CorroboratingEvent struct {
+	Type      string    `json:"type"`
+	Pod       string    `json:"pod"`
+	Namespace string    `json:"namespace"`
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// corroborateWithEvents folds the service's recent OOMKilled, CrashLoop and
+// FailedScheduling pod events into the detections they support. Failing to
+// read events leaves the detections as they are.
+func (ua *UltimateAnalyzer) corroborateWithEvents(ctx context.Context, serviceName string, detections []*Detection) {
+	until := storage.AsOf(ctx)
+	events, err := ua.db.GetServiceEvents(ctx, serviceName, corroboratingEventTypes, until.Add(-analysisWindow(ctx)), until)
+	if err != nil {
+		logger.FromContext(ctx).Warn("Failed to read pod events for diagnosis",
+			zap.String("service", serviceName),
+			zap.Error(err))
+		return
+	}
+	ua.applyEvents(detections, events)
+}
+
+// applyEvents adds matching events to the evidence of every finished
+// detection; a detected problem also gains confidence, and severity if the
+// new confidence warrants it
+func (ua *UltimateAnalyzer) applyEvents(detections []*Detection, events []*storage.Event) {
+	if len(events) == 0 {
+		return
+	}
+
+	for _, d := range detections {
+		if d == nil || d.Status != DetectionStatusOK {
+			continue
+		}
+
+		var (
+			boost   float64
+			counts  = make(map[string]int)
+			matched []CorroboratingEvent
+		)
+		for _, e := range events {
+			if eventCorroboration[e.EventType][d.Type] == 0 {
+				continue
+			}
+			if counts[e.EventType] == 0 {
+				boost += eventCorroboration[e.EventType][d.Type]
+			}
+			counts[e.EventType]++
+			if len(matched) < maxEvidenceEvents {
+				matched = append(matched, CorroboratingEvent{
+					Type:      e.EventType,
+					Pod:       e.PodName,
+					Namespace: e.Namespace,
+					Message:   e.Message,
+					Timestamp: e.Timestamp,
+				})
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+
+		if d.Evidence == nil {
+			d.Evidence = map[string]interface{}{}
+		}
+		d.Evidence["k8s_events"] = matched
+		d.Evidence["k8s_event_counts"] = counts
+		if !d.Detected {
+			continue
+		}
+
+		boost = math.Min(boost, maxEventBoost)
+		d.Evidence["k8s_event_boost"] = boost
+		d.Confidence = math.Min(100, d.Confidence+boost)
+		if severity := ua.risk.SeverityForConfidence(d.Confidence); severityRank[severity] > severityRank[d.Severity] {
+			d.Severity = severity
+		}
+	}
+}
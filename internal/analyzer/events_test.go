@@ -0,0 +1,89 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+)
+
+func podEvent(eventType, pod string, at time.Time) *storage.Event {
+	return &storage.Event{Timestamp: at, EventType: eventType, PodName: pod, Namespace: "default", Message: eventType + " " + pod}
+}
+
+func TestOOMKilledRaisesMemoryLeakConfidence(t *testing.T) {
+	cfg := &core.Config{}
+	cfg.ApplyDefaults()
+	ua := NewUltimateAnalyzer(nil, core.NewConfigStore("", cfg))
+
+	leak := &Detection{Type: DetectionMemoryLeak, Detected: true, Confidence: 60, Severity: SeverityMedium, Status: DetectionStatusOK}
+	exhaustion := &Detection{Type: DetectionResourceExhaustion, Detected: false, Confidence: 30, Status: DetectionStatusOK}
+	deploy := &Detection{Type: DetectionDeploymentBug, Detected: true, Confidence: 70, Severity: SeverityHigh, Status: DetectionStatusOK}
+	timedOut := &Detection{Type: DetectionMemoryLeak, Confidence: 0, Status: DetectionStatusTimeout}
+
+	now := time.Now()
+	events := []*storage.Event{
+		podEvent(storage.EventOOMKilled, "checkout-7d9f-a", now.Add(-time.Minute)),
+		podEvent(storage.EventOOMKilled, "checkout-7d9f-b", now.Add(-2*time.Minute)),
+	}
+	ua.applyEvents([]*Detection{leak, exhaustion, deploy, timedOut}, events)
+
+	// One OOMKilled kind adds 20 however many pods were killed
+	if leak.Confidence != 80 || leak.Severity != SeverityHigh {
+		t.Errorf("memory leak = %.0f %s, want 80 HIGH", leak.Confidence, leak.Severity)
+	}
+	if counts, _ := leak.Evidence["k8s_event_counts"].(map[string]int); counts[storage.EventOOMKilled] != 2 {
+		t.Errorf("memory leak event counts = %v, want 2 OOMKilled", leak.Evidence["k8s_event_counts"])
+	}
+	if listed, _ := leak.Evidence["k8s_events"].([]CorroboratingEvent); len(listed) != 2 || listed[0].Pod != "checkout-7d9f-a" {
+		t.Errorf("memory leak events = %v, want both OOM kills", leak.Evidence["k8s_events"])
+	}
+
+	// Undetected problems get the evidence but no confidence
+	if exhaustion.Confidence != 30 || exhaustion.Evidence["k8s_events"] == nil {
+		t.Errorf("resource exhaustion = %.0f with evidence %v, want 30 with the events listed", exhaustion.Confidence, exhaustion.Evidence)
+	}
+	if _, boosted := exhaustion.Evidence["k8s_event_boost"]; boosted {
+		t.Error("undetected resource exhaustion was boosted")
+	}
+
+	if deploy.Confidence != 70 || deploy.Evidence != nil {
+		t.Errorf("deployment bug = %.0f with evidence %v, want it untouched by OOM kills", deploy.Confidence, deploy.Evidence)
+	}
+	if timedOut.Evidence != nil {
+		t.Errorf("timed-out detection gained evidence %v", timedOut.Evidence)
+	}
+}
+
+func TestEventBoostIsCapped(t *testing.T) {
+	cfg := &core.Config{}
+	cfg.ApplyDefaults()
+	ua := NewUltimateAnalyzer(nil, core.NewConfigStore("", cfg))
+
+	leak := &Detection{Type: DetectionMemoryLeak, Detected: true, Confidence: 70, Severity: SeverityHigh, Status: DetectionStatusOK}
+	now := time.Now()
+	var events []*storage.Event
+	for i := 0; i < maxEvidenceEvents+2; i++ {
+		events = append(events, podEvent(storage.EventOOMKilled, "checkout-1", now.Add(-time.Duration(i)*time.Minute)))
+	}
+	events = append(events, podEvent(storage.EventCrashLoop, "checkout-1", now))
+	ua.applyEvents([]*Detection{leak}, events)
+
+	// OOMKilled (20) and CrashLoop (5) reach the cap exactly
+	if boost := leak.Evidence["k8s_event_boost"]; boost != maxEventBoost {
+		t.Errorf("boost = %v, want %v", boost, maxEventBoost)
+	}
+	if leak.Confidence != 95 || leak.Severity != SeverityCritical {
+		t.Errorf("memory leak = %.0f %s, want 95 CRITICAL", leak.Confidence, leak.Severity)
+	}
+	if listed, _ := leak.Evidence["k8s_events"].([]CorroboratingEvent); len(listed) != maxEvidenceEvents {
+		t.Errorf("listed %d events, want at most %d", len(listed), maxEvidenceEvents)
+	}
+
+	before := *leak
+	ua.applyEvents([]*Detection{&before}, nil)
+	if before.Confidence != leak.Confidence {
+		t.Error("no events changed the detection")
+	}
+}
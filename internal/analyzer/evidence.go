@@ -0,0 +1,84 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// EvidenceFormatLegacy is the ?format= value that renders numeric evidence
+// as the formatted strings ("45.20%", "120.00ms") responses used to carry
+const EvidenceFormatLegacy = "legacy"
+
+// Quantity is a numeric evidence value. It marshals as a bare JSON number;
+// Format is the unit-bearing layout legacy output and logs render it with.
+type Quantity struct {
+	Value  float64
+	Format string // e.g. "%.2f%%", "%.2fms", "%.4f%%/min"
+}
+
+func quantity(format string, value float64) Quantity {
+	return Quantity{Value: value, Format: format}
+}
+
+func (q Quantity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(q.Value)
+}
+
+// String renders the value with its unit, as legacy evidence did
+func (q Quantity) String() string {
+	return fmt.Sprintf(q.Format, q.Value)
+}
+
+// LegacyEvidence returns a copy of evidence with every Quantity rendered as
+// its formatted string. Other values are kept as they are.
+func LegacyEvidence(evidence map[string]interface{}) map[string]interface{} {
+	if evidence == nil {
+		return nil
+	}
+	legacy := make(map[string]interface{}, len(evidence))
+	for k, v := range evidence {
+		if q, ok := v.(Quantity); ok {
+			v = q.String()
+		}
+		legacy[k] = v
+	}
+	return legacy
+}
+
+// Legacy returns a copy of the detection with legacy evidence
+func (d *Detection) Legacy() *Detection {
+	if d == nil {
+		return nil
+	}
+	legacy := *d
+	legacy.Evidence = LegacyEvidence(d.Evidence)
+	return &legacy
+}
+
+// Legacy returns a copy of the diagnosis whose detections carry legacy
+// evidence. Diagnoses read back from storage were decoded from JSON and hold
+// plain numbers, so they are unaffected.
+func (diag *UltimateDiagnosis) Legacy() *UltimateDiagnosis {
+	legacy := *diag
+	legacy.PrimaryDetection = diag.PrimaryDetection.Legacy()
+	legacy.AllDetections = make([]*Detection, len(diag.AllDetections))
+	for i, d := range diag.AllDetections {
+		legacy.AllDetections[i] = d.Legacy()
+	}
+	return &legacy
+}
+
+// Legacy returns a copy of the result whose detections carry legacy evidence
+func (r *EnsembleResult) Legacy() *EnsembleResult {
+	legacy := *r
+	legacy.Primary = r.Primary.Legacy()
+	legacy.Verdicts = make([]*EnsembleVerdict, len(r.Verdicts))
+	for i, v := range r.Verdicts {
+		verdict := *v
+		verdict.Classic = v.Classic.Legacy()
+		verdict.Enhanced = v.Enhanced.Legacy()
+		verdict.Merged = v.Merged.Legacy()
+		legacy.Verdicts[i] = &verdict
+	}
+	return &legacy
+}
@@ -0,0 +1,130 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+// decodeEvidence round-trips a detection through JSON and returns its evidence
+func decodeEvidence(t *testing.T, d *Detection) map[string]interface{} {
+	t.Helper()
+
+	raw, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var decoded struct {
+		Evidence map[string]interface{} `json:"evidence"`
+	}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	return decoded.Evidence
+}
+
+func TestQuantityMarshalsAsNumber(t *testing.T) {
+	raw, err := json.Marshal(quantity("%.2f%%", 45.2))
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(raw) != "45.2" {
+		t.Errorf("Marshal = %s, want 45.2", raw)
+	}
+	if got := quantity("%.2fms", 120).String(); got != "120.00ms" {
+		t.Errorf("String = %q, want 120.00ms", got)
+	}
+}
+
+func TestDetectorEvidenceSerializesNumbers(t *testing.T) {
+	features := &ServiceFeatures{ServiceName: "checkout", CPUMean: 91.5, MemoryMean: 88.25, LatencyP95: 420, ErrorRateMean: 3}
+
+	ed := newTestDetector(&core.Config{})
+	evidence := decodeEvidence(t, ed.resourceExhaustion("checkout", features))
+	for key, want := range map[string]float64{"cpu_usage": 91.5, "memory_usage": 88.25} {
+		if got, ok := evidence[key].(float64); !ok || got != want {
+			t.Errorf("enhanced evidence[%s] = %#v, want the number %v", key, evidence[key], want)
+		}
+	}
+
+	cfg := &core.Config{}
+	cfg.ApplyDefaults()
+	for _, d := range NewClassicDetector(core.NewConfigStore("", cfg)).DetectAll(features) {
+		decoded := decodeEvidence(t, d)
+		for key, v := range d.Evidence {
+			q, ok := v.(Quantity)
+			if !ok {
+				continue
+			}
+			if got, ok := decoded[key].(float64); !ok || got != q.Value {
+				t.Errorf("classic %s evidence[%s] = %#v, want the number %v", d.Type, key, decoded[key], q.Value)
+			}
+		}
+	}
+}
+
+func TestLegacyEvidence(t *testing.T) {
+	evidence := map[string]interface{}{
+		"cpu_mean":    quantity("%.2f%%", 45.2),
+		"latency_p95": quantity("%.2fms", 120),
+		"signal":      "cpu",
+		"saturated":   true,
+	}
+
+	legacy := LegacyEvidence(evidence)
+	want := map[string]interface{}{"cpu_mean": "45.20%", "latency_p95": "120.00ms", "signal": "cpu", "saturated": true}
+	for key, w := range want {
+		if legacy[key] != w {
+			t.Errorf("legacy[%s] = %#v, want %#v", key, legacy[key], w)
+		}
+	}
+	if _, ok := evidence["cpu_mean"].(Quantity); !ok {
+		t.Error("LegacyEvidence modified its input")
+	}
+	if LegacyEvidence(nil) != nil {
+		t.Error("LegacyEvidence(nil) != nil")
+	}
+}
+
+func TestLegacyCopies(t *testing.T) {
+	detection := func() *Detection {
+		return &Detection{Type: DetectionResourceExhaustion, Evidence: map[string]interface{}{"cpu_mean": quantity("%.2f%%", 91.5)}}
+	}
+
+	diag := &UltimateDiagnosis{PrimaryDetection: detection(), AllDetections: []*Detection{detection()}}
+	legacy := diag.Legacy()
+	if got := decodeEvidence(t, legacy.PrimaryDetection)["cpu_mean"]; got != "91.50%" {
+		t.Errorf("legacy primary cpu_mean = %#v, want 91.50%%", got)
+	}
+	if got := decodeEvidence(t, legacy.AllDetections[0])["cpu_mean"]; got != "91.50%" {
+		t.Errorf("legacy detection cpu_mean = %#v, want 91.50%%", got)
+	}
+	if got := decodeEvidence(t, diag.PrimaryDetection)["cpu_mean"]; got != 91.5 {
+		t.Errorf("original primary cpu_mean = %#v, want the number kept", got)
+	}
+
+	// A diagnosis with nothing detected has no primary detection
+	if (&UltimateDiagnosis{}).Legacy().PrimaryDetection != nil {
+		t.Error("Legacy invented a primary detection")
+	}
+
+	result := &EnsembleResult{
+		Primary:  detection(),
+		Verdicts: []*EnsembleVerdict{{Type: DetectionResourceExhaustion, Classic: detection(), Merged: detection()}},
+	}
+	legacyResult := result.Legacy()
+	verdict := legacyResult.Verdicts[0]
+	if got := decodeEvidence(t, verdict.Classic)["cpu_mean"]; got != "91.50%" {
+		t.Errorf("legacy classic cpu_mean = %#v, want 91.50%%", got)
+	}
+	if verdict.Enhanced != nil {
+		t.Error("legacy verdict gained an enhanced detection")
+	}
+	if verdict == result.Verdicts[0] {
+		t.Error("Legacy shares verdicts with the original")
+	}
+	if got := decodeEvidence(t, result.Verdicts[0].Merged)["cpu_mean"]; got != 91.5 {
+		t.Errorf("original merged cpu_mean = %#v, want the number kept", got)
+	}
+}
@@ -2,177 +2,354 @@ package analyzer
 
 import (
 	"context"
+	"fmt"
 	"math"
 	"time"
 
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
 	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/pkg/logger"
+	"go.uber.org/zap"
 )
 
 // FeatureExtractor extracts 60+ dimensional features from raw metrics
 type FeatureExtractor struct {
-	db *storage.PostgresClient
+	db       *storage.PostgresClient
+	resolver *MetricResolver
+	config   *core.ConfigStore
 }
 
-func NewFeatureExtractor(db *storage.PostgresClient) *FeatureExtractor {
-	return &FeatureExtractor{db: db}
+func NewFeatureExtractor(db *storage.PostgresClient, config *core.ConfigStore) *FeatureExtractor {
+	return &FeatureExtractor{
+		db:       db,
+		resolver: NewMetricResolver(db, config),
+		config:   config,
+	}
+}
+
+// Resolver returns the metric name resolver used for feature extraction
+func (fe *FeatureExtractor) Resolver() *MetricResolver {
+	return fe.resolver
 }
 
+func (fe *FeatureExtractor) cfg() *core.Config {
+	return fe.config.Get()
+}
+
+// healthWeights returns the configured health score deductions
+func (fe *FeatureExtractor) healthWeights() core.HealthWeights {
+	if cfg := fe.cfg(); cfg != nil {
+		return cfg.Health.Weights.WithDefaults()
+	}
+	return core.DefaultHealthWeights()
+}
+
+// defaultMaxFeatureWindow applies when analyzer.max_feature_window is unset
+const defaultMaxFeatureWindow = 24 * time.Hour
+
+// maxPropagationLag is how many sample intervals latency is searched for
+// leading errors
+const maxPropagationLag = 5
+
+// Correlation keys for ServiceFeatures.Correlations
+const (
+	CorrCPUMemory    = "cpu_memory"
+	CorrCPUError     = "cpu_error"
+	CorrMemoryError  = "memory_error"
+	CorrLatencyError = "latency_error"
+)
+
 // ServiceFeatures represents comprehensive feature set
 type ServiceFeatures struct {
-	ServiceName string
-	Timestamp   time.Time
+	ServiceName string    `json:"service_name"`
+	Timestamp   time.Time `json:"timestamp"`
 
 	// Time-domain features (CPU)
-	CPUMean            float64
-	CPUStdDev          float64
-	CPUMin             float64
-	CPUMax             float64
-	CPURange           float64
-	CPUTrend           float64 // slope (% per minute)
-	CPUVolatility      float64
-	CPUAutocorrelation float64
-	CPUAnomalyScore    float64
+	CPUMean            float64 `json:"cpu_mean"`
+	CPUStdDev          float64 `json:"cpu_std_dev"`
+	CPUMin             float64 `json:"cpu_min"`
+	CPUMax             float64 `json:"cpu_max"`
+	CPURange           float64 `json:"cpu_range"`
+	CPUTrend           float64 `json:"cpu_trend"` // slope (% per minute)
+	CPUVolatility      float64 `json:"cpu_volatility"`
+	CPUAutocorrelation float64 `json:"cpu_autocorrelation"`
+	CPUAnomalyScore    float64 `json:"cpu_anomaly_score"`
 
 	// Time-domain features (Memory)
-	MemoryMean            float64
-	MemoryStdDev          float64
-	MemoryMin             float64
-	MemoryMax             float64
-	MemoryRange           float64
-	MemoryTrend           float64
-	MemoryVolatility      float64
-	MemoryAutocorrelation float64
-	MemoryAnomalyScore    float64
+	MemoryMean            float64 `json:"memory_mean"`
+	MemoryStdDev          float64 `json:"memory_std_dev"`
+	MemoryMin             float64 `json:"memory_min"`
+	MemoryMax             float64 `json:"memory_max"`
+	MemoryRange           float64 `json:"memory_range"`
+	MemoryTrend           float64 `json:"memory_trend"`
+	MemoryVolatility      float64 `json:"memory_volatility"`
+	MemoryAutocorrelation float64 `json:"memory_autocorrelation"`
+	MemoryAnomalyScore    float64 `json:"memory_anomaly_score"`
 
 	// Error rate features
-	ErrorRateMean      float64
-	ErrorRateMax       float64
-	ErrorRateTrend     float64
-	ErrorRateSpikiness float64
-	ErrorAnomalyScore  float64
+	ErrorRateMean      float64 `json:"error_rate_mean"`
+	ErrorRateMax       float64 `json:"error_rate_max"`
+	ErrorRateTrend     float64 `json:"error_rate_trend"`
+	ErrorRateSpikiness float64 `json:"error_rate_spikiness"`
+	ErrorAnomalyScore  float64 `json:"error_anomaly_score"`
+
+	// RequestRateMean is requests/min and ErrorRatio the share of requests
+	// failing (0-1); both are zero without a request_rate series.
+	// ErrorBasis is how detectors judge errors, ErrorBasisRatio or
+	// ErrorBasisAbsolute, and ScoredErrorRate the errors/min figure they
+	// compare against their cutoffs (see applyErrorRatio).
+	RequestRateMean float64 `json:"request_rate_mean"`
+	ErrorRatio      float64 `json:"error_ratio"`
+	ErrorBasis      string  `json:"error_basis"`
+	ScoredErrorRate float64 `json:"scored_error_rate"`
 
 	// Latency features
-	LatencyMean         float64
-	LatencyP50          float64
-	LatencyP95          float64
-	LatencyP99          float64
-	LatencyStdDev       float64
-	LatencyAnomalyScore float64
+	LatencyMean         float64 `json:"latency_mean"`
+	LatencyP50          float64 `json:"latency_p50"`
+	LatencyP95          float64 `json:"latency_p95"`
+	LatencyP99          float64 `json:"latency_p99"`
+	LatencyStdDev       float64 `json:"latency_std_dev"`
+	LatencyAnomalyScore float64 `json:"latency_anomaly_score"`
+
+	// Smoothing describes the preprocessing applied to CPU, memory and error
+	// series, e.g. "sma:5"; empty when features come from raw samples
+	Smoothing string `json:"smoothing,omitempty"`
+
+	// SpikinessMethod is how ErrorRateSpikiness was measured: cv or percentile
+	SpikinessMethod string `json:"spikiness_method,omitempty"`
+
+	// HistogramPercentiles is true when P50/P95/P99 come from the Prometheus
+	// histogram rather than being approximated from raw samples
+	HistogramPercentiles bool `json:"histogram_percentiles"`
+
+	// Window is the span features were computed over, after the
+	// analyzer.max_feature_window cap; CoveredSpan is the span the samples
+	// actually cover. Downsampled is true when a series held more than
+	// analyzer.max_series_points samples and was thinned evenly.
+	Window      time.Duration `json:"window"`
+	CoveredSpan time.Duration `json:"covered_span"`
+	Downsampled bool          `json:"downsampled"`
+
+	// Transforms lists the metric_transforms unit conversions applied to
+	// the series the features were computed from
+	Transforms []AppliedTransform `json:"transforms,omitempty"`
+
+	// MissingMetrics lists the canonical metrics with no samples in the
+	// window. Their features are zero because nothing was measured, not
+	// because the service is idle.
+	MissingMetrics []string `json:"missing_metrics,omitempty"`
 
 	// Cross-metric correlations
-	CPUMemoryCorr    float64
-	CPUErrorCorr     float64
-	MemoryErrorCorr  float64
-	LatencyErrorCorr float64
-	RequestCPUCorr   float64
+	CPUMemoryCorr    float64 `json:"cpu_memory_corr"`
+	CPUErrorCorr     float64 `json:"cpu_error_corr"`
+	MemoryErrorCorr  float64 `json:"memory_error_corr"`
+	LatencyErrorCorr float64 `json:"latency_error_corr"`
+	RequestCPUCorr   float64 `json:"request_cpu_corr"`
+
+	// Correlations keeps the sample count and strength behind each coefficient
+	// above, so "no data" can be told apart from "no correlation"
+	Correlations map[string]CorrelationResult `json:"correlations"`
 
 	// Pattern detection
-	HasPeriodicPattern bool
-	PeriodLength       time.Duration
-	HasSeasonality     bool
-	HasTrend           bool
-	TrendDirection     string // "increasing", "decreasing", "stable"
+	HasPeriodicPattern bool          `json:"has_periodic_pattern"`
+	PeriodLength       time.Duration `json:"period_length"`
+	HasSeasonality     bool          `json:"has_seasonality"`
+	HasTrend           bool          `json:"has_trend"`
+	TrendDirection     string        `json:"trend_direction"` // "increasing", "decreasing", "stable"
 
 	// Composite scores
-	SystemStress        float64 // 0-100
-	HealthScore         float64 // 0-100
-	StabilityIndex      float64 // 0-10
-	PredictabilityScore float64 // 0-100
+	SystemStress        float64 `json:"system_stress"`        // 0-100
+	HealthScore         float64 `json:"health_score"`         // 0-100
+	StabilityIndex      float64 `json:"stability_index"`      // 0-10
+	PredictabilityScore float64 `json:"predictability_score"` // 0-100
 }
 
 // ExtractFeatures performs comprehensive feature extraction
 func (fe *FeatureExtractor) ExtractFeatures(ctx context.Context, serviceName string, window time.Duration) (*ServiceFeatures, error) {
 	features := &ServiceFeatures{
 		ServiceName: serviceName,
-		Timestamp:   time.Now(),
+		Timestamp:   storage.AsOf(ctx),
+	}
+
+	window = fe.capWindow(ctx, serviceName, window)
+	ctx = storage.WithMaxPoints(ctx, fe.maxSeriesPoints())
+
+	canonicals := []string{MetricCPU, MetricMemory, MetricErrors, MetricLatency, MetricRequests}
+	series, totals, err := fe.resolver.ResolveSeriesMulti(ctx, serviceName, canonicals, window)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch metrics for %s: %w", serviceName, err)
+	}
+	features.Transforms = fe.resolver.seriesTransforms(serviceName, series, canonicals)
+	features.Window = window
+	features.CoveredSpan = coveredSpan(series)
+	for canonical, total := range totals {
+		if total > len(series[canonical]) {
+			features.Downsampled = true
+			logger.FromContext(ctx).Warn("Feature series downsampled to the point cap",
+				zap.String("service", serviceName),
+				zap.String("metric", canonical),
+				zap.Int("samples", total),
+				zap.Int("kept", len(series[canonical])),
+				zap.Duration("window", window),
+			)
+		}
 	}
 
-	// Extract CPU features
-	cpuMetrics, err := fe.db.GetRecentMetrics(ctx, serviceName, "cpu_usage", window)
-	if err == nil && len(cpuMetrics) > 0 {
-		fe.extractCPUFeatures(cpuMetrics, features)
+	// Smoothing only reshapes CPU, memory and error values; latency keeps raw
+	// samples so percentiles stay true and error spikiness reads rawErrors
+	method, smoothWindow := fe.cfg().SmoothingFor(serviceName)
+	if smoothWindow > 1 {
+		features.Smoothing = fmt.Sprintf("%s:%d", method, smoothWindow)
 	}
+	rawErrors := series[MetricErrors]
 
-	// Try alternative CPU metric names
-	if len(cpuMetrics) == 0 {
-		cpuMetrics, _ = fe.db.GetRecentMetrics(ctx, serviceName, "cpu_usage_percent", window)
-		if len(cpuMetrics) > 0 {
-			fe.extractCPUFeatures(cpuMetrics, features)
-		}
+	cpuMetrics := smoothSeries(series[MetricCPU], method, smoothWindow)
+	if len(cpuMetrics) > 0 {
+		fe.extractCPUFeatures(cpuMetrics, features)
 	}
 
-	// Extract Memory features
-	memMetrics, err := fe.db.GetRecentMetrics(ctx, serviceName, "memory_usage", window)
-	if err == nil && len(memMetrics) > 0 {
+	memMetrics := smoothSeries(series[MetricMemory], method, smoothWindow)
+	if len(memMetrics) > 0 {
 		fe.extractMemoryFeatures(memMetrics, features)
 	}
 
-	// Try alternative memory metric names
-	if len(memMetrics) == 0 {
-		memMetrics, _ = fe.db.GetRecentMetrics(ctx, serviceName, "memory_usage_percent", window)
-		if len(memMetrics) > 0 {
-			fe.extractMemoryFeatures(memMetrics, features)
-		}
+	errorMetrics := smoothSeries(rawErrors, method, smoothWindow)
+	if len(errorMetrics) > 0 {
+		fe.extractErrorFeatures(errorMetrics, rawErrors, features)
 	}
 
-	// Extract Error features
-	errorMetrics, err := fe.db.GetRecentMetrics(ctx, serviceName, "error_rate", window)
-	if err == nil && len(errorMetrics) > 0 {
-		fe.extractErrorFeatures(errorMetrics, features)
-	}
+	fe.applyErrorRatio(serviceName, series[MetricRequests], features)
 
-	// Try alternative error metric names
-	if len(errorMetrics) == 0 {
-		errorMetrics, _ = fe.db.GetRecentMetrics(ctx, serviceName, "app_errors_total", window)
-		if len(errorMetrics) > 0 {
-			fe.extractErrorFeatures(errorMetrics, features)
-		}
-	}
-	if len(errorMetrics) == 0 {
-		errorMetrics, _ = fe.db.GetRecentMetrics(ctx, serviceName, "error_count", window)
-		if len(errorMetrics) > 0 {
-			fe.extractErrorFeatures(errorMetrics, features)
-		}
-	} // Extract Latency features
-	latencyMetrics, err := fe.db.GetRecentMetrics(ctx, serviceName, "response_time", window)
-	if err == nil && len(latencyMetrics) > 0 {
+	latencyMetrics := series[MetricLatency]
+	if len(latencyMetrics) > 0 {
 		fe.extractLatencyFeatures(latencyMetrics, features)
 	}
 
-	// Try alternative latency metric names
-	if len(latencyMetrics) == 0 {
-		latencyMetrics, _ = fe.db.GetRecentMetrics(ctx, serviceName, "response_time_p95_ms", window)
-		if len(latencyMetrics) > 0 {
-			fe.extractLatencyFeatures(latencyMetrics, features)
+	// Prefer real percentiles computed from the Prometheus histogram over the
+	// approximation from raw latency samples
+	fe.applyHistogramPercentiles(ctx, serviceName, window, features)
+
+	for _, canonical := range []string{MetricCPU, MetricMemory, MetricErrors, MetricLatency} {
+		if len(series[canonical]) > 0 || (canonical == MetricLatency && features.HistogramPercentiles) {
+			continue
 		}
+		features.MissingMetrics = append(features.MissingMetrics, canonical)
 	}
 
 	// Calculate cross-metric correlations
-	if len(cpuMetrics) > 0 && len(memMetrics) > 0 {
-		features.CPUMemoryCorr = CalculatePearsonCorrelation(cpuMetrics, memMetrics)
+	minSamples, corrWindow := fe.correlationSettings()
+	cpuCorr := trimToWindow(cpuMetrics, corrWindow)
+	memCorr := trimToWindow(memMetrics, corrWindow)
+	errCorr := trimToWindow(errorMetrics, corrWindow)
+	latCorr := trimToWindow(latencyMetrics, corrWindow)
+
+	features.Correlations = map[string]CorrelationResult{
+		CorrCPUMemory:    CorrelatePearson(cpuCorr, memCorr, minSamples),
+		CorrCPUError:     CorrelatePearson(cpuCorr, errCorr, minSamples),
+		CorrMemoryError:  CorrelatePearson(memCorr, errCorr, minSamples),
+		CorrLatencyError: CorrelateLagged(latCorr, errCorr, minSamples, maxPropagationLag),
+	}
+	features.CPUMemoryCorr = math.Abs(features.Correlations[CorrCPUMemory].Coefficient)
+	features.CPUErrorCorr = math.Abs(features.Correlations[CorrCPUError].Coefficient)
+	features.MemoryErrorCorr = math.Abs(features.Correlations[CorrMemoryError].Coefficient)
+	features.LatencyErrorCorr = math.Abs(features.Correlations[CorrLatencyError].Coefficient)
+
+	// Pattern detection
+	if len(cpuMetrics) > 10 {
+		fe.detectPatterns(cpuMetrics, features)
 	}
 
-	if len(cpuMetrics) > 0 && len(errorMetrics) > 0 {
-		features.CPUErrorCorr = CalculatePearsonCorrelation(cpuMetrics, errorMetrics)
+	// Calculate composite scores
+	fe.calculateCompositeScores(features)
+
+	return features, nil
+}
+
+// CorrelationKnown reports whether the named correlation had enough samples
+func (f *ServiceFeatures) CorrelationKnown(key string) bool {
+	r, ok := f.Correlations[key]
+	return ok && r.Sufficient()
+}
+
+// capWindow limits window to analyzer.max_feature_window
+func (fe *FeatureExtractor) capWindow(ctx context.Context, serviceName string, window time.Duration) time.Duration {
+	maxWindow := defaultMaxFeatureWindow
+	if cfg := fe.cfg(); cfg != nil {
+		if d, err := time.ParseDuration(cfg.Analyzer.MaxFeatureWindow); err == nil && d > 0 {
+			maxWindow = d
+		}
 	}
+	if window <= maxWindow {
+		return window
+	}
+
+	logger.FromContext(ctx).Warn("Feature window exceeds analyzer.max_feature_window; capping",
+		zap.String("service", serviceName),
+		zap.Duration("requested", window),
+		zap.Duration("max", maxWindow),
+	)
+	return maxWindow
+}
 
-	if len(memMetrics) > 0 && len(errorMetrics) > 0 {
-		features.MemoryErrorCorr = CalculatePearsonCorrelation(memMetrics, errorMetrics)
+// maxSeriesPoints returns analyzer.max_series_points
+func (fe *FeatureExtractor) maxSeriesPoints() int {
+	if cfg := fe.cfg(); cfg != nil && cfg.Analyzer.MaxSeriesPoints > 0 {
+		return cfg.Analyzer.MaxSeriesPoints
 	}
+	return storage.DefaultMaxSeriesPoints
+}
 
-	if len(latencyMetrics) > 0 && len(errorMetrics) > 0 {
-		features.LatencyErrorCorr = CalculatePearsonCorrelation(latencyMetrics, errorMetrics)
+// HasMetric reports whether the canonical metric had samples in the window
+func (f *ServiceFeatures) HasMetric(canonical string) bool {
+	for _, m := range f.MissingMetrics {
+		if m == canonical {
+			return false
+		}
 	}
+	return true
+}
 
-	// Pattern detection
-	if len(cpuMetrics) > 10 {
-		fe.detectPatterns(cpuMetrics, features)
+// coveredSpan is the longest time span covered by any of the series. Metrics
+// are ordered oldest first.
+func coveredSpan(series map[string][]*storage.Metric) time.Duration {
+	var span time.Duration
+	for _, metrics := range series {
+		if len(metrics) < 2 {
+			continue
+		}
+		if d := metrics[len(metrics)-1].Timestamp.Sub(metrics[0].Timestamp); d > span {
+			span = d
+		}
 	}
+	return span
+}
 
-	// Calculate composite scores
-	fe.calculateCompositeScores(features)
+// correlationSettings returns the configured minimum sample count and the
+// trailing window correlations are computed over (0 means the whole window)
+func (fe *FeatureExtractor) correlationSettings() (int, time.Duration) {
+	cfg := fe.cfg()
+	if cfg == nil {
+		return DefaultMinCorrelationSamples, 0
+	}
 
-	return features, nil
+	window, _ := time.ParseDuration(cfg.Analyzer.CorrelationWindow) // validated in LoadConfig
+	return cfg.Analyzer.MinCorrelationSamples, window
+}
+
+// trimToWindow keeps the samples within window of the newest one. Metrics
+// are ordered oldest first.
+func trimToWindow(metrics []*storage.Metric, window time.Duration) []*storage.Metric {
+	if window <= 0 || len(metrics) == 0 {
+		return metrics
+	}
+
+	cutoff := metrics[len(metrics)-1].Timestamp.Add(-window)
+	for i, m := range metrics {
+		if !m.Timestamp.Before(cutoff) {
+			return metrics[i:]
+		}
+	}
+	return nil
 }
 
 func (fe *FeatureExtractor) extractCPUFeatures(metrics []*storage.Metric, features *ServiceFeatures) {
@@ -184,8 +361,7 @@ func (fe *FeatureExtractor) extractCPUFeatures(metrics []*storage.Metric, featur
 	features.CPUMax = maxFloat64(values)
 	features.CPURange = features.CPUMax - features.CPUMin
 
-	slope, _, _, _ := PerformLinearRegression(metrics)
-	features.CPUTrend = slope
+	features.CPUTrend = trendSlope(fe.cfg(), metrics)
 
 	if features.CPUMean > 0 {
 		features.CPUVolatility = features.CPUStdDev / features.CPUMean
@@ -203,8 +379,7 @@ func (fe *FeatureExtractor) extractMemoryFeatures(metrics []*storage.Metric, fea
 	features.MemoryMax = maxFloat64(values)
 	features.MemoryRange = features.MemoryMax - features.MemoryMin
 
-	slope, _, _, _ := PerformLinearRegression(metrics)
-	features.MemoryTrend = slope
+	features.MemoryTrend = trendSlope(fe.cfg(), metrics)
 
 	if features.MemoryMean > 0 {
 		features.MemoryVolatility = features.MemoryStdDev / features.MemoryMean
@@ -213,26 +388,57 @@ func (fe *FeatureExtractor) extractMemoryFeatures(metrics []*storage.Metric, fea
 	features.MemoryAnomalyScore = calculateAnomalyScore(values)
 }
 
-func (fe *FeatureExtractor) extractErrorFeatures(metrics []*storage.Metric, features *ServiceFeatures) {
+// extractErrorFeatures reads spikiness from the raw samples; smoothing would
+// flatten exactly the bursts it measures
+func (fe *FeatureExtractor) extractErrorFeatures(metrics, raw []*storage.Metric, features *ServiceFeatures) {
 	values := extractMetricValues(metrics)
 
 	features.ErrorRateMean = CalculateMean(values)
 	features.ErrorRateMax = maxFloat64(values)
 
-	slope, _, _, _ := PerformLinearRegression(metrics)
-	features.ErrorRateTrend = slope
+	features.ErrorRateTrend = trendSlope(fe.cfg(), metrics)
 
-	features.ErrorRateSpikiness = calculateSpikiness(values)
+	features.SpikinessMethod = fe.spikinessMethod()
+	if features.SpikinessMethod == core.SpikinessPercentile {
+		features.ErrorRateSpikiness = calculatePercentileSpikiness(extractMetricValues(raw))
+	} else {
+		features.ErrorRateSpikiness = calculateSpikiness(extractMetricValues(raw))
+	}
 	features.ErrorAnomalyScore = calculateAnomalyScore(values)
 }
 
+// histogramPercentileMetrics maps the percentile series the observer derives
+// via histogram_quantile to the feature they populate
+var histogramPercentileMetrics = []struct {
+	metricName string
+	apply      func(f *ServiceFeatures, v float64)
+}{
+	{"response_time_p50_ms", func(f *ServiceFeatures, v float64) { f.LatencyP50 = v }},
+	{"response_time_p95_ms", func(f *ServiceFeatures, v float64) { f.LatencyP95 = v }},
+	{"response_time_p99_ms", func(f *ServiceFeatures, v float64) { f.LatencyP99 = v }},
+}
+
+// applyHistogramPercentiles overrides latency percentiles with the window
+// average of the histogram-derived series, when the service exports them
+func (fe *FeatureExtractor) applyHistogramPercentiles(ctx context.Context, serviceName string, window time.Duration, features *ServiceFeatures) {
+	for _, p := range histogramPercentileMetrics {
+		metrics, err := fe.db.GetRecentMetrics(ctx, serviceName, p.metricName, window)
+		if err != nil || len(metrics) == 0 {
+			continue
+		}
+		p.apply(features, CalculateMean(extractMetricValues(metrics)))
+		features.HistogramPercentiles = true
+	}
+}
+
 func (fe *FeatureExtractor) extractLatencyFeatures(metrics []*storage.Metric, features *ServiceFeatures) {
 	values := extractMetricValues(metrics)
 
 	features.LatencyMean = CalculateMean(values)
-	features.LatencyP50 = CalculatePercentile(values, 50)
-	features.LatencyP95 = CalculatePercentile(values, 95)
-	features.LatencyP99 = CalculatePercentile(values, 99)
+	method := fe.percentileMethod()
+	features.LatencyP50 = CalculatePercentile(values, 50, method)
+	features.LatencyP95 = CalculatePercentile(values, 95, method)
+	features.LatencyP99 = CalculatePercentile(values, 99, method)
 	features.LatencyStdDev = CalculateStdDev(values)
 	features.LatencyAnomalyScore = calculateAnomalyScore(values)
 }
@@ -271,7 +477,7 @@ func (fe *FeatureExtractor) detectPatterns(metrics []*storage.Metric, features *
 	}
 
 	// Detect trend
-	slope, _, _, _ := PerformLinearRegression(metrics)
+	slope := trendSlope(fe.cfg(), metrics)
 	if math.Abs(slope) > 0.1 {
 		features.HasTrend = true
 		if slope > 0 {
@@ -295,24 +501,25 @@ func (fe *FeatureExtractor) calculateCompositeScores(features *ServiceFeatures)
 	}
 
 	// Health Score (0-100): inverse of problems
+	weights := fe.healthWeights()
 	healthDeductions := 0.0
 	if features.CPUMean > 80 {
-		healthDeductions += 20
+		healthDeductions += weights.CPU
 	}
 	if features.MemoryMean > 85 {
-		healthDeductions += 20
+		healthDeductions += weights.Memory
 	}
 	if features.ErrorRateMean > 5 {
-		healthDeductions += 30
+		healthDeductions += weights.ErrorRate
 	}
 	if features.LatencyP95 > 2000 {
-		healthDeductions += 15
+		healthDeductions += weights.Latency
 	}
 	if features.CPUTrend > 0.5 {
-		healthDeductions += 10 // growing CPU
+		healthDeductions += weights.CPUTrend // growing CPU
 	}
 	if features.MemoryTrend > 0.5 {
-		healthDeductions += 10 // growing memory (leak?)
+		healthDeductions += weights.MemoryTrend // growing memory (leak?)
 	}
 	features.HealthScore = math.Max(0, 100-healthDeductions)
 
@@ -337,6 +544,29 @@ func (fe *FeatureExtractor) calculateCompositeScores(features *ServiceFeatures)
 
 // ==================== HELPER FUNCTIONS ====================
 
+// smoothSeries returns copies of the metrics with smoothed values, or the
+// metrics unchanged when window <= 1
+func smoothSeries(metrics []*storage.Metric, method string, window int) []*storage.Metric {
+	if window <= 1 || len(metrics) == 0 {
+		return metrics
+	}
+
+	values := extractMetricValues(metrics)
+	if method == core.SmoothingEMA {
+		values = ExponentialMovingAverage(values, window)
+	} else {
+		values = MovingAverage(values, window)
+	}
+
+	smoothed := make([]*storage.Metric, len(metrics))
+	for i, m := range metrics {
+		c := *m
+		c.MetricValue = values[i]
+		smoothed[i] = &c
+	}
+	return smoothed
+}
+
 func extractMetricValues(metrics []*storage.Metric) []float64 {
 	values := make([]float64, len(metrics))
 	for i, m := range metrics {
@@ -392,16 +622,60 @@ func calculateAnomalyScore(values []float64) float64 {
 	return (float64(anomalyCount) / float64(len(values))) * 100
 }
 
-func calculateSpikiness(values []float64) float64 {
-	if len(values) < 2 {
-		return 0
+// spikinessMethod returns the configured error spikiness method
+func (fe *FeatureExtractor) spikinessMethod() string {
+	if cfg := fe.cfg(); cfg != nil && cfg.Analyzer.SpikinessMethod != "" {
+		return cfg.Analyzer.SpikinessMethod
 	}
+	return core.SpikinessCV
+}
 
-	// Calculate successive differences
+// percentileMethod returns the configured latency percentile method
+func (fe *FeatureExtractor) percentileMethod() string {
+	if cfg := fe.cfg(); cfg != nil && cfg.Analyzer.PercentileMethod != "" {
+		return cfg.Analyzer.PercentileMethod
+	}
+	return core.PercentileInterpolated
+}
+
+// successiveDiffs returns the absolute differences between neighbouring values
+func successiveDiffs(values []float64) []float64 {
 	diffs := make([]float64, len(values)-1)
 	for i := 1; i < len(values); i++ {
 		diffs[i-1] = math.Abs(values[i] - values[i-1])
 	}
+	return diffs
+}
+
+// calculatePercentileSpikiness is the ratio of the 95th-percentile successive
+// difference to the median one. A lone spike only moves the top couple of
+// differences, so unlike the coefficient of variation it stays low unless
+// jumps are sustained. When most samples repeat and the median difference is
+// 0, the mean difference stands in for it.
+func calculatePercentileSpikiness(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+
+	diffs := successiveDiffs(values)
+	p95 := CalculatePercentile(diffs, 95, core.PercentileInterpolated)
+	if p95 == 0 {
+		return 0
+	}
+
+	base := CalculatePercentile(diffs, 50, core.PercentileInterpolated)
+	if base == 0 {
+		base = CalculateMean(diffs)
+	}
+	return p95 / base
+}
+
+func calculateSpikiness(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+
+	diffs := successiveDiffs(values)
 
 	meanDiff := CalculateMean(diffs)
 	stdDevDiff := CalculateStdDev(diffs)
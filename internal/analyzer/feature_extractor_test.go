@@ -0,0 +1,260 @@
+package analyzer
+
+import (
+	"context"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/pkg/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestMovingAverages(t *testing.T) {
+	values := []float64{10, 20, 30, 40}
+
+	if got, want := MovingAverage(values, 2), []float64{10, 15, 25, 35}; !slices.Equal(got, want) {
+		t.Errorf("MovingAverage(2) = %v, want %v", got, want)
+	}
+	if got := MovingAverage(values, 1); !slices.Equal(got, values) {
+		t.Errorf("MovingAverage(1) = %v, want the input unchanged", got)
+	}
+
+	// alpha = 2/(3+1) = 0.5
+	if got, want := ExponentialMovingAverage(values, 3), []float64{10, 15, 22.5, 31.25}; !slices.Equal(got, want) {
+		t.Errorf("ExponentialMovingAverage(3) = %v, want %v", got, want)
+	}
+}
+
+func TestSmoothSeriesKeepsRaw(t *testing.T) {
+	raw := seriesOf(5*time.Second, 10, 30, 10, 30)
+	smoothed := smoothSeries(raw, core.SmoothingSMA, 2)
+
+	if got := extractMetricValues(smoothed); !slices.Equal(got, []float64{10, 20, 20, 20}) {
+		t.Errorf("smoothed values = %v, want [10 20 20 20]", got)
+	}
+	if got := extractMetricValues(raw); !slices.Equal(got, []float64{10, 30, 10, 30}) {
+		t.Errorf("raw series was modified to %v", got)
+	}
+	for i := range raw {
+		if !smoothed[i].Timestamp.Equal(raw[i].Timestamp) {
+			t.Errorf("smoothed[%d] moved from %v to %v", i, raw[i].Timestamp, smoothed[i].Timestamp)
+		}
+	}
+}
+
+// TestSmoothingSuppressesNoisyLeak feeds memory that is flat at 80% apart
+// from sample jitter and a 20s burst at the end of the window. On raw
+// samples the burst reads as steep growth and passes the memory leak
+// detector's gate; a 6-sample moving average absorbs it.
+func TestSmoothingSuppressesNoisyLeak(t *testing.T) {
+	memory := seriesOf(5*time.Second, generate(24, func(i int) float64 {
+		switch {
+		case i >= 20:
+			return 98
+		case i%2 == 0:
+			return 84
+		default:
+			return 76
+		}
+	})...)
+
+	leakGate := func(cfg *core.Config) (float64, int) {
+		cfg.ApplyDefaults()
+		fe := NewFeatureExtractor(nil, core.NewConfigStore("", cfg))
+		method, window := cfg.SmoothingFor("checkout")
+
+		features := &ServiceFeatures{}
+		fe.extractMemoryFeatures(smoothSeries(memory, method, window), features)
+		signals, quality := memoryLeakSignals(features)
+		total := 0.0
+		for _, s := range signals {
+			total += s
+		}
+		return total, quality
+	}
+
+	total, quality := leakGate(&core.Config{})
+	if total <= memoryLeakCutoff || quality < 2 {
+		t.Fatalf("raw series scored %.1f with %d quality signals; want it to trip the gate (> %v, >= 2)", total, quality, memoryLeakCutoff)
+	}
+
+	smoothed := &core.Config{}
+	smoothed.Analyzer.SmoothingWindow = 6
+	total, quality = leakGate(smoothed)
+	if total > memoryLeakCutoff && quality >= 2 {
+		t.Errorf("smoothed series scored %.1f with %d quality signals; want it below the gate", total, quality)
+	}
+}
+
+func TestHealthScoreWeights(t *testing.T) {
+	healthOf := func(weights core.HealthWeights, features ServiceFeatures) float64 {
+		cfg := &core.Config{}
+		cfg.Health.Weights = weights
+		cfg.ApplyDefaults()
+		fe := NewFeatureExtractor(nil, core.NewConfigStore("", cfg))
+		fe.calculateCompositeScores(&features)
+		return features.HealthScore
+	}
+
+	// Hot CPU and slow responses, everything else healthy
+	slowAndBusy := ServiceFeatures{CPUMean: 90, LatencyP95: 2500, TrendDirection: "stable"}
+	busy := ServiceFeatures{CPUMean: 90, LatencyP95: 300, TrendDirection: "stable"}
+
+	defaults := core.HealthWeights{}
+	latencyFirst := core.HealthWeights{CPU: 5, Latency: 40}
+
+	if got := healthOf(defaults, slowAndBusy); got != 65 {
+		t.Errorf("default health = %.0f, want 100 - 20 (cpu) - 15 (latency) = 65", got)
+	}
+	if got := healthOf(latencyFirst, slowAndBusy); got != 55 {
+		t.Errorf("latency-weighted health = %.0f, want 100 - 5 - 40 = 55", got)
+	}
+
+	// The same re-weighting forgives a service that is only busy
+	if d, l := healthOf(defaults, busy), healthOf(latencyFirst, busy); l <= d {
+		t.Errorf("busy service: latency-weighted health %.0f, want above the default %.0f", l, d)
+	}
+
+	// Deductions past 100 floor at zero
+	everything := ServiceFeatures{CPUMean: 95, MemoryMean: 95, ErrorRateMean: 20, LatencyP95: 5000, CPUTrend: 2, MemoryTrend: 2}
+	if got := healthOf(core.HealthWeights{CPU: 50, Memory: 50, ErrorRate: 50}, everything); got != 0 {
+		t.Errorf("health = %.0f, want 0 when deductions exceed 100", got)
+	}
+}
+
+func TestFeatureWindowCaps(t *testing.T) {
+	cfg := &core.Config{}
+	cfg.Analyzer.MaxFeatureWindow = "6h"
+	cfg.Analyzer.MaxSeriesPoints = 300
+	cfg.ApplyDefaults()
+	fe := NewFeatureExtractor(nil, core.NewConfigStore("", cfg))
+
+	if got := fe.capWindow(context.Background(), "checkout", time.Hour); got != time.Hour {
+		t.Errorf("capWindow(1h) = %v, want it unchanged", got)
+	}
+	if got := fe.capWindow(context.Background(), "checkout", 48*time.Hour); got != 6*time.Hour {
+		t.Errorf("capWindow(48h) = %v, want the 6h cap", got)
+	}
+	if got := fe.maxSeriesPoints(); got != 300 {
+		t.Errorf("maxSeriesPoints = %d, want 300", got)
+	}
+
+	defaults := &core.Config{}
+	defaults.ApplyDefaults()
+	fe = NewFeatureExtractor(nil, core.NewConfigStore("", defaults))
+	if got := fe.capWindow(context.Background(), "checkout", 7*24*time.Hour); got != defaultMaxFeatureWindow {
+		t.Errorf("capWindow(7d) = %v, want the default %v", got, defaultMaxFeatureWindow)
+	}
+}
+
+func TestCoveredSpan(t *testing.T) {
+	series := map[string][]*storage.Metric{
+		MetricCPU:     seriesOf(time.Minute, generate(31, func(int) float64 { return 50 })...),
+		MetricMemory:  seriesOf(time.Minute, 1, 2, 3),
+		MetricLatency: seriesOf(time.Minute, 100), // a single sample spans nothing
+	}
+	if got := coveredSpan(series); got != 30*time.Minute {
+		t.Errorf("coveredSpan = %v, want the longest series' 30m", got)
+	}
+	if got := coveredSpan(nil); got != 0 {
+		t.Errorf("coveredSpan(nil) = %v, want 0", got)
+	}
+}
+
+// TestSpikinessMethods contrasts the two spikiness measures. A flat error
+// rate with one giant spike reads as spiky to the coefficient of variation
+// but not to the percentile ratio; sustained bursts read as spiky to both.
+func TestSpikinessMethods(t *testing.T) {
+	jitter := func(i int) float64 { return 1 + 0.1*float64(i%2) }
+	lone := generate(60, jitter)
+	lone[30] = 50
+	bursts := generate(60, func(i int) float64 {
+		if i%10 >= 7 {
+			return jitter(i) + 20
+		}
+		return jitter(i)
+	})
+
+	if cv := calculateSpikiness(lone); cv < 3 {
+		t.Errorf("cv spikiness of a lone spike = %.2f, want at least 3", cv)
+	}
+	if p := calculatePercentileSpikiness(lone); p > 1.5 {
+		t.Errorf("percentile spikiness of a lone spike = %.2f, want at most 1.5", p)
+	}
+	if cv := calculateSpikiness(bursts); cv < 1.5 {
+		t.Errorf("cv spikiness of sustained bursts = %.2f, want at least 1.5", cv)
+	}
+	if p := calculatePercentileSpikiness(bursts); p < 10 {
+		t.Errorf("percentile spikiness of sustained bursts = %.2f, want at least 10", p)
+	}
+
+	flat := generate(10, func(int) float64 { return 2 })
+	if p := calculatePercentileSpikiness(flat); p != 0 {
+		t.Errorf("percentile spikiness of a flat series = %.2f, want 0", p)
+	}
+	// Mostly repeated samples: the median difference is 0, so the mean (0.2)
+	// stands in for it against a 95th percentile of 0.2
+	repeated := generate(21, func(i int) float64 {
+		if i == 20 {
+			return 5
+		}
+		return 1
+	})
+	if p := calculatePercentileSpikiness(repeated); math.Abs(p-1) > 1e-9 {
+		t.Errorf("percentile spikiness with a zero median = %v, want 1", p)
+	}
+}
+
+func TestPercentileMethodConfig(t *testing.T) {
+	cfg := &core.Config{}
+	cfg.ApplyDefaults()
+	if got := NewFeatureExtractor(nil, core.NewConfigStore("", cfg)).percentileMethod(); got != core.PercentileInterpolated {
+		t.Errorf("default percentileMethod = %q, want %q", got, core.PercentileInterpolated)
+	}
+
+	cfg.Analyzer.PercentileMethod = core.PercentileNearestRank
+	if got := NewFeatureExtractor(nil, core.NewConfigStore("", cfg)).percentileMethod(); got != core.PercentileNearestRank {
+		t.Errorf("percentileMethod = %q, want %q", got, core.PercentileNearestRank)
+	}
+}
+
+func TestSpikinessMethodConfig(t *testing.T) {
+	cfg := &core.Config{}
+	cfg.ApplyDefaults()
+	if got := NewFeatureExtractor(nil, core.NewConfigStore("", cfg)).spikinessMethod(); got != core.SpikinessCV {
+		t.Errorf("default spikinessMethod = %q, want %q", got, core.SpikinessCV)
+	}
+
+	cfg.Analyzer.SpikinessMethod = core.SpikinessPercentile
+	if got := NewFeatureExtractor(nil, core.NewConfigStore("", cfg)).spikinessMethod(); got != core.SpikinessPercentile {
+		t.Errorf("spikinessMethod = %q, want %q", got, core.SpikinessPercentile)
+	}
+}
+
+func TestFeatureWindowCapLogsRequestID(t *testing.T) {
+	observed, logs := observer.New(zapcore.WarnLevel)
+	previous := logger.Log
+	logger.Log = zap.New(observed)
+	t.Cleanup(func() { logger.Log = previous })
+
+	cfg := &core.Config{}
+	cfg.Analyzer.MaxFeatureWindow = "6h"
+	cfg.ApplyDefaults()
+	fe := NewFeatureExtractor(nil, core.NewConfigStore("", cfg))
+
+	fe.capWindow(logger.WithRequestID(context.Background(), "req-789"), "checkout", 48*time.Hour)
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("%d lines logged, want the capping warning", len(entries))
+	}
+	if got := entries[0].ContextMap()["request_id"]; got != "req-789" {
+		t.Errorf("request_id = %v, want req-789 from the request context", got)
+	}
+}
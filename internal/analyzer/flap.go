@@ -0,0 +1,110 @@
+package analyzer
+
+import (
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+// Flap damping defaults, used when analyzer.transition_dwell and
+// analyzer.transition_hysteresis are unset
+const (
+	defaultTransitionDwell      = 2
+	defaultTransitionHysteresis = 5.0
+)
+
+// transitionDwell returns how many consecutive diagnoses must agree before a
+// change is committed
+func transitionDwell(cfg *core.Config) int {
+	if cfg != nil && cfg.Analyzer.TransitionDwell > 0 {
+		return cfg.Analyzer.TransitionDwell
+	}
+	return defaultTransitionDwell
+}
+
+// transitionHysteresis returns the confidence margin for leaving a severity;
+// 0 when disabled
+func transitionHysteresis(cfg *core.Config) float64 {
+	if cfg == nil || cfg.Analyzer.TransitionHysteresis == 0 {
+		return defaultTransitionHysteresis
+	}
+	if cfg.Analyzer.TransitionHysteresis < 0 {
+		return 0
+	}
+	return cfg.Analyzer.TransitionHysteresis
+}
+
+// dwell counts consecutive diagnoses that agree on a candidate state. The
+// same diagnosis seen twice (one shared result returned to two callers)
+// counts once.
+type dwell struct {
+	candidate      string
+	count          int
+	lastPrediction string
+}
+
+// observe records a diagnosis proposing candidate and returns how many
+// consecutive diagnoses have proposed it
+func (d *dwell) observe(candidate, predictionID string) int {
+	if predictionID != "" && predictionID == d.lastPrediction {
+		return d.count
+	}
+	d.lastPrediction = predictionID
+	if candidate != d.candidate {
+		d.candidate = candidate
+		d.count = 0
+	}
+	d.count++
+	return d.count
+}
+
+// detectionCutoffs are the confidences the enhanced detectors must exceed to
+// report their problem
+var detectionCutoffs = map[DetectionType]float64{
+	DetectionMemoryLeak:         memoryLeakCutoff,
+	DetectionResourceExhaustion: resourceExhaustionCutoff,
+	DetectionDeploymentBug:      deploymentBugCutoff,
+	DetectionExternalFailure:    externalFailureCutoff,
+	DetectionCascadingFailure:   cascadeFailureCutoff,
+}
+
+// holdSeverity applies hysteresis to a downward move out of a problem
+// severity. Between two problem severities, the old one is kept while the
+// primary detection's confidence is within margin of its cutoff. On a move to
+// healthy, the old one is kept while any detector's confidence is within
+// margin of its detection cutoff. Upward moves and moves out of healthy are
+// returned unchanged.
+func holdSeverity(risk *RiskClassifier, oldSeverity, newSeverity string, diag *UltimateDiagnosis, margin float64) string {
+	if margin <= 0 || oldSeverity == SeverityNone ||
+		severityRank[newSeverity] >= severityRank[oldSeverity] {
+		return newSeverity
+	}
+	if newSeverity == SeverityNone {
+		if nearDetection(diag.AllDetections, margin) {
+			return oldSeverity
+		}
+		return newSeverity
+	}
+	if severityRank[risk.SeverityForConfidence(diag.PrimaryDetection.Confidence+margin)] >= severityRank[oldSeverity] {
+		return oldSeverity
+	}
+	return newSeverity
+}
+
+// nearDetection reports whether a detector fell short of its detection
+// cutoff by less than margin. The cutoffs apply to the detector's own
+// confidence, before calibration.
+func nearDetection(detections []*Detection, margin float64) bool {
+	for _, d := range detections {
+		cutoff, ok := detectionCutoffs[d.Type]
+		if !ok {
+			continue
+		}
+		confidence := d.Confidence
+		if d.RawConfidence != 0 {
+			confidence = d.RawConfidence
+		}
+		if confidence > cutoff-margin {
+			return true
+		}
+	}
+	return false
+}
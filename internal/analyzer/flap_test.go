@@ -0,0 +1,131 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+func TestFlapDampingDefaults(t *testing.T) {
+	if got := transitionDwell(nil); got != defaultTransitionDwell {
+		t.Errorf("transitionDwell(nil) = %d, want %d", got, defaultTransitionDwell)
+	}
+	if got := transitionHysteresis(nil); got != defaultTransitionHysteresis {
+		t.Errorf("transitionHysteresis(nil) = %v, want %v", got, defaultTransitionHysteresis)
+	}
+
+	cfg := &core.Config{}
+	cfg.Analyzer.TransitionDwell = 3
+	cfg.Analyzer.TransitionHysteresis = 8
+	if transitionDwell(cfg) != 3 || transitionHysteresis(cfg) != 8 {
+		t.Errorf("configured damping = %d, %v, want 3, 8", transitionDwell(cfg), transitionHysteresis(cfg))
+	}
+	cfg.Analyzer.TransitionHysteresis = -1
+	if got := transitionHysteresis(cfg); got != 0 {
+		t.Errorf("negative hysteresis = %v, want 0 (disabled)", got)
+	}
+}
+
+func TestHoldSeverity(t *testing.T) {
+	cfg := &core.Config{}
+	cfg.ApplyDefaults()
+	risk := NewRiskClassifier(core.NewConfigStore("", cfg)) // HIGH from 70, CRITICAL from 85
+
+	// confidence is the primary detection's; on a move to healthy, that of an
+	// undetected memory leak (detection cutoff 65)
+	tests := []struct {
+		name       string
+		old, new   string
+		confidence float64
+		want       string
+	}{
+		{"within margin of the cutoff", SeverityHigh, SeverityMedium, 67, SeverityHigh},
+		{"clearly below the cutoff", SeverityHigh, SeverityMedium, 60, SeverityMedium},
+		{"upward moves pass", SeverityMedium, SeverityHigh, 70, SeverityHigh},
+		{"recovering within margin of detection", SeverityMedium, SeverityNone, 62, SeverityMedium},
+		{"recovering clearly below detection", SeverityCritical, SeverityNone, 55, SeverityNone},
+		{"leaving healthy passes", SeverityNone, SeverityLow, 30, SeverityLow},
+		{"critical held", SeverityCritical, SeverityHigh, 82, SeverityCritical},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := holdSeverity(risk, tt.old, tt.new, diagnosisWith(tt.new, tt.confidence), 5); got != tt.want {
+				t.Errorf("holdSeverity(%s -> %s at %.0f) = %s, want %s", tt.old, tt.new, tt.confidence, got, tt.want)
+			}
+		})
+	}
+
+	if got := holdSeverity(risk, SeverityHigh, SeverityMedium, diagnosisWith(SeverityMedium, 67), 0); got != SeverityMedium {
+		t.Errorf("holdSeverity with hysteresis off = %s, want MEDIUM", got)
+	}
+	if got := holdSeverity(risk, SeverityHigh, SeverityNone, diagnosisWith(SeverityNone, 62), 0); got != SeverityNone {
+		t.Errorf("holdSeverity to healthy with hysteresis off = %s, want NONE", got)
+	}
+}
+
+// diagnosisWith is a memory leak diagnosis at confidence, detected unless
+// severity is NONE
+func diagnosisWith(severity string, confidence float64) *UltimateDiagnosis {
+	leak := &Detection{Type: DetectionMemoryLeak, Detected: severity != SeverityNone, Confidence: confidence, Severity: severity}
+	primary := leak
+	if !leak.Detected {
+		primary = &Detection{Type: DetectionHealthy, Confidence: 90, Severity: SeverityNone}
+	}
+	return &UltimateDiagnosis{PrimaryDetection: primary, AllDetections: []*Detection{leak}}
+}
+
+// TestOscillatingServiceTransitionsOnce feeds services whose confidence
+// hovers either side of a cutoff: one settles into high and then hovers at the
+// HIGH cutoff (70), the other hovers at the memory leak detector's cutoff (65),
+// between healthy and a MEDIUM leak. Undamped, every crossing is a transition;
+// damped, only the first is.
+func TestOscillatingServiceTransitionsOnce(t *testing.T) {
+	tests := []struct {
+		name        string
+		confidences []float64
+		want        string
+	}{
+		{"severity boundary", []float64{76, 75, 68, 72, 67, 71, 69, 73, 66, 70, 68}, SeverityNone + "->" + SeverityHigh},
+		{"healthy boundary", []float64{58, 67, 68, 63, 66, 62, 67, 64, 66, 61, 67}, SeverityNone + "->" + SeverityMedium},
+	}
+
+	newTracker := func(dwell int, hysteresis float64) *TransitionTracker {
+		cfg := &core.Config{}
+		cfg.Analyzer.TransitionDwell = dwell
+		cfg.Analyzer.TransitionHysteresis = hysteresis
+		cfg.ApplyDefaults()
+		return NewTransitionTracker(nil, nil, core.NewConfigStore("", cfg))
+	}
+	run := func(tracker *TransitionTracker, confidences []float64) []string {
+		var committed []string
+		for i, confidence := range confidences {
+			// A memory leak is detected above its cutoff; below it the
+			// service is diagnosed healthy
+			severity := SeverityNone
+			if confidence > memoryLeakCutoff {
+				severity = tracker.risk.SeverityForConfidence(confidence)
+			}
+			diag := diagnosisWith(severity, confidence)
+			diag.ServiceName = "checkout"
+			diag.Timestamp = testEpoch.Add(time.Duration(i) * time.Minute)
+			diag.PredictionID = diag.Timestamp.String()
+			if transition := tracker.advance(diag); transition != nil {
+				committed = append(committed, transition.OldSeverity+"->"+transition.NewSeverity)
+			}
+		}
+		return committed
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := run(newTracker(1, -1), tt.confidences); len(got) < 5 {
+				t.Fatalf("undamped transitions = %v, want the series to flap", got)
+			}
+			got := run(newTracker(0, 0), tt.confidences) // defaults: dwell 2, hysteresis 5
+			if len(got) != 1 || got[0] != tt.want {
+				t.Errorf("damped transitions = %v, want a single %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package analyzer
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+)
+
+// gcPausedLatency returns n samples around 100ms where every tenth request
+// hits a ~400ms pause
+func gcPausedLatency(n int) []float64 {
+	return generate(n, func(i int) float64 {
+		if i%10 == 9 {
+			return 400 + float64(i%3)*10
+		}
+		return 100 + float64(i%7)*2
+	})
+}
+
+func TestLatencyBimodality(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []float64
+		bimodal bool
+	}{
+		{"GC pauses", gcPausedLatency(120), true},
+		{"uniform spread", generate(120, func(i int) float64 { return 100 + float64(i%11)*3 }), false},
+		{"skewed tail", generate(120, func(i int) float64 {
+			return 100 + 50*-math.Log(1-(float64(i)+0.5)/120)
+		}), false},
+		{"lone outlier", generate(120, func(i int) float64 {
+			if i == 60 {
+				return 900
+			}
+			return 100 + float64(i%7)*2
+		}), false},
+		{"slow majority", generate(120, func(i int) float64 {
+			if i%2 == 0 {
+				return 400
+			}
+			return 100
+		}), false},
+		{"too few samples", gcPausedLatency(minBimodalitySamples - 1), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := latencyBimodality(tt.values)
+			if b.Bimodal != tt.bimodal {
+				t.Errorf("bimodal = %v, want %v (%+v)", b.Bimodal, tt.bimodal, b)
+			}
+			if b.Samples != len(tt.values) {
+				t.Errorf("samples = %d, want %d", b.Samples, len(tt.values))
+			}
+		})
+	}
+}
+
+func TestLatencyBimodalitySplit(t *testing.T) {
+	b := latencyBimodality(gcPausedLatency(100))
+	if math.Abs(b.SlowShare-0.1) > 1e-9 {
+		t.Errorf("slow share = %.3f, want 0.1", b.SlowShare)
+	}
+	if b.FastMean < 100 || b.FastMean > 112 || b.SlowMean < 400 || b.SlowMean > 420 {
+		t.Errorf("means = %.1f/%.1f, want ~106/~410", b.FastMean, b.SlowMean)
+	}
+	if b.Separation <= bimodalSeparation {
+		t.Errorf("separation = %.2f, want above %.0f", b.Separation, bimodalSeparation)
+	}
+
+	// Constant clusters have no spread; D is capped rather than +Inf
+	constant := generate(100, func(i int) float64 {
+		if i%10 == 9 {
+			return 400
+		}
+		return 100
+	})
+	if b := latencyBimodality(constant); b.Separation != maxReportedSeparation || !b.Bimodal {
+		t.Errorf("constant clusters = %+v, want bimodal at D %.0f", b, maxReportedSeparation)
+	}
+}
+
+func TestDetectGCPressure(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+
+	end := time.Now().Add(-time.Minute)
+	latency := gcPausedLatency(50)
+	memory := generate(50, func(i int) float64 { return 60 + float64(i)*0.2 })
+	storagetest.Seed(t, db, storagetest.Series(service, MetricLatency, end, 30*time.Second, latency...))
+	storagetest.Seed(t, db, storagetest.Series(service, MetricMemory, end, 30*time.Second, memory...))
+	storagetest.Seed(t, db, storagetest.Series(service, MetricCPU, end, 30*time.Second, generate(50, func(int) float64 { return 40 })...))
+
+	cfg := &core.Config{}
+	cfg.ApplyDefaults()
+	store := core.NewConfigStore("", cfg)
+	ed := NewEnhancedDetector(NewFeatureExtractor(db, store), store)
+
+	d, err := ed.DetectGCPressure(context.Background(), service)
+	if err != nil {
+		t.Fatalf("DetectGCPressure: %v", err)
+	}
+	if d.Type != DetectionGCPressure || !d.Detected {
+		t.Fatalf("detection = %s detected=%v, want a GC pressure detection", d.Type, d.Detected)
+	}
+	// Bimodal latency with growing memory earns the trend bonus
+	if d.Confidence < 65 {
+		t.Errorf("confidence = %.1f, want at least 65 with memory growing", d.Confidence)
+	}
+	if b, ok := d.Evidence["latency_bimodality"].(LatencyBimodality); !ok || !b.Bimodal {
+		t.Errorf("evidence = %v, want a bimodal split", d.Evidence["latency_bimodality"])
+	}
+}
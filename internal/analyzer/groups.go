@@ -0,0 +1,114 @@
+package analyzer
+
+import (
+	"context"
+	"sort"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+// maxGroupOffenders is how many of a group's least healthy services are
+// listed as its worst offenders
+const maxGroupOffenders = 5
+
+// GroupAnalysis aggregates the diagnoses of every service in a group
+type GroupAnalysis struct {
+	Group              string              `json:"group"`
+	ServiceCount       int                 `json:"service_count"`
+	AnalyzedCount      int                 `json:"analyzed_count"` // services whose diagnosis succeeded
+	AverageHealth      float64             `json:"average_health"`
+	MinHealth          float64             `json:"min_health"`
+	RequiringAttention int                 `json:"requiring_attention"`
+	WorstSeverity      string              `json:"worst_severity"`
+	WorstOffenders     []ServiceComparison `json:"worst_offenders"`
+	Services           []ServiceComparison `json:"services"`
+}
+
+// GroupMembers returns the services in a group: those whose metrics carry
+// the group label, plus those service_groups assigns to it, minus those
+// service_groups assigns elsewhere
+func (ua *UltimateAnalyzer) GroupMembers(ctx context.Context, group string) ([]string, error) {
+	labelled, err := ua.db.GetServicesByGroup(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+	return groupMembers(ua.cfg(), group, labelled), nil
+}
+
+// groupMembers merges the services labelled with a group with the
+// service_groups assignments, which take precedence
+func groupMembers(cfg *core.Config, group string, labelled []string) []string {
+	members := make(map[string]bool)
+	for _, service := range labelled {
+		if cfg != nil {
+			if configured := cfg.GroupOf(service); configured != "" && configured != group {
+				continue
+			}
+		}
+		members[service] = true
+	}
+	if cfg != nil {
+		for _, service := range cfg.ServicesInGroup(group) {
+			members[service] = true
+		}
+	}
+
+	services := make([]string, 0, len(members))
+	for service := range members {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	return services
+}
+
+// AnalyzeGroup diagnoses the services concurrently and aggregates them into
+// the group's health
+func (ua *UltimateAnalyzer) AnalyzeGroup(ctx context.Context, group string, services []string) (*GroupAnalysis, error) {
+	comparisons, err := ua.CompareServices(ctx, services)
+	if err != nil {
+		return nil, err
+	}
+	return summarizeGroup(group, len(services), comparisons), nil
+}
+
+// summarizeGroup aggregates a group's comparisons, which CompareServices
+// sorts worst-first
+func summarizeGroup(group string, serviceCount int, comparisons []ServiceComparison) *GroupAnalysis {
+	analysis := &GroupAnalysis{
+		Group:         group,
+		ServiceCount:  serviceCount,
+		AnalyzedCount: len(comparisons),
+		WorstSeverity: SeverityNone,
+		Services:      comparisons,
+	}
+	if len(comparisons) == 0 {
+		return analysis
+	}
+
+	analysis.MinHealth = comparisons[0].HealthScore
+	total := 0.0
+	for _, c := range comparisons {
+		total += c.HealthScore
+		if c.RequiresAttention {
+			analysis.RequiringAttention++
+		}
+		if severityRank[c.Severity] > severityRank[analysis.WorstSeverity] {
+			analysis.WorstSeverity = c.Severity
+		}
+	}
+	analysis.AverageHealth = total / float64(len(comparisons))
+
+	for _, c := range comparisons {
+		if len(analysis.WorstOffenders) == maxGroupOffenders {
+			break
+		}
+		if c.RequiresAttention {
+			analysis.WorstOffenders = append(analysis.WorstOffenders, c)
+		}
+	}
+	if analysis.WorstOffenders == nil {
+		analysis.WorstOffenders = []ServiceComparison{}
+	}
+
+	return analysis
+}
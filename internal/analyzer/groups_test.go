@@ -0,0 +1,72 @@
+package analyzer
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+func TestGroupMembers(t *testing.T) {
+	cfg := &core.Config{ServiceGroups: map[string]string{
+		"inventory": "checkout",  // configured only
+		"search":    "discovery", // labelled checkout, configured elsewhere
+	}}
+	labelled := []string{"payments", "search", "cart"}
+
+	got := groupMembers(cfg, "checkout", labelled)
+	if want := []string{"cart", "inventory", "payments"}; !slices.Equal(got, want) {
+		t.Errorf("groupMembers = %v, want %v", got, want)
+	}
+
+	if got := groupMembers(nil, "checkout", labelled); !slices.Equal(got, []string{"cart", "payments", "search"}) {
+		t.Errorf("groupMembers without config = %v, want the labelled services sorted", got)
+	}
+	if got := groupMembers(cfg, "unknown", nil); len(got) != 0 {
+		t.Errorf("groupMembers(unknown) = %v, want none", got)
+	}
+}
+
+func TestSummarizeGroup(t *testing.T) {
+	// Worst-first, as CompareServices returns them
+	comparisons := []ServiceComparison{
+		{ServiceName: "payments", HealthScore: 30, Severity: SeverityCritical, RequiresAttention: true},
+		{ServiceName: "cart", HealthScore: 60, Severity: SeverityHigh, RequiresAttention: true},
+		{ServiceName: "inventory", HealthScore: 90, Severity: SeverityLow},
+	}
+
+	analysis := summarizeGroup("checkout", 4, comparisons)
+	if analysis.ServiceCount != 4 || analysis.AnalyzedCount != 3 {
+		t.Errorf("counts = %d/%d, want 4 services with 3 analyzed", analysis.ServiceCount, analysis.AnalyzedCount)
+	}
+	if analysis.AverageHealth != 60 || analysis.MinHealth != 30 {
+		t.Errorf("health = avg %v min %v, want 60 and 30", analysis.AverageHealth, analysis.MinHealth)
+	}
+	if analysis.WorstSeverity != SeverityCritical || analysis.RequiringAttention != 2 {
+		t.Errorf("worst = %s with %d needing attention, want %s and 2", analysis.WorstSeverity, analysis.RequiringAttention, SeverityCritical)
+	}
+	var offenders []string
+	for _, c := range analysis.WorstOffenders {
+		offenders = append(offenders, c.ServiceName)
+	}
+	if !slices.Equal(offenders, []string{"payments", "cart"}) {
+		t.Errorf("worst offenders = %v, want [payments cart]", offenders)
+	}
+}
+
+func TestSummarizeGroupCapsOffenders(t *testing.T) {
+	comparisons := make([]ServiceComparison, maxGroupOffenders+3)
+	for i := range comparisons {
+		comparisons[i] = ServiceComparison{ServiceName: string(rune('a' + i)), HealthScore: float64(10 * i), Severity: SeverityHigh, RequiresAttention: true}
+	}
+	if got := summarizeGroup("g", len(comparisons), comparisons).WorstOffenders; len(got) != maxGroupOffenders {
+		t.Errorf("got %d worst offenders, want the cap of %d", len(got), maxGroupOffenders)
+	}
+}
+
+func TestSummarizeGroupNothingAnalyzed(t *testing.T) {
+	analysis := summarizeGroup("checkout", 2, nil)
+	if analysis.AnalyzedCount != 0 || analysis.WorstSeverity != SeverityNone || analysis.WorstOffenders != nil {
+		t.Errorf("analysis = %+v, want an empty summary", analysis)
+	}
+}
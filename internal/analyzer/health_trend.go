@@ -0,0 +1,228 @@
+package analyzer
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Health trajectories
+const (
+	TrajectoryImproving = "improving"
+	TrajectoryStable    = "stable"
+	TrajectoryDegrading = "degrading"
+	TrajectoryUnknown   = "unknown" // not enough history
+)
+
+const (
+	DefaultHealthTrendWindow = 30 * time.Minute
+
+	// Slopes within ±0.5 points/min are treated as flat
+	healthTrendStableSlope = 0.5
+
+	// The adjusted score projects the trend this far ahead, capped at
+	// ±maxHealthTrendAdjustment so a noisy history can't dominate
+	healthTrendHorizon       = 10 * time.Minute
+	maxHealthTrendAdjustment = 15.0
+
+	minHealthTrendSamples = 3
+
+	// healthIntelligenceLookback covers the oldest HealthHistory point (1h)
+	// with room for a record shortly before it
+	healthIntelligenceLookback = time.Hour + 15*time.Minute
+
+	// MaxHealthTimelineWindow bounds GET /health/:service/timeline
+	MaxHealthTimelineWindow = 7 * 24 * time.Hour
+)
+
+// HealthTrend is a point health score together with its recent direction
+type HealthTrend struct {
+	ServiceName   string                `json:"service_name"`
+	Score         float64               `json:"score"`          // current point score
+	AdjustedScore float64               `json:"adjusted_score"` // score shifted by the trend
+	Trajectory    string                `json:"trajectory"`
+	Slope         float64               `json:"slope_per_minute"` // time-weighted, health points per minute
+	Samples       int                   `json:"samples"`
+	Window        string                `json:"window"`
+	History       []storage.HealthPoint `json:"history"`
+	Timestamp     time.Time             `json:"timestamp"`
+}
+
+// HealthTrend diagnoses the service and weighs its health score against the
+// recorded health history over the window. Recent points count more than old
+// ones (half-life of a third of the window), so a service that just turned
+// around is reported by where it is heading rather than where it has been.
+func (ua *UltimateAnalyzer) HealthTrend(ctx context.Context, serviceName string, window time.Duration) (*HealthTrend, *UltimateDiagnosis, error) {
+	if window <= 0 {
+		window = DefaultHealthTrendWindow
+	}
+
+	// Read the history first: the diagnosis records its own score
+	history, err := ua.db.GetHealthHistory(ctx, serviceName, window)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	diagnosis, err := ua.DiagnoseService(ctx, serviceName)
+	if err != nil {
+		return nil, nil, err
+	}
+	history = append(history, storage.HealthPoint{Timestamp: diagnosis.Timestamp, HealthScore: diagnosis.HealthScore})
+
+	trend := &HealthTrend{
+		ServiceName:   serviceName,
+		Score:         diagnosis.HealthScore,
+		AdjustedScore: diagnosis.HealthScore,
+		Trajectory:    TrajectoryUnknown,
+		Samples:       len(history),
+		Window:        window.String(),
+		History:       history,
+		Timestamp:     diagnosis.Timestamp,
+	}
+	if len(history) < minHealthTrendSamples {
+		return trend, diagnosis, nil
+	}
+
+	trend.Slope = weightedHealthSlope(history, diagnosis.Timestamp, window/3)
+	trend.Trajectory = classifyTrajectory(trend.Slope)
+	trend.AdjustedScore = adjustedHealth(diagnosis.HealthScore, trend.Slope)
+
+	return trend, diagnosis, nil
+}
+
+// adjustedHealth projects score along slope over healthTrendHorizon, moving
+// it by at most maxHealthTrendAdjustment and keeping it within 0-100
+func adjustedHealth(score, slope float64) float64 {
+	adjustment := slope * healthTrendHorizon.Minutes()
+	adjustment = math.Max(-maxHealthTrendAdjustment, math.Min(maxHealthTrendAdjustment, adjustment))
+	return math.Max(0, math.Min(100, score+adjustment))
+}
+
+// HealthTimeline is a service's recorded health scores over a window
+type HealthTimeline struct {
+	ServiceName string                `json:"service_name"`
+	Window      string                `json:"window"`
+	Points      []storage.HealthPoint `json:"points"`
+	Samples     int                   `json:"samples"`
+	Min         float64               `json:"min"`
+	Max         float64               `json:"max"`
+	Mean        float64               `json:"mean"`
+	Trajectory  string                `json:"trajectory"`
+	Slope       float64               `json:"slope_per_minute"` // time-weighted, half-life a third of the window
+}
+
+// HealthTimeline returns the health scores recorded for the service over the
+// window, oldest first, with a summary. Unlike HealthTrend it reads only
+// stored scores and doesn't run a diagnosis.
+func (ua *UltimateAnalyzer) HealthTimeline(ctx context.Context, serviceName string, window time.Duration) (*HealthTimeline, error) {
+	points, err := ua.db.GetHealthHistory(ctx, serviceName, window)
+	if err != nil {
+		return nil, err
+	}
+
+	timeline := &HealthTimeline{
+		ServiceName: serviceName,
+		Window:      window.String(),
+		Points:      points,
+		Samples:     len(points),
+		Trajectory:  TrajectoryUnknown,
+	}
+	if timeline.Points == nil {
+		timeline.Points = []storage.HealthPoint{}
+	}
+	if len(points) == 0 {
+		return timeline, nil
+	}
+
+	scores := make([]float64, len(points))
+	for i, p := range points {
+		scores[i] = p.HealthScore
+	}
+	timeline.Min, timeline.Max = scores[0], scores[0]
+	for _, s := range scores {
+		timeline.Min = math.Min(timeline.Min, s)
+		timeline.Max = math.Max(timeline.Max, s)
+	}
+	timeline.Mean = CalculateMean(scores)
+
+	if len(points) >= minHealthTrendSamples {
+		timeline.Slope = weightedHealthSlope(points, storage.AsOf(ctx), window/3)
+		timeline.Trajectory = classifyTrajectory(timeline.Slope)
+	}
+	return timeline, nil
+}
+
+// healthAsOf returns the newest recorded score at or before t, or nil when
+// every point is newer. points must be oldest first.
+func healthAsOf(points []storage.HealthPoint, t time.Time) *float64 {
+	var score *float64
+	for i := range points {
+		if points[i].Timestamp.After(t) {
+			break
+		}
+		score = &points[i].HealthScore
+	}
+	return score
+}
+
+// recordHealth stores the diagnosis' health score. Replays over historical
+// data are not recorded.
+func (ua *UltimateAnalyzer) recordHealth(ctx context.Context, diag *UltimateDiagnosis) {
+	if storage.HasAsOf(ctx) {
+		return
+	}
+	point := storage.HealthPoint{Timestamp: diag.Timestamp, HealthScore: diag.HealthScore}
+	if err := ua.db.SaveHealthPoint(ctx, diag.ServiceName, point); err != nil {
+		logger.FromContext(ctx).Warn("Failed to record health score", zap.String("service", diag.ServiceName), zap.Error(err))
+	}
+}
+
+func classifyTrajectory(slope float64) string {
+	switch {
+	case slope > healthTrendStableSlope:
+		return TrajectoryImproving
+	case slope < -healthTrendStableSlope:
+		return TrajectoryDegrading
+	default:
+		return TrajectoryStable
+	}
+}
+
+// weightedHealthSlope fits health against time (minutes) with exponentially
+// decaying weights, newest points weighing most
+func weightedHealthSlope(points []storage.HealthPoint, now time.Time, halfLife time.Duration) float64 {
+	if halfLife <= 0 {
+		halfLife = DefaultHealthTrendWindow / 3
+	}
+
+	var sumW, sumX, sumY float64
+	weights := make([]float64, len(points))
+	xs := make([]float64, len(points))
+	for i, p := range points {
+		age := now.Sub(p.Timestamp)
+		weights[i] = math.Pow(0.5, age.Minutes()/halfLife.Minutes())
+		xs[i] = -age.Minutes()
+		sumW += weights[i]
+		sumX += weights[i] * xs[i]
+		sumY += weights[i] * p.HealthScore
+	}
+	if sumW == 0 {
+		return 0
+	}
+
+	meanX, meanY := sumX/sumW, sumY/sumW
+	var num, den float64
+	for i, p := range points {
+		dx := xs[i] - meanX
+		num += weights[i] * dx * (p.HealthScore - meanY)
+		den += weights[i] * dx * dx
+	}
+	if den == 0 {
+		return 0
+	}
+	return num / den
+}
@@ -0,0 +1,190 @@
+package analyzer
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+)
+
+// healthPoints returns one point per minute ending at testEpoch
+func healthPoints(scores ...float64) []storage.HealthPoint {
+	points := make([]storage.HealthPoint, len(scores))
+	start := testEpoch.Add(-time.Duration(len(scores)-1) * time.Minute)
+	for i, s := range scores {
+		points[i] = storage.HealthPoint{Timestamp: start.Add(time.Duration(i) * time.Minute), HealthScore: s}
+	}
+	return points
+}
+
+func TestHealthTrajectory(t *testing.T) {
+	tests := []struct {
+		name   string
+		scores []float64
+		want   string
+	}{
+		{"improving", generate(30, func(i int) float64 { return 40 + 2*float64(i) }), TrajectoryImproving},
+		{"degrading", generate(30, func(i int) float64 { return 95 - 2*float64(i) }), TrajectoryDegrading},
+		{"flat", generate(30, func(i int) float64 { return 80 + float64(i%2) }), TrajectoryStable},
+		// A symmetric V has no unweighted slope; weighting favors the
+		// recent recovery over the older decline
+		{"recovering", generate(31, func(i int) float64 { return 60 + 2*math.Abs(float64(i-15)) }), TrajectoryImproving},
+		{"relapsing", generate(31, func(i int) float64 { return 90 - 2*math.Abs(float64(i-15)) }), TrajectoryDegrading},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slope := weightedHealthSlope(healthPoints(tt.scores...), testEpoch, DefaultHealthTrendWindow/3)
+			if got := classifyTrajectory(slope); got != tt.want {
+				t.Errorf("trajectory = %s (slope %.2f/min), want %s", got, slope, tt.want)
+			}
+		})
+	}
+}
+
+func TestWeightedHealthSlopeOfLine(t *testing.T) {
+	slope := weightedHealthSlope(healthPoints(generate(20, func(i int) float64 { return 100 - 1.5*float64(i) })...), testEpoch, 10*time.Minute)
+	if math.Abs(slope-(-1.5)) > 1e-9 {
+		t.Errorf("slope of a straight line = %v, want -1.5", slope)
+	}
+}
+
+func TestAdjustedHealth(t *testing.T) {
+	tests := []struct {
+		score, slope, want float64
+	}{
+		{70, 0, 70},
+		{70, 1, 80},  // 10 minutes ahead
+		{70, -1, 60}, // 10 minutes behind
+		{70, 5, 85},  // capped at +15
+		{70, -5, 55}, // capped at -15
+		{95, 1, 100}, // within 0-100
+		{5, -1, 0},
+	}
+	for _, tt := range tests {
+		if got := adjustedHealth(tt.score, tt.slope); got != tt.want {
+			t.Errorf("adjustedHealth(%v, %v) = %v, want %v", tt.score, tt.slope, got, tt.want)
+		}
+	}
+}
+
+func TestHealthAsOf(t *testing.T) {
+	points := healthPoints(90, 80, 70)
+	if got := healthAsOf(points, testEpoch.Add(-time.Hour)); got != nil {
+		t.Errorf("healthAsOf before every point = %v, want nil", *got)
+	}
+	if got := healthAsOf(points, testEpoch.Add(-90*time.Second)); got == nil || *got != 90 {
+		t.Errorf("healthAsOf between the first two points = %v, want 90", got)
+	}
+	if got := healthAsOf(points, testEpoch); got == nil || *got != 70 {
+		t.Errorf("healthAsOf at the newest point = %v, want 70", got)
+	}
+}
+
+// seedHealth records one health score per minute ending a minute ago
+func seedHealth(t *testing.T, db *storage.PostgresClient, service string, scores ...float64) time.Time {
+	t.Helper()
+
+	now := time.Now()
+	start := now.Add(-time.Duration(len(scores)) * time.Minute)
+	for i, s := range scores {
+		point := storage.HealthPoint{Timestamp: start.Add(time.Duration(i) * time.Minute), HealthScore: s}
+		if err := db.SaveHealthPoint(context.Background(), service, point); err != nil {
+			t.Fatalf("SaveHealthPoint: %v", err)
+		}
+	}
+	return now
+}
+
+func TestHealthTimelineMatchesStoredSeries(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+
+	// 90 down to 61, a point a minute
+	scores := generate(30, func(i int) float64 { return 90 - float64(i) })
+	seedHealth(t, db, service, scores...)
+
+	cfg := &core.Config{}
+	cfg.ApplyDefaults()
+	ua := NewUltimateAnalyzer(db, core.NewConfigStore("", cfg))
+
+	timeline, err := ua.HealthTimeline(context.Background(), service, time.Hour)
+	if err != nil {
+		t.Fatalf("HealthTimeline: %v", err)
+	}
+	if timeline.Samples != len(scores) || len(timeline.Points) != len(scores) {
+		t.Fatalf("samples = %d, want %d", timeline.Samples, len(scores))
+	}
+	for i, p := range timeline.Points {
+		if p.HealthScore != scores[i] {
+			t.Fatalf("point %d = %.0f, want %.0f oldest first", i, p.HealthScore, scores[i])
+		}
+	}
+	if timeline.Min != 61 || timeline.Max != 90 || timeline.Mean != 75.5 {
+		t.Errorf("min/max/mean = %.1f/%.1f/%.1f, want 61/90/75.5", timeline.Min, timeline.Max, timeline.Mean)
+	}
+	if timeline.Trajectory != TrajectoryDegrading || math.Abs(timeline.Slope-(-1)) > 0.01 {
+		t.Errorf("trajectory = %s at %.3f/min, want degrading at -1", timeline.Trajectory, timeline.Slope)
+	}
+
+	// A window shorter than the history only sees its tail
+	short, err := ua.HealthTimeline(context.Background(), service, 10*time.Minute+30*time.Second)
+	if err != nil {
+		t.Fatalf("HealthTimeline: %v", err)
+	}
+	if short.Samples != 10 || short.Min != 61 || short.Max != 70 {
+		t.Errorf("10m timeline = %d samples over %.0f-%.0f, want 10 over 61-70", short.Samples, short.Min, short.Max)
+	}
+}
+
+func TestHealthIntelligenceFromStoredHistory(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+
+	// 90 an hour ago, falling a point a minute to 31 a minute ago
+	now := seedHealth(t, db, service, generate(60, func(i int) float64 { return 90 - float64(i) })...)
+
+	cfg := &core.Config{}
+	cfg.ApplyDefaults()
+	ua := NewUltimateAnalyzer(db, core.NewConfigStore("", cfg))
+
+	intel := ua.buildHealthIntelligence(context.Background(), &UltimateDiagnosis{
+		ServiceName: service,
+		Timestamp:   now,
+		HealthScore: 30,
+	})
+
+	// Scores as of 5, 15, 30 and 60 minutes ago
+	history := intel.HealthHistory
+	for _, tt := range []struct {
+		name string
+		got  *float64
+		want float64
+	}{
+		{"5m", history.Last5Minutes, 35},
+		{"15m", history.Last15Minutes, 45},
+		{"30m", history.Last30Minutes, 60},
+		{"1h", history.Last1Hour, 90},
+	} {
+		if tt.got == nil || *tt.got != tt.want {
+			t.Errorf("health %s ago = %v, want %.0f", tt.name, tt.got, tt.want)
+		}
+	}
+
+	if intel.HealthTrend != "DEGRADING" || math.Abs(intel.DegradationRate-(-1)) > 0.05 {
+		t.Errorf("trend = %s at %.3f/min, want DEGRADING at -1", intel.HealthTrend, intel.DegradationRate)
+	}
+
+	// A service with no records has no history and no trend
+	fresh := ua.buildHealthIntelligence(context.Background(), &UltimateDiagnosis{
+		ServiceName: service + "-new",
+		Timestamp:   now,
+		HealthScore: 30,
+	})
+	if fresh.HealthHistory.Last5Minutes != nil || fresh.HealthTrend != "UNKNOWN" || fresh.DegradationRate != 0 {
+		t.Errorf("fresh service = %+v, trend %s, want no history and UNKNOWN", fresh.HealthHistory, fresh.HealthTrend)
+	}
+}
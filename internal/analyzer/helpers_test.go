@@ -0,0 +1,41 @@
+package analyzer
+
+import (
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+)
+
+// testEpoch anchors generated series so results don't depend on the clock
+var testEpoch = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// newTestDetector returns a detector over cfg with defaults applied. It has
+// no database, so only the features-based scoring can run.
+func newTestDetector(cfg *core.Config) *EnhancedDetector {
+	cfg.ApplyDefaults()
+	store := core.NewConfigStore("", cfg)
+	return NewEnhancedDetector(NewFeatureExtractor(nil, store), store)
+}
+
+// seriesOf returns one sample per step starting at testEpoch
+func seriesOf(step time.Duration, values ...float64) []*storage.Metric {
+	metrics := make([]*storage.Metric, len(values))
+	for i, v := range values {
+		metrics[i] = &storage.Metric{
+			Timestamp:   testEpoch.Add(time.Duration(i) * step),
+			MetricName:  "test_metric",
+			MetricValue: v,
+		}
+	}
+	return metrics
+}
+
+// generate returns n values of f(i)
+func generate(n int, f func(i int) float64) []float64 {
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = f(i)
+	}
+	return values
+}
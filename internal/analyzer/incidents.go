@@ -0,0 +1,272 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/notify"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultIncidentResolveAfter = 10 * time.Minute
+	minIncidentSweepInterval    = 10 * time.Second
+
+	// maxOpenIncidents bounds how many open incidents are reloaded at startup
+	maxOpenIncidents = 1000
+)
+
+type incidentKey struct {
+	service string
+	problem DetectionType
+}
+
+// IncidentTracker collapses consecutive diagnoses of the same service and
+// problem into one open incident, so a problem that persists across many
+// diagnoses notifies once when it opens and again only if it gets worse
+type IncidentTracker struct {
+	db       *storage.PostgresClient
+	notifier notify.Notifier
+	config   *core.ConfigStore
+
+	mu      sync.Mutex
+	open    map[incidentKey]*storage.Incident
+	pending map[string]*dwell // service -> problem not yet held long enough to open
+}
+
+func NewIncidentTracker(db *storage.PostgresClient, notifier notify.Notifier, config *core.ConfigStore) *IncidentTracker {
+	return &IncidentTracker{
+		db:       db,
+		notifier: notifier,
+		config:   config,
+		open:     make(map[incidentKey]*storage.Incident),
+		pending:  make(map[string]*dwell),
+	}
+}
+
+func (t *IncidentTracker) cfg() *core.Config {
+	return t.config.Get()
+}
+
+// ResolveAfter returns how long a problem must stay clear before its
+// incident closes
+func (t *IncidentTracker) ResolveAfter() time.Duration {
+	if cfg := t.cfg(); cfg != nil {
+		if d, err := time.ParseDuration(cfg.Incidents.ResolveAfter); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultIncidentResolveAfter
+}
+
+// Load restores open incidents from the database so a restart doesn't reopen
+// (and re-notify) incidents that were already being tracked
+func (t *IncidentTracker) Load(ctx context.Context) error {
+	incidents, err := t.db.GetIncidents(ctx, storage.IncidentOpen, "", maxOpenIncidents)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, inc := range incidents {
+		t.open[incidentKey{service: inc.ServiceName, problem: DetectionType(inc.ProblemType)}] = inc
+	}
+	return nil
+}
+
+// sweepInterval is how often Run looks for resolved incidents
+func (t *IncidentTracker) sweepInterval() time.Duration {
+	interval := t.ResolveAfter() / 4
+	if interval < minIncidentSweepInterval {
+		interval = minIncidentSweepInterval
+	}
+	return interval
+}
+
+// Run closes resolved incidents until the context is cancelled. The interval
+// is re-read after every sweep so a config reload takes effect.
+func (t *IncidentTracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.sweepInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.Sweep(ctx)
+			ticker.Reset(t.sweepInterval())
+		}
+	}
+}
+
+// Observe folds a diagnosis into the open incidents. A new incident opens
+// only once its problem has been the primary one for analyzer.transition_dwell
+// consecutive diagnoses. Healthy diagnoses reset that count and replays over
+// historical data are ignored; incidents close through Sweep.
+func (t *IncidentTracker) Observe(ctx context.Context, diag *UltimateDiagnosis) *storage.Incident {
+	if diag.PrimaryDetection == nil || storage.HasAsOf(ctx) {
+		return nil
+	}
+	snapshot, opened, escalated := t.fold(diag)
+	if snapshot == nil {
+		return nil
+	}
+
+	if err := t.db.SaveIncident(ctx, snapshot); err != nil {
+		logger.FromContext(ctx).Warn("Failed to persist incident", zap.String("incident", snapshot.ID), zap.Error(err))
+	}
+
+	switch {
+	case opened:
+		logger.FromContext(ctx).Info("🚩 Incident opened",
+			zap.String("incident", snapshot.ID),
+			zap.String("service", snapshot.ServiceName),
+			zap.String("problem", snapshot.ProblemType),
+			zap.String("severity", snapshot.CurrentSeverity),
+		)
+		t.notify(ctx, snapshot, diag, fmt.Sprintf("%s incident opened: %s", snapshot.ServiceName, snapshot.ProblemType))
+	case escalated:
+		logger.FromContext(ctx).Info("⬆️ Incident escalated",
+			zap.String("incident", snapshot.ID),
+			zap.String("service", snapshot.ServiceName),
+			zap.String("severity", snapshot.PeakSeverity),
+		)
+		t.notify(ctx, snapshot, diag, fmt.Sprintf("%s incident escalated to %s: %s", snapshot.ServiceName, snapshot.PeakSeverity, snapshot.ProblemType))
+	}
+
+	return snapshot
+}
+
+// fold applies a diagnosis to the open incidents and returns a snapshot of
+// the incident it updated, or nil when it updated none, and whether that
+// incident just opened or escalated
+func (t *IncidentTracker) fold(diag *UltimateDiagnosis) (snapshot *storage.Incident, opened, escalated bool) {
+	primary := diag.PrimaryDetection
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !primary.Detected || primary.Type == DetectionHealthy {
+		delete(t.pending, diag.ServiceName)
+		return nil, false, false
+	}
+
+	key := incidentKey{service: diag.ServiceName, problem: primary.Type}
+	inc, exists := t.open[key]
+	if exists && inc.LastPredictionID == diag.PredictionID {
+		// The same shared diagnosis reported by a second caller
+		return nil, false, false
+	}
+	if exists {
+		delete(t.pending, diag.ServiceName)
+	} else {
+		pending, ok := t.pending[diag.ServiceName]
+		if !ok {
+			pending = &dwell{}
+			t.pending[diag.ServiceName] = pending
+		}
+		if pending.observe(string(primary.Type), diag.PredictionID) < transitionDwell(t.cfg()) {
+			return nil, false, false
+		}
+		delete(t.pending, diag.ServiceName)
+	}
+	if !exists {
+		inc = &storage.Incident{
+			ID:           uuid.NewString(),
+			ServiceName:  diag.ServiceName,
+			ProblemType:  string(primary.Type),
+			Status:       storage.IncidentOpen,
+			FirstSeen:    diag.Timestamp,
+			PeakSeverity: primary.Severity,
+		}
+		t.open[key] = inc
+	} else if severityRank[primary.Severity] > severityRank[inc.PeakSeverity] {
+		inc.PeakSeverity = primary.Severity
+		escalated = true
+	}
+	inc.LastSeen = diag.Timestamp
+	inc.Occurrences++
+	inc.CurrentSeverity = primary.Severity
+	inc.LastPredictionID = diag.PredictionID
+	copied := *inc
+	return &copied, !exists, escalated
+}
+
+// Sweep closes incidents whose problem has not been diagnosed for
+// ResolveAfter and returns them
+func (t *IncidentTracker) Sweep(ctx context.Context) []*storage.Incident {
+	closed := t.closeResolved(time.Now())
+	for _, inc := range closed {
+		if err := t.db.SaveIncident(ctx, inc); err != nil {
+			logger.FromContext(ctx).Warn("Failed to persist closed incident", zap.String("incident", inc.ID), zap.Error(err))
+		}
+		logger.FromContext(ctx).Info("✅ Incident resolved",
+			zap.String("incident", inc.ID),
+			zap.String("service", inc.ServiceName),
+			zap.String("problem", inc.ProblemType),
+			zap.Int("occurrences", inc.Occurrences),
+			zap.Duration("duration", inc.LastSeen.Sub(inc.FirstSeen)),
+		)
+	}
+	return closed
+}
+
+// closeResolved closes and returns the incidents last seen ResolveAfter or
+// more before now
+func (t *IncidentTracker) closeResolved(now time.Time) []*storage.Incident {
+	resolveAfter := t.ResolveAfter()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var closed []*storage.Incident
+	for key, inc := range t.open {
+		if now.Sub(inc.LastSeen) < resolveAfter {
+			continue
+		}
+		delete(t.open, key)
+		closedAt := now
+		inc.Status = storage.IncidentClosed
+		inc.ClosedAt = &closedAt
+		closed = append(closed, inc)
+	}
+	return closed
+}
+
+func (t *IncidentTracker) notify(ctx context.Context, inc *storage.Incident, diag *UltimateDiagnosis, title string) {
+	if t.notifier == nil {
+		return
+	}
+
+	details := map[string]interface{}{
+		"incident_id":   inc.ID,
+		"first_seen":    inc.FirstSeen.Format(time.RFC3339),
+		"occurrences":   inc.Occurrences,
+		"peak_severity": inc.PeakSeverity,
+	}
+	addNoteDetails(details, diag)
+
+	err := t.notifier.Notify(ctx, notify.Notification{
+		Service:      inc.ServiceName,
+		Severity:     inc.CurrentSeverity,
+		Title:        title,
+		Message:      diag.Recommendation,
+		PredictionID: diag.PredictionID,
+		BurnRate:     burnRate(diag),
+		Actions:      notificationActions(t.cfg(), diag),
+		Details:      details,
+		Timestamp:    time.Now(),
+	})
+	if err != nil && !errors.Is(err, notify.ErrSuppressed) {
+		logger.FromContext(ctx).Warn("Incident notification failed", zap.String("incident", inc.ID), zap.Error(err))
+	}
+}
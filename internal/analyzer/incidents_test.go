@@ -0,0 +1,153 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+)
+
+func newTestIncidentTracker(db *storage.PostgresClient, dwell int) (*IncidentTracker, *recordingNotifier) {
+	cfg := &core.Config{}
+	cfg.Analyzer.TransitionDwell = dwell
+	cfg.Incidents.ResolveAfter = "10m"
+	cfg.ApplyDefaults()
+	notifier := &recordingNotifier{}
+	return NewIncidentTracker(db, notifier, core.NewConfigStore("", cfg)), notifier
+}
+
+// diagnosisAt returns a diagnosis of service with a detected problem
+func diagnosisAt(service string, at time.Time, problem DetectionType, severity string) *UltimateDiagnosis {
+	return &UltimateDiagnosis{
+		ServiceName:  service,
+		Timestamp:    at,
+		PredictionID: fmt.Sprintf("%s-%s-%d", service, problem, at.UnixNano()),
+		PrimaryDetection: &Detection{
+			Type:     problem,
+			Detected: true,
+			Severity: severity,
+		},
+	}
+}
+
+func healthyAt(service string, at time.Time) *UltimateDiagnosis {
+	return &UltimateDiagnosis{
+		ServiceName:      service,
+		Timestamp:        at,
+		PredictionID:     fmt.Sprintf("%s-healthy-%d", service, at.UnixNano()),
+		PrimaryDetection: &Detection{Type: DetectionHealthy},
+	}
+}
+
+func TestIncidentLifecycle(t *testing.T) {
+	tracker, _ := newTestIncidentTracker(nil, 1)
+	t0 := testEpoch
+
+	inc, opened, escalated := tracker.fold(diagnosisAt("checkout", t0, DetectionMemoryLeak, SeverityMedium))
+	if inc == nil || !opened || escalated {
+		t.Fatalf("first diagnosis: incident %v opened=%v escalated=%v, want a new incident", inc, opened, escalated)
+	}
+	id := inc.ID
+
+	// Repeating at the same severity folds into the incident silently
+	inc, opened, escalated = tracker.fold(diagnosisAt("checkout", t0.Add(time.Minute), DetectionMemoryLeak, SeverityMedium))
+	if inc == nil || inc.ID != id || opened || escalated {
+		t.Fatalf("repeat: incident %v opened=%v escalated=%v, want the same incident unchanged in severity", inc, opened, escalated)
+	}
+	if inc.Occurrences != 2 {
+		t.Errorf("occurrences = %d, want 2", inc.Occurrences)
+	}
+
+	inc, _, escalated = tracker.fold(diagnosisAt("checkout", t0.Add(2*time.Minute), DetectionMemoryLeak, SeverityCritical))
+	if !escalated || inc.PeakSeverity != SeverityCritical {
+		t.Fatalf("worse diagnosis: escalated=%v peak=%s, want an escalation to %s", escalated, inc.PeakSeverity, SeverityCritical)
+	}
+
+	// Easing off keeps the peak and doesn't escalate again
+	inc, _, escalated = tracker.fold(diagnosisAt("checkout", t0.Add(3*time.Minute), DetectionMemoryLeak, SeverityHigh))
+	if escalated || inc.PeakSeverity != SeverityCritical || inc.CurrentSeverity != SeverityHigh {
+		t.Errorf("milder diagnosis: escalated=%v peak=%s current=%s, want peak kept at %s", escalated, inc.PeakSeverity, inc.CurrentSeverity, SeverityCritical)
+	}
+	lastSeen := inc.LastSeen
+
+	if closed := tracker.closeResolved(lastSeen.Add(9 * time.Minute)); len(closed) != 0 {
+		t.Fatalf("closed %d incidents before resolve_after, want none", len(closed))
+	}
+	closed := tracker.closeResolved(lastSeen.Add(10 * time.Minute))
+	if len(closed) != 1 || closed[0].ID != id || closed[0].Status != storage.IncidentClosed || closed[0].ClosedAt == nil {
+		t.Fatalf("closed = %+v, want incident %s closed", closed, id)
+	}
+
+	// The problem coming back afterwards opens a new incident
+	inc, opened, _ = tracker.fold(diagnosisAt("checkout", lastSeen.Add(11*time.Minute), DetectionMemoryLeak, SeverityMedium))
+	if !opened || inc.ID == id {
+		t.Errorf("recurrence: opened=%v id=%s, want a new incident", opened, inc.ID)
+	}
+}
+
+func TestIncidentDwell(t *testing.T) {
+	tracker, _ := newTestIncidentTracker(nil, 2)
+	t0 := testEpoch
+
+	first := diagnosisAt("checkout", t0, DetectionMemoryLeak, SeverityHigh)
+	if inc, _, _ := tracker.fold(first); inc != nil {
+		t.Fatal("an incident opened on the first diagnosis with a dwell of 2")
+	}
+	// The same shared diagnosis reported again doesn't count twice
+	if inc, _, _ := tracker.fold(first); inc != nil {
+		t.Fatal("a repeated prediction id counted towards the dwell")
+	}
+	// A healthy diagnosis in between resets the count
+	tracker.fold(healthyAt("checkout", t0.Add(time.Minute)))
+	if inc, _, _ := tracker.fold(diagnosisAt("checkout", t0.Add(2*time.Minute), DetectionMemoryLeak, SeverityHigh)); inc != nil {
+		t.Fatal("an incident opened although a healthy diagnosis reset the dwell")
+	}
+	if inc, opened, _ := tracker.fold(diagnosisAt("checkout", t0.Add(3*time.Minute), DetectionMemoryLeak, SeverityHigh)); inc == nil || !opened {
+		t.Fatal("no incident after two consecutive diagnoses of the problem")
+	}
+}
+
+// TestIncidentTrackerPersistsAndNotifies runs open -> repeat -> escalate ->
+// close against the database: notifications go out on open and escalation
+// only, and the stored incident follows every step
+func TestIncidentTrackerPersistsAndNotifies(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	tracker, notifier := newTestIncidentTracker(db, 1)
+	ctx := context.Background()
+
+	t0 := time.Now().Add(-30 * time.Minute).Truncate(time.Second)
+	opened := tracker.Observe(ctx, diagnosisAt(service, t0, DetectionMemoryLeak, SeverityMedium))
+	if opened == nil {
+		t.Fatal("Observe didn't open an incident")
+	}
+	tracker.Observe(ctx, diagnosisAt(service, t0.Add(time.Minute), DetectionMemoryLeak, SeverityMedium))
+	tracker.Observe(ctx, diagnosisAt(service, t0.Add(2*time.Minute), DetectionMemoryLeak, SeverityCritical))
+
+	if sent := notifier.notifications(); len(sent) != 2 {
+		t.Errorf("sent %d notifications, want 2 (open and escalation)", len(sent))
+	}
+
+	stored, err := db.GetIncident(ctx, opened.ID)
+	if err != nil {
+		t.Fatalf("GetIncident: %v", err)
+	}
+	if stored.Status != storage.IncidentOpen || stored.Occurrences != 3 || stored.PeakSeverity != SeverityCritical {
+		t.Errorf("stored incident = %s, %d occurrences, peak %s; want open, 3, %s",
+			stored.Status, stored.Occurrences, stored.PeakSeverity, SeverityCritical)
+	}
+
+	if closed := tracker.Sweep(ctx); len(closed) != 1 {
+		t.Fatalf("Sweep closed %d incidents, want 1", len(closed))
+	}
+	if stored, err = db.GetIncident(ctx, opened.ID); err != nil {
+		t.Fatalf("GetIncident: %v", err)
+	}
+	if stored.Status != storage.IncidentClosed || stored.ClosedAt == nil {
+		t.Errorf("stored incident after Sweep = %s closed_at %v, want closed", stored.Status, stored.ClosedAt)
+	}
+}
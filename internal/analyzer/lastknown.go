@@ -0,0 +1,99 @@
+package analyzer
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// maxLastKnownDiagnoses bounds the degraded-mode cache; the least recently
+// diagnosed services are evicted first
+const maxLastKnownDiagnoses = 256
+
+// LastKnownDiagnosis is the most recent successful live diagnosis of a
+// service, served while the database is unavailable
+type LastKnownDiagnosis struct {
+	Diagnosis *UltimateDiagnosis
+	CachedAt  time.Time
+}
+
+// lastKnownCache is an LRU of the latest diagnosis per service
+type lastKnownCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // front is most recently stored
+	entries  map[string]*list.Element
+}
+
+func newLastKnownCache(capacity int) *lastKnownCache {
+	return &lastKnownCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+func (c *lastKnownCache) put(diag *UltimateDiagnosis) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &LastKnownDiagnosis{Diagnosis: diag, CachedAt: time.Now()}
+	if el, ok := c.entries[diag.ServiceName]; ok {
+		el.Value = entry
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[diag.ServiceName] = c.order.PushFront(entry)
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*LastKnownDiagnosis).Diagnosis.ServiceName)
+	}
+}
+
+func (c *lastKnownCache) get(serviceName string) (*LastKnownDiagnosis, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[serviceName]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*LastKnownDiagnosis), true
+}
+
+func (c *lastKnownCache) all() []*LastKnownDiagnosis {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries := make([]*LastKnownDiagnosis, 0, c.order.Len())
+	for el := c.order.Front(); el != nil; el = el.Next() {
+		entries = append(entries, el.Value.(*LastKnownDiagnosis))
+	}
+	return entries
+}
+
+// LastKnownDiagnosis returns the latest successful live diagnosis of a
+// service, for serving when a fresh diagnosis can't reach the database
+func (ua *UltimateAnalyzer) LastKnownDiagnosis(serviceName string) (*LastKnownDiagnosis, bool) {
+	return ua.lastKnown.get(serviceName)
+}
+
+// LastKnownComparisons builds the fleet overview from last-known diagnoses,
+// sorted worst-first. LastSeen is the time of each diagnosis.
+func (ua *UltimateAnalyzer) LastKnownComparisons() []ServiceComparison {
+	entries := ua.lastKnown.all()
+	cfg := ua.cfg()
+	comparisons := make([]ServiceComparison, 0, len(entries))
+	for _, entry := range entries {
+		comparison := comparisonOf(entry.Diagnosis)
+		lastSeen := entry.Diagnosis.Timestamp
+		comparison.LastSeen = &lastSeen
+		comparison.RemediationMode = remediationMode(cfg, entry.Diagnosis.ServiceName)
+		comparisons = append(comparisons, *comparison)
+	}
+	sortWorstFirst(comparisons)
+	return comparisons
+}
@@ -0,0 +1,107 @@
+package analyzer
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+// lastKnownDiag returns a minimal diagnosis of the service at the health score
+func lastKnownDiag(service string, health float64) *UltimateDiagnosis {
+	return &UltimateDiagnosis{
+		ServiceName:      service,
+		Timestamp:        testEpoch,
+		HealthScore:      health,
+		PrimaryDetection: &Detection{Type: DetectionHealthy, Severity: "LOW"},
+	}
+}
+
+func TestLastKnownCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newLastKnownCache(2)
+	c.put(lastKnownDiag("checkout", 90))
+	c.put(lastKnownDiag("payments", 90))
+
+	// Reading checkout makes payments the least recently used
+	if _, ok := c.get("checkout"); !ok {
+		t.Fatal("checkout not cached")
+	}
+	c.put(lastKnownDiag("search", 90))
+
+	if _, ok := c.get("payments"); ok {
+		t.Error("payments still cached, want it evicted as least recently used")
+	}
+	for _, service := range []string{"checkout", "search"} {
+		if _, ok := c.get(service); !ok {
+			t.Errorf("%s evicted, want it kept", service)
+		}
+	}
+}
+
+func TestLastKnownCacheReplacesPerService(t *testing.T) {
+	c := newLastKnownCache(4)
+	c.put(lastKnownDiag("checkout", 90))
+	first, _ := c.get("checkout")
+
+	time.Sleep(time.Millisecond)
+	c.put(lastKnownDiag("checkout", 40))
+
+	got, ok := c.get("checkout")
+	if !ok || got.Diagnosis.HealthScore != 40 {
+		t.Fatalf("checkout = %+v, want the newer diagnosis", got)
+	}
+	if !got.CachedAt.After(first.CachedAt) {
+		t.Errorf("cached_at %v not after the first %v", got.CachedAt, first.CachedAt)
+	}
+	if n := len(c.all()); n != 1 {
+		t.Errorf("%d entries, want one per service", n)
+	}
+}
+
+func TestLastKnownComparisonsWorstFirst(t *testing.T) {
+	cfg := &core.Config{}
+	cfg.ApplyDefaults()
+	ua := NewUltimateAnalyzer(nil, core.NewConfigStore("", cfg))
+
+	ua.lastKnown.put(lastKnownDiag("checkout", 85))
+	ua.lastKnown.put(lastKnownDiag("payments", 30))
+	ua.lastKnown.put(lastKnownDiag("search", 60))
+
+	comparisons := ua.LastKnownComparisons()
+	var order []string
+	for _, c := range comparisons {
+		order = append(order, c.ServiceName)
+	}
+	if !slices.Equal(order, []string{"payments", "search", "checkout"}) {
+		t.Fatalf("order = %v, want [payments search checkout]", order)
+	}
+	for _, c := range comparisons {
+		if c.LastSeen == nil || !c.LastSeen.Equal(testEpoch) {
+			t.Errorf("%s: last_seen = %v, want the diagnosis time", c.ServiceName, c.LastSeen)
+		}
+	}
+	if !comparisons[0].RequiresAttention || comparisons[2].RequiresAttention {
+		t.Error("requires_attention should follow the health score")
+	}
+}
+
+func TestLastKnownComparisonsRemediationMode(t *testing.T) {
+	cfg := &core.Config{}
+	cfg.ApplyDefaults()
+	cfg.Actuator.AutoRemediate = []string{"checkout"}
+	ua := NewUltimateAnalyzer(nil, core.NewConfigStore("", cfg))
+
+	ua.lastKnown.put(lastKnownDiag("checkout", 85))
+	ua.lastKnown.put(lastKnownDiag("payments", 30))
+
+	for _, c := range ua.LastKnownComparisons() {
+		want := RemediationRecommend
+		if c.ServiceName == "checkout" {
+			want = RemediationAuto
+		}
+		if c.RemediationMode != want {
+			t.Errorf("%s: remediation_mode = %q, want %q", c.ServiceName, c.RemediationMode, want)
+		}
+	}
+}
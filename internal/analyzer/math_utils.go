@@ -2,7 +2,9 @@ package analyzer
 
 import (
 	"math"
+	"sort"
 
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
 	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
 )
 
@@ -236,33 +238,150 @@ func CalculateStdDev(values []float64) float64 {
 	return math.Sqrt(variance / float64(len(values)))
 }
 
-// CalculatePearsonCorrelation calculates Pearson correlation between two metric sets
-func CalculatePearsonCorrelation(m1, m2 []*storage.Metric) float64 {
+// Correlation strength labels
+const (
+	StrengthInsufficientData = "insufficient_data"
+	StrengthNone             = "none"
+	StrengthWeak             = "weak"
+	StrengthModerate         = "moderate"
+	StrengthStrong           = "strong"
+)
+
+// DefaultMinCorrelationSamples is the smallest paired sample count a Pearson
+// coefficient is computed from when no minimum is configured
+const DefaultMinCorrelationSamples = 3
+
+// CorrelationResult is a Pearson coefficient plus how much data backs it.
+// Lag and LagCoefficient are only set by CorrelateLagged.
+type CorrelationResult struct {
+	Coefficient    float64 `json:"coefficient"` // signed, -1 to 1
+	Samples        int     `json:"samples"`
+	Strength       string  `json:"strength"`
+	Lag            int     `json:"lag"`                       // intervals the first series leads the second
+	LagCoefficient float64 `json:"lag_coefficient,omitempty"` // coefficient at Lag
+}
+
+// Sufficient reports whether the coefficient was computed from enough samples
+func (r CorrelationResult) Sufficient() bool {
+	return r.Strength != StrengthInsufficientData
+}
+
+// CorrelatePearson is the single Pearson implementation used by the analyzer.
+// Series are paired by index. With fewer than minSamples pairs the result is
+// marked insufficient_data instead of reporting a misleading zero.
+func CorrelatePearson(m1, m2 []*storage.Metric, minSamples int) CorrelationResult {
+	if minSamples < DefaultMinCorrelationSamples {
+		minSamples = DefaultMinCorrelationSamples
+	}
+
 	n := int(math.Min(float64(len(m1)), float64(len(m2))))
-	if n < 3 {
-		return 0
+	result := CorrelationResult{Samples: n}
+	if n < minSamples {
+		result.Strength = StrengthInsufficientData
+		return result
 	}
 
+	result.Coefficient = pearson(extractMetricValues(m1[:n]), extractMetricValues(m2[:n]))
+	result.Strength = correlationStrength(result.Coefficient)
+	return result
+}
+
+// CorrelateLagged is CorrelatePearson plus the best lag of up to maxLag
+// intervals between the series, see CrossCorrelation
+func CorrelateLagged(m1, m2 []*storage.Metric, minSamples, maxLag int) CorrelationResult {
+	result := CorrelatePearson(m1, m2, minSamples)
+	if !result.Sufficient() {
+		return result
+	}
+	result.Lag, result.LagCoefficient = CrossCorrelation(extractMetricValues(m1), extractMetricValues(m2), maxLag)
+	return result
+}
+
+// CrossCorrelation finds the shift between two equally spaced series that
+// correlates them most strongly. A positive lag means x leads y: x[i] moves
+// with y[i+lag]. Shifts leaving fewer than DefaultMinCorrelationSamples
+// overlapping points are skipped; ties go to the smaller shift.
+func CrossCorrelation(x, y []float64, maxLag int) (bestLag int, r float64) {
+	n := len(x)
+	if len(y) < n {
+		n = len(y)
+	}
+	if maxLag < 0 {
+		maxLag = 0
+	}
+
+	best := -1.0
+	for _, lag := range lagOrder(maxLag) {
+		var xs, ys []float64
+		if lag >= 0 {
+			if n-lag < DefaultMinCorrelationSamples {
+				continue
+			}
+			xs, ys = x[:n-lag], y[lag:n]
+		} else {
+			if n+lag < DefaultMinCorrelationSamples {
+				continue
+			}
+			xs, ys = x[-lag:n], y[:n+lag]
+		}
+
+		coefficient := pearson(xs, ys)
+		if math.Abs(coefficient) > best {
+			best = math.Abs(coefficient)
+			bestLag, r = lag, coefficient
+		}
+	}
+	return bestLag, r
+}
+
+// lagOrder lists 0, 1, -1, 2, -2, ... so smaller shifts win ties
+func lagOrder(maxLag int) []int {
+	lags := []int{0}
+	for l := 1; l <= maxLag; l++ {
+		lags = append(lags, l, -l)
+	}
+	return lags
+}
+
+// pearson computes the Pearson coefficient of two equal-length series, or 0
+// when either is constant
+func pearson(x, y []float64) float64 {
 	var sumX, sumY, sumXY, sumX2, sumY2 float64
-	for i := 0; i < n; i++ {
-		x := m1[i].MetricValue
-		y := m2[i].MetricValue
-		sumX += x
-		sumY += y
-		sumXY += x * y
-		sumX2 += x * x
-		sumY2 += y * y
+	for i := range x {
+		sumX += x[i]
+		sumY += y[i]
+		sumXY += x[i] * y[i]
+		sumX2 += x[i] * x[i]
+		sumY2 += y[i] * y[i]
 	}
 
-	nf := float64(n)
+	nf := float64(len(x))
 	numerator := nf*sumXY - sumX*sumY
 	denominator := math.Sqrt((nf*sumX2 - sumX*sumX) * (nf*sumY2 - sumY*sumY))
-
-	if denominator == 0 {
+	if denominator == 0 || math.IsNaN(denominator) {
 		return 0
 	}
+	return numerator / denominator
+}
 
-	return math.Abs(numerator / denominator)
+func correlationStrength(coefficient float64) string {
+	abs := math.Abs(coefficient)
+	switch {
+	case abs >= 0.7:
+		return StrengthStrong
+	case abs >= 0.4:
+		return StrengthModerate
+	case abs >= 0.2:
+		return StrengthWeak
+	default:
+		return StrengthNone
+	}
+}
+
+// CalculatePearsonCorrelation returns the magnitude of the Pearson correlation
+// between two metric sets, or 0 when there are too few samples
+func CalculatePearsonCorrelation(m1, m2 []*storage.Metric) float64 {
+	return math.Abs(CorrelatePearson(m1, m2, DefaultMinCorrelationSamples).Coefficient)
 }
 
 // RecordsToValues converts metric records to float slice
@@ -297,22 +416,28 @@ func HasAnomalies(values []float64, threshold float64) bool {
 	return false
 }
 
-// CalculatePercentile calculates the nth percentile
-func CalculatePercentile(values []float64, percentile float64) float64 {
+// CalculatePercentile calculates the nth percentile using method, one of
+// core.PercentileInterpolated (also used for "") or core.PercentileNearestRank.
+// Nearest-rank always returns one of the values; interpolation blends the two
+// values either side of the fractional rank.
+func CalculatePercentile(values []float64, percentile float64, method string) float64 {
 	if len(values) == 0 {
 		return 0
 	}
 
 	sorted := make([]float64, len(values))
 	copy(sorted, values)
+	sort.Float64s(sorted)
 
-	// Simple bubble sort for small datasets
-	for i := 0; i < len(sorted); i++ {
-		for j := i + 1; j < len(sorted); j++ {
-			if sorted[i] > sorted[j] {
-				sorted[i], sorted[j] = sorted[j], sorted[i]
-			}
+	if method == core.PercentileNearestRank {
+		rank := int(math.Ceil(percentile / 100.0 * float64(len(sorted))))
+		if rank < 1 {
+			rank = 1
+		}
+		if rank > len(sorted) {
+			rank = len(sorted)
 		}
+		return sorted[rank-1]
 	}
 
 	index := (percentile / 100.0) * float64(len(sorted)-1)
@@ -338,3 +463,42 @@ func CalculateMean(values []float64) float64 {
 	}
 	return sum / float64(len(values))
 }
+
+// MovingAverage smooths values with a trailing simple moving average. The
+// first window-1 points average over the samples available so far.
+func MovingAverage(values []float64, window int) []float64 {
+	if window <= 1 || len(values) == 0 {
+		return values
+	}
+
+	smoothed := make([]float64, len(values))
+	sum := 0.0
+	for i, v := range values {
+		sum += v
+		if i >= window {
+			sum -= values[i-window]
+		}
+		n := i + 1
+		if n > window {
+			n = window
+		}
+		smoothed[i] = sum / float64(n)
+	}
+	return smoothed
+}
+
+// ExponentialMovingAverage smooths values with an EMA whose span matches a
+// window-sample SMA (alpha = 2/(window+1))
+func ExponentialMovingAverage(values []float64, window int) []float64 {
+	if window <= 1 || len(values) == 0 {
+		return values
+	}
+
+	alpha := 2 / (float64(window) + 1)
+	smoothed := make([]float64, len(values))
+	smoothed[0] = values[0]
+	for i := 1; i < len(values); i++ {
+		smoothed[i] = alpha*values[i] + (1-alpha)*smoothed[i-1]
+	}
+	return smoothed
+}
@@ -0,0 +1,220 @@
+package analyzer
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+func TestCorrelatePearsonInsufficientData(t *testing.T) {
+	tests := []struct {
+		name       string
+		n1, n2     int
+		minSamples int
+		want       bool
+	}{
+		{name: "below configured minimum", n1: 9, n2: 9, minSamples: 10, want: false},
+		{name: "shorter series limits pairs", n1: 20, n2: 4, minSamples: 5, want: false},
+		{name: "minimum floors at the default", n1: 2, n2: 2, minSamples: 1, want: false},
+		{name: "empty series", n1: 0, n2: 12, minSamples: 3, want: false},
+		{name: "exactly the minimum", n1: 10, n2: 10, minSamples: 10, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x := seriesOf(time.Minute, generate(tt.n1, func(i int) float64 { return float64(i) })...)
+			y := seriesOf(time.Minute, generate(tt.n2, func(i int) float64 { return float64(2 * i) })...)
+
+			result := CorrelatePearson(x, y, tt.minSamples)
+			if result.Sufficient() != tt.want {
+				t.Fatalf("Sufficient() = %v, want %v (%+v)", result.Sufficient(), tt.want, result)
+			}
+			if !tt.want {
+				if result.Strength != StrengthInsufficientData || result.Coefficient != 0 {
+					t.Errorf("result = %+v, want insufficient_data with no coefficient", result)
+				}
+				if want := min(tt.n1, tt.n2); result.Samples != want {
+					t.Errorf("Samples = %d, want %d", result.Samples, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCorrelatePearsonStrength(t *testing.T) {
+	rising := generate(12, func(i int) float64 { return float64(i) })
+
+	tests := []struct {
+		name     string
+		other    []float64
+		wantSign float64
+		want     string
+	}{
+		{name: "perfect positive", other: generate(12, func(i int) float64 { return 3*float64(i) + 1 }), wantSign: 1, want: StrengthStrong},
+		{name: "perfect negative", other: generate(12, func(i int) float64 { return -float64(i) }), wantSign: -1, want: StrengthStrong},
+		{name: "constant", other: generate(12, func(i int) float64 { return 5 }), want: StrengthNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CorrelatePearson(seriesOf(time.Minute, rising...), seriesOf(time.Minute, tt.other...), 3)
+			if result.Strength != tt.want {
+				t.Errorf("Strength = %q, want %q (coefficient %.3f)", result.Strength, tt.want, result.Coefficient)
+			}
+			if tt.wantSign != 0 && math.Abs(result.Coefficient-tt.wantSign) > 1e-9 {
+				t.Errorf("Coefficient = %.6f, want %v", result.Coefficient, tt.wantSign)
+			}
+		})
+	}
+}
+
+func TestCorrelateLaggedSkipsInsufficientData(t *testing.T) {
+	x := seriesOf(time.Minute, 1, 2, 3, 4)
+	result := CorrelateLagged(x, x, 10, 3)
+
+	if result.Sufficient() || result.Lag != 0 || result.LagCoefficient != 0 {
+		t.Errorf("result = %+v, want insufficient_data without a lag", result)
+	}
+}
+
+func TestTrimToWindow(t *testing.T) {
+	metrics := seriesOf(time.Minute, generate(30, func(i int) float64 { return float64(i) })...)
+
+	if got := trimToWindow(metrics, 0); len(got) != 30 {
+		t.Errorf("trimToWindow(0) kept %d samples, want all 30", len(got))
+	}
+	got := trimToWindow(metrics, 10*time.Minute)
+	if len(got) != 11 || got[0].MetricValue != 19 {
+		t.Errorf("trimToWindow(10m) kept %d samples from %v, want 11 from 19", len(got), got[0].MetricValue)
+	}
+}
+
+// shifted returns x delayed by lag intervals: out[i] = x[i-lag], with the
+// first lag values held at x[0]
+func shifted(x []float64, lag int) []float64 {
+	out := make([]float64, len(x))
+	for i := range out {
+		out[i] = x[max(0, i-lag)]
+	}
+	return out
+}
+
+func TestCrossCorrelationFindsShift(t *testing.T) {
+	// An irregular signal so no shift but the true one lines up
+	latency := generate(40, func(i int) float64 {
+		return 100 + 30*math.Sin(float64(i)*0.7) + 20*math.Sin(float64(i*i)*0.13)
+	})
+
+	tests := []struct {
+		name    string
+		x, y    []float64
+		wantLag int
+	}{
+		{"aligned", latency, latency, 0},
+		{"x leads y by 3", latency, shifted(latency, 3), 3},
+		{"y leads x by 2", shifted(latency, 2), latency, -2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lag, r := CrossCorrelation(tt.x, tt.y, 5)
+			if lag != tt.wantLag {
+				t.Errorf("lag = %d, want %d", lag, tt.wantLag)
+			}
+			if r < 0.99 {
+				t.Errorf("r = %.3f at the best lag, want ~1", r)
+			}
+		})
+	}
+
+	// A shift beyond maxLag isn't found
+	if lag, _ := CrossCorrelation(latency, shifted(latency, 4), 2); lag == 4 {
+		t.Error("found a lag of 4 with maxLag 2")
+	}
+}
+
+func TestCrossCorrelationAntiCorrelated(t *testing.T) {
+	x := generate(30, func(i int) float64 { return math.Sin(float64(i) * 0.9) })
+	y := generate(30, func(i int) float64 { return -x[i] })
+
+	lag, r := CrossCorrelation(x, y, 3)
+	if lag != 0 || r > -0.99 {
+		t.Errorf("lag %d, r %.3f; want the inverse found at lag 0 with r ~ -1", lag, r)
+	}
+}
+
+func TestCrossCorrelationTooShort(t *testing.T) {
+	// Every shift leaves fewer than DefaultMinCorrelationSamples points
+	x := []float64{1, 2}
+	if lag, r := CrossCorrelation(x, x, 2); lag != 0 || r != 0 {
+		t.Errorf("lag %d, r %.3f; want 0, 0", lag, r)
+	}
+	if lag, r := CrossCorrelation(nil, nil, 3); lag != 0 || r != 0 {
+		t.Errorf("empty series: lag %d, r %.3f; want 0, 0", lag, r)
+	}
+}
+
+func TestCorrelateLaggedShiftedMetrics(t *testing.T) {
+	latency := generate(30, func(i int) float64 { return 200 + 80*math.Sin(float64(i)*0.5) + float64(i%7)*9 })
+	errors := shifted(latency, 2)
+
+	result := CorrelateLagged(seriesOf(30*time.Second, latency...), seriesOf(30*time.Second, errors...), 5, 5)
+	if result.Lag != 2 || result.LagCoefficient < 0.99 {
+		t.Errorf("lag %d, r %.3f; want latency leading errors by 2 intervals", result.Lag, result.LagCoefficient)
+	}
+	if result.LagCoefficient <= result.Coefficient {
+		t.Errorf("lagged r %.3f not above unshifted r %.3f", result.LagCoefficient, result.Coefficient)
+	}
+}
+
+func TestCalculatePercentileMethods(t *testing.T) {
+	values := []float64{50, 15, 40, 20, 35} // sorted: 15 20 35 40 50
+
+	tests := []struct {
+		percentile   float64
+		interpolated float64
+		nearestRank  float64
+	}{
+		{0, 15, 15},
+		{30, 23, 20},
+		{40, 29, 20},
+		{50, 35, 35},
+		{95, 48, 50},
+		{100, 50, 50},
+	}
+	for _, tt := range tests {
+		if got := CalculatePercentile(values, tt.percentile, core.PercentileInterpolated); math.Abs(got-tt.interpolated) > 1e-9 {
+			t.Errorf("interpolated p%v = %v, want %v", tt.percentile, got, tt.interpolated)
+		}
+		if got := CalculatePercentile(values, tt.percentile, ""); math.Abs(got-tt.interpolated) > 1e-9 {
+			t.Errorf("default p%v = %v, want the interpolated %v", tt.percentile, got, tt.interpolated)
+		}
+		if got := CalculatePercentile(values, tt.percentile, core.PercentileNearestRank); got != tt.nearestRank {
+			t.Errorf("nearest-rank p%v = %v, want %v", tt.percentile, got, tt.nearestRank)
+		}
+	}
+
+	if values[0] != 50 || values[4] != 35 {
+		t.Errorf("CalculatePercentile reordered its input: %v", values)
+	}
+	if got := CalculatePercentile(nil, 99, core.PercentileNearestRank); got != 0 {
+		t.Errorf("p99 of no values = %v, want 0", got)
+	}
+}
+
+func BenchmarkCalculatePercentile(b *testing.B) {
+	values := make([]float64, 100_000)
+	for i := range values {
+		values[i] = float64((i * 7919) % len(values)) // a fixed shuffle of 0..n-1
+	}
+
+	for _, method := range []string{core.PercentileInterpolated, core.PercentileNearestRank} {
+		b.Run(method, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				CalculatePercentile(values, 99, method)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package analyzer
+
+import (
+	"context"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// serviceNotes loads the operator notes in effect at the diagnosis time.
+// A failed lookup leaves the diagnosis without notes.
+func (ua *UltimateAnalyzer) serviceNotes(ctx context.Context, serviceName string) []*storage.ServiceNote {
+	notes, err := ua.db.GetServiceNotes(ctx, serviceName, storage.AsOf(ctx), false)
+	if err != nil {
+		logger.FromContext(ctx).Warn("Failed to load service notes", zap.String("service", serviceName), zap.Error(err))
+		return nil
+	}
+	return notes
+}
+
+// addNoteDetails copies the diagnosis' operator notes into notification
+// details, so their context reaches whoever is alerted
+func addNoteDetails(details map[string]interface{}, diag *UltimateDiagnosis) {
+	if len(diag.Notes) == 0 {
+		return
+	}
+	notes := make([]string, 0, len(diag.Notes))
+	for _, n := range diag.Notes {
+		notes = append(notes, n.Note)
+	}
+	details["notes"] = notes
+}
@@ -0,0 +1,28 @@
+package analyzer
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+)
+
+func TestAddNoteDetails(t *testing.T) {
+	details := map[string]interface{}{"incident_id": "inc-1"}
+	addNoteDetails(details, &UltimateDiagnosis{})
+	if _, ok := details["notes"]; ok {
+		t.Error("notes added for a diagnosis without any")
+	}
+
+	addNoteDetails(details, &UltimateDiagnosis{Notes: []*storage.ServiceNote{
+		{Note: "known noisy before 9am"},
+		{Note: "migration in progress"},
+	}})
+	notes, ok := details["notes"].([]string)
+	if !ok || !slices.Equal(notes, []string{"known noisy before 9am", "migration in progress"}) {
+		t.Errorf("details[notes] = %v, want the notes' text", details["notes"])
+	}
+	if details["incident_id"] != "inc-1" {
+		t.Error("existing details were lost")
+	}
+}
@@ -0,0 +1,101 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+)
+
+// OOM projection bases
+const (
+	OOMBasisMemoryLimit = "memory_limit" // absolute usage against resources.<service>.memory_limit_mb
+	OOMBasisPercent     = "percent"      // memory_usage percentage against 100%
+)
+
+// minOOMProjectionSamples is the fewest absolute samples a limit-based
+// projection is fitted from
+const minOOMProjectionSamples = 3
+
+// OOMProjection estimates when a growing service runs out of memory
+type OOMProjection struct {
+	Basis           string    `json:"basis"`
+	LimitMB         float64   `json:"limit_mb,omitempty"`
+	CurrentMB       float64   `json:"current_mb,omitempty"`
+	GrowthPerMinute float64   `json:"growth_per_minute"` // MiB/min on a limit basis, %/min otherwise
+	MinutesToOOM    float64   `json:"minutes_to_oom"`
+	EstimatedOOM    time.Time `json:"estimated_oom"`
+}
+
+// Describe renders the projection for recommendations and actuator reasons
+func (p *OOMProjection) Describe() string {
+	if p.Basis == OOMBasisMemoryLimit {
+		return fmt.Sprintf("%.0fMi of %.0fMi limit, growing %.1fMi/min - OOM in ~%.0f min",
+			p.CurrentMB, p.LimitMB, p.GrowthPerMinute, p.MinutesToOOM)
+	}
+	return fmt.Sprintf("growing %.2f%%/min - memory exhausted in ~%.0f min", p.GrowthPerMinute, p.MinutesToOOM)
+}
+
+// projectOOM projects time to OOM for a growing service. Containers are
+// killed at their memory limit, so a configured limit is used when an
+// absolute memory series is available; otherwise the percentage series is
+// run to 100%. Returns nil when memory is not growing.
+func (ed *EnhancedDetector) projectOOM(ctx context.Context, serviceName string, features *ServiceFeatures, window time.Duration) *OOMProjection {
+	limit := ed.cfg().MemoryLimitFor(serviceName)
+	var series []*storage.Metric
+	if limit > 0 {
+		series, _ = ed.featureExtractor.Resolver().ResolveSeries(ctx, serviceName, MetricMemoryMB, window)
+	}
+	return oomProjection(ed.cfg(), features, series, limit, time.Now())
+}
+
+// oomProjection projects from the absolute memory series against limit
+// when both are usable, and from the percentage features otherwise
+func oomProjection(cfg *core.Config, features *ServiceFeatures, series []*storage.Metric, limit float64, now time.Time) *OOMProjection {
+	if limit > 0 {
+		if len(series) >= minOOMProjectionSamples {
+			slope := trendSlope(cfg, series)
+			if slope <= 0 {
+				return nil
+			}
+			current := series[len(series)-1].MetricValue
+			minutes := math.Max(0, (limit-current)/slope)
+			return &OOMProjection{
+				Basis:           OOMBasisMemoryLimit,
+				LimitMB:         limit,
+				CurrentMB:       current,
+				GrowthPerMinute: slope,
+				MinutesToOOM:    minutes,
+				EstimatedOOM:    now.Add(time.Duration(minutes * float64(time.Minute))),
+			}
+		}
+	}
+
+	if features.MemoryTrend <= 0 {
+		return nil
+	}
+	minutes := math.Max(0, (100-features.MemoryMean)/features.MemoryTrend)
+	return &OOMProjection{
+		Basis:           OOMBasisPercent,
+		GrowthPerMinute: features.MemoryTrend,
+		MinutesToOOM:    minutes,
+		EstimatedOOM:    now.Add(time.Duration(minutes * float64(time.Minute))),
+	}
+}
+
+// oomProjectionOf returns the OOM projection recorded by the memory leak
+// detector in a diagnosis, if any
+func oomProjectionOf(diag *UltimateDiagnosis) *OOMProjection {
+	for _, d := range diag.AllDetections {
+		if d == nil || d.Type != DetectionMemoryLeak || d.Evidence == nil {
+			continue
+		}
+		if p, ok := d.Evidence["oom_projection"].(*OOMProjection); ok {
+			return p
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package analyzer
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+)
+
+func TestOOMProjectionWithLimit(t *testing.T) {
+	cfg := &core.Config{Resources: map[string]core.ServiceResources{"checkout": {MemoryLimitMB: 512}}}
+	cfg.ApplyDefaults()
+
+	// 10 MiB/min up to 450 MiB: 62 MiB of headroom is ~6.2 minutes
+	series := seriesOf(time.Minute, 400, 410, 420, 430, 440, 450)
+	features := &ServiceFeatures{MemoryMean: 60, MemoryTrend: 0.5}
+
+	p := oomProjection(cfg, features, series, cfg.MemoryLimitFor("checkout"), testEpoch)
+	if p == nil {
+		t.Fatal("expected a projection for growing memory")
+	}
+	if p.Basis != OOMBasisMemoryLimit {
+		t.Errorf("basis = %q, want %q", p.Basis, OOMBasisMemoryLimit)
+	}
+	if p.LimitMB != 512 || p.CurrentMB != 450 {
+		t.Errorf("limit/current = %.0f/%.0f, want 512/450", p.LimitMB, p.CurrentMB)
+	}
+	if math.Abs(p.GrowthPerMinute-10) > 1e-6 {
+		t.Errorf("growth = %.3f MiB/min, want 10", p.GrowthPerMinute)
+	}
+	if math.Abs(p.MinutesToOOM-6.2) > 1e-6 {
+		t.Errorf("minutes to OOM = %.3f, want 6.2", p.MinutesToOOM)
+	}
+	if want := testEpoch.Add(372 * time.Second); !p.EstimatedOOM.Equal(want) {
+		t.Errorf("estimated OOM = %v, want %v", p.EstimatedOOM, want)
+	}
+
+	// Flat absolute usage isn't a leak, whatever the percentage trend says
+	flat := seriesOf(time.Minute, 450, 450, 450, 450)
+	if p := oomProjection(cfg, features, flat, 512, testEpoch); p != nil {
+		t.Errorf("flat usage projected %+v, want nil", p)
+	}
+
+	// Past the limit already, the projection is immediate
+	over := seriesOf(time.Minute, 500, 510, 520)
+	if p := oomProjection(cfg, features, over, 512, testEpoch); p == nil || p.MinutesToOOM != 0 {
+		t.Errorf("over-limit projection = %+v, want 0 minutes", p)
+	}
+}
+
+func TestOOMProjectionWithoutLimit(t *testing.T) {
+	cfg := &core.Config{}
+	cfg.ApplyDefaults()
+
+	// 70% growing 2%/min reaches 100% in 15 minutes
+	features := &ServiceFeatures{MemoryMean: 70, MemoryTrend: 2}
+	series := seriesOf(time.Minute, 400, 410, 420, 430)
+
+	tests := []struct {
+		name   string
+		series []*storage.Metric
+		limit  float64
+	}{
+		{"no limit configured", series, 0},
+		{"limit without memory_usage_mb", nil, 512},
+		{"too few absolute samples", series[:minOOMProjectionSamples-1], 512},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := oomProjection(cfg, features, tt.series, tt.limit, testEpoch)
+			if p == nil {
+				t.Fatal("expected a percentage projection")
+			}
+			if p.Basis != OOMBasisPercent {
+				t.Errorf("basis = %q, want %q", p.Basis, OOMBasisPercent)
+			}
+			if p.MinutesToOOM != 15 || p.GrowthPerMinute != 2 {
+				t.Errorf("projection = %.1f min at %.1f%%/min, want 15 at 2", p.MinutesToOOM, p.GrowthPerMinute)
+			}
+			if want := testEpoch.Add(15 * time.Minute); !p.EstimatedOOM.Equal(want) {
+				t.Errorf("estimated OOM = %v, want %v", p.EstimatedOOM, want)
+			}
+		})
+	}
+
+	if p := oomProjection(cfg, &ServiceFeatures{MemoryMean: 70, MemoryTrend: -1}, nil, 0, testEpoch); p != nil {
+		t.Errorf("shrinking memory projected %+v, want nil", p)
+	}
+}
+
+func TestOOMProjectionDescribe(t *testing.T) {
+	limit := &OOMProjection{Basis: OOMBasisMemoryLimit, LimitMB: 512, CurrentMB: 450, GrowthPerMinute: 10, MinutesToOOM: 6.2}
+	if got, want := limit.Describe(), "450Mi of 512Mi limit, growing 10.0Mi/min - OOM in ~6 min"; got != want {
+		t.Errorf("Describe() = %q, want %q", got, want)
+	}
+	percent := &OOMProjection{Basis: OOMBasisPercent, GrowthPerMinute: 2, MinutesToOOM: 15}
+	if got, want := percent.Describe(), "growing 2.00%/min - memory exhausted in ~15 min"; got != want {
+		t.Errorf("Describe() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,60 @@
+package analyzer
+
+import (
+	"math"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+)
+
+// MinTrendDataPoints is the fewest samples DetectTrend will fit a line through
+const MinTrendDataPoints = 5
+
+// TrendResult describes a significant linear trend in a metric series
+type TrendResult struct {
+	Direction         string  `json:"direction"` // increasing, decreasing
+	Slope             float64 `json:"slope"`     // units per minute
+	Intercept         float64 `json:"intercept"`
+	RSquared          float64 `json:"r_squared"`
+	GrowthRatePercent float64 `json:"growth_rate_percent"`
+	DataPoints        int     `json:"data_points"`
+}
+
+// PatternMatcher recognizes shapes in raw metric series
+type PatternMatcher struct {
+	minSlope    float64
+	minRSquared float64
+}
+
+func NewPatternMatcher() *PatternMatcher {
+	return &PatternMatcher{
+		minSlope:    0.1, // same cut-off detectPatterns uses for HasTrend
+		minRSquared: 0.3, // below this the fit is mostly noise
+	}
+}
+
+// DetectTrend fits a linear regression over the series and returns nil when
+// there is no significant trend, i.e. the metric is stable.
+func (pm *PatternMatcher) DetectTrend(metrics []*storage.Metric) *TrendResult {
+	if len(metrics) < MinTrendDataPoints {
+		return nil
+	}
+
+	slope, intercept, rSquared, growthRate := PerformLinearRegression(metrics)
+	if math.Abs(slope) < pm.minSlope || rSquared < pm.minRSquared {
+		return nil
+	}
+
+	direction := "increasing"
+	if slope < 0 {
+		direction = "decreasing"
+	}
+
+	return &TrendResult{
+		Direction:         direction,
+		Slope:             slope,
+		Intercept:         intercept,
+		RSquared:          rSquared,
+		GrowthRatePercent: growthRate,
+		DataPoints:        len(metrics),
+	}
+}
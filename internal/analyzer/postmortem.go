@@ -0,0 +1,382 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// postmortemPadding widens the window on both sides of an incident so
+	// the bundle shows what led up to it and how the service settled
+	postmortemPadding = 30 * time.Minute
+
+	maxPostmortemDiagnoses = 1000
+	maxPostmortemDecisions = 200
+)
+
+// postmortemMetrics are the canonical metrics summarized around an incident
+var postmortemMetrics = []string{MetricCPU, MetricMemory, MetricErrors, MetricLatency, MetricRequests}
+
+// Postmortem bundles everything recorded about one incident: what happened
+// when, what AURA diagnosed and did, how the metrics moved and how it ended
+type Postmortem struct {
+	Incident    *storage.Incident `json:"incident"`
+	From        time.Time         `json:"from"` // window covered, incident +/- padding
+	To          time.Time         `json:"to"`
+	GeneratedAt time.Time         `json:"generated_at"`
+
+	Timeline    []*TimelineEvent            `json:"timeline"`
+	Diagnoses   []*storage.DiagnosisSummary `json:"diagnoses"`
+	Metrics     []PostmortemMetric          `json:"metrics"`
+	Decisions   []*storage.Decision         `json:"decisions"`
+	BlastRadius *BlastRadius                `json:"blast_radius,omitempty"` // from the latest diagnosis
+	Outcome     PostmortemOutcome           `json:"outcome"`
+}
+
+// PostmortemMetric compares a metric before, during and after an incident.
+// A phase with no samples is nil.
+type PostmortemMetric struct {
+	Metric     string    `json:"metric"` // canonical name
+	Series     string    `json:"series"` // stored name it resolved to
+	Samples    int       `json:"samples"`
+	BeforeMean *float64  `json:"before_mean"`
+	DuringMean *float64  `json:"during_mean"`
+	AfterMean  *float64  `json:"after_mean"`
+	Peak       float64   `json:"peak"`
+	PeakAt     time.Time `json:"peak_at"`
+}
+
+// PostmortemOutcome is how the incident ended, or where it stands if open
+type PostmortemOutcome struct {
+	Status          string  `json:"status"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Duration        string  `json:"duration"`
+	ActionsProposed int     `json:"actions_proposed"`
+	ActionsExecuted int     `json:"actions_executed"`
+
+	// Remediation is the latest recovery check after a remediation:
+	// resolved, persists or worsened
+	Remediation          string     `json:"remediation,omitempty"`
+	RemediationCheckedAt *time.Time `json:"remediation_checked_at,omitempty"`
+}
+
+// PostmortemBuilder assembles postmortems from stored incidents, diagnoses,
+// decisions and metrics
+type PostmortemBuilder struct {
+	db       *storage.PostgresClient
+	resolver *MetricResolver
+}
+
+func NewPostmortemBuilder(db *storage.PostgresClient, config *core.ConfigStore) *PostmortemBuilder {
+	return &PostmortemBuilder{
+		db:       db,
+		resolver: NewMetricResolver(db, config),
+	}
+}
+
+// Build assembles the postmortem of an incident. It returns
+// storage.ErrNotFound when there is no such incident.
+func (b *PostmortemBuilder) Build(ctx context.Context, incidentID string) (*Postmortem, error) {
+	inc, err := b.db.GetIncident(ctx, incidentID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	end := now
+	if inc.ClosedAt != nil {
+		end = *inc.ClosedAt
+	}
+	pm := &Postmortem{
+		Incident:    inc,
+		From:        inc.FirstSeen.Add(-postmortemPadding),
+		To:          end.Add(postmortemPadding),
+		GeneratedAt: now,
+	}
+	if pm.To.After(now) {
+		pm.To = now
+	}
+
+	annotations, err := b.db.GetAnnotations(ctx, inc.ServiceName, pm.From, pm.To)
+	if err != nil {
+		return nil, err
+	}
+	pm.Diagnoses, err = b.db.GetDiagnosisSummariesBetween(ctx, inc.ServiceName, pm.From, pm.To, maxPostmortemDiagnoses)
+	if err != nil {
+		return nil, err
+	}
+	pm.Decisions, err = b.db.GetDecisions(ctx, storage.DecisionFilter{
+		ServiceName: inc.ServiceName,
+		Since:       pm.From,
+		Until:       pm.To,
+		Limit:       maxPostmortemDecisions,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// GetDecisions is newest first; the bundle reads oldest first
+	sort.SliceStable(pm.Decisions, func(i, j int) bool {
+		return pm.Decisions[i].Timestamp.Before(pm.Decisions[j].Timestamp)
+	})
+
+	pm.Timeline = postmortemTimeline(annotations, pm.Decisions)
+	pm.Metrics = b.metrics(ctx, inc, pm.From, pm.To, end)
+	pm.BlastRadius = b.blastRadius(ctx, inc)
+	pm.Outcome = postmortemOutcome(inc, end, pm.Decisions)
+	return pm, nil
+}
+
+// postmortemTimeline merges the service's annotations with the decisions
+// taken into one chronological list of events
+func postmortemTimeline(annotations []storage.Annotation, decisions []*storage.Decision) []*TimelineEvent {
+	events := make([]*TimelineEvent, 0, len(annotations)+len(decisions))
+	for _, a := range annotations {
+		events = append(events, &TimelineEvent{Timestamp: a.Time, Type: a.Type, Description: a.Text})
+	}
+	for _, d := range decisions {
+		events = append(events, &TimelineEvent{
+			Timestamp:   d.Timestamp,
+			Type:        "decision",
+			Description: fmt.Sprintf("%s proposed for %s: %s", d.ActionType, d.PatternDetected, d.Reason),
+		})
+		if d.Executed && d.ExecutedAt != nil {
+			description := d.ActionType + " executed"
+			if d.ExecutionResult != "" {
+				description += ": " + d.ExecutionResult
+			}
+			events = append(events, &TimelineEvent{
+				Timestamp:   *d.ExecutedAt,
+				Type:        "action_executed",
+				Description: description,
+			})
+		}
+		if d.Outcome != "" && d.OutcomeCheckedAt != nil {
+			events = append(events, &TimelineEvent{
+				Timestamp:   *d.OutcomeCheckedAt,
+				Type:        "remediation_outcome",
+				Description: fmt.Sprintf("%s outcome: %s", d.ActionType, d.Outcome),
+			})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+	return events
+}
+
+// metrics summarizes each canonical metric with data over [from, to],
+// split at the incident's first sighting and its end
+func (b *PostmortemBuilder) metrics(ctx context.Context, inc *storage.Incident, from, to, end time.Time) []PostmortemMetric {
+	series, totals, err := b.resolver.ResolveSeriesMulti(storage.WithAsOf(ctx, to), inc.ServiceName, postmortemMetrics, to.Sub(from))
+	if err != nil {
+		logger.FromContext(ctx).Warn("Postmortem metric lookup failed", zap.String("incident", inc.ID), zap.Error(err))
+		return []PostmortemMetric{}
+	}
+
+	result := make([]PostmortemMetric, 0, len(series))
+	for _, canonical := range postmortemMetrics {
+		samples := series[canonical]
+		if len(samples) == 0 {
+			continue
+		}
+		m := PostmortemMetric{
+			Metric:  canonical,
+			Series:  samples[0].MetricName,
+			Samples: totals[canonical],
+			Peak:    math.Inf(-1),
+		}
+		var before, during, after []float64
+		for _, s := range samples {
+			switch {
+			case s.Timestamp.Before(inc.FirstSeen):
+				before = append(before, s.MetricValue)
+			case s.Timestamp.After(end):
+				after = append(after, s.MetricValue)
+			default:
+				during = append(during, s.MetricValue)
+			}
+			if s.MetricValue > m.Peak {
+				m.Peak = s.MetricValue
+				m.PeakAt = s.Timestamp
+			}
+		}
+		m.BeforeMean, m.DuringMean, m.AfterMean = phaseMean(before), phaseMean(during), phaseMean(after)
+		result = append(result, m)
+	}
+	return result
+}
+
+func phaseMean(values []float64) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	mean := math.Round(CalculateMean(values)*100) / 100
+	return &mean
+}
+
+// blastRadius reads the blast radius from the incident's latest stored
+// diagnosis, if it was an enhanced one
+func (b *PostmortemBuilder) blastRadius(ctx context.Context, inc *storage.Incident) *BlastRadius {
+	if inc.LastPredictionID == "" {
+		return nil
+	}
+	record, err := b.db.GetUltimateDiagnosisByID(ctx, inc.LastPredictionID)
+	if err != nil {
+		logger.FromContext(ctx).Warn("Postmortem diagnosis lookup failed", zap.String("incident", inc.ID), zap.Error(err))
+		return nil
+	}
+	if record == nil {
+		return nil
+	}
+	raw, ok := record.Diagnosis.(json.RawMessage)
+	if !ok {
+		return nil
+	}
+
+	var diag struct {
+		EnhancedData *EnhancedDiagnosticData `json:"enhanced_data"`
+	}
+	if err := json.Unmarshal(raw, &diag); err != nil {
+		logger.FromContext(ctx).Warn("Postmortem diagnosis is unreadable", zap.String("incident", inc.ID), zap.Error(err))
+		return nil
+	}
+	if diag.EnhancedData == nil || diag.EnhancedData.DetailedRootCause == nil {
+		return nil
+	}
+	return diag.EnhancedData.DetailedRootCause.BlastRadius
+}
+
+func postmortemOutcome(inc *storage.Incident, end time.Time, decisions []*storage.Decision) PostmortemOutcome {
+	duration := end.Sub(inc.FirstSeen).Round(time.Second)
+	outcome := PostmortemOutcome{
+		Status:               inc.Status,
+		DurationSeconds:      duration.Seconds(),
+		Duration:             duration.String(),
+		ActionsProposed:      len(decisions),
+		Remediation:          inc.RemediationOutcome,
+		RemediationCheckedAt: inc.RemediationCheckedAt,
+	}
+	for _, d := range decisions {
+		if d.Executed {
+			outcome.ActionsExecuted++
+		}
+	}
+	return outcome
+}
+
+// Markdown renders the postmortem as a document to paste into a review
+func (pm *Postmortem) Markdown() string {
+	var b strings.Builder
+	inc := pm.Incident
+
+	fmt.Fprintf(&b, "# Postmortem: %s %s\n\n", inc.ServiceName, inc.ProblemType)
+	fmt.Fprintf(&b, "- **Incident:** %s\n", inc.ID)
+	fmt.Fprintf(&b, "- **Status:** %s\n", pm.Outcome.Status)
+	fmt.Fprintf(&b, "- **First seen:** %s\n", inc.FirstSeen.UTC().Format(time.RFC3339))
+	if inc.ClosedAt != nil {
+		fmt.Fprintf(&b, "- **Closed:** %s\n", inc.ClosedAt.UTC().Format(time.RFC3339))
+	}
+	fmt.Fprintf(&b, "- **Duration:** %s\n", pm.Outcome.Duration)
+	fmt.Fprintf(&b, "- **Peak severity:** %s\n", inc.PeakSeverity)
+	fmt.Fprintf(&b, "- **Occurrences:** %d\n\n", inc.Occurrences)
+
+	b.WriteString("## Timeline\n\n")
+	if len(pm.Timeline) == 0 {
+		b.WriteString("No events were recorded.\n")
+	}
+	for _, e := range pm.Timeline {
+		fmt.Fprintf(&b, "- `%s` **%s** %s\n", e.Timestamp.UTC().Format(time.RFC3339), e.Type, e.Description)
+	}
+	b.WriteString("\n")
+
+	b.WriteString("## Metrics\n\n")
+	if len(pm.Metrics) == 0 {
+		b.WriteString("No metrics were stored for the window.\n")
+	} else {
+		b.WriteString("| Metric | Before | During | After | Peak |\n")
+		b.WriteString("|---|---|---|---|---|\n")
+		for _, m := range pm.Metrics {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %.2f at %s |\n",
+				m.Metric, formatMean(m.BeforeMean), formatMean(m.DuringMean), formatMean(m.AfterMean),
+				m.Peak, m.PeakAt.UTC().Format(time.RFC3339))
+		}
+	}
+	b.WriteString("\n")
+
+	b.WriteString("## Diagnoses\n\n")
+	if len(pm.Diagnoses) == 0 {
+		b.WriteString("No diagnoses were stored for the window.\n")
+	} else {
+		b.WriteString("| Time | Problem | Severity | Confidence | Health |\n")
+		b.WriteString("|---|---|---|---|---|\n")
+		for _, d := range pm.Diagnoses {
+			fmt.Fprintf(&b, "| %s | %s | %s | %.1f%% | %.0f |\n",
+				d.Timestamp.UTC().Format(time.RFC3339), d.PrimaryProblem, d.PrimarySeverity, d.PrimaryConfidence, d.HealthScore)
+		}
+	}
+	b.WriteString("\n")
+
+	if br := pm.BlastRadius; br != nil {
+		b.WriteString("## Blast Radius\n\n")
+		fmt.Fprintf(&b, "- **Scope:** %s\n", br.Scope)
+		if len(br.AffectedServices) > 0 {
+			fmt.Fprintf(&b, "- **Affected services:** %s\n", strings.Join(br.AffectedServices, ", "))
+		}
+		if br.AffectedUsers != "" {
+			fmt.Fprintf(&b, "- **Affected users:** %s\n", br.AffectedUsers)
+		}
+		if len(br.DownstreamImpact) > 0 {
+			fmt.Fprintf(&b, "- **Downstream:** %s\n", strings.Join(br.DownstreamImpact, ", "))
+		}
+		if len(br.UpstreamImpact) > 0 {
+			fmt.Fprintf(&b, "- **Upstream:** %s\n", strings.Join(br.UpstreamImpact, ", "))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## Actions\n\n")
+	if len(pm.Decisions) == 0 {
+		b.WriteString("No actions were taken.\n")
+	}
+	for _, d := range pm.Decisions {
+		state := "not executed"
+		if d.Executed {
+			state = "executed"
+		}
+		if d.Outcome != "" {
+			state += ", " + d.Outcome
+		}
+		fmt.Fprintf(&b, "- `%s` **%s** (%s): %s\n", d.Timestamp.UTC().Format(time.RFC3339), d.ActionType, state, d.Reason)
+	}
+	b.WriteString("\n")
+
+	b.WriteString("## Outcome\n\n")
+	if pm.Outcome.Status == storage.IncidentClosed {
+		fmt.Fprintf(&b, "Closed after %s", pm.Outcome.Duration)
+	} else {
+		fmt.Fprintf(&b, "Still open after %s", pm.Outcome.Duration)
+	}
+	fmt.Fprintf(&b, ", with %d of %d proposed actions executed.", pm.Outcome.ActionsExecuted, pm.Outcome.ActionsProposed)
+	if pm.Outcome.Remediation != "" {
+		fmt.Fprintf(&b, " The last remediation check found the problem %s.", pm.Outcome.Remediation)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func formatMean(v *float64) string {
+	if v == nil {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.2f", *v)
+}
@@ -0,0 +1,241 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+)
+
+// closedIncident is an incident that was closed an hour after firstSeen
+func closedIncident(service string, firstSeen time.Time) *storage.Incident {
+	closed := firstSeen.Add(time.Hour)
+	return &storage.Incident{
+		ID:              service + "-MEMORY_LEAK-postmortem",
+		ServiceName:     service,
+		ProblemType:     "MEMORY_LEAK",
+		Status:          storage.IncidentClosed,
+		FirstSeen:       firstSeen,
+		LastSeen:        closed,
+		ClosedAt:        &closed,
+		Occurrences:     6,
+		PeakSeverity:    SeverityHigh,
+		CurrentSeverity: SeverityLow,
+	}
+}
+
+func TestPostmortemTimeline(t *testing.T) {
+	executedAt := testEpoch.Add(10 * time.Minute)
+	checkedAt := testEpoch.Add(20 * time.Minute)
+	decisions := []*storage.Decision{
+		{
+			Timestamp:        testEpoch.Add(5 * time.Minute),
+			PatternDetected:  "MEMORY_LEAK",
+			ActionType:       "restart",
+			Reason:           "memory climbing",
+			Executed:         true,
+			ExecutedAt:       &executedAt,
+			ExecutionResult:  "pods restarted",
+			Outcome:          storage.OutcomeResolved,
+			OutcomeCheckedAt: &checkedAt,
+		},
+		{Timestamp: testEpoch.Add(30 * time.Minute), PatternDetected: "MEMORY_LEAK", ActionType: "scale_up", Reason: "still high"},
+	}
+	annotations := []storage.Annotation{
+		{Time: testEpoch.Add(40 * time.Minute), Type: storage.AnnotationIncidentClosed, Text: "MEMORY_LEAK closed"},
+		{Time: testEpoch, Type: storage.AnnotationIncidentOpened, Text: "MEMORY_LEAK opened"},
+	}
+
+	events := postmortemTimeline(annotations, decisions)
+
+	want := []struct {
+		typ         string
+		offset      time.Duration
+		description string
+	}{
+		{storage.AnnotationIncidentOpened, 0, "MEMORY_LEAK opened"},
+		{"decision", 5 * time.Minute, "restart proposed for MEMORY_LEAK: memory climbing"},
+		{"action_executed", 10 * time.Minute, "restart executed: pods restarted"},
+		{"remediation_outcome", 20 * time.Minute, "restart outcome: resolved"},
+		{"decision", 30 * time.Minute, "scale_up proposed for MEMORY_LEAK: still high"},
+		{storage.AnnotationIncidentClosed, 40 * time.Minute, "MEMORY_LEAK closed"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d", len(events), len(want))
+	}
+	for i, w := range want {
+		e := events[i]
+		if e.Type != w.typ || !e.Timestamp.Equal(testEpoch.Add(w.offset)) || e.Description != w.description {
+			t.Errorf("event %d = %s at %v %q, want %s at +%v %q", i, e.Type, e.Timestamp, e.Description, w.typ, w.offset, w.description)
+		}
+	}
+}
+
+func TestPhaseMean(t *testing.T) {
+	if got := phaseMean(nil); got != nil {
+		t.Errorf("phaseMean(nil) = %v, want nil", *got)
+	}
+	if got := phaseMean([]float64{1, 2, 2}); got == nil || *got != 1.67 {
+		t.Errorf("phaseMean(1, 2, 2) = %v, want 1.67", got)
+	}
+}
+
+func TestPostmortemOutcome(t *testing.T) {
+	inc := closedIncident("checkout", testEpoch)
+	inc.RemediationOutcome = storage.OutcomeResolved
+	decisions := []*storage.Decision{{Executed: true}, {}, {Executed: true}}
+
+	outcome := postmortemOutcome(inc, *inc.ClosedAt, decisions)
+
+	if outcome.Status != storage.IncidentClosed || outcome.Duration != "1h0m0s" || outcome.DurationSeconds != 3600 {
+		t.Errorf("outcome = %s after %s (%vs), want closed after 1h0m0s", outcome.Status, outcome.Duration, outcome.DurationSeconds)
+	}
+	if outcome.ActionsProposed != 3 || outcome.ActionsExecuted != 2 {
+		t.Errorf("actions = %d of %d executed, want 2 of 3", outcome.ActionsExecuted, outcome.ActionsProposed)
+	}
+	if outcome.Remediation != storage.OutcomeResolved {
+		t.Errorf("remediation = %q, want resolved", outcome.Remediation)
+	}
+}
+
+func TestPostmortemMarkdown(t *testing.T) {
+	inc := closedIncident("checkout", testEpoch)
+	before, during := 40.0, 92.5
+	decision := &storage.Decision{
+		Timestamp:  testEpoch.Add(5 * time.Minute),
+		ActionType: "restart",
+		Reason:     "memory climbing",
+		Executed:   true,
+		Outcome:    storage.OutcomeResolved,
+	}
+	pm := &Postmortem{
+		Incident: inc,
+		Timeline: []*TimelineEvent{{Timestamp: testEpoch, Type: storage.AnnotationIncidentOpened, Description: "MEMORY_LEAK opened"}},
+		Metrics: []PostmortemMetric{{
+			Metric: MetricMemory, BeforeMean: &before, DuringMean: &during,
+			Peak: 97, PeakAt: testEpoch.Add(50 * time.Minute),
+		}},
+		Decisions:   []*storage.Decision{decision},
+		BlastRadius: &BlastRadius{Scope: "service", AffectedServices: []string{"checkout", "payments"}},
+		Outcome:     postmortemOutcome(inc, *inc.ClosedAt, []*storage.Decision{decision}),
+	}
+
+	md := pm.Markdown()
+
+	for _, want := range []string{
+		"# Postmortem: checkout MEMORY_LEAK\n",
+		"- **Closed:** 2026-01-01T13:00:00Z\n",
+		"- **Duration:** 1h0m0s\n",
+		"- `2026-01-01T12:00:00Z` **incident_opened** MEMORY_LEAK opened\n",
+		"| memory_usage | 40.00 | 92.50 | n/a | 97.00 at 2026-01-01T12:50:00Z |\n",
+		"No diagnoses were stored for the window.\n",
+		"- **Affected services:** checkout, payments\n",
+		"- `2026-01-01T12:05:00Z` **restart** (executed, resolved): memory climbing\n",
+		"Closed after 1h0m0s, with 1 of 1 proposed actions executed.\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown is missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestBuildPostmortemFromSeededClosedIncident(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+
+	// An hour-long incident that closed an hour ago, inside a window padded
+	// by postmortemPadding on both sides
+	now := time.Now().UTC().Truncate(time.Minute)
+	inc := closedIncident(service, now.Add(-2*time.Hour))
+	if err := db.SaveIncident(ctx, inc); err != nil {
+		t.Fatalf("SaveIncident: %v", err)
+	}
+
+	// Every 10 minutes from -155m to -35m: 4 samples before the incident,
+	// 6 during and 3 after
+	values := append(append(generate(4, func(int) float64 { return 20 }),
+		generate(6, func(i int) float64 { return 85 + float64(i) })...),
+		generate(3, func(int) float64 { return 25 })...)
+	storagetest.Seed(t, db, storagetest.Series(service, MetricMemory, now.Add(-35*time.Minute), 10*time.Minute, values...))
+
+	executedAt := inc.FirstSeen.Add(15 * time.Minute)
+	for _, d := range []*storage.Decision{
+		{
+			Timestamp: inc.FirstSeen.Add(10 * time.Minute), ServiceName: service,
+			PatternDetected: "MEMORY_LEAK", ActionType: "restart", Reason: "memory climbing",
+			Executed: true, ExecutedAt: &executedAt, Confidence: 90,
+		},
+		{
+			Timestamp: inc.FirstSeen.Add(40 * time.Minute), ServiceName: service,
+			PatternDetected: "MEMORY_LEAK", ActionType: "scale_up", Reason: "still high", Confidence: 80,
+		},
+		// After the padded window: not part of the postmortem
+		{
+			Timestamp: now.Add(-5 * time.Minute), ServiceName: service,
+			PatternDetected: "MEMORY_LEAK", ActionType: "restart", Reason: "later", Confidence: 70,
+		},
+	} {
+		if err := db.SaveDecision(ctx, d); err != nil {
+			t.Fatalf("SaveDecision: %v", err)
+		}
+	}
+
+	cfg := &core.Config{}
+	cfg.ApplyDefaults()
+	builder := NewPostmortemBuilder(db, core.NewConfigStore("", cfg))
+
+	pm, err := builder.Build(ctx, inc.ID)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	if !pm.From.Equal(inc.FirstSeen.Add(-postmortemPadding)) || !pm.To.Equal(inc.ClosedAt.Add(postmortemPadding)) {
+		t.Errorf("window = [%v, %v], want the incident padded by %v", pm.From, pm.To, postmortemPadding)
+	}
+
+	if len(pm.Metrics) != 1 {
+		t.Fatalf("got %d metrics, want memory only", len(pm.Metrics))
+	}
+	m := pm.Metrics[0]
+	if m.Metric != MetricMemory || m.Samples != len(values) {
+		t.Errorf("metric = %s with %d samples, want %s with %d", m.Metric, m.Samples, MetricMemory, len(values))
+	}
+	if m.BeforeMean == nil || *m.BeforeMean != 20 || m.DuringMean == nil || *m.DuringMean != 87.5 || m.AfterMean == nil || *m.AfterMean != 25 {
+		t.Errorf("means = %s / %s / %s, want 20.00 / 87.50 / 25.00", formatMean(m.BeforeMean), formatMean(m.DuringMean), formatMean(m.AfterMean))
+	}
+	if m.Peak != 90 || !m.PeakAt.Equal(now.Add(-65*time.Minute)) {
+		t.Errorf("peak = %v at %v, want 90 at the last sample during the incident", m.Peak, m.PeakAt)
+	}
+
+	if len(pm.Decisions) != 2 || pm.Decisions[0].ActionType != "restart" || pm.Decisions[1].ActionType != "scale_up" {
+		t.Fatalf("decisions = %+v, want restart then scale_up", pm.Decisions)
+	}
+	if pm.Outcome.Status != storage.IncidentClosed || pm.Outcome.Duration != "1h0m0s" ||
+		pm.Outcome.ActionsProposed != 2 || pm.Outcome.ActionsExecuted != 1 {
+		t.Errorf("outcome = %+v, want closed after 1h0m0s with 1 of 2 actions executed", pm.Outcome)
+	}
+
+	types := make(map[string]int)
+	for i, e := range pm.Timeline {
+		types[e.Type]++
+		if i > 0 && e.Timestamp.Before(pm.Timeline[i-1].Timestamp) {
+			t.Errorf("timeline is out of order at %d", i)
+		}
+	}
+	if types["decision"] != 2 || types["action_executed"] != 1 {
+		t.Errorf("timeline types = %v, want 2 decisions and 1 executed action", types)
+	}
+	if types[storage.AnnotationIncidentOpened] != 1 || types[storage.AnnotationIncidentClosed] != 1 {
+		t.Errorf("timeline types = %v, want the incident's opening and closing", types)
+	}
+
+	if _, err := builder.Build(ctx, service+"-no-such-incident"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("unknown incident: err = %v, want ErrNotFound", err)
+	}
+}
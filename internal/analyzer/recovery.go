@@ -0,0 +1,172 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultRecoveryVerifyAfter = 5 * time.Minute
+	minRecoveryCheckInterval   = 10 * time.Second
+
+	// Decisions executed longer ago than this are no longer verified
+	recoveryMaxAge = 24 * time.Hour
+
+	// maxRecoveryChecks bounds the decisions verified per pass
+	maxRecoveryChecks = 50
+
+	// recoveryHealthDrop is how far the health score must fall, in points,
+	// for a persisting problem to count as worsened
+	recoveryHealthDrop = 10
+)
+
+// RecoveryVerifier closes the loop on remediations: some time after a
+// decision is executed it re-diagnoses the service and records whether the
+// problem the decision addressed cleared
+type RecoveryVerifier struct {
+	ua     *UltimateAnalyzer
+	db     *storage.PostgresClient
+	config *core.ConfigStore
+}
+
+func NewRecoveryVerifier(ua *UltimateAnalyzer, config *core.ConfigStore) *RecoveryVerifier {
+	return &RecoveryVerifier{ua: ua, db: ua.db, config: config}
+}
+
+// VerifyAfter returns how long after execution a decision is verified
+func (v *RecoveryVerifier) VerifyAfter() time.Duration {
+	if cfg := v.config.Get(); cfg != nil {
+		if d, err := time.ParseDuration(cfg.Incidents.VerifyAfter); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultRecoveryVerifyAfter
+}
+
+// checkInterval is how often Run looks for decisions due for verification
+func (v *RecoveryVerifier) checkInterval() time.Duration {
+	interval := v.VerifyAfter() / 5
+	if interval < minRecoveryCheckInterval {
+		interval = minRecoveryCheckInterval
+	}
+	return interval
+}
+
+// Run verifies due decisions until the context is cancelled. The interval is
+// re-read after every pass so a config reload takes effect.
+func (v *RecoveryVerifier) Run(ctx context.Context) {
+	ticker := time.NewTicker(v.checkInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := v.Check(ctx); err != nil && ctx.Err() == nil {
+				logger.FromContext(ctx).Warn("Recovery check failed", zap.Error(err))
+			}
+			ticker.Reset(v.checkInterval())
+		}
+	}
+}
+
+// Check verifies every decision executed at least VerifyAfter ago that has
+// no outcome yet, and returns how many were recorded
+func (v *RecoveryVerifier) Check(ctx context.Context) (int, error) {
+	now := time.Now()
+	decisions, err := v.db.GetUnverifiedDecisions(ctx, now.Add(-recoveryMaxAge), now.Add(-v.VerifyAfter()), maxRecoveryChecks)
+	if err != nil {
+		return 0, err
+	}
+
+	recorded := 0
+	for _, d := range decisions {
+		if err := v.verify(ctx, d); err != nil {
+			logger.FromContext(ctx).Warn("Failed to verify decision outcome",
+				zap.Int64("decision_id", d.ID),
+				zap.String("service", d.ServiceName),
+				zap.Error(err))
+			continue
+		}
+		recorded++
+	}
+	return recorded, nil
+}
+
+func (v *RecoveryVerifier) verify(ctx context.Context, d *storage.Decision) error {
+	diag, err := v.ua.DiagnoseService(ctx, d.ServiceName)
+	if err != nil {
+		return err
+	}
+
+	outcome := RecoveryOutcome(baselineOf(d), DetectionType(d.PatternDetected), diag)
+	checkedAt := time.Now()
+	if err := v.db.SetDecisionOutcome(ctx, d.ID, outcome, checkedAt); err != nil {
+		return err
+	}
+	if _, err := v.db.SetIncidentOutcome(ctx, d.ServiceName, d.PatternDetected, outcome, checkedAt); err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).Info("🔁 Remediation outcome recorded",
+		zap.Int64("decision_id", d.ID),
+		zap.String("service", d.ServiceName),
+		zap.String("problem", d.PatternDetected),
+		zap.String("outcome", outcome))
+	return nil
+}
+
+// RemediationBaseline is the state of a service when a decision was executed
+type RemediationBaseline struct {
+	Severity    string   `json:"severity"`
+	HealthScore *float64 `json:"health_score"`
+}
+
+// baselineOf reads the baseline stored in a decision's parameters; decisions
+// recorded without one compare against a zero baseline
+func baselineOf(d *storage.Decision) RemediationBaseline {
+	var b RemediationBaseline
+	if len(d.Parameters) > 0 {
+		_ = json.Unmarshal(d.Parameters, &b)
+	}
+	return b
+}
+
+// RecoveryOutcome compares a fresh diagnosis with the baseline of the
+// problem a decision addressed. The problem no longer being detected is
+// resolved unless something more severe took its place; a problem still
+// detected is worsened when its severity rose or health fell by
+// recoveryHealthDrop points, and persists otherwise.
+func RecoveryOutcome(before RemediationBaseline, problem DetectionType, diag *UltimateDiagnosis) string {
+	var current *Detection
+	for _, d := range diag.AllDetections {
+		if d.Type == problem && d.Detected {
+			current = d
+			break
+		}
+	}
+
+	if current == nil {
+		primary := diag.PrimaryDetection
+		if primary != nil && primary.Detected && before.Severity != "" &&
+			severityRank[primary.Severity] > severityRank[before.Severity] {
+			return storage.OutcomeWorsened
+		}
+		return storage.OutcomeResolved
+	}
+
+	if before.Severity != "" && severityRank[current.Severity] > severityRank[before.Severity] {
+		return storage.OutcomeWorsened
+	}
+	if before.HealthScore != nil && *before.HealthScore-diag.HealthScore >= recoveryHealthDrop {
+		return storage.OutcomeWorsened
+	}
+	return storage.OutcomePersists
+}
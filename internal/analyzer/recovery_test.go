@@ -0,0 +1,191 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+)
+
+func TestRecoveryOutcome(t *testing.T) {
+	health := func(v float64) *float64 { return &v }
+	detected := func(typ DetectionType, severity string) *Detection {
+		return &Detection{Type: typ, Detected: true, Confidence: 70, Severity: severity}
+	}
+	diagnosis := func(healthScore float64, detections ...*Detection) *UltimateDiagnosis {
+		d := &UltimateDiagnosis{HealthScore: healthScore, AllDetections: detections}
+		d.PrimaryDetection = primaryOf(detections)
+		return d
+	}
+
+	tests := []struct {
+		name   string
+		before RemediationBaseline
+		diag   *UltimateDiagnosis
+		want   string
+	}{
+		{
+			name:   "problem gone",
+			before: RemediationBaseline{Severity: SeverityHigh, HealthScore: health(40)},
+			diag:   diagnosis(95),
+			want:   storage.OutcomeResolved,
+		},
+		{
+			name:   "problem gone, milder one left",
+			before: RemediationBaseline{Severity: SeverityHigh},
+			diag:   diagnosis(80, detected(DetectionGCPressure, SeverityLow)),
+			want:   storage.OutcomeResolved,
+		},
+		{
+			name:   "problem gone, worse one took its place",
+			before: RemediationBaseline{Severity: SeverityMedium},
+			diag:   diagnosis(30, detected(DetectionGCPressure, SeverityCritical)),
+			want:   storage.OutcomeWorsened,
+		},
+		{
+			name:   "still detected at the same severity",
+			before: RemediationBaseline{Severity: SeverityHigh, HealthScore: health(50)},
+			diag:   diagnosis(45, detected(DetectionMemoryLeak, SeverityHigh)),
+			want:   storage.OutcomePersists,
+		},
+		{
+			name:   "still detected, severity rose",
+			before: RemediationBaseline{Severity: SeverityMedium},
+			diag:   diagnosis(50, detected(DetectionMemoryLeak, SeverityHigh)),
+			want:   storage.OutcomeWorsened,
+		},
+		{
+			name:   "still detected, health fell",
+			before: RemediationBaseline{Severity: SeverityHigh, HealthScore: health(50)},
+			diag:   diagnosis(40, detected(DetectionMemoryLeak, SeverityHigh)),
+			want:   storage.OutcomeWorsened,
+		},
+		{
+			name: "still detected, no baseline",
+			diag: diagnosis(10, detected(DetectionMemoryLeak, SeverityCritical)),
+			want: storage.OutcomePersists,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RecoveryOutcome(tt.before, DetectionMemoryLeak, tt.diag); got != tt.want {
+				t.Errorf("outcome = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBaselineOf(t *testing.T) {
+	params, _ := json.Marshal(map[string]interface{}{"service": "checkout", "severity": SeverityHigh, "health_score": 42.5})
+	b := baselineOf(&storage.Decision{Parameters: params})
+	if b.Severity != SeverityHigh || b.HealthScore == nil || *b.HealthScore != 42.5 {
+		t.Errorf("baseline = %+v, want HIGH at 42.5", b)
+	}
+
+	if b := baselineOf(&storage.Decision{}); b.Severity != "" || b.HealthScore != nil {
+		t.Errorf("baseline without parameters = %+v, want zero", b)
+	}
+}
+
+func TestRecoveryVerifyAfter(t *testing.T) {
+	cfg := &core.Config{}
+	cfg.ApplyDefaults()
+	v := &RecoveryVerifier{config: core.NewConfigStore("", cfg)}
+	if got := v.VerifyAfter(); got != defaultRecoveryVerifyAfter {
+		t.Errorf("VerifyAfter = %v, want the default %v", got, defaultRecoveryVerifyAfter)
+	}
+	if got := v.checkInterval(); got != defaultRecoveryVerifyAfter/5 {
+		t.Errorf("checkInterval = %v, want %v", got, defaultRecoveryVerifyAfter/5)
+	}
+
+	cfg.Incidents.VerifyAfter = "20s"
+	if got := v.VerifyAfter(); got != 20*time.Second {
+		t.Errorf("VerifyAfter = %v, want 20s", got)
+	}
+	if got := v.checkInterval(); got != minRecoveryCheckInterval {
+		t.Errorf("checkInterval = %v, want the %v floor", got, minRecoveryCheckInterval)
+	}
+}
+
+// TestRecoveryCheckRecordsOutcome seeds a service that recovered and one
+// still climbing in memory, records an executed decision for each and lets
+// the verifier settle them
+func TestRecoveryCheckRecordsOutcome(t *testing.T) {
+	db := storagetest.NewClient(t)
+	recovered := storagetest.Service(t, db)
+	leaking := storagetest.Service(t, db)
+	ctx := context.Background()
+
+	end := time.Now().Add(-30 * time.Second)
+	flat := generate(30, func(int) float64 { return 40 })
+	storagetest.Seed(t, db, storagetest.Series(recovered, MetricCPU, end, 30*time.Second, flat...))
+	storagetest.Seed(t, db, storagetest.Series(recovered, MetricMemory, end, 30*time.Second, flat...))
+	storagetest.Seed(t, db, storagetest.Series(leaking, MetricCPU, end, 30*time.Second, flat...))
+	storagetest.Seed(t, db, storagetest.Series(leaking, MetricMemory, end, 30*time.Second,
+		generate(30, func(i int) float64 { return 60 + float64(i) })...))
+
+	cfg := &core.Config{}
+	cfg.ApplyDefaults()
+	ua := NewUltimateAnalyzer(db, core.NewConfigStore("", cfg))
+
+	// Whatever the climb trips is the problem a remediation failed to clear
+	diag, err := ua.DiagnoseService(ctx, leaking)
+	if err != nil {
+		t.Fatalf("DiagnoseService: %v", err)
+	}
+	if diag.PrimaryDetection == nil || !diag.PrimaryDetection.Detected {
+		t.Fatalf("seeded memory climb detected nothing")
+	}
+	problem := diag.PrimaryDetection
+
+	executedAt := time.Now().Add(-10 * time.Minute)
+	record := func(service string) *storage.Decision {
+		params, _ := json.Marshal(map[string]interface{}{
+			"service":      service,
+			"severity":     problem.Severity,
+			"health_score": diag.HealthScore,
+		})
+		d := &storage.Decision{
+			Timestamp:       executedAt,
+			PatternDetected: string(problem.Type),
+			ActionType:      "RESTART",
+			Confidence:      problem.Confidence,
+			Reason:          "test remediation",
+			Parameters:      params,
+			Executed:        true,
+			ExecutedAt:      &executedAt,
+			ServiceName:     service,
+		}
+		if err := db.SaveDecision(ctx, d); err != nil {
+			t.Fatalf("SaveDecision: %v", err)
+		}
+		return d
+	}
+	fixed := record(recovered)
+	unfixed := record(leaking)
+
+	if _, err := NewRecoveryVerifier(ua, core.NewConfigStore("", cfg)).Check(ctx); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+
+	for _, tt := range []struct {
+		decision *storage.Decision
+		want     string
+	}{
+		{fixed, storage.OutcomeResolved},
+		{unfixed, storage.OutcomePersists},
+	} {
+		got, err := db.GetDecisionById(ctx, strconv.FormatInt(tt.decision.ID, 10))
+		if err != nil {
+			t.Fatalf("GetDecisionById: %v", err)
+		}
+		if got.Outcome != tt.want || got.OutcomeCheckedAt == nil {
+			t.Errorf("%s: outcome = %q checked at %v, want %s", got.ServiceName, got.Outcome, got.OutcomeCheckedAt, tt.want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+)
+
+// defaultAnalysisWindow is the feature window used by DiagnoseService
+const defaultAnalysisWindow = 30 * time.Minute
+
+type analysisWindowKey struct{}
+
+// WithAnalysisWindow overrides the feature extraction window for DiagnoseService
+func WithAnalysisWindow(ctx context.Context, window time.Duration) context.Context {
+	return context.WithValue(ctx, analysisWindowKey{}, window)
+}
+
+func analysisWindow(ctx context.Context) time.Duration {
+	if w, ok := ctx.Value(analysisWindowKey{}).(time.Duration); ok && w > 0 {
+		return w
+	}
+	return defaultAnalysisWindow
+}
+
+// AnalyzeAtTime replays a diagnosis as if it ran at asOf: every metric query
+// made during the analysis is bounded to end at asOf instead of now. A zero
+// window keeps the default feature window.
+func (ua *UltimateAnalyzer) AnalyzeAtTime(ctx context.Context, serviceName string, asOf time.Time, window time.Duration) (*UltimateDiagnosis, error) {
+	if asOf.After(time.Now()) {
+		return nil, fmt.Errorf("replay time %s is in the future", asOf.Format(time.RFC3339))
+	}
+
+	ctx = storage.WithAsOf(ctx, asOf)
+	if window > 0 {
+		ctx = WithAnalysisWindow(ctx, window)
+	}
+
+	return ua.DiagnoseService(ctx, serviceName)
+}
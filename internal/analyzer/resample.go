@@ -0,0 +1,71 @@
+package analyzer
+
+import (
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+)
+
+// maxResamplePoints bounds the grid; a step too fine for the series' span
+// is widened to fit
+const maxResamplePoints = 2000
+
+// ResampleUniform interpolates the series linearly onto a grid of step from
+// its first sample to its last. Every stretch of time then carries the same
+// weight in a regression, however irregular the scrapes or long the gaps.
+// Series of fewer than two samples, or a non-positive step, are returned
+// unchanged.
+func ResampleUniform(metrics []*storage.Metric, step time.Duration) []*storage.Metric {
+	if len(metrics) < 2 || step <= 0 {
+		return metrics
+	}
+	start := metrics[0].Timestamp
+	span := metrics[len(metrics)-1].Timestamp.Sub(start)
+	if span <= 0 {
+		return metrics
+	}
+	if span/step >= maxResamplePoints {
+		step = span / (maxResamplePoints - 1)
+	}
+
+	resampled := make([]*storage.Metric, 0, int(span/step)+1)
+	next := 1 // first sample at or after the grid point
+	for offset := time.Duration(0); offset <= span; offset += step {
+		t := start.Add(offset)
+		for next < len(metrics)-1 && metrics[next].Timestamp.Before(t) {
+			next++
+		}
+		prev, after := metrics[next-1], metrics[next]
+
+		value := after.MetricValue
+		if gap := after.Timestamp.Sub(prev.Timestamp); gap > 0 {
+			frac := float64(t.Sub(prev.Timestamp)) / float64(gap)
+			value = prev.MetricValue + frac*(after.MetricValue-prev.MetricValue)
+		}
+		resampled = append(resampled, &storage.Metric{
+			Timestamp:   t,
+			ServiceName: prev.ServiceName,
+			MetricName:  prev.MetricName,
+			MetricValue: value,
+		})
+	}
+	return resampled
+}
+
+// resampleStep returns analyzer.resample, or 0 when trends are fitted to the
+// raw timestamps
+func resampleStep(cfg *core.Config) time.Duration {
+	if cfg == nil || cfg.Analyzer.Resample == "" {
+		return 0
+	}
+	d, _ := time.ParseDuration(cfg.Analyzer.Resample) // validated in LoadConfig
+	return d
+}
+
+// trendSlope is the PerformLinearRegression slope (per minute) of the
+// series, resampled first when analyzer.resample is set
+func trendSlope(cfg *core.Config, metrics []*storage.Metric) float64 {
+	slope, _, _, _ := PerformLinearRegression(ResampleUniform(metrics, resampleStep(cfg)))
+	return slope
+}
@@ -0,0 +1,118 @@
+package analyzer
+
+import (
+	"math"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+)
+
+func TestResampleUniform(t *testing.T) {
+	// 0 at 0s, 10 at 10s, then a gap to 40 at 40s
+	metrics := []*storage.Metric{
+		{Timestamp: testEpoch, MetricValue: 0},
+		{Timestamp: testEpoch.Add(10 * time.Second), MetricValue: 10},
+		{Timestamp: testEpoch.Add(40 * time.Second), MetricValue: 40},
+	}
+
+	resampled := ResampleUniform(metrics, 5*time.Second)
+
+	if len(resampled) != 9 {
+		t.Fatalf("got %d points, want 9 from 0s to 40s", len(resampled))
+	}
+	for i, m := range resampled {
+		if !m.Timestamp.Equal(testEpoch.Add(time.Duration(i) * 5 * time.Second)) {
+			t.Errorf("point %d at %v, want on the 5s grid", i, m.Timestamp)
+		}
+		if want := float64(i * 5); math.Abs(m.MetricValue-want) > 1e-9 {
+			t.Errorf("point %d = %v, want %v interpolated", i, m.MetricValue, want)
+		}
+	}
+}
+
+func TestResampleUniformUnchanged(t *testing.T) {
+	series := seriesOf(time.Minute, 1, 2, 3)
+	if got := ResampleUniform(series, 0); len(got) != 3 || got[0] != series[0] {
+		t.Error("a zero step should return the series as is")
+	}
+	single := seriesOf(time.Minute, 1)
+	if got := ResampleUniform(single, time.Second); len(got) != 1 || got[0] != single[0] {
+		t.Error("a single sample should be returned as is")
+	}
+}
+
+func TestResampleUniformCapsPoints(t *testing.T) {
+	// A week at 1s would be 604801 points
+	metrics := []*storage.Metric{
+		{Timestamp: testEpoch, MetricValue: 0},
+		{Timestamp: testEpoch.Add(7 * 24 * time.Hour), MetricValue: 1},
+	}
+	if got := ResampleUniform(metrics, time.Second); len(got) > maxResamplePoints {
+		t.Errorf("got %d points, want at most %d", len(got), maxResamplePoints)
+	}
+}
+
+// gappySeries rises 1 per minute over an hour, scraped every 5 minutes,
+// with a burst of 1s scrapes catching a transient +20 blip at minute burstAt
+func gappySeries(burstAt int) []*storage.Metric {
+	var metrics []*storage.Metric
+	for minute := 0; minute <= 60; minute += 5 {
+		metrics = append(metrics, &storage.Metric{
+			Timestamp:   testEpoch.Add(time.Duration(minute) * time.Minute),
+			MetricValue: float64(minute),
+		})
+	}
+	burstStart := testEpoch.Add(time.Duration(burstAt)*time.Minute + 30*time.Second)
+	for s := 0; s < 60; s++ {
+		at := burstStart.Add(time.Duration(s) * time.Second)
+		metrics = append(metrics, &storage.Metric{
+			Timestamp:   at,
+			MetricValue: at.Sub(testEpoch).Minutes() + 20,
+		})
+	}
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].Timestamp.Before(metrics[j].Timestamp) })
+	return metrics
+}
+
+func TestResampleStabilizesGappySlope(t *testing.T) {
+	raw := &core.Config{}
+	resampled := &core.Config{}
+	resampled.Analyzer.Resample = "30s"
+
+	early, late := gappySeries(5), gappySeries(50)
+
+	rawEarly, rawLate := trendSlope(raw, early), trendSlope(raw, late)
+	resEarly, resLate := trendSlope(resampled, early), trendSlope(resampled, late)
+
+	// Without resampling, the dense burst dominates the fit and drags the
+	// slope down or up depending on where it falls
+	if rawEarly > 0.8 || rawLate < 1.2 {
+		t.Errorf("raw slopes = %.3f early, %.3f late, want the burst to bias them away from 1", rawEarly, rawLate)
+	}
+	// Resampled, the burst is one minute of an hour
+	for name, slope := range map[string]float64{"early": resEarly, "late": resLate} {
+		if math.Abs(slope-1) > 0.15 {
+			t.Errorf("resampled %s slope = %.3f, want close to the true 1/min", name, slope)
+		}
+	}
+	if spread, rawSpread := math.Abs(resLate-resEarly), math.Abs(rawLate-rawEarly); spread >= rawSpread/3 {
+		t.Errorf("resampled slopes spread %.3f, raw %.3f: want resampling to make the slope much steadier", spread, rawSpread)
+	}
+}
+
+func TestTrendSlopeDefaultsToRawTimestamps(t *testing.T) {
+	series := gappySeries(50)
+	want, _, _, _ := PerformLinearRegression(series)
+
+	cfg := &core.Config{}
+	cfg.ApplyDefaults()
+	if got := trendSlope(cfg, series); got != want {
+		t.Errorf("trendSlope = %v, want the raw regression %v", got, want)
+	}
+	if got := trendSlope(nil, series); got != want {
+		t.Errorf("trendSlope(nil config) = %v, want the raw regression %v", got, want)
+	}
+}
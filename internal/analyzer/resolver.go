@@ -0,0 +1,129 @@
+package analyzer
+
+import (
+	"context"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Canonical metric names the analyzer reasons about
+const (
+	MetricCPU     = "cpu_usage"
+	MetricMemory  = "memory_usage"
+	MetricErrors  = "error_rate"
+	MetricLatency = "response_time"
+
+	// MetricRequests is request volume, read as requests/min, for the error ratio
+	MetricRequests = "request_rate"
+
+	// MetricMemoryMB is absolute memory in MiB, used against configured limits
+	MetricMemoryMB = "memory_usage_mb"
+)
+
+// defaultMetricAliases lists, per canonical name, the stored metric names to
+// try in order. Different exporters name the same signal differently.
+var defaultMetricAliases = map[string][]string{
+	MetricCPU:     {"cpu_usage", "cpu_usage_percent"},
+	MetricMemory:  {"memory_usage", "memory_usage_percent"},
+	MetricErrors:  {"error_rate", "app_errors_total", "error_count"},
+	MetricLatency: {"response_time", "response_time_p95_ms", "http_latency", "latency_ms"},
+
+	MetricRequests: {"request_rate", "http_requests", "http_requests_total"},
+
+	MetricMemoryMB: {"memory_usage_mb", "memory_working_set_mb"},
+}
+
+// MetricResolver maps canonical metric names to the series actually stored
+// for a service
+type MetricResolver struct {
+	db     *storage.PostgresClient
+	config *core.ConfigStore
+}
+
+func NewMetricResolver(db *storage.PostgresClient, config *core.ConfigStore) *MetricResolver {
+	return &MetricResolver{db: db, config: config}
+}
+
+// Aliases returns the ordered names tried for a canonical metric. Entries in
+// config.metric_aliases replace the built-in list for that metric; unknown
+// canonical names resolve to themselves.
+func (r *MetricResolver) Aliases(canonical string) []string {
+	if cfg := r.config.Get(); cfg != nil {
+		if aliases := cfg.MetricAliases[canonical]; len(aliases) > 0 {
+			return aliases
+		}
+	}
+	if aliases, ok := defaultMetricAliases[canonical]; ok {
+		return aliases
+	}
+	return []string{canonical}
+}
+
+// ResolveSeries returns the samples of the first alias with data in the
+// window, along with the name that matched. It returns nil, "" when none do.
+// Counter series come back as per-minute rates, and metric_transforms are
+// applied.
+func (r *MetricResolver) ResolveSeries(ctx context.Context, serviceName, canonical string, window time.Duration) ([]*storage.Metric, string) {
+	for _, name := range r.Aliases(canonical) {
+		metrics, err := r.db.GetRecentMetrics(ctx, serviceName, name, window)
+		if err != nil {
+			logger.FromContext(ctx).Debug("Metric alias lookup failed",
+				zap.String("service", serviceName),
+				zap.String("metric", name),
+				zap.Error(err),
+			)
+			continue
+		}
+		if len(metrics) > 0 {
+			return r.normalize(serviceName, name, metrics), name
+		}
+	}
+	return nil, ""
+}
+
+// ResolveSeriesMulti resolves several canonical metrics with one query over
+// all of their aliases. The result maps each canonical name with data to its
+// samples, and to the sample count in the window before downsampling; alias
+// precedence and counter handling are the same as ResolveSeries.
+func (r *MetricResolver) ResolveSeriesMulti(ctx context.Context, serviceName string, canonicals []string, window time.Duration) (map[string][]*storage.Metric, map[string]int, error) {
+	var names []string
+	seen := make(map[string]bool)
+	for _, canonical := range canonicals {
+		for _, name := range r.Aliases(canonical) {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+
+	series, totals, err := r.db.GetRecentMetricsMulti(ctx, serviceName, names, window)
+	if err != nil {
+		return nil, nil, err
+	}
+	resolved, resolvedTotals := r.resolveFetched(serviceName, canonicals, series, totals)
+	return resolved, resolvedTotals, nil
+}
+
+// resolveFetched picks, per canonical metric, the first alias with samples
+// among the fetched series
+func (r *MetricResolver) resolveFetched(serviceName string, canonicals []string, series map[string][]*storage.Metric, totals map[string]int) (map[string][]*storage.Metric, map[string]int) {
+	resolved := make(map[string][]*storage.Metric, len(canonicals))
+	resolvedTotals := make(map[string]int, len(canonicals))
+	for _, canonical := range canonicals {
+		for _, name := range r.Aliases(canonical) {
+			if metrics := series[name]; len(metrics) > 0 {
+				normalized := r.normalize(serviceName, name, metrics)
+				resolved[canonical] = normalized
+				// Differencing drops samples; that isn't downsampling
+				resolvedTotals[canonical] = totals[name] - (len(metrics) - len(normalized))
+				break
+			}
+		}
+	}
+	return resolved, resolvedTotals
+}
@@ -0,0 +1,106 @@
+package analyzer
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+)
+
+func newTestResolver(cfg *core.Config) *MetricResolver {
+	if cfg != nil {
+		cfg.ApplyDefaults()
+	}
+	return NewMetricResolver(nil, core.NewConfigStore("", cfg))
+}
+
+func TestMetricResolverAliases(t *testing.T) {
+	configured := newTestResolver(&core.Config{
+		MetricAliases: map[string][]string{
+			MetricLatency: {"http_server_latency_ms", "response_time"},
+		},
+	})
+
+	tests := []struct {
+		name      string
+		resolver  *MetricResolver
+		canonical string
+		want      []string
+	}{
+		{name: "built-in", resolver: newTestResolver(nil), canonical: MetricLatency, want: defaultMetricAliases[MetricLatency]},
+		{name: "configured replaces built-in", resolver: configured, canonical: MetricLatency, want: []string{"http_server_latency_ms", "response_time"}},
+		{name: "other metrics keep built-in", resolver: configured, canonical: MetricCPU, want: defaultMetricAliases[MetricCPU]},
+		{name: "unknown resolves to itself", resolver: configured, canonical: "queue_depth", want: []string{"queue_depth"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.resolver.Aliases(tt.canonical); !slices.Equal(got, tt.want) {
+				t.Errorf("Aliases(%q) = %v, want %v", tt.canonical, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetricResolverAliasPrecedence(t *testing.T) {
+	fetched := map[string][]*storage.Metric{
+		"response_time": seriesOf(time.Minute, 120, 130),
+		"http_latency":  seriesOf(time.Minute, 900, 950, 1000),
+		"latency_ms":    seriesOf(time.Minute, 5),
+		// Only the fallback CPU alias has data
+		"cpu_usage_percent": seriesOf(time.Minute, 40),
+	}
+	totals := map[string]int{"response_time": 2, "http_latency": 3, "latency_ms": 1, "cpu_usage_percent": 1}
+
+	tests := []struct {
+		name      string
+		resolver  *MetricResolver
+		canonical string
+		wantFirst float64
+	}{
+		{name: "first built-in alias with data wins", resolver: newTestResolver(nil), canonical: MetricLatency, wantFirst: 120},
+		{
+			name: "configured order wins",
+			resolver: newTestResolver(&core.Config{MetricAliases: map[string][]string{
+				MetricLatency: {"latency_ms", "http_latency", "response_time"},
+			}}),
+			canonical: MetricLatency,
+			wantFirst: 5,
+		},
+		{
+			name: "aliases without data are skipped",
+			resolver: newTestResolver(&core.Config{MetricAliases: map[string][]string{
+				MetricLatency: {"http_server_latency_ms", "http_latency"},
+			}}),
+			canonical: MetricLatency,
+			wantFirst: 900,
+		},
+		{name: "fallback alias", resolver: newTestResolver(nil), canonical: MetricCPU, wantFirst: 40},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, _ := tt.resolver.resolveFetched("checkout", []string{tt.canonical}, fetched, totals)
+			metrics := resolved[tt.canonical]
+			if len(metrics) == 0 {
+				t.Fatalf("%s did not resolve", tt.canonical)
+			}
+			if metrics[0].MetricValue != tt.wantFirst {
+				t.Errorf("resolved series starts at %v, want %v", metrics[0].MetricValue, tt.wantFirst)
+			}
+		})
+	}
+}
+
+func TestMetricResolverUnresolved(t *testing.T) {
+	resolved, totals := newTestResolver(nil).resolveFetched("checkout", []string{MetricMemory}, map[string][]*storage.Metric{}, map[string]int{})
+
+	if _, ok := resolved[MetricMemory]; ok {
+		t.Errorf("resolved %s without any series", MetricMemory)
+	}
+	if len(totals) != 0 {
+		t.Errorf("totals = %v, want none", totals)
+	}
+}
@@ -0,0 +1,68 @@
+package analyzer
+
+import (
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+// Risk levels assigned to a diagnosis
+const (
+	RiskCritical = "CRITICAL"
+	RiskHigh     = "HIGH"
+	RiskMedium   = "MEDIUM"
+	RiskLow      = "LOW"
+	RiskNormal   = "NORMAL"
+)
+
+// RiskClassifier maps health, stress and confidence onto risk levels and
+// severities using the operator-tunable config.RiskThresholds
+type RiskClassifier struct {
+	config *core.ConfigStore
+}
+
+func NewRiskClassifier(config *core.ConfigStore) *RiskClassifier {
+	return &RiskClassifier{config: config}
+}
+
+// Thresholds returns the effective cutoffs, falling back to the defaults
+func (rc *RiskClassifier) Thresholds() core.RiskThresholds {
+	if rc == nil || rc.config.Get() == nil {
+		return core.DefaultRiskThresholds()
+	}
+	cfg := rc.config.Get()
+	return cfg.RiskThresholds.WithDefaults()
+}
+
+// RiskLevel combines the primary detection's severity with the overall
+// health score and system stress. The worst matching level wins.
+func (rc *RiskClassifier) RiskLevel(severity string, health, stress float64) string {
+	t := rc.Thresholds()
+
+	switch {
+	case severity == SeverityCritical || health < t.CriticalHealth:
+		return RiskCritical
+	case severity == SeverityHigh || health < t.HighHealth || stress > t.HighStress:
+		return RiskHigh
+	case severity == SeverityMedium || health < t.MediumHealth:
+		return RiskMedium
+	case severity == SeverityLow:
+		return RiskLow
+	default:
+		return RiskNormal
+	}
+}
+
+// SeverityForConfidence derives a severity from detection confidence
+func (rc *RiskClassifier) SeverityForConfidence(confidence float64) string {
+	t := rc.Thresholds()
+
+	switch {
+	case confidence >= t.CriticalConfidence:
+		return SeverityCritical
+	case confidence >= t.HighConfidence:
+		return SeverityHigh
+	case confidence >= t.MediumConfidence:
+		return SeverityMedium
+	default:
+		return SeverityLow
+	}
+}
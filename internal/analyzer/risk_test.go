@@ -0,0 +1,73 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+func newTestClassifier(thresholds core.RiskThresholds) *RiskClassifier {
+	cfg := &core.Config{RiskThresholds: thresholds}
+	cfg.ApplyDefaults()
+	return NewRiskClassifier(core.NewConfigStore("", cfg))
+}
+
+func TestRiskLevelDefaults(t *testing.T) {
+	rc := newTestClassifier(core.RiskThresholds{})
+
+	tests := []struct {
+		severity       string
+		health, stress float64
+		want           string
+	}{
+		{SeverityNone, 95, 10, RiskNormal},
+		{SeverityLow, 95, 10, RiskLow},
+		{SeverityNone, 65, 10, RiskMedium},
+		{SeverityMedium, 95, 10, RiskMedium},
+		{SeverityNone, 45, 10, RiskHigh},
+		{SeverityNone, 95, 85, RiskHigh},
+		{SeverityHigh, 95, 10, RiskHigh},
+		{SeverityNone, 25, 10, RiskCritical},
+		{SeverityCritical, 95, 10, RiskCritical},
+	}
+	for _, tt := range tests {
+		if got := rc.RiskLevel(tt.severity, tt.health, tt.stress); got != tt.want {
+			t.Errorf("RiskLevel(%q, health %v, stress %v) = %s, want %s", tt.severity, tt.health, tt.stress, got, tt.want)
+		}
+	}
+}
+
+func TestRiskLevelLoweredThreshold(t *testing.T) {
+	// health 28 is CRITICAL under the default cutoff of 30
+	if got := newTestClassifier(core.RiskThresholds{}).RiskLevel(SeverityNone, 28, 10); got != RiskCritical {
+		t.Fatalf("default RiskLevel(health 28) = %s, want %s", got, RiskCritical)
+	}
+	lowered := newTestClassifier(core.RiskThresholds{CriticalHealth: 25})
+	if got := lowered.RiskLevel(SeverityNone, 28, 10); got != RiskHigh {
+		t.Errorf("RiskLevel(health 28) with critical_health 25 = %s, want %s", got, RiskHigh)
+	}
+}
+
+func TestSeverityForConfidence(t *testing.T) {
+	rc := newTestClassifier(core.RiskThresholds{})
+	tests := []struct {
+		confidence float64
+		want       string
+	}{
+		{40, SeverityLow},
+		{50, SeverityMedium},
+		{70, SeverityHigh},
+		{80, SeverityHigh},
+		{85, SeverityCritical},
+	}
+	for _, tt := range tests {
+		if got := rc.SeverityForConfidence(tt.confidence); got != tt.want {
+			t.Errorf("SeverityForConfidence(%v) = %s, want %s", tt.confidence, got, tt.want)
+		}
+	}
+
+	lowered := newTestClassifier(core.RiskThresholds{CriticalConfidence: 75})
+	if got := lowered.SeverityForConfidence(80); got != SeverityCritical {
+		t.Errorf("SeverityForConfidence(80) with critical_confidence 75 = %s, want %s", got, SeverityCritical)
+	}
+}
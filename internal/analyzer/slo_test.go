@@ -0,0 +1,54 @@
+package analyzer
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSLOSeverity(t *testing.T) {
+	tests := []struct {
+		p99, slo float64
+		want     string
+	}{
+		{150, 200, SeverityNone},
+		{200, 200, SeverityNone},
+		{250, 200, SeverityLow},
+		{300, 200, SeverityMedium},
+		{800, 200, SeverityHigh},
+		{1000, 200, SeverityCritical},
+		{4000, 200, SeverityCritical},
+	}
+	for _, tt := range tests {
+		if got := sloSeverity(tt.p99 / tt.slo); got != tt.want {
+			t.Errorf("sloSeverity(%v/%v) = %s, want %s", tt.p99, tt.slo, got, tt.want)
+		}
+	}
+}
+
+func TestExternalLatencySignal(t *testing.T) {
+	tests := []struct {
+		name      string
+		p99, slo  float64
+		wantScore float64
+		wantRatio float64
+		strong    bool
+	}{
+		{"within SLO", 180, 200, 0, 0.9, false},
+		{"slightly over SLO", 250, 200, 0.25 / 4 * 100 * 0.35, 1.25, false},
+		{"well over SLO", 800, 200, 3.0 / 4 * 100 * 0.35, 4, true},
+		{"score capped", 4000, 200, 35, 20, true},
+		// Without an SLO, 800ms is nowhere near the fixed cutoffs
+		{"no SLO, fast", 800, 0, 0, 0, false},
+		{"no SLO, over 3000ms", 4000, 0, 10 * 0.35, 0, false},
+		{"no SLO, over 5000ms", 8000, 0, 50 * 0.35, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, ratio, strong := externalLatencySignal(tt.p99, tt.slo)
+			if math.Abs(score-tt.wantScore) > 1e-9 || math.Abs(ratio-tt.wantRatio) > 1e-9 || strong != tt.strong {
+				t.Errorf("externalLatencySignal(%v, %v) = %v, %v, %v; want %v, %v, %v",
+					tt.p99, tt.slo, score, ratio, strong, tt.wantScore, tt.wantRatio, tt.strong)
+			}
+		})
+	}
+}
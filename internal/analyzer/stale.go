@@ -0,0 +1,202 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/notify"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultStaleAfter = 3 * time.Minute
+
+	// Services silent for longer than this are treated as decommissioned
+	// rather than stale, so old registry entries don't alert forever
+	staleForgetAfter = 24 * time.Hour
+
+	minStaleCheckInterval = 10 * time.Second
+)
+
+// StalenessMonitor is a dead-man's switch over the services registry. The
+// regular detectors only see data that arrived, so a service that stops
+// reporting entirely is flagged here instead.
+type StalenessMonitor struct {
+	db       *storage.PostgresClient
+	notifier notify.Notifier
+	risk     *RiskClassifier
+	config   *core.ConfigStore
+
+	mu    sync.RWMutex
+	stale map[string]*Detection
+}
+
+func NewStalenessMonitor(db *storage.PostgresClient, notifier notify.Notifier, config *core.ConfigStore) *StalenessMonitor {
+	return &StalenessMonitor{
+		db:       db,
+		notifier: notifier,
+		risk:     NewRiskClassifier(config),
+		config:   config,
+		stale:    make(map[string]*Detection),
+	}
+}
+
+// StaleAfter returns how long a service may stay silent before it is stale
+func (m *StalenessMonitor) StaleAfter() time.Duration {
+	cfg := m.config.Get()
+	if cfg == nil {
+		return defaultStaleAfter
+	}
+	d, err := time.ParseDuration(cfg.Analyzer.StaleAfter)
+	if err != nil || d <= 0 {
+		return defaultStaleAfter
+	}
+	return d
+}
+
+// checkInterval is how often Run checks the registry
+func (m *StalenessMonitor) checkInterval() time.Duration {
+	interval := m.StaleAfter() / 3
+	if interval < minStaleCheckInterval {
+		interval = minStaleCheckInterval
+	}
+	return interval
+}
+
+// Run checks the registry until the context is cancelled. The interval is
+// re-read after every check so a config reload takes effect.
+func (m *StalenessMonitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.checkInterval())
+	defer ticker.Stop()
+
+	for {
+		if _, err := m.Check(ctx); err != nil && ctx.Err() == nil {
+			logger.FromContext(ctx).Warn("Staleness check failed", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ticker.Reset(m.checkInterval())
+		}
+	}
+}
+
+// Check compares every recently-active service's last_seen with StaleAfter,
+// notifies on services that just went dark and returns the stale set. Only
+// the registry is read, which holds services but not pods, so pods replaced
+// by a rollout never count as stale.
+func (m *StalenessMonitor) Check(ctx context.Context) ([]*Detection, error) {
+	records, err := m.db.GetServiceRecords(ctx, staleForgetAfter)
+	if err != nil {
+		return nil, err
+	}
+	return m.update(ctx, records, time.Now()), nil
+}
+
+// update replaces the stale set with the records silent for longer than
+// StaleAfter at now, notifying on the newly stale ones
+func (m *StalenessMonitor) update(ctx context.Context, records []*storage.ServiceRecord, now time.Time) []*Detection {
+	staleAfter := m.StaleAfter()
+
+	current := make(map[string]*Detection)
+	for _, r := range records {
+		silent := now.Sub(r.LastSeen)
+		if silent > staleAfter {
+			current[r.Name] = m.detection(r, silent, staleAfter, now)
+		}
+	}
+
+	m.mu.RLock()
+	previous := m.stale
+	m.mu.RUnlock()
+
+	// Notify before publishing so the suppressed tag lands on the stored detection
+	for name, d := range current {
+		if _, ok := previous[name]; !ok {
+			m.notify(ctx, d)
+		}
+	}
+
+	m.mu.Lock()
+	m.stale = current
+	m.mu.Unlock()
+
+	for name := range previous {
+		if _, ok := current[name]; !ok {
+			logger.FromContext(ctx).Info("📡 Service reporting metrics again", zap.String("service", name))
+		}
+	}
+
+	return m.Stale()
+}
+
+// Stale returns the services flagged by the last check, ordered by name
+func (m *StalenessMonitor) Stale() []*Detection {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	out := make([]*Detection, 0, len(m.stale))
+	for _, d := range m.stale {
+		out = append(out, d)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ServiceName < out[j].ServiceName })
+	return out
+}
+
+// detection starts at 70% confidence once the service crosses StaleAfter and
+// gains 10 points per further StaleAfter of silence
+func (m *StalenessMonitor) detection(r *storage.ServiceRecord, silent, staleAfter time.Duration, now time.Time) *Detection {
+	confidence := math.Min(70+10*(silent.Seconds()/staleAfter.Seconds()-1), 100)
+
+	return &Detection{
+		Type:        DetectionServiceStale,
+		ServiceName: r.Name,
+		Detected:    true,
+		Confidence:  confidence,
+		Timestamp:   now,
+		Severity:    m.risk.SeverityForConfidence(confidence),
+		Evidence: map[string]interface{}{
+			"last_seen":   r.LastSeen.Format(time.RFC3339),
+			"silent_for":  silent.Round(time.Second).String(),
+			"stale_after": staleAfter.String(),
+		},
+		Recommendation: fmt.Sprintf("No metrics from %s for %s. Check that its pods are running and that Prometheus can still scrape them.",
+			r.Name, silent.Round(time.Second)),
+	}
+}
+
+func (m *StalenessMonitor) notify(ctx context.Context, d *Detection) {
+	logger.FromContext(ctx).Warn("🔇 Service stopped reporting metrics",
+		zap.String("service", d.ServiceName),
+		zap.Any("last_seen", d.Evidence["last_seen"]),
+	)
+
+	if m.notifier == nil {
+		return
+	}
+
+	err := m.notifier.Notify(ctx, notify.Notification{
+		Service:   d.ServiceName,
+		Severity:  d.Severity,
+		Title:     fmt.Sprintf("%s stopped reporting metrics", d.ServiceName),
+		Message:   d.Recommendation,
+		Details:   d.Evidence,
+		Timestamp: d.Timestamp,
+	})
+	switch {
+	case errors.Is(err, notify.ErrSuppressed):
+		d.Evidence["suppressed"] = true
+	case err != nil:
+		logger.FromContext(ctx).Warn("Staleness notification failed", zap.String("service", d.ServiceName), zap.Error(err))
+	}
+}
@@ -0,0 +1,149 @@
+package analyzer
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/notify"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+)
+
+// recordingNotifier keeps every notification it is sent
+type recordingNotifier struct {
+	mu   sync.Mutex
+	sent []notify.Notification
+}
+
+func (r *recordingNotifier) Notify(_ context.Context, n notify.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sent = append(r.sent, n)
+	return nil
+}
+
+func (r *recordingNotifier) notifications() []notify.Notification {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]notify.Notification(nil), r.sent...)
+}
+
+func newTestStalenessMonitor(staleAfter string) (*StalenessMonitor, *recordingNotifier) {
+	cfg := &core.Config{}
+	cfg.Analyzer.StaleAfter = staleAfter
+	cfg.ApplyDefaults()
+	notifier := &recordingNotifier{}
+	return NewStalenessMonitor(nil, notifier, core.NewConfigStore("", cfg)), notifier
+}
+
+func TestStalenessMonitorFlagsSilentServices(t *testing.T) {
+	m, notifier := newTestStalenessMonitor("3m")
+	now := testEpoch
+	records := []*storage.ServiceRecord{
+		{Name: "checkout", LastSeen: now.Add(-time.Minute)},
+		{Name: "payments", LastSeen: now.Add(-6 * time.Minute)},
+	}
+
+	stale := m.update(context.Background(), records, now)
+	if len(stale) != 1 || stale[0].ServiceName != "payments" {
+		t.Fatalf("stale = %v, want only payments", serviceNames(stale))
+	}
+	d := stale[0]
+	if d.Type != DetectionServiceStale || !d.Detected {
+		t.Errorf("detection = %s detected=%v, want a detected %s", d.Type, d.Detected, DetectionServiceStale)
+	}
+	// twice StaleAfter of silence: 70 + 10 points
+	if d.Confidence != 80 {
+		t.Errorf("confidence after 6m silent = %v, want 80", d.Confidence)
+	}
+	if sent := notifier.notifications(); len(sent) != 1 || sent[0].Service != "payments" {
+		t.Fatalf("notifications = %+v, want one for payments", sent)
+	}
+
+	// Still silent a minute later: still stale, not notified again
+	stale = m.update(context.Background(), records, now.Add(time.Minute))
+	if len(stale) != 1 {
+		t.Fatalf("stale after a minute = %v, want payments", serviceNames(stale))
+	}
+	if sent := notifier.notifications(); len(sent) != 1 {
+		t.Errorf("notified %d times for one outage, want once", len(sent))
+	}
+
+	// Reporting again clears it, and a later outage notifies afresh
+	records[1].LastSeen = now.Add(2 * time.Minute)
+	if stale := m.update(context.Background(), records, now.Add(2*time.Minute)); len(stale) != 0 {
+		t.Fatalf("stale after recovery = %v, want none", serviceNames(stale))
+	}
+	if stale := m.update(context.Background(), records, now.Add(10*time.Minute)); len(stale) != 2 {
+		t.Fatalf("stale after both go quiet = %v, want checkout and payments", serviceNames(stale))
+	}
+	if sent := notifier.notifications(); len(sent) != 3 {
+		t.Errorf("notifications after the second outage = %d, want 3", len(sent))
+	}
+}
+
+func TestStalenessMonitorIgnoresReplacedPods(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	pod := storagetest.Service(t, db)
+	ctx := context.Background()
+
+	// Both stop reporting ten minutes ago; the pod was replaced by a rollout
+	end := time.Now().Add(-10 * time.Minute).Truncate(time.Second)
+	storagetest.Seed(t, db, storagetest.Series(service, "cpu_usage", end, 15*time.Second, 40, 42))
+	storagetest.Seed(t, db, storagetest.Series(pod, "pod_status", end, 15*time.Second, 1, 1))
+	storagetest.Seed(t, db, storagetest.Series(pod, "pod_restarts", end, 15*time.Second, 0, 0))
+
+	cfg := &core.Config{}
+	cfg.Analyzer.StaleAfter = "3m"
+	cfg.ApplyDefaults()
+	notifier := &recordingNotifier{}
+	m := NewStalenessMonitor(db, notifier, core.NewConfigStore("", cfg))
+
+	stale, err := m.Check(ctx)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	names := serviceNames(stale)
+	if !slices.Contains(names, service) {
+		t.Errorf("stale = %v, want it to include %s", names, service)
+	}
+	if slices.Contains(names, pod) {
+		t.Errorf("stale = %v, want no detection for the replaced pod %s", names, pod)
+	}
+	for _, n := range notifier.notifications() {
+		if n.Service == pod {
+			t.Errorf("notified for the replaced pod %s", pod)
+		}
+	}
+}
+
+func TestStaleAfter(t *testing.T) {
+	tests := []struct {
+		configured string
+		want       time.Duration
+	}{
+		{"", defaultStaleAfter},
+		{"bogus", defaultStaleAfter},
+		{"-1m", defaultStaleAfter},
+		{"10m", 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		m, _ := newTestStalenessMonitor(tt.configured)
+		if got := m.StaleAfter(); got != tt.want {
+			t.Errorf("StaleAfter with %q = %v, want %v", tt.configured, got, tt.want)
+		}
+	}
+}
+
+func serviceNames(detections []*Detection) []string {
+	names := make([]string, len(detections))
+	for i, d := range detections {
+		names[i] = d.ServiceName
+	}
+	return names
+}
@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"context"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+)
+
+func TestRenormalizeSignals(t *testing.T) {
+	// Error signals worth 35% of the weight scaled up to the full weight
+	signals := map[string]float64{"external_pattern": 14, "error_spikes": 7, "dependency_unhealthy": 20}
+	renormalizeSignals(signals, []string{"external_pattern", "error_spikes", "latency"}, 1/0.35)
+
+	if math.Abs(signals["external_pattern"]-40) > 1e-9 || math.Abs(signals["error_spikes"]-20) > 1e-9 {
+		t.Errorf("rescaled signals = %v, want external_pattern 40 and error_spikes 20", signals)
+	}
+	if signals["dependency_unhealthy"] != 20 {
+		t.Errorf("unlisted signal = %v, want it unchanged", signals["dependency_unhealthy"])
+	}
+	if _, ok := signals["latency"]; ok {
+		t.Error("renormalizing added a signal that wasn't scored")
+	}
+}
+
+func TestServiceFeaturesHasMetric(t *testing.T) {
+	f := &ServiceFeatures{MissingMetrics: []string{MetricLatency}}
+	if f.HasMetric(MetricLatency) {
+		t.Error("HasMetric(latency) = true for a missing metric")
+	}
+	if !f.HasMetric(MetricCPU) {
+		t.Error("HasMetric(cpu) = false for a present metric")
+	}
+}
+
+func TestEnsembleCarriesDataSufficiency(t *testing.T) {
+	cfg := &core.Config{}
+	cfg.ApplyDefaults()
+	store := core.NewConfigStore("", cfg)
+	ea := NewEnsembleAnalyzer(NewUltimateAnalyzer(nil, store), store)
+
+	classic := &Detection{Type: DetectionExternalFailure, Confidence: 40}
+	enhanced := &Detection{Type: DetectionExternalFailure, Confidence: 60, Detected: true, DataSufficiency: DataSufficiencyPartial}
+	if got := ea.merge(EnsembleMax, classic, enhanced).Merged.DataSufficiency; got != DataSufficiencyPartial {
+		t.Errorf("merged data sufficiency = %q, want partial", got)
+	}
+	if got := ea.merge(EnsembleMax, classic, nil).Merged.DataSufficiency; got != "" {
+		t.Errorf("merged data sufficiency without an enhanced detection = %q, want unset", got)
+	}
+}
+
+func TestDetectorsWithOnlyResourceMetrics(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+
+	end := time.Now().Add(-30 * time.Second)
+	steady := generate(40, func(i int) float64 { return 50 + float64(i%3) })
+	storagetest.Seed(t, db, storagetest.Series(service, MetricCPU, end, 30*time.Second, steady...))
+	storagetest.Seed(t, db, storagetest.Series(service, MetricMemory, end, 30*time.Second, steady...))
+
+	cfg := &core.Config{}
+	cfg.ApplyDefaults()
+	store := core.NewConfigStore("", cfg)
+	ed := NewEnhancedDetector(NewFeatureExtractor(db, store), store)
+
+	features, err := ed.featureExtractor.ExtractFeatures(ctx, service, 20*time.Minute)
+	if err != nil {
+		t.Fatalf("ExtractFeatures: %v", err)
+	}
+	if want := []string{MetricErrors, MetricLatency}; !slices.Equal(features.MissingMetrics, want) {
+		t.Errorf("missing metrics = %v, want %v", features.MissingMetrics, want)
+	}
+
+	for name, detect := range map[string]func(context.Context, string) (*Detection, error){
+		"external_failure": ed.DetectExternalFailureEnhanced,
+		"cascade_failure":  ed.DetectCascadeFailureEnhanced,
+	} {
+		d, err := detect(ctx, service)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if d.DataSufficiency != DataSufficiencyPartial {
+			t.Errorf("%s data sufficiency = %q, want partial", name, d.DataSufficiency)
+		}
+		if missing, _ := d.Evidence["missing_metrics"].([]string); !slices.Contains(missing, MetricLatency) {
+			t.Errorf("%s evidence missing_metrics = %v, want response_time listed", name, d.Evidence["missing_metrics"])
+		}
+	}
+
+	// With latency reported the same detectors score on full data
+	storagetest.Seed(t, db, storagetest.Series(service, MetricLatency, end, 30*time.Second, generate(40, func(int) float64 { return 120 })...))
+	d, err := ed.DetectExternalFailureEnhanced(ctx, service)
+	if err != nil {
+		t.Fatalf("DetectExternalFailureEnhanced: %v", err)
+	}
+	if d.DataSufficiency != DataSufficiencyFull {
+		t.Errorf("data sufficiency with latency = %q, want full", d.DataSufficiency)
+	}
+}
@@ -0,0 +1,95 @@
+package analyzer
+
+import (
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+)
+
+// AppliedTransform is a metric_transforms entry as applied to one service's
+// series, reported in evidence so converted values can be traced back
+type AppliedTransform struct {
+	Metric string  `json:"metric"` // stored name
+	Scale  float64 `json:"scale"`
+	Offset float64 `json:"offset,omitempty"`
+	Unit   string  `json:"unit,omitempty"`
+
+	// MemoryLimitMB is set when values were converted to a percentage of
+	// the service's memory limit
+	MemoryLimitMB float64 `json:"memory_limit_mb,omitempty"`
+}
+
+// TransformFor returns the conversion config.metric_transforms applies to a
+// stored metric of the service, or nil when it is read as stored
+func (r *MetricResolver) TransformFor(serviceName, name string) *AppliedTransform {
+	cfg := r.config.Get()
+	if cfg == nil {
+		return nil
+	}
+	t, ok := cfg.MetricTransforms[name]
+	if !ok {
+		return nil
+	}
+
+	applied := &AppliedTransform{
+		Metric: name,
+		Scale:  t.ScaleOrDefault(),
+		Offset: t.Offset,
+		Unit:   t.Unit,
+	}
+	if t.PercentOfMemoryLimit {
+		applied.MemoryLimitMB = cfg.MemoryLimitFor(serviceName)
+		if applied.MemoryLimitMB <= 0 {
+			return nil
+		}
+	}
+	return applied
+}
+
+// Apply returns the value converted
+func (t *AppliedTransform) Apply(value float64) float64 {
+	value = value*t.Scale + t.Offset
+	if t.MemoryLimitMB > 0 {
+		value = value / t.MemoryLimitMB * 100
+	}
+	return value
+}
+
+// transformSeries returns converted copies of the samples, leaving the
+// originals untouched
+func transformSeries(metrics []*storage.Metric, t *AppliedTransform) []*storage.Metric {
+	converted := make([]*storage.Metric, 0, len(metrics))
+	for _, m := range metrics {
+		c := *m
+		c.MetricValue = t.Apply(m.MetricValue)
+		if m.MetricMin != nil {
+			low := t.Apply(*m.MetricMin)
+			c.MetricMin = &low
+		}
+		if m.MetricMax != nil {
+			high := t.Apply(*m.MetricMax)
+			c.MetricMax = &high
+		}
+		converted = append(converted, &c)
+	}
+	return converted
+}
+
+// seriesTransforms lists the transforms applied to the resolved series, in
+// the order of canonicals
+func (r *MetricResolver) seriesTransforms(serviceName string, series map[string][]*storage.Metric, canonicals []string) []AppliedTransform {
+	var applied []AppliedTransform
+	for _, canonical := range canonicals {
+		if s := series[canonical]; len(s) > 0 {
+			if t := r.TransformFor(serviceName, s[0].MetricName); t != nil {
+				applied = append(applied, *t)
+			}
+		}
+	}
+	return applied
+}
+
+// addTransformEvidence reports the unit conversions behind the features
+func addTransformEvidence(evidence map[string]interface{}, f *ServiceFeatures) {
+	if len(f.Transforms) > 0 {
+		evidence["metric_transforms"] = f.Transforms
+	}
+}
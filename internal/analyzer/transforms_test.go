@@ -0,0 +1,133 @@
+package analyzer
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+)
+
+const mib = 1 << 20
+
+// bytesToPercentConfig converts memory_usage, exported in bytes, to a
+// percentage of checkout's 512 MiB limit
+func bytesToPercentConfig(service string) *core.Config {
+	scale := 1.0 / mib
+	cfg := &core.Config{
+		MetricTransforms: map[string]core.MetricTransform{
+			"memory_usage": {Scale: &scale, Unit: "percent", PercentOfMemoryLimit: true},
+		},
+		Resources: map[string]core.ServiceResources{service: {MemoryLimitMB: 512}},
+	}
+	cfg.ApplyDefaults()
+	return cfg
+}
+
+func TestTransformBytesToPercentOfLimit(t *testing.T) {
+	resolver := NewMetricResolver(nil, core.NewConfigStore("", bytesToPercentConfig("checkout")))
+
+	transform := resolver.TransformFor("checkout", "memory_usage")
+	if transform == nil {
+		t.Fatal("no transform for memory_usage")
+	}
+	if transform.MemoryLimitMB != 512 || transform.Unit != "percent" || transform.Scale != 1.0/mib {
+		t.Errorf("transform = %+v, want bytes to percent of 512 MiB", transform)
+	}
+
+	// 128, 256 and 460.8 MiB of a 512 MiB limit
+	series := seriesOf(time.Minute, 128*mib, 256*mib, 460.8*mib)
+	low, high := float64(100*mib), float64(500*mib)
+	series[1].MetricMin, series[1].MetricMax = &low, &high
+
+	converted := transformSeries(series, transform)
+
+	for i, want := range []float64{25, 50, 90} {
+		if math.Abs(converted[i].MetricValue-want) > 1e-9 {
+			t.Errorf("sample %d = %v%%, want %v%%", i, converted[i].MetricValue, want)
+		}
+	}
+	if math.Abs(*converted[1].MetricMin-19.53125) > 1e-9 || math.Abs(*converted[1].MetricMax-97.65625) > 1e-9 {
+		t.Errorf("min/max = %v/%v, want both converted", *converted[1].MetricMin, *converted[1].MetricMax)
+	}
+	if series[0].MetricValue != 128*mib || *series[1].MetricMin != 100*mib {
+		t.Error("the stored samples were modified")
+	}
+}
+
+func TestTransformFor(t *testing.T) {
+	scale := 0.001
+	cfg := bytesToPercentConfig("checkout")
+	cfg.MetricTransforms["response_time"] = core.MetricTransform{Scale: &scale, Offset: 2, Unit: "s"}
+	resolver := NewMetricResolver(nil, core.NewConfigStore("", cfg))
+
+	if got := resolver.TransformFor("search", "memory_usage"); got != nil {
+		t.Errorf("service without a memory limit: transform = %+v, want none", got)
+	}
+	if got := resolver.TransformFor("checkout", "cpu_usage"); got != nil {
+		t.Errorf("metric without a transform: transform = %+v, want none", got)
+	}
+
+	latency := resolver.TransformFor("search", "response_time")
+	if latency == nil || latency.MemoryLimitMB != 0 {
+		t.Fatalf("transform = %+v, want scale and offset only", latency)
+	}
+	if got := latency.Apply(1500); got != 3.5 {
+		t.Errorf("Apply(1500) = %v, want 1500 * 0.001 + 2", got)
+	}
+}
+
+func TestTransformEvidence(t *testing.T) {
+	features := &ServiceFeatures{
+		CPUMean:    95,
+		MemoryMean: 90,
+		Transforms: []AppliedTransform{{Metric: "memory_usage", Scale: 1.0 / mib, Unit: "percent", MemoryLimitMB: 512}},
+	}
+
+	evidence := decodeEvidence(t, newTestDetector(&core.Config{}).resourceExhaustion("checkout", features))
+
+	transforms, ok := evidence["metric_transforms"].([]interface{})
+	if !ok || len(transforms) != 1 {
+		t.Fatalf("metric_transforms = %v, want the applied transform", evidence["metric_transforms"])
+	}
+	applied := transforms[0].(map[string]interface{})
+	if applied["metric"] != "memory_usage" || applied["memory_limit_mb"] != 512.0 || applied["unit"] != "percent" {
+		t.Errorf("transform evidence = %v", applied)
+	}
+
+	if evidence := decodeEvidence(t, newTestDetector(&core.Config{}).resourceExhaustion("checkout", &ServiceFeatures{CPUMean: 95})); evidence["metric_transforms"] != nil {
+		t.Error("metric_transforms reported without any transform applied")
+	}
+}
+
+func TestExtractedMemoryConvertedFromBytes(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+
+	// Memory in bytes: 128, 256 and 384 MiB of the 512 MiB limit
+	end := time.Now().Add(-30 * time.Second)
+	storagetest.Seed(t, db, storagetest.Series(service, "memory_usage", end, time.Minute,
+		generate(15, func(i int) float64 { return float64((i%3+1)*128) * mib })...))
+
+	features, err := NewFeatureExtractor(db, core.NewConfigStore("", bytesToPercentConfig(service))).ExtractFeatures(context.Background(), service, 20*time.Minute)
+	if err != nil {
+		t.Fatalf("ExtractFeatures: %v", err)
+	}
+
+	if math.Abs(features.MemoryMean-50) > 1e-6 || math.Abs(features.MemoryMax-75) > 1e-6 {
+		t.Errorf("memory mean/max = %v/%v%%, want 50/75%% of the limit", features.MemoryMean, features.MemoryMax)
+	}
+	if len(features.Transforms) != 1 || features.Transforms[0].Metric != "memory_usage" || features.Transforms[0].MemoryLimitMB != 512 {
+		t.Errorf("transforms = %+v, want the memory conversion recorded", features.Transforms)
+	}
+
+	stored, err := db.GetRecentMetrics(context.Background(), service, "memory_usage", time.Hour)
+	if err != nil {
+		t.Fatalf("GetRecentMetrics: %v", err)
+	}
+	if len(stored) == 0 || stored[0].MetricValue < mib {
+		t.Errorf("stored samples = %v, want the original bytes kept", stored)
+	}
+}
@@ -0,0 +1,192 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/notify"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Transition kinds, derived from the old and new severity
+const (
+	TransitionDegraded  = "degraded"  // healthy -> unhealthy
+	TransitionEscalated = "escalated" // unhealthy -> more severe
+	TransitionImproved  = "improved"  // unhealthy -> less severe
+	TransitionRecovered = "recovered" // unhealthy -> healthy
+)
+
+// TransitionTracker remembers each service's last diagnosed severity and
+// fires the transition webhooks only when it changes. It is the
+// edge-triggered complement to the notifier, which sees every diagnosis.
+// Changes are damped: a new severity must hold for analyzer.transition_dwell
+// diagnoses, and leaving a severity needs the confidence to drop
+// analyzer.transition_hysteresis points below its cutoff.
+type TransitionTracker struct {
+	db       *storage.PostgresClient
+	notifier notify.Notifier
+	config   *core.ConfigStore
+	risk     *RiskClassifier
+
+	mu       sync.Mutex
+	severity map[string]string
+	pending  map[string]*dwell // service -> uncommitted severity change
+}
+
+// NewTransitionTracker builds a tracker. A nil notifier only records
+// transitions.
+func NewTransitionTracker(db *storage.PostgresClient, notifier notify.Notifier, config *core.ConfigStore) *TransitionTracker {
+	return &TransitionTracker{
+		db:       db,
+		notifier: notifier,
+		config:   config,
+		risk:     NewRiskClassifier(config),
+		severity: make(map[string]string),
+		pending:  make(map[string]*dwell),
+	}
+}
+
+// Load restores each service's last severity so a restart doesn't report
+// every unhealthy service as newly degraded
+func (t *TransitionTracker) Load(ctx context.Context) error {
+	severities, err := t.db.GetCurrentSeverities(ctx)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for service, severity := range severities {
+		t.severity[service] = severity
+	}
+	return nil
+}
+
+// Observe compares a diagnosis with the service's previous severity and
+// records and announces the change, if any, once it has held long enough.
+// Services with no history start out healthy. Replays over historical data
+// are ignored.
+func (t *TransitionTracker) Observe(ctx context.Context, diag *UltimateDiagnosis) *storage.StatusTransition {
+	if diag.PrimaryDetection == nil || storage.HasAsOf(ctx) {
+		return nil
+	}
+
+	transition := t.advance(diag)
+	if transition == nil {
+		return nil
+	}
+	if err := t.db.SaveStatusTransition(ctx, transition); err != nil {
+		logger.FromContext(ctx).Warn("Failed to persist status transition", zap.String("service", diag.ServiceName), zap.Error(err))
+	}
+
+	kind := TransitionKind(transition.OldSeverity, transition.NewSeverity)
+	logger.FromContext(ctx).Info("🔀 Service severity changed",
+		zap.String("service", diag.ServiceName),
+		zap.String("transition", kind),
+		zap.String("old_severity", transition.OldSeverity),
+		zap.String("new_severity", transition.NewSeverity),
+	)
+	t.notify(ctx, transition, kind, diag)
+
+	return transition
+}
+
+// advance moves the service's severity on by one diagnosis and returns the
+// transition once a change has held for the dwell, or nil
+func (t *TransitionTracker) advance(diag *UltimateDiagnosis) *storage.StatusTransition {
+	primary := diag.PrimaryDetection
+	newSeverity := primary.Severity
+	if !primary.Detected || primary.Type == DetectionHealthy || newSeverity == "" {
+		newSeverity = SeverityNone
+	}
+
+	cfg := t.config.Get()
+
+	t.mu.Lock()
+	oldSeverity, ok := t.severity[diag.ServiceName]
+	if !ok {
+		oldSeverity = SeverityNone
+	}
+	newSeverity = holdSeverity(t.risk, oldSeverity, newSeverity, diag, transitionHysteresis(cfg))
+	if oldSeverity == newSeverity {
+		delete(t.pending, diag.ServiceName)
+		t.mu.Unlock()
+		return nil
+	}
+	pending, ok := t.pending[diag.ServiceName]
+	if !ok {
+		pending = &dwell{}
+		t.pending[diag.ServiceName] = pending
+	}
+	if pending.observe(newSeverity, diag.PredictionID) < transitionDwell(cfg) {
+		t.mu.Unlock()
+		return nil
+	}
+	delete(t.pending, diag.ServiceName)
+	t.severity[diag.ServiceName] = newSeverity
+	t.mu.Unlock()
+
+	return &storage.StatusTransition{
+		ServiceName:    diag.ServiceName,
+		OldSeverity:    oldSeverity,
+		NewSeverity:    newSeverity,
+		ProblemType:    string(primary.Type),
+		HealthScore:    diag.HealthScore,
+		PredictionID:   diag.PredictionID,
+		TransitionedAt: diag.Timestamp,
+	}
+}
+
+// TransitionKind classifies a severity change
+func TransitionKind(oldSeverity, newSeverity string) string {
+	switch {
+	case oldSeverity == SeverityNone:
+		return TransitionDegraded
+	case newSeverity == SeverityNone:
+		return TransitionRecovered
+	case severityRank[newSeverity] > severityRank[oldSeverity]:
+		return TransitionEscalated
+	default:
+		return TransitionImproved
+	}
+}
+
+func (t *TransitionTracker) notify(ctx context.Context, transition *storage.StatusTransition, kind string, diag *UltimateDiagnosis) {
+	if t.notifier == nil {
+		return
+	}
+
+	// A recovered service needs no remediation
+	var actions []notify.Action
+	if kind != TransitionRecovered {
+		actions = notificationActions(t.config.Get(), diag)
+	}
+
+	details := map[string]interface{}{
+		"transition":   kind,
+		"old_severity": transition.OldSeverity,
+		"new_severity": transition.NewSeverity,
+		"problem":      transition.ProblemType,
+		"health_score": transition.HealthScore,
+	}
+	addNoteDetails(details, diag)
+
+	err := t.notifier.Notify(ctx, notify.Notification{
+		Service:      transition.ServiceName,
+		Severity:     transition.NewSeverity,
+		Title:        fmt.Sprintf("%s %s: %s -> %s", transition.ServiceName, kind, transition.OldSeverity, transition.NewSeverity),
+		Message:      diag.Recommendation,
+		PredictionID: transition.PredictionID,
+		Actions:      actions,
+		Details:      details,
+		Timestamp:    time.Now(),
+	})
+	if err != nil {
+		logger.FromContext(ctx).Warn("Transition webhook failed", zap.String("service", transition.ServiceName), zap.Error(err))
+	}
+}
@@ -0,0 +1,170 @@
+package analyzer
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+)
+
+func newTestTransitionTracker(db *storage.PostgresClient, dwell int) (*TransitionTracker, *recordingNotifier) {
+	cfg := &core.Config{}
+	cfg.Analyzer.TransitionDwell = dwell
+	cfg.ApplyDefaults()
+	notifier := &recordingNotifier{}
+	return NewTransitionTracker(db, notifier, core.NewConfigStore("", cfg)), notifier
+}
+
+func TestTransitionKind(t *testing.T) {
+	tests := []struct {
+		old, new string
+		want     string
+	}{
+		{SeverityNone, SeverityHigh, TransitionDegraded},
+		{SeverityHigh, SeverityCritical, TransitionEscalated},
+		{SeverityCritical, SeverityMedium, TransitionImproved},
+		{SeverityCritical, SeverityNone, TransitionRecovered},
+	}
+	for _, tt := range tests {
+		if got := TransitionKind(tt.old, tt.new); got != tt.want {
+			t.Errorf("TransitionKind(%s, %s) = %s, want %s", tt.old, tt.new, got, tt.want)
+		}
+	}
+}
+
+func TestTransitionTrackerFiresOnEdgesOnly(t *testing.T) {
+	tracker, _ := newTestTransitionTracker(nil, 1)
+	t0 := testEpoch
+
+	steps := []struct {
+		diag     *UltimateDiagnosis
+		old, new string // empty when no transition is expected
+	}{
+		{healthyAt("checkout", t0), "", ""},
+		{diagnosisAt("checkout", t0.Add(time.Minute), DetectionMemoryLeak, SeverityHigh), SeverityNone, SeverityHigh},
+		{diagnosisAt("checkout", t0.Add(2*time.Minute), DetectionMemoryLeak, SeverityHigh), "", ""},
+		{diagnosisAt("checkout", t0.Add(3*time.Minute), DetectionMemoryLeak, SeverityCritical), SeverityHigh, SeverityCritical},
+		{healthyAt("checkout", t0.Add(4*time.Minute)), SeverityCritical, SeverityNone},
+		{healthyAt("checkout", t0.Add(5*time.Minute)), "", ""},
+	}
+	for i, step := range steps {
+		transition := tracker.advance(step.diag)
+		if step.new == "" {
+			if transition != nil {
+				t.Errorf("step %d: unexpected transition %s -> %s", i, transition.OldSeverity, transition.NewSeverity)
+			}
+			continue
+		}
+		if transition == nil {
+			t.Fatalf("step %d: no transition, want %s -> %s", i, step.old, step.new)
+		}
+		if transition.OldSeverity != step.old || transition.NewSeverity != step.new {
+			t.Errorf("step %d: transition %s -> %s, want %s -> %s", i, transition.OldSeverity, transition.NewSeverity, step.old, step.new)
+		}
+		if transition.PredictionID != step.diag.PredictionID || !transition.TransitionedAt.Equal(step.diag.Timestamp) {
+			t.Errorf("step %d: transition not tied to the diagnosis that caused it", i)
+		}
+	}
+
+	// Services are tracked independently
+	if transition := tracker.advance(diagnosisAt("payments", t0, DetectionCascadingFailure, SeverityHigh)); transition == nil || transition.OldSeverity != SeverityNone {
+		t.Errorf("payments transition = %+v, want from NONE", transition)
+	}
+}
+
+func TestTransitionTrackerDwell(t *testing.T) {
+	tracker, _ := newTestTransitionTracker(nil, 2)
+	t0 := testEpoch
+
+	// One unhealthy diagnosis between healthy ones is a blip
+	blip := diagnosisAt("checkout", t0, DetectionMemoryLeak, SeverityHigh)
+	if transition := tracker.advance(blip); transition != nil {
+		t.Fatal("transition after one diagnosis with a dwell of 2")
+	}
+	// The same shared diagnosis seen again counts once
+	if transition := tracker.advance(blip); transition != nil {
+		t.Fatal("a repeated diagnosis counted towards the dwell")
+	}
+	if transition := tracker.advance(healthyAt("checkout", t0.Add(time.Minute))); transition != nil {
+		t.Fatal("transition back to healthy that never left")
+	}
+
+	tracker.advance(diagnosisAt("checkout", t0.Add(2*time.Minute), DetectionMemoryLeak, SeverityHigh))
+	transition := tracker.advance(diagnosisAt("checkout", t0.Add(3*time.Minute), DetectionMemoryLeak, SeverityHigh))
+	if transition == nil || transition.NewSeverity != SeverityHigh {
+		t.Errorf("transition = %+v, want NONE -> HIGH after two diagnoses", transition)
+	}
+}
+
+func TestTransitionNotification(t *testing.T) {
+	tracker, notifier := newTestTransitionTracker(nil, 1)
+	ctx := context.Background()
+
+	escalation := diagnosisAt("checkout", testEpoch, DetectionMemoryLeak, SeverityCritical)
+	tracker.notify(ctx, &storage.StatusTransition{
+		ServiceName: "checkout", OldSeverity: SeverityHigh, NewSeverity: SeverityCritical,
+		ProblemType: string(DetectionMemoryLeak), PredictionID: escalation.PredictionID,
+	}, TransitionEscalated, escalation)
+
+	recovery := healthyAt("checkout", testEpoch.Add(time.Minute))
+	tracker.notify(ctx, &storage.StatusTransition{
+		ServiceName: "checkout", OldSeverity: SeverityCritical, NewSeverity: SeverityNone,
+		ProblemType: string(DetectionHealthy),
+	}, TransitionRecovered, recovery)
+
+	sent := notifier.notifications()
+	if len(sent) != 2 {
+		t.Fatalf("sent %d notifications, want 2", len(sent))
+	}
+
+	n := sent[0]
+	if n.Severity != SeverityCritical || !strings.Contains(n.Title, "escalated: HIGH -> CRITICAL") {
+		t.Errorf("escalation = %q (%s), want the old and new state", n.Title, n.Severity)
+	}
+	if n.Details["old_severity"] != SeverityHigh || n.Details["new_severity"] != SeverityCritical || n.Details["transition"] != TransitionEscalated {
+		t.Errorf("escalation details = %v", n.Details)
+	}
+	if n.PredictionID != escalation.PredictionID {
+		t.Errorf("prediction id = %q, want %q", n.PredictionID, escalation.PredictionID)
+	}
+
+	if r := sent[1]; r.Details["transition"] != TransitionRecovered || len(r.Actions) != 0 {
+		t.Errorf("recovery: transition %v with %d actions, want recovered with none", r.Details["transition"], len(r.Actions))
+	}
+}
+
+// TestTransitionTrackerPersists checks Observe stores each transition for
+// audit and that Load restores the last severity after a restart
+func TestTransitionTrackerPersists(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+
+	tracker, notifier := newTestTransitionTracker(db, 1)
+	now := time.Now().Truncate(time.Second)
+	tracker.Observe(ctx, diagnosisAt(service, now.Add(-2*time.Minute), DetectionMemoryLeak, SeverityHigh))
+	tracker.Observe(ctx, diagnosisAt(service, now.Add(-time.Minute), DetectionMemoryLeak, SeverityCritical))
+
+	transitions, err := db.GetStatusTransitions(ctx, service, 10)
+	if err != nil {
+		t.Fatalf("GetStatusTransitions: %v", err)
+	}
+	if len(transitions) != 2 {
+		t.Fatalf("stored %d transitions, want 2", len(transitions))
+	}
+	if len(notifier.notifications()) != 2 {
+		t.Errorf("sent %d notifications, want one per transition", len(notifier.notifications()))
+	}
+
+	restarted, _ := newTestTransitionTracker(db, 1)
+	if err := restarted.Load(ctx); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if transition := restarted.advance(diagnosisAt(service, now, DetectionMemoryLeak, SeverityCritical)); transition != nil {
+		t.Errorf("restarted tracker reported %s -> %s, want CRITICAL restored", transition.OldSeverity, transition.NewSeverity)
+	}
+}
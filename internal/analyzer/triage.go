@@ -0,0 +1,117 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// maxRecommendationSummary bounds a triage item's recommendation, in runes
+const maxRecommendationSummary = 160
+
+// TriageItem is one service needing attention in the on-call view
+type TriageItem struct {
+	Service               string        `json:"service"`
+	Problem               DetectionType `json:"problem"`
+	Severity              string        `json:"severity"`
+	PriorityScore         float64       `json:"priority_score"`
+	RecommendationSummary string        `json:"recommendation_summary"`
+}
+
+// ValidSeverity reports whether s is a detection severity other than NONE
+func ValidSeverity(s string) bool {
+	return severityRank[s] > 0
+}
+
+// TriageMinSeverity returns the configured default triage cutoff
+func (ua *UltimateAnalyzer) TriageMinSeverity() string {
+	if cfg := ua.cfg(); cfg != nil && ValidSeverity(cfg.Analyzer.TriageMinSeverity) {
+		return cfg.Analyzer.TriageMinSeverity
+	}
+	return SeverityLow
+}
+
+// Triage diagnoses the services concurrently and returns those whose primary
+// detection is a problem at or above minSeverity, highest priority first
+func (ua *UltimateAnalyzer) Triage(ctx context.Context, services []string, minSeverity string) ([]TriageItem, error) {
+	results := make([]*TriageItem, len(services))
+
+	ua.forEachService(ctx, services, func(ctx context.Context, i int, serviceName string) {
+		diag, err := ua.DiagnoseService(ctx, serviceName)
+		if err != nil {
+			logger.FromContext(ctx).Warn("Triage diagnosis failed",
+				zap.String("service", serviceName),
+				zap.Error(err),
+			)
+			return
+		}
+		results[i] = ua.triageItem(diag)
+	})
+
+	items, analyzed := selectTriage(results, minSeverity)
+	if len(services) > 0 && analyzed == 0 {
+		return nil, fmt.Errorf("failed to analyze any of %d services", len(services))
+	}
+	return items, nil
+}
+
+// selectTriage keeps the analyzed services (non-nil results) with a problem
+// at or above minSeverity, highest priority first, and counts the analyzed
+func selectTriage(results []*TriageItem, minSeverity string) ([]TriageItem, int) {
+	items := make([]TriageItem, 0)
+	analyzed := 0
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+		analyzed++
+		if r.Problem != "" && severityRank[r.Severity] >= severityRank[minSeverity] {
+			items = append(items, *r)
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].PriorityScore != items[j].PriorityScore {
+			return items[i].PriorityScore > items[j].PriorityScore
+		}
+		return items[i].Service < items[j].Service
+	})
+	return items, analyzed
+}
+
+// triageItem condenses a diagnosis; Problem is empty when nothing was detected
+func (ua *UltimateAnalyzer) triageItem(diag *UltimateDiagnosis) *TriageItem {
+	item := &TriageItem{Service: diag.ServiceName, Severity: SeverityNone}
+
+	primary := diag.PrimaryDetection
+	if primary == nil || !primary.Detected || primary.Type == DetectionHealthy {
+		return item
+	}
+
+	impact := ua.calculateImpactScore(diag)
+	item.Problem = primary.Type
+	item.Severity = primary.Severity
+	item.PriorityScore = ua.calculatePriorityScore(diag, impact)
+	item.RecommendationSummary = summarizeRecommendation(primary.Recommendation)
+	return item
+}
+
+// summarizeRecommendation keeps the first non-empty line, shortened to
+// maxRecommendationSummary runes
+func summarizeRecommendation(recommendation string) string {
+	summary := ""
+	for _, line := range strings.Split(recommendation, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			summary = line
+			break
+		}
+	}
+	if runes := []rune(summary); len(runes) > maxRecommendationSummary {
+		summary = string(runes[:maxRecommendationSummary-1]) + "…"
+	}
+	return summary
+}
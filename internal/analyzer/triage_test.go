@@ -0,0 +1,88 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+func TestSelectTriage(t *testing.T) {
+	results := []*TriageItem{
+		{Service: "search", Severity: SeverityNone},
+		{Service: "cart", Problem: DetectionMemoryLeak, Severity: SeverityLow, PriorityScore: 20},
+		nil, // diagnosis failed
+		{Service: "payments", Problem: DetectionExternalFailure, Severity: SeverityCritical, PriorityScore: 90},
+		{Service: "checkout", Problem: DetectionDeploymentBug, Severity: SeverityHigh, PriorityScore: 60},
+		{Service: "auth", Problem: DetectionMemoryLeak, Severity: SeverityHigh, PriorityScore: 60},
+	}
+
+	items, analyzed := selectTriage(results, SeverityLow)
+	if analyzed != 5 {
+		t.Errorf("analyzed = %d, want 5", analyzed)
+	}
+	var got []string
+	for _, item := range items {
+		got = append(got, item.Service)
+	}
+	// Highest priority first, ties broken by name; healthy services are left out
+	if want := "payments,auth,checkout,cart"; strings.Join(got, ",") != want {
+		t.Errorf("triage order = %v, want %s", got, want)
+	}
+
+	items, _ = selectTriage(results, SeverityHigh)
+	if len(items) != 3 {
+		t.Errorf("got %d items at HIGH and above, want 3", len(items))
+	}
+	if items, analyzed = selectTriage([]*TriageItem{nil}, SeverityLow); len(items) != 0 || analyzed != 0 {
+		t.Errorf("all failed = %v items, %d analyzed; want none", items, analyzed)
+	}
+}
+
+func TestTriageItem(t *testing.T) {
+	cfg := &core.Config{}
+	cfg.ApplyDefaults()
+	ua := NewUltimateAnalyzer(nil, core.NewConfigStore("", cfg))
+
+	diag := scoredDiagnosis()
+	diag.ServiceName = "checkout"
+	diag.PrimaryDetection.Recommendation = "\n  Restart the pods gradually.\nThen watch memory."
+
+	item := ua.triageItem(diag)
+	if item.Problem != DetectionMemoryLeak || item.Severity != SeverityHigh || item.PriorityScore <= 0 {
+		t.Errorf("item = %+v, want the HIGH memory leak with a priority", item)
+	}
+	if item.RecommendationSummary != "Restart the pods gradually." {
+		t.Errorf("summary = %q, want the first non-empty line", item.RecommendationSummary)
+	}
+
+	healthy := &UltimateDiagnosis{ServiceName: "search", PrimaryDetection: &Detection{Type: DetectionHealthy, Detected: true}}
+	if item := ua.triageItem(healthy); item.Problem != "" || item.Severity != SeverityNone {
+		t.Errorf("healthy item = %+v, want no problem", item)
+	}
+}
+
+func TestSummarizeRecommendationTruncates(t *testing.T) {
+	summary := summarizeRecommendation(strings.Repeat("é", maxRecommendationSummary+20))
+	if runes := []rune(summary); len(runes) != maxRecommendationSummary || !strings.HasSuffix(summary, "…") {
+		t.Errorf("summary has %d runes, want %d ending in an ellipsis", len(runes), maxRecommendationSummary)
+	}
+}
+
+func TestTriageMinSeverity(t *testing.T) {
+	for _, tt := range []struct{ configured, want string }{
+		{"", SeverityLow},
+		{SeverityHigh, SeverityHigh},
+		{"SEVERE", SeverityLow},
+	} {
+		cfg := &core.Config{}
+		cfg.Analyzer.TriageMinSeverity = tt.configured
+		ua := NewUltimateAnalyzer(nil, core.NewConfigStore("", cfg))
+		if got := ua.TriageMinSeverity(); got != tt.want {
+			t.Errorf("TriageMinSeverity with %q = %s, want %s", tt.configured, got, tt.want)
+		}
+	}
+	if ValidSeverity(SeverityNone) || !ValidSeverity(SeverityCritical) {
+		t.Error("ValidSeverity must accept CRITICAL and reject NONE")
+	}
+}
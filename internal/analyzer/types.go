@@ -12,6 +12,8 @@ const (
 	DetectionCascadingFailure   DetectionType = "CASCADING_FAILURE"
 	DetectionExternalFailure    DetectionType = "EXTERNAL_FAILURE"
 	DetectionResourceExhaustion DetectionType = "RESOURCE_EXHAUSTION"
+	DetectionServiceStale       DetectionType = "SERVICE_STALE"
+	DetectionGCPressure         DetectionType = "GC_PRESSURE"
 	DetectionHealthy            DetectionType = "HEALTHY"
 	DetectionUnknown            DetectionType = "UNKNOWN"
 )
@@ -34,8 +36,44 @@ type Detection struct {
 	Evidence       map[string]interface{} `json:"evidence"`
 	Recommendation string                 `json:"recommendation"`
 	Severity       string                 `json:"severity"` // LOW, MEDIUM, HIGH, CRITICAL
+
+	// RawConfidence is the detector's own confidence before calibration;
+	// only set by the ultimate analyzer's fan-out
+	RawConfidence float64 `json:"raw_confidence,omitempty"`
+
+	// AggregateConfidence is the sum of an enhanced detector's weighted
+	// signals before its quality gates; Dampening is the product of the
+	// factors the failed gates applied (1 when none failed), named in
+	// DampeningReasons. RawConfidence is AggregateConfidence x Dampening
+	// unless the detector raised it to a floor (a saturated single resource).
+	AggregateConfidence float64  `json:"aggregate_confidence,omitempty"`
+	Dampening           float64  `json:"dampening,omitempty"`
+	DampeningReasons    []string `json:"dampening_reasons,omitempty"`
+
+	// Status reports whether the detector finished (ok) or was cut short
+	// (timeout, error); only set by the ultimate analyzer's fan-out
+	Status string `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+
+	// DataSufficiency is partial when a metric the detector weighs had no
+	// samples and its weights were re-normalized over the signals that
+	// could be measured; only set by detectors that do so
+	DataSufficiency string `json:"data_sufficiency,omitempty"`
 }
 
+// Data sufficiency of a detection
+const (
+	DataSufficiencyFull    = "full"
+	DataSufficiencyPartial = "partial"
+)
+
+// Detector completion statuses
+const (
+	DetectionStatusOK      = "ok"
+	DetectionStatusTimeout = "timeout"
+	DetectionStatusError   = "error"
+)
+
 type Diagnosis struct {
 	ServiceName         string                 `json:"service_name"`
 	Problem             DetectionType          `json:"problem"`
@@ -67,14 +105,25 @@ type CorrelationInsight struct {
 }
 
 type ServiceComparison struct {
-	ServiceName       string  `json:"service_name"`
-	HealthScore       float64 `json:"health_score"` // 0-100, higher is better
-	PrimaryIssue      string  `json:"primary_issue"`
-	IssueCount        int     `json:"issue_count"`
-	Severity          string  `json:"severity"`
-	RequiresAttention bool    `json:"requires_attention"` // true if health < 80
+	ServiceName       string     `json:"service_name"`
+	HealthScore       float64    `json:"health_score"` // 0-100, higher is better
+	PrimaryIssue      string     `json:"primary_issue"`
+	IssueCount        int        `json:"issue_count"`
+	Severity          string     `json:"severity"`
+	RequiresAttention bool       `json:"requires_attention"`  // true if health < 80
+	LastSeen          *time.Time `json:"last_seen,omitempty"` // timestamp of the latest metric sample, nil if never seen
+
+	// RemediationMode is auto when the service's actions are executed and
+	// recommend when they are only recorded (actuator.auto_remediate)
+	RemediationMode string `json:"remediation_mode,omitempty"`
 }
 
+// Remediation modes of a service
+const (
+	RemediationAuto      = "auto"
+	RemediationRecommend = "recommend"
+)
+
 // ==================== ENHANCED DIAGNOSTIC TYPES ====================
 
 // EnhancedDiagnosticData contains all the rich diagnostic information
@@ -247,11 +296,13 @@ type HealthIntelligence struct {
 	DegradationRate float64        `json:"degradation_rate"`
 }
 
+// HealthHistory holds the recorded health score as of each point in the
+// past; a point before the service's earliest record is null
 type HealthHistory struct {
-	Last5Minutes  float64 `json:"last_5_minutes"`
-	Last15Minutes float64 `json:"last_15_minutes"`
-	Last30Minutes float64 `json:"last_30_minutes"`
-	Last1Hour     float64 `json:"last_1_hour"`
+	Last5Minutes  *float64 `json:"last_5_minutes"`
+	Last15Minutes *float64 `json:"last_15_minutes"`
+	Last30Minutes *float64 `json:"last_30_minutes"`
+	Last1Hour     *float64 `json:"last_1_hour"`
 }
 
 type SLACompliance struct {
@@ -261,6 +312,10 @@ type SLACompliance struct {
 	Metrics           map[string]*SLAMetric `json:"metrics"`
 	ViolationCount    int                   `json:"violation_count"`
 	WarningCount      int                   `json:"warning_count"`
+
+	// ErrorBudgetBurnRate is observed unavailability over the budget the
+	// availability target allows; above 1 the budget runs out early
+	ErrorBudgetBurnRate float64 `json:"error_budget_burn_rate"`
 }
 
 type SLAMetric struct {
@@ -0,0 +1,124 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+)
+
+func TestDetectorWindows(t *testing.T) {
+	defaults := newTestDetector(&core.Config{})
+	cfg := &core.Config{}
+	cfg.Analyzer.Windows.MemoryLeak = "10m"
+	cfg.Analyzer.Windows.CascadeFailure = "1h"
+	configured := newTestDetector(cfg)
+
+	tests := []struct {
+		detector string
+		ed       *EnhancedDetector
+		want     time.Duration
+	}{
+		{"memory_leak", defaults, 30 * time.Minute},
+		{"resource_exhaustion", defaults, 15 * time.Minute},
+		{"deployment_bug", defaults, 20 * time.Minute},
+		{"external_failure", defaults, 15 * time.Minute},
+		{"cascade_failure", defaults, 20 * time.Minute},
+		{"gc_pressure", defaults, 30 * time.Minute},
+		{"memory_leak", configured, 10 * time.Minute},
+		{"cascade_failure", configured, time.Hour},
+		{"deployment_bug", configured, 20 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := tt.ed.window(tt.detector); got != tt.want {
+			t.Errorf("window(%q) = %v, want %v", tt.detector, got, tt.want)
+		}
+	}
+
+	// The catalog lists the window each detector runs with
+	ua := NewUltimateAnalyzer(nil, core.NewConfigStore("", cfg))
+	if got := detectorNamed(t, ua.Detectors(""), "memory_leak").Thresholds["window"]; got != "10m" {
+		t.Errorf("catalog memory_leak window = %v, want 10m", got)
+	}
+}
+
+func TestWindowLabel(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{30 * time.Minute, "30m"},
+		{90 * time.Minute, "1h30m"},
+		{2 * time.Hour, "2h"},
+		{45 * time.Second, "45s"},
+		{10 * time.Minute / 3, "3m20s"},
+	}
+	for _, tt := range tests {
+		if got := windowLabel(tt.d); got != tt.want {
+			t.Errorf("windowLabel(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestShortenedWindowConsidersFewerPoints(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+
+	// 40 minutes of steadily growing memory at 30s resolution
+	end := time.Now().Add(-30 * time.Second)
+	memory := generate(80, func(i int) float64 { return 50 + 0.1*float64(i) })
+	storagetest.Seed(t, db, storagetest.Series(service, MetricMemory, end, 30*time.Second, memory...))
+	storagetest.Seed(t, db, storagetest.Series(service, MetricCPU, end, 30*time.Second, generate(80, func(int) float64 { return 40 })...))
+
+	detect := func(window string) *Detection {
+		cfg := &core.Config{}
+		cfg.Analyzer.Windows.MemoryLeak = window
+		cfg.ApplyDefaults()
+		store := core.NewConfigStore("", cfg)
+		d, err := NewEnhancedDetector(NewFeatureExtractor(db, store), store).DetectMemoryLeakEnhanced(context.Background(), service)
+		if err != nil {
+			t.Fatalf("DetectMemoryLeakEnhanced with %s: %v", window, err)
+		}
+		return d
+	}
+
+	full, short := detect("30m"), detect("10m")
+	if full.Evidence["window"] != "30m" || short.Evidence["window"] != "10m" {
+		t.Errorf("evidence windows = %v/%v, want 30m/10m", full.Evidence["window"], short.Evidence["window"])
+	}
+
+	// Trend confirmation fits the trailing thirds of whichever window is set
+	fullSlopes := full.Evidence["window_slopes"].(map[string]float64)
+	shortSlopes := short.Evidence["window_slopes"].(map[string]float64)
+	for _, label := range []string{"10m", "20m", "30m"} {
+		if _, ok := fullSlopes[label]; !ok {
+			t.Errorf("30m window slopes %v lack %s", fullSlopes, label)
+		}
+	}
+	for _, label := range []string{"3m20s", "6m40s", "10m"} {
+		if _, ok := shortSlopes[label]; !ok {
+			t.Errorf("10m window slopes %v lack %s", shortSlopes, label)
+		}
+	}
+
+	// Features only see the samples inside the window
+	cfg := &core.Config{}
+	cfg.ApplyDefaults()
+	fe := NewFeatureExtractor(db, core.NewConfigStore("", cfg))
+	long, err := fe.ExtractFeatures(context.Background(), service, 30*time.Minute)
+	if err != nil {
+		t.Fatalf("ExtractFeatures: %v", err)
+	}
+	brief, err := fe.ExtractFeatures(context.Background(), service, 10*time.Minute)
+	if err != nil {
+		t.Fatalf("ExtractFeatures: %v", err)
+	}
+	if brief.CoveredSpan > 10*time.Minute || brief.CoveredSpan >= long.CoveredSpan {
+		t.Errorf("covered spans = %v (10m) and %v (30m), want the short window to cover less", brief.CoveredSpan, long.CoveredSpan)
+	}
+	if brief.MemoryMean <= long.MemoryMean {
+		t.Errorf("memory means = %.2f (10m) and %.2f (30m), want the short window to see only the newest, higher samples", brief.MemoryMean, long.MemoryMean)
+	}
+}
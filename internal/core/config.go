@@ -2,9 +2,15 @@
 package core
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
+	"net/url"
 	"os"
+	"sort"
 	"strings"
+	"text/template"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -12,11 +18,23 @@ import (
 // Config holds all AURA configuration with validation
 type Config struct {
 	App struct {
-		Name     string `yaml:"name"`
-		Version  string `yaml:"version"`
-		LogLevel string `yaml:"log_level"`
+		Name        string `yaml:"name"`
+		Version     string `yaml:"version"`
+		LogLevel    string `yaml:"log_level"`
+		LogFormat   string `yaml:"log_format"`   // json or console; empty picks by ENVIRONMENT
+		LogSampling bool   `yaml:"log_sampling"` // sample repetitive log lines under load
 	} `yaml:"app"`
 
+	HTTP struct {
+		// AllowedOrigins lists the browser origins (scheme://host[:port])
+		// allowed to call the API cross-origin. Empty keeps the API
+		// same-origin only; "*" allows any origin and must be explicit.
+		AllowedOrigins []string `yaml:"allowed_origins"`
+
+		// MaxBodyBytes caps request bodies on every route (default 1 MiB)
+		MaxBodyBytes int64 `yaml:"max_body_bytes"`
+	} `yaml:"http"`
+
 	Database struct {
 		Host           string `yaml:"host"`
 		Port           int    `yaml:"port"`
@@ -24,17 +42,84 @@ type Config struct {
 		Password       string `yaml:"password"`
 		DBName         string `yaml:"dbname"`
 		MaxConnections int    `yaml:"max_connections"`
+
+		// ReadReplicaURL is a postgres:// URL of a read-only replica. When
+		// set, query endpoints and analyzer reads use a second pool on it,
+		// sized by pool, while writes stay on the primary. Replica reads
+		// may lag the primary. Overridden by AURA_DB_READ_REPLICA_URL.
+		ReadReplicaURL string `yaml:"read_replica_url"`
+
+		// Pool tunes the connection pool. MaxConns falls back to
+		// max_connections; the rest default to min 5 connections, a 1h
+		// lifetime, 30m idle time, 1m health checks and a 10s connect timeout.
+		//
+		// QueryExecMode picks how queries run: cache_statement (default)
+		// prepares each distinct query once per connection and reuses it,
+		// so hot queries skip parsing and planning; cache_describe,
+		// describe_exec, exec and simple_protocol cache less. Use exec or
+		// simple_protocol behind a transaction-pooling proxy.
+		// StatementCacheCapacity bounds the statements cached per
+		// connection (default 512).
+		Pool struct {
+			MaxConns          int    `yaml:"max_conns"`
+			MinConns          int    `yaml:"min_conns"`
+			MaxConnLifetime   string `yaml:"max_conn_lifetime"`
+			MaxConnIdleTime   string `yaml:"max_conn_idle_time"`
+			HealthCheckPeriod string `yaml:"health_check_period"`
+			ConnectTimeout    string `yaml:"connect_timeout"`
+
+			QueryExecMode          string `yaml:"query_exec_mode"`
+			StatementCacheCapacity int    `yaml:"statement_cache_capacity"`
+		} `yaml:"pool"`
 	} `yaml:"database"`
 
 	Prometheus struct {
 		URL            string `yaml:"url"`
 		ScrapeInterval string `yaml:"scrape_interval"`
+
+		// Required keeps /ready failing until Prometheus has answered a
+		// scrape. When false (the default) a first attempt is enough, so an
+		// optional Prometheus being down doesn't take AURA out of rotation.
+		Required bool `yaml:"required"`
+
+		// MetricIntervals scrapes individual stored metrics (e.g.
+		// response_time_p99_ms) less often than scrape_interval, for queries
+		// that are expensive on the Prometheus side
+		MetricIntervals map[string]string `yaml:"metric_intervals"`
+
+		// ScrapeTimeout bounds each scheduled scrape query and QueryTimeout
+		// each ad-hoc query (the query API, dependency checks), so expensive
+		// user PromQL can't stall collection or the other way round
+		// (default 10s each)
+		ScrapeTimeout string `yaml:"scrape_timeout"`
+		QueryTimeout  string `yaml:"query_timeout"`
+
+		// RemoteWrite maps the series Prometheus pushes to POST
+		// /api/v1/prometheus/remote_write onto AURA metrics. The service is
+		// the value of the first of service_labels a series carries; series
+		// with none of them are dropped. metric_names renames a series'
+		// __name__ to the metric detectors read; with drop_unmapped, series
+		// whose name it doesn't list are dropped instead of stored as is.
+		RemoteWrite struct {
+			ServiceLabels []string          `yaml:"service_labels"` // default [service, job]
+			MetricNames   map[string]string `yaml:"metric_names"`
+			DropUnmapped  bool              `yaml:"drop_unmapped"`
+		} `yaml:"remote_write"`
 	} `yaml:"prometheus"`
 
 	Kubernetes struct {
 		Enabled         bool   `yaml:"enabled"`
 		Namespace       string `yaml:"namespace"`
 		MetricsInterval string `yaml:"metrics_interval"`
+		MaxReplicas     int32  `yaml:"max_replicas"` // upper bound for actuator scaling
+
+		// ResourceMetricsInterval is how often pod CPU/memory is read from metrics-server
+		ResourceMetricsInterval string `yaml:"resource_metrics_interval"`
+
+		// Namespaces watches several namespaces, one watcher each sharing
+		// the client. When set it replaces Namespace; the actuator acts in
+		// the first one.
+		Namespaces []string `yaml:"namespaces"`
 	} `yaml:"kubernetes"`
 
 	Observer struct {
@@ -47,15 +132,918 @@ type Config struct {
 		MemoryThreshold    float64 `yaml:"memory_threshold"`
 		ErrorRateThreshold float64 `yaml:"error_rate_threshold"`
 		LatencyThreshold   float64 `yaml:"latency_threshold"`
+
+		// EnsembleStrategy controls how classic and enhanced verdicts are
+		// merged: agreement, max or average (default max)
+		EnsembleStrategy    string  `yaml:"ensemble_strategy"`
+		DisagreementPenalty float64 `yaml:"disagreement_penalty"` // 0-1 confidence multiplier when stacks disagree
+
+		// MinCorrelationSamples is the fewest paired samples a correlation is
+		// trusted from (default 3); CorrelationWindow limits correlations to the
+		// most recent part of the feature window (default: the whole window)
+		MinCorrelationSamples int    `yaml:"min_correlation_samples"`
+		CorrelationWindow     string `yaml:"correlation_window"`
+
+		// StaleAfter is how long a previously-active service may go without
+		// reporting metrics before it is flagged SERVICE_STALE (default 3m)
+		StaleAfter string `yaml:"stale_after"`
+
+		// PerDetectorTimeout bounds each enhanced detector in a diagnosis; a
+		// detector that runs over is reported as timed out (default 10s)
+		PerDetectorTimeout string `yaml:"per_detector_timeout"`
+
+		// DiagnosisCacheTTL is how long a diagnosis is reused for later
+		// requests for the same service; concurrent requests always share one
+		// run (default 5s)
+		DiagnosisCacheTTL string `yaml:"diagnosis_cache_ttl"`
+
+		// SmoothingWindow applies a moving average over this many samples to
+		// CPU, memory and error series before feature extraction (0 = off).
+		// Volatility, trend and autocorrelation are then computed on the
+		// smoothed series; error spikiness always uses the raw samples.
+		SmoothingWindow int    `yaml:"smoothing_window"`
+		SmoothingMethod string `yaml:"smoothing_method"` // sma (default) or ema
+
+		// SpikinessMethod measures error-rate spikiness from successive
+		// differences: cv (default) is their coefficient of variation;
+		// percentile is the ratio of their 95th percentile to their median,
+		// which a single outlier barely moves
+		SpikinessMethod string `yaml:"spikiness_method"`
+
+		// PercentileMethod computes latency percentiles from raw samples:
+		// interpolated (default) blends the two nearest ranks linearly;
+		// nearest_rank returns an observed sample, which matches how most
+		// SLO tooling reports a p99
+		PercentileMethod string `yaml:"percentile_method"`
+
+		// MaxFeatureWindow caps the window features are extracted over
+		// (default 24h). MaxSeriesPoints caps the samples loaded per series;
+		// longer series are downsampled evenly across the window so trends
+		// still span all of it (default 1000).
+		MaxFeatureWindow string `yaml:"max_feature_window"`
+		MaxSeriesPoints  int    `yaml:"max_series_points"`
+
+		// Resample, when set, interpolates a series onto a uniform grid of
+		// this step before its trend is fitted: the CPU, memory and error
+		// trend features, memory leak confirmation and OOM projection.
+		// Irregular scrapes and gaps then don't bias the slope. Empty (the
+		// default) fits the raw timestamps.
+		Resample string `yaml:"resample"`
+
+		// TriageMinSeverity is the lowest severity the triage view lists when
+		// the request doesn't set min_severity (default LOW)
+		TriageMinSeverity string `yaml:"triage_min_severity"`
+
+		// SnapshotInterval is how often each active service's key features
+		// are stored for drift analysis; each snapshot covers the interval
+		// since the previous one (default 1h)
+		SnapshotInterval string `yaml:"snapshot_interval"`
+
+		// TransitionDwell is how many consecutive diagnoses must agree on a
+		// new severity, or a new problem, before a severity transition or an
+		// incident is committed, damping services that flap around a
+		// threshold (default 2; 1 commits immediately)
+		TransitionDwell int `yaml:"transition_dwell"`
+
+		// TransitionHysteresis lowers the threshold for leaving a severity
+		// by this many confidence points below the one for entering it, so a
+		// confidence hovering at a cutoff doesn't flip the severity back and
+		// forth (default 5; negative disables)
+		TransitionHysteresis float64 `yaml:"transition_hysteresis"`
+
+		// CompositeMinConfidence is the confidence a detection needs to be
+		// folded into a composite diagnosis (?composite=true) as the root,
+		// an effect or a concurrent problem (default 60)
+		CompositeMinConfidence float64 `yaml:"composite_min_confidence"`
+
+		// DisabledDetectors names detectors (memory_leak, resource_exhaustion,
+		// deployment_bug, external_failure, cascade_failure) that diagnoses
+		// and the ensemble skip. GET /api/v1/detectors lists the names.
+		DisabledDetectors []string `yaml:"disabled_detectors"`
+
+		// Windows is how far back each enhanced detector extracts features.
+		// Shorten them where metrics are dense, lengthen them where scrapes
+		// are sparse.
+		Windows DetectorWindows `yaml:"windows"`
+
+		// Dampening sets the factors enhanced detectors multiply their
+		// confidence by when a quality gate fails. Detections report the
+		// confidence before dampening and the factor applied.
+		Dampening DampeningFactors `yaml:"dampening"`
 	} `yaml:"analyzer"`
 
 	Decision struct {
 		ConfidenceThreshold float64 `yaml:"confidence_threshold"`
 		DryRun              bool    `yaml:"dry_run"`
 	} `yaml:"decision"`
+
+	// Actuator is the safe mode in front of every execution: an action is
+	// blocked, and recorded as blocked, instead of executed when the
+	// detection behind it is below min_confidence or was made on partial
+	// data, or when max_actions have already run within window
+	Actuator struct {
+		MinConfidence    float64 `yaml:"min_confidence"`     // 0-100 (default 70; negative disables)
+		AllowPartialData bool    `yaml:"allow_partial_data"` // execute on detections missing a weighed metric
+		MaxActions       int     `yaml:"max_actions"`        // executions per window across all services (default 3; negative disables)
+		Window           string  `yaml:"window"`             // default 10m
+
+		// AutoRemediate lists the services whose actions are executed; "*"
+		// matches every service. Actions for the rest are only recorded as
+		// decisions. Empty (the default) is recommend-only everywhere.
+		AutoRemediate []string `yaml:"auto_remediate"`
+	} `yaml:"actuator"`
+
+	// Thresholds holds per-service detector overrides keyed by service name
+	Thresholds map[string]ServiceThresholds `yaml:"thresholds"`
+
+	// Resources holds per-service container resource limits keyed by service name
+	Resources map[string]ServiceResources `yaml:"resources"`
+
+	// RiskThresholds tunes how health, stress and confidence map to risk
+	// levels and severities
+	RiskThresholds RiskThresholds `yaml:"risk_thresholds"`
+
+	Calibration struct {
+		// Detectors maps a detection type (MEMORY_LEAK, DEPLOYMENT_BUG, ...)
+		// to the table that puts its raw confidence on the common scale the
+		// risk thresholds expect. Types without a table pass through unchanged.
+		Detectors map[string]CalibrationTable `yaml:"detectors"`
+
+		// MinConfidence clears a detection whose calibrated confidence falls
+		// below it (0 = keep every detection the detector reports)
+		MinConfidence float64 `yaml:"min_confidence"`
+	} `yaml:"calibration"`
+
+	Health struct {
+		// Weights are the points deducted from a service's 100-point health
+		// score for each problem signal
+		Weights HealthWeights `yaml:"weights"`
+	} `yaml:"health"`
+
+	// Scoring tunes the impact and priority scores of the advanced analysis
+	Scoring ScoringWeights `yaml:"scoring"`
+
+	Notifications struct {
+		// Escalation is the severity x burn-rate matrix, evaluated top to
+		// bottom. Empty uses the built-in matrix.
+		Escalation []EscalationRule `yaml:"escalation"`
+
+		// Webhooks receive every notification in parallel with the log
+		Webhooks []WebhookConfig `yaml:"webhooks"`
+
+		// MaxActions caps the suggested actuator actions embedded in
+		// notifications about a diagnosis (default 3; negative omits them)
+		MaxActions int `yaml:"max_actions"`
+	} `yaml:"notifications"`
+
+	// Transitions are called only when a service's diagnosed severity changes
+	// (healthy -> unhealthy, escalation, improvement, recovery), unlike
+	// notifications which see every diagnosis
+	Transitions struct {
+		Webhooks []WebhookConfig `yaml:"webhooks"`
+	} `yaml:"transitions"`
+
+	// Cascade bounds the cross-service correlation done by cascade detection
+	Cascade struct {
+		// MaxCandidates caps how many other services are correlated against
+		// (default 5). Declared dependencies are used first; without them the
+		// services with the highest recent error rate are picked.
+		MaxCandidates int                 `yaml:"max_candidates"`
+		Dependencies  map[string][]string `yaml:"dependencies"` // service -> services it calls
+		CacheTTL      string              `yaml:"cache_ttl"`    // reuse pair correlations this long (default 2m)
+	} `yaml:"cascade"`
+
+	// ServiceGroups assigns services to groups (service -> group), e.g. a
+	// team or tier. It overrides the "group" label on a service's metrics.
+	ServiceGroups map[string]string `yaml:"service_groups"`
+
+	// Dependencies declares, per service, PromQL checks of the external
+	// dependencies it calls. External failure detection runs them and treats
+	// a failing check as direct evidence.
+	Dependencies map[string][]DependencyCheck `yaml:"dependencies"`
+
+	// Incidents groups repeated diagnoses of the same service and problem
+	Incidents struct {
+		// ResolveAfter closes an incident once its problem has not been
+		// diagnosed for this long (default 10m)
+		ResolveAfter string `yaml:"resolve_after"`
+
+		// VerifyAfter is how long after a decision is executed the service is
+		// re-diagnosed to confirm the problem cleared (default 5m)
+		VerifyAfter string `yaml:"verify_after"`
+	} `yaml:"incidents"`
+
+	// Reports compiles a summary of each UTC day (incident counts, MTTR,
+	// least healthy services, error budget burn) shortly after midnight and
+	// stores it for GET /api/v1/reports/daily, which can also compile any
+	// other day on request
+	Reports struct {
+		Enabled     bool `yaml:"enabled"`      // compile each day on schedule
+		Notify      bool `yaml:"notify"`       // send scheduled reports through the alert notifiers
+		TopServices int  `yaml:"top_services"` // services listed as least healthy and by SLO burn (default 5)
+	} `yaml:"reports"`
+
+	// Anomaly runs a background outlier scan over key metrics of every
+	// active service. Each outlier is recorded as an Anomaly event.
+	Anomaly struct {
+		Enabled  bool   `yaml:"enabled"`
+		Interval string `yaml:"interval"` // how often services are scanned (default 1m)
+		Window   string `yaml:"window"`   // baseline the newest sample is compared against (default 30m)
+
+		// Metrics are the canonical metrics scanned (default cpu_usage,
+		// memory_usage, error_rate, response_time)
+		Metrics []string `yaml:"metrics"`
+
+		// Method scores the newest sample: zscore (default) in standard
+		// deviations from the mean, or mad, the modified z-score from the
+		// median absolute deviation, which the outliers themselves barely move
+		Method    string  `yaml:"method"`
+		Threshold float64 `yaml:"threshold"` // score above which a sample is an anomaly (default 3.5)
+		Notify    bool    `yaml:"notify"`    // send anomalies through the alert notifiers
+	} `yaml:"anomaly"`
+
+	// Ingest tunes POST /api/v1/metrics/ingest for push-based sources
+	Ingest struct {
+		MaxItems       int    `yaml:"max_items"`       // items accepted per request, with a 2 KiB body allowance each (default 5000)
+		BatchSize      int    `yaml:"batch_size"`      // metrics per database write (default 1000)
+		BufferCapacity int    `yaml:"buffer_capacity"` // metrics held awaiting a write before ingest returns 503 (default 50000)
+		FlushInterval  string `yaml:"flush_interval"`  // write pending metrics at least this often (default 2s)
+	} `yaml:"ingest"`
+
+	// Storage bounds the labels stored with each metric. Keys outside
+	// label_allowlist are dropped, then keys past max_label_keys in sorted
+	// order, then the last keys until the labels fit max_label_bytes; a
+	// warning is logged when labels are dropped. A negative limit or an
+	// empty allowlist disables that check.
+	Storage struct {
+		MaxLabelKeys   int      `yaml:"max_label_keys"`  // default 32
+		MaxLabelBytes  int      `yaml:"max_label_bytes"` // default 4096
+		LabelAllowlist []string `yaml:"label_allowlist"`
+
+		// WriteResolution, when set, makes the ingest buffer store one row
+		// per series and bucket of this length: the mean of the bucket's
+		// samples, with their min and max kept alongside. Detectors read the
+		// means like any other samples. Scraped metrics are written as is.
+		WriteResolution string `yaml:"write_resolution"`
+	} `yaml:"storage"`
+
+	// Admin guards the /api/v1/admin endpoints and service purges
+	// (DELETE /api/v1/services/:service). They are disabled unless a
+	// token is set (also AURA_ADMIN_TOKEN); requests must send it as
+	// "Authorization: Bearer <token>".
+	Admin struct {
+		Token string `yaml:"token"`
+	} `yaml:"admin"`
+
+	Testing struct {
+		// Enabled exposes POST /api/v1/test/inject for feeding synthetic
+		// metrics in integration tests. Never enable in production.
+		Enabled bool `yaml:"enabled"`
+	} `yaml:"testing"`
+
+	// Maintenance declares windows during which notifications are suppressed.
+	// Diagnoses still run and are recorded.
+	Maintenance []MaintenanceWindow `yaml:"maintenance"`
+
+	// MetricAliases maps a canonical metric (cpu_usage, memory_usage,
+	// error_rate, response_time, request_rate) to the stored names to try,
+	// in order
+	MetricAliases map[string][]string `yaml:"metric_aliases"`
+
+	// MetricTypes declares stored metrics as "gauge" or "counter". Counters
+	// are turned into per-minute rates before feature extraction. Entries
+	// override the analyzer's built-in list.
+	MetricTypes map[string]string `yaml:"metric_types"`
+
+	// MetricTransforms converts stored metrics to the units detectors
+	// assume, keyed by stored metric name. They apply at read time, so
+	// stored samples keep their original values.
+	MetricTransforms map[string]MetricTransform `yaml:"metric_transforms"`
+}
+
+// RiskThresholds are the cutoffs used by the analyzer's RiskClassifier.
+// Zero values take the defaults from DefaultRiskThresholds.
+type RiskThresholds struct {
+	CriticalHealth float64 `yaml:"critical_health"` // health below this is CRITICAL risk
+	HighHealth     float64 `yaml:"high_health"`     // health below this is HIGH risk
+	HighStress     float64 `yaml:"high_stress"`     // system stress above this is HIGH risk
+	MediumHealth   float64 `yaml:"medium_health"`   // health below this is MEDIUM risk
+
+	// Confidence cutoffs used when a detector derives severity from confidence
+	CriticalConfidence float64 `yaml:"critical_confidence"`
+	HighConfidence     float64 `yaml:"high_confidence"`
+	MediumConfidence   float64 `yaml:"medium_confidence"`
+}
+
+// DefaultRiskThresholds returns the built-in cutoffs
+func DefaultRiskThresholds() RiskThresholds {
+	return RiskThresholds{
+		CriticalHealth:     30,
+		HighHealth:         50,
+		HighStress:         80,
+		MediumHealth:       70,
+		CriticalConfidence: 85,
+		HighConfidence:     70,
+		MediumConfidence:   50,
+	}
+}
+
+// WithDefaults fills zero fields from DefaultRiskThresholds
+func (t RiskThresholds) WithDefaults() RiskThresholds {
+	d := DefaultRiskThresholds()
+	if t.CriticalHealth == 0 {
+		t.CriticalHealth = d.CriticalHealth
+	}
+	if t.HighHealth == 0 {
+		t.HighHealth = d.HighHealth
+	}
+	if t.HighStress == 0 {
+		t.HighStress = d.HighStress
+	}
+	if t.MediumHealth == 0 {
+		t.MediumHealth = d.MediumHealth
+	}
+	if t.CriticalConfidence == 0 {
+		t.CriticalConfidence = d.CriticalConfidence
+	}
+	if t.HighConfidence == 0 {
+		t.HighConfidence = d.HighConfidence
+	}
+	if t.MediumConfidence == 0 {
+		t.MediumConfidence = d.MediumConfidence
+	}
+	return t
+}
+
+// HealthWeights are the health score deductions applied by the feature
+// extractor. Zero values take the defaults from DefaultHealthWeights.
+type HealthWeights struct {
+	CPU         float64 `yaml:"cpu"`          // mean CPU above 80%
+	Memory      float64 `yaml:"memory"`       // mean memory above 85%
+	ErrorRate   float64 `yaml:"error_rate"`   // mean error rate above 5%
+	Latency     float64 `yaml:"latency"`      // p95 latency above 2000ms
+	CPUTrend    float64 `yaml:"cpu_trend"`    // CPU growing faster than 0.5%/min
+	MemoryTrend float64 `yaml:"memory_trend"` // memory growing faster than 0.5%/min
+}
+
+// DefaultHealthWeights returns the built-in deductions
+func DefaultHealthWeights() HealthWeights {
+	return HealthWeights{
+		CPU:         20,
+		Memory:      20,
+		ErrorRate:   30,
+		Latency:     15,
+		CPUTrend:    10,
+		MemoryTrend: 10,
+	}
+}
+
+// WithDefaults fills zero fields from DefaultHealthWeights
+func (w HealthWeights) WithDefaults() HealthWeights {
+	d := DefaultHealthWeights()
+	if w.CPU == 0 {
+		w.CPU = d.CPU
+	}
+	if w.Memory == 0 {
+		w.Memory = d.Memory
+	}
+	if w.ErrorRate == 0 {
+		w.ErrorRate = d.ErrorRate
+	}
+	if w.Latency == 0 {
+		w.Latency = d.Latency
+	}
+	if w.CPUTrend == 0 {
+		w.CPUTrend = d.CPUTrend
+	}
+	if w.MemoryTrend == 0 {
+		w.MemoryTrend = d.MemoryTrend
+	}
+	return w
+}
+
+// CalibrationTable maps a detector's raw confidence to a calibrated one.
+// Points, when set, define a monotone piecewise-linear curve and take
+// precedence over Multiplier; a zero Multiplier is the identity.
+type CalibrationTable struct {
+	Multiplier float64            `yaml:"multiplier" json:"multiplier,omitempty"`
+	Points     []CalibrationPoint `yaml:"points" json:"points,omitempty"`
+}
+
+// CalibrationPoint is one knot of a calibration curve, both values in 0-100
+type CalibrationPoint struct {
+	Raw        float64 `yaml:"raw" json:"raw"`
+	Calibrated float64 `yaml:"calibrated" json:"calibrated"`
+}
+
+// Apply calibrates a raw confidence. Below the first point and above the
+// last the curve is flat; the result is clamped to 0-100.
+func (t CalibrationTable) Apply(raw float64) float64 {
+	var calibrated float64
+	switch n := len(t.Points); {
+	case n > 0:
+		i := sort.Search(n, func(i int) bool { return t.Points[i].Raw >= raw })
+		switch {
+		case i == 0:
+			calibrated = t.Points[0].Calibrated
+		case i == n:
+			calibrated = t.Points[n-1].Calibrated
+		default:
+			lo, hi := t.Points[i-1], t.Points[i]
+			calibrated = lo.Calibrated + (raw-lo.Raw)/(hi.Raw-lo.Raw)*(hi.Calibrated-lo.Calibrated)
+		}
+	case t.Multiplier > 0:
+		calibrated = raw * t.Multiplier
+	default:
+		return raw
+	}
+	return math.Max(0, math.Min(100, calibrated))
+}
+
+// ScoringWeights weigh problems in the advanced analysis. Map entries
+// override the defaults from DefaultScoringWeights key by key; zero scalar
+// fields take the default.
+type ScoringWeights struct {
+	// TypeMultipliers scale impact by detection type (MEMORY_LEAK, ...),
+	// reflecting how far each problem tends to spread
+	TypeMultipliers map[string]float64 `yaml:"type_multipliers"`
+	// SeverityWeights scale the primary detection's confidence into impact
+	SeverityWeights map[string]float64 `yaml:"severity_weights"`
+
+	BreadthBonus float64 `yaml:"breadth_bonus"` // impact added per additional detected issue
+	ImpactWeight float64 `yaml:"impact_weight"` // share of impact in the priority score
+	HealthWeight float64 `yaml:"health_weight"` // share of the health deficit in the priority score
+	ActionBonus  float64 `yaml:"action_bonus"`  // priority added when action is required
+}
+
+// DefaultScoringWeights returns the built-in weights
+func DefaultScoringWeights() ScoringWeights {
+	return ScoringWeights{
+		TypeMultipliers: map[string]float64{
+			"CASCADING_FAILURE":   1.5,
+			"RESOURCE_EXHAUSTION": 1.3,
+			"MEMORY_LEAK":         1.2,
+			"EXTERNAL_FAILURE":    1.1,
+			"DEPLOYMENT_BUG":      1.0,
+		},
+		SeverityWeights: map[string]float64{
+			"CRITICAL": 1.0,
+			"HIGH":     0.75,
+			"MEDIUM":   0.5,
+			"LOW":      0.25,
+			"NONE":     0,
+		},
+		BreadthBonus: 10,
+		ImpactWeight: 0.6,
+		HealthWeight: 0.3,
+		ActionBonus:  10,
+	}
+}
+
+// WithDefaults merges w over DefaultScoringWeights
+func (w ScoringWeights) WithDefaults() ScoringWeights {
+	d := DefaultScoringWeights()
+	for k, v := range w.TypeMultipliers {
+		d.TypeMultipliers[k] = v
+	}
+	for k, v := range w.SeverityWeights {
+		d.SeverityWeights[k] = v
+	}
+	w.TypeMultipliers, w.SeverityWeights = d.TypeMultipliers, d.SeverityWeights
+	if w.BreadthBonus == 0 {
+		w.BreadthBonus = d.BreadthBonus
+	}
+	if w.ImpactWeight == 0 {
+		w.ImpactWeight = d.ImpactWeight
+	}
+	if w.HealthWeight == 0 {
+		w.HealthWeight = d.HealthWeight
+	}
+	if w.ActionBonus == 0 {
+		w.ActionBonus = d.ActionBonus
+	}
+	return w
+}
+
+// EscalationRule escalates notifications at or above Severity when the error
+// budget burn rate is at least MinBurnRate
+type EscalationRule struct {
+	Severity     string  `yaml:"severity"`
+	MinBurnRate  float64 `yaml:"min_burn_rate"`
+	Level        string  `yaml:"level"`
+	Page         bool    `yaml:"page"`
+	BumpSeverity bool    `yaml:"bump_severity"` // raise the notification one severity level
+}
+
+// MaintenanceWindow is either a one-off window (Start/End, RFC3339) or a
+// recurring daily one ("HH:MM-HH:MM", may cross midnight) optionally limited
+// to some weekdays. An empty Service applies to every service.
+type MaintenanceWindow struct {
+	Service  string   `yaml:"service" json:"service,omitempty"`
+	Start    string   `yaml:"start" json:"start,omitempty"`
+	End      string   `yaml:"end" json:"end,omitempty"`
+	Daily    string   `yaml:"daily" json:"daily,omitempty"`
+	Days     []string `yaml:"days" json:"days,omitempty"` // mon, tue, ... (default every day)
+	Timezone string   `yaml:"timezone" json:"timezone,omitempty"`
+	Reason   string   `yaml:"reason" json:"reason,omitempty"`
+}
+
+// ParseDailyRange parses "HH:MM-HH:MM" into offsets from midnight
+func ParseDailyRange(s string) (start, end time.Duration, err error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("daily window %q must look like HH:MM-HH:MM", s)
+	}
+	if start, err = parseClock(strings.TrimSpace(from)); err != nil {
+		return 0, 0, err
+	}
+	if end, err = parseClock(strings.TrimSpace(to)); err != nil {
+		return 0, 0, err
+	}
+	if start == end {
+		return 0, 0, fmt.Errorf("daily window %q is empty", s)
+	}
+	return start, end, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ParseWeekday parses a three-letter weekday abbreviation (case-insensitive)
+func ParseWeekday(s string) (time.Weekday, error) {
+	d, ok := weekdays[strings.ToLower(strings.TrimSpace(s))]
+	if !ok {
+		return 0, fmt.Errorf("invalid weekday %q (use mon, tue, ...)", s)
+	}
+	return d, nil
+}
+
+// WebhookConfig is a generic HTTP notification target. Template is a Go
+// text/template rendering the JSON body from a notify.Notification; empty
+// posts the notification as JSON.
+type WebhookConfig struct {
+	Name     string            `yaml:"name"`
+	URL      string            `yaml:"url"`
+	Headers  map[string]string `yaml:"headers"` // values expand ${ENV_VARS}
+	Template string            `yaml:"template"`
+	Timeout  string            `yaml:"timeout"` // per attempt (default 5s)
+	Retries  int               `yaml:"retries"` // extra attempts on failure (default 2)
+}
+
+// DependencyCheck is a directly observable health signal for one external
+// dependency. HealthQuery is an alerting-style PromQL expression: the
+// dependency is unhealthy while it returns any series, e.g.
+// `up{job="postgres"} == 0`.
+type DependencyCheck struct {
+	Name        string `yaml:"name"`
+	HealthQuery string `yaml:"health_query"`
+}
+
+// WebhookTemplateFuncs are available in webhook templates: {{json .X}}
+// renders X as a JSON value, so strings are quoted and escaped
+var WebhookTemplateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// DetectorWindows holds the feature window of each enhanced detector, keyed
+// by detector name. Empty values take the defaults from DefaultDetectorWindows.
+type DetectorWindows struct {
+	MemoryLeak         string `yaml:"memory_leak"`
+	ResourceExhaustion string `yaml:"resource_exhaustion"`
+	DeploymentBug      string `yaml:"deployment_bug"`
+	ExternalFailure    string `yaml:"external_failure"`
+	CascadeFailure     string `yaml:"cascade_failure"`
+	GCPressure         string `yaml:"gc_pressure"`
+}
+
+// DefaultDetectorWindows returns the built-in windows
+func DefaultDetectorWindows() DetectorWindows {
+	return DetectorWindows{
+		MemoryLeak:         "30m",
+		ResourceExhaustion: "15m",
+		DeploymentBug:      "20m",
+		ExternalFailure:    "15m",
+		CascadeFailure:     "20m",
+		GCPressure:         "30m",
+	}
+}
+
+// WithDefaults fills empty fields from DefaultDetectorWindows
+func (w DetectorWindows) WithDefaults() DetectorWindows {
+	d := DefaultDetectorWindows()
+	if w.MemoryLeak == "" {
+		w.MemoryLeak = d.MemoryLeak
+	}
+	if w.ResourceExhaustion == "" {
+		w.ResourceExhaustion = d.ResourceExhaustion
+	}
+	if w.DeploymentBug == "" {
+		w.DeploymentBug = d.DeploymentBug
+	}
+	if w.ExternalFailure == "" {
+		w.ExternalFailure = d.ExternalFailure
+	}
+	if w.CascadeFailure == "" {
+		w.CascadeFailure = d.CascadeFailure
+	}
+	if w.GCPressure == "" {
+		w.GCPressure = d.GCPressure
+	}
+	return w
+}
+
+// For returns the window configured for the named detector, or "" for an
+// unknown name
+func (w DetectorWindows) For(detector string) string {
+	switch detector {
+	case "memory_leak":
+		return w.MemoryLeak
+	case "resource_exhaustion":
+		return w.ResourceExhaustion
+	case "deployment_bug":
+		return w.DeploymentBug
+	case "external_failure":
+		return w.ExternalFailure
+	case "cascade_failure":
+		return w.CascadeFailure
+	case "gc_pressure":
+		return w.GCPressure
+	}
+	return ""
+}
+
+// DampeningFactors are the confidence multipliers, in (0, 1], of the enhanced
+// detectors' quality gates. Zero values take the defaults from
+// DefaultDampeningFactors; 1 turns a gate's dampening off.
+type DampeningFactors struct {
+	UnconfirmedTrend   float64 `yaml:"unconfirmed_trend"`   // memory growth not confirmed across the window thirds
+	MemoryLeak         float64 `yaml:"memory_leak"`         // fewer than 2 quality signals
+	ResourceExhaustion float64 `yaml:"resource_exhaustion"` // fewer than 2 quality signals and no saturated resource
+	DeploymentBug      float64 `yaml:"deployment_bug"`      // fewer than 2 quality signals
+	ExternalFailure    float64 `yaml:"external_failure"`    // no external pattern, failing dependency or 3 quality signals
+	CascadeFailure     float64 `yaml:"cascade_failure"`     // fewer than 3 degraded metrics or 2 quality signals
+}
+
+// DefaultDampeningFactors returns the built-in factors
+func DefaultDampeningFactors() DampeningFactors {
+	return DampeningFactors{
+		UnconfirmedTrend:   0.8,
+		MemoryLeak:         0.7,
+		ResourceExhaustion: 0.75,
+		DeploymentBug:      0.7,
+		ExternalFailure:    0.65,
+		CascadeFailure:     0.75,
+	}
+}
+
+// WithDefaults fills zero fields from DefaultDampeningFactors
+func (f DampeningFactors) WithDefaults() DampeningFactors {
+	d := DefaultDampeningFactors()
+	if f.UnconfirmedTrend == 0 {
+		f.UnconfirmedTrend = d.UnconfirmedTrend
+	}
+	if f.MemoryLeak == 0 {
+		f.MemoryLeak = d.MemoryLeak
+	}
+	if f.ResourceExhaustion == 0 {
+		f.ResourceExhaustion = d.ResourceExhaustion
+	}
+	if f.DeploymentBug == 0 {
+		f.DeploymentBug = d.DeploymentBug
+	}
+	if f.ExternalFailure == 0 {
+		f.ExternalFailure = d.ExternalFailure
+	}
+	if f.CascadeFailure == 0 {
+		f.CascadeFailure = d.CascadeFailure
+	}
+	return f
+}
+
+// For returns the weak-signal factor of the named detector, or 1 for a
+// detector without one
+func (f DampeningFactors) For(detector string) float64 {
+	switch detector {
+	case "memory_leak":
+		return f.MemoryLeak
+	case "resource_exhaustion":
+		return f.ResourceExhaustion
+	case "deployment_bug":
+		return f.DeploymentBug
+	case "external_failure":
+		return f.ExternalFailure
+	case "cascade_failure":
+		return f.CascadeFailure
+	}
+	return 1
+}
+
+// ServiceThresholds tunes detectors for a single service. Zero values fall
+// back to the built-in defaults.
+type ServiceThresholds struct {
+	// RequireBothResources makes resource exhaustion fire only when CPU and
+	// memory are high together (default true). CPU-bound batch jobs can set
+	// it to false so a single saturated resource is enough.
+	RequireBothResources *bool `yaml:"require_both_resources"`
+
+	// SingleResourceThreshold is the sustained usage (%) a lone resource must
+	// exceed when RequireBothResources is false (default 92)
+	SingleResourceThreshold float64 `yaml:"single_resource_threshold"`
+
+	// SmoothingWindow / SmoothingMethod override analyzer.smoothing_* for
+	// this service; a window of 0 keeps the global setting, -1 disables it
+	SmoothingWindow int    `yaml:"smoothing_window"`
+	SmoothingMethod string `yaml:"smoothing_method"`
+
+	// LatencySLOMs is the service's P99 latency target in milliseconds.
+	// With it set, external failure detection scores latency and derives
+	// severity from P99 as a multiple of the target instead of from fixed
+	// millisecond cutoffs.
+	LatencySLOMs float64 `yaml:"latency_slo_ms"`
+
+	// ErrorRatioThreshold is the share of requests failing (0-1) that is as
+	// bad as analyzer.error_rate_threshold errors/min. With it set and a
+	// request_rate series stored, detectors judge errors by errors/requests
+	// rather than absolute errors/min. ErrorRatioMinRate (errors/min,
+	// default 1) must still be reached, so a trickle of requests can't fire
+	// on a single failure.
+	ErrorRatioThreshold float64 `yaml:"error_ratio_threshold"`
+	ErrorRatioMinRate   float64 `yaml:"error_ratio_min_rate"`
+}
+
+// BothResourcesRequired reports whether resource exhaustion needs CPU and memory high together
+func (t ServiceThresholds) BothResourcesRequired() bool {
+	return t.RequireBothResources == nil || *t.RequireBothResources
+}
+
+// SingleResourceLimit returns the saturation threshold for single-resource detection
+func (t ServiceThresholds) SingleResourceLimit() float64 {
+	if t.SingleResourceThreshold > 0 {
+		return t.SingleResourceThreshold
+	}
+	return 92
+}
+
+// ThresholdsFor returns the overrides configured for a service, or defaults
+func (c *Config) ThresholdsFor(serviceName string) ServiceThresholds {
+	if c == nil {
+		return ServiceThresholds{}
+	}
+	return c.Thresholds[serviceName]
+}
+
+// AutoRemediates reports whether actuator actions for the service are
+// executed rather than only recommended
+func (c *Config) AutoRemediates(serviceName string) bool {
+	if c == nil {
+		return false
+	}
+	for _, s := range c.Actuator.AutoRemediate {
+		if s == "*" || s == serviceName {
+			return true
+		}
+	}
+	return false
+}
+
+// ServiceResources describes the container limits a service runs under
+type ServiceResources struct {
+	// MemoryLimitMB is the container memory limit in MiB. With it set, OOM
+	// projections use the memory_usage_mb series and run to this limit
+	// instead of to 100%.
+	MemoryLimitMB float64 `yaml:"memory_limit_mb"`
+}
+
+// MemoryLimitFor returns the configured memory limit (MiB) for a service, or 0
+func (c *Config) MemoryLimitFor(serviceName string) float64 {
+	if c == nil {
+		return 0
+	}
+	return c.Resources[serviceName].MemoryLimitMB
+}
+
+// MetricTransform converts a metric's values as value*scale + offset. With
+// PercentOfMemoryLimit the result, which must then be in MiB, is further
+// expressed as a percentage of the service's memory_limit_mb; services
+// without a limit read the metric unconverted.
+type MetricTransform struct {
+	Scale                *float64 `yaml:"scale"` // default 1
+	Offset               float64  `yaml:"offset"`
+	Unit                 string   `yaml:"unit"` // unit after conversion, reported in evidence
+	PercentOfMemoryLimit bool     `yaml:"percent_of_memory_limit"`
 }
 
-// LoadConfig reads and validates configuration from YAML file
+// ScaleOrDefault returns Scale, or 1 when unset
+func (t MetricTransform) ScaleOrDefault() float64 {
+	if t.Scale == nil {
+		return 1
+	}
+	return *t.Scale
+}
+
+// Smoothing methods
+const (
+	SmoothingSMA = "sma"
+	SmoothingEMA = "ema"
+)
+
+// Spikiness methods
+const (
+	SpikinessCV         = "cv"
+	SpikinessPercentile = "percentile"
+)
+
+// Percentile methods
+const (
+	PercentileInterpolated = "interpolated"
+	PercentileNearestRank  = "nearest_rank"
+)
+
+// Anomaly scoring methods
+const (
+	AnomalyZScore = "zscore"
+	AnomalyMAD    = "mad"
+)
+
+// Metric types
+const (
+	MetricTypeGauge   = "gauge"
+	MetricTypeCounter = "counter"
+)
+
+// GroupOf returns the group configured for a service, or "" when
+// service_groups does not list it
+func (c *Config) GroupOf(serviceName string) string {
+	return c.ServiceGroups[serviceName]
+}
+
+// ServicesInGroup returns the services service_groups assigns to group,
+// ordered by name
+func (c *Config) ServicesInGroup(group string) []string {
+	var services []string
+	for service, g := range c.ServiceGroups {
+		if g == group {
+			services = append(services, service)
+		}
+	}
+	sort.Strings(services)
+	return services
+}
+
+// WatchedNamespaces returns the Kubernetes namespaces to watch:
+// kubernetes.namespaces when set, otherwise kubernetes.namespace
+func (c *Config) WatchedNamespaces() []string {
+	if len(c.Kubernetes.Namespaces) > 0 {
+		return c.Kubernetes.Namespaces
+	}
+	if c.Kubernetes.Namespace == "" {
+		return []string{"default"}
+	}
+	return []string{c.Kubernetes.Namespace}
+}
+
+// SmoothingFor returns the smoothing method and window for a service. A
+// window of 0 means smoothing is off.
+func (c *Config) SmoothingFor(serviceName string) (string, int) {
+	if c == nil {
+		return SmoothingSMA, 0
+	}
+
+	method, window := c.Analyzer.SmoothingMethod, c.Analyzer.SmoothingWindow
+	if t, ok := c.Thresholds[serviceName]; ok {
+		if t.SmoothingWindow != 0 {
+			window = t.SmoothingWindow
+		}
+		if t.SmoothingMethod != "" {
+			method = t.SmoothingMethod
+		}
+	}
+
+	if window < 0 {
+		window = 0
+	}
+	if method == "" {
+		method = SmoothingSMA
+	}
+	return method, window
+}
+
+// LoadConfig reads and validates configuration from YAML file. Defaults and
+// environment overrides are applied before validation, so the final values
+// are what gets checked.
 func LoadConfig(path string) (*Config, error) {
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		return nil, fmt.Errorf("config file does not exist: %s", path)
@@ -71,70 +1059,673 @@ func LoadConfig(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to parse config: %w", err)
 	}
 
+	config.ApplyDefaults()
+	config.ApplyEnvOverrides()
+
 	if err := config.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid configuration: %w", err)
+		return nil, fmt.Errorf("invalid configuration %s: %w", path, err)
 	}
 
-	config.ApplyEnvOverrides()
 	return &config, nil
 }
 
-// Validate checks if configuration values are valid
+// ApplyDefaults fills optional fields left empty in the YAML
+func (c *Config) ApplyDefaults() {
+	if c.App.LogLevel == "" {
+		c.App.LogLevel = "info"
+	}
+	if c.Database.Port == 0 {
+		c.Database.Port = 5432
+	}
+	if c.Database.MaxConnections == 0 {
+		c.Database.MaxConnections = 25
+	}
+	if c.Database.Pool.MaxConns == 0 {
+		c.Database.Pool.MaxConns = c.Database.MaxConnections
+	}
+	if c.Prometheus.ScrapeInterval == "" {
+		c.Prometheus.ScrapeInterval = "10s"
+	}
+	if c.Prometheus.ScrapeTimeout == "" {
+		c.Prometheus.ScrapeTimeout = "10s"
+	}
+	if c.Prometheus.QueryTimeout == "" {
+		c.Prometheus.QueryTimeout = "10s"
+	}
+	if len(c.Prometheus.RemoteWrite.ServiceLabels) == 0 {
+		c.Prometheus.RemoteWrite.ServiceLabels = []string{"service", "job"}
+	}
+	if c.Kubernetes.Namespace == "" {
+		c.Kubernetes.Namespace = "default"
+	}
+	if c.Kubernetes.MetricsInterval == "" {
+		c.Kubernetes.MetricsInterval = "30s"
+	}
+	if c.Observer.MetricsInterval == "" {
+		c.Observer.MetricsInterval = "10s"
+	}
+	if c.Observer.RetentionPeriod == "" {
+		c.Observer.RetentionPeriod = "24h"
+	}
+	if c.Analyzer.CPUThreshold == 0 {
+		c.Analyzer.CPUThreshold = 85
+	}
+	if c.Analyzer.MemoryThreshold == 0 {
+		c.Analyzer.MemoryThreshold = 90
+	}
+	if c.Analyzer.ErrorRateThreshold == 0 {
+		c.Analyzer.ErrorRateThreshold = 15
+	}
+	if c.Analyzer.LatencyThreshold == 0 {
+		c.Analyzer.LatencyThreshold = 2000
+	}
+	if c.Analyzer.StaleAfter == "" {
+		c.Analyzer.StaleAfter = "3m"
+	}
+	if c.Analyzer.PerDetectorTimeout == "" {
+		c.Analyzer.PerDetectorTimeout = "10s"
+	}
+	if c.Analyzer.DiagnosisCacheTTL == "" {
+		c.Analyzer.DiagnosisCacheTTL = "5s"
+	}
+	if c.Analyzer.MaxFeatureWindow == "" {
+		c.Analyzer.MaxFeatureWindow = "24h"
+	}
+	if c.Analyzer.MaxSeriesPoints == 0 {
+		c.Analyzer.MaxSeriesPoints = 1000
+	}
+	if c.Analyzer.TriageMinSeverity == "" {
+		c.Analyzer.TriageMinSeverity = "LOW"
+	}
+	c.Analyzer.Windows = c.Analyzer.Windows.WithDefaults()
+	c.Analyzer.Dampening = c.Analyzer.Dampening.WithDefaults()
+	if c.Analyzer.SnapshotInterval == "" {
+		c.Analyzer.SnapshotInterval = "1h"
+	}
+	if c.Analyzer.TransitionDwell == 0 {
+		c.Analyzer.TransitionDwell = 2
+	}
+	if c.Analyzer.TransitionHysteresis == 0 {
+		c.Analyzer.TransitionHysteresis = 5
+	}
+	if c.Analyzer.CompositeMinConfidence == 0 {
+		c.Analyzer.CompositeMinConfidence = 60
+	}
+	if c.Cascade.MaxCandidates == 0 {
+		c.Cascade.MaxCandidates = 5
+	}
+	if c.Notifications.MaxActions == 0 {
+		c.Notifications.MaxActions = 3
+	}
+	if c.Actuator.MinConfidence == 0 {
+		c.Actuator.MinConfidence = 70
+	}
+	if c.Actuator.MaxActions == 0 {
+		c.Actuator.MaxActions = 3
+	}
+	if c.Actuator.Window == "" {
+		c.Actuator.Window = "10m"
+	}
+	if c.Cascade.CacheTTL == "" {
+		c.Cascade.CacheTTL = "2m"
+	}
+	if c.Incidents.ResolveAfter == "" {
+		c.Incidents.ResolveAfter = "10m"
+	}
+	if c.Incidents.VerifyAfter == "" {
+		c.Incidents.VerifyAfter = "5m"
+	}
+	if c.Anomaly.Interval == "" {
+		c.Anomaly.Interval = "1m"
+	}
+	if c.Anomaly.Window == "" {
+		c.Anomaly.Window = "30m"
+	}
+	if len(c.Anomaly.Metrics) == 0 {
+		c.Anomaly.Metrics = []string{"cpu_usage", "memory_usage", "error_rate", "response_time"}
+	}
+	if c.Anomaly.Method == "" {
+		c.Anomaly.Method = AnomalyZScore
+	}
+	if c.Anomaly.Threshold == 0 {
+		c.Anomaly.Threshold = 3.5
+	}
+	if c.HTTP.MaxBodyBytes == 0 {
+		c.HTTP.MaxBodyBytes = 1 << 20
+	}
+	if c.Ingest.MaxItems == 0 {
+		c.Ingest.MaxItems = 5000
+	}
+	if c.Ingest.BatchSize == 0 {
+		c.Ingest.BatchSize = 1000
+	}
+	if c.Ingest.BufferCapacity == 0 {
+		c.Ingest.BufferCapacity = 50000
+	}
+	if c.Ingest.FlushInterval == "" {
+		c.Ingest.FlushInterval = "2s"
+	}
+	if c.Reports.TopServices == 0 {
+		c.Reports.TopServices = 5
+	}
+	if c.Storage.MaxLabelKeys == 0 {
+		c.Storage.MaxLabelKeys = 32
+	}
+	if c.Storage.MaxLabelBytes == 0 {
+		c.Storage.MaxLabelBytes = 4096
+	}
+	if c.Decision.ConfidenceThreshold == 0 {
+		c.Decision.ConfidenceThreshold = 80
+	}
+	c.RiskThresholds = c.RiskThresholds.WithDefaults()
+	c.Health.Weights = c.Health.Weights.WithDefaults()
+}
+
+// ValidationError lists every problem found in a configuration
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Problems) == 1 {
+		return e.Problems[0]
+	}
+	return fmt.Sprintf("%d problems:\n  - %s", len(e.Problems), strings.Join(e.Problems, "\n  - "))
+}
+
+func (e *ValidationError) addf(format string, args ...interface{}) {
+	e.Problems = append(e.Problems, fmt.Sprintf(format, args...))
+}
+
+// checkDuration records a problem unless value is empty or a positive duration
+func (e *ValidationError) checkDuration(field, value string) {
+	if value == "" {
+		return
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		e.addf("%s is not a valid duration: %q", field, value)
+		return
+	}
+	if d <= 0 {
+		e.addf("%s must be greater than 0", field)
+	}
+}
+
+// Validate checks if configuration values are valid. It reports every
+// problem at once as a *ValidationError rather than stopping at the first.
 func (c *Config) Validate() error {
+	errs := &ValidationError{}
+
 	if c.App.Name == "" {
-		return fmt.Errorf("app.name cannot be empty")
+		errs.addf("app.name cannot be empty")
 	}
 	if c.App.Version == "" {
-		return fmt.Errorf("app.version cannot be empty")
+		errs.addf("app.version cannot be empty")
 	}
 	validLogLevels := map[string]bool{"debug": true, "info": true, "warn": true, "error": true}
 	if !validLogLevels[c.App.LogLevel] {
-		return fmt.Errorf("app.log_level must be one of: debug, info, warn, error")
+		errs.addf("app.log_level must be one of: debug, info, warn, error")
+	}
+	if c.App.LogFormat != "" && c.App.LogFormat != "json" && c.App.LogFormat != "console" {
+		errs.addf("app.log_format must be one of: json, console")
 	}
 
 	if c.Database.Host == "" {
-		return fmt.Errorf("database.host cannot be empty")
+		errs.addf("database.host cannot be empty")
 	}
 	if c.Database.Port <= 0 || c.Database.Port > 65535 {
-		return fmt.Errorf("database.port must be between 1 and 65535")
+		errs.addf("database.port must be between 1 and 65535")
 	}
 	if c.Database.User == "" {
-		return fmt.Errorf("database.user cannot be empty")
+		errs.addf("database.user cannot be empty")
 	}
 	if c.Database.DBName == "" {
-		return fmt.Errorf("database.dbname cannot be empty")
+		errs.addf("database.dbname cannot be empty")
 	}
 	if c.Database.MaxConnections <= 0 {
-		return fmt.Errorf("database.max_connections must be positive")
+		errs.addf("database.max_connections must be positive")
+	}
+	if r := c.Database.ReadReplicaURL; r != "" {
+		if u, err := url.Parse(r); err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") || u.Host == "" {
+			errs.addf("database.read_replica_url must be a postgres:// URL with a host")
+		}
+	}
+	if c.Database.Pool.MaxConns < 0 || c.Database.Pool.MinConns < 0 {
+		errs.addf("database.pool connection counts must be non-negative")
+	}
+	if c.Database.Pool.MaxConns > 0 && c.Database.Pool.MinConns > c.Database.Pool.MaxConns {
+		errs.addf("database.pool.min_conns must not exceed max_conns")
+	}
+	errs.checkDuration("database.pool.max_conn_lifetime", c.Database.Pool.MaxConnLifetime)
+	errs.checkDuration("database.pool.max_conn_idle_time", c.Database.Pool.MaxConnIdleTime)
+	errs.checkDuration("database.pool.health_check_period", c.Database.Pool.HealthCheckPeriod)
+	errs.checkDuration("database.pool.connect_timeout", c.Database.Pool.ConnectTimeout)
+	switch c.Database.Pool.QueryExecMode {
+	case "", "cache_statement", "cache_describe", "describe_exec", "exec", "simple_protocol":
+	default:
+		errs.addf("database.pool.query_exec_mode must be one of: cache_statement, cache_describe, describe_exec, exec, simple_protocol")
+	}
+	if c.Database.Pool.StatementCacheCapacity < 0 {
+		errs.addf("database.pool.statement_cache_capacity must be non-negative")
 	}
 
 	if c.Prometheus.URL == "" {
-		return fmt.Errorf("prometheus.url cannot be empty")
+		errs.addf("prometheus.url cannot be empty")
+	} else if !strings.HasPrefix(c.Prometheus.URL, "http://") && !strings.HasPrefix(c.Prometheus.URL, "https://") {
+		errs.addf("prometheus.url must start with http:// or https://")
+	}
+	errs.checkDuration("prometheus.scrape_interval", c.Prometheus.ScrapeInterval)
+	errs.checkDuration("prometheus.scrape_timeout", c.Prometheus.ScrapeTimeout)
+	errs.checkDuration("prometheus.query_timeout", c.Prometheus.QueryTimeout)
+	for metric, interval := range c.Prometheus.MetricIntervals {
+		errs.checkDuration("prometheus.metric_intervals."+metric, interval)
+		base, baseErr := time.ParseDuration(c.Prometheus.ScrapeInterval)
+		if d, err := time.ParseDuration(interval); err == nil && baseErr == nil && d < base {
+			errs.addf("prometheus.metric_intervals.%s must not be shorter than scrape_interval", metric)
+		}
+	}
+	for i, label := range c.Prometheus.RemoteWrite.ServiceLabels {
+		if strings.TrimSpace(label) == "" {
+			errs.addf("prometheus.remote_write.service_labels[%d] is empty", i)
+		}
+	}
+	for from, to := range c.Prometheus.RemoteWrite.MetricNames {
+		if strings.TrimSpace(to) == "" {
+			errs.addf("prometheus.remote_write.metric_names.%s is empty", from)
+		}
+	}
+
+	errs.checkDuration("kubernetes.metrics_interval", c.Kubernetes.MetricsInterval)
+	errs.checkDuration("kubernetes.resource_metrics_interval", c.Kubernetes.ResourceMetricsInterval)
+	seenNamespaces := make(map[string]bool, len(c.Kubernetes.Namespaces))
+	for i, namespace := range c.Kubernetes.Namespaces {
+		switch {
+		case namespace == "":
+			errs.addf("kubernetes.namespaces[%d] must not be empty", i)
+		case seenNamespaces[namespace]:
+			errs.addf("kubernetes.namespaces[%d]: duplicate namespace %q", i, namespace)
+		}
+		seenNamespaces[namespace] = true
 	}
-	if !strings.HasPrefix(c.Prometheus.URL, "http://") && !strings.HasPrefix(c.Prometheus.URL, "https://") {
-		return fmt.Errorf("prometheus.url must start with http:// or https://")
+	if c.Kubernetes.MaxReplicas < 0 {
+		errs.addf("kubernetes.max_replicas must be non-negative")
 	}
 
+	errs.checkDuration("observer.metrics_interval", c.Observer.MetricsInterval)
+	errs.checkDuration("observer.retention_period", c.Observer.RetentionPeriod)
+
 	if c.Analyzer.CPUThreshold <= 0 || c.Analyzer.CPUThreshold > 100 {
-		return fmt.Errorf("analyzer.cpu_threshold must be between 0 and 100")
+		errs.addf("analyzer.cpu_threshold must be between 0 and 100")
 	}
 	if c.Analyzer.MemoryThreshold <= 0 || c.Analyzer.MemoryThreshold > 100 {
-		return fmt.Errorf("analyzer.memory_threshold must be between 0 and 100")
+		errs.addf("analyzer.memory_threshold must be between 0 and 100")
 	}
-	if c.Analyzer.ErrorRateThreshold < 0 {
-		return fmt.Errorf("analyzer.error_rate_threshold must be non-negative")
+	if c.Analyzer.ErrorRateThreshold < 0 || c.Analyzer.ErrorRateThreshold > 100 {
+		errs.addf("analyzer.error_rate_threshold must be between 0 and 100")
 	}
 	if c.Analyzer.LatencyThreshold < 0 {
-		return fmt.Errorf("analyzer.latency_threshold must be non-negative")
+		errs.addf("analyzer.latency_threshold must be non-negative")
+	}
+	if c.Analyzer.MinCorrelationSamples < 0 {
+		errs.addf("analyzer.min_correlation_samples must be non-negative")
+	}
+	errs.checkDuration("analyzer.correlation_window", c.Analyzer.CorrelationWindow)
+	errs.checkDuration("analyzer.stale_after", c.Analyzer.StaleAfter)
+	errs.checkDuration("analyzer.per_detector_timeout", c.Analyzer.PerDetectorTimeout)
+	errs.checkDuration("analyzer.diagnosis_cache_ttl", c.Analyzer.DiagnosisCacheTTL)
+	errs.checkDuration("analyzer.max_feature_window", c.Analyzer.MaxFeatureWindow)
+	errs.checkDuration("analyzer.resample", c.Analyzer.Resample)
+	errs.checkDuration("analyzer.snapshot_interval", c.Analyzer.SnapshotInterval)
+	errs.checkDuration("analyzer.windows.memory_leak", c.Analyzer.Windows.MemoryLeak)
+	errs.checkDuration("analyzer.windows.resource_exhaustion", c.Analyzer.Windows.ResourceExhaustion)
+	errs.checkDuration("analyzer.windows.deployment_bug", c.Analyzer.Windows.DeploymentBug)
+	errs.checkDuration("analyzer.windows.external_failure", c.Analyzer.Windows.ExternalFailure)
+	errs.checkDuration("analyzer.windows.cascade_failure", c.Analyzer.Windows.CascadeFailure)
+	errs.checkDuration("analyzer.windows.gc_pressure", c.Analyzer.Windows.GCPressure)
+	for field, v := range map[string]float64{
+		"unconfirmed_trend":   c.Analyzer.Dampening.UnconfirmedTrend,
+		"memory_leak":         c.Analyzer.Dampening.MemoryLeak,
+		"resource_exhaustion": c.Analyzer.Dampening.ResourceExhaustion,
+		"deployment_bug":      c.Analyzer.Dampening.DeploymentBug,
+		"external_failure":    c.Analyzer.Dampening.ExternalFailure,
+		"cascade_failure":     c.Analyzer.Dampening.CascadeFailure,
+	} {
+		if v < 0 || v > 1 {
+			errs.addf("analyzer.dampening.%s must be between 0 and 1", field)
+		}
+	}
+	if c.Analyzer.MaxSeriesPoints < 0 {
+		errs.addf("analyzer.max_series_points must be non-negative")
+	}
+	switch c.Analyzer.TriageMinSeverity {
+	case "", "LOW", "MEDIUM", "HIGH", "CRITICAL":
+	default:
+		errs.addf("analyzer.triage_min_severity must be one of: LOW, MEDIUM, HIGH, CRITICAL")
+	}
+	if c.Analyzer.TransitionDwell < 0 {
+		errs.addf("analyzer.transition_dwell must be non-negative")
+	}
+	if c.Analyzer.TransitionHysteresis > 50 {
+		errs.addf("analyzer.transition_hysteresis must be at most 50 confidence points")
+	}
+	if c.Analyzer.CompositeMinConfidence < 0 || c.Analyzer.CompositeMinConfidence > 100 {
+		errs.addf("analyzer.composite_min_confidence must be between 0 and 100")
+	}
+	for i, name := range c.Analyzer.DisabledDetectors {
+		if strings.TrimSpace(name) == "" {
+			errs.addf("analyzer.disabled_detectors[%d] is empty", i)
+		}
+	}
+	errs.checkDuration("cascade.cache_ttl", c.Cascade.CacheTTL)
+	if c.Cascade.MaxCandidates < 0 {
+		errs.addf("cascade.max_candidates must be non-negative")
+	}
+	for service, group := range c.ServiceGroups {
+		if group == "" {
+			errs.addf("service_groups.%s must name a group", service)
+		}
+	}
+	for service, checks := range c.Dependencies {
+		for i, check := range checks {
+			if check.Name == "" || check.HealthQuery == "" {
+				errs.addf("dependencies.%s[%d] requires name and health_query", service, i)
+			}
+		}
+	}
+	errs.checkDuration("incidents.resolve_after", c.Incidents.ResolveAfter)
+	errs.checkDuration("incidents.verify_after", c.Incidents.VerifyAfter)
+	errs.checkDuration("anomaly.interval", c.Anomaly.Interval)
+	errs.checkDuration("anomaly.window", c.Anomaly.Window)
+	if m := c.Anomaly.Method; m != "" && m != AnomalyZScore && m != AnomalyMAD {
+		errs.addf("anomaly.method must be %q or %q", AnomalyZScore, AnomalyMAD)
+	}
+	if c.Anomaly.Threshold < 0 {
+		errs.addf("anomaly.threshold must be non-negative")
+	}
+	for i, metric := range c.Anomaly.Metrics {
+		if strings.TrimSpace(metric) == "" {
+			errs.addf("anomaly.metrics[%d] is empty", i)
+		}
+	}
+	errs.checkDuration("ingest.flush_interval", c.Ingest.FlushInterval)
+	if c.Ingest.MaxItems < 0 || c.Ingest.BatchSize < 0 || c.Ingest.BufferCapacity < 0 {
+		errs.addf("ingest.max_items, batch_size and buffer_capacity must be non-negative")
+	}
+	if c.Ingest.MaxItems > c.Ingest.BufferCapacity && c.Ingest.BufferCapacity > 0 {
+		errs.addf("ingest.max_items must not exceed ingest.buffer_capacity")
+	}
+	if c.Reports.TopServices < 0 {
+		errs.addf("reports.top_services must be non-negative")
+	}
+	for i, key := range c.Storage.LabelAllowlist {
+		if key == "" {
+			errs.addf("storage.label_allowlist[%d] is empty", i)
+		}
+	}
+	errs.checkDuration("storage.write_resolution", c.Storage.WriteResolution)
+	if d, err := time.ParseDuration(c.Storage.WriteResolution); err == nil && d > 0 && d < time.Second {
+		errs.addf("storage.write_resolution must be at least 1s")
+	}
+
+	if c.HTTP.MaxBodyBytes < 0 {
+		errs.addf("http.max_body_bytes must be non-negative")
+	}
+	for i, origin := range c.HTTP.AllowedOrigins {
+		if origin == "*" {
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+			errs.addf("http.allowed_origins[%d] must be \"*\" or scheme://host[:port], got %q", i, origin)
+		}
+	}
+
+	switch c.Analyzer.EnsembleStrategy {
+	case "", "agreement", "max", "average":
+	default:
+		errs.addf("analyzer.ensemble_strategy must be one of: agreement, max, average")
+	}
+	if c.Analyzer.DisagreementPenalty < 0 || c.Analyzer.DisagreementPenalty > 1 {
+		errs.addf("analyzer.disagreement_penalty must be between 0 and 1")
 	}
 
 	if c.Decision.ConfidenceThreshold < 0 || c.Decision.ConfidenceThreshold > 100 {
-		return fmt.Errorf("decision.confidence_threshold must be between 0 and 100")
+		errs.addf("decision.confidence_threshold must be between 0 and 100")
+	}
+	if c.Actuator.MinConfidence > 100 {
+		errs.addf("actuator.min_confidence must not exceed 100")
+	}
+	errs.checkDuration("actuator.window", c.Actuator.Window)
+	for i, service := range c.Actuator.AutoRemediate {
+		if strings.TrimSpace(service) == "" {
+			errs.addf("actuator.auto_remediate[%d] is empty", i)
+		}
+	}
+
+	services := make([]string, 0, len(c.Thresholds))
+	for service := range c.Thresholds {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	for _, service := range services {
+		t := c.Thresholds[service]
+		if t.SingleResourceThreshold < 0 || t.SingleResourceThreshold > 100 {
+			errs.addf("thresholds.%s.single_resource_threshold must be between 0 and 100", service)
+		}
+		if t.SmoothingWindow < -1 {
+			errs.addf("thresholds.%s.smoothing_window must be -1 (off), 0 (inherit) or positive", service)
+		}
+		if t.SmoothingMethod != "" && t.SmoothingMethod != SmoothingSMA && t.SmoothingMethod != SmoothingEMA {
+			errs.addf("thresholds.%s.smoothing_method must be one of: sma, ema", service)
+		}
+		if t.LatencySLOMs < 0 {
+			errs.addf("thresholds.%s.latency_slo_ms must be non-negative", service)
+		}
+		if t.ErrorRatioThreshold < 0 || t.ErrorRatioThreshold > 1 {
+			errs.addf("thresholds.%s.error_ratio_threshold must be between 0 and 1", service)
+		}
+		if t.ErrorRatioMinRate < 0 {
+			errs.addf("thresholds.%s.error_ratio_min_rate must be non-negative", service)
+		}
+	}
+
+	if c.Analyzer.SmoothingWindow < 0 {
+		errs.addf("analyzer.smoothing_window must be non-negative")
+	}
+	if m := c.Analyzer.SmoothingMethod; m != "" && m != SmoothingSMA && m != SmoothingEMA {
+		errs.addf("analyzer.smoothing_method must be one of: sma, ema")
+	}
+	if m := c.Analyzer.SpikinessMethod; m != "" && m != SpikinessCV && m != SpikinessPercentile {
+		errs.addf("analyzer.spikiness_method must be one of: cv, percentile")
+	}
+	if m := c.Analyzer.PercentileMethod; m != "" && m != PercentileInterpolated && m != PercentileNearestRank {
+		errs.addf("analyzer.percentile_method must be one of: interpolated, nearest_rank")
 	}
 
+	rt := c.RiskThresholds
+	for field, v := range map[string]float64{
+		"critical_health": rt.CriticalHealth, "high_health": rt.HighHealth, "medium_health": rt.MediumHealth,
+		"high_stress":         rt.HighStress,
+		"critical_confidence": rt.CriticalConfidence, "high_confidence": rt.HighConfidence, "medium_confidence": rt.MediumConfidence,
+	} {
+		if v < 0 || v > 100 {
+			errs.addf("risk_thresholds.%s must be between 0 and 100", field)
+		}
+	}
+	if rt.CriticalHealth > rt.HighHealth || rt.HighHealth > rt.MediumHealth {
+		errs.addf("risk_thresholds must satisfy critical_health <= high_health <= medium_health")
+	}
+	if rt.MediumConfidence > rt.HighConfidence || rt.HighConfidence > rt.CriticalConfidence {
+		errs.addf("risk_thresholds must satisfy medium_confidence <= high_confidence <= critical_confidence")
+	}
+
+	if mc := c.Calibration.MinConfidence; mc < 0 || mc > 100 {
+		errs.addf("calibration.min_confidence must be between 0 and 100")
+	}
+	for typ, table := range c.Calibration.Detectors {
+		field := "calibration.detectors." + typ
+		if table.Multiplier < 0 {
+			errs.addf("%s.multiplier must be non-negative", field)
+		}
+		for i, p := range table.Points {
+			if p.Raw < 0 || p.Raw > 100 || p.Calibrated < 0 || p.Calibrated > 100 {
+				errs.addf("%s.points[%d] must be between 0 and 100", field, i)
+			}
+			if i > 0 && p.Raw <= table.Points[i-1].Raw {
+				errs.addf("%s.points must have strictly increasing raw values", field)
+				break
+			}
+			if i > 0 && p.Calibrated < table.Points[i-1].Calibrated {
+				errs.addf("%s.points must have non-decreasing calibrated values", field)
+				break
+			}
+		}
+	}
+
+	hw := c.Health.Weights
+	for field, v := range map[string]float64{
+		"cpu": hw.CPU, "memory": hw.Memory, "error_rate": hw.ErrorRate, "latency": hw.Latency,
+		"cpu_trend": hw.CPUTrend, "memory_trend": hw.MemoryTrend,
+	} {
+		if v < 0 || v > 100 {
+			errs.addf("health.weights.%s must be between 0 and 100", field)
+		}
+	}
+
+	sw := c.Scoring
+	for typ, v := range sw.TypeMultipliers {
+		if v < 0 {
+			errs.addf("scoring.type_multipliers.%s must be non-negative", typ)
+		}
+	}
+	for severity, v := range sw.SeverityWeights {
+		if v < 0 {
+			errs.addf("scoring.severity_weights.%s must be non-negative", severity)
+		}
+	}
+	for field, v := range map[string]float64{
+		"breadth_bonus": sw.BreadthBonus, "impact_weight": sw.ImpactWeight,
+		"health_weight": sw.HealthWeight, "action_bonus": sw.ActionBonus,
+	} {
+		if v < 0 {
+			errs.addf("scoring.%s must be non-negative", field)
+		}
+	}
+
+	validSeverities := map[string]bool{"LOW": true, "MEDIUM": true, "HIGH": true, "CRITICAL": true}
+	for i, rule := range c.Notifications.Escalation {
+		if !validSeverities[rule.Severity] {
+			errs.addf("notifications.escalation[%d].severity must be one of: LOW, MEDIUM, HIGH, CRITICAL", i)
+		}
+		if rule.MinBurnRate < 0 {
+			errs.addf("notifications.escalation[%d].min_burn_rate must be non-negative", i)
+		}
+		if rule.Level == "" {
+			errs.addf("notifications.escalation[%d].level cannot be empty", i)
+		}
+	}
+
+	for i, w := range c.Notifications.Webhooks {
+		c.validateWebhook(errs, fmt.Sprintf("notifications.webhooks[%d]", i), w)
+	}
+	for i, w := range c.Transitions.Webhooks {
+		c.validateWebhook(errs, fmt.Sprintf("transitions.webhooks[%d]", i), w)
+	}
+
+	for name, r := range c.Resources {
+		if r.MemoryLimitMB < 0 {
+			errs.addf("resources.%s.memory_limit_mb must be non-negative", name)
+		}
+	}
+
+	for i, w := range c.Maintenance {
+		c.validateMaintenanceWindow(errs, fmt.Sprintf("maintenance[%d]", i), w)
+	}
+
+	for canonical, aliases := range c.MetricAliases {
+		for _, alias := range aliases {
+			if strings.TrimSpace(alias) == "" {
+				errs.addf("metric_aliases.%s contains an empty name", canonical)
+				break
+			}
+		}
+	}
+
+	for name, t := range c.MetricTransforms {
+		if t.ScaleOrDefault() == 0 {
+			errs.addf("metric_transforms.%s.scale must not be zero", name)
+		}
+	}
+
+	for name, kind := range c.MetricTypes {
+		if kind != MetricTypeGauge && kind != MetricTypeCounter {
+			errs.addf("metric_types.%s must be %q or %q", name, MetricTypeGauge, MetricTypeCounter)
+		}
+	}
+
+	if len(errs.Problems) > 0 {
+		return errs
+	}
 	return nil
 }
 
+func (c *Config) validateWebhook(errs *ValidationError, field string, w WebhookConfig) {
+	if u, err := url.Parse(w.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs.addf("%s.url must be an absolute http(s) URL", field)
+	}
+	if w.Template != "" {
+		if _, err := template.New(field).Funcs(WebhookTemplateFuncs).Parse(w.Template); err != nil {
+			errs.addf("%s.template: %v", field, err)
+		}
+	}
+	errs.checkDuration(field+".timeout", w.Timeout)
+	if w.Retries < 0 {
+		errs.addf("%s.retries must be non-negative", field)
+	}
+}
+
+func (c *Config) validateMaintenanceWindow(errs *ValidationError, field string, w MaintenanceWindow) {
+	explicit := w.Start != "" || w.End != ""
+	switch {
+	case explicit && w.Daily != "":
+		errs.addf("%s must set either start/end or daily, not both", field)
+		return
+	case !explicit && w.Daily == "":
+		errs.addf("%s must set start/end or daily", field)
+		return
+	}
+
+	if explicit {
+		start, err1 := time.Parse(time.RFC3339, w.Start)
+		end, err2 := time.Parse(time.RFC3339, w.End)
+		if err1 != nil {
+			errs.addf("%s.start must be an RFC3339 time", field)
+		}
+		if err2 != nil {
+			errs.addf("%s.end must be an RFC3339 time", field)
+		}
+		if err1 == nil && err2 == nil && !end.After(start) {
+			errs.addf("%s.end must be after start", field)
+		}
+		return
+	}
+
+	if _, _, err := ParseDailyRange(w.Daily); err != nil {
+		errs.addf("%s.daily: %v", field, err)
+	}
+	for _, d := range w.Days {
+		if _, err := ParseWeekday(d); err != nil {
+			errs.addf("%s.days: %v", field, err)
+		}
+	}
+	if w.Timezone != "" {
+		if _, err := time.LoadLocation(w.Timezone); err != nil {
+			errs.addf("%s.timezone %q is not a known location", field, w.Timezone)
+		}
+	}
+}
+
 // ApplyEnvOverrides applies environment variable overrides
 func (c *Config) ApplyEnvOverrides() {
 	if host := os.Getenv("AURA_DB_HOST"); host != "" {
@@ -149,12 +1740,24 @@ func (c *Config) ApplyEnvOverrides() {
 	if dbname := os.Getenv("AURA_DB_NAME"); dbname != "" {
 		c.Database.DBName = dbname
 	}
+	if replica := os.Getenv("AURA_DB_READ_REPLICA_URL"); replica != "" {
+		c.Database.ReadReplicaURL = replica
+	}
 	if url := os.Getenv("AURA_PROMETHEUS_URL"); url != "" {
 		c.Prometheus.URL = url
 	}
 	if logLevel := os.Getenv("AURA_LOG_LEVEL"); logLevel != "" {
 		c.App.LogLevel = logLevel
 	}
+	if logFormat := os.Getenv("AURA_LOG_FORMAT"); logFormat != "" {
+		c.App.LogFormat = logFormat
+	}
+	if token := os.Getenv("AURA_ADMIN_TOKEN"); token != "" {
+		c.Admin.Token = token
+	}
+	if os.Getenv("AURA_TESTING_ENABLED") == "true" {
+		c.Testing.Enabled = true
+	}
 }
 
 // GetDatabaseURL returns PostgreSQL connection string
@@ -0,0 +1,359 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// minimalConfig sets only the fields Validate requires
+const minimalConfig = `
+app:
+  name: "AURA"
+  version: "test"
+  log_level: "info"
+database:
+  host: "localhost"
+  user: "aura"
+  dbname: "aura_db"
+prometheus:
+  url: "http://localhost:9090"
+`
+
+// clearEnvOverrides unsets the variables ApplyEnvOverrides reads
+func clearEnvOverrides(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{
+		"AURA_DB_HOST", "AURA_DB_USER", "AURA_DB_PASSWORD", "AURA_DB_NAME", "AURA_DB_READ_REPLICA_URL",
+		"AURA_PROMETHEUS_URL", "AURA_LOG_LEVEL", "AURA_LOG_FORMAT", "AURA_ADMIN_TOKEN", "AURA_TESTING_ENABLED",
+	} {
+		t.Setenv(name, "")
+	}
+}
+
+// writeConfig writes a config file into a temporary directory
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "aura.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+// loadProblems loads the config and returns its validation problems
+func loadProblems(t *testing.T, content string) []string {
+	t.Helper()
+
+	_, err := LoadConfig(writeConfig(t, content))
+	if err == nil {
+		return nil
+	}
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("LoadConfig error %v is not a ValidationError", err)
+	}
+	return verr.Problems
+}
+
+func hasProblem(problems []string, substr string) bool {
+	for _, p := range problems {
+		if strings.Contains(p, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestLoadConfigShippedConfig(t *testing.T) {
+	clearEnvOverrides(t)
+
+	if _, err := LoadConfig(filepath.Join("..", "..", "configs", "aura.yaml")); err != nil {
+		t.Fatalf("configs/aura.yaml does not load: %v", err)
+	}
+}
+
+func TestLoadConfigAppliesDefaults(t *testing.T) {
+	clearEnvOverrides(t)
+
+	config, err := LoadConfig(writeConfig(t, minimalConfig))
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+
+	if config.Database.Port != 5432 || config.Database.MaxConnections != 25 || config.Database.Pool.MaxConns != 25 {
+		t.Errorf("database port, max_connections, pool.max_conns = %d, %d, %d, want 5432, 25, 25",
+			config.Database.Port, config.Database.MaxConnections, config.Database.Pool.MaxConns)
+	}
+	if config.Prometheus.ScrapeInterval != "10s" {
+		t.Errorf("prometheus.scrape_interval = %q, want 10s", config.Prometheus.ScrapeInterval)
+	}
+	if config.Analyzer.CPUThreshold != 85 || config.Analyzer.MemoryThreshold != 90 {
+		t.Errorf("analyzer cpu, memory thresholds = %v, %v, want 85, 90", config.Analyzer.CPUThreshold, config.Analyzer.MemoryThreshold)
+	}
+}
+
+func TestLoadConfigMissingDatabase(t *testing.T) {
+	clearEnvOverrides(t)
+
+	withoutDatabase := `
+app:
+  name: "AURA"
+  version: "test"
+  log_level: "info"
+prometheus:
+  url: "http://localhost:9090"
+`
+	problems := loadProblems(t, withoutDatabase)
+
+	for _, want := range []string{"database.host", "database.user", "database.dbname"} {
+		if !hasProblem(problems, want) {
+			t.Errorf("problems %q do not mention %s", problems, want)
+		}
+	}
+}
+
+func TestLoadConfigDatabaseFromEnvironment(t *testing.T) {
+	clearEnvOverrides(t)
+	t.Setenv("AURA_DB_HOST", "db.internal")
+
+	config, err := LoadConfig(writeConfig(t, strings.Replace(minimalConfig, `host: "localhost"`, `host: ""`, 1)))
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if config.Database.Host != "db.internal" {
+		t.Errorf("database.host = %q, want the AURA_DB_HOST override", config.Database.Host)
+	}
+}
+
+func TestLoadConfigReadReplicaFromEnvironment(t *testing.T) {
+	clearEnvOverrides(t)
+	t.Setenv("AURA_DB_READ_REPLICA_URL", "postgres://aura@replica.internal:5432/aura_db")
+
+	config, err := LoadConfig(writeConfig(t, minimalConfig))
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if config.Database.ReadReplicaURL != "postgres://aura@replica.internal:5432/aura_db" {
+		t.Errorf("database.read_replica_url = %q, want the AURA_DB_READ_REPLICA_URL override", config.Database.ReadReplicaURL)
+	}
+}
+
+func TestLoadConfigOutOfRangeThresholds(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   string
+	}{
+		{name: "cpu threshold", config: minimalConfig + "analyzer:\n  cpu_threshold: 150\n", want: "analyzer.cpu_threshold"},
+		{name: "memory threshold", config: minimalConfig + "analyzer:\n  memory_threshold: -5\n", want: "analyzer.memory_threshold"},
+		{name: "error rate threshold", config: minimalConfig + "analyzer:\n  error_rate_threshold: 101\n", want: "analyzer.error_rate_threshold"},
+		{name: "negative latency threshold", config: minimalConfig + "analyzer:\n  latency_threshold: -1\n", want: "analyzer.latency_threshold"},
+		{name: "health weight", config: minimalConfig + "health:\n  weights:\n    latency: 120\n", want: "health.weights.latency"},
+		{name: "negative health weight", config: minimalConfig + "health:\n  weights:\n    cpu: -10\n", want: "health.weights.cpu"},
+		{name: "scrape interval", config: minimalConfig + "  scrape_interval: often\n", want: "prometheus.scrape_interval"},
+		{name: "metric interval", config: minimalConfig + "  metric_intervals:\n    response_time_p99_ms: 1x\n", want: "prometheus.metric_intervals.response_time_p99_ms"},
+		{name: "metric interval below scrape interval", config: minimalConfig + "  scrape_interval: 15s\n  metric_intervals:\n    cpu_usage: 5s\n", want: "must not be shorter than scrape_interval"},
+		{name: "max feature window", config: minimalConfig + "analyzer:\n  max_feature_window: forever\n", want: "analyzer.max_feature_window"},
+		{name: "negative max series points", config: minimalConfig + "analyzer:\n  max_series_points: -1\n", want: "analyzer.max_series_points"},
+		{name: "negative latency slo", config: minimalConfig + "thresholds:\n  checkout:\n    latency_slo_ms: -200\n", want: "thresholds.checkout.latency_slo_ms"},
+		{name: "calibration min confidence", config: minimalConfig + "calibration:\n  min_confidence: 120\n", want: "calibration.min_confidence"},
+		{name: "negative calibration multiplier", config: minimalConfig + "calibration:\n  detectors:\n    MEMORY_LEAK:\n      multiplier: -1\n", want: "calibration.detectors.MEMORY_LEAK.multiplier"},
+		{name: "calibration raw not increasing", config: minimalConfig + "calibration:\n  detectors:\n    MEMORY_LEAK:\n      points:\n        - {raw: 50, calibrated: 40}\n        - {raw: 50, calibrated: 60}\n", want: "strictly increasing raw"},
+		{name: "calibration decreasing", config: minimalConfig + "calibration:\n  detectors:\n    MEMORY_LEAK:\n      points:\n        - {raw: 40, calibrated: 60}\n        - {raw: 80, calibrated: 50}\n", want: "non-decreasing calibrated"},
+		{name: "negative type multiplier", config: minimalConfig + "scoring:\n  type_multipliers:\n    MEMORY_LEAK: -1\n", want: "scoring.type_multipliers.MEMORY_LEAK"},
+		{name: "negative impact weight", config: minimalConfig + "scoring:\n  impact_weight: -0.5\n", want: "scoring.impact_weight"},
+		{name: "triage min severity", config: minimalConfig + "analyzer:\n  triage_min_severity: SEVERE\n", want: "analyzer.triage_min_severity"},
+		{name: "snapshot interval", config: minimalConfig + "analyzer:\n  snapshot_interval: hourly\n", want: "analyzer.snapshot_interval"},
+		{name: "scrape timeout", config: minimalConfig + "  scrape_timeout: 10\n", want: "prometheus.scrape_timeout"},
+		{name: "query timeout", config: minimalConfig + "  query_timeout: slow\n", want: "prometheus.query_timeout"},
+		{name: "spikiness method", config: minimalConfig + "analyzer:\n  spikiness_method: iqr\n", want: "analyzer.spikiness_method"},
+		{name: "verify after", config: minimalConfig + "incidents:\n  verify_after: soon\n", want: "incidents.verify_after"},
+		{name: "percentile method", config: minimalConfig + "analyzer:\n  percentile_method: exact\n", want: "analyzer.percentile_method"},
+		{name: "empty namespace", config: minimalConfig + "kubernetes:\n  namespaces: [shop, \"\"]\n", want: "kubernetes.namespaces[1]"},
+		{name: "duplicate namespace", config: minimalConfig + "kubernetes:\n  namespaces: [shop, billing, shop]\n", want: "duplicate namespace \"shop\""},
+		{name: "negative transition dwell", config: minimalConfig + "analyzer:\n  transition_dwell: -1\n", want: "analyzer.transition_dwell"},
+		{name: "transition hysteresis", config: minimalConfig + "analyzer:\n  transition_hysteresis: 60\n", want: "analyzer.transition_hysteresis"},
+		{name: "pool min above max", config: strings.Replace(minimalConfig, "  user:", "  pool:\n    max_conns: 4\n    min_conns: 8\n  user:", 1), want: "database.pool.min_conns"},
+		{name: "pool lifetime", config: strings.Replace(minimalConfig, "  user:", "  pool:\n    max_conn_lifetime: forever\n  user:", 1), want: "database.pool.max_conn_lifetime"},
+		{name: "metric type", config: minimalConfig + "metric_types:\n  error_count: histogram\n", want: "metric_types.error_count"},
+		{name: "empty disabled detector", config: minimalConfig + "analyzer:\n  disabled_detectors: [memory_leak, \"\"]\n", want: "analyzer.disabled_detectors[1]"},
+		{name: "detector window", config: minimalConfig + "analyzer:\n  windows:\n    deployment_bug: 20\n", want: "analyzer.windows.deployment_bug"},
+		{name: "anomaly method", config: minimalConfig + "anomaly:\n  method: iqr\n", want: "anomaly.method"},
+		{name: "anomaly threshold", config: minimalConfig + "anomaly:\n  threshold: -1\n", want: "anomaly.threshold"},
+		{name: "dampening factor", config: minimalConfig + "analyzer:\n  dampening:\n    external_failure: 1.5\n", want: "analyzer.dampening.external_failure"},
+		{name: "negative dampening factor", config: minimalConfig + "analyzer:\n  dampening:\n    unconfirmed_trend: -0.2\n", want: "analyzer.dampening.unconfirmed_trend"},
+		{name: "read replica scheme", config: strings.Replace(minimalConfig, "  user:", "  read_replica_url: mysql://replica:3306/aura\n  user:", 1), want: "database.read_replica_url"},
+		{name: "read replica without host", config: strings.Replace(minimalConfig, "  user:", "  read_replica_url: postgres:///aura\n  user:", 1), want: "database.read_replica_url"},
+		{name: "empty label allowlist key", config: minimalConfig + "storage:\n  label_allowlist: [pod, \"\"]\n", want: "storage.label_allowlist[1]"},
+		{name: "error ratio threshold", config: minimalConfig + "thresholds:\n  checkout:\n    error_ratio_threshold: 5\n", want: "thresholds.checkout.error_ratio_threshold"},
+		{name: "negative error ratio min rate", config: minimalConfig + "thresholds:\n  checkout:\n    error_ratio_min_rate: -1\n", want: "thresholds.checkout.error_ratio_min_rate"},
+		{name: "query exec mode", config: strings.Replace(minimalConfig, "  user:", "  pool:\n    query_exec_mode: prepared\n  user:", 1), want: "database.pool.query_exec_mode"},
+		{name: "negative statement cache", config: strings.Replace(minimalConfig, "  user:", "  pool:\n    statement_cache_capacity: -1\n  user:", 1), want: "database.pool.statement_cache_capacity"},
+		{name: "bad write resolution", config: minimalConfig + "storage:\n  write_resolution: often\n", want: "storage.write_resolution"},
+		{name: "sub-second write resolution", config: minimalConfig + "storage:\n  write_resolution: 500ms\n", want: "storage.write_resolution must be at least 1s"},
+		{name: "empty auto remediate entry", config: minimalConfig + "actuator:\n  auto_remediate: [checkout, \" \"]\n", want: "actuator.auto_remediate[1]"},
+		{name: "bad resample step", config: minimalConfig + "analyzer:\n  resample: fine\n", want: "analyzer.resample"},
+		{name: "composite min confidence", config: minimalConfig + "analyzer:\n  composite_min_confidence: 120\n", want: "analyzer.composite_min_confidence"},
+		{name: "empty remote write service label", config: minimalConfig + "  remote_write:\n    service_labels: [service, \"\"]\n", want: "prometheus.remote_write.service_labels[1]"},
+		{name: "empty remote write metric name", config: minimalConfig + "  remote_write:\n    metric_names:\n      container_cpu_percent: \"\"\n", want: "prometheus.remote_write.metric_names.container_cpu_percent"},
+		{name: "zero metric transform scale", config: minimalConfig + "metric_transforms:\n  memory_usage:\n    scale: 0\n", want: "metric_transforms.memory_usage.scale"},
+		{name: "empty service group", config: minimalConfig + "service_groups:\n  payments: \"\"\n", want: "service_groups.payments"},
+		{name: "dependency check without query", config: minimalConfig + "dependencies:\n  checkout:\n    - name: postgres\n", want: "dependencies.checkout[0]"},
+		{name: "negative max body bytes", config: minimalConfig + "http:\n  max_body_bytes: -1\n", want: "http.max_body_bytes"},
+		{name: "database port", config: strings.Replace(minimalConfig, "  user:", "  port: 70000\n  user:", 1), want: "database.port"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnvOverrides(t)
+
+			problems := loadProblems(t, tt.config)
+			if !hasProblem(problems, tt.want) {
+				t.Errorf("problems %q do not mention %s", problems, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigReportsEveryProblem(t *testing.T) {
+	clearEnvOverrides(t)
+
+	problems := loadProblems(t, minimalConfig+"analyzer:\n  cpu_threshold: 150\n  memory_threshold: 150\n")
+	if len(problems) != 2 {
+		t.Errorf("problems = %q, want both thresholds reported", problems)
+	}
+}
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	if _, err := LoadConfig(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("LoadConfig succeeded for a missing file")
+	}
+}
+
+func TestServiceGroups(t *testing.T) {
+	c := &Config{ServiceGroups: map[string]string{"payments": "checkout", "cart": "checkout", "search": "discovery"}}
+
+	if got := c.GroupOf("payments"); got != "checkout" {
+		t.Errorf("GroupOf(payments) = %q, want checkout", got)
+	}
+	if got := c.GroupOf("unlisted"); got != "" {
+		t.Errorf("GroupOf(unlisted) = %q, want empty", got)
+	}
+	got := c.ServicesInGroup("checkout")
+	if strings.Join(got, ",") != "cart,payments" {
+		t.Errorf("ServicesInGroup(checkout) = %v, want [cart payments]", got)
+	}
+	if got := c.ServicesInGroup("unknown"); len(got) != 0 {
+		t.Errorf("ServicesInGroup(unknown) = %v, want none", got)
+	}
+}
+
+func TestAutoRemediates(t *testing.T) {
+	var unset *Config
+	if unset.AutoRemediates("checkout") {
+		t.Error("a nil config auto-remediates")
+	}
+	c := &Config{}
+	if c.AutoRemediates("checkout") {
+		t.Error("an empty auto_remediate auto-remediates")
+	}
+	c.Actuator.AutoRemediate = []string{"checkout", "payments"}
+	if !c.AutoRemediates("payments") || c.AutoRemediates("search") {
+		t.Error("auto_remediate should match listed services only")
+	}
+	c.Actuator.AutoRemediate = []string{"*"}
+	if !c.AutoRemediates("search") {
+		t.Error(`"*" should match every service`)
+	}
+}
+
+func TestWatchedNamespaces(t *testing.T) {
+	c := &Config{}
+	if got := c.WatchedNamespaces(); strings.Join(got, ",") != "default" {
+		t.Errorf("WatchedNamespaces = %v, want [default]", got)
+	}
+	c.Kubernetes.Namespace = "shop"
+	if got := c.WatchedNamespaces(); strings.Join(got, ",") != "shop" {
+		t.Errorf("WatchedNamespaces = %v, want [shop]", got)
+	}
+	c.Kubernetes.Namespaces = []string{"billing", "jobs"}
+	if got := c.WatchedNamespaces(); strings.Join(got, ",") != "billing,jobs" {
+		t.Errorf("WatchedNamespaces = %v, want namespaces to replace namespace", got)
+	}
+}
+
+func TestCalibrationTableApply(t *testing.T) {
+	curve := CalibrationTable{Points: []CalibrationPoint{{Raw: 40, Calibrated: 30}, {Raw: 80, Calibrated: 90}}}
+	tests := []struct {
+		name  string
+		table CalibrationTable
+		raw   float64
+		want  float64
+	}{
+		{"identity", CalibrationTable{}, 63, 63},
+		{"multiplier", CalibrationTable{Multiplier: 0.8}, 50, 40},
+		{"multiplier clamped", CalibrationTable{Multiplier: 1.5}, 90, 100},
+		{"below first point", curve, 10, 30},
+		{"on a point", curve, 40, 30},
+		{"interpolated", curve, 60, 60},
+		{"above last point", curve, 95, 90},
+		{"points take precedence", CalibrationTable{Multiplier: 0.1, Points: curve.Points}, 80, 90},
+	}
+	for _, tt := range tests {
+		if got := tt.table.Apply(tt.raw); got != tt.want {
+			t.Errorf("%s: Apply(%v) = %v, want %v", tt.name, tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestScoringWeightsWithDefaults(t *testing.T) {
+	w := ScoringWeights{
+		TypeMultipliers: map[string]float64{"MEMORY_LEAK": 2, "GC_PRESSURE": 1.4},
+		ImpactWeight:    0.8,
+	}.WithDefaults()
+
+	if w.TypeMultipliers["MEMORY_LEAK"] != 2 || w.TypeMultipliers["GC_PRESSURE"] != 1.4 {
+		t.Errorf("type multipliers = %v, want the overrides", w.TypeMultipliers)
+	}
+	if w.TypeMultipliers["CASCADING_FAILURE"] != 1.5 || w.SeverityWeights["HIGH"] != 0.75 {
+		t.Errorf("weights = %+v, want unlisted keys kept from the defaults", w)
+	}
+	if w.ImpactWeight != 0.8 || w.HealthWeight != 0.3 || w.BreadthBonus != 10 || w.ActionBonus != 10 {
+		t.Errorf("scalars = %+v, want impact_weight overridden and the rest defaulted", w)
+	}
+
+	// Merging must not leak into the shared defaults
+	if DefaultScoringWeights().TypeMultipliers["MEMORY_LEAK"] != 1.2 {
+		t.Error("WithDefaults modified DefaultScoringWeights")
+	}
+}
+
+func TestDampeningFactorsWithDefaults(t *testing.T) {
+	f := DampeningFactors{MemoryLeak: 0.5, CascadeFailure: 1}.WithDefaults()
+	defaults := DefaultDampeningFactors()
+
+	if f.MemoryLeak != 0.5 || f.CascadeFailure != 1 {
+		t.Errorf("configured factors = %v/%v, want 0.5/1 kept", f.MemoryLeak, f.CascadeFailure)
+	}
+	if f.UnconfirmedTrend != defaults.UnconfirmedTrend || f.ExternalFailure != defaults.ExternalFailure {
+		t.Errorf("unset factors = %v/%v, want the defaults %v/%v", f.UnconfirmedTrend, f.ExternalFailure, defaults.UnconfirmedTrend, defaults.ExternalFailure)
+	}
+
+	tests := map[string]float64{
+		"memory_leak":         0.5,
+		"resource_exhaustion": defaults.ResourceExhaustion,
+		"deployment_bug":      defaults.DeploymentBug,
+		"external_failure":    defaults.ExternalFailure,
+		"cascade_failure":     1,
+		"gc_pressure":         1, // no weak-signal gate
+	}
+	for detector, want := range tests {
+		if got := f.For(detector); got != want {
+			t.Errorf("For(%s) = %v, want %v", detector, got, want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// staticSections are the top-level config sections that are only read at
+// startup. A reload keeps their running values; changing them needs a restart.
+var staticSections = map[string]bool{
+	"app":        true,
+	"http":       true,
+	"database":   true,
+	"prometheus": true,
+	"kubernetes": true,
+	"observer":   true,
+	"decision":   true,
+	"ingest":     true,
+	"storage":    true,
+	"testing":    true,
+	"admin":      true,
+}
+
+// ConfigStore holds the live configuration. Reload swaps a freshly loaded
+// file in atomically, so readers always see one whole config, old or new.
+type ConfigStore struct {
+	path    string
+	current atomic.Pointer[Config]
+
+	mu        sync.Mutex // serializes reloads
+	listeners []func(*Config)
+}
+
+// ReloadResult lists the top-level sections a reload changed. Sections in
+// RestartRequired differ on disk but kept their running values.
+type ReloadResult struct {
+	Applied         []string `json:"applied"`
+	RestartRequired []string `json:"restart_required"`
+}
+
+// NewConfigStore serves cfg until the file at path is reloaded
+func NewConfigStore(path string, cfg *Config) *ConfigStore {
+	s := &ConfigStore{path: path}
+	s.current.Store(cfg)
+	return s
+}
+
+// Get returns the live configuration. A nil store yields a nil config, which
+// the analyzer treats as "use the defaults".
+func (s *ConfigStore) Get() *Config {
+	if s == nil {
+		return nil
+	}
+	return s.current.Load()
+}
+
+// OnReload registers fn to be called with the new config after each
+// successful reload, for components that build state from it
+func (s *ConfigStore) OnReload(fn func(*Config)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = append(s.listeners, fn)
+}
+
+// Reload re-reads and validates the config file. On error the running config
+// is left untouched. Static sections keep their running values.
+func (s *ConfigStore) Reload() (*ReloadResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next, err := LoadConfig(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReloadResult{Applied: []string{}, RestartRequired: []string{}}
+	old := s.current.Load()
+	oldValue := reflect.ValueOf(old).Elem()
+	nextValue := reflect.ValueOf(next).Elem()
+	for i := 0; i < oldValue.NumField(); i++ {
+		section := strings.Split(oldValue.Type().Field(i).Tag.Get("yaml"), ",")[0]
+		if reflect.DeepEqual(oldValue.Field(i).Interface(), nextValue.Field(i).Interface()) {
+			continue
+		}
+		if staticSections[section] {
+			nextValue.Field(i).Set(oldValue.Field(i))
+			result.RestartRequired = append(result.RestartRequired, section)
+			continue
+		}
+		result.Applied = append(result.Applied, section)
+	}
+
+	s.current.Store(next)
+	for _, fn := range s.listeners {
+		fn(next)
+	}
+	return result, nil
+}
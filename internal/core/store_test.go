@@ -0,0 +1,80 @@
+package core
+
+import (
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestConfigStoreReload(t *testing.T) {
+	clearEnvOverrides(t)
+
+	path := writeConfig(t, minimalConfig+"analyzer:\n  cpu_threshold: 80\n")
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	store := NewConfigStore(path, cfg)
+
+	var reloaded *Config
+	store.OnReload(func(c *Config) { reloaded = c })
+
+	// One hot-reloadable change and one to a section read only at startup
+	// (minimalConfig ends inside the prometheus section)
+	next := minimalConfig + "  scrape_interval: 5s\nanalyzer:\n  cpu_threshold: 70\n"
+	if err := os.WriteFile(path, []byte(next), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := store.Reload()
+	if err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if !slices.Equal(result.Applied, []string{"analyzer"}) || !slices.Equal(result.RestartRequired, []string{"prometheus"}) {
+		t.Errorf("result = %+v, want analyzer applied and prometheus needing a restart", result)
+	}
+
+	live := store.Get()
+	if live.Analyzer.CPUThreshold != 70 {
+		t.Errorf("cpu_threshold = %v, want the reloaded 70", live.Analyzer.CPUThreshold)
+	}
+	if live.Prometheus.ScrapeInterval != cfg.Prometheus.ScrapeInterval {
+		t.Errorf("scrape_interval = %q, want the running %q kept", live.Prometheus.ScrapeInterval, cfg.Prometheus.ScrapeInterval)
+	}
+	if reloaded != live {
+		t.Error("OnReload listener did not receive the new config")
+	}
+	if cfg.Analyzer.CPUThreshold != 80 {
+		t.Error("reload modified the previous config in place")
+	}
+}
+
+func TestConfigStoreReloadRejectsInvalidFile(t *testing.T) {
+	clearEnvOverrides(t)
+
+	path := writeConfig(t, minimalConfig)
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	store := NewConfigStore(path, cfg)
+	called := false
+	store.OnReload(func(*Config) { called = true })
+
+	if err := os.WriteFile(path, []byte(minimalConfig+"analyzer:\n  cpu_threshold: 150\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Reload(); err == nil {
+		t.Fatal("Reload accepted an invalid file")
+	}
+	if store.Get() != cfg || called {
+		t.Error("an invalid file replaced the running config")
+	}
+}
+
+func TestConfigStoreNil(t *testing.T) {
+	var store *ConfigStore
+	if store.Get() != nil {
+		t.Error("Get on a nil store returned a config")
+	}
+}
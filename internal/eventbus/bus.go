@@ -0,0 +1,86 @@
+// Package eventbus decouples event producers (the observer) from consumers
+// (the analyzer) with a small in-process publish/subscribe bus.
+package eventbus
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Event types published on the bus
+const (
+	EventCrashLoop = "CrashLoop"
+)
+
+// Event is a single occurrence published by a producer
+type Event struct {
+	Type      string                 `json:"type"`
+	Service   string                 `json:"service"`
+	Pod       string                 `json:"pod,omitempty"`
+	Namespace string                 `json:"namespace,omitempty"`
+	Payload   map[string]interface{} `json:"payload,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+}
+
+// Bus fans events out to subscribers by type. Publishing never blocks: when a
+// subscriber's buffer is full the event is dropped for that subscriber.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[string][]chan Event
+	logger      *zap.Logger
+}
+
+func New(logger *zap.Logger) *Bus {
+	return &Bus{
+		subscribers: make(map[string][]chan Event),
+		logger:      logger,
+	}
+}
+
+// Subscribe returns a channel receiving every event of the given type
+func (b *Bus) Subscribe(eventType string, buffer int) <-chan Event {
+	ch := make(chan Event, buffer)
+
+	b.mu.Lock()
+	b.subscribers[eventType] = append(b.subscribers[eventType], ch)
+	b.mu.Unlock()
+
+	return ch
+}
+
+// Publish delivers the event to all subscribers of its type
+func (b *Bus) Publish(event Event) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, ch := range b.subscribers[event.Type] {
+		select {
+		case ch <- event:
+		default:
+			b.logger.Warn("Event bus subscriber full, dropping event",
+				zap.String("type", event.Type),
+				zap.String("service", event.Service),
+			)
+		}
+	}
+}
+
+// Close closes every subscriber channel and drops the subscriptions, so
+// publishing after Close is a no-op.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for eventType, subs := range b.subscribers {
+		for _, ch := range subs {
+			close(ch)
+		}
+		delete(b.subscribers, eventType)
+	}
+}
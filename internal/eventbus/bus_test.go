@@ -0,0 +1,28 @@
+package eventbus
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestPublishAfterCloseIsNoOp(t *testing.T) {
+	bus := New(zap.NewNop())
+	ch := bus.Subscribe(EventCrashLoop, 1)
+
+	bus.Publish(Event{Type: EventCrashLoop, Service: "checkout"})
+	if event := <-ch; event.Service != "checkout" || event.Timestamp.IsZero() {
+		t.Fatalf("event = %+v, want the checkout event stamped on publish", event)
+	}
+
+	bus.Close()
+	if _, ok := <-ch; ok {
+		t.Fatal("subscriber channel still open after Close")
+	}
+
+	// Must neither panic on the closed channel nor deliver anything
+	bus.Publish(Event{Type: EventCrashLoop, Service: "payments"})
+	if _, ok := <-ch; ok {
+		t.Error("event delivered after Close")
+	}
+}
@@ -0,0 +1,98 @@
+package notify
+
+import (
+	"context"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+// Escalation levels, matching ExecutiveSummary.EscalationLevel
+const (
+	EscalationNone        = ""
+	EscalationOncall      = "ONCALL"
+	EscalationEngineering = "ENGINEERING"
+	EscalationManagement  = "MANAGEMENT"
+	EscalationExecutive   = "EXECUTIVE"
+)
+
+var severityOrder = []string{"LOW", "MEDIUM", "HIGH", "CRITICAL"}
+
+func severityRank(severity string) int {
+	for i, s := range severityOrder {
+		if s == severity {
+			return i
+		}
+	}
+	return -1
+}
+
+// defaultEscalationMatrix pages when a serious diagnosis coincides with a
+// fast error-budget burn. 14.4x exhausts a 30-day budget in ~2 days, 6x in 5.
+var defaultEscalationMatrix = []core.EscalationRule{
+	{Severity: "CRITICAL", MinBurnRate: 14.4, Level: EscalationExecutive, Page: true, BumpSeverity: true},
+	{Severity: "CRITICAL", MinBurnRate: 6, Level: EscalationManagement, Page: true},
+	{Severity: "HIGH", MinBurnRate: 14.4, Level: EscalationManagement, Page: true, BumpSeverity: true},
+	{Severity: "HIGH", MinBurnRate: 6, Level: EscalationEngineering, Page: true},
+	{Severity: "CRITICAL", Level: EscalationManagement},
+	{Severity: "HIGH", Level: EscalationEngineering},
+	{Severity: "MEDIUM", Level: EscalationOncall},
+}
+
+// Escalation is the outcome of applying the matrix to a notification
+type Escalation struct {
+	Level    string `json:"level"`
+	Page     bool   `json:"page"`
+	Severity string `json:"severity"` // effective severity after any bump
+}
+
+// EscalationPolicy maps severity x error-budget burn rate to an escalation.
+// Rules are evaluated in order; the first whose severity is at or below the
+// notification's and whose burn-rate floor is met wins.
+type EscalationPolicy struct {
+	rules []core.EscalationRule
+}
+
+func NewEscalationPolicy(rules []core.EscalationRule) *EscalationPolicy {
+	if len(rules) == 0 {
+		rules = defaultEscalationMatrix
+	}
+	return &EscalationPolicy{rules: rules}
+}
+
+// Evaluate returns the escalation for a severity and burn rate
+func (p *EscalationPolicy) Evaluate(severity string, burnRate float64) Escalation {
+	rank := severityRank(severity)
+
+	for _, rule := range p.rules {
+		if rank < severityRank(rule.Severity) || burnRate < rule.MinBurnRate {
+			continue
+		}
+
+		effective := severity
+		if rule.BumpSeverity && rank >= 0 && rank < len(severityOrder)-1 {
+			effective = severityOrder[rank+1]
+		}
+		return Escalation{Level: rule.Level, Page: rule.Page, Severity: effective}
+	}
+
+	return Escalation{Level: EscalationNone, Severity: severity}
+}
+
+// EscalatingNotifier annotates notifications with their escalation before
+// handing them to the next notifier
+type EscalatingNotifier struct {
+	next   Notifier
+	policy *EscalationPolicy
+}
+
+func NewEscalatingNotifier(next Notifier, policy *EscalationPolicy) *EscalatingNotifier {
+	return &EscalatingNotifier{next: next, policy: policy}
+}
+
+func (e *EscalatingNotifier) Notify(ctx context.Context, n Notification) error {
+	escalation := e.policy.Evaluate(n.Severity, n.BurnRate)
+	n.Severity = escalation.Severity
+	n.EscalationLevel = escalation.Level
+	n.Page = escalation.Page
+	return e.next.Notify(ctx, n)
+}
@@ -0,0 +1,75 @@
+package notify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+)
+
+func TestDefaultEscalationMatrix(t *testing.T) {
+	tests := []struct {
+		severity string
+		burnRate float64
+		want     Escalation
+	}{
+		{"CRITICAL", 20, Escalation{Level: EscalationExecutive, Page: true, Severity: "CRITICAL"}},
+		{"CRITICAL", 8, Escalation{Level: EscalationManagement, Page: true, Severity: "CRITICAL"}},
+		{"CRITICAL", 0, Escalation{Level: EscalationManagement, Severity: "CRITICAL"}},
+		{"HIGH", 14.4, Escalation{Level: EscalationManagement, Page: true, Severity: "CRITICAL"}},
+		{"HIGH", 6, Escalation{Level: EscalationEngineering, Page: true, Severity: "HIGH"}},
+		{"HIGH", 1, Escalation{Level: EscalationEngineering, Severity: "HIGH"}},
+		{"MEDIUM", 50, Escalation{Level: EscalationOncall, Severity: "MEDIUM"}},
+		{"LOW", 50, Escalation{Level: EscalationNone, Severity: "LOW"}},
+		{"UNKNOWN", 50, Escalation{Level: EscalationNone, Severity: "UNKNOWN"}},
+	}
+
+	policy := NewEscalationPolicy(nil)
+	for _, tt := range tests {
+		if got := policy.Evaluate(tt.severity, tt.burnRate); got != tt.want {
+			t.Errorf("Evaluate(%s, %v) = %+v, want %+v", tt.severity, tt.burnRate, got, tt.want)
+		}
+	}
+}
+
+func TestConfiguredEscalationRules(t *testing.T) {
+	policy := NewEscalationPolicy([]core.EscalationRule{
+		{Severity: "MEDIUM", MinBurnRate: 2, Level: EscalationEngineering, Page: true, BumpSeverity: true},
+		{Severity: "LOW", Level: EscalationOncall},
+	})
+
+	tests := []struct {
+		severity string
+		burnRate float64
+		want     Escalation
+	}{
+		// Rules match at or above their severity, first match wins
+		{"CRITICAL", 3, Escalation{Level: EscalationEngineering, Page: true, Severity: "CRITICAL"}},
+		{"MEDIUM", 2, Escalation{Level: EscalationEngineering, Page: true, Severity: "HIGH"}},
+		{"MEDIUM", 1.9, Escalation{Level: EscalationOncall, Severity: "MEDIUM"}},
+		{"LOW", 10, Escalation{Level: EscalationOncall, Severity: "LOW"}},
+	}
+
+	for _, tt := range tests {
+		if got := policy.Evaluate(tt.severity, tt.burnRate); got != tt.want {
+			t.Errorf("Evaluate(%s, %v) = %+v, want %+v", tt.severity, tt.burnRate, got, tt.want)
+		}
+	}
+}
+
+func TestEscalatingNotifierAnnotates(t *testing.T) {
+	next := &recordingNotifier{}
+	notifier := NewEscalatingNotifier(next, NewEscalationPolicy(nil))
+
+	if err := notifier.Notify(context.Background(), Notification{Service: "checkout", Severity: "HIGH", BurnRate: 15}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	sent := next.notifications()
+	if len(sent) != 1 {
+		t.Fatalf("forwarded %d notifications, want 1", len(sent))
+	}
+	if n := sent[0]; n.Severity != "CRITICAL" || n.EscalationLevel != EscalationManagement || !n.Page {
+		t.Errorf("forwarded %+v, want CRITICAL paging MANAGEMENT", n)
+	}
+}
@@ -0,0 +1,235 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"go.uber.org/zap"
+)
+
+// ErrSuppressed is returned by MaintenanceGate when a notification falls in a
+// maintenance window. Callers record the diagnosis as suppressed.
+var ErrSuppressed = errors.New("notification suppressed by maintenance window")
+
+// Window sources
+const (
+	WindowConfigured = "config"
+	WindowAdHoc      = "ad-hoc"
+)
+
+// MaintenanceWindow is a resolved window ready to be matched against times
+type MaintenanceWindow struct {
+	ID     string                 `json:"id"`
+	Source string                 `json:"source"`
+	Window core.MaintenanceWindow `json:"window"`
+
+	start, end time.Time // one-off windows
+	dailyFrom  time.Duration
+	dailyTo    time.Duration
+	days       map[time.Weekday]bool
+	location   *time.Location
+	recurring  bool
+}
+
+// NewMaintenanceWindow resolves a configured window
+func NewMaintenanceWindow(w core.MaintenanceWindow, source string) (*MaintenanceWindow, error) {
+	mw := &MaintenanceWindow{
+		ID:       uuid.NewString(),
+		Source:   source,
+		Window:   w,
+		location: time.UTC,
+	}
+
+	if w.Daily == "" {
+		start, err := time.Parse(time.RFC3339, w.Start)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start: %w", err)
+		}
+		end, err := time.Parse(time.RFC3339, w.End)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end: %w", err)
+		}
+		if !end.After(start) {
+			return nil, fmt.Errorf("end must be after start")
+		}
+		mw.start, mw.end = start, end
+		return mw, nil
+	}
+
+	from, to, err := core.ParseDailyRange(w.Daily)
+	if err != nil {
+		return nil, err
+	}
+	mw.recurring = true
+	mw.dailyFrom, mw.dailyTo = from, to
+
+	if len(w.Days) > 0 {
+		mw.days = make(map[time.Weekday]bool, len(w.Days))
+		for _, d := range w.Days {
+			day, err := core.ParseWeekday(d)
+			if err != nil {
+				return nil, err
+			}
+			mw.days[day] = true
+		}
+	}
+	if w.Timezone != "" {
+		if mw.location, err = time.LoadLocation(w.Timezone); err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", w.Timezone, err)
+		}
+	}
+	return mw, nil
+}
+
+// Covers reports whether the window applies to the service at the given time
+func (w *MaintenanceWindow) Covers(service string, at time.Time) bool {
+	if w.Window.Service != "" && w.Window.Service != service {
+		return false
+	}
+	if !w.recurring {
+		return !at.Before(w.start) && at.Before(w.end)
+	}
+
+	local := at.In(w.location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, w.location)
+	offset := local.Sub(midnight)
+
+	if w.dailyFrom < w.dailyTo {
+		return offset >= w.dailyFrom && offset < w.dailyTo && w.onDay(local.Weekday())
+	}
+	// Crosses midnight: the evening part belongs to today, the early
+	// morning part to the window that started yesterday
+	if offset >= w.dailyFrom {
+		return w.onDay(local.Weekday())
+	}
+	return offset < w.dailyTo && w.onDay(midnight.AddDate(0, 0, -1).Weekday())
+}
+
+// Expired reports whether a one-off window has ended
+func (w *MaintenanceWindow) Expired(at time.Time) bool {
+	return !w.recurring && !at.Before(w.end)
+}
+
+func (w *MaintenanceWindow) onDay(day time.Weekday) bool {
+	return len(w.days) == 0 || w.days[day]
+}
+
+// MaintenanceGate suppresses notifications for services in a maintenance
+// window. It sits in front of the rest of the notifier chain.
+type MaintenanceGate struct {
+	next   Notifier
+	logger *zap.Logger
+
+	mu      sync.RWMutex
+	windows []*MaintenanceWindow
+}
+
+// NewMaintenanceGate resolves the configured windows. The config has already
+// been validated, so resolution errors only drop the offending window.
+func NewMaintenanceGate(next Notifier, windows []core.MaintenanceWindow, logger *zap.Logger) *MaintenanceGate {
+	g := &MaintenanceGate{next: next, logger: logger}
+	g.SetConfigured(windows)
+	return g
+}
+
+// SetConfigured replaces the configured windows, e.g. after a config reload.
+// Ad-hoc windows are kept.
+func (g *MaintenanceGate) SetConfigured(windows []core.MaintenanceWindow) {
+	var configured []*MaintenanceWindow
+	for i, w := range windows {
+		mw, err := NewMaintenanceWindow(w, WindowConfigured)
+		if err != nil {
+			g.logger.Warn("Ignoring invalid maintenance window", zap.Int("index", i), zap.Error(err))
+			continue
+		}
+		configured = append(configured, mw)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, w := range g.windows {
+		if w.Source == WindowAdHoc {
+			configured = append(configured, w)
+		}
+	}
+	g.windows = configured
+}
+
+// Add declares an ad-hoc window
+func (g *MaintenanceGate) Add(w core.MaintenanceWindow) (*MaintenanceWindow, error) {
+	mw, err := NewMaintenanceWindow(w, WindowAdHoc)
+	if err != nil {
+		return nil, err
+	}
+	if mw.Expired(time.Now()) {
+		return nil, fmt.Errorf("window already ended")
+	}
+
+	g.mu.Lock()
+	g.windows = append(g.windows, mw)
+	g.mu.Unlock()
+
+	g.logger.Info("🛠️ Maintenance window declared",
+		zap.String("id", mw.ID),
+		zap.String("service", w.Service),
+		zap.String("start", w.Start),
+		zap.String("end", w.End),
+		zap.String("reason", w.Reason),
+	)
+	return mw, nil
+}
+
+// Windows returns configured windows and unexpired ad-hoc windows. Expired
+// ad-hoc windows are dropped.
+func (g *MaintenanceGate) Windows() []*MaintenanceWindow {
+	now := time.Now()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	kept := g.windows[:0]
+	for _, w := range g.windows {
+		if w.Source == WindowAdHoc && w.Expired(now) {
+			continue
+		}
+		kept = append(kept, w)
+	}
+	g.windows = kept
+
+	out := make([]*MaintenanceWindow, len(kept))
+	copy(out, kept)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Source < out[j].Source })
+	return out
+}
+
+// Active returns the first window covering the service at the given time
+func (g *MaintenanceGate) Active(service string, at time.Time) *MaintenanceWindow {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	for _, w := range g.windows {
+		if w.Covers(service, at) {
+			return w
+		}
+	}
+	return nil
+}
+
+func (g *MaintenanceGate) Notify(ctx context.Context, n Notification) error {
+	if w := g.Active(n.Service, time.Now()); w != nil {
+		g.logger.Info("🔕 Notification suppressed by maintenance window",
+			zap.String("service", n.Service),
+			zap.String("title", n.Title),
+			zap.String("window", w.ID),
+			zap.String("reason", w.Window.Reason),
+		)
+		return ErrSuppressed
+	}
+	return g.next.Notify(ctx, n)
+}
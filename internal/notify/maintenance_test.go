@@ -0,0 +1,113 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"go.uber.org/zap"
+)
+
+func TestMaintenanceWindowCovers(t *testing.T) {
+	// 2026-01-10 is a Saturday
+	sat := func(hhmm string) time.Time {
+		at, err := time.Parse(time.RFC3339, "2026-01-10T"+hhmm+":00Z")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return at
+	}
+
+	tests := []struct {
+		name    string
+		window  core.MaintenanceWindow
+		service string
+		at      time.Time
+		want    bool
+	}{
+		{"one-off inside", core.MaintenanceWindow{Start: "2026-01-10T22:00:00Z", End: "2026-01-10T23:30:00Z"}, "checkout", sat("22:30"), true},
+		{"one-off at start", core.MaintenanceWindow{Start: "2026-01-10T22:00:00Z", End: "2026-01-10T23:30:00Z"}, "checkout", sat("22:00"), true},
+		{"one-off at end", core.MaintenanceWindow{Start: "2026-01-10T22:00:00Z", End: "2026-01-10T23:30:00Z"}, "checkout", sat("23:30"), false},
+		{"one-off before", core.MaintenanceWindow{Start: "2026-01-10T22:00:00Z", End: "2026-01-10T23:30:00Z"}, "checkout", sat("21:59"), false},
+		{"other service", core.MaintenanceWindow{Service: "payments", Start: "2026-01-10T22:00:00Z", End: "2026-01-10T23:30:00Z"}, "checkout", sat("22:30"), false},
+		{"own service", core.MaintenanceWindow{Service: "payments", Start: "2026-01-10T22:00:00Z", End: "2026-01-10T23:30:00Z"}, "payments", sat("22:30"), true},
+		{"daily inside", core.MaintenanceWindow{Daily: "02:00-03:00"}, "checkout", sat("02:30"), true},
+		{"daily outside", core.MaintenanceWindow{Daily: "02:00-03:00"}, "checkout", sat("03:00"), false},
+		{"daily on listed day", core.MaintenanceWindow{Daily: "02:00-03:00", Days: []string{"sat"}}, "checkout", sat("02:30"), true},
+		{"daily on other day", core.MaintenanceWindow{Daily: "02:00-03:00", Days: []string{"sun"}}, "checkout", sat("02:30"), false},
+		{"overnight evening", core.MaintenanceWindow{Daily: "23:00-01:00", Days: []string{"sat"}}, "checkout", sat("23:30"), true},
+		{"overnight morning after", core.MaintenanceWindow{Daily: "23:00-01:00", Days: []string{"fri"}}, "checkout", sat("00:30"), true},
+		{"overnight morning of listed day", core.MaintenanceWindow{Daily: "23:00-01:00", Days: []string{"sat"}}, "checkout", sat("00:30"), false},
+		{"daily in timezone", core.MaintenanceWindow{Daily: "02:00-03:00", Timezone: "America/New_York"}, "checkout", sat("07:30"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := NewMaintenanceWindow(tt.window, WindowConfigured)
+			if err != nil {
+				t.Fatalf("NewMaintenanceWindow: %v", err)
+			}
+			if got := w.Covers(tt.service, tt.at); got != tt.want {
+				t.Errorf("Covers(%s, %v) = %v, want %v", tt.service, tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaintenanceGateSuppressesInWindow(t *testing.T) {
+	next := &recordingNotifier{}
+	now := time.Now().UTC()
+	gate := NewMaintenanceGate(next, []core.MaintenanceWindow{{
+		Service: "checkout",
+		Start:   now.Add(-time.Hour).Format(time.RFC3339),
+		End:     now.Add(time.Hour).Format(time.RFC3339),
+	}}, zap.NewNop())
+	ctx := context.Background()
+
+	if err := gate.Notify(ctx, Notification{Service: "checkout", Title: "in window"}); !errors.Is(err, ErrSuppressed) {
+		t.Errorf("Notify in a window = %v, want ErrSuppressed", err)
+	}
+	if err := gate.Notify(ctx, Notification{Service: "payments", Title: "out of window"}); err != nil {
+		t.Errorf("Notify outside any window = %v, want nil", err)
+	}
+
+	sent := next.notifications()
+	if len(sent) != 1 || sent[0].Title != "out of window" {
+		t.Errorf("forwarded %+v, want only the out-of-window notification", sent)
+	}
+}
+
+func TestMaintenanceGateAdHocWindows(t *testing.T) {
+	gate := NewMaintenanceGate(&recordingNotifier{}, nil, zap.NewNop())
+	now := time.Now().UTC()
+
+	if _, err := gate.Add(core.MaintenanceWindow{
+		Start: now.Add(-2 * time.Hour).Format(time.RFC3339),
+		End:   now.Add(-time.Hour).Format(time.RFC3339),
+	}); err == nil {
+		t.Error("Add accepted a window that already ended")
+	}
+
+	w, err := gate.Add(core.MaintenanceWindow{
+		Service: "checkout",
+		Start:   now.Format(time.RFC3339),
+		End:     now.Add(time.Hour).Format(time.RFC3339),
+	})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if active := gate.Active("checkout", now.Add(time.Minute)); active == nil || active.ID != w.ID {
+		t.Errorf("Active = %v, want the ad-hoc window", active)
+	}
+
+	// A config reload replaces configured windows but keeps ad-hoc ones
+	gate.SetConfigured([]core.MaintenanceWindow{{Daily: "02:00-03:00"}})
+	windows := gate.Windows()
+	if len(windows) != 2 {
+		t.Fatalf("windows after reload = %d, want the configured and the ad-hoc one", len(windows))
+	}
+	if w.Covers("checkout", now.Add(2*time.Hour)) {
+		t.Error("the ad-hoc window still covers the service after its end")
+	}
+}
@@ -0,0 +1,102 @@
+// Package notify delivers diagnosis notifications to operators
+package notify
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Notification is a single operator-facing message about a service
+type Notification struct {
+	Service      string `json:"service"`
+	Severity     string `json:"severity"`
+	Title        string `json:"title"`
+	Message      string `json:"message"`
+	PredictionID string `json:"prediction_id,omitempty"`
+
+	// BurnRate is how fast the error budget is being consumed (1 = exactly on budget)
+	BurnRate        float64 `json:"burn_rate,omitempty"`
+	EscalationLevel string  `json:"escalation_level,omitempty"`
+	Page            bool    `json:"page,omitempty"` // wake someone up (@here / pager)
+
+	// Actions are the diagnosis' top suggested actuator actions, most
+	// relevant first, so on-call can act on the notification directly
+	Actions []Action `json:"actions,omitempty"`
+
+	Details   map[string]interface{} `json:"details,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+}
+
+// Action is a suggested remediation, e.g. SCALE_UP of replicas
+type Action struct {
+	Type        string      `json:"type"`     // SCALE_UP, ROLLBACK, RESTART, ...
+	Priority    string      `json:"priority"` // IMMEDIATE, HIGH, MEDIUM, LOW
+	Target      string      `json:"target"`   // what the action changes: replicas, memory, ...
+	TargetValue interface{} `json:"target_value,omitempty"`
+	Reason      string      `json:"reason"`
+}
+
+// Notifier delivers notifications to a destination
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// SwappableNotifier forwards to a notifier that can be replaced while in use,
+// so a config reload can rebuild the chain behind it. A nil target drops
+// notifications.
+type SwappableNotifier struct {
+	target atomic.Pointer[Notifier]
+}
+
+func NewSwappableNotifier(target Notifier) *SwappableNotifier {
+	s := &SwappableNotifier{}
+	s.Swap(target)
+	return s
+}
+
+// Swap replaces the target for subsequent notifications
+func (s *SwappableNotifier) Swap(target Notifier) {
+	s.target.Store(&target)
+}
+
+func (s *SwappableNotifier) Notify(ctx context.Context, n Notification) error {
+	target := *s.target.Load()
+	if target == nil {
+		return nil
+	}
+	return target.Notify(ctx, n)
+}
+
+// LogNotifier writes notifications to the structured log
+type LogNotifier struct {
+	logger *zap.Logger
+}
+
+func NewLogNotifier(logger *zap.Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+func (l *LogNotifier) Notify(ctx context.Context, n Notification) error {
+	l.logger.Warn("🚨 "+n.Title,
+		zap.String("service", n.Service),
+		zap.String("severity", n.Severity),
+		zap.String("message", n.Message),
+		zap.String("prediction_id", n.PredictionID),
+		zap.Float64("burn_rate", n.BurnRate),
+		zap.String("escalation_level", n.EscalationLevel),
+		zap.Bool("page", n.Page),
+		zap.Strings("actions", actionTypes(n.Actions)),
+	)
+	return nil
+}
+
+func actionTypes(actions []Action) []string {
+	types := make([]string, len(actions))
+	for i, a := range actions {
+		types[i] = a.Type
+	}
+	return types
+}
@@ -0,0 +1,52 @@
+package notify
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+// recordingNotifier keeps every notification it receives
+type recordingNotifier struct {
+	mu   sync.Mutex
+	sent []Notification
+}
+
+func (r *recordingNotifier) Notify(ctx context.Context, n Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sent = append(r.sent, n)
+	return nil
+}
+
+func (r *recordingNotifier) notifications() []Notification {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Notification(nil), r.sent...)
+}
+
+func TestSwappableNotifier(t *testing.T) {
+	first, second := &recordingNotifier{}, &recordingNotifier{}
+	s := NewSwappableNotifier(first)
+	ctx := context.Background()
+
+	_ = s.Notify(ctx, Notification{Title: "one"})
+	s.Swap(second)
+	_ = s.Notify(ctx, Notification{Title: "two"})
+
+	if got := first.notifications(); len(got) != 1 || got[0].Title != "one" {
+		t.Errorf("first target got %v, want only the notification before the swap", got)
+	}
+	if got := second.notifications(); len(got) != 1 || got[0].Title != "two" {
+		t.Errorf("second target got %v, want only the notification after the swap", got)
+	}
+
+	// A nil target drops notifications
+	s.Swap(nil)
+	if err := s.Notify(ctx, Notification{Title: "three"}); err != nil {
+		t.Errorf("Notify with no target = %v, want nil", err)
+	}
+	if len(first.notifications())+len(second.notifications()) != 2 {
+		t.Error("a notification reached a swapped-out target")
+	}
+}
@@ -0,0 +1,188 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultWebhookTimeout = 5 * time.Second
+	defaultWebhookRetries = 2
+	webhookBackoff        = 500 * time.Millisecond
+)
+
+// WebhookNotifier POSTs notifications to an HTTP endpoint, rendering the body
+// from a text/template
+type WebhookNotifier struct {
+	name     string
+	url      string
+	headers  map[string]string
+	template *template.Template
+	timeout  time.Duration
+	retries  int
+	client   *http.Client
+	logger   *zap.Logger
+}
+
+func NewWebhookNotifier(cfg core.WebhookConfig, logger *zap.Logger) (*WebhookNotifier, error) {
+	w := &WebhookNotifier{
+		name:    cfg.Name,
+		url:     cfg.URL,
+		headers: make(map[string]string, len(cfg.Headers)),
+		timeout: defaultWebhookTimeout,
+		retries: defaultWebhookRetries,
+		client:  &http.Client{},
+		logger:  logger,
+	}
+	if w.name == "" {
+		w.name = cfg.URL
+	}
+	for k, v := range cfg.Headers {
+		w.headers[k] = os.ExpandEnv(v)
+	}
+	if cfg.Template != "" {
+		tmpl, err := template.New(w.name).Funcs(core.WebhookTemplateFuncs).Parse(cfg.Template)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook template: %w", err)
+		}
+		w.template = tmpl
+	}
+	if cfg.Timeout != "" {
+		d, err := time.ParseDuration(cfg.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook timeout: %w", err)
+		}
+		w.timeout = d
+	}
+	if cfg.Retries > 0 {
+		w.retries = cfg.Retries
+	}
+	return w, nil
+}
+
+// Render builds the request body for a notification
+func (w *WebhookNotifier) Render(n Notification) ([]byte, error) {
+	if w.template == nil {
+		return json.Marshal(n)
+	}
+
+	var buf bytes.Buffer
+	if err := w.template.Execute(&buf, n); err != nil {
+		return nil, fmt.Errorf("failed to render webhook template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (w *WebhookNotifier) Notify(ctx context.Context, n Notification) error {
+	body, err := w.Render(n)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= w.retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(webhookBackoff << (attempt - 1)):
+			}
+		}
+
+		retryable, err := w.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retryable {
+			break
+		}
+		w.logger.Debug("Webhook attempt failed",
+			zap.String("webhook", w.name),
+			zap.Int("attempt", attempt+1),
+			zap.Error(err),
+		)
+	}
+	return fmt.Errorf("webhook %s: %w", w.name, lastErr)
+}
+
+// post sends one attempt. Client errors other than 429 are not retried.
+func (w *WebhookNotifier) post(ctx context.Context, body []byte) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10)) // drain so the connection is reused
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return true, nil
+	}
+	retryable := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	return retryable, fmt.Errorf("unexpected status %d", resp.StatusCode)
+}
+
+// MultiNotifier fans a notification out to several notifiers in parallel and
+// joins their errors. One slow or failing target never blocks the others.
+type MultiNotifier struct {
+	notifiers []Notifier
+}
+
+func NewMultiNotifier(notifiers ...Notifier) *MultiNotifier {
+	return &MultiNotifier{notifiers: notifiers}
+}
+
+func (m *MultiNotifier) Notify(ctx context.Context, n Notification) error {
+	errs := make([]error, len(m.notifiers))
+
+	var wg sync.WaitGroup
+	for i, notifier := range m.notifiers {
+		wg.Add(1)
+		go func(i int, notifier Notifier) {
+			defer wg.Done()
+			errs[i] = notifier.Notify(ctx, n)
+		}(i, notifier)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// NewWebhookNotifiers builds a notifier per configured webhook. The config
+// is validated at load, so a failure here only drops that webhook.
+func NewWebhookNotifiers(configs []core.WebhookConfig, logger *zap.Logger) []Notifier {
+	notifiers := make([]Notifier, 0, len(configs))
+	for i, cfg := range configs {
+		w, err := NewWebhookNotifier(cfg, logger)
+		if err != nil {
+			logger.Warn("Ignoring invalid webhook", zap.Int("index", i), zap.Error(err))
+			continue
+		}
+		notifiers = append(notifiers, w)
+	}
+	return notifiers
+}
@@ -0,0 +1,210 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/core"
+	"go.uber.org/zap"
+)
+
+// webhookCapture records the requests a test webhook endpoint receives and
+// answers them with the given statuses in turn, then 200
+type webhookCapture struct {
+	mu       sync.Mutex
+	statuses []int
+	bodies   [][]byte
+	headers  []http.Header
+}
+
+func newWebhookServer(t *testing.T, statuses ...int) (*httptest.Server, *webhookCapture) {
+	t.Helper()
+
+	capture := &webhookCapture{statuses: statuses}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		capture.mu.Lock()
+		capture.bodies = append(capture.bodies, body)
+		capture.headers = append(capture.headers, r.Header.Clone())
+		status := http.StatusOK
+		if len(capture.statuses) > 0 {
+			status, capture.statuses = capture.statuses[0], capture.statuses[1:]
+		}
+		capture.mu.Unlock()
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, capture
+}
+
+func (c *webhookCapture) requests() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.bodies)
+}
+
+func testNotification() Notification {
+	return Notification{
+		Service:   "checkout",
+		Severity:  "HIGH",
+		Title:     `Memory leak in "checkout"`,
+		Message:   "Memory grew 12% in 30m",
+		Timestamp: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestWebhookRendersTemplate(t *testing.T) {
+	server, capture := newWebhookServer(t)
+	t.Setenv("TEST_WEBHOOK_TOKEN", "s3cret")
+
+	w, err := NewWebhookNotifier(core.WebhookConfig{
+		Name:     "opsgenie",
+		URL:      server.URL,
+		Headers:  map[string]string{"Authorization": "GenieKey ${TEST_WEBHOOK_TOKEN}"},
+		Template: `{"alias": {{json .Service}}, "message": {{json .Title}}, "priority": "{{if eq .Severity "HIGH"}}P2{{else}}P4{{end}}"}`,
+	}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier: %v", err)
+	}
+
+	if err := w.Notify(context.Background(), testNotification()); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	if capture.requests() != 1 {
+		t.Fatalf("endpoint received %d requests, want 1", capture.requests())
+	}
+	var payload map[string]string
+	if err := json.Unmarshal(capture.bodies[0], &payload); err != nil {
+		t.Fatalf("rendered payload %q is not JSON: %v", capture.bodies[0], err)
+	}
+	want := map[string]string{"alias": "checkout", "message": `Memory leak in "checkout"`, "priority": "P2"}
+	for k, v := range want {
+		if payload[k] != v {
+			t.Errorf("payload[%s] = %q, want %q", k, payload[k], v)
+		}
+	}
+	if got := capture.headers[0].Get("Authorization"); got != "GenieKey s3cret" {
+		t.Errorf("Authorization = %q, want the expanded header", got)
+	}
+	if got := capture.headers[0].Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+}
+
+func TestWebhookDefaultPayload(t *testing.T) {
+	server, capture := newWebhookServer(t)
+	w, err := NewWebhookNotifier(core.WebhookConfig{URL: server.URL}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier: %v", err)
+	}
+
+	if err := w.Notify(context.Background(), testNotification()); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	var got Notification
+	if err := json.Unmarshal(capture.bodies[0], &got); err != nil {
+		t.Fatalf("payload is not a Notification: %v", err)
+	}
+	if got.Service != "checkout" || got.Title != testNotification().Title {
+		t.Errorf("payload = %+v, want the notification as JSON", got)
+	}
+}
+
+func TestWebhookRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantErr      bool
+		wantRequests int
+	}{
+		{"server error then success", []int{http.StatusServiceUnavailable}, false, 2},
+		{"rate limited then success", []int{http.StatusTooManyRequests}, false, 2},
+		{"client error is not retried", []int{http.StatusBadRequest}, true, 1},
+		{"gives up after the retries", []int{500, 500}, true, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, capture := newWebhookServer(t, tt.statuses...)
+			w, err := NewWebhookNotifier(core.WebhookConfig{URL: server.URL, Retries: 1}, zap.NewNop())
+			if err != nil {
+				t.Fatalf("NewWebhookNotifier: %v", err)
+			}
+
+			err = w.Notify(context.Background(), testNotification())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Notify error = %v, want error %v", err, tt.wantErr)
+			}
+			if capture.requests() != tt.wantRequests {
+				t.Errorf("endpoint received %d requests, want %d", capture.requests(), tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestNewWebhookNotifierInvalidTemplate(t *testing.T) {
+	if _, err := NewWebhookNotifier(core.WebhookConfig{URL: "http://example.invalid", Template: "{{.Service"}, zap.NewNop()); err == nil {
+		t.Error("NewWebhookNotifier accepted an unparseable template")
+	}
+}
+
+// failingNotifier always fails
+type failingNotifier struct{ err error }
+
+func (f failingNotifier) Notify(context.Context, Notification) error { return f.err }
+
+func TestMultiNotifierFansOut(t *testing.T) {
+	first, second := &recordingNotifier{}, &recordingNotifier{}
+	errDown := errors.New("endpoint down")
+	multi := NewMultiNotifier(first, failingNotifier{errDown}, second)
+
+	err := multi.Notify(context.Background(), testNotification())
+	if !errors.Is(err, errDown) {
+		t.Errorf("Notify error = %v, want it to carry the failing notifier's error", err)
+	}
+	if len(first.notifications()) != 1 || len(second.notifications()) != 1 {
+		t.Error("a failing notifier kept the others from being notified")
+	}
+}
+
+func TestWebhookDefaultPayloadActions(t *testing.T) {
+	w, err := NewWebhookNotifier(core.WebhookConfig{URL: "http://localhost:1"}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier: %v", err)
+	}
+
+	n := testNotification()
+	body, err := w.Render(n)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		t.Fatalf("payload is not JSON: %v", err)
+	}
+	if _, ok := raw["actions"]; ok {
+		t.Errorf("payload %s lists actions, want them omitted when there are none", body)
+	}
+
+	n.Actions = []Action{{Type: "SCALE_UP", Priority: "HIGH", Target: "replicas", TargetValue: 3, Reason: "CPU at 95%"}}
+	body, err = w.Render(n)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	var got Notification
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("payload is not a Notification: %v", err)
+	}
+	if len(got.Actions) != 1 || got.Actions[0].Type != "SCALE_UP" || got.Actions[0].TargetValue != 3.0 {
+		t.Errorf("actions = %+v, want the SCALE_UP to 3 replicas", got.Actions)
+	}
+}
@@ -0,0 +1,48 @@
+package observer
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Pod watch reconnect policy
+const (
+	watchBackoffBase = 1 * time.Second
+	watchBackoffMax  = 2 * time.Minute
+
+	// A watch that stayed up this long counts as healthy and resets the backoff
+	watchStableAfter = 1 * time.Minute
+)
+
+// backoff produces exponentially growing, capped delays with jitter so that
+// many clients reconnecting after an outage spread out instead of arriving
+// at the API server together
+type backoff struct {
+	base    time.Duration
+	max     time.Duration
+	attempt int
+}
+
+func newBackoff(base, max time.Duration) *backoff {
+	return &backoff{base: base, max: max}
+}
+
+// Next returns the delay before the next attempt: base * 2^attempt capped at
+// max, with the upper half jittered
+func (b *backoff) Next() time.Duration {
+	d := b.max
+	if b.attempt < 32 {
+		if exp := b.base << b.attempt; exp > 0 && exp < b.max {
+			d = exp
+		}
+	}
+	b.attempt++
+
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// Reset starts the sequence over after a success
+func (b *backoff) Reset() {
+	b.attempt = 0
+}
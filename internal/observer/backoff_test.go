@@ -0,0 +1,86 @@
+package observer
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBackoffGrowsToCap(t *testing.T) {
+	b := newBackoff(time.Second, 30*time.Second)
+
+	// Each delay falls in [d/2, d] for d = base*2^n capped at max. Until the
+	// cap, the next floor is the previous ceiling, so delays never decrease.
+	ceilings := []time.Duration{1, 2, 4, 8, 16, 30, 30, 30}
+	var prev time.Duration
+	for i, c := range ceilings {
+		ceiling := c * time.Second
+		d := b.Next()
+		if d < ceiling/2 || d > ceiling {
+			t.Errorf("attempt %d: delay %v outside [%v, %v]", i, d, ceiling/2, ceiling)
+		}
+		if ceiling < 30*time.Second && d < prev {
+			t.Errorf("attempt %d: delay %v shorter than previous %v", i, d, prev)
+		}
+		prev = d
+	}
+
+	// Far past the point where base<<attempt would overflow, delays stay capped
+	for i := 0; i < 100; i++ {
+		if d := b.Next(); d < 15*time.Second || d > 30*time.Second {
+			t.Fatalf("late attempt: delay %v outside [15s, 30s]", d)
+		}
+	}
+}
+
+func TestBackoffReset(t *testing.T) {
+	b := newBackoff(time.Second, time.Minute)
+	for i := 0; i < 5; i++ {
+		b.Next()
+	}
+	b.Reset()
+	if d := b.Next(); d < 500*time.Millisecond || d > time.Second {
+		t.Errorf("delay after reset = %v, want within [500ms, 1s]", d)
+	}
+}
+
+func TestBackoffJitterSpreadsDelays(t *testing.T) {
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 20; i++ {
+		b := newBackoff(time.Second, time.Minute)
+		b.Next()
+		b.Next()
+		seen[b.Next()] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("20 clients all chose the same third delay; want jitter to spread them")
+	}
+}
+
+func TestRecordWatchEndCountsConsecutiveFailures(t *testing.T) {
+	k := newTestWatcher("default")
+
+	retry := time.Now().Add(4 * time.Second)
+	for want := 1; want <= 3; want++ {
+		if got := k.recordWatchEnd(errors.New("connection refused"), retry); got != want {
+			t.Errorf("failure %d: consecutive failures = %d", want, got)
+		}
+	}
+	status := k.WatchStatus()
+	if status.Connected || status.ConsecutiveFailures != 3 || status.LastError != "connection refused" || !status.NextRetry.Equal(retry) {
+		t.Errorf("status after failures = %+v", status)
+	}
+
+	k.recordWatchConnected()
+	if status := k.WatchStatus(); !status.Connected || !status.NextRetry.IsZero() {
+		t.Errorf("status after connect = %+v, want connected with no retry pending", status)
+	}
+
+	// A watch that ends cleanly resets the count but keeps the last error
+	if got := k.recordWatchEnd(nil, time.Time{}); got != 0 {
+		t.Errorf("consecutive failures after clean end = %d, want 0", got)
+	}
+	if status := k.WatchStatus(); status.ConsecutiveFailures != 0 || status.LastError != "connection refused" {
+		t.Errorf("status after clean end = %+v", status)
+	}
+}
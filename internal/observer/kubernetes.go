@@ -3,42 +3,85 @@ package observer
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/eventbus"
 	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
+// ErrDeploymentNotFound is returned when a scale or restart targets a missing deployment
+var ErrDeploymentNotFound = errors.New("deployment not found")
+
+// crashLoopRestartThreshold is the restart count at which a pod is considered crash-looping
+const crashLoopRestartThreshold = 3
+
+// restartedAtAnnotation is the pod template annotation kubectl uses for rollout restarts
+const restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
 type KubernetesWatcher struct {
-	clientset *kubernetes.Clientset
+	clientset kubernetes.Interface
 	namespace string
 	db        *storage.PostgresClient
 	enabled   bool
 	logger    *zap.Logger
+
+	resourceInterval     time.Duration
+	metricsServerMissing bool // only touched by the resource metrics goroutine
+
+	bus          *eventbus.Bus
+	lastRestarts map[string]int32 // pod -> restarts seen; only touched by the pod watch goroutine
+
+	// Per-pod state for the derived OOMKilled and FailedScheduling events,
+	// only touched by the pod watch goroutine
+	lastOOMKill   map[string]time.Time // pod -> finish time of the last OOM kill recorded
+	unschedulable map[string]bool      // pod -> FailedScheduling already recorded
+
+	// List-then-watch state, only touched by the pod watch goroutine.
+	// resourceVersion is where the next watch resumes ("" forces a re-list);
+	// handledVersions de-duplicates events by pod UID and resourceVersion.
+	resourceVersion string
+	handledVersions map[types.UID]string
+
+	watchMu     sync.RWMutex
+	watchStatus WatchStatus
 }
 
-func NewKubernetesWatcher(namespace string, db *storage.PostgresClient, logger *zap.Logger) (*KubernetesWatcher, error) {
-	if namespace == "" {
-		namespace = "default"
-	}
+// WatchStatus reports the state of the pod watch for health checks
+type WatchStatus struct {
+	Connected           bool      `json:"connected"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	LastError           string    `json:"last_error,omitempty"`
+	LastConnected       time.Time `json:"last_connected,omitempty"`
+	NextRetry           time.Time `json:"next_retry,omitempty"`
+}
 
-	watcher := &KubernetesWatcher{
-		namespace: namespace,
-		db:        db,
-		enabled:   false,
-		logger:    logger,
+func NewKubernetesWatcher(namespace string, db *storage.PostgresClient, logger *zap.Logger) (*KubernetesWatcher, error) {
+	watchers, err := NewKubernetesWatchers([]string{namespace}, db, logger)
+	if err != nil {
+		return nil, err
 	}
+	return watchers[0], nil
+}
 
-	clientset, err := watcher.createKubernetesClient()
+// NewKubernetesWatchers builds one watcher per namespace. They share a single
+// clientset; each keeps its own watch, backoff and restart tracking, so one
+// namespace failing doesn't affect the others.
+func NewKubernetesWatchers(namespaces []string, db *storage.PostgresClient, logger *zap.Logger) ([]*KubernetesWatcher, error) {
+	clientset, err := createKubernetesClient()
 	if err != nil {
 		// Changed: do not return a disabled watcher silently. Return an error so the caller
 		// can detect that Kubernetes connectivity failed and choose a fallback (or disable features).
@@ -46,13 +89,42 @@ func NewKubernetesWatcher(namespace string, db *storage.PostgresClient, logger *
 		return nil, fmt.Errorf("could not create kubernetes client: %w", err)
 	}
 
-	watcher.clientset = clientset
-	watcher.enabled = true
+	if len(namespaces) == 0 {
+		namespaces = []string{"default"}
+	}
+	watchers := make([]*KubernetesWatcher, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		if namespace == "" {
+			namespace = "default"
+		}
+		watchers = append(watchers, newKubernetesWatcher(clientset, namespace, db, logger))
+	}
+
+	return watchers, nil
+}
+
+func newKubernetesWatcher(clientset kubernetes.Interface, namespace string, db *storage.PostgresClient, logger *zap.Logger) *KubernetesWatcher {
+	return &KubernetesWatcher{
+		clientset: clientset,
+		namespace: namespace,
+		db:        db,
+		enabled:   true,
+		logger:    logger,
 
-	return watcher, nil
+		resourceInterval: defaultResourceMetricsInterval,
+		lastRestarts:     make(map[string]int32),
+		lastOOMKill:      make(map[string]time.Time),
+		unschedulable:    make(map[string]bool),
+		handledVersions:  make(map[types.UID]string),
+	}
 }
 
-func (k *KubernetesWatcher) createKubernetesClient() (*kubernetes.Clientset, error) {
+// Namespace returns the namespace the watcher covers
+func (k *KubernetesWatcher) Namespace() string {
+	return k.namespace
+}
+
+func createKubernetesClient() (*kubernetes.Clientset, error) {
 	config, err := rest.InClusterConfig()
 	/*
 		"Hey Kubernetes… am I already running inside your cluster as a pod?"
@@ -98,6 +170,7 @@ func (k *KubernetesWatcher) Start(ctx context.Context) error {
 
 	go k.watchPods(ctx)
 	go k.collectPodMetrics(ctx)
+	go k.collectResourceMetrics(ctx)
 
 	k.logger.Info("Kubernetes watcher started successfully - monitoring pods")
 
@@ -109,31 +182,102 @@ func (k *KubernetesWatcher) Start(ctx context.Context) error {
 func (k *KubernetesWatcher) watchPods(ctx context.Context) {
 	k.logger.Info("Starting pod event watcher", zap.String("namespace", k.namespace))
 
+	retry := newBackoff(watchBackoffBase, watchBackoffMax)
 	for {
+		started := time.Now()
+		err := k.watchPodsOnce(ctx)
+		if ctx.Err() != nil {
+			k.logger.Info("Pod watcher stopped")
+			return
+		}
+
+		// A watch that ran for a while and then ended (server-side timeout,
+		// routine disconnect) is healthy: reconnect straight away
+		if err == nil || time.Since(started) >= watchStableAfter {
+			retry.Reset()
+			k.recordWatchEnd(nil, time.Time{})
+			continue
+		}
+
+		delay := retry.Next()
+		failures := k.recordWatchEnd(err, time.Now().Add(delay))
+		k.logger.Error("Pod watch error, backing off",
+			zap.String("namespace", k.namespace),
+			zap.Error(err),
+			zap.Int("consecutive_failures", failures),
+			zap.Duration("retry_in", delay),
+		)
+
 		select {
 		case <-ctx.Done():
 			k.logger.Info("Pod watcher stopped")
 			return
-		default:
-			if err := k.watchPodsOnce(ctx); err != nil {
-				k.logger.Error("Pod watch error, retrying in 5s", zap.Error(err))
-				time.Sleep(5 * time.Second)
-			}
+		case <-time.After(delay):
 		}
 	}
 }
 
+// recordWatchEnd updates the watch status when a watch ends and returns the
+// consecutive failure count
+func (k *KubernetesWatcher) recordWatchEnd(err error, nextRetry time.Time) int {
+	k.watchMu.Lock()
+	defer k.watchMu.Unlock()
+
+	k.watchStatus.Connected = false
+	k.watchStatus.NextRetry = nextRetry
+	if err == nil {
+		k.watchStatus.ConsecutiveFailures = 0
+		return 0
+	}
+	k.watchStatus.ConsecutiveFailures++
+	k.watchStatus.LastError = err.Error()
+	return k.watchStatus.ConsecutiveFailures
+}
+
+func (k *KubernetesWatcher) recordWatchConnected() {
+	k.watchMu.Lock()
+	defer k.watchMu.Unlock()
+
+	k.watchStatus.Connected = true
+	k.watchStatus.LastConnected = time.Now()
+	k.watchStatus.NextRetry = time.Time{}
+}
+
+// WatchStatus returns the current pod watch state
+func (k *KubernetesWatcher) WatchStatus() WatchStatus {
+	k.watchMu.RLock()
+	defer k.watchMu.RUnlock()
+	return k.watchStatus
+}
+
+// watchPodsOnce resumes the watch from the last seen resourceVersion, listing
+// first when there is none. Pods found by the list seed the restart tracking
+// but are not saved as events, so a reconnect never replays the initial state.
 func (k *KubernetesWatcher) watchPodsOnce(ctx context.Context) error {
+	if k.resourceVersion == "" {
+		if err := k.relistPods(ctx); err != nil {
+			return err
+		}
+	}
+
 	timeout := int64(300)
 	watcher, err := k.clientset.CoreV1().Pods(k.namespace).Watch(ctx, metav1.ListOptions{
-		TimeoutSeconds: &timeout,
+		TimeoutSeconds:      &timeout,
+		ResourceVersion:     k.resourceVersion,
+		AllowWatchBookmarks: true,
 	})
 	if err != nil {
+		if isResourceVersionExpired(err) {
+			k.logger.Info("Pod watch resourceVersion expired, re-listing", zap.String("resource_version", k.resourceVersion))
+			k.resourceVersion = ""
+			return nil
+		}
 		return fmt.Errorf("failed to start watch: %w", err)
 	}
 	defer watcher.Stop()
 
-	k.logger.Info("Pod watcher connected, monitoring for events...")
+	k.recordWatchConnected()
+	k.logger.Info("Pod watcher connected, monitoring for events...", zap.String("resource_version", k.resourceVersion))
 
 	for {
 		select {
@@ -144,13 +288,84 @@ func (k *KubernetesWatcher) watchPodsOnce(ctx context.Context) error {
 				k.logger.Warn("Watch channel closed, will reconnect")
 				return fmt.Errorf("watch channel closed")
 			}
+
+			switch event.Type {
+			case watch.Error:
+				err := apierrors.FromObject(event.Object)
+				if isResourceVersionExpired(err) {
+					k.logger.Info("Pod watch resourceVersion expired, re-listing", zap.String("resource_version", k.resourceVersion))
+					k.resourceVersion = ""
+					return nil
+				}
+				return fmt.Errorf("watch error: %w", err)
+			case watch.Bookmark:
+				if pod, ok := event.Object.(*corev1.Pod); ok {
+					k.resourceVersion = pod.ResourceVersion
+				}
+				continue
+			}
+
+			// Reconnect from the last saved event rather than skip past
+			// one that couldn't be saved
 			if err := k.handlePodEvent(ctx, event); err != nil {
-				k.logger.Error("Failed to handle pod event", zap.Error(err))
+				return err
 			}
 		}
 	}
 }
 
+// relistPods lists the namespace to get a consistent resourceVersion to
+// watch from and resyncs the per-pod state to the pods that still exist
+func (k *KubernetesWatcher) relistPods(ctx context.Context) error {
+	pods, err := k.clientset.CoreV1().Pods(k.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	present := make(map[types.UID]bool, len(pods.Items))
+	names := make(map[string]bool, len(pods.Items))
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		present[pod.UID] = true
+		names[pod.Name] = true
+		k.handledVersions[pod.UID] = pod.ResourceVersion
+		k.trackRestarts(pod, string(watch.Added), k.getPodRestarts(pod))
+		k.seedPodConditions(pod)
+	}
+	for uid := range k.handledVersions {
+		if !present[uid] {
+			delete(k.handledVersions, uid)
+		}
+	}
+	for name := range k.lastRestarts {
+		if !names[name] {
+			delete(k.lastRestarts, name)
+		}
+	}
+	for name := range k.lastOOMKill {
+		if !names[name] {
+			delete(k.lastOOMKill, name)
+		}
+	}
+	for name := range k.unschedulable {
+		if !names[name] {
+			delete(k.unschedulable, name)
+		}
+	}
+
+	k.resourceVersion = pods.ResourceVersion
+	k.logger.Info("Pods listed",
+		zap.Int("pods", len(pods.Items)),
+		zap.String("resource_version", k.resourceVersion),
+	)
+	return nil
+}
+
+// isResourceVersionExpired reports a 410 Gone / "too old resource version"
+func isResourceVersionExpired(err error) bool {
+	return apierrors.IsResourceExpired(err) || apierrors.IsGone(err)
+}
+
 func (k *KubernetesWatcher) handlePodEvent(ctx context.Context, event watch.Event) error {
 	pod, ok := event.Object.(*corev1.Pod)
 	/*
@@ -168,6 +383,11 @@ func (k *KubernetesWatcher) handlePodEvent(ctx context.Context, event watch.Even
 		return fmt.Errorf("unexpected object type: %T", event.Object)
 	}
 
+	if k.handledVersions[pod.UID] == pod.ResourceVersion {
+		k.resourceVersion = pod.ResourceVersion
+		return nil // already handled (seen in the list or a replayed event)
+	}
+
 	eventType := string(event.Type)
 	message := k.buildEventMessage(pod, eventType)
 
@@ -190,8 +410,20 @@ func (k *KubernetesWatcher) handlePodEvent(ctx context.Context, event watch.Even
 		return fmt.Errorf("failed to save event: %w", err)
 	}
 
+	// Only a saved event moves the watch on; after a failure the reconnect
+	// resumes from before it and the event is delivered again
+	k.resourceVersion = pod.ResourceVersion
+	if event.Type == watch.Deleted {
+		delete(k.handledVersions, pod.UID)
+	} else {
+		k.handledVersions[pod.UID] = pod.ResourceVersion
+	}
+
 	restarts := k.getPodRestarts(pod)
-	if restarts >= 3 {
+	k.trackRestarts(pod, eventType, restarts)
+	k.recordPodConditions(ctx, pod, eventType)
+
+	if restarts >= crashLoopRestartThreshold {
 		k.logger.Warn("Pod crash-looping",
 			zap.String("pod", pod.Name),
 			zap.Int32("restarts", restarts),
@@ -199,7 +431,7 @@ func (k *KubernetesWatcher) handlePodEvent(ctx context.Context, event watch.Even
 
 		crashEvent := &storage.Event{
 			Timestamp: time.Now(),
-			EventType: "CrashLoop",
+			EventType: storage.EventCrashLoop,
 			PodName:   pod.Name,
 			Namespace: pod.Namespace,
 			Message:   fmt.Sprintf("Pod restarted %d times", restarts),
@@ -210,6 +442,141 @@ func (k *KubernetesWatcher) handlePodEvent(ctx context.Context, event watch.Even
 	return nil
 }
 
+// recordPodConditions saves an OOMKilled event for each new OOM kill of one
+// of the pod's containers, and a FailedScheduling event when the scheduler
+// first reports the pod unschedulable. The analyzer reads both as
+// corroborating evidence.
+func (k *KubernetesWatcher) recordPodConditions(ctx context.Context, pod *corev1.Pod, eventType string) {
+	if eventType == string(watch.Deleted) {
+		delete(k.lastOOMKill, pod.Name)
+		delete(k.unschedulable, pod.Name)
+		return
+	}
+
+	if killedAt, container := lastOOMKill(pod); !killedAt.IsZero() && killedAt.After(k.lastOOMKill[pod.Name]) {
+		k.lastOOMKill[pod.Name] = killedAt
+		k.saveDerivedEvent(ctx, pod, storage.EventOOMKilled, killedAt,
+			fmt.Sprintf("Container %s of pod %s was OOMKilled", container, pod.Name))
+	}
+
+	message, ok := unschedulableMessage(pod)
+	switch {
+	case ok && !k.unschedulable[pod.Name]:
+		k.unschedulable[pod.Name] = true
+		k.saveDerivedEvent(ctx, pod, storage.EventFailedScheduling, time.Now(),
+			fmt.Sprintf("Pod %s could not be scheduled: %s", pod.Name, message))
+	case !ok:
+		delete(k.unschedulable, pod.Name)
+	}
+}
+
+// seedPodConditions records a listed pod's current OOM and scheduling state
+// without saving events, so a re-list never replays them
+func (k *KubernetesWatcher) seedPodConditions(pod *corev1.Pod) {
+	if killedAt, _ := lastOOMKill(pod); !killedAt.IsZero() {
+		k.lastOOMKill[pod.Name] = killedAt
+	}
+	if _, ok := unschedulableMessage(pod); ok {
+		k.unschedulable[pod.Name] = true
+	}
+}
+
+func (k *KubernetesWatcher) saveDerivedEvent(ctx context.Context, pod *corev1.Pod, eventType string, at time.Time, message string) {
+	k.logger.Warn("Kubernetes pod condition detected",
+		zap.String("event_type", eventType),
+		zap.String("pod_name", pod.Name),
+		zap.String("namespace", pod.Namespace))
+
+	event := &storage.Event{
+		Timestamp: at,
+		EventType: eventType,
+		PodName:   pod.Name,
+		Namespace: pod.Namespace,
+		Message:   message,
+	}
+	if err := k.db.SaveEvent(ctx, event); err != nil {
+		k.logger.Error("Failed to save event to database", zap.Error(err))
+	}
+}
+
+// lastOOMKill returns when the most recent OOM kill among the pod's
+// containers finished and which container it was, or a zero time
+func lastOOMKill(pod *corev1.Pod) (time.Time, string) {
+	var (
+		latest    time.Time
+		container string
+	)
+	for _, status := range pod.Status.ContainerStatuses {
+		for _, terminated := range []*corev1.ContainerStateTerminated{status.State.Terminated, status.LastTerminationState.Terminated} {
+			if terminated == nil || terminated.Reason != "OOMKilled" {
+				continue
+			}
+			if at := terminated.FinishedAt.Time; at.After(latest) {
+				latest = at
+				container = status.Name
+			}
+		}
+	}
+	return latest, container
+}
+
+// unschedulableMessage returns the scheduler's message when the pod's
+// PodScheduled condition is False for reason Unschedulable
+func unschedulableMessage(pod *corev1.Pod) (string, bool) {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled &&
+			condition.Status == corev1.ConditionFalse &&
+			condition.Reason == corev1.PodReasonUnschedulable {
+			return condition.Message, true
+		}
+	}
+	return "", false
+}
+
+// SetEventBus makes the watcher publish CrashLoop events for other components
+func (k *KubernetesWatcher) SetEventBus(bus *eventbus.Bus) {
+	k.bus = bus
+}
+
+// trackRestarts publishes a CrashLoop event the first time a pod's restart
+// count crosses the threshold, so a looping pod triggers one analysis rather
+// than one per watch event
+func (k *KubernetesWatcher) trackRestarts(pod *corev1.Pod, eventType string, restarts int32) {
+	if eventType == string(watch.Deleted) {
+		delete(k.lastRestarts, pod.Name)
+		return
+	}
+
+	previous := k.lastRestarts[pod.Name]
+	k.lastRestarts[pod.Name] = restarts
+
+	if k.bus == nil || previous >= crashLoopRestartThreshold || restarts < crashLoopRestartThreshold {
+		return
+	}
+
+	k.bus.Publish(eventbus.Event{
+		Type:      eventbus.EventCrashLoop,
+		Service:   podService(pod),
+		Pod:       pod.Name,
+		Namespace: pod.Namespace,
+		Payload: map[string]interface{}{
+			"restarts":          restarts,
+			"previous_restarts": previous,
+			"phase":             string(pod.Status.Phase),
+		},
+	})
+}
+
+// podService resolves the service a pod belongs to from its app label
+func podService(pod *corev1.Pod) string {
+	for _, key := range []string{"app.kubernetes.io/name", "app"} {
+		if name := pod.Labels[key]; name != "" {
+			return name
+		}
+	}
+	return pod.Name
+}
+
 func (k *KubernetesWatcher) collectPodMetrics(ctx context.Context) {
 	ticker := time.NewTicker(30 * time.Second)
 	defer ticker.Stop()
@@ -402,6 +769,93 @@ func (k *KubernetesWatcher) GetPodMetrics(ctx context.Context) ([]PodMetric, err
 	return metrics, nil
 }
 
+// DeploymentReplicas returns the desired replica count of a deployment
+func (k *KubernetesWatcher) DeploymentReplicas(ctx context.Context, name string) (int32, error) {
+	if !k.enabled {
+		return 0, fmt.Errorf("kubernetes watcher not enabled")
+	}
+
+	scale, err := k.clientset.AppsV1().Deployments(k.namespace).GetScale(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return 0, k.deploymentError(name, "get scale of", err)
+	}
+
+	return scale.Spec.Replicas, nil
+}
+
+// ScaleDeployment sets the desired replica count through the scale subresource
+func (k *KubernetesWatcher) ScaleDeployment(ctx context.Context, name string, replicas int32) error {
+	if !k.enabled {
+		return fmt.Errorf("kubernetes watcher not enabled")
+	}
+	if replicas < 0 {
+		return fmt.Errorf("replicas must be non-negative, got %d", replicas)
+	}
+
+	deployments := k.clientset.AppsV1().Deployments(k.namespace)
+	scale, err := deployments.GetScale(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return k.deploymentError(name, "get scale of", err)
+	}
+
+	previous := scale.Spec.Replicas
+	scale.Spec.Replicas = replicas
+	if _, err := deployments.UpdateScale(ctx, name, scale, metav1.UpdateOptions{}); err != nil {
+		return k.deploymentError(name, "scale", err)
+	}
+
+	k.logger.Info("Scaled deployment",
+		zap.String("deployment", name),
+		zap.String("namespace", k.namespace),
+		zap.Int32("from", previous),
+		zap.Int32("to", replicas),
+	)
+
+	return nil
+}
+
+// RestartDeployment triggers a rolling restart by stamping the pod template,
+// the same way `kubectl rollout restart` does
+func (k *KubernetesWatcher) RestartDeployment(ctx context.Context, name string) error {
+	if !k.enabled {
+		return fmt.Errorf("kubernetes watcher not enabled")
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]string{
+						restartedAtAnnotation: time.Now().Format(time.RFC3339),
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build restart patch: %w", err)
+	}
+
+	_, err = k.clientset.AppsV1().Deployments(k.namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return k.deploymentError(name, "restart", err)
+	}
+
+	k.logger.Info("Restarted deployment",
+		zap.String("deployment", name),
+		zap.String("namespace", k.namespace),
+	)
+
+	return nil
+}
+
+func (k *KubernetesWatcher) deploymentError(name, op string, err error) error {
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("%w: %s/%s", ErrDeploymentNotFound, k.namespace, name)
+	}
+	return fmt.Errorf("failed to %s deployment %s/%s: %w", op, k.namespace, name, err)
+}
+
 type PodMetric struct {
 	Name      string `json:"name"`
 	Namespace string `json:"namespace"`
@@ -0,0 +1,380 @@
+package observer
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/eventbus"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// newTestPod returns a running pod of the app whose single container has
+// restarted the given number of times
+func newTestPod(name, namespace, app string, restarts int32) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{"app": app},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: app, RestartCount: restarts},
+			},
+		},
+	}
+}
+
+// newTestWatcher returns a watcher without a clientset or database
+func newTestWatcher(namespace string) *KubernetesWatcher {
+	return newKubernetesWatcher(nil, namespace, nil, zap.NewNop())
+}
+
+// drain returns the events already buffered on ch
+func drain(ch <-chan eventbus.Event) []eventbus.Event {
+	var events []eventbus.Event
+	for {
+		select {
+		case e := <-ch:
+			events = append(events, e)
+		case <-time.After(10 * time.Millisecond):
+			return events
+		}
+	}
+}
+
+func TestTrackRestartsPublishesCrashLoopOnce(t *testing.T) {
+	bus := eventbus.New(zap.NewNop())
+	crashLoops := bus.Subscribe(eventbus.EventCrashLoop, 10)
+
+	k := newTestWatcher("default")
+	k.SetEventBus(bus)
+
+	for _, restarts := range []int32{0, 1, 2} {
+		k.trackRestarts(newTestPod("checkout-7d9f-abc12", "default", "checkout", restarts), string(watch.Modified), restarts)
+	}
+	if events := drain(crashLoops); len(events) != 0 {
+		t.Fatalf("published %d CrashLoop events below the threshold", len(events))
+	}
+
+	// Crossing the threshold publishes once; further restarts don't repeat it
+	for _, restarts := range []int32{crashLoopRestartThreshold, crashLoopRestartThreshold + 1, crashLoopRestartThreshold + 4} {
+		k.trackRestarts(newTestPod("checkout-7d9f-abc12", "default", "checkout", restarts), string(watch.Modified), restarts)
+	}
+
+	events := drain(crashLoops)
+	if len(events) != 1 {
+		t.Fatalf("published %d CrashLoop events, want 1", len(events))
+	}
+	event := events[0]
+	if event.Service != "checkout" || event.Pod != "checkout-7d9f-abc12" || event.Namespace != "default" {
+		t.Errorf("event = %+v, want service checkout on pod checkout-7d9f-abc12 in default", event)
+	}
+	if event.Payload["restarts"] != int32(crashLoopRestartThreshold) || event.Payload["previous_restarts"] != int32(2) {
+		t.Errorf("payload = %v, want restarts %d after 2", event.Payload, crashLoopRestartThreshold)
+	}
+}
+
+func TestTrackRestartsForgetsDeletedPods(t *testing.T) {
+	bus := eventbus.New(zap.NewNop())
+	crashLoops := bus.Subscribe(eventbus.EventCrashLoop, 10)
+
+	k := newTestWatcher("default")
+	k.SetEventBus(bus)
+
+	pod := newTestPod("worker-0", "default", "worker", 5)
+	k.trackRestarts(pod, string(watch.Modified), 5)
+	k.trackRestarts(pod, string(watch.Deleted), 5)
+
+	// A recreated pod with the same name that loops again is reported again
+	k.trackRestarts(pod, string(watch.Added), 6)
+
+	if events := drain(crashLoops); len(events) != 2 {
+		t.Errorf("published %d CrashLoop events, want one per pod incarnation", len(events))
+	}
+}
+
+func TestPodService(t *testing.T) {
+	pod := newTestPod("api-1", "default", "api", 0)
+	pod.Labels["app.kubernetes.io/name"] = "api-server"
+	if got := podService(pod); got != "api-server" {
+		t.Errorf("podService = %q, want the app.kubernetes.io/name label", got)
+	}
+
+	unlabelled := newTestPod("standalone", "default", "", 0)
+	unlabelled.Labels = nil
+	if got := podService(unlabelled); got != "standalone" {
+		t.Errorf("podService = %q, want the pod name", got)
+	}
+}
+
+// listPods answers pod lists with the given lists in turn, repeating the last,
+// and returns the number of calls made
+func listPods(fakeClient *fake.Clientset, lists ...*corev1.PodList) *int {
+	calls := 0
+	fakeClient.PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		list := lists[len(lists)-1]
+		if calls < len(lists) {
+			list = lists[calls]
+		}
+		calls++
+		return true, list, nil
+	})
+	return &calls
+}
+
+// podList returns a list at the resourceVersion holding the pods
+func podList(resourceVersion string, pods ...*corev1.Pod) *corev1.PodList {
+	list := &corev1.PodList{ListMeta: metav1.ListMeta{ResourceVersion: resourceVersion}}
+	for _, pod := range pods {
+		list.Items = append(list.Items, *pod)
+	}
+	return list
+}
+
+// versionedPod returns a test pod with a UID and resourceVersion
+func versionedPod(name, uid, resourceVersion string) *corev1.Pod {
+	pod := newTestPod(name, "default", "checkout", 0)
+	pod.UID = types.UID(uid)
+	pod.ResourceVersion = resourceVersion
+	return pod
+}
+
+var errExpired = &metav1.Status{
+	Status:  metav1.StatusFailure,
+	Code:    http.StatusGone,
+	Reason:  metav1.StatusReasonExpired,
+	Message: "too old resource version: 100 (150)",
+}
+
+func TestWatchRelistsAfterResourceVersionExpired(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	lists := listPods(fakeClient,
+		podList("100", versionedPod("checkout-a", "uid-a", "90"), versionedPod("checkout-b", "uid-b", "95")),
+		podList("200", versionedPod("checkout-b", "uid-b", "180")),
+	)
+
+	// The first watch fails mid-stream with 410 Gone; the second ends after
+	// a bookmark
+	expired := watch.NewFakeWithChanSize(1, false)
+	expired.Error(errExpired)
+	resumed := watch.NewFakeWithChanSize(1, false)
+	resumed.Action(watch.Bookmark, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{ResourceVersion: "250"}})
+	resumed.Stop()
+
+	watchers := []*watch.FakeWatcher{expired, resumed}
+	var watchedFrom []string
+	fakeClient.PrependWatchReactor("pods", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		watchedFrom = append(watchedFrom, action.(k8stesting.WatchActionImpl).GetWatchRestrictions().ResourceVersion)
+		w := watchers[0]
+		watchers = watchers[1:]
+		return true, w, nil
+	})
+
+	// No database: a re-list must not save anything
+	k := newKubernetesWatcher(fakeClient, "default", nil, zap.NewNop())
+	ctx := context.Background()
+
+	if err := k.watchPodsOnce(ctx); err != nil {
+		t.Fatalf("expired watch returned %v, want nil so the caller re-lists straight away", err)
+	}
+	if k.resourceVersion != "" {
+		t.Errorf("resourceVersion = %q after 410, want it cleared", k.resourceVersion)
+	}
+
+	if err := k.watchPodsOnce(ctx); err == nil {
+		t.Fatal("watch ended by a closed channel returned nil, want an error")
+	}
+	if *lists != 2 {
+		t.Errorf("listed %d times, want one list per resourceVersion", *lists)
+	}
+	if len(watchedFrom) != 2 || watchedFrom[0] != "100" || watchedFrom[1] != "200" {
+		t.Errorf("watched from %v, want [100 200]", watchedFrom)
+	}
+	if k.resourceVersion != "250" {
+		t.Errorf("resourceVersion = %q, want the bookmark's 250", k.resourceVersion)
+	}
+
+	// The re-list resynced per-pod state to the pods that still exist
+	if _, ok := k.handledVersions["uid-a"]; ok {
+		t.Error("deleted pod checkout-a still tracked after the re-list")
+	}
+	if got := k.handledVersions["uid-b"]; got != "180" {
+		t.Errorf("checkout-b handled at %q, want the re-listed 180", got)
+	}
+	if _, ok := k.lastRestarts["checkout-a"]; ok {
+		t.Error("deleted pod checkout-a still has restart tracking")
+	}
+}
+
+func TestWatchStartExpiredClearsResourceVersion(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	fakeClient.PrependWatchReactor("pods", func(k8stesting.Action) (bool, watch.Interface, error) {
+		return true, nil, apierrors.NewResourceExpired("too old resource version")
+	})
+
+	k := newKubernetesWatcher(fakeClient, "default", nil, zap.NewNop())
+	k.resourceVersion = "100"
+	if err := k.watchPodsOnce(context.Background()); err != nil {
+		t.Fatalf("watchPodsOnce = %v, want nil", err)
+	}
+	if k.resourceVersion != "" {
+		t.Errorf("resourceVersion = %q, want it cleared for a re-list", k.resourceVersion)
+	}
+}
+
+func TestHandlePodEventSkipsHandledVersion(t *testing.T) {
+	// No database: an already handled version must not be saved again
+	k := newTestWatcher("default")
+	k.handledVersions["uid-a"] = "120"
+
+	pod := versionedPod("checkout-a", "uid-a", "120")
+	if err := k.handlePodEvent(context.Background(), watch.Event{Type: watch.Modified, Object: pod}); err != nil {
+		t.Fatalf("handlePodEvent = %v, want nil", err)
+	}
+	if k.resourceVersion != "120" {
+		t.Errorf("resourceVersion = %q, want 120", k.resourceVersion)
+	}
+}
+
+// TestHandlePodEventAdvancesOnlyAfterSave checks a failed save leaves the
+// watch where it was, so the event is delivered again
+func TestHandlePodEventAdvancesOnlyAfterSave(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+
+	closed, err := storage.NewPostgresClient(storagetest.URL(t), zap.NewNop())
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	closed.Close()
+
+	k := newKubernetesWatcher(nil, "default", closed, zap.NewNop())
+	k.resourceVersion = "100"
+
+	pod := versionedPod(service+"-7d9f-abc12", "uid-a", "120")
+	event := watch.Event{Type: watch.Added, Object: pod}
+	if err := k.handlePodEvent(context.Background(), event); err == nil {
+		t.Fatal("handlePodEvent on a closed database returned nil")
+	}
+	if k.resourceVersion != "100" {
+		t.Errorf("resourceVersion = %q after a failed save, want 100", k.resourceVersion)
+	}
+	if _, ok := k.handledVersions["uid-a"]; ok {
+		t.Error("pod marked handled after a failed save")
+	}
+
+	k.db = db
+	if err := k.handlePodEvent(context.Background(), event); err != nil {
+		t.Fatalf("redelivered event: %v", err)
+	}
+	if k.resourceVersion != "120" || k.handledVersions["uid-a"] != "120" {
+		t.Errorf("after save: resourceVersion %q, handled %q, want 120 for both", k.resourceVersion, k.handledVersions["uid-a"])
+	}
+}
+
+// oomKilledPod returns a pod whose container was last OOMKilled at finishedAt
+func oomKilledPod(name string, finishedAt time.Time) *corev1.Pod {
+	pod := newTestPod(name, "default", "checkout", 1)
+	pod.Status.ContainerStatuses[0].LastTerminationState.Terminated = &corev1.ContainerStateTerminated{
+		Reason:     "OOMKilled",
+		FinishedAt: metav1.NewTime(finishedAt),
+	}
+	return pod
+}
+
+// unschedulablePod returns a pending pod the scheduler could not place
+func unschedulablePod(name string) *corev1.Pod {
+	pod := newTestPod(name, "default", "checkout", 0)
+	pod.Status.Phase = corev1.PodPending
+	pod.Status.Conditions = []corev1.PodCondition{{
+		Type:    corev1.PodScheduled,
+		Status:  corev1.ConditionFalse,
+		Reason:  corev1.PodReasonUnschedulable,
+		Message: "0/3 nodes are available: 3 Insufficient memory.",
+	}}
+	return pod
+}
+
+func TestPodConditionReaders(t *testing.T) {
+	killedAt := time.Now().Add(-time.Minute).Truncate(time.Second)
+	if at, container := lastOOMKill(oomKilledPod("checkout-1", killedAt)); !at.Equal(killedAt) || container != "checkout" {
+		t.Errorf("lastOOMKill = %v, %q, want %v, checkout", at, container, killedAt)
+	}
+	if at, _ := lastOOMKill(newTestPod("checkout-2", "default", "checkout", 3)); !at.IsZero() {
+		t.Errorf("lastOOMKill of a pod never OOMKilled = %v, want zero", at)
+	}
+
+	if message, ok := unschedulableMessage(unschedulablePod("checkout-3")); !ok || message == "" {
+		t.Errorf("unschedulableMessage = %q, %v, want the scheduler's message", message, ok)
+	}
+	if _, ok := unschedulableMessage(newTestPod("checkout-4", "default", "checkout", 0)); ok {
+		t.Error("running pod reported unschedulable")
+	}
+}
+
+func TestSeedPodConditionsSavesNothing(t *testing.T) {
+	// No database: seeding from a list must only record state
+	k := newTestWatcher("default")
+	killedAt := time.Now().Add(-time.Minute)
+	k.seedPodConditions(oomKilledPod("checkout-1", killedAt))
+	k.seedPodConditions(unschedulablePod("checkout-2"))
+
+	if !k.lastOOMKill["checkout-1"].Equal(killedAt) || !k.unschedulable["checkout-2"] {
+		t.Errorf("seeded state = %v, %v", k.lastOOMKill, k.unschedulable)
+	}
+
+	// The same OOM kill and scheduling failure seen again by the watch are
+	// not new, so nothing is saved
+	k.recordPodConditions(context.Background(), oomKilledPod("checkout-1", killedAt), string(watch.Modified))
+	k.recordPodConditions(context.Background(), unschedulablePod("checkout-2"), string(watch.Modified))
+
+	k.recordPodConditions(context.Background(), oomKilledPod("checkout-1", killedAt), string(watch.Deleted))
+	if _, ok := k.lastOOMKill["checkout-1"]; ok {
+		t.Error("deleted pod's OOM state kept")
+	}
+}
+
+func TestRecordPodConditionsSavesEachConditionOnce(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+
+	k := newKubernetesWatcher(nil, "default", db, zap.NewNop())
+	killedAt := time.Now().Add(-time.Minute).Truncate(time.Second)
+	oomPod := oomKilledPod(service+"-7d9f-a", killedAt)
+	pendingPod := unschedulablePod(service + "-7d9f-b")
+	for i := 0; i < 2; i++ {
+		k.recordPodConditions(ctx, oomPod, string(watch.Modified))
+		k.recordPodConditions(ctx, pendingPod, string(watch.Modified))
+	}
+
+	// A later OOM kill of the same pod is a new event
+	k.recordPodConditions(ctx, oomKilledPod(service+"-7d9f-a", killedAt.Add(30*time.Second)), string(watch.Modified))
+
+	events, err := db.GetServiceEvents(ctx, service,
+		[]string{storage.EventOOMKilled, storage.EventFailedScheduling}, time.Now().Add(-time.Hour), time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("GetServiceEvents: %v", err)
+	}
+	counts := make(map[string]int)
+	for _, e := range events {
+		counts[e.EventType]++
+	}
+	if counts[storage.EventOOMKilled] != 2 || counts[storage.EventFailedScheduling] != 1 {
+		t.Errorf("saved events = %v, want 2 OOMKilled and 1 FailedScheduling", counts)
+	}
+}
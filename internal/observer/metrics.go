@@ -2,6 +2,7 @@ package observer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,9 +10,13 @@ import (
 	"go.uber.org/zap"
 )
 
+// ErrNamespaceNotWatched is returned when pods are requested for a namespace
+// no watcher covers
+var ErrNamespaceNotWatched = errors.New("namespace not watched")
+
 type MetricsObserver struct {
 	prometheus *PrometheusClient
-	kubernetes *KubernetesWatcher
+	kubernetes []*KubernetesWatcher // one per watched namespace; empty without Kubernetes
 	db         *storage.PostgresClient
 	logger     *zap.Logger
 }
@@ -19,7 +24,7 @@ type MetricsObserver struct {
 func NewMetricsObserver(
 	prometheusURL string,
 	scrapeInterval time.Duration,
-	k8sNamespace string,
+	k8sNamespaces []string,
 	db *storage.PostgresClient,
 	logger *zap.Logger,
 ) (*MetricsObserver, error) {
@@ -28,20 +33,33 @@ func NewMetricsObserver(
 		return nil, fmt.Errorf("failed to create prometheus client: %w", err)
 	}
 
-	k8sWatcher, err := NewKubernetesWatcher(k8sNamespace, db, logger)
+	k8sWatchers, err := NewKubernetesWatchers(k8sNamespaces, db, logger)
 	if err != nil {
 		logger.Warn("Kubernetes watcher not available", zap.Error(err))
-		k8sWatcher = nil
+		k8sWatchers = nil
 	}
 
 	return &MetricsObserver{
 		prometheus: promClient,
-		kubernetes: k8sWatcher,
+		kubernetes: k8sWatchers,
 		db:         db,
 		logger:     logger,
 	}, nil
 }
 
+// Probe checks Prometheus connectivity before the observer starts. An
+// unreachable Prometheus is logged and reported as degraded rather than
+// failing startup, so the API and database-backed endpoints keep serving.
+func (m *MetricsObserver) Probe(ctx context.Context) ReadinessStatus {
+	if err := m.prometheus.Probe(ctx); err != nil {
+		m.logger.Warn("Prometheus unreachable at startup; observer running degraded until a scrape succeeds",
+			zap.String("url", m.prometheus.url),
+			zap.Error(err),
+		)
+	}
+	return m.prometheus.Readiness()
+}
+
 func (m *MetricsObserver) Start(ctx context.Context) error {
 	go func() {
 		if err := m.prometheus.Start(ctx); err != nil && err != context.Canceled {
@@ -49,12 +67,12 @@ func (m *MetricsObserver) Start(ctx context.Context) error {
 		}
 	}()
 
-	if m.kubernetes != nil {
-		go func() {
-			if err := m.kubernetes.Start(ctx); err != nil && err != context.Canceled {
-				m.logger.Error("Kubernetes error", zap.Error(err))
+	for _, watcher := range m.kubernetes {
+		go func(watcher *KubernetesWatcher) {
+			if err := watcher.Start(ctx); err != nil && err != context.Canceled {
+				m.logger.Error("Kubernetes error", zap.String("namespace", watcher.namespace), zap.Error(err))
 			}
-		}()
+		}(watcher)
 	}
 
 	<-ctx.Done()
@@ -110,9 +128,9 @@ func (m *MetricsObserver) Health(ctx context.Context) error {
 		return fmt.Errorf("prometheus health check failed: %w", err)
 	}
 
-	if m.kubernetes != nil {
-		if err := m.kubernetes.Health(ctx); err != nil {
-			m.logger.Warn("Kubernetes health check failed", zap.Error(err))
+	for _, watcher := range m.kubernetes {
+		if err := watcher.Health(ctx); err != nil {
+			m.logger.Warn("Kubernetes health check failed", zap.String("namespace", watcher.namespace), zap.Error(err))
 		}
 	}
 
@@ -147,9 +165,71 @@ func (s *ServiceMetrics) IsHealthy(cpuThreshold, memThreshold, errorRateThreshol
 	return true
 }
 
-func (m *MetricsObserver) GetKubernetesPods(ctx context.Context) ([]PodMetric, error) {
-	if m.kubernetes == nil {
+// GetKubernetesPods lists the pods of one watched namespace, or of all of
+// them when namespace is "". A namespace that fails to list is logged and
+// left out so the others are still reported; the call only fails when none
+// could be listed.
+func (m *MetricsObserver) GetKubernetesPods(ctx context.Context, namespace string) ([]PodMetric, error) {
+	if len(m.kubernetes) == 0 {
 		return nil, fmt.Errorf("kubernetes watcher not initialized")
 	}
-	return m.kubernetes.GetPodMetrics(ctx)
+
+	var (
+		pods    []PodMetric
+		lastErr error
+		listed  bool
+	)
+	for _, watcher := range m.kubernetes {
+		if namespace != "" && watcher.namespace != namespace {
+			continue
+		}
+		nsPods, err := watcher.GetPodMetrics(ctx)
+		if err != nil {
+			m.logger.Warn("Failed to list pods", zap.String("namespace", watcher.namespace), zap.Error(err))
+			lastErr = err
+			continue
+		}
+		listed = true
+		pods = append(pods, nsPods...)
+	}
+
+	switch {
+	case listed:
+		if pods == nil {
+			pods = []PodMetric{}
+		}
+		return pods, nil
+	case lastErr != nil:
+		return nil, lastErr
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrNamespaceNotWatched, namespace)
+	}
+}
+
+// Kubernetes returns the watcher of the first watched namespace, the one the
+// actuator acts in, or nil when Kubernetes is unavailable
+func (m *MetricsObserver) Kubernetes() *KubernetesWatcher {
+	if len(m.kubernetes) == 0 {
+		return nil
+	}
+	return m.kubernetes[0]
+}
+
+// KubernetesWatchers returns every namespace watcher, in config order
+func (m *MetricsObserver) KubernetesWatchers() []*KubernetesWatcher {
+	return m.kubernetes
+}
+
+// WatchedNamespaces lists the namespaces with a running watcher
+func (m *MetricsObserver) WatchedNamespaces() []string {
+	namespaces := make([]string, 0, len(m.kubernetes))
+	for _, watcher := range m.kubernetes {
+		namespaces = append(namespaces, watcher.namespace)
+	}
+	return namespaces
+}
+
+// Prometheus returns the Prometheus scraper
+func (m *MetricsObserver) Prometheus() *PrometheusClient {
+	return m.prometheus
 }
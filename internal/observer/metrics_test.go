@@ -0,0 +1,128 @@
+package observer
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// newTestNamespaceObserver returns an observer watching the namespaces
+// through one fake clientset holding the pods
+func newTestNamespaceObserver(fakeClient *fake.Clientset, namespaces ...string) *MetricsObserver {
+	m := &MetricsObserver{logger: zap.NewNop()}
+	for _, namespace := range namespaces {
+		m.kubernetes = append(m.kubernetes, newKubernetesWatcher(fakeClient, namespace, nil, zap.NewNop()))
+	}
+	return m
+}
+
+func podNames(pods []PodMetric) []string {
+	names := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		names = append(names, pod.Namespace+"/"+pod.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestGetKubernetesPodsAcrossNamespaces(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(
+		newTestPod("checkout-1", "shop", "checkout", 0),
+		newTestPod("cart-1", "shop", "cart", 2),
+		newTestPod("invoices-1", "billing", "invoices", 0),
+		newTestPod("batch-1", "jobs", "batch", 0),
+	)
+	m := newTestNamespaceObserver(fakeClient, "shop", "billing")
+	ctx := context.Background()
+
+	if got := m.WatchedNamespaces(); !slices.Equal(got, []string{"shop", "billing"}) {
+		t.Errorf("WatchedNamespaces = %v, want [shop billing]", got)
+	}
+	if got := m.Kubernetes(); got == nil || got.Namespace() != "shop" {
+		t.Errorf("Kubernetes() = %v, want the first namespace's watcher", got)
+	}
+
+	tests := []struct {
+		namespace string
+		want      []string
+	}{
+		{"", []string{"billing/invoices-1", "shop/cart-1", "shop/checkout-1"}},
+		{"billing", []string{"billing/invoices-1"}},
+	}
+	for _, tt := range tests {
+		pods, err := m.GetKubernetesPods(ctx, tt.namespace)
+		if err != nil {
+			t.Fatalf("GetKubernetesPods(%q): %v", tt.namespace, err)
+		}
+		if got := podNames(pods); !slices.Equal(got, tt.want) {
+			t.Errorf("GetKubernetesPods(%q) = %v, want %v", tt.namespace, got, tt.want)
+		}
+	}
+
+	if _, err := m.GetKubernetesPods(ctx, "jobs"); !errors.Is(err, ErrNamespaceNotWatched) {
+		t.Errorf("unwatched namespace: err = %v, want ErrNamespaceNotWatched", err)
+	}
+}
+
+func TestGetKubernetesPodsSkipsFailingNamespace(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(
+		newTestPod("checkout-1", "shop", "checkout", 0),
+		newTestPod("invoices-1", "billing", "invoices", 0),
+	)
+	fakeClient.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetNamespace() == "billing" {
+			return true, nil, errors.New("forbidden")
+		}
+		return false, nil, nil
+	})
+	m := newTestNamespaceObserver(fakeClient, "shop", "billing")
+	ctx := context.Background()
+
+	pods, err := m.GetKubernetesPods(ctx, "")
+	if err != nil {
+		t.Fatalf("GetKubernetesPods: %v", err)
+	}
+	if got := podNames(pods); !slices.Equal(got, []string{"shop/checkout-1"}) {
+		t.Errorf("pods = %v, want only shop's", got)
+	}
+
+	if _, err := m.GetKubernetesPods(ctx, "billing"); err == nil || errors.Is(err, ErrNamespaceNotWatched) {
+		t.Errorf("failing namespace alone: err = %v, want its list error", err)
+	}
+}
+
+func TestGetKubernetesPodsWithoutKubernetes(t *testing.T) {
+	m := &MetricsObserver{logger: zap.NewNop()}
+	if _, err := m.GetKubernetesPods(context.Background(), ""); err == nil {
+		t.Error("GetKubernetesPods without watchers succeeded, want an error")
+	}
+	if m.Kubernetes() != nil || len(m.WatchedNamespaces()) != 0 {
+		t.Error("observer without watchers reports one")
+	}
+}
+
+// TestNamespaceWatchStatusIsIndependent checks a failing watch in one
+// namespace leaves the other's status untouched
+func TestNamespaceWatchStatusIsIndependent(t *testing.T) {
+	m := newTestNamespaceObserver(fake.NewSimpleClientset(), "shop", "billing")
+	shop, billing := m.KubernetesWatchers()[0], m.KubernetesWatchers()[1]
+
+	shop.recordWatchConnected()
+	billing.recordWatchEnd(errors.New("forbidden"), time.Now().Add(time.Second))
+	billing.recordWatchEnd(errors.New("forbidden"), time.Now().Add(2*time.Second))
+
+	if s := shop.WatchStatus(); !s.Connected || s.ConsecutiveFailures != 0 {
+		t.Errorf("shop status = %+v, want connected without failures", s)
+	}
+	if s := billing.WatchStatus(); s.Connected || s.ConsecutiveFailures != 2 {
+		t.Errorf("billing status = %+v, want two consecutive failures", s)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
@@ -18,6 +19,16 @@ type PrometheusClient struct {
 	api      promv1.API // Api of Prometheus 
 	url      string // url we have of Prometheus 
 	interval time.Duration // Type Time Interval 
+
+	// metricIntervals slows individual metrics below the base interval
+	metricIntervals map[string]time.Duration
+
+	// scrapeTimeout bounds each scheduled scrape query and queryTimeout each
+	// ad-hoc Query, so a slow user query can't eat into collection
+	scrapeTimeout time.Duration
+	queryTimeout  time.Duration
+
+	readiness Readiness
 	db       *storage.PostgresClient// db Postgres Client 
 	logger   *zap.Logger// Logger 
 }
@@ -35,16 +46,105 @@ func NewPrometheusClient(prometheusURL string, scrapeInterval time.Duration, db
 		api:      promv1.NewAPI(client),
 		url:      prometheusURL,
 		interval: scrapeInterval,
+		scrapeTimeout: defaultPrometheusTimeout,
+		queryTimeout:  defaultPrometheusTimeout,
 		db:       db,
 		logger:   logger,
 	}, nil
 }// new client with the given configuratiuon has started and then returned 
 
+// defaultPrometheusTimeout bounds scrape and ad-hoc queries unless SetTimeouts overrides it
+const defaultPrometheusTimeout = 10 * time.Second
+
+// SetTimeouts sets the scrape and ad-hoc query timeouts; non-positive values
+// keep the current ones. Must be called before Start.
+func (p *PrometheusClient) SetTimeouts(scrape, query time.Duration) {
+	if scrape > 0 {
+		p.scrapeTimeout = scrape
+	}
+	if query > 0 {
+		p.queryTimeout = query
+	}
+}
+
+// QueryTimeout is how long an ad-hoc Query may run
+func (p *PrometheusClient) QueryTimeout() time.Duration {
+	return p.queryTimeout
+}
+
+// scrapedMetric is a PromQL query and the metric name its samples are stored under
+type scrapedMetric struct {
+	query      string
+	metricName string
+}
+
+var scrapedMetrics = []scrapedMetric{
+	{"cpu_usage_percent", "cpu_usage"},
+	{"memory_usage_percent", "memory_usage"},
+	{"http_requests_total", "http_requests"},
+	{"http_request_duration_seconds", "http_latency"},
+	{"app_errors_total", "error_count"},
+	// Gauges exported by apps that compute their own rate and p95; they take
+	// precedence over error_count and the histogram percentiles when present
+	{"error_rate_per_min", "error_rate"},
+	{"response_time_p95_ms", "response_time"},
+	// Latency percentiles computed server-side from the request duration histogram
+	{latencyQuantileQuery(0.50), "response_time_p50_ms"},
+	{latencyQuantileQuery(0.95), "response_time_p95_ms"},
+	{latencyQuantileQuery(0.99), "response_time_p99_ms"},
+}
+
+// SetMetricIntervals scrapes the named metrics less often than the base
+// interval. Intervals are rounded up to whole base ticks; shorter ones and
+// unknown metric names are ignored. Must be called before Start.
+func (p *PrometheusClient) SetMetricIntervals(intervals map[string]time.Duration) {
+	known := make(map[string]bool, len(scrapedMetrics))
+	for _, m := range scrapedMetrics {
+		known[m.metricName] = true
+	}
+
+	p.metricIntervals = make(map[string]time.Duration, len(intervals))
+	for name, interval := range intervals {
+		if !known[name] {
+			p.logger.Warn("Ignoring interval for unknown scraped metric", zap.String("metric", name))
+			continue
+		}
+		if interval > p.interval {
+			p.metricIntervals[name] = interval
+		}
+	}
+}
+
+// Interval returns the base scrape interval
+func (p *PrometheusClient) Interval() time.Duration {
+	return p.interval
+}
+
+// MetricIntervals returns the effective interval of every scraped metric
+func (p *PrometheusClient) MetricIntervals() map[string]time.Duration {
+	intervals := make(map[string]time.Duration, len(scrapedMetrics))
+	for _, m := range scrapedMetrics {
+		intervals[m.metricName] = time.Duration(p.everyTicks(m.metricName)) * p.interval
+	}
+	return intervals
+}
+
+// everyTicks is how many base ticks pass between scrapes of a metric
+func (p *PrometheusClient) everyTicks(metricName string) int {
+	interval, ok := p.metricIntervals[metricName]
+	if !ok {
+		return 1
+	}
+	return int((interval + p.interval - 1) / p.interval)
+}
+
 func (p *PrometheusClient) Start(ctx context.Context) error {
 	ticker := time.NewTicker(p.interval)
 	defer ticker.Stop()
 
-	if err := p.scrapeAllMetrics(ctx); err != nil {
+	// Every metric is scraped on tick 0; slower ones then every N ticks
+	tick := 0
+	if err := p.scrapeAllMetrics(ctx, tick); err != nil {
 		p.logger.Error("Initial metric scrape failed", zap.Error(err))
 	}
 
@@ -53,39 +153,61 @@ func (p *PrometheusClient) Start(ctx context.Context) error {
 		case <-ctx.Done(): //context is done
 			return ctx.Err() //return the error because context is done and then error 
 		case <-ticker.C: //ticker channel in easy language this is used to trigger events at regular intervals
-			if err := p.scrapeAllMetrics(ctx); err != nil { // scrape all metrics
+			tick++
+			if err := p.scrapeAllMetrics(ctx, tick); err != nil { // scrape all metrics
 				p.logger.Error("Metric scrape failed", zap.Error(err))// if error occurs
 			}
 		}
 	} //p.interval se time for ticker set kar diya hai and then we are scrapping all metrics at that interval
 }
 
-func (p *PrometheusClient) scrapeAllMetrics(ctx context.Context) error {
-	metrics := []struct {
-		query      string
-		metricName string
-	}{
-		{"cpu_usage_percent", "cpu_usage"},
-		{"memory_usage_percent", "memory_usage"},
-		{"http_requests_total", "http_requests"},
-		{"http_request_duration_seconds", "http_latency"},
-		{"app_errors_total", "error_count"},
-	} //array of strcut i have made 
-
-	var collectedMetrics []*storage.Metric
+// scrapeAllMetrics scrapes every metric that is due on this tick
+func (p *PrometheusClient) scrapeAllMetrics(ctx context.Context, tick int) error {
 	timestamp := time.Now() //we need it because we are using it as a timestamp for all metrics
 
-	for _, m := range metrics {
-		result, err := p.queryMetric(ctx, m.query) //model.vector of that query and then we are storing result 
+	// Readiness only needs Prometheus to have answered, not to have had samples
+	collectedMetrics, due, answered, queryErr := p.collectMetrics(ctx, tick, timestamp)
+
+	if len(collectedMetrics) > 0 {
+		if err := p.db.BatchSaveMetrics(ctx, collectedMetrics); err != nil {
+			err = fmt.Errorf("failed to save metrics batch: %w", err)
+			p.readiness.recordScrape(answered > 0, err)
+			return err
+		}
+	} //Save kardiya Batch metrics ko 
+
+	if due > 0 {
+		p.readiness.recordScrape(answered > 0, queryErr)
+	}
+	return nil
+}
+
+// collectMetrics queries every metric that is due on this tick. It returns
+// the samples, how many queries were due and answered, and the last query error.
+func (p *PrometheusClient) collectMetrics(ctx context.Context, tick int, timestamp time.Time) (collectedMetrics []*storage.Metric, due, answered int, queryErr error) {
+	for _, m := range scrapedMetrics {
+		if tick%p.everyTicks(m.metricName) != 0 {
+			continue
+		}
+		due++
+
+		result, err := p.queryMetric(ctx, m.query, p.scrapeTimeout) //model.vector of that query and then we are storing result 
 		if err != nil { 
 			p.logger.Warn("Failed to query metric",
 				zap.String("metric", m.metricName),
 				zap.Error(err),
 			)
+			queryErr = err
 			continue //bahar niklo
 		}
+		answered++
 
 		for _, sample := range result {
+			// histogram_quantile yields NaN when there was no traffic in the range
+			if math.IsNaN(float64(sample.Value)) || math.IsInf(float64(sample.Value), 0) {
+				continue
+			}
+
 			metric := &storage.Metric{
 				Timestamp:   timestamp,
 				ServiceName: string(sample.Metric["service"]),
@@ -102,17 +224,33 @@ func (p *PrometheusClient) scrapeAllMetrics(ctx context.Context) error {
 		}
 	}// collected metrics ka array i have made and also 
 
-	if len(collectedMetrics) > 0 {
-		if err := p.db.BatchSaveMetrics(ctx, collectedMetrics); err != nil {
-			return fmt.Errorf("failed to save metrics batch: %w", err)
-		}
-	} //Save kardiya Batch metrics ko 
+	return collectedMetrics, due, answered, queryErr
+}
 
-	return nil
+// Probe checks that Prometheus answers. A failure leaves the scraper running
+// but reported as degraded until a scrape gets through.
+func (p *PrometheusClient) Probe(ctx context.Context) error {
+	err := p.Health(ctx)
+	p.readiness.recordProbe(err)
+	return err
+}
+
+// Readiness returns the scraper's startup milestones
+func (p *PrometheusClient) Readiness() ReadinessStatus {
+	return p.readiness.Status()
 }
 
-func (p *PrometheusClient) queryMetric(ctx context.Context, query string) (model.Vector, error) {
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+// latencyQuantileQuery builds a histogram_quantile query over the request
+// duration histogram, converted from seconds to milliseconds
+func latencyQuantileQuery(quantile float64) string {
+	return fmt.Sprintf(
+		"histogram_quantile(%.2f, sum(rate(http_request_duration_seconds_bucket[1m])) by (le, service)) * 1000",
+		quantile,
+	)
+}
+
+func (p *PrometheusClient) queryMetric(ctx context.Context, query string, timeout time.Duration) (model.Vector, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	result, warnings, err := p.api.Query(ctx, query, time.Now()) // this is prometheus api call and query prom.api
@@ -133,6 +271,13 @@ func (p *PrometheusClient) queryMetric(ctx context.Context, query string) (model
 	return vector, nil //return the vector
 } 
 
+// Query runs an ad-hoc instant PromQL query and returns its samples. It is
+// bounded by the query timeout; running over yields an error wrapping
+// context.DeadlineExceeded.
+func (p *PrometheusClient) Query(ctx context.Context, query string) (model.Vector, error) {
+	return p.queryMetric(ctx, query, p.queryTimeout)
+}
+
 func (p *PrometheusClient) Health(ctx context.Context) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
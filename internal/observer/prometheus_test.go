@@ -0,0 +1,239 @@
+package observer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// promSample is one element of a fake instant-query vector
+type promSample struct {
+	labels map[string]string
+	value  float64
+}
+
+// newFakePrometheus serves /api/v1/query, answering each query with the
+// samples respond returns for it
+func newFakePrometheus(t *testing.T, respond func(query string) []promSample) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query" {
+			http.NotFound(w, r)
+			return
+		}
+
+		var result []string
+		for _, s := range respond(r.FormValue("query")) {
+			var labels []string
+			for k, v := range s.labels {
+				labels = append(labels, fmt.Sprintf("%q:%q", k, v))
+			}
+			result = append(result, fmt.Sprintf(`{"metric":{%s},"value":[%d,%q]}`,
+				strings.Join(labels, ","), time.Now().Unix(), strconv.FormatFloat(s.value, 'f', -1, 64)))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":[%s]}}`, strings.Join(result, ","))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestPrometheusClient(t *testing.T, url string) *PrometheusClient {
+	t.Helper()
+
+	p, err := NewPrometheusClient(url, time.Second, nil, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewPrometheusClient: %v", err)
+	}
+	return p
+}
+
+var quantileQueryPattern = regexp.MustCompile(`^histogram_quantile\(([0-9.]+), sum\(rate\(http_request_duration_seconds_bucket\[1m\]\)\) by \(le, service\)\) \* 1000$`)
+
+func TestCollectHistogramPercentiles(t *testing.T) {
+	// Request durations of checkout: 50 under 100ms, 40 more under 250ms and
+	// the last 10 under 500ms. Prometheus interpolates linearly within the
+	// bucket holding the rank:
+	//   p50: rank 50 ends the (0, 0.1] bucket          -> 0.1s
+	//   p95: rank 95 is 5 of 10 into (0.25, 0.5]       -> 0.375s
+	//   p99: rank 99 is 9 of 10 into (0.25, 0.5]       -> 0.475s
+	checkoutMs := map[string]float64{"0.50": 100, "0.95": 375, "0.99": 475}
+
+	srv := newFakePrometheus(t, func(query string) []promSample {
+		match := quantileQueryPattern.FindStringSubmatch(query)
+		if match == nil {
+			return nil
+		}
+		value, ok := checkoutMs[match[1]]
+		if !ok {
+			t.Errorf("unexpected quantile %s in %q", match[1], query)
+		}
+		return []promSample{
+			{labels: map[string]string{"service": "checkout"}, value: value},
+			// No traffic in the range: histogram_quantile yields NaN
+			{labels: map[string]string{"service": "idle"}, value: math.NaN()},
+		}
+	})
+
+	metrics, due, answered, err := newTestPrometheusClient(t, srv.URL).collectMetrics(context.Background(), 0, time.Now())
+	if err != nil {
+		t.Fatalf("collectMetrics: %v", err)
+	}
+	if due != len(scrapedMetrics) || answered != due {
+		t.Errorf("due, answered = %d, %d, want %d, %d", due, answered, len(scrapedMetrics), len(scrapedMetrics))
+	}
+
+	want := map[string]float64{
+		"response_time_p50_ms": 100,
+		"response_time_p95_ms": 375,
+		"response_time_p99_ms": 475,
+	}
+	got := make(map[string]float64)
+	for _, m := range metrics {
+		if m.ServiceName == "idle" {
+			t.Errorf("stored %s = %v for a service without traffic", m.MetricName, m.MetricValue)
+			continue
+		}
+		got[m.MetricName] = m.MetricValue
+	}
+
+	for name, value := range want {
+		if math.Abs(got[name]-value) > 1e-9 {
+			t.Errorf("%s = %v, want %v", name, got[name], value)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("collected %v, want only the percentile series", got)
+	}
+}
+
+func TestMetricIntervals(t *testing.T) {
+	p, err := NewPrometheusClient("http://localhost:9090", 10*time.Second, nil, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewPrometheusClient: %v", err)
+	}
+	p.SetMetricIntervals(map[string]time.Duration{
+		"response_time_p99_ms": 25 * time.Second, // rounded up to 3 ticks
+		"response_time_p95_ms": time.Minute,
+		"cpu_usage":            5 * time.Second, // faster than the base: ignored
+		"not_scraped":          time.Minute,     // unknown: ignored
+	})
+
+	intervals := p.MetricIntervals()
+	want := map[string]time.Duration{
+		"response_time_p99_ms": 30 * time.Second,
+		"response_time_p95_ms": time.Minute,
+		"cpu_usage":            10 * time.Second,
+		"memory_usage":         10 * time.Second,
+	}
+	for metric, interval := range want {
+		if intervals[metric] != interval {
+			t.Errorf("%s interval = %v, want %v", metric, intervals[metric], interval)
+		}
+	}
+	if _, ok := intervals["not_scraped"]; ok {
+		t.Error("reported an interval for a metric that isn't scraped")
+	}
+	if len(intervals) != len(scrapedMetrics) {
+		t.Errorf("reported %d intervals, want one per scraped metric (%d)", len(intervals), len(scrapedMetrics))
+	}
+	if p.Interval() != 10*time.Second {
+		t.Errorf("base interval = %v, want 10s", p.Interval())
+	}
+}
+
+func TestCollectMetricsSkipsMetricsNotDue(t *testing.T) {
+	var queried []string
+	srv := newFakePrometheus(t, func(query string) []promSample {
+		queried = append(queried, query)
+		return nil
+	})
+
+	p := newTestPrometheusClient(t, srv.URL)
+	p.SetMetricIntervals(map[string]time.Duration{"cpu_usage": 3 * time.Second})
+
+	// cpu_usage is due on ticks 0, 3, 6...; everything else every tick
+	for tick, wantCPU := range []bool{true, false, false, true} {
+		queried = nil
+		_, due, _, _ := p.collectMetrics(context.Background(), tick, time.Now())
+
+		scrapedCPU := false
+		for _, q := range queried {
+			if q == "cpu_usage_percent" {
+				scrapedCPU = true
+			}
+		}
+		if scrapedCPU != wantCPU {
+			t.Errorf("tick %d: scraped cpu_usage = %v, want %v", tick, scrapedCPU, wantCPU)
+		}
+		wantDue := len(scrapedMetrics)
+		if !wantCPU {
+			wantDue--
+		}
+		if due != wantDue {
+			t.Errorf("tick %d: %d queries due, want %d", tick, due, wantDue)
+		}
+	}
+}
+
+func TestQueryReturnsVector(t *testing.T) {
+	srv := newFakePrometheus(t, func(query string) []promSample {
+		if query != `up{job="postgres"} == 0` {
+			return nil
+		}
+		return []promSample{{labels: map[string]string{"instance": "db-0"}, value: 0}}
+	})
+	p := newTestPrometheusClient(t, srv.URL)
+
+	vector, err := p.Query(context.Background(), `up{job="postgres"} == 0`)
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(vector) != 1 || vector[0].Metric["instance"] != "db-0" {
+		t.Errorf("vector = %v, want the one db-0 series", vector)
+	}
+
+	vector, err = p.Query(context.Background(), `up{job="payments"} == 0`)
+	if err != nil || len(vector) != 0 {
+		t.Errorf("healthy query = %v, %v; want an empty vector", vector, err)
+	}
+}
+
+func TestQueryTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(release) })
+
+	p := newTestPrometheusClient(t, srv.URL)
+	p.SetTimeouts(0, 50*time.Millisecond)
+	if p.QueryTimeout() != 50*time.Millisecond || p.scrapeTimeout != defaultPrometheusTimeout {
+		t.Fatalf("timeouts = scrape %v, query %v; want the scrape default kept", p.scrapeTimeout, p.QueryTimeout())
+	}
+
+	start := time.Now()
+	_, err := p.Query(context.Background(), "up")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Query error = %v, want a deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Query took %v, want it cut off at the query timeout", elapsed)
+	}
+}
@@ -0,0 +1,99 @@
+package observer
+
+import (
+	"sync"
+	"time"
+)
+
+// Connectivity states of the Prometheus scraper
+const (
+	StateStarting = "starting" // not yet probed or scraped
+	StateHealthy  = "healthy"  // Prometheus has answered
+	StateDegraded = "degraded" // unreachable at startup and not answered since
+)
+
+// Readiness records the observer's startup milestones for the readiness
+// probe: whether a scrape has been attempted at all, and when one first
+// reached Prometheus
+type Readiness struct {
+	mu            sync.Mutex
+	attempted     bool
+	firstScrape   time.Time
+	degradedSince time.Time
+	lastError     string
+}
+
+// ReadinessStatus is a snapshot of Readiness
+type ReadinessStatus struct {
+	State         string     `json:"state"`
+	Initialized   bool       `json:"initialized"` // a scrape has been attempted
+	Scraped       bool       `json:"scraped"`     // a scrape has reached Prometheus
+	FirstScrapeAt *time.Time `json:"first_scrape_at,omitempty"`
+	DegradedSince *time.Time `json:"degraded_since,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+}
+
+// Degraded reports whether Prometheus was unreachable at startup and has not
+// answered a scrape since
+func (s ReadinessStatus) Degraded() bool {
+	return s.State == StateDegraded
+}
+
+// recordProbe records the startup connectivity probe. A failure marks the
+// scraper degraded until a scrape reaches Prometheus.
+func (r *Readiness) recordProbe(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err == nil || !r.firstScrape.IsZero() {
+		return
+	}
+	if r.degradedSince.IsZero() {
+		r.degradedSince = time.Now()
+	}
+	r.lastError = err.Error()
+}
+
+// recordScrape marks a scrape attempt; ok reports whether any query succeeded
+func (r *Readiness) recordScrape(ok bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.attempted = true
+	if ok {
+		if r.firstScrape.IsZero() {
+			r.firstScrape = time.Now()
+		}
+		r.degradedSince = time.Time{}
+	}
+	r.lastError = ""
+	if err != nil {
+		r.lastError = err.Error()
+	}
+}
+
+// Status returns the current milestones
+func (r *Readiness) Status() ReadinessStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	status := ReadinessStatus{
+		State:       StateStarting,
+		Initialized: r.attempted,
+		Scraped:     !r.firstScrape.IsZero(),
+		LastError:   r.lastError,
+	}
+	switch {
+	case !r.degradedSince.IsZero():
+		status.State = StateDegraded
+		since := r.degradedSince
+		status.DegradedSince = &since
+	case status.Scraped:
+		status.State = StateHealthy
+	}
+	if status.Scraped {
+		first := r.firstScrape
+		status.FirstScrapeAt = &first
+	}
+	return status
+}
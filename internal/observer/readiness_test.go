@@ -0,0 +1,79 @@
+package observer
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestReadinessMilestones(t *testing.T) {
+	var r Readiness
+
+	status := r.Status()
+	if status.State != StateStarting || status.Initialized || status.Scraped {
+		t.Fatalf("fresh status = %+v, want starting and uninitialized", status)
+	}
+
+	// Prometheus down at startup
+	r.recordProbe(errors.New("connection refused"))
+	status = r.Status()
+	if !status.Degraded() || status.DegradedSince == nil || status.LastError != "connection refused" {
+		t.Errorf("after failed probe = %+v, want degraded", status)
+	}
+
+	// A scrape that reached nothing initializes the observer but isn't a scrape
+	r.recordScrape(false, errors.New("connection refused"))
+	status = r.Status()
+	if !status.Initialized || status.Scraped || !status.Degraded() {
+		t.Errorf("after failed scrape = %+v, want initialized, unscraped, still degraded", status)
+	}
+
+	r.recordScrape(true, nil)
+	status = r.Status()
+	if status.State != StateHealthy || !status.Scraped || status.FirstScrapeAt == nil || status.DegradedSince != nil || status.LastError != "" {
+		t.Errorf("after successful scrape = %+v, want healthy", status)
+	}
+	first := *status.FirstScrapeAt
+
+	// Later failures keep the first scrape time and don't re-degrade on a probe
+	r.recordScrape(false, errors.New("timeout"))
+	r.recordProbe(errors.New("timeout"))
+	status = r.Status()
+	if !status.FirstScrapeAt.Equal(first) || status.Degraded() || status.LastError != "timeout" {
+		t.Errorf("after later failure = %+v, want first scrape kept and not degraded", status)
+	}
+}
+
+func TestScrapeMarksReadiness(t *testing.T) {
+	srv := newFakePrometheus(t, func(string) []promSample { return nil })
+	p := newTestPrometheusClient(t, srv.URL)
+
+	if status := p.Readiness(); status.Initialized {
+		t.Fatalf("readiness before any scrape = %+v", status)
+	}
+	// Prometheus answering with no samples is enough; nothing is saved
+	if err := p.scrapeAllMetrics(context.Background(), 0); err != nil {
+		t.Fatalf("scrapeAllMetrics: %v", err)
+	}
+	if status := p.Readiness(); !status.Initialized || !status.Scraped || status.State != StateHealthy {
+		t.Errorf("readiness after scrape = %+v, want healthy", status)
+	}
+}
+
+func TestUnreachablePrometheusDegrades(t *testing.T) {
+	srv := newFakePrometheus(t, func(string) []promSample { return nil })
+	p := newTestPrometheusClient(t, srv.URL)
+	srv.Close()
+
+	ctx := context.Background()
+	if err := p.Probe(ctx); err == nil {
+		t.Fatal("Probe of a closed server succeeded")
+	}
+	if err := p.scrapeAllMetrics(ctx, 0); err != nil {
+		t.Fatalf("scrapeAllMetrics: %v", err)
+	}
+	status := p.Readiness()
+	if !status.Initialized || status.Scraped || !status.Degraded() {
+		t.Errorf("readiness = %+v, want initialized but degraded", status)
+	}
+}
@@ -0,0 +1,140 @@
+package observer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaultResourceMetricsInterval matches the metrics-server default resolution
+const defaultResourceMetricsInterval = 30 * time.Second
+
+// podMetricsList mirrors metrics.k8s.io/v1beta1 PodMetricsList. Decoding it
+// locally avoids pulling in the k8s.io/metrics module for two fields.
+type podMetricsList struct {
+	Items []struct {
+		Metadata   metav1.ObjectMeta `json:"metadata"`
+		Containers []struct {
+			Name  string              `json:"name"`
+			Usage corev1.ResourceList `json:"usage"`
+		} `json:"containers"`
+	} `json:"items"`
+}
+
+// SetResourceMetricsInterval overrides how often metrics-server is polled
+func (k *KubernetesWatcher) SetResourceMetricsInterval(interval time.Duration) {
+	if interval > 0 {
+		k.resourceInterval = interval
+	}
+}
+
+func (k *KubernetesWatcher) collectResourceMetrics(ctx context.Context) {
+	interval := k.resourceInterval
+	if interval <= 0 {
+		interval = defaultResourceMetricsInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := k.collectAndStoreResourceMetrics(ctx); err != nil {
+			k.logger.Error("Pod resource metrics error", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			k.logger.Info("Pod resource metrics collection stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// collectAndStoreResourceMetrics stores per-pod CPU (millicores) and memory
+// (MiB) from metrics-server. Clusters without metrics-server are skipped.
+func (k *KubernetesWatcher) collectAndStoreResourceMetrics(ctx context.Context) error {
+	data, err := k.clientset.Discovery().RESTClient().Get().
+		AbsPath("/apis/metrics.k8s.io/v1beta1/namespaces", k.namespace, "pods").
+		DoRaw(ctx)
+	if err != nil {
+		if apierrors.IsNotFound(err) || apierrors.IsServiceUnavailable(err) {
+			if !k.metricsServerMissing {
+				k.logger.Warn("metrics-server not available - skipping pod CPU/memory collection",
+					zap.String("namespace", k.namespace),
+					zap.Error(err))
+			}
+			k.metricsServerMissing = true
+			return nil
+		}
+		return fmt.Errorf("failed to query metrics-server: %w", err)
+	}
+
+	if k.metricsServerMissing {
+		k.logger.Info("metrics-server became available - collecting pod CPU/memory")
+		k.metricsServerMissing = false
+	}
+
+	var list podMetricsList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("failed to decode pod metrics: %w", err)
+	}
+
+	now := time.Now()
+	metrics := make([]*storage.Metric, 0, len(list.Items)*2)
+
+	for _, item := range list.Items {
+		var cpuMillis, memBytes int64
+		for _, container := range item.Containers {
+			if cpu, ok := container.Usage[corev1.ResourceCPU]; ok {
+				cpuMillis += cpu.MilliValue()
+			}
+			if mem, ok := container.Usage[corev1.ResourceMemory]; ok {
+				memBytes += mem.Value()
+			}
+		}
+
+		labels, _ := json.Marshal(map[string]string{
+			"namespace": item.Metadata.Namespace,
+			"pod":       item.Metadata.Name,
+		})
+
+		metrics = append(metrics,
+			&storage.Metric{
+				Timestamp:   now,
+				ServiceName: item.Metadata.Name,
+				MetricName:  "pod_cpu_usage",
+				MetricValue: float64(cpuMillis),
+				Labels:      labels,
+			},
+			&storage.Metric{
+				Timestamp:   now,
+				ServiceName: item.Metadata.Name,
+				MetricName:  "pod_memory_usage",
+				MetricValue: float64(memBytes) / (1024 * 1024),
+				Labels:      labels,
+			},
+		)
+	}
+
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	if err := k.db.BatchSaveMetrics(ctx, metrics); err != nil {
+		return fmt.Errorf("failed to save pod resource metrics: %w", err)
+	}
+
+	k.logger.Debug("Pod resource metrics saved",
+		zap.Int("pod_count", len(list.Items)),
+		zap.String("namespace", k.namespace))
+
+	return nil
+}
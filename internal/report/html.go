@@ -0,0 +1,101 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+)
+
+// htmlTemplate mirrors the Markdown layout. html/template escapes every
+// value, so reasons and insights can't inject markup.
+var htmlTemplate = template.Must(template.New("recommendation").Funcs(template.FuncMap{
+	"pct":     func(f float64) string { return fmt.Sprintf("%.0f", f) },
+	"dec1":    func(f float64) string { return fmt.Sprintf("%.1f", f) },
+	"value":   func(v interface{}) string { return fmt.Sprintf("%v", v) },
+	"rfc3339": func(t time.Time) string { return t.Format(time.RFC3339) },
+	"lower":   strings.ToLower,
+}).Parse(`<section class="recommendation risk-{{lower .R.RiskLevel}}">
+<h2>{{.H.Emoji}} {{.H.Title}}</h2>
+{{- if .H.IssueLabel}}
+<p><strong>{{.H.IssueLabel}}:</strong> {{.Issue}}</p>
+{{- if .R.TimeToImpact}}
+<p><strong>Time to Impact:</strong> {{.R.TimeToImpact}}</p>
+{{- end}}
+{{- else}}
+<p>{{.Summary}}</p>
+{{- end}}
+{{- if .R.ImmediateActions}}
+<h3>Immediate Actions</h3>
+<ol>
+{{- range .R.ImmediateActions}}
+<li><strong>{{.Type}}</strong>: {{.TargetMetric}}<ul><li>Current: {{value .CurrentValue}} → Target: {{value .TargetValue}}</li><li>Reason: {{.Reason}}</li></ul></li>
+{{- end}}
+</ol>
+{{- end}}
+{{- if .R.HighPriorityActions}}
+<h3>High Priority Actions</h3>
+<ol>
+{{- range .R.HighPriorityActions}}
+<li><strong>{{.Type}}</strong>: {{.Reason}}</li>
+{{- end}}
+</ol>
+{{- end}}
+{{- with .R.RootCause}}
+<h3>Root Cause Analysis</h3>
+<ul>
+<li>Primary Issue: {{.PrimaryIssue}}</li>
+<li>Contributing Factors:<ul>
+{{- range .ContributingFactors}}
+<li>{{.}}</li>
+{{- end}}
+</ul></li>
+</ul>
+{{- end}}
+{{- if .R.AffectedMetrics}}
+<h3>Affected Metrics</h3>
+<ul>
+{{- range .R.AffectedMetrics}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .R.Insights}}
+<h3>Predictive Insights</h3>
+<ul>
+{{- range .R.Insights}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+<h3>Health Assessment</h3>
+<ul>
+<li>Overall Health: {{pct .R.Health.Score}}/100 ({{.R.Health.Rating}})</li>
+<li>System Stress: {{pct .R.Health.SystemStress}}/100</li>
+<li>Stability Index: {{dec1 .R.Health.StabilityIndex}}/10</li>
+<li>Predictability: {{pct .R.Health.Predictability}}/100</li>
+</ul>
+<h3>Next Steps</h3>
+<ol>
+{{- range .R.NextSteps}}
+<li>{{.}}</li>
+{{- end}}
+</ol>
+<p class="meta">Diagnosis ID: {{.R.DiagnosisID}} · Generated: {{rfc3339 .R.GeneratedAt}}</p>
+</section>
+`))
+
+// HTML renders the recommendation as an HTML fragment
+func HTML(r *Recommendation) (string, error) {
+	var b strings.Builder
+	err := htmlTemplate.Execute(&b, struct {
+		R       *Recommendation
+		H       headline
+		Issue   string
+		Summary string
+	}{r, headlineFor(r.RiskLevel), issueLine(r), summaryLine(r)})
+	if err != nil {
+		return "", fmt.Errorf("failed to render recommendation: %w", err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,89 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Markdown renders the recommendation as the Markdown embedded in diagnoses
+// and notifications
+func Markdown(r *Recommendation) string {
+	var b strings.Builder
+
+	h := headlineFor(r.RiskLevel)
+	fmt.Fprintf(&b, "%s **%s**\n\n", h.Emoji, h.Title)
+	switch {
+	case h.IssueLabel == "":
+		fmt.Fprintf(&b, "%s\n\n", summaryLine(r))
+	case r.TimeToImpact != "":
+		fmt.Fprintf(&b, "**%s:** %s\n", h.IssueLabel, issueLine(r))
+		fmt.Fprintf(&b, "**Time to Impact:** %s\n\n", r.TimeToImpact)
+	default:
+		fmt.Fprintf(&b, "**%s:** %s\n\n", h.IssueLabel, issueLine(r))
+	}
+
+	if len(r.ImmediateActions) > 0 {
+		b.WriteString("**IMMEDIATE ACTIONS:**\n")
+		for i, a := range r.ImmediateActions {
+			fmt.Fprintf(&b, "%d. **%s**: %s\n   - Current: %v → Target: %v\n   - Reason: %s\n",
+				i+1, a.Type, a.TargetMetric, a.CurrentValue, a.TargetValue, a.Reason)
+		}
+	}
+	if len(r.HighPriorityActions) > 0 {
+		if len(r.ImmediateActions) > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("**HIGH PRIORITY ACTIONS:**\n")
+		for i, a := range r.HighPriorityActions {
+			fmt.Fprintf(&b, "%d. **%s**: %s\n", i+1, a.Type, a.Reason)
+		}
+	}
+	if len(r.ImmediateActions) > 0 || len(r.HighPriorityActions) > 0 {
+		b.WriteString("\n")
+	}
+
+	if r.RootCause != nil {
+		b.WriteString("**ROOT CAUSE ANALYSIS:**\n")
+		fmt.Fprintf(&b, "- Primary Issue: %s\n", r.RootCause.PrimaryIssue)
+		b.WriteString("- Contributing Factors:\n")
+		for _, issue := range r.RootCause.ContributingFactors {
+			fmt.Fprintf(&b, "  • %s\n", issue)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(r.AffectedMetrics) > 0 {
+		b.WriteString("**AFFECTED METRICS:**\n")
+		for _, metric := range r.AffectedMetrics {
+			fmt.Fprintf(&b, "- %s\n", metric)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(r.Insights) > 0 {
+		b.WriteString("**PREDICTIVE INSIGHTS:**\n")
+		for _, insight := range r.Insights {
+			fmt.Fprintf(&b, "• %s\n", insight)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("**HEALTH ASSESSMENT:**\n")
+	fmt.Fprintf(&b, "- Overall Health: %.0f/100 (%s)\n", r.Health.Score, r.Health.Rating)
+	fmt.Fprintf(&b, "- System Stress: %.0f/100\n", r.Health.SystemStress)
+	fmt.Fprintf(&b, "- Stability Index: %.1f/10\n", r.Health.StabilityIndex)
+	fmt.Fprintf(&b, "- Predictability: %.0f/100\n", r.Health.Predictability)
+	b.WriteString("\n")
+
+	b.WriteString("**NEXT STEPS:**\n")
+	for i, step := range r.NextSteps {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, step)
+	}
+
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "**Diagnosis ID:** %s\n", r.DiagnosisID)
+	fmt.Fprintf(&b, "**Generated:** %s\n", r.GeneratedAt.Format(time.RFC3339))
+
+	return b.String()
+}
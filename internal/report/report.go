@@ -0,0 +1,132 @@
+// Package report renders the recommendation built for a diagnosis. The
+// analyzer fills in a Recommendation; this package only decides how it looks,
+// as Markdown for notifications and the diagnosis body, or as HTML.
+package report
+
+import (
+	"fmt"
+	"time"
+)
+
+// Output formats accepted by Render
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+	FormatJSON     = "json"
+)
+
+// Recommendation is the structured advice for one diagnosis
+type Recommendation struct {
+	ServiceName string  `json:"service_name"`
+	RiskLevel   string  `json:"risk_level"` // LOW, NORMAL, MEDIUM, HIGH, CRITICAL
+	Problem     string  `json:"problem"`
+	Confidence  float64 `json:"confidence"`
+
+	// TimeToImpact is only set for HIGH and CRITICAL risk
+	TimeToImpact string `json:"time_to_impact,omitempty"`
+
+	ImmediateActions    []Action   `json:"immediate_actions"`
+	HighPriorityActions []Action   `json:"high_priority_actions"`
+	RootCause           *RootCause `json:"root_cause,omitempty"` // set when there are contributing issues
+	AffectedMetrics     []string   `json:"affected_metrics"`
+	Insights            []string   `json:"insights"`
+	Health              Health     `json:"health"`
+	NextSteps           []string   `json:"next_steps"`
+
+	DiagnosisID string    `json:"diagnosis_id"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// Action is an actuator action worth calling out
+type Action struct {
+	Type         string      `json:"type"`
+	TargetMetric string      `json:"target_metric"`
+	CurrentValue interface{} `json:"current_value"`
+	TargetValue  interface{} `json:"target_value"`
+	Reason       string      `json:"reason"`
+}
+
+// RootCause names the primary issue and what contributed to it
+type RootCause struct {
+	PrimaryIssue        string   `json:"primary_issue"`
+	ContributingFactors []string `json:"contributing_factors"`
+}
+
+// Health summarizes the composite scores
+type Health struct {
+	Score          float64 `json:"score"`
+	Rating         string  `json:"rating"` // CRITICAL, POOR, FAIR, GOOD, EXCELLENT
+	SystemStress   float64 `json:"system_stress"`
+	StabilityIndex float64 `json:"stability_index"`
+	Predictability float64 `json:"predictability"`
+}
+
+// HealthRating buckets a 0-100 health score
+func HealthRating(score float64) string {
+	switch {
+	case score < 30:
+		return "CRITICAL"
+	case score < 50:
+		return "POOR"
+	case score < 70:
+		return "FAIR"
+	case score < 90:
+		return "GOOD"
+	default:
+		return "EXCELLENT"
+	}
+}
+
+// ValidFormat reports whether format is one Render accepts
+func ValidFormat(format string) bool {
+	switch format {
+	case FormatMarkdown, FormatHTML, FormatJSON:
+		return true
+	}
+	return false
+}
+
+// Render renders the recommendation as Markdown or HTML. JSON is left to the
+// caller, which serializes the Recommendation itself.
+func Render(r *Recommendation, format string) (string, error) {
+	switch format {
+	case FormatMarkdown:
+		return Markdown(r), nil
+	case FormatHTML:
+		return HTML(r)
+	default:
+		return "", fmt.Errorf("unsupported report format %q", format)
+	}
+}
+
+// headline is the banner and issue line for the recommendation's risk level
+type headline struct {
+	Emoji      string
+	Title      string
+	IssueLabel string // "" for the healthy banner, which has no issue line
+}
+
+func headlineFor(riskLevel string) headline {
+	switch riskLevel {
+	case "CRITICAL":
+		return headline{"🚨", "CRITICAL ACTION REQUIRED", "Primary Issue"}
+	case "HIGH":
+		return headline{"⚠️", "URGENT ACTION REQUIRED", "Primary Issue"}
+	case "MEDIUM":
+		return headline{"⚡", "ATTENTION REQUIRED", "Detected Issue"}
+	case "LOW":
+		return headline{"📊", "ADVISORY NOTICE", "Monitoring"}
+	default:
+		return headline{"✅", "SYSTEM HEALTHY", ""}
+	}
+}
+
+// summaryLine is the sentence under the healthy banner
+func summaryLine(r *Recommendation) string {
+	return fmt.Sprintf("No critical issues detected. Health Score: %.0f/100", r.Health.Score)
+}
+
+// issueLine is the problem and confidence under an unhealthy banner
+func issueLine(r *Recommendation) string {
+	return fmt.Sprintf("%s (%.1f%% confidence)", r.Problem, r.Confidence)
+}
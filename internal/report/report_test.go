@@ -0,0 +1,115 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func criticalRecommendation() *Recommendation {
+	return &Recommendation{
+		ServiceName:  "checkout",
+		RiskLevel:    "CRITICAL",
+		Problem:      "MEMORY_LEAK",
+		Confidence:   91.5,
+		TimeToImpact: "~12 minutes",
+		ImmediateActions: []Action{
+			{Type: "RESTART", TargetMetric: "memory_usage", CurrentValue: 94.0, TargetValue: 60.0, Reason: "memory climbing 2%/min"},
+		},
+		HighPriorityActions: []Action{{Type: "SCALE_UP", Reason: "spread the load"}},
+		RootCause: &RootCause{
+			PrimaryIssue:        "Heap growth after deploy",
+			ContributingFactors: []string{"GC pauses rising"},
+		},
+		AffectedMetrics: []string{"memory_usage"},
+		Insights:        []string{"OOM expected within 15 minutes"},
+		Health:          Health{Score: 22, Rating: HealthRating(22), SystemStress: 80, StabilityIndex: 3.25, Predictability: 40},
+		NextSteps:       []string{"Restart the pods", "Roll back the deploy"},
+		DiagnosisID:     "pred-1",
+		GeneratedAt:     time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	md := Markdown(criticalRecommendation())
+	for _, want := range []string{
+		"🚨 **CRITICAL ACTION REQUIRED**",
+		"**Primary Issue:** MEMORY_LEAK (91.5% confidence)\n**Time to Impact:** ~12 minutes",
+		"**IMMEDIATE ACTIONS:**\n1. **RESTART**: memory_usage\n   - Current: 94 → Target: 60\n   - Reason: memory climbing 2%/min",
+		"**HIGH PRIORITY ACTIONS:**\n1. **SCALE_UP**: spread the load",
+		"- Primary Issue: Heap growth after deploy\n- Contributing Factors:\n  • GC pauses rising",
+		"**PREDICTIVE INSIGHTS:**\n• OOM expected within 15 minutes",
+		"- Overall Health: 22/100 (CRITICAL)",
+		"- Stability Index: 3.2/10",
+		"**NEXT STEPS:**\n1. Restart the pods\n2. Roll back the deploy",
+		"**Diagnosis ID:** pred-1\n**Generated:** 2026-10-16T12:00:00Z",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown lacks %q:\n%s", want, md)
+		}
+	}
+
+	healthy := Markdown(&Recommendation{RiskLevel: "NORMAL", Health: Health{Score: 96, Rating: HealthRating(96)}})
+	if !strings.HasPrefix(healthy, "✅ **SYSTEM HEALTHY**\n\nNo critical issues detected. Health Score: 96/100") {
+		t.Errorf("healthy markdown starts %q", healthy)
+	}
+	for _, absent := range []string{"ACTIONS", "ROOT CAUSE", "Time to Impact"} {
+		if strings.Contains(healthy, absent) {
+			t.Errorf("healthy markdown mentions %q", absent)
+		}
+	}
+}
+
+func TestHTMLEscapesValues(t *testing.T) {
+	r := criticalRecommendation()
+	r.Insights = []string{`<script>alert("x")</script>`}
+
+	html, err := HTML(r)
+	if err != nil {
+		t.Fatalf("HTML: %v", err)
+	}
+	for _, want := range []string{
+		`<section class="recommendation risk-critical">`,
+		"<h2>🚨 CRITICAL ACTION REQUIRED</h2>",
+		"<p><strong>Time to Impact:</strong> ~12 minutes</p>",
+		"<li><strong>SCALE_UP</strong>: spread the load</li>",
+		"&lt;script&gt;",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("html lacks %q:\n%s", want, html)
+		}
+	}
+	if strings.Contains(html, "<script>") {
+		t.Error("html contains an unescaped script tag")
+	}
+}
+
+func TestRender(t *testing.T) {
+	r := criticalRecommendation()
+
+	md, err := Render(r, FormatMarkdown)
+	if err != nil || md != Markdown(r) {
+		t.Errorf("Render(markdown) = %v, want Markdown's output", err)
+	}
+	html, err := Render(r, FormatHTML)
+	if err != nil || !strings.HasPrefix(html, "<section") {
+		t.Errorf("Render(html) = %q, %v", html, err)
+	}
+	if _, err := Render(r, FormatJSON); err == nil {
+		t.Error("Render(json) succeeded, want JSON left to the caller")
+	}
+
+	for format, want := range map[string]bool{FormatJSON: true, FormatMarkdown: true, FormatHTML: true, "pdf": false, "": false} {
+		if got := ValidFormat(format); got != want {
+			t.Errorf("ValidFormat(%q) = %v, want %v", format, got, want)
+		}
+	}
+}
+
+func TestHealthRating(t *testing.T) {
+	for score, want := range map[float64]string{0: "CRITICAL", 29.9: "CRITICAL", 30: "POOR", 50: "FAIR", 70: "GOOD", 90: "EXCELLENT", 100: "EXCELLENT"} {
+		if got := HealthRating(score); got != want {
+			t.Errorf("HealthRating(%v) = %s, want %s", score, got, want)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Annotation types
+const (
+	AnnotationDeployment     = "deployment"
+	AnnotationIncidentOpened = "incident_opened"
+	AnnotationIncidentClosed = "incident_closed"
+	AnnotationDiagnosis      = "diagnosis"
+)
+
+// maxAnnotationsPerSource bounds each source so a long range can't flood a chart
+const maxAnnotationsPerSource = 500
+
+// Deployment is a release of a service, as reported by CI/CD
+type Deployment struct {
+	ID          int64     `json:"id"`
+	ServiceName string    `json:"service_name"`
+	Version     string    `json:"version"`
+	Description string    `json:"description,omitempty"`
+	DeployedAt  time.Time `json:"deployed_at"`
+}
+
+// Annotation is a point-in-time marker to overlay on a metric chart
+type Annotation struct {
+	Time time.Time `json:"time"`
+	Type string    `json:"type"`
+	Text string    `json:"text"`
+}
+
+// SaveDeployment records a deployment
+func (c *PostgresClient) SaveDeployment(ctx context.Context, d *Deployment) error {
+	query := `
+		INSERT INTO deployments (service_name, version, description, deployed_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := c.pool.QueryRow(ctx, query, d.ServiceName, d.Version, d.Description, d.DeployedAt).Scan(&d.ID); err != nil {
+		return fmt.Errorf("failed to save deployment: %w", err)
+	}
+	return nil
+}
+
+// annotationSources select (time, text) for one annotation type within
+// [$2, $3] for service $1
+var annotationSources = []struct {
+	typ   string
+	query string
+}{
+	{AnnotationDeployment, `
+		SELECT deployed_at,
+		       'Deployed ' || version || COALESCE(': ' || NULLIF(description, ''), '')
+		FROM deployments
+		WHERE service_name = $1 AND deployed_at >= $2 AND deployed_at <= $3
+		ORDER BY deployed_at ASC
+		LIMIT $4`},
+	{AnnotationIncidentOpened, `
+		SELECT first_seen,
+		       'Incident opened: ' || problem_type || ' (' || peak_severity || ')'
+		FROM incidents
+		WHERE service_name = $1 AND first_seen >= $2 AND first_seen <= $3
+		ORDER BY first_seen ASC
+		LIMIT $4`},
+	{AnnotationIncidentClosed, `
+		SELECT closed_at,
+		       'Incident resolved: ' || problem_type || ' after ' || occurrences || ' diagnoses'
+		FROM incidents
+		WHERE service_name = $1 AND closed_at >= $2 AND closed_at <= $3
+		ORDER BY closed_at ASC
+		LIMIT $4`},
+	{AnnotationDiagnosis, `
+		SELECT timestamp,
+		       primary_problem || ' (' || primary_severity || ', ' || ROUND(primary_confidence::numeric) || '% confidence)'
+		FROM ultimate_diagnoses
+		WHERE service_name = $1 AND timestamp >= $2 AND timestamp <= $3
+		  AND primary_detected AND primary_severity IN ('HIGH', 'CRITICAL')
+		ORDER BY timestamp ASC
+		LIMIT $4`},
+}
+
+// GetAnnotations merges deployments, incident open/close events and
+// high-severity diagnoses for a service into one chronological feed
+func (c *PostgresClient) GetAnnotations(ctx context.Context, serviceName string, from, to time.Time) ([]Annotation, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	annotations := make([]Annotation, 0)
+	for _, source := range annotationSources {
+		rows, err := c.queryPool(ctx).Query(ctx, source.query, serviceName, from, to, maxAnnotationsPerSource)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s annotations: %w", source.typ, err)
+		}
+		for rows.Next() {
+			a := Annotation{Type: source.typ}
+			if err := rows.Scan(&a.Time, &a.Text); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan %s annotation: %w", source.typ, err)
+			}
+			annotations = append(annotations, a)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("error iterating %s annotations: %w", source.typ, err)
+		}
+	}
+
+	sort.SliceStable(annotations, func(i, j int) bool { return annotations[i].Time.Before(annotations[j].Time) })
+	return annotations, nil
+}
@@ -0,0 +1,68 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+)
+
+func saveDiagnosis(t *testing.T, db *storage.PostgresClient, service string, at time.Time, severity string) {
+	t.Helper()
+
+	err := db.SaveUltimateDiagnosis(context.Background(), &storage.UltimateDiagnosisRecord{
+		PredictionID:      uuid.NewString(),
+		ServiceName:       service,
+		Timestamp:         at,
+		PrimaryProblem:    "MEMORY_LEAK",
+		PrimaryDetected:   true,
+		PrimaryConfidence: 87.4,
+		PrimarySeverity:   severity,
+		HealthScore:       40,
+		RiskLevel:         severity,
+	})
+	if err != nil {
+		t.Fatalf("SaveUltimateDiagnosis: %v", err)
+	}
+}
+
+func TestGetAnnotationsCombinesSources(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+
+	to := time.Now().Truncate(time.Second)
+	from := to.Add(-time.Hour)
+
+	deployedAt := from.Add(10 * time.Minute)
+	if err := db.SaveDeployment(ctx, &storage.Deployment{ServiceName: service, Version: "v1.4.2", Description: "cache rework", DeployedAt: deployedAt}); err != nil {
+		t.Fatalf("SaveDeployment: %v", err)
+	}
+	// Outside the range
+	if err := db.SaveDeployment(ctx, &storage.Deployment{ServiceName: service, Version: "v1.4.1", DeployedAt: from.Add(-time.Minute)}); err != nil {
+		t.Fatalf("SaveDeployment: %v", err)
+	}
+
+	diagnosedAt := from.Add(25 * time.Minute)
+	saveDiagnosis(t, db, service, diagnosedAt, "HIGH")
+	saveDiagnosis(t, db, service, from.Add(20*time.Minute), "LOW") // not annotated
+
+	annotations, err := db.GetAnnotations(ctx, service, from, to)
+	if err != nil {
+		t.Fatalf("GetAnnotations: %v", err)
+	}
+	if len(annotations) != 2 {
+		t.Fatalf("got %d annotations (%+v), want the deployment and the HIGH diagnosis", len(annotations), annotations)
+	}
+
+	deployment, diagnosis := annotations[0], annotations[1]
+	if deployment.Type != storage.AnnotationDeployment || !deployment.Time.Equal(deployedAt) || deployment.Text != "Deployed v1.4.2: cache rework" {
+		t.Errorf("first annotation = %+v, want the v1.4.2 deployment at %v", deployment, deployedAt)
+	}
+	if diagnosis.Type != storage.AnnotationDiagnosis || !diagnosis.Time.Equal(diagnosedAt) || diagnosis.Text != "MEMORY_LEAK (HIGH, 87% confidence)" {
+		t.Errorf("second annotation = %+v, want the HIGH diagnosis at %v", diagnosis, diagnosedAt)
+	}
+}
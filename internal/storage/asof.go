@@ -0,0 +1,28 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+type asOfKey struct{}
+
+// WithAsOf makes time-windowed queries on ctx end at t instead of now, so
+// historical windows can be replayed as if they were live
+func WithAsOf(ctx context.Context, t time.Time) context.Context {
+	return context.WithValue(ctx, asOfKey{}, t)
+}
+
+// AsOf returns the query end time carried by ctx, or now when none is set
+func AsOf(ctx context.Context) time.Time {
+	if t, ok := ctx.Value(asOfKey{}).(time.Time); ok {
+		return t
+	}
+	return time.Now()
+}
+
+// HasAsOf reports whether ctx carries an explicit query end time
+func HasAsOf(ctx context.Context) bool {
+	_, ok := ctx.Value(asOfKey{}).(time.Time)
+	return ok
+}
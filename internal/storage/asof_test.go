@@ -0,0 +1,27 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAsOf(t *testing.T) {
+	ctx := context.Background()
+	if HasAsOf(ctx) {
+		t.Fatal("HasAsOf on a plain context = true, want false")
+	}
+	before := time.Now()
+	if got := AsOf(ctx); got.Before(before) || got.After(time.Now()) {
+		t.Errorf("AsOf on a plain context = %v, want now", got)
+	}
+
+	at := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	ctx = WithAsOf(ctx, at)
+	if !HasAsOf(ctx) {
+		t.Fatal("HasAsOf after WithAsOf = false, want true")
+	}
+	if got := AsOf(ctx); !got.Equal(at) {
+		t.Errorf("AsOf = %v, want %v", got, at)
+	}
+}
@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// BacktestRun is a persisted backtest report
+type BacktestRun struct {
+	ID        int64     `json:"id"`
+	RunAt     time.Time `json:"run_at"`
+	CaseCount int       `json:"case_count"`
+	Accuracy  float64   `json:"accuracy"`
+	MacroF1   float64   `json:"macro_f1"`
+	// DetectorVersion is the detection logic the run evaluated
+	DetectorVersion string          `json:"detector_version"`
+	Report          json.RawMessage `json:"report,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+}
+
+// SaveBacktestRun stores a run; report is marshalled to JSON as-is
+func (c *PostgresClient) SaveBacktestRun(ctx context.Context, run *BacktestRun, report interface{}) error {
+	query := `
+		INSERT INTO backtest_runs (run_at, case_count, accuracy, macro_f1, detector_version, report)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	reportJSON, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal backtest report: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err = c.pool.QueryRow(ctx, query,
+		run.RunAt,
+		run.CaseCount,
+		run.Accuracy,
+		run.MacroF1,
+		run.DetectorVersion,
+		reportJSON,
+	).Scan(&run.ID, &run.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save backtest run: %w", err)
+	}
+
+	run.Report = reportJSON
+	return nil
+}
+
+// GetRecentBacktestRuns returns the newest runs without their full reports
+func (c *PostgresClient) GetRecentBacktestRuns(ctx context.Context, limit int) ([]*BacktestRun, error) {
+	query := `
+		SELECT id, run_at, case_count, accuracy, macro_f1, COALESCE(detector_version, ''), created_at
+		FROM backtest_runs
+		ORDER BY run_at DESC
+		LIMIT $1
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := c.queryPool(ctx).Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query backtest runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []*BacktestRun
+	for rows.Next() {
+		var r BacktestRun
+		if err := rows.Scan(&r.ID, &r.RunAt, &r.CaseCount, &r.Accuracy, &r.MacroF1, &r.DetectorVersion, &r.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan backtest run: %w", err)
+		}
+		runs = append(runs, &r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating backtest runs: %w", err)
+	}
+
+	return runs, nil
+}
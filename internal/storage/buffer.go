@@ -0,0 +1,154 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrBufferFull is returned by MetricBuffer.Add when accepting the metrics
+// would exceed the buffer's capacity
+var ErrBufferFull = errors.New("metric buffer full")
+
+// MetricBuffer collects pushed metrics and writes them with BatchSaveMetrics
+// in batches, so many small ingest requests don't each become a COPY. With a
+// write resolution set, samples are averaged per resolution bucket first.
+type MetricBuffer struct {
+	db            *PostgresClient
+	maxBatch      int
+	capacity      int
+	flushInterval time.Duration
+	resolution    time.Duration
+
+	mu      sync.Mutex
+	pending []*Metric
+	full    chan struct{} // signalled when a batch is ready
+
+	written atomic.Int64
+	failed  atomic.Int64
+}
+
+// MetricBufferStats counts metrics that went through the buffer
+type MetricBufferStats struct {
+	Pending int   `json:"pending"`
+	Written int64 `json:"written"` // rows, fewer than accepted with a write resolution
+	Failed  int64 `json:"failed"`  // dropped after a failed write
+}
+
+func NewMetricBuffer(db *PostgresClient, maxBatch, capacity int, flushInterval time.Duration) *MetricBuffer {
+	return &MetricBuffer{
+		db:            db,
+		maxBatch:      maxBatch,
+		capacity:      capacity,
+		flushInterval: flushInterval,
+		full:          make(chan struct{}, 1),
+	}
+}
+
+// SetWriteResolution makes the buffer store one row per series and
+// resolution bucket, the mean of its samples, instead of every sample. A
+// bucket is held until it ends so its samples aren't split across flushes.
+// Zero stores every sample. Call it before Run.
+func (b *MetricBuffer) SetWriteResolution(resolution time.Duration) {
+	b.resolution = resolution
+}
+
+// Add queues metrics for the next flush. Either all of them are accepted or,
+// when the buffer can't hold them, none are and ErrBufferFull is returned.
+func (b *MetricBuffer) Add(metrics []*Metric) error {
+	b.mu.Lock()
+	if len(b.pending)+len(metrics) > b.capacity {
+		b.mu.Unlock()
+		return ErrBufferFull
+	}
+	b.pending = append(b.pending, metrics...)
+	ready := len(b.pending) >= b.maxBatch
+	b.mu.Unlock()
+
+	if ready {
+		select {
+		case b.full <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Run flushes every flushInterval, or as soon as a batch fills, until the
+// context is cancelled. Call Flush on shutdown for whatever is still pending.
+func (b *MetricBuffer) Run(ctx context.Context) {
+	ticker := time.NewTicker(b.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.flush(ctx, time.Now())
+		case <-b.full:
+			b.flush(ctx, time.Now())
+		}
+	}
+}
+
+// Flush writes every pending metric, including write resolution buckets
+// that haven't ended yet. Batches of at most maxBatch are written; a batch
+// that fails to write is dropped and counted rather than retried, so a
+// database outage can't grow the buffer without bound.
+func (b *MetricBuffer) Flush(ctx context.Context) {
+	b.flush(ctx, time.Time{})
+}
+
+// flush writes pending metrics, holding back the samples of write resolution
+// buckets that end after cutoff; a zero cutoff holds nothing back
+func (b *MetricBuffer) flush(ctx context.Context, cutoff time.Time) {
+	b.mu.Lock()
+	pending := b.pending
+	b.pending = nil
+	if b.resolution > 0 {
+		pending, b.pending = aggregateMetrics(pending, b.resolution, cutoff)
+	}
+	b.mu.Unlock()
+
+	for start := 0; start < len(pending); start += b.maxBatch {
+		end := start + b.maxBatch
+		if end > len(pending) {
+			end = len(pending)
+		}
+		batch := pending[start:end]
+
+		if err := b.db.BatchSaveMetrics(ctx, batch); err != nil {
+			b.failed.Add(int64(len(batch)))
+			b.db.logger.Warn("Dropping buffered metrics after failed write",
+				zap.Int("count", len(batch)),
+				zap.Error(err))
+			continue
+		}
+		b.written.Add(int64(len(batch)))
+	}
+}
+
+// Accepting reports whether the buffer has room for more metrics
+func (b *MetricBuffer) Accepting() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.pending) < b.capacity
+}
+
+// Stats returns the buffer's counters
+func (b *MetricBuffer) Stats() MetricBufferStats {
+	b.mu.Lock()
+	pending := len(b.pending)
+	b.mu.Unlock()
+
+	return MetricBufferStats{
+		Pending: pending,
+		Written: b.written.Load(),
+		Failed:  b.failed.Load(),
+	}
+}
@@ -0,0 +1,101 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+)
+
+func TestMetricBufferAddAllOrNothing(t *testing.T) {
+	buffer := storage.NewMetricBuffer(nil, 10, 5, time.Minute)
+	end := time.Now()
+
+	if err := buffer.Add(storagetest.Series("checkout", "cpu_usage", end, time.Second, 1, 2, 3)); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := buffer.Add(storagetest.Series("checkout", "cpu_usage", end, time.Second, 4, 5, 6)); !errors.Is(err, storage.ErrBufferFull) {
+		t.Fatalf("Add past capacity: err = %v, want ErrBufferFull", err)
+	}
+	if pending := buffer.Stats().Pending; pending != 3 {
+		t.Errorf("pending = %d, want the rejected batch left out entirely", pending)
+	}
+	if !buffer.Accepting() {
+		t.Error("Accepting() = false with room for two more")
+	}
+
+	if err := buffer.Add(storagetest.Series("checkout", "cpu_usage", end, time.Second, 4, 5)); err != nil {
+		t.Fatalf("Add up to capacity: %v", err)
+	}
+	if buffer.Accepting() {
+		t.Error("Accepting() = true when full")
+	}
+}
+
+func TestMetricBufferFlushWritesBatches(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+
+	buffer := storage.NewMetricBuffer(db, 2, 100, time.Minute)
+	end := time.Now().Add(-time.Minute).Truncate(time.Second)
+	if err := buffer.Add(storagetest.Series(service, "cpu_usage", end, time.Second, 1, 2, 3, 4, 5)); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	buffer.Flush(ctx)
+
+	stats := buffer.Stats()
+	if stats.Pending != 0 || stats.Written != 5 || stats.Failed != 0 {
+		t.Errorf("stats = %+v, want all 5 written", stats)
+	}
+	stored, err := db.GetRecentMetrics(ctx, service, "cpu_usage", time.Hour)
+	if err != nil {
+		t.Fatalf("GetRecentMetrics: %v", err)
+	}
+	if len(stored) != 5 {
+		t.Errorf("stored %d samples, want 5", len(stored))
+	}
+}
+
+func TestMetricBufferFlushAggregatesWriteResolution(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+
+	buffer := storage.NewMetricBuffer(db, 100, 100, time.Minute)
+	buffer.SetWriteResolution(30 * time.Second)
+
+	// 12 samples 5s apart spanning two whole 30s buckets
+	start := time.Now().Add(-10 * time.Minute).Truncate(30 * time.Second)
+	values := []float64{1, 2, 3, 4, 5, 6, 10, 20, 30, 40, 50, 60}
+	if err := buffer.Add(storagetest.Series(service, "cpu_usage", start.Add(55*time.Second), 5*time.Second, values...)); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	buffer.Flush(ctx)
+
+	if stats := buffer.Stats(); stats.Pending != 0 || stats.Written != 2 || stats.Failed != 0 {
+		t.Errorf("stats = %+v, want 2 rows written", stats)
+	}
+	stored, err := db.GetRecentMetrics(ctx, service, "cpu_usage", time.Hour)
+	if err != nil {
+		t.Fatalf("GetRecentMetrics: %v", err)
+	}
+	if len(stored) != 2 {
+		t.Fatalf("stored %d rows, want one per bucket", len(stored))
+	}
+	want := []struct{ mean, min, max float64 }{{3.5, 1, 6}, {35, 10, 60}}
+	for i, w := range want {
+		row := stored[i]
+		if !row.Timestamp.Equal(start.Add(time.Duration(i) * 30 * time.Second)) {
+			t.Errorf("row %d stamped %v, want the bucket start", i, row.Timestamp)
+		}
+		if row.MetricValue != w.mean || row.MetricMin == nil || *row.MetricMin != w.min || row.MetricMax == nil || *row.MetricMax != w.max {
+			t.Errorf("row %d = %v (min %v, max %v), want %v (min %v, max %v)", i, row.MetricValue, row.MetricMin, row.MetricMax, w.mean, w.min, w.max)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+)
+
+// saveDecision stores a decision for the service and returns it with its id
+func saveDecision(t *testing.T, db *storage.PostgresClient, service, actionType string, at time.Time, executed bool) *storage.Decision {
+	t.Helper()
+
+	d := &storage.Decision{
+		Timestamp:       at,
+		PatternDetected: "MEMORY_LEAK",
+		ActionType:      actionType,
+		Confidence:      80,
+		Reason:          "test decision",
+		Executed:        executed,
+		ServiceName:     service,
+	}
+	if err := db.SaveDecision(context.Background(), d); err != nil {
+		t.Fatalf("SaveDecision: %v", err)
+	}
+	return d
+}
+
+func TestGetDecisionsFilter(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+
+	// Action types unique to this run keep other rows out of the results
+	restart := strings.ToUpper(service) + "_RESTART"
+	scale := strings.ToUpper(service) + "_SCALE"
+
+	now := time.Now().Truncate(time.Second)
+	recent := saveDecision(t, db, service, restart, now.Add(-10*time.Minute), true)
+	old := saveDecision(t, db, service, restart, now.Add(-2*time.Hour), false)
+	saveDecision(t, db, service, scale, now.Add(-5*time.Minute), false)
+
+	executed, pending := true, false
+	tests := []struct {
+		name   string
+		filter storage.DecisionFilter
+		want   []int64
+	}{
+		{"action type, newest first", storage.DecisionFilter{ActionType: restart}, []int64{recent.ID, old.ID}},
+		{"executed", storage.DecisionFilter{ActionType: restart, Executed: &executed}, []int64{recent.ID}},
+		{"not executed", storage.DecisionFilter{ActionType: restart, Executed: &pending}, []int64{old.ID}},
+		{"window", storage.DecisionFilter{ActionType: restart, Since: now.Add(-time.Hour)}, []int64{recent.ID}},
+		{"limit", storage.DecisionFilter{ActionType: restart, Limit: 1}, []int64{recent.ID}},
+		{"no match", storage.DecisionFilter{ActionType: scale, Executed: &executed}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decisions, err := db.GetDecisions(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("GetDecisions: %v", err)
+			}
+			var got []int64
+			for _, d := range decisions {
+				got = append(got, d.ID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got ids %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got ids %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateDecisionExecution(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+
+	d := saveDecision(t, db, service, strings.ToUpper(service)+"_RESTART", time.Now(), false)
+	id := strconv.FormatInt(d.ID, 10)
+
+	before := time.Now().Add(-time.Second)
+	if err := db.UpdateDecisionExecution(ctx, id, true, "rolled out 3 replicas"); err != nil {
+		t.Fatalf("UpdateDecisionExecution: %v", err)
+	}
+	got, err := db.GetDecisionById(ctx, id)
+	if err != nil {
+		t.Fatalf("GetDecisionById: %v", err)
+	}
+	if !got.Executed || got.ExecutionResult != "rolled out 3 replicas" {
+		t.Errorf("after marking executed: executed %v, result %q", got.Executed, got.ExecutionResult)
+	}
+	if got.ExecutedAt == nil || got.ExecutedAt.Before(before) {
+		t.Errorf("executed_at = %v, want stamped now", got.ExecutedAt)
+	}
+
+	// Un-marking clears the stamp and result
+	if err := db.UpdateDecisionExecution(ctx, id, false, ""); err != nil {
+		t.Fatalf("UpdateDecisionExecution: %v", err)
+	}
+	got, err = db.GetDecisionById(ctx, id)
+	if err != nil {
+		t.Fatalf("GetDecisionById: %v", err)
+	}
+	if got.Executed || got.ExecutedAt != nil || got.ExecutionResult != "" {
+		t.Errorf("after un-marking: executed %v, executed_at %v, result %q", got.Executed, got.ExecutedAt, got.ExecutionResult)
+	}
+
+	if err := db.UpdateDecisionExecution(ctx, "2147483000", true, ""); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("unknown id: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestDecisionOutcomes(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+	now := time.Now()
+
+	executed := func(at time.Time) *storage.Decision {
+		d := &storage.Decision{
+			Timestamp:       at,
+			PatternDetected: "MEMORY_LEAK",
+			ActionType:      strings.ToUpper(service) + "_RESTART",
+			Confidence:      80,
+			Reason:          "test decision",
+			Executed:        true,
+			ExecutedAt:      &at,
+			ServiceName:     service,
+		}
+		if err := db.SaveDecision(ctx, d); err != nil {
+			t.Fatalf("SaveDecision: %v", err)
+		}
+		return d
+	}
+	due := executed(now.Add(-10 * time.Minute))
+	tooRecent := executed(now.Add(-time.Minute))
+	saveDecision(t, db, service, strings.ToUpper(service)+"_RESTART", now.Add(-10*time.Minute), false)
+
+	unverified := func() map[int64]bool {
+		decisions, err := db.GetUnverifiedDecisions(ctx, now.Add(-time.Hour), now.Add(-5*time.Minute), 1000)
+		if err != nil {
+			t.Fatalf("GetUnverifiedDecisions: %v", err)
+		}
+		ids := make(map[int64]bool)
+		for _, d := range decisions {
+			if d.ServiceName == service {
+				ids[d.ID] = true
+			}
+		}
+		return ids
+	}
+	if got := unverified(); len(got) != 1 || !got[due.ID] {
+		t.Fatalf("unverified = %v, want only decision %d (not %d, executed too recently)", got, due.ID, tooRecent.ID)
+	}
+
+	checkedAt := now.Truncate(time.Second)
+	if err := db.SetDecisionOutcome(ctx, due.ID, storage.OutcomePersists, checkedAt); err != nil {
+		t.Fatalf("SetDecisionOutcome: %v", err)
+	}
+	got, err := db.GetDecisionById(ctx, strconv.FormatInt(due.ID, 10))
+	if err != nil {
+		t.Fatalf("GetDecisionById: %v", err)
+	}
+	if got.Outcome != storage.OutcomePersists || got.OutcomeCheckedAt == nil || !got.OutcomeCheckedAt.Equal(checkedAt) {
+		t.Errorf("outcome = %q at %v, want persists at %v", got.Outcome, got.OutcomeCheckedAt, checkedAt)
+	}
+	if ids := unverified(); len(ids) != 0 {
+		t.Errorf("unverified after recording = %v, want none", ids)
+	}
+
+	if err := db.SetDecisionOutcome(ctx, 2147483000, storage.OutcomeResolved, checkedAt); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("unknown id: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSetIncidentOutcome(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	for i, lastSeen := range []time.Time{now.Add(-2 * time.Hour), now.Add(-10 * time.Minute)} {
+		if err := db.SaveIncident(ctx, &storage.Incident{
+			ID:              service + "-" + strconv.Itoa(i),
+			ServiceName:     service,
+			ProblemType:     "MEMORY_LEAK",
+			Status:          storage.IncidentClosed,
+			FirstSeen:       lastSeen.Add(-time.Hour),
+			LastSeen:        lastSeen,
+			Occurrences:     3,
+			PeakSeverity:    "HIGH",
+			CurrentSeverity: "HIGH",
+		}); err != nil {
+			t.Fatalf("SaveIncident: %v", err)
+		}
+	}
+
+	found, err := db.SetIncidentOutcome(ctx, service, "MEMORY_LEAK", storage.OutcomeResolved, now)
+	if err != nil || !found {
+		t.Fatalf("SetIncidentOutcome = %v, %v, want an incident updated", found, err)
+	}
+	incidents, err := db.GetIncidents(ctx, "", service, 10)
+	if err != nil {
+		t.Fatalf("GetIncidents: %v", err)
+	}
+	for _, inc := range incidents {
+		latest := inc.ID == service+"-1"
+		if got := inc.RemediationOutcome == storage.OutcomeResolved; got != latest {
+			t.Errorf("incident %s outcome = %q, want it only on the latest incident", inc.ID, inc.RemediationOutcome)
+		}
+	}
+
+	if found, err := db.SetIncidentOutcome(ctx, service, "GC_PRESSURE", storage.OutcomeResolved, now); err != nil || found {
+		t.Errorf("SetIncidentOutcome for a problem without incidents = %v, %v, want false", found, err)
+	}
+}
@@ -23,7 +23,7 @@ type DiagnosisRecord struct {
 func (p *PostgresClient) SaveDiagnosis(ctx context.Context, diagnosis *DiagnosisRecord) error {
 	evidenceJSON, err := json.Marshal(diagnosis.Evidence)
 	if err != nil {
-		logger.Error("Failed to marshal evidence",
+		logger.FromContext(ctx).Error("Failed to marshal evidence",
 			zap.String("service", diagnosis.ServiceName),
 			zap.Error(err),
 		)
@@ -53,13 +53,13 @@ func (p *PostgresClient) SaveDiagnosis(ctx context.Context, diagnosis *Diagnosis
 	).Scan(&id)
 
 	if err != nil {
-		logger.Error("Failed to save diagnosis",
+		logger.FromContext(ctx).Error("Failed to save diagnosis",
 			zap.String("service", diagnosis.ServiceName),
 			zap.Error(err),
 		)
 		return err
 	}
-	logger.Info("Diagnosis saved",
+	logger.FromContext(ctx).Info("Diagnosis saved",
 		zap.String("service", diagnosis.ServiceName),
 		zap.Int64("id", id),
 	)
@@ -101,12 +101,12 @@ func (p *PostgresClient) GetRecentDiagnosis(ctx context.Context, serviceName str
 		)
 
 		if err != nil {
-			logger.Error("Failed to scan diagnosis", zap.Error(err))
+			logger.FromContext(ctx).Error("Failed to scan diagnosis", zap.Error(err))
 			continue
 		}
 
 		if err := json.Unmarshal(evidenceJSON, &d.Evidence); err != nil {
-			logger.Error("Failed to unmarshal evidence", zap.Error(err))
+			logger.FromContext(ctx).Error("Failed to unmarshal evidence", zap.Error(err))
 			continue
 		}
 
@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// EventFilter selects events for GetEvents. Empty fields match everything.
+// Results are newest first; a cursor from a previous page continues after
+// its last event.
+type EventFilter struct {
+	Namespace string
+	PodName   string
+	EventType string
+	Since     time.Time
+	Cursor    *EventCursor
+	Limit     int
+}
+
+// EventCursor marks the last event of a page by its timestamp and ID, which
+// together order events uniquely
+type EventCursor struct {
+	Timestamp time.Time
+	ID        int64
+}
+
+// EventCursorFor returns the cursor that continues after e
+func EventCursorFor(e *Event) *EventCursor {
+	return &EventCursor{Timestamp: e.Timestamp, ID: e.ID}
+}
+
+// Encode renders the cursor as an opaque URL-safe token
+func (c *EventCursor) Encode() string {
+	raw := strconv.FormatInt(c.Timestamp.UnixNano(), 10) + ":" + strconv.FormatInt(c.ID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseEventCursor decodes a token produced by EventCursor.Encode
+func ParseEventCursor(token string) (*EventCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	ts, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	eventID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &EventCursor{Timestamp: time.Unix(0, ts), ID: eventID}, nil
+}
+
+// GetEvents returns the events matching filter, newest first
+func (c *PostgresClient) GetEvents(ctx context.Context, filter EventFilter) ([]*Event, error) {
+	query := `
+		SELECT id, timestamp, event_type, pod_name, namespace, message, created_at
+		FROM events
+		WHERE ($1 = '' OR namespace = $1)
+		  AND ($2 = '' OR pod_name = $2)
+		  AND ($3 = '' OR event_type = $3)
+		  AND timestamp > $4
+		  AND ($5::timestamptz IS NULL OR (timestamp, id) < ($5, $6))
+		ORDER BY timestamp DESC, id DESC
+		LIMIT $7
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var (
+		cursorTime *time.Time
+		cursorID   int64
+	)
+	if filter.Cursor != nil {
+		cursorTime = &filter.Cursor.Timestamp
+		cursorID = filter.Cursor.ID
+	}
+
+	rows, err := c.queryPool(ctx).Query(ctx, query,
+		filter.Namespace, filter.PodName, filter.EventType, filter.Since,
+		cursorTime, cursorID, filter.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	return scanEvents(rows)
+}
+
+func scanEvents(rows pgx.Rows) ([]*Event, error) {
+	defer rows.Close()
+
+	var events []*Event
+	for rows.Next() {
+		var e Event
+		if err := rows.Scan(
+			&e.ID,
+			&e.Timestamp,
+			&e.EventType,
+			&e.PodName,
+			&e.Namespace,
+			&e.Message,
+			&e.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		events = append(events, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating events: %w", err)
+	}
+	return events, nil
+}
@@ -0,0 +1,196 @@
+package storage_test
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+)
+
+// saveEvent stores a pod event in the default namespace
+func saveEvent(t *testing.T, db *storage.PostgresClient, eventType, pod string, at time.Time) {
+	t.Helper()
+	saveNamespacedEvent(t, db, "default", eventType, pod, at)
+}
+
+// saveNamespacedEvent stores a pod event in namespace
+func saveNamespacedEvent(t *testing.T, db *storage.PostgresClient, namespace, eventType, pod string, at time.Time) {
+	t.Helper()
+
+	if err := db.SaveEvent(context.Background(), &storage.Event{
+		Timestamp: at,
+		EventType: eventType,
+		PodName:   pod,
+		Namespace: namespace,
+		Message:   "test event",
+	}); err != nil {
+		t.Fatalf("SaveEvent: %v", err)
+	}
+}
+
+func TestGetServiceEventsMatchesServicePods(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+	now := time.Now()
+
+	saveEvent(t, db, storage.EventOOMKilled, service, now.Add(-time.Minute))
+	saveEvent(t, db, storage.EventOOMKilled, service+"-7d9f-abc12", now.Add(-2*time.Minute))
+	saveEvent(t, db, storage.EventFailedScheduling, service+"-7d9f-def34", now.Add(-3*time.Minute))
+	saveEvent(t, db, storage.EventOOMKilled, service+"-7d9f-old", now.Add(-3*time.Hour))
+	saveEvent(t, db, "Created", service+"-7d9f-abc12", now.Add(-time.Minute))
+	// A pod of another service whose name only starts with this one's
+	saveEvent(t, db, storage.EventOOMKilled, service+"x-7d9f", now.Add(-time.Minute))
+	t.Cleanup(func() {
+		if _, err := db.PurgeService(context.Background(), service+"x-7d9f"); err != nil {
+			t.Errorf("PurgeService: %v", err)
+		}
+	})
+
+	events, err := db.GetServiceEvents(ctx, service,
+		[]string{storage.EventOOMKilled, storage.EventFailedScheduling}, now.Add(-time.Hour), now)
+	if err != nil {
+		t.Fatalf("GetServiceEvents: %v", err)
+	}
+
+	var pods []string
+	for _, e := range events {
+		pods = append(pods, e.PodName)
+	}
+	want := []string{service, service + "-7d9f-abc12", service + "-7d9f-def34"}
+	if strings.Join(pods, ",") != strings.Join(want, ",") {
+		t.Errorf("events for pods %v, want %v newest first", pods, want)
+	}
+	if !sort.SliceIsSorted(events, func(i, j int) bool { return events[i].Timestamp.After(events[j].Timestamp) }) {
+		t.Error("events not newest first")
+	}
+}
+
+func TestGetServiceEventsSkipsLongerServicesPods(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+	now := time.Now().Add(-time.Minute).Truncate(time.Second)
+
+	// gateway's pods start with "<service>-" too, like api and api-gateway
+	gateway := service + "-gateway"
+	t.Cleanup(func() {
+		if _, err := db.PurgeService(context.Background(), gateway); err != nil {
+			t.Errorf("PurgeService: %v", err)
+		}
+	})
+
+	storagetest.Seed(t, db, storagetest.Series(service, "cpu_usage", now, 15*time.Second, 40))
+	storagetest.Seed(t, db, storagetest.Series(gateway, "cpu_usage", now, 15*time.Second, 30))
+	saveEvent(t, db, storage.EventOOMKilled, service+"-7d9f-abc12", now)
+	saveEvent(t, db, storage.EventOOMKilled, gateway, now)
+	saveEvent(t, db, storage.EventOOMKilled, gateway+"-5c6d-def34", now)
+
+	oom := []string{storage.EventOOMKilled}
+	events, err := db.GetServiceEvents(ctx, service, oom, now.Add(-time.Hour), now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetServiceEvents: %v", err)
+	}
+	if got, want := eventPods(events), []string{service + "-7d9f-abc12"}; !slices.Equal(got, want) {
+		t.Errorf("events of %s = %v, want %v without %s's pods", service, got, want, gateway)
+	}
+
+	if events, err = db.GetServiceEvents(ctx, gateway, oom, now.Add(-time.Hour), now.Add(time.Hour)); err != nil {
+		t.Fatalf("GetServiceEvents: %v", err)
+	}
+	got := eventPods(events)
+	slices.Sort(got)
+	if want := []string{gateway, gateway + "-5c6d-def34"}; !slices.Equal(got, want) {
+		t.Errorf("events of %s = %v, want %v", gateway, got, want)
+	}
+}
+
+func TestEventCursorRoundTrip(t *testing.T) {
+	cursor := &storage.EventCursor{Timestamp: time.Date(2026, 1, 1, 12, 0, 0, 123456789, time.UTC), ID: 42}
+
+	parsed, err := storage.ParseEventCursor(cursor.Encode())
+	if err != nil {
+		t.Fatalf("ParseEventCursor: %v", err)
+	}
+	if !parsed.Timestamp.Equal(cursor.Timestamp) || parsed.ID != cursor.ID {
+		t.Errorf("round trip = %+v, want %+v", parsed, cursor)
+	}
+
+	for _, token := range []string{"not base64!", "bm8tY29sb24", "eDo0Mg", "MTIzOng"} {
+		if _, err := storage.ParseEventCursor(token); err == nil {
+			t.Errorf("ParseEventCursor(%q) succeeded, want an error", token)
+		}
+	}
+}
+
+// eventPods returns the pod names of events in order
+func eventPods(events []*storage.Event) []string {
+	pods := make([]string, len(events))
+	for i, e := range events {
+		pods[i] = e.PodName
+	}
+	return pods
+}
+
+func TestGetEventsFilters(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+	now := time.Now()
+
+	// The service name doubles as a namespace no other test writes to
+	namespace, other := service, service+"-other"
+	saveNamespacedEvent(t, db, namespace, storage.EventOOMKilled, service+"-a", now.Add(-time.Minute))
+	saveNamespacedEvent(t, db, namespace, "Created", service+"-b", now.Add(-2*time.Minute))
+	saveNamespacedEvent(t, db, namespace, storage.EventOOMKilled, service+"-c", now.Add(-3*time.Minute))
+	saveNamespacedEvent(t, db, namespace, storage.EventOOMKilled, service+"-old", now.Add(-2*time.Hour))
+	saveNamespacedEvent(t, db, other, storage.EventOOMKilled, service+"-d", now.Add(-time.Minute))
+
+	tests := []struct {
+		name   string
+		filter storage.EventFilter
+		want   []string
+	}{
+		{"namespace", storage.EventFilter{Namespace: namespace},
+			[]string{service + "-a", service + "-b", service + "-c"}},
+		{"other namespace", storage.EventFilter{Namespace: other},
+			[]string{service + "-d"}},
+		{"event type", storage.EventFilter{Namespace: namespace, EventType: storage.EventOOMKilled},
+			[]string{service + "-a", service + "-c"}},
+		{"pod", storage.EventFilter{Namespace: namespace, PodName: service + "-b"},
+			[]string{service + "-b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.filter.Since = now.Add(-time.Hour)
+			tt.filter.Limit = 100
+			events, err := db.GetEvents(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("GetEvents: %v", err)
+			}
+			if got := eventPods(events); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("pods = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Paging two at a time continues where the cursor left off
+	filter := storage.EventFilter{Namespace: namespace, Since: now.Add(-time.Hour), Limit: 2}
+	first, err := db.GetEvents(ctx, filter)
+	if err != nil {
+		t.Fatalf("GetEvents: %v", err)
+	}
+	filter.Cursor = storage.EventCursorFor(first[len(first)-1])
+	second, err := db.GetEvents(ctx, filter)
+	if err != nil {
+		t.Fatalf("GetEvents: %v", err)
+	}
+	if got := eventPods(append(first, second...)); strings.Join(got, ",") != strings.Join(tests[0].want, ",") {
+		t.Errorf("paged pods = %v, want %v", got, tests[0].want)
+	}
+}
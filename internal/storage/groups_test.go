@@ -0,0 +1,24 @@
+package storage
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestGroupLabel(t *testing.T) {
+	tests := []struct {
+		labels string
+		want   string
+	}{
+		{`{"group":"checkout","pod":"p-1"}`, "checkout"},
+		{`{"pod":"p-1"}`, ""},
+		{`{"group":42}`, ""},
+		{`{"group":`, ""},
+		{``, ""},
+	}
+	for _, tt := range tests {
+		if got := groupLabel(json.RawMessage(tt.labels)); got != tt.want {
+			t.Errorf("groupLabel(%s) = %q, want %q", tt.labels, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// maxHealthPoints caps the points GetHealthHistory returns; longer histories
+// keep the most recent ones
+const maxHealthPoints = 2000
+
+// HealthPoint is one recorded health score of a service
+type HealthPoint struct {
+	Timestamp   time.Time `json:"timestamp"`
+	HealthScore float64   `json:"health_score"`
+}
+
+// SaveHealthPoint records a service's health score as computed by a diagnosis
+func (c *PostgresClient) SaveHealthPoint(ctx context.Context, serviceName string, p HealthPoint) error {
+	query := `
+		INSERT INTO health_history (service_name, timestamp, health_score)
+		VALUES ($1, $2, $3)
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := c.pool.Exec(ctx, query, serviceName, p.Timestamp, p.HealthScore); err != nil {
+		return fmt.Errorf("failed to save health point: %w", err)
+	}
+	return nil
+}
+
+// GetHealthHistory returns the health scores recorded for a service within
+// the duration before AsOf(ctx), oldest first
+func (c *PostgresClient) GetHealthHistory(ctx context.Context, serviceName string, duration time.Duration) ([]HealthPoint, error) {
+	query := `
+		SELECT timestamp, health_score FROM (
+			SELECT timestamp, health_score
+			FROM health_history
+			WHERE service_name = $1
+			  AND timestamp > $2
+			  AND timestamp <= $3
+			ORDER BY timestamp DESC
+			LIMIT $4
+		) recent
+		ORDER BY timestamp ASC
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	until := AsOf(ctx)
+	rows, err := c.queryPool(ctx).Query(ctx, query, serviceName, until.Add(-duration), until, maxHealthPoints)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query health history: %w", err)
+	}
+	defer rows.Close()
+
+	var points []HealthPoint
+	for rows.Next() {
+		var p HealthPoint
+		if err := rows.Scan(&p.Timestamp, &p.HealthScore); err != nil {
+			return nil, fmt.Errorf("failed to scan health point: %w", err)
+		}
+		points = append(points, p)
+	}
+
+	return points, rows.Err()
+}
+
+// GetLatestHealth returns each service's most recent health score recorded
+// within the duration before AsOf(ctx)
+func (c *PostgresClient) GetLatestHealth(ctx context.Context, within time.Duration) (map[string]HealthPoint, error) {
+	query := `
+		SELECT DISTINCT ON (service_name) service_name, timestamp, health_score
+		FROM health_history
+		WHERE timestamp > $1
+		  AND timestamp <= $2
+		ORDER BY service_name, timestamp DESC
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	until := AsOf(ctx)
+	rows, err := c.queryPool(ctx).Query(ctx, query, until.Add(-within), until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query latest health: %w", err)
+	}
+	defer rows.Close()
+
+	latest := make(map[string]HealthPoint)
+	for rows.Next() {
+		var service string
+		var p HealthPoint
+		if err := rows.Scan(&service, &p.Timestamp, &p.HealthScore); err != nil {
+			return nil, fmt.Errorf("failed to scan latest health: %w", err)
+		}
+		latest[service] = p
+	}
+
+	return latest, rows.Err()
+}
+
+// HealthSummary aggregates a service's recorded health scores over a period
+type HealthSummary struct {
+	ServiceName string  `json:"service_name"`
+	Points      int     `json:"points"`
+	Min         float64 `json:"min"`
+	Mean        float64 `json:"mean"`
+}
+
+// GetHealthSummaries aggregates each service's health scores recorded in
+// [from, to), least healthy (lowest mean) first
+func (c *PostgresClient) GetHealthSummaries(ctx context.Context, from, to time.Time) ([]HealthSummary, error) {
+	query := `
+		SELECT service_name, COUNT(*), MIN(health_score), AVG(health_score)
+		FROM health_history
+		WHERE timestamp >= $1
+		  AND timestamp < $2
+		GROUP BY service_name
+		ORDER BY AVG(health_score) ASC, service_name
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	rows, err := c.queryPool(ctx).Query(ctx, query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query health summaries: %w", err)
+	}
+	defer rows.Close()
+
+	var summaries []HealthSummary
+	for rows.Next() {
+		var s HealthSummary
+		if err := rows.Scan(&s.ServiceName, &s.Points, &s.Min, &s.Mean); err != nil {
+			return nil, fmt.Errorf("failed to scan health summary: %w", err)
+		}
+		summaries = append(summaries, s)
+	}
+
+	return summaries, rows.Err()
+}
@@ -0,0 +1,86 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+)
+
+func TestGetLatestHealth(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	stale := storagetest.Service(t, db)
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	points := []struct {
+		service string
+		ago     time.Duration
+		score   float64
+	}{
+		{service, 20 * time.Minute, 80},
+		{service, 5 * time.Minute, 62},
+		{stale, 2 * time.Hour, 40},
+	}
+	for _, p := range points {
+		if err := db.SaveHealthPoint(ctx, p.service, storage.HealthPoint{Timestamp: now.Add(-p.ago), HealthScore: p.score}); err != nil {
+			t.Fatalf("SaveHealthPoint: %v", err)
+		}
+	}
+
+	latest, err := db.GetLatestHealth(ctx, time.Hour)
+	if err != nil {
+		t.Fatalf("GetLatestHealth: %v", err)
+	}
+	if got := latest[service]; got.HealthScore != 62 || !got.Timestamp.Equal(now.Add(-5*time.Minute)) {
+		t.Errorf("latest[%s] = %+v, want the 62 recorded 5m ago", service, got)
+	}
+	if got, ok := latest[stale]; ok {
+		t.Errorf("latest[%s] = %+v, want a score older than the window left out", stale, got)
+	}
+}
+
+func TestCountIncidents(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	before := make(map[string]int)
+	for _, status := range []string{"", storage.IncidentOpen, storage.IncidentClosed} {
+		n, err := db.CountIncidents(ctx, status)
+		if err != nil {
+			t.Fatalf("CountIncidents(%q): %v", status, err)
+		}
+		before[status] = n
+	}
+
+	for i, status := range []string{storage.IncidentOpen, storage.IncidentOpen, storage.IncidentClosed} {
+		if err := db.SaveIncident(ctx, &storage.Incident{
+			ID:              service + "-" + string(rune('a'+i)),
+			ServiceName:     service,
+			ProblemType:     "MEMORY_LEAK",
+			Status:          status,
+			FirstSeen:       now.Add(-time.Hour),
+			LastSeen:        now,
+			Occurrences:     1,
+			PeakSeverity:    "HIGH",
+			CurrentSeverity: "HIGH",
+		}); err != nil {
+			t.Fatalf("SaveIncident: %v", err)
+		}
+	}
+
+	for status, added := range map[string]int{"": 3, storage.IncidentOpen: 2, storage.IncidentClosed: 1} {
+		n, err := db.CountIncidents(ctx, status)
+		if err != nil {
+			t.Fatalf("CountIncidents(%q): %v", status, err)
+		}
+		if n != before[status]+added {
+			t.Errorf("CountIncidents(%q) = %d, want %d", status, n, before[status]+added)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Incident statuses
+const (
+	IncidentOpen   = "open"
+	IncidentClosed = "closed"
+)
+
+// Incident groups consecutive diagnoses of the same service and problem
+type Incident struct {
+	ID               string     `json:"id"`
+	ServiceName      string     `json:"service_name"`
+	ProblemType      string     `json:"problem_type"`
+	Status           string     `json:"status"`
+	FirstSeen        time.Time  `json:"first_seen"`
+	LastSeen         time.Time  `json:"last_seen"`
+	ClosedAt         *time.Time `json:"closed_at,omitempty"`
+	Occurrences      int        `json:"occurrences"`
+	PeakSeverity     string     `json:"peak_severity"`
+	CurrentSeverity  string     `json:"current_severity"`
+	LastPredictionID string     `json:"last_prediction_id,omitempty"`
+
+	// RemediationOutcome is the latest recovery check after a remediation of
+	// this incident's problem: resolved, persists or worsened
+	RemediationOutcome   string     `json:"remediation_outcome,omitempty"`
+	RemediationCheckedAt *time.Time `json:"remediation_checked_at,omitempty"`
+}
+
+// SaveIncident inserts the incident or updates it in place
+func (c *PostgresClient) SaveIncident(ctx context.Context, inc *Incident) error {
+	query := `
+		INSERT INTO incidents (
+			id, service_name, problem_type, status, first_seen, last_seen,
+			closed_at, occurrences, peak_severity, current_severity, last_prediction_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (id) DO UPDATE SET
+			status = EXCLUDED.status,
+			last_seen = EXCLUDED.last_seen,
+			closed_at = EXCLUDED.closed_at,
+			occurrences = EXCLUDED.occurrences,
+			peak_severity = EXCLUDED.peak_severity,
+			current_severity = EXCLUDED.current_severity,
+			last_prediction_id = EXCLUDED.last_prediction_id
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := c.pool.Exec(ctx, query,
+		inc.ID,
+		inc.ServiceName,
+		inc.ProblemType,
+		inc.Status,
+		inc.FirstSeen,
+		inc.LastSeen,
+		inc.ClosedAt,
+		inc.Occurrences,
+		inc.PeakSeverity,
+		inc.CurrentSeverity,
+		inc.LastPredictionID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save incident: %w", err)
+	}
+	return nil
+}
+
+// GetIncidents returns incidents, newest activity first. Empty status or
+// service match everything.
+func (c *PostgresClient) GetIncidents(ctx context.Context, status, service string, limit int) ([]*Incident, error) {
+	query := `
+		SELECT id, service_name, problem_type, status, first_seen, last_seen,
+		       closed_at, occurrences, peak_severity, current_severity,
+		       COALESCE(last_prediction_id, ''),
+		       COALESCE(remediation_outcome, ''), remediation_checked_at
+		FROM incidents
+		WHERE ($1 = '' OR status = $1)
+		  AND ($2 = '' OR service_name = $2)
+		ORDER BY last_seen DESC
+		LIMIT $3
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := c.queryPool(ctx).Query(ctx, query, status, service, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query incidents: %w", err)
+	}
+	defer rows.Close()
+
+	return scanIncidents(rows)
+}
+
+// GetIncident loads one incident by ID. It returns ErrNotFound when there is
+// no such incident.
+func (c *PostgresClient) GetIncident(ctx context.Context, id string) (*Incident, error) {
+	query := `
+		SELECT id, service_name, problem_type, status, first_seen, last_seen,
+		       closed_at, occurrences, peak_severity, current_severity,
+		       COALESCE(last_prediction_id, ''),
+		       COALESCE(remediation_outcome, ''), remediation_checked_at
+		FROM incidents
+		WHERE id = $1
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := c.queryPool(ctx).Query(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query incident: %w", err)
+	}
+	defer rows.Close()
+
+	incidents, err := scanIncidents(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(incidents) == 0 {
+		return nil, ErrNotFound
+	}
+	return incidents[0], nil
+}
+
+func scanIncidents(rows pgx.Rows) ([]*Incident, error) {
+	var incidents []*Incident
+	for rows.Next() {
+		var inc Incident
+		if err := rows.Scan(
+			&inc.ID,
+			&inc.ServiceName,
+			&inc.ProblemType,
+			&inc.Status,
+			&inc.FirstSeen,
+			&inc.LastSeen,
+			&inc.ClosedAt,
+			&inc.Occurrences,
+			&inc.PeakSeverity,
+			&inc.CurrentSeverity,
+			&inc.LastPredictionID,
+			&inc.RemediationOutcome,
+			&inc.RemediationCheckedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan incident: %w", err)
+		}
+		incidents = append(incidents, &inc)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating incidents: %w", err)
+	}
+
+	return incidents, nil
+}
+
+// GetIncidentsBetween returns the incidents active at some point in
+// [from, to): opened before to and not closed before from. Oldest first.
+func (c *PostgresClient) GetIncidentsBetween(ctx context.Context, from, to time.Time, limit int) ([]*Incident, error) {
+	query := `
+		SELECT id, service_name, problem_type, status, first_seen, last_seen,
+		       closed_at, occurrences, peak_severity, current_severity,
+		       COALESCE(last_prediction_id, ''),
+		       COALESCE(remediation_outcome, ''), remediation_checked_at
+		FROM incidents
+		WHERE first_seen < $2
+		  AND (closed_at IS NULL OR closed_at >= $1)
+		ORDER BY first_seen ASC
+		LIMIT $3
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	rows, err := c.queryPool(ctx).Query(ctx, query, from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query incidents: %w", err)
+	}
+	defer rows.Close()
+
+	return scanIncidents(rows)
+}
+
+// CountIncidents returns how many incidents have the status; empty counts all
+func (c *PostgresClient) CountIncidents(ctx context.Context, status string) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM incidents
+		WHERE ($1 = '' OR status = $1)
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var count int
+	if err := c.queryPool(ctx).QueryRow(ctx, query, status).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count incidents: %w", err)
+	}
+	return count, nil
+}
+
+// SetIncidentOutcome records a recovery check on the service's most recent
+// incident for the problem. It reports whether such an incident exists.
+func (c *PostgresClient) SetIncidentOutcome(ctx context.Context, service, problemType, outcome string, checkedAt time.Time) (bool, error) {
+	query := `
+		UPDATE incidents
+		SET remediation_outcome = $3, remediation_checked_at = $4
+		WHERE id = (
+			SELECT id FROM incidents
+			WHERE service_name = $1 AND problem_type = $2
+			ORDER BY last_seen DESC
+			LIMIT 1
+		)
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tag, err := c.pool.Exec(ctx, query, service, problemType, outcome, checkedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to update incident outcome: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"encoding/json"
+	"sort"
+
+	"go.uber.org/zap"
+)
+
+// LabelLimits bounds the labels stored with each metric, so a source with
+// high-cardinality labels can't bloat the metrics table. Zero or negative
+// limits and an empty allowlist disable the corresponding check.
+type LabelLimits struct {
+	MaxKeys  int      // label keys kept per metric
+	MaxBytes int      // encoded size of a metric's labels
+	Allowed  []string // label keys to keep; empty keeps every key
+}
+
+// SetLabelLimits trims the labels of metrics saved from now on. Call it
+// before the client is shared.
+func (c *PostgresClient) SetLabelLimits(limits LabelLimits) {
+	c.labelLimits = limits
+}
+
+// enabled reports whether any limit is set
+func (l LabelLimits) enabled() bool {
+	return l.MaxKeys > 0 || l.MaxBytes > 0 || len(l.Allowed) > 0
+}
+
+// trim returns labels cut down to the limits and whether anything was
+// dropped. Keys outside the allowlist go first, then keys past MaxKeys in
+// sorted order, then the last keys until the labels fit MaxBytes. Labels
+// that aren't a JSON object are dropped whole when they exceed MaxBytes.
+func (l LabelLimits) trim(labels json.RawMessage) (json.RawMessage, bool) {
+	if !l.enabled() || len(labels) == 0 {
+		return labels, false
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(labels, &fields); err != nil || fields == nil {
+		if l.MaxBytes > 0 && len(labels) > l.MaxBytes {
+			return nil, true
+		}
+		return labels, false
+	}
+
+	allowed := make(map[string]bool, len(l.Allowed))
+	for _, key := range l.Allowed {
+		allowed[key] = true
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		if len(allowed) == 0 || allowed[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	if l.MaxKeys > 0 && len(keys) > l.MaxKeys {
+		keys = keys[:l.MaxKeys]
+	}
+
+	if len(keys) == len(fields) && (l.MaxBytes <= 0 || len(labels) <= l.MaxBytes) {
+		return labels, false
+	}
+
+	kept := make(map[string]json.RawMessage, len(keys))
+	for _, key := range keys {
+		kept[key] = fields[key]
+	}
+	for {
+		trimmed, err := json.Marshal(kept)
+		if err != nil {
+			return nil, true
+		}
+		if l.MaxBytes <= 0 || len(trimmed) <= l.MaxBytes || len(keys) == 0 {
+			return trimmed, len(keys) < len(fields)
+		}
+		delete(kept, keys[len(keys)-1])
+		keys = keys[:len(keys)-1]
+	}
+}
+
+// trimLabels applies the label limits to metrics in place, logging one
+// warning per call that names the first metric trimmed
+func (c *PostgresClient) trimLabels(metrics []*Metric) {
+	if !c.labelLimits.enabled() {
+		return
+	}
+	var first *Metric
+	trimmed := 0
+	for _, m := range metrics {
+		labels, cut := c.labelLimits.trim(m.Labels)
+		if !cut {
+			continue
+		}
+		m.Labels = labels
+		if first == nil {
+			first = m
+		}
+		trimmed++
+	}
+	if trimmed > 0 {
+		c.logger.Warn("Dropped metric labels over the configured limits",
+			zap.Int("metrics", trimmed),
+			zap.String("service", first.ServiceName),
+			zap.String("metric", first.MetricName))
+	}
+}
@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// labelKeys decodes a labels object and returns its keys
+func labelKeys(t *testing.T, labels json.RawMessage) map[string]bool {
+	t.Helper()
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(labels, &fields); err != nil {
+		t.Fatalf("trimmed labels %s aren't an object: %v", labels, err)
+	}
+	keys := make(map[string]bool, len(fields))
+	for k := range fields {
+		keys[k] = true
+	}
+	return keys
+}
+
+func TestLabelLimitsTooManyKeys(t *testing.T) {
+	labels := json.RawMessage(`{"d":"4","a":"1","c":"3","b":"2","e":"5"}`)
+
+	trimmed, cut := LabelLimits{MaxKeys: 3}.trim(labels)
+	if !cut {
+		t.Fatal("5 keys against a limit of 3 weren't trimmed")
+	}
+	keys := labelKeys(t, trimmed)
+	if len(keys) != 3 || !keys["a"] || !keys["b"] || !keys["c"] {
+		t.Errorf("kept %s, want the first 3 keys in sorted order", trimmed)
+	}
+}
+
+func TestLabelLimitsOversized(t *testing.T) {
+	fields := make(map[string]string)
+	for i := range 10 {
+		fields[fmt.Sprintf("key%02d", i)] = strings.Repeat("v", 40)
+	}
+	labels, err := json.Marshal(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	limits := LabelLimits{MaxBytes: 200}
+	trimmed, cut := limits.trim(labels)
+	if !cut {
+		t.Fatalf("%d bytes of labels against a limit of 200 weren't trimmed", len(labels))
+	}
+	if len(trimmed) > limits.MaxBytes {
+		t.Errorf("trimmed labels are %d bytes, want at most %d", len(trimmed), limits.MaxBytes)
+	}
+	keys := labelKeys(t, trimmed)
+	if len(keys) == 0 || !keys["key00"] || keys["key09"] {
+		t.Errorf("kept %s, want the last keys dropped first", trimmed)
+	}
+
+	// A single value larger than the limit leaves an empty object
+	trimmed, cut = LabelLimits{MaxBytes: 10}.trim(json.RawMessage(`{"pod":"` + strings.Repeat("x", 50) + `"}`))
+	if !cut || string(trimmed) != "{}" {
+		t.Errorf("oversized single label = %s (cut %v), want {}", trimmed, cut)
+	}
+}
+
+func TestLabelLimitsAllowlist(t *testing.T) {
+	labels := json.RawMessage(`{"pod":"api-1","namespace":"prod","request_id":"8f3a","user":"42"}`)
+
+	trimmed, cut := LabelLimits{Allowed: []string{"pod", "namespace", "instance"}}.trim(labels)
+	if !cut {
+		t.Fatal("labels outside the allowlist weren't dropped")
+	}
+	keys := labelKeys(t, trimmed)
+	if len(keys) != 2 || !keys["pod"] || !keys["namespace"] {
+		t.Errorf("kept %s, want only pod and namespace", trimmed)
+	}
+}
+
+func TestLabelLimitsKeepsLabelsWithinLimits(t *testing.T) {
+	labels := json.RawMessage(`{"pod":"api-1", "namespace":"prod"}`)
+
+	tests := []struct {
+		name   string
+		limits LabelLimits
+		labels json.RawMessage
+	}{
+		{"within limits", LabelLimits{MaxKeys: 2, MaxBytes: 100, Allowed: []string{"pod", "namespace"}}, labels},
+		{"disabled", LabelLimits{MaxKeys: -1, MaxBytes: -1}, labels},
+		{"no labels", LabelLimits{MaxKeys: 1, MaxBytes: 1}, nil},
+		{"small non-object", LabelLimits{MaxKeys: 1, MaxBytes: 100}, json.RawMessage(`["a","b","c"]`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trimmed, cut := tt.limits.trim(tt.labels)
+			if cut || string(trimmed) != string(tt.labels) {
+				t.Errorf("trim = %s (cut %v), want %s untouched", trimmed, cut, tt.labels)
+			}
+		})
+	}
+}
+
+func TestLabelLimitsOversizedNonObject(t *testing.T) {
+	trimmed, cut := LabelLimits{MaxBytes: 10}.trim(json.RawMessage(`["` + strings.Repeat("x", 20) + `"]`))
+	if !cut || trimmed != nil {
+		t.Errorf("trim = %s (cut %v), want an oversized non-object dropped whole", trimmed, cut)
+	}
+}
+
+func TestTrimLabelsLogsOnce(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	c := &PostgresClient{logger: zap.New(core)}
+	c.SetLabelLimits(LabelLimits{MaxKeys: 1})
+
+	metrics := []*Metric{
+		{ServiceName: "checkout", MetricName: "cpu_usage", Labels: json.RawMessage(`{"pod":"p"}`)},
+		{ServiceName: "checkout", MetricName: "memory_usage", Labels: json.RawMessage(`{"pod":"p","node":"n"}`)},
+		{ServiceName: "checkout", MetricName: "error_rate", Labels: json.RawMessage(`{"pod":"p","node":"n"}`)},
+	}
+	c.trimLabels(metrics)
+
+	if string(metrics[0].Labels) != `{"pod":"p"}` {
+		t.Errorf("labels within the limit = %s, want them untouched", metrics[0].Labels)
+	}
+	if string(metrics[1].Labels) != `{"node":"n"}` {
+		t.Errorf("trimmed labels = %s, want the first key kept", metrics[1].Labels)
+	}
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("logged %d warnings, want 1 per call", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["metrics"] != int64(2) || fields["metric"] != "memory_usage" {
+		t.Errorf("warning fields = %v, want 2 metrics and the first trimmed, memory_usage", fields)
+	}
+}
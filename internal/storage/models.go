@@ -13,6 +13,11 @@ type Metric struct {
 	MetricValue float64         `json:"metric_value"`
 	Labels      json.RawMessage `json:"labels,omitempty"`
 	CreatedAt   time.Time       `json:"created_at"`
+
+	// MetricMin and MetricMax are set on rows averaged over a write
+	// resolution bucket, where MetricValue is the mean
+	MetricMin *float64 `json:"metric_min,omitempty"`
+	MetricMax *float64 `json:"metric_max,omitempty"`
 }
 
 type MetricStats struct {
@@ -26,6 +31,18 @@ type MetricStats struct {
 	Duration    time.Duration `json:"duration"`
 }
 
+// Event types the pod watcher records besides the watch's own ADDED,
+// MODIFIED and DELETED
+const (
+	EventCrashLoop        = "CrashLoop"
+	EventOOMKilled        = "OOMKilled"
+	EventFailedScheduling = "FailedScheduling"
+)
+
+// EventAnomaly is recorded by the analyzer's anomaly scan, with the service
+// name as the pod name
+const EventAnomaly = "Anomaly"
+
 // Event represents a Kubernetes event
 type Event struct {
 	ID        int64     `json:"id"`
@@ -46,7 +63,35 @@ type Decision struct {
 	Reason          string          `json:"reason"`
 	Parameters      json.RawMessage `json:"parameters,omitempty"`
 	Executed        bool            `json:"executed"`
+	ExecutedAt      *time.Time      `json:"executed_at,omitempty"`
+	ExecutionResult string          `json:"execution_result,omitempty"`
 	CreatedAt       time.Time       `json:"created_at"`
+
+	// ServiceName is the remediated service. Older rows only carry it in
+	// Parameters, which the read path falls back to.
+	ServiceName string `json:"service_name,omitempty"`
+
+	// Outcome is the recovery check run some time after execution:
+	// resolved, persists or worsened
+	Outcome          string     `json:"outcome,omitempty"`
+	OutcomeCheckedAt *time.Time `json:"outcome_checked_at,omitempty"`
+}
+
+// Remediation outcomes recorded by the recovery check
+const (
+	OutcomeResolved = "resolved"
+	OutcomePersists = "persists"
+	OutcomeWorsened = "worsened"
+)
+
+// DecisionFilter narrows GetDecisions. Zero values match everything.
+type DecisionFilter struct {
+	Executed    *bool
+	ActionType  string
+	ServiceName string
+	Since       time.Time
+	Until       time.Time
+	Limit       int
 }
 
 type DecisionStats struct {
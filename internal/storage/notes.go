@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ServiceNote is operator context attached to a service, e.g. "known noisy,
+// ignore cascade alerts before 9am". A note without ExpiresAt never expires.
+type ServiceNote struct {
+	ID          int64      `json:"id"`
+	ServiceName string     `json:"service_name"`
+	Note        string     `json:"note"`
+	Author      string     `json:"author,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+// SaveServiceNote records a note and fills in its ID and creation time
+func (c *PostgresClient) SaveServiceNote(ctx context.Context, n *ServiceNote) error {
+	query := `
+		INSERT INTO service_notes (service_name, note, author, expires_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4)
+		RETURNING id, created_at
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := c.pool.QueryRow(ctx, query, n.ServiceName, n.Note, n.Author, n.ExpiresAt).Scan(&n.ID, &n.CreatedAt); err != nil {
+		return fmt.Errorf("failed to save service note: %w", err)
+	}
+	return nil
+}
+
+// GetServiceNotes returns a service's notes, newest first. Unless
+// includeExpired is set, only the notes in effect at the given time are
+// returned: created by then and not yet expired.
+func (c *PostgresClient) GetServiceNotes(ctx context.Context, serviceName string, at time.Time, includeExpired bool) ([]*ServiceNote, error) {
+	query := `
+		SELECT id, service_name, note, COALESCE(author, ''), created_at, expires_at
+		FROM service_notes
+		WHERE service_name = $1
+		  AND ($3 OR (created_at <= $2 AND (expires_at IS NULL OR expires_at > $2)))
+		ORDER BY created_at DESC
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := c.queryPool(ctx).Query(ctx, query, serviceName, at, includeExpired)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query service notes: %w", err)
+	}
+	defer rows.Close()
+
+	var notes []*ServiceNote
+	for rows.Next() {
+		var n ServiceNote
+		if err := rows.Scan(&n.ID, &n.ServiceName, &n.Note, &n.Author, &n.CreatedAt, &n.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan service note: %w", err)
+		}
+		notes = append(notes, &n)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating service notes: %w", err)
+	}
+
+	return notes, nil
+}
+
+// DeleteServiceNote removes one of a service's notes. It returns ErrNotFound
+// when the service has no note with that ID.
+func (c *PostgresClient) DeleteServiceNote(ctx context.Context, serviceName string, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := c.pool.Exec(ctx, `DELETE FROM service_notes WHERE service_name = $1 AND id = $2`, serviceName, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete service note: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
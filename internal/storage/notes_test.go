@@ -0,0 +1,108 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+)
+
+// noteTexts lists the notes' text in order
+func noteTexts(notes []*storage.ServiceNote) []string {
+	texts := make([]string, len(notes))
+	for i, n := range notes {
+		texts[i] = n.Note
+	}
+	return texts
+}
+
+func TestServiceNotesCRUD(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+
+	note := &storage.ServiceNote{ServiceName: service, Note: "known noisy before 9am", Author: "oncall"}
+	if err := db.SaveServiceNote(ctx, note); err != nil {
+		t.Fatalf("SaveServiceNote: %v", err)
+	}
+	if note.ID == 0 || note.CreatedAt.IsZero() {
+		t.Fatalf("saved note = %+v, want its id and creation time filled in", note)
+	}
+
+	// A minute ahead tolerates clock skew against the database's created_at
+	at := time.Now().Add(time.Minute)
+	notes, err := db.GetServiceNotes(ctx, service, at, false)
+	if err != nil {
+		t.Fatalf("GetServiceNotes: %v", err)
+	}
+	if len(notes) != 1 || notes[0].ID != note.ID || notes[0].Note != note.Note || notes[0].Author != "oncall" || notes[0].ExpiresAt != nil {
+		t.Fatalf("notes = %+v, want the saved note", notes)
+	}
+	if notes, err := db.GetServiceNotes(ctx, "other-"+service, at, false); err != nil || len(notes) != 0 {
+		t.Errorf("another service's notes = %v (err %v), want none", notes, err)
+	}
+
+	if err := db.DeleteServiceNote(ctx, "other-"+service, note.ID); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("delete under another service: err = %v, want ErrNotFound", err)
+	}
+	if err := db.DeleteServiceNote(ctx, service, note.ID); err != nil {
+		t.Fatalf("DeleteServiceNote: %v", err)
+	}
+	if notes, err := db.GetServiceNotes(ctx, service, at, true); err != nil || len(notes) != 0 {
+		t.Errorf("notes after delete = %v (err %v), want none", notes, err)
+	}
+	if err := db.DeleteServiceNote(ctx, service, note.ID); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("second delete: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestServiceNotesExpiry(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+
+	now := time.Now()
+	inAnHour := now.Add(time.Hour)
+	expired := now.Add(-time.Minute)
+	for _, n := range []*storage.ServiceNote{
+		{ServiceName: service, Note: "permanent"},
+		{ServiceName: service, Note: "for the next hour", ExpiresAt: &inAnHour},
+		{ServiceName: service, Note: "already over", ExpiresAt: &expired},
+	} {
+		if err := db.SaveServiceNote(ctx, n); err != nil {
+			t.Fatalf("SaveServiceNote: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name           string
+		at             time.Time
+		includeExpired bool
+		want           []string
+	}{
+		{"in effect now", now.Add(time.Minute), false, []string{"for the next hour", "permanent"}},
+		{"after the hour", now.Add(2 * time.Hour), false, []string{"permanent"}},
+		{"before any was written", now.Add(-time.Hour), false, nil},
+		{"all", now.Add(2 * time.Hour), true, []string{"already over", "for the next hour", "permanent"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notes, err := db.GetServiceNotes(ctx, service, tt.at, tt.includeExpired)
+			if err != nil {
+				t.Fatalf("GetServiceNotes: %v", err)
+			}
+			got := noteTexts(notes)
+			if len(got) != len(tt.want) {
+				t.Fatalf("notes = %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("notes = %q, want %q newest first", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package storage
+
+import "context"
+
+// DefaultMaxSeriesPoints caps the samples returned per series when no other
+// cap is set on the context
+const DefaultMaxSeriesPoints = 1000
+
+type maxPointsKey struct{}
+
+// WithMaxPoints caps the samples returned per series by time-windowed
+// queries on ctx. Longer series are downsampled evenly across the window
+// rather than cut off, so the returned samples still span all of it.
+func WithMaxPoints(ctx context.Context, n int) context.Context {
+	return context.WithValue(ctx, maxPointsKey{}, n)
+}
+
+// MaxPoints returns the per-series sample cap carried by ctx, or
+// DefaultMaxSeriesPoints when none is set
+func MaxPoints(ctx context.Context) int {
+	if n, ok := ctx.Value(maxPointsKey{}).(int); ok && n > 0 {
+		return n
+	}
+	return DefaultMaxSeriesPoints
+}
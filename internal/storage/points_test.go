@@ -0,0 +1,19 @@
+package storage
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMaxPoints(t *testing.T) {
+	ctx := context.Background()
+	if got := MaxPoints(ctx); got != DefaultMaxSeriesPoints {
+		t.Errorf("MaxPoints on a plain context = %d, want %d", got, DefaultMaxSeriesPoints)
+	}
+	if got := MaxPoints(WithMaxPoints(ctx, 250)); got != 250 {
+		t.Errorf("MaxPoints = %d, want 250", got)
+	}
+	if got := MaxPoints(WithMaxPoints(ctx, 0)); got != DefaultMaxSeriesPoints {
+		t.Errorf("MaxPoints with a zero cap = %d, want the default %d", got, DefaultMaxSeriesPoints)
+	}
+}
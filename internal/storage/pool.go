@@ -0,0 +1,196 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Query execution modes, named as in pgx's default_query_exec_mode.
+// Statements are prepared and cached per connection, so a pooled
+// connection never runs a statement another connection prepared. Behind a
+// transaction-pooling proxy such as PgBouncer, where successive queries may
+// reach different server connections, use exec or simple_protocol.
+const (
+	QueryExecCacheStatement = "cache_statement" // prepare each distinct query once per connection (default)
+	QueryExecCacheDescribe  = "cache_describe"  // cache only the parameter and result descriptions
+	QueryExecDescribeExec   = "describe_exec"   // describe every query, cache nothing
+	QueryExecExec           = "exec"            // unnamed statements, no round trip to describe
+	QueryExecSimpleProtocol = "simple_protocol" // client-side parameter interpolation
+)
+
+var queryExecModes = map[string]pgx.QueryExecMode{
+	QueryExecCacheStatement: pgx.QueryExecModeCacheStatement,
+	QueryExecCacheDescribe:  pgx.QueryExecModeCacheDescribe,
+	QueryExecDescribeExec:   pgx.QueryExecModeDescribeExec,
+	QueryExecExec:           pgx.QueryExecModeExec,
+	QueryExecSimpleProtocol: pgx.QueryExecModeSimpleProtocol,
+}
+
+func queryExecModeName(mode pgx.QueryExecMode) string {
+	for name, m := range queryExecModes {
+		if m == mode {
+			return name
+		}
+	}
+	return mode.String()
+}
+
+// PoolOptions sizes the connection pool. Zero fields keep the defaults.
+type PoolOptions struct {
+	MaxConns          int32
+	MinConns          int32
+	MaxConnLifetime   time.Duration
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
+	ConnectTimeout    time.Duration
+
+	// QueryExecMode is one of the QueryExec* modes; StatementCacheCapacity
+	// bounds the statements (or descriptions) cached per connection
+	QueryExecMode          string
+	StatementCacheCapacity int
+}
+
+// DefaultPoolOptions returns the pool settings used when none are configured
+func DefaultPoolOptions() PoolOptions {
+	return PoolOptions{
+		MaxConns:          25,
+		MinConns:          5,
+		MaxConnLifetime:   time.Hour,
+		MaxConnIdleTime:   30 * time.Minute,
+		HealthCheckPeriod: time.Minute,
+		ConnectTimeout:    10 * time.Second,
+
+		QueryExecMode:          QueryExecCacheStatement,
+		StatementCacheCapacity: 512,
+	}
+}
+
+// apply copies the set options onto a parsed pool config
+func (o PoolOptions) apply(config *pgxpool.Config) {
+	defaults := DefaultPoolOptions()
+	pick := func(v, def time.Duration) time.Duration {
+		if v > 0 {
+			return v
+		}
+		return def
+	}
+
+	config.MaxConns = defaults.MaxConns
+	if o.MaxConns > 0 {
+		config.MaxConns = o.MaxConns
+	}
+	config.MinConns = defaults.MinConns
+	if o.MinConns > 0 {
+		config.MinConns = o.MinConns
+	}
+	if config.MinConns > config.MaxConns {
+		config.MinConns = config.MaxConns
+	}
+	config.MaxConnLifetime = pick(o.MaxConnLifetime, defaults.MaxConnLifetime)
+	config.MaxConnIdleTime = pick(o.MaxConnIdleTime, defaults.MaxConnIdleTime)
+	config.HealthCheckPeriod = pick(o.HealthCheckPeriod, defaults.HealthCheckPeriod)
+	config.ConnConfig.ConnectTimeout = pick(o.ConnectTimeout, defaults.ConnectTimeout)
+
+	mode, ok := queryExecModes[o.QueryExecMode]
+	if !ok {
+		mode = queryExecModes[defaults.QueryExecMode]
+	}
+	capacity := defaults.StatementCacheCapacity
+	if o.StatementCacheCapacity > 0 {
+		capacity = o.StatementCacheCapacity
+	}
+	config.ConnConfig.DefaultQueryExecMode = mode
+	config.ConnConfig.StatementCacheCapacity = capacity
+	config.ConnConfig.DescriptionCacheCapacity = capacity
+}
+
+// PoolStats is a snapshot of the connection pool
+type PoolStats struct {
+	MaxConns             int32   `json:"max_conns"`
+	MinConns             int32   `json:"min_conns"`
+	TotalConns           int32   `json:"total_conns"`
+	AcquiredConns        int32   `json:"acquired_conns"`
+	IdleConns            int32   `json:"idle_conns"`
+	ConstructingConns    int32   `json:"constructing_conns"`
+	AcquireCount         int64   `json:"acquire_count"`
+	WaitCount            int64   `json:"wait_count"` // acquires that had to wait for a connection
+	CanceledAcquireCount int64   `json:"canceled_acquire_count"`
+	AcquireDurationMs    float64 `json:"acquire_duration_ms"` // total time spent acquiring
+
+	QueryExecMode          string `json:"query_exec_mode"`
+	StatementCacheCapacity int    `json:"statement_cache_capacity"` // per connection
+}
+
+// GetPoolStats returns the connection pool's current state and counters
+func (c *PostgresClient) GetPoolStats() PoolStats {
+	return poolStats(c.pool)
+}
+
+// GetReplicaPoolStats returns the read replica pool's state, if one is
+// connected
+func (c *PostgresClient) GetReplicaPoolStats() (PoolStats, bool) {
+	if c.replica == nil {
+		return PoolStats{}, false
+	}
+	return poolStats(c.replica), true
+}
+
+func poolStats(pool *pgxpool.Pool) PoolStats {
+	stat := pool.Stat()
+	return PoolStats{
+		MaxConns:             stat.MaxConns(),
+		MinConns:             pool.Config().MinConns,
+		TotalConns:           stat.TotalConns(),
+		AcquiredConns:        stat.AcquiredConns(),
+		IdleConns:            stat.IdleConns(),
+		ConstructingConns:    stat.ConstructingConns(),
+		AcquireCount:         stat.AcquireCount(),
+		WaitCount:            stat.EmptyAcquireCount(),
+		CanceledAcquireCount: stat.CanceledAcquireCount(),
+		AcquireDurationMs:    float64(stat.AcquireDuration()) / float64(time.Millisecond),
+
+		QueryExecMode:          queryExecModeName(pool.Config().ConnConfig.DefaultQueryExecMode),
+		StatementCacheCapacity: pool.Config().ConnConfig.StatementCacheCapacity,
+	}
+}
+
+// PoolCollectors returns Prometheus collectors that read the pool's state
+// on every scrape
+func (c *PostgresClient) PoolCollectors() []prometheus.Collector {
+	gauge := func(name, help string, value func(*pgxpool.Stat) float64) prometheus.Collector {
+		return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: "aura",
+			Subsystem: "db_pool",
+			Name:      name,
+			Help:      help,
+		}, func() float64 { return value(c.pool.Stat()) })
+	}
+	counter := func(name, help string, value func(*pgxpool.Stat) float64) prometheus.Collector {
+		return prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: "aura",
+			Subsystem: "db_pool",
+			Name:      name,
+			Help:      help,
+		}, func() float64 { return value(c.pool.Stat()) })
+	}
+
+	return []prometheus.Collector{
+		gauge("max_conns", "Maximum size of the connection pool.",
+			func(s *pgxpool.Stat) float64 { return float64(s.MaxConns()) }),
+		gauge("total_conns", "Connections currently in the pool.",
+			func(s *pgxpool.Stat) float64 { return float64(s.TotalConns()) }),
+		gauge("acquired_conns", "Connections currently checked out.",
+			func(s *pgxpool.Stat) float64 { return float64(s.AcquiredConns()) }),
+		gauge("idle_conns", "Idle connections in the pool.",
+			func(s *pgxpool.Stat) float64 { return float64(s.IdleConns()) }),
+		counter("acquires_total", "Successful connection acquires.",
+			func(s *pgxpool.Stat) float64 { return float64(s.AcquireCount()) }),
+		counter("waits_total", "Acquires that waited because the pool was empty.",
+			func(s *pgxpool.Stat) float64 { return float64(s.EmptyAcquireCount()) }),
+		counter("acquire_seconds_total", "Total time spent acquiring connections.",
+			func(s *pgxpool.Stat) float64 { return s.AcquireDuration().Seconds() }),
+	}
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func parsedPoolConfig(t *testing.T) *pgxpool.Config {
+	t.Helper()
+
+	config, err := pgxpool.ParseConfig("postgres://aura@localhost:5432/aura")
+	if err != nil {
+		t.Fatalf("ParseConfig: %v", err)
+	}
+	return config
+}
+
+func TestPoolOptionsApply(t *testing.T) {
+	config := parsedPoolConfig(t)
+	PoolOptions{}.apply(config)
+
+	defaults := DefaultPoolOptions()
+	if config.MaxConns != defaults.MaxConns || config.MinConns != defaults.MinConns ||
+		config.MaxConnLifetime != defaults.MaxConnLifetime || config.MaxConnIdleTime != defaults.MaxConnIdleTime ||
+		config.HealthCheckPeriod != defaults.HealthCheckPeriod || config.ConnConfig.ConnectTimeout != defaults.ConnectTimeout {
+		t.Errorf("unset options applied %d/%d conns, lifetime %v, idle %v, health check %v, connect %v; want the defaults",
+			config.MaxConns, config.MinConns, config.MaxConnLifetime, config.MaxConnIdleTime,
+			config.HealthCheckPeriod, config.ConnConfig.ConnectTimeout)
+	}
+
+	config = parsedPoolConfig(t)
+	PoolOptions{
+		MaxConns:          60,
+		MinConns:          10,
+		MaxConnLifetime:   15 * time.Minute,
+		MaxConnIdleTime:   time.Minute,
+		HealthCheckPeriod: 20 * time.Second,
+		ConnectTimeout:    3 * time.Second,
+	}.apply(config)
+	if config.MaxConns != 60 || config.MinConns != 10 ||
+		config.MaxConnLifetime != 15*time.Minute || config.MaxConnIdleTime != time.Minute ||
+		config.HealthCheckPeriod != 20*time.Second || config.ConnConfig.ConnectTimeout != 3*time.Second {
+		t.Errorf("configured options applied %d/%d conns, lifetime %v, idle %v, health check %v, connect %v",
+			config.MaxConns, config.MinConns, config.MaxConnLifetime, config.MaxConnIdleTime,
+			config.HealthCheckPeriod, config.ConnConfig.ConnectTimeout)
+	}
+
+	// A pool smaller than the default minimum keeps min within max
+	config = parsedPoolConfig(t)
+	PoolOptions{MaxConns: 3}.apply(config)
+	if config.MaxConns != 3 || config.MinConns != 3 {
+		t.Errorf("max 3: got %d/%d conns, want min clamped to 3", config.MaxConns, config.MinConns)
+	}
+}
+
+func TestPoolOptionsQueryExecMode(t *testing.T) {
+	config := parsedPoolConfig(t)
+	PoolOptions{}.apply(config)
+	if config.ConnConfig.DefaultQueryExecMode != pgx.QueryExecModeCacheStatement || config.ConnConfig.StatementCacheCapacity != 512 {
+		t.Errorf("unset options: mode %v, cache %d, want cache_statement and 512",
+			config.ConnConfig.DefaultQueryExecMode, config.ConnConfig.StatementCacheCapacity)
+	}
+
+	config = parsedPoolConfig(t)
+	PoolOptions{QueryExecMode: QueryExecSimpleProtocol, StatementCacheCapacity: 64}.apply(config)
+	if config.ConnConfig.DefaultQueryExecMode != pgx.QueryExecModeSimpleProtocol ||
+		config.ConnConfig.StatementCacheCapacity != 64 || config.ConnConfig.DescriptionCacheCapacity != 64 {
+		t.Errorf("configured options: mode %v, caches %d/%d, want simple_protocol and 64",
+			config.ConnConfig.DefaultQueryExecMode, config.ConnConfig.StatementCacheCapacity, config.ConnConfig.DescriptionCacheCapacity)
+	}
+
+	// An unknown mode keeps the default
+	config = parsedPoolConfig(t)
+	PoolOptions{QueryExecMode: "prepared"}.apply(config)
+	if config.ConnConfig.DefaultQueryExecMode != pgx.QueryExecModeCacheStatement {
+		t.Errorf("unknown mode applied %v, want cache_statement", config.ConnConfig.DefaultQueryExecMode)
+	}
+}
+
+func TestQueryExecModeName(t *testing.T) {
+	for name, mode := range queryExecModes {
+		if got := queryExecModeName(mode); got != name {
+			t.Errorf("queryExecModeName(%v) = %s, want %s", mode, got, name)
+		}
+	}
+}
@@ -2,31 +2,52 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/pkg/logger"
 	"go.uber.org/zap"
 )
 
+// ErrNotFound is returned by single-record lookups when no row matches
+var ErrNotFound = errors.New("not found")
+
 type PostgresClient struct {
-	pool   *pgxpool.Pool
-	logger *zap.Logger
+	pool    *pgxpool.Pool
+	replica *pgxpool.Pool // read-only; nil when no replica is configured
+	logger  *zap.Logger
+
+	labelLimits LabelLimits
 }
 
 func NewPostgresClient(connectionURL string, logger *zap.Logger) (*PostgresClient, error) {
+	return NewPostgresClientWithPool(connectionURL, DefaultPoolOptions(), logger)
+}
+
+// NewPostgresClientWithPool connects with the given pool settings
+func NewPostgresClientWithPool(connectionURL string, opts PoolOptions, logger *zap.Logger) (*PostgresClient, error) {
+	pool, err := connectPool(connectionURL, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PostgresClient{
+		pool:   pool,
+		logger: logger,
+	}, nil
+}
+
+// connectPool opens a pool with the given settings and checks it answers
+func connectPool(connectionURL string, opts PoolOptions) (*pgxpool.Pool, error) {
 	config, err := pgxpool.ParseConfig(connectionURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse connection URL: %w", err)
 	}
 
-	config.MaxConns = 25
-	config.MinConns = 5
-	config.MaxConnLifetime = time.Hour
-	config.MaxConnIdleTime = 30 * time.Minute
-	config.HealthCheckPeriod = 1 * time.Minute
-	config.ConnConfig.ConnectTimeout = 10 * time.Second
+	opts.apply(config)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
@@ -40,33 +61,52 @@ func NewPostgresClient(connectionURL string, logger *zap.Logger) (*PostgresClien
 		pool.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
-
-	return &PostgresClient{
-		pool:   pool,
-		logger: logger,
-	}, nil
+	return pool, nil
 }
 
 func (c *PostgresClient) Close() {
 	c.pool.Close()
+	if c.replica != nil {
+		c.replica.Close()
+	}
+}
+
+// log returns the client's logger annotated with the request ID in ctx, so
+// lines logged while serving a request can be correlated with it
+func (c *PostgresClient) log(ctx context.Context) *zap.Logger {
+	if id := logger.RequestID(ctx); id != "" {
+		return c.logger.With(zap.String("request_id", id))
+	}
+	return c.logger
 }
 
+// Health pings the primary and, when one is connected, the read replica
 func (c *PostgresClient) Health(ctx context.Context) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
-	return c.pool.Ping(ctx)
+	if err := c.pool.Ping(ctx); err != nil {
+		return err
+	}
+	if c.replica != nil {
+		if err := c.replica.Ping(ctx); err != nil {
+			return fmt.Errorf("read replica: %w", err)
+		}
+	}
+	return nil
 }
 
 func (c *PostgresClient) SaveMetric(ctx context.Context, metric *Metric) error {
 	query := `
-		INSERT INTO metrics (timestamp, service_name, metric_name, metric_value, labels)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO metrics (timestamp, service_name, metric_name, metric_value, labels, metric_min, metric_max)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING id
 	`
 
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
+	c.trimLabels([]*Metric{metric})
+
 	err := c.pool.QueryRow(
 		ctx,
 		query,
@@ -75,15 +115,21 @@ func (c *PostgresClient) SaveMetric(ctx context.Context, metric *Metric) error {
 		metric.MetricName,
 		metric.MetricValue,
 		metric.Labels,
+		metric.MetricMin,
+		metric.MetricMax,
 	).Scan(&metric.ID)
 
 	if err != nil {
 		return fmt.Errorf("failed to save metric: %w", err)
 	}
 
+	c.touchServices(ctx, []*Metric{metric})
+
 	return nil
 }
 
+// GetRecentMetrics returns a metric's samples in the window, oldest first.
+// Windows holding more than MaxPoints(ctx) samples are downsampled evenly.
 func (c *PostgresClient) GetRecentMetrics(
 	ctx context.Context,
 	serviceName string,
@@ -91,21 +137,30 @@ func (c *PostgresClient) GetRecentMetrics(
 	duration time.Duration,
 ) ([]*Metric, error) {
 	query := `
-		SELECT id, timestamp, service_name, metric_name, metric_value, labels, created_at
-		FROM metrics
-		WHERE service_name = $1
-		  AND metric_name = $2
-		  AND timestamp > $3 
+		SELECT id, timestamp, service_name, metric_name, metric_value, labels, created_at,
+		       metric_min, metric_max
+		FROM (
+			SELECT *,
+			       ROW_NUMBER() OVER (ORDER BY timestamp ASC) AS rn,
+			       COUNT(*) OVER () AS total
+			FROM metrics
+			WHERE service_name = $1
+			  AND metric_name = $2
+			  AND timestamp > $3
+			  AND timestamp <= $4
+		) ranked
+		WHERE (rn - 1) % CAST(CEIL(total::numeric / $5::int) AS bigint) = 0
 		ORDER BY timestamp ASC
-		LIMIT 1000
 	`
 	// what is this timestamp for ? answer is that it is used to get the recent metrics in a duration
 	// we ar ordering
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 	//since := time.Now().Add(-duration) this is getting the time from duration means how, answer is it is getting the time from now and subtracting the duration from it
-	since := time.Now().Add(-duration)                                    //we have added duration here because we are getting recent metrics in a duration
-	rows, err := c.pool.Query(ctx, query, serviceName, metricName, since) // so this are getting the rows from the database on the basis of service name , metric name and since time
+	// The window normally ends now; replays set an earlier end via WithAsOf
+	until := AsOf(ctx)
+	since := until.Add(-duration)                                                                          //we have added duration here because we are getting recent metrics in a duration
+	rows, err := c.queryPool(ctx).Query(ctx, query, serviceName, metricName, since, until, MaxPoints(ctx)) // so this are getting the rows from the database on the basis of service name , metric name and since time
 	if err != nil {
 		return nil, fmt.Errorf("failed to query metrics: %w", err)
 	}
@@ -122,6 +177,8 @@ func (c *PostgresClient) GetRecentMetrics(
 			&m.MetricValue,
 			&m.Labels,
 			&m.CreatedAt,
+			&m.MetricMin,
+			&m.MetricMax,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan metric row: %w", err)
 		}
@@ -135,6 +192,79 @@ func (c *PostgresClient) GetRecentMetrics(
 	return metrics, nil
 }
 
+// GetRecentMetricsMulti fetches several metrics of one service in a single
+// query. Each series keeps the GetRecentMetrics semantics: ascending by
+// timestamp and downsampled to at most MaxPoints(ctx) samples. The second
+// map holds each series' sample count before downsampling. Metrics without
+// samples are absent from both maps.
+func (c *PostgresClient) GetRecentMetricsMulti(
+	ctx context.Context,
+	serviceName string,
+	metricNames []string,
+	duration time.Duration,
+) (map[string][]*Metric, map[string]int, error) {
+	result := make(map[string][]*Metric, len(metricNames))
+	totals := make(map[string]int, len(metricNames))
+	if len(metricNames) == 0 {
+		return result, totals, nil
+	}
+
+	query := `
+		SELECT id, timestamp, service_name, metric_name, metric_value, labels, created_at,
+		       metric_min, metric_max, total
+		FROM (
+			SELECT *,
+			       ROW_NUMBER() OVER (PARTITION BY metric_name ORDER BY timestamp ASC) AS rn,
+			       COUNT(*) OVER (PARTITION BY metric_name) AS total
+			FROM metrics
+			WHERE service_name = $1
+			  AND metric_name = ANY($2)
+			  AND timestamp > $3
+			  AND timestamp <= $4
+		) ranked
+		WHERE (rn - 1) % CAST(CEIL(total::numeric / $5::int) AS bigint) = 0
+		ORDER BY metric_name, timestamp ASC
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	until := AsOf(ctx)
+	since := until.Add(-duration)
+	rows, err := c.queryPool(ctx).Query(ctx, query, serviceName, metricNames, since, until, MaxPoints(ctx))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query metrics: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var m Metric
+		var total int64
+		if err := rows.Scan(
+			&m.ID,
+			&m.Timestamp,
+			&m.ServiceName,
+			&m.MetricName,
+			&m.MetricValue,
+			&m.Labels,
+			&m.CreatedAt,
+			&m.MetricMin,
+			&m.MetricMax,
+			&total,
+		); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan metric row: %w", err)
+		}
+		result[m.MetricName] = append(result[m.MetricName], &m)
+		totals[m.MetricName] = int(total)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating metrics: %w", err)
+	}
+
+	return result, totals, nil
+}
+
 // GetMetricsInRange retrieves metrics within a specific time range
 func (c *PostgresClient) GetMetricsInRange(serviceName, metricName string, startTime, endTime time.Time) ([]MetricRecord, error) {
 	ctx := context.Background()
@@ -152,7 +282,7 @@ func (c *PostgresClient) GetMetricsInRange(serviceName, metricName string, start
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	rows, err := c.pool.Query(ctx, query, serviceName, metricName, startTime, endTime)
+	rows, err := c.queryPool(ctx).Query(ctx, query, serviceName, metricName, startTime, endTime)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query metrics in range: %w", err)
 	}
@@ -176,8 +306,8 @@ func (c *PostgresClient) GetMetricsInRange(serviceName, metricName string, start
 
 func (c *PostgresClient) SaveDecision(ctx context.Context, decision *Decision) error {
 	query := `
-		INSERT INTO decisions (timestamp, pattern_detected, action_type, confidence, reason, parameters, executed)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO decisions (timestamp, pattern_detected, action_type, confidence, reason, parameters, executed, executed_at, execution_result, service_name)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), NULLIF($10, ''))
 		RETURNING id, created_at
 	`
 
@@ -194,6 +324,9 @@ func (c *PostgresClient) SaveDecision(ctx context.Context, decision *Decision) e
 		decision.Reason,
 		decision.Parameters,
 		decision.Executed,
+		decision.ExecutedAt,
+		decision.ExecutionResult,
+		decision.ServiceName,
 	).Scan(&decision.ID, &decision.CreatedAt)
 
 	if err != nil {
@@ -203,6 +336,89 @@ func (c *PostgresClient) SaveDecision(ctx context.Context, decision *Decision) e
 	return nil
 }
 
+// UpdateDecisionExecution records whether a decision was acted on. Marking it
+// executed stamps the execution time; un-marking clears it.
+func (c *PostgresClient) UpdateDecisionExecution(ctx context.Context, id string, executed bool, result string) error {
+	query := `
+		UPDATE decisions
+		SET executed = $2,
+		    executed_at = CASE WHEN $2 THEN NOW() ELSE NULL END,
+		    execution_result = NULLIF($3, '')
+		WHERE id = $1
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tag, err := c.pool.Exec(ctx, query, id, executed, result)
+	if err != nil {
+		return fmt.Errorf("failed to update decision: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// GetUnverifiedDecisions returns executed decisions with no recovery outcome
+// yet that were executed between since and before, oldest first
+func (c *PostgresClient) GetUnverifiedDecisions(ctx context.Context, since, before time.Time, limit int) ([]*Decision, error) {
+	query := `
+		SELECT ` + decisionColumns + `
+		FROM decisions
+		WHERE executed AND outcome IS NULL
+		  AND executed_at > $1 AND executed_at <= $2
+		  AND COALESCE(service_name, parameters->>'service', '') <> ''
+		ORDER BY executed_at ASC
+		LIMIT $3
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := c.queryPool(ctx).Query(ctx, query, since, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query unverified decisions: %w", err)
+	}
+	defer rows.Close()
+
+	var decisions []*Decision
+	for rows.Next() {
+		d, err := scanDecision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan decision: %w", err)
+		}
+		decisions = append(decisions, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating decisions: %w", err)
+	}
+	return decisions, nil
+}
+
+// SetDecisionOutcome records the recovery check of an executed decision
+func (c *PostgresClient) SetDecisionOutcome(ctx context.Context, id int64, outcome string, checkedAt time.Time) error {
+	query := `
+		UPDATE decisions
+		SET outcome = $2, outcome_checked_at = $3
+		WHERE id = $1
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tag, err := c.pool.Exec(ctx, query, id, outcome, checkedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update decision outcome: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
 func (c *PostgresClient) SaveEvent(ctx context.Context, event *Event) error {
 	query := `
 		INSERT INTO events (timestamp, event_type, pod_name, namespace, message)
@@ -224,14 +440,14 @@ func (c *PostgresClient) SaveEvent(ctx context.Context, event *Event) error {
 	).Scan(&event.ID, &event.CreatedAt)
 
 	if err != nil {
-		c.logger.Error("Failed to save Kubernetes event",
+		c.log(ctx).Error("Failed to save Kubernetes event",
 			zap.Error(err),
 			zap.String("event_type", event.EventType),
 			zap.String("pod_name", event.PodName))
 		return fmt.Errorf("failed to save event: %w", err)
 	}
 
-	c.logger.Debug("Saved Kubernetes event",
+	c.log(ctx).Debug("Saved Kubernetes event",
 		zap.Int64("event_id", event.ID),
 		zap.String("event_type", event.EventType),
 		zap.String("pod_name", event.PodName),
@@ -242,13 +458,15 @@ func (c *PostgresClient) SaveEvent(ctx context.Context, event *Event) error {
 
 func (c *PostgresClient) BatchSaveMetrics(ctx context.Context, metrics []*Metric) error {
 	if len(metrics) == 0 {
-		c.logger.Debug("No metrics to save")
+		c.log(ctx).Debug("No metrics to save")
 		return nil
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
+	c.trimLabels(metrics)
+
 	// Build rows for batch insert
 	rows := make([][]any, 0, len(metrics))
 	for _, metric := range metrics {
@@ -258,6 +476,8 @@ func (c *PostgresClient) BatchSaveMetrics(ctx context.Context, metrics []*Metric
 			metric.MetricName,
 			metric.MetricValue,
 			metric.Labels,
+			metric.MetricMin,
+			metric.MetricMax,
 		})
 	}
 
@@ -265,25 +485,23 @@ func (c *PostgresClient) BatchSaveMetrics(ctx context.Context, metrics []*Metric
 	copyCount, err := c.pool.CopyFrom(
 		ctx,
 		pgx.Identifier{"metrics"},
-		[]string{"timestamp", "service_name", "metric_name", "metric_value", "labels"},
+		[]string{"timestamp", "service_name", "metric_name", "metric_value", "labels", "metric_min", "metric_max"},
 		pgx.CopyFromRows(rows),
 	)
 	if err != nil {
-		c.logger.Error("Failed to batch save metrics",
+		c.log(ctx).Error("Failed to batch save metrics",
 			zap.Error(err),
 			zap.Int("attempted_count", len(metrics)))
 		return fmt.Errorf("failed to copy metrics: %w", err)
 	}
 
-	c.logger.Info("Batch saved metrics to database",
+	c.log(ctx).Info("Batch saved metrics to database",
 		zap.Int64("saved_count", copyCount),
 		zap.Int("metrics_count", len(metrics)))
 
-	return nil
-}
+	c.touchServices(ctx, metrics)
 
-func (c *PostgresClient) GetPoolStats() *pgxpool.Stat {
-	return c.pool.Stat()
+	return nil
 }
 
 func (c *PostgresClient) GetLatestMetric(
@@ -292,7 +510,8 @@ func (c *PostgresClient) GetLatestMetric(
 	metricName string,
 ) (*Metric, error) {
 	query := `
-		SELECT id, timestamp, service_name, metric_name, metric_value, labels, created_at
+		SELECT id, timestamp, service_name, metric_name, metric_value, labels, created_at,
+		       metric_min, metric_max
 		FROM metrics
 		WHERE service_name = $1
 		  AND metric_name = $2
@@ -304,7 +523,7 @@ func (c *PostgresClient) GetLatestMetric(
 	defer cancel()
 	//difference between get latest and get recent is that get latest is giving only one latest metric and get recent is giving multiple metrics in a duration
 	var metric Metric
-	err := c.pool.QueryRow(ctx, query, serviceName, metricName).Scan(
+	err := c.queryPool(ctx).QueryRow(ctx, query, serviceName, metricName).Scan(
 		&metric.ID,
 		&metric.Timestamp,
 		&metric.ServiceName,
@@ -312,6 +531,8 @@ func (c *PostgresClient) GetLatestMetric(
 		&metric.MetricValue,
 		&metric.Labels,
 		&metric.CreatedAt,
+		&metric.MetricMin,
+		&metric.MetricMax,
 	)
 
 	if err != nil {
@@ -350,7 +571,7 @@ func (c *PostgresClient) GetMetricStatistics(
 	var stats MetricStats
 	var stddev *float64
 
-	err := c.pool.QueryRow(ctx, query, serviceName, metricName, since).Scan(
+	err := c.queryPool(ctx).QueryRow(ctx, query, serviceName, metricName, since).Scan(
 		&stats.Count,
 		&stats.Avg,
 		&stats.Min,
@@ -378,60 +599,56 @@ func (c *PostgresClient) GetRecentEvents(
 	namespace string,
 	duration time.Duration,
 ) ([]*Event, error) {
-	query := `
-		SELECT id, timestamp, event_type, pod_name, namespace, message, created_at
-		FROM events
-		WHERE namespace = $1
-		  AND timestamp > $2
-		ORDER BY timestamp DESC
-		LIMIT 100
-	`
-
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
-
-	since := time.Now().Add(-duration)
-	rows, err := c.pool.Query(ctx, query, namespace, since)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query events: %w", err)
-	}
-	defer rows.Close()
-
-	var events []*Event
-	for rows.Next() {
-		var e Event
-		if err := rows.Scan(
-			&e.ID,
-			&e.Timestamp,
-			&e.EventType,
-			&e.PodName,
-			&e.Namespace,
-			&e.Message,
-			&e.CreatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("failed to scan event: %w", err)
-		}
-		events = append(events, &e)
-	}
-
-	return events, rows.Err()
+	return c.GetEvents(ctx, EventFilter{
+		Namespace: namespace,
+		Since:     time.Now().Add(-duration),
+		Limit:     100,
+	})
 }
 
 func (c *PostgresClient) GetRecentDecisions(
 	ctx context.Context,
 	limit int,
 ) ([]*Decision, error) {
+	return c.GetDecisions(ctx, DecisionFilter{Limit: limit})
+}
+
+// decisionColumns is the column list scanDecision expects
+const decisionColumns = `id, timestamp, pattern_detected, action_type, confidence, reason, parameters,
+		       executed, executed_at, COALESCE(execution_result, ''), created_at,
+		       COALESCE(service_name, parameters->>'service', ''), COALESCE(outcome, ''), outcome_checked_at`
+
+// GetDecisions returns decisions matching the filter, newest first
+func (c *PostgresClient) GetDecisions(ctx context.Context, filter DecisionFilter) ([]*Decision, error) {
 	query := `
-		SELECT id, timestamp, pattern_detected, action_type, confidence, reason, parameters, executed, created_at
+		SELECT ` + decisionColumns + `
 		FROM decisions
+		WHERE ($1::boolean IS NULL OR executed = $1)
+		  AND ($2 = '' OR action_type = $2)
+		  AND ($3::timestamptz IS NULL OR timestamp > $3)
+		  AND ($4::timestamptz IS NULL OR timestamp <= $4)
+		  AND ($5 = '' OR COALESCE(service_name, parameters->>'service', '') = $5)
 		ORDER BY timestamp DESC
-		LIMIT $1
+		LIMIT $6
 	`
 
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	rows, err := c.pool.Query(ctx, query, limit)
+	var since *time.Time
+	if !filter.Since.IsZero() {
+		since = &filter.Since
+	}
+	var until *time.Time
+	if !filter.Until.IsZero() {
+		until = &filter.Until
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	rows, err := c.queryPool(ctx).Query(ctx, query, filter.Executed, filter.ActionType, since, until, filter.ServiceName, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query decisions: %w", err)
 	}
@@ -439,26 +656,40 @@ func (c *PostgresClient) GetRecentDecisions(
 
 	var decisions []*Decision
 	for rows.Next() {
-		var d Decision
-		if err := rows.Scan(
-			&d.ID,
-			&d.Timestamp,
-			&d.PatternDetected,
-			&d.ActionType,
-			&d.Confidence,
-			&d.Reason,
-			&d.Parameters,
-			&d.Executed,
-			&d.CreatedAt,
-		); err != nil {
+		d, err := scanDecision(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan decision: %w", err)
 		}
-		decisions = append(decisions, &d)
+		decisions = append(decisions, d)
 	}
 
 	return decisions, rows.Err()
 }
 
+func scanDecision(row pgx.Row) (*Decision, error) {
+	var d Decision
+	err := row.Scan(
+		&d.ID,
+		&d.Timestamp,
+		&d.PatternDetected,
+		&d.ActionType,
+		&d.Confidence,
+		&d.Reason,
+		&d.Parameters,
+		&d.Executed,
+		&d.ExecutedAt,
+		&d.ExecutionResult,
+		&d.CreatedAt,
+		&d.ServiceName,
+		&d.Outcome,
+		&d.OutcomeCheckedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
 func (c *PostgresClient) GetDecisionStats(ctx context.Context, duration time.Duration) (*DecisionStats, error) {
 	query := `
 		SELECT 
@@ -476,7 +707,7 @@ func (c *PostgresClient) GetDecisionStats(ctx context.Context, duration time.Dur
 	since := time.Now().Add(-duration)
 	var stats DecisionStats
 
-	err := c.pool.QueryRow(ctx, query, since).Scan(
+	err := c.queryPool(ctx).QueryRow(ctx, query, since).Scan(
 		&stats.Total,
 		&stats.Executed,
 		&stats.Pending,
@@ -508,39 +739,9 @@ func (c *PostgresClient) DeleteOldMetrics(ctx context.Context, olderThan time.Du
 	return result.RowsAffected(), nil
 }
 
-func (c *PostgresClient) GetAllServices(ctx context.Context) ([]string, error) {
-	query := `
-		SELECT DISTINCT service_name
-		FROM metrics
-		WHERE timestamp > $1
-		ORDER BY service_name
-	`
-
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
-
-	since := time.Now().Add(-24 * time.Hour)
-	rows, err := c.pool.Query(ctx, query, since)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query services: %w", err)
-	}
-	defer rows.Close()
-
-	var services []string
-	for rows.Next() {
-		var service string
-		if err := rows.Scan(&service); err != nil {
-			return nil, fmt.Errorf("failed to scan service: %w", err)
-		}
-		services = append(services, service)
-	}
-
-	return services, rows.Err()
-}
-
 func (c *PostgresClient) GetDecisionById(ctx context.Context, id string) (*Decision, error) {
 	query := `
-		SELECT id, timestamp, pattern_detected, action_type, confidence, reason, parameters, executed, created_at
+		SELECT ` + decisionColumns + `
 		FROM decisions
 		WHERE id = $1
 	`
@@ -548,35 +749,38 @@ func (c *PostgresClient) GetDecisionById(ctx context.Context, id string) (*Decis
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	var decision Decision
-	err := c.pool.QueryRow(ctx, query, id).Scan(
-		&decision.ID,
-		&decision.Timestamp,
-		&decision.PatternDetected,
-		&decision.ActionType,
-		&decision.Confidence,
-		&decision.Reason,
-		&decision.Parameters,
-		&decision.Executed,
-		&decision.CreatedAt,
-	)
-
+	decision, err := scanDecision(c.queryPool(ctx).QueryRow(ctx, query, id))
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("decision not found")
+			return nil, ErrNotFound
 		}
 		return nil, fmt.Errorf("failed to get decision: %w", err)
 	}
 
-	return &decision, nil
+	return decision, nil
 }
 
 func (c *PostgresClient) GetPodEvents(ctx context.Context, podName string, duration time.Duration) ([]*Event, error) {
+	return c.GetEvents(ctx, EventFilter{
+		PodName: podName,
+		Since:   time.Now().Add(-duration),
+		Limit:   100,
+	})
+}
+
+// GetServiceEvents returns events of the given types between since and until
+// for the service's pods: those named after it, either exactly or as the
+// service name followed by "-" and the generated suffix, unless a registered
+// service with a longer name owns them. Newest first.
+func (c *PostgresClient) GetServiceEvents(ctx context.Context, serviceName string, eventTypes []string, since, until time.Time) ([]*Event, error) {
 	query := `
 		SELECT id, timestamp, event_type, pod_name, namespace, message, created_at
 		FROM events
-		WHERE pod_name = $1
-		  AND timestamp > $2
+		WHERE (pod_name = $1 OR left(pod_name, length($1) + 1) = $1 || '-')
+		  AND NOT` + podOwnedByLongerService + `
+		  AND event_type = ANY($2)
+		  AND timestamp > $3
+		  AND timestamp <= $4
 		ORDER BY timestamp DESC
 		LIMIT 100
 	`
@@ -584,31 +788,11 @@ func (c *PostgresClient) GetPodEvents(ctx context.Context, podName string, durat
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	since := time.Now().Add(-duration)
-	rows, err := c.pool.Query(ctx, query, podName, since)
+	rows, err := c.queryPool(ctx).Query(ctx, query, serviceName, eventTypes, since, until)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query pod events: %w", err)
+		return nil, fmt.Errorf("failed to query service events: %w", err)
 	}
-	defer rows.Close()
-
-	var events []*Event
-	for rows.Next() {
-		var e Event
-		if err := rows.Scan(
-			&e.ID,
-			&e.Timestamp,
-			&e.EventType,
-			&e.PodName,
-			&e.Namespace,
-			&e.Message,
-			&e.CreatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("failed to scan event: %w", err)
-		}
-		events = append(events, &e)
-	}
-
-	return events, rows.Err()
+	return scanEvents(rows)
 }
 
 /*
@@ -0,0 +1,274 @@
+package storage_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+var multiMetricNames = []string{"cpu_usage", "memory_usage", "error_rate", "latency_p95"}
+
+// seedMultiMetrics saves n samples of each of multiMetricNames, one per
+// 15s, ending a minute ago
+func seedMultiMetrics(tb testing.TB, db *storage.PostgresClient, service string, n int) {
+	tb.Helper()
+
+	end := time.Now().Add(-time.Minute).Truncate(time.Second)
+	for i, name := range multiMetricNames {
+		values := make([]float64, n)
+		for j := range values {
+			values[j] = float64(10*i + j%7)
+		}
+		storagetest.Seed(tb, db, storagetest.Series(service, name, end, 15*time.Second, values...))
+	}
+}
+
+func TestGetRecentMetricsMultiMatchesPerMetric(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+	seedMultiMetrics(t, db, service, 40)
+
+	names := append([]string{"gc_pause"}, multiMetricNames...) // gc_pause has no samples
+	series, totals, err := db.GetRecentMetricsMulti(ctx, service, names, time.Hour)
+	if err != nil {
+		t.Fatalf("GetRecentMetricsMulti: %v", err)
+	}
+	if _, ok := series["gc_pause"]; ok {
+		t.Error("a metric without samples is present in the result")
+	}
+	if len(series) != len(multiMetricNames) {
+		t.Errorf("got %d series, want %d", len(series), len(multiMetricNames))
+	}
+
+	for _, name := range multiMetricNames {
+		want, err := db.GetRecentMetrics(ctx, service, name, time.Hour)
+		if err != nil {
+			t.Fatalf("GetRecentMetrics(%s): %v", name, err)
+		}
+		got := series[name]
+		if len(got) != len(want) {
+			t.Errorf("%s: %d samples, want %d from the per-metric query", name, len(got), len(want))
+			continue
+		}
+		if totals[name] != len(want) {
+			t.Errorf("%s: total %d, want %d", name, totals[name], len(want))
+		}
+		for i := range want {
+			if !got[i].Timestamp.Equal(want[i].Timestamp) || got[i].MetricValue != want[i].MetricValue || got[i].MetricName != name {
+				t.Errorf("%s[%d] = %s %v at %v, want %v at %v", name, i,
+					got[i].MetricName, got[i].MetricValue, got[i].Timestamp, want[i].MetricValue, want[i].Timestamp)
+				break
+			}
+		}
+	}
+}
+
+func TestGetRecentMetricsDownsamplesAcrossWindow(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+
+	const n = 100
+	end := time.Now().Add(-time.Minute).Truncate(time.Second)
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = float64(i)
+	}
+	storagetest.Seed(t, db, storagetest.Series(service, "cpu_usage", end, 15*time.Second, values...))
+
+	ctx := storage.WithMaxPoints(context.Background(), 10)
+	metrics, err := db.GetRecentMetrics(ctx, service, "cpu_usage", time.Hour)
+	if err != nil {
+		t.Fatalf("GetRecentMetrics: %v", err)
+	}
+	if len(metrics) == 0 || len(metrics) > 10 {
+		t.Fatalf("got %d samples, want at most the 10-point cap", len(metrics))
+	}
+	// Thinned evenly, not cut off: the oldest sample is kept and the kept
+	// samples reach the end of the window
+	if metrics[0].MetricValue != 0 {
+		t.Errorf("first sample = %v, want the oldest (0)", metrics[0].MetricValue)
+	}
+	if last := metrics[len(metrics)-1].MetricValue; last < n-n/10 {
+		t.Errorf("last sample = %v, want one from the newest tenth of the window", last)
+	}
+	for i := 1; i < len(metrics); i++ {
+		if !metrics[i].Timestamp.After(metrics[i-1].Timestamp) {
+			t.Fatalf("samples out of order at %d", i)
+		}
+	}
+
+	series, totals, err := db.GetRecentMetricsMulti(ctx, service, []string{"cpu_usage"}, time.Hour)
+	if err != nil {
+		t.Fatalf("GetRecentMetricsMulti: %v", err)
+	}
+	if len(series["cpu_usage"]) != len(metrics) || totals["cpu_usage"] != n {
+		t.Errorf("multi = %d samples of %d, want %d of %d", len(series["cpu_usage"]), totals["cpu_usage"], len(metrics), n)
+	}
+}
+
+// BenchmarkFeatureSeriesFetch compares fetching the feature extractor's
+// series in one query with one query per metric
+func BenchmarkFeatureSeriesFetch(b *testing.B) {
+	db := storagetest.NewClient(b)
+	service := storagetest.Service(b, db)
+	ctx := context.Background()
+	seedMultiMetrics(b, db, service, 120)
+
+	b.Run("multi", func(b *testing.B) {
+		for b.Loop() {
+			if _, _, err := db.GetRecentMetricsMulti(ctx, service, multiMetricNames, time.Hour); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("per-metric", func(b *testing.B) {
+		for b.Loop() {
+			for _, name := range multiMetricNames {
+				if _, err := db.GetRecentMetrics(ctx, service, name, time.Hour); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
+
+func TestConfiguredPoolSizesApplied(t *testing.T) {
+	db, err := storage.NewPostgresClientWithPool(storagetest.URL(t), storage.PoolOptions{MaxConns: 7, MinConns: 2}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewPostgresClientWithPool: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.Health(context.Background()); err != nil {
+		t.Fatalf("Health: %v", err)
+	}
+	stats := db.GetPoolStats()
+	if stats.MaxConns != 7 || stats.MinConns != 2 {
+		t.Errorf("pool stats max %d, min %d, want 7 and 2", stats.MaxConns, stats.MinConns)
+	}
+	if stats.AcquireCount == 0 || stats.TotalConns == 0 {
+		t.Errorf("pool stats %+v, want the health check's acquire counted", stats)
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(db.PoolCollectors()...)
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() == "aura_db_pool_max_conns" {
+			if got := family.GetMetric()[0].GetGauge().GetValue(); got != 7 {
+				t.Errorf("aura_db_pool_max_conns = %v, want 7", got)
+			}
+			return
+		}
+	}
+	t.Error("aura_db_pool_max_conns not registered")
+}
+
+func TestSavedMetricLabelsAreTrimmed(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	db.SetLabelLimits(storage.LabelLimits{MaxKeys: 2, Allowed: []string{"pod", "namespace", "node"}})
+	ctx := context.Background()
+
+	at := time.Now().Add(-time.Minute).Truncate(time.Second)
+	labels := json.RawMessage(`{"pod":"api-1","namespace":"prod","node":"n1","request_id":"8f3a"}`)
+	if err := db.BatchSaveMetrics(ctx, []*storage.Metric{
+		{Timestamp: at, ServiceName: service, MetricName: "cpu_usage", MetricValue: 40, Labels: labels},
+	}); err != nil {
+		t.Fatalf("BatchSaveMetrics: %v", err)
+	}
+
+	stored, err := db.GetLatestMetric(ctx, service, "cpu_usage")
+	if err != nil {
+		t.Fatalf("GetLatestMetric: %v", err)
+	}
+	var got map[string]string
+	if err := json.Unmarshal(stored.Labels, &got); err != nil {
+		t.Fatalf("stored labels %s: %v", stored.Labels, err)
+	}
+	if len(got) != 2 || got["namespace"] != "prod" || got["node"] != "n1" {
+		t.Errorf("stored labels = %v, want the 2 first allowed keys: namespace and node", got)
+	}
+}
+
+// latestMetricClient connects with the given query execution mode and seeds
+// a service to read back
+func latestMetricClient(tb testing.TB, mode string) (*storage.PostgresClient, string) {
+	tb.Helper()
+
+	db, err := storage.NewPostgresClientWithPool(storagetest.URL(tb), storage.PoolOptions{MaxConns: 4, QueryExecMode: mode}, zap.NewNop())
+	if err != nil {
+		tb.Fatalf("NewPostgresClientWithPool: %v", err)
+	}
+	tb.Cleanup(db.Close)
+	service := storagetest.Service(tb, db)
+	seedMultiMetrics(tb, db, service, 60)
+	return db, service
+}
+
+func TestGetLatestMetricAcrossExecModes(t *testing.T) {
+	modes := []string{
+		storage.QueryExecCacheStatement, storage.QueryExecCacheDescribe, storage.QueryExecDescribeExec,
+		storage.QueryExecExec, storage.QueryExecSimpleProtocol,
+	}
+	for _, mode := range modes {
+		t.Run(mode, func(t *testing.T) {
+			db, service := latestMetricClient(t, mode)
+			if got := db.GetPoolStats().QueryExecMode; got != mode {
+				t.Errorf("pool stats query_exec_mode = %s, want %s", got, mode)
+			}
+
+			// More readers than connections, so each connection prepares
+			// and reuses its own statements
+			errs := make(chan error, 16)
+			for range 16 {
+				go func() {
+					for range 10 {
+						m, err := db.GetLatestMetric(context.Background(), service, "memory_usage")
+						if err == nil && m.ServiceName != service {
+							err = fmt.Errorf("read %s's metric", m.ServiceName)
+						}
+						if err != nil {
+							errs <- err
+							return
+						}
+					}
+					errs <- nil
+				}()
+			}
+			for range 16 {
+				if err := <-errs; err != nil {
+					t.Errorf("GetLatestMetric: %v", err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkGetLatestMetric compares repeated GetLatestMetric calls on a pool
+// that parses every query (exec, as with statement caching off) with the
+// default pool that prepares it once per connection (cache_statement)
+func BenchmarkGetLatestMetric(b *testing.B) {
+	for _, mode := range []string{storage.QueryExecExec, storage.QueryExecCacheStatement} {
+		b.Run(mode, func(b *testing.B) {
+			db, service := latestMetricClient(b, mode)
+			ctx := context.Background()
+			for b.Loop() {
+				if _, err := db.GetLatestMetric(ctx, service, "memory_usage"); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
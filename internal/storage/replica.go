@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type primaryKey struct{}
+
+// WithPrimary sends the reads made with ctx to the primary even when a read
+// replica is connected, for reads that must see a write just made
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// ConnectReadReplica opens a read-only pool on a replica of the primary.
+// Read queries go to it from then on; writes stay on the primary. Call it
+// before the client is shared.
+func (c *PostgresClient) ConnectReadReplica(connectionURL string, opts PoolOptions) error {
+	pool, err := connectPool(connectionURL, opts)
+	if err != nil {
+		return err
+	}
+	c.replica = pool
+	return nil
+}
+
+// HasReadReplica reports whether read queries go to a replica
+func (c *PostgresClient) HasReadReplica() bool {
+	return c.replica != nil
+}
+
+// queryPool picks the pool for a read query: the replica when one is
+// connected, unless ctx asks for the primary. Replica reads may lag the
+// primary by the replication delay.
+func (c *PostgresClient) queryPool(ctx context.Context) *pgxpool.Pool {
+	if c.replica == nil {
+		return c.pool
+	}
+	if primary, _ := ctx.Value(primaryKey{}).(bool); primary {
+		return c.pool
+	}
+	return c.replica
+}
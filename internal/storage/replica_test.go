@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// unreachablePool returns a pool on a port nothing listens on. Pools connect
+// lazily, so every query fails with an error naming the pool's database.
+func unreachablePool(t *testing.T, database string) *pgxpool.Pool {
+	t.Helper()
+
+	config, err := pgxpool.ParseConfig("postgres://aura@127.0.0.1:1/" + database + "?connect_timeout=1")
+	if err != nil {
+		t.Fatalf("ParseConfig: %v", err)
+	}
+	pool, err := pgxpool.NewWithConfig(context.Background(), config)
+	if err != nil {
+		t.Fatalf("NewWithConfig: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func TestQueryPool(t *testing.T) {
+	primary, replica := unreachablePool(t, "primary"), unreachablePool(t, "replica")
+	ctx := context.Background()
+
+	single := &PostgresClient{pool: primary, logger: zap.NewNop()}
+	if single.queryPool(ctx) != primary {
+		t.Error("without a replica, reads don't use the primary")
+	}
+	if single.HasReadReplica() {
+		t.Error("HasReadReplica without a replica")
+	}
+
+	replicated := &PostgresClient{pool: primary, replica: replica, logger: zap.NewNop()}
+	if replicated.queryPool(ctx) != replica {
+		t.Error("with a replica, reads don't use it")
+	}
+	if replicated.queryPool(WithPrimary(ctx)) != primary {
+		t.Error("WithPrimary reads don't use the primary")
+	}
+}
+
+func TestReadsAndWritesUseTheirPools(t *testing.T) {
+	db := &PostgresClient{
+		pool:    unreachablePool(t, "primary"),
+		replica: unreachablePool(t, "replica"),
+		logger:  zap.NewNop(),
+	}
+	ctx := context.Background()
+
+	// Each error names the database of the pool the call went to
+	tests := []struct {
+		name string
+		call func() error
+		want string
+	}{
+		{"health history read", func() error {
+			_, err := db.GetHealthHistory(ctx, "checkout", time.Hour)
+			return err
+		}, "replica"},
+		{"incident count read", func() error {
+			_, err := db.CountIncidents(ctx, IncidentOpen)
+			return err
+		}, "replica"},
+		{"read on the primary", func() error {
+			_, err := db.GetHealthHistory(WithPrimary(ctx), "checkout", time.Hour)
+			return err
+		}, "primary"},
+		{"write", func() error {
+			return db.SaveHealthPoint(ctx, "checkout", HealthPoint{Timestamp: time.Now(), HealthScore: 90})
+		}, "primary"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if err == nil {
+				t.Fatal("call succeeded against an unreachable pool")
+			}
+			if !strings.Contains(err.Error(), "database="+tt.want) {
+				t.Errorf("error %q, want it from the %s pool", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetReplicaPoolStats(t *testing.T) {
+	db := &PostgresClient{pool: unreachablePool(t, "primary"), logger: zap.NewNop()}
+	if _, ok := db.GetReplicaPoolStats(); ok {
+		t.Error("replica stats reported without a replica")
+	}
+
+	db.replica = unreachablePool(t, "replica")
+	stats, ok := db.GetReplicaPoolStats()
+	if !ok {
+		t.Fatal("no replica stats with a replica")
+	}
+	if stats.MaxConns != db.replica.Stat().MaxConns() {
+		t.Errorf("replica MaxConns = %d, want %d", stats.MaxConns, db.replica.Stat().MaxConns())
+	}
+}
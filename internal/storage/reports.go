@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// DailyReport is a persisted summary of one UTC day
+type DailyReport struct {
+	Date        time.Time       `json:"date"`
+	GeneratedAt time.Time       `json:"generated_at"`
+	Report      json.RawMessage `json:"report"`
+}
+
+// SaveDailyReport stores the day's report, replacing any earlier one;
+// report is marshalled to JSON as-is
+func (c *PostgresClient) SaveDailyReport(ctx context.Context, r *DailyReport, report interface{}) error {
+	query := `
+		INSERT INTO daily_reports (report_date, generated_at, report)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (report_date) DO UPDATE SET
+			generated_at = EXCLUDED.generated_at,
+			report = EXCLUDED.report
+	`
+
+	reportJSON, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal daily report: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := c.pool.Exec(ctx, query, r.Date, r.GeneratedAt, reportJSON); err != nil {
+		return fmt.Errorf("failed to save daily report: %w", err)
+	}
+
+	r.Report = reportJSON
+	return nil
+}
+
+// GetDailyReport returns the stored report for the day, or ErrNotFound
+func (c *PostgresClient) GetDailyReport(ctx context.Context, date time.Time) (*DailyReport, error) {
+	query := `
+		SELECT report_date, generated_at, report
+		FROM daily_reports
+		WHERE report_date = $1
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var r DailyReport
+	err := c.queryPool(ctx).QueryRow(ctx, query, date).Scan(&r.Date, &r.GeneratedAt, &r.Report)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query daily report: %w", err)
+	}
+	return &r, nil
+}
@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"time"
+)
+
+// seriesBucket identifies the samples of one series within one write
+// resolution bucket
+type seriesBucket struct {
+	service string
+	metric  string
+	labels  string
+	start   time.Time
+}
+
+// aggregateMetrics averages the samples of each series (service, metric and
+// labels) that fall in the same resolution bucket into one row stamped with
+// the bucket's start, keeping the samples' range in MetricMin and MetricMax.
+// Buckets that end after cutoff may still receive samples; their samples
+// are returned unaggregated in open. A zero cutoff aggregates every bucket.
+func aggregateMetrics(metrics []*Metric, resolution time.Duration, cutoff time.Time) (aggregated, open []*Metric) {
+	type bucket struct {
+		row   *Metric
+		sum   float64
+		count int
+	}
+
+	buckets := make(map[seriesBucket]*bucket)
+	for _, m := range metrics {
+		start := m.Timestamp.Truncate(resolution)
+		if !cutoff.IsZero() && start.Add(resolution).After(cutoff) {
+			open = append(open, m)
+			continue
+		}
+
+		key := seriesBucket{m.ServiceName, m.MetricName, string(m.Labels), start}
+		b, ok := buckets[key]
+		if !ok {
+			low, high := m.MetricValue, m.MetricValue
+			b = &bucket{row: &Metric{
+				Timestamp:   start,
+				ServiceName: m.ServiceName,
+				MetricName:  m.MetricName,
+				Labels:      m.Labels,
+				MetricMin:   &low,
+				MetricMax:   &high,
+			}}
+			buckets[key] = b
+			aggregated = append(aggregated, b.row)
+		}
+		b.sum += m.MetricValue
+		b.count++
+		if m.MetricValue < *b.row.MetricMin {
+			*b.row.MetricMin = m.MetricValue
+		}
+		if m.MetricValue > *b.row.MetricMax {
+			*b.row.MetricMax = m.MetricValue
+		}
+	}
+
+	for _, b := range buckets {
+		b.row.MetricValue = b.sum / float64(b.count)
+	}
+	return aggregated, open
+}
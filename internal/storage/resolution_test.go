@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+var resolutionEpoch = time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+func resolutionSample(service, metric string, offset time.Duration, value float64, labels string) *Metric {
+	m := &Metric{
+		Timestamp:   resolutionEpoch.Add(offset),
+		ServiceName: service,
+		MetricName:  metric,
+		MetricValue: value,
+	}
+	if labels != "" {
+		m.Labels = json.RawMessage(labels)
+	}
+	return m
+}
+
+func TestAggregateMetricsAveragesPerBucket(t *testing.T) {
+	metrics := []*Metric{
+		// First 30s bucket of checkout's cpu: 5s samples
+		resolutionSample("checkout", "cpu_usage", 0, 10, ""),
+		resolutionSample("checkout", "cpu_usage", 5*time.Second, 40, ""),
+		resolutionSample("checkout", "cpu_usage", 25*time.Second, 25, ""),
+		// Second bucket
+		resolutionSample("checkout", "cpu_usage", 30*time.Second, 50, ""),
+		resolutionSample("checkout", "cpu_usage", 55*time.Second, 70, ""),
+		// Same bucket, other series
+		resolutionSample("checkout", "memory_usage", 10*time.Second, 60, ""),
+		resolutionSample("search", "cpu_usage", 10*time.Second, 5, ""),
+		resolutionSample("checkout", "cpu_usage", 10*time.Second, 90, `{"pod":"checkout-1"}`),
+	}
+
+	aggregated, open := aggregateMetrics(metrics, 30*time.Second, time.Time{})
+
+	if len(open) != 0 {
+		t.Errorf("got %d open samples with a zero cutoff, want none", len(open))
+	}
+	want := []struct {
+		service, metric, labels string
+		start                   time.Duration
+		mean, min, max          float64
+	}{
+		{"checkout", "cpu_usage", "", 0, 25, 10, 40},
+		{"checkout", "cpu_usage", "", 30 * time.Second, 60, 50, 70},
+		{"checkout", "memory_usage", "", 0, 60, 60, 60},
+		{"search", "cpu_usage", "", 0, 5, 5, 5},
+		{"checkout", "cpu_usage", `{"pod":"checkout-1"}`, 0, 90, 90, 90},
+	}
+	if len(aggregated) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(aggregated), len(want))
+	}
+	for i, w := range want {
+		row := aggregated[i]
+		if row.ServiceName != w.service || row.MetricName != w.metric || string(row.Labels) != w.labels {
+			t.Errorf("row %d is %s/%s %s, want %s/%s %s", i, row.ServiceName, row.MetricName, row.Labels, w.service, w.metric, w.labels)
+			continue
+		}
+		if !row.Timestamp.Equal(resolutionEpoch.Add(w.start)) {
+			t.Errorf("row %d stamped %v, want the bucket start %v", i, row.Timestamp, resolutionEpoch.Add(w.start))
+		}
+		if row.MetricValue != w.mean {
+			t.Errorf("row %d mean = %v, want %v", i, row.MetricValue, w.mean)
+		}
+		if row.MetricMin == nil || row.MetricMax == nil || *row.MetricMin != w.min || *row.MetricMax != w.max {
+			t.Errorf("row %d min/max = %v/%v, want %v/%v", i, row.MetricMin, row.MetricMax, w.min, w.max)
+		}
+	}
+}
+
+func TestAggregateMetricsHoldsOpenBuckets(t *testing.T) {
+	metrics := []*Metric{
+		resolutionSample("checkout", "cpu_usage", 0, 10, ""),
+		resolutionSample("checkout", "cpu_usage", 20*time.Second, 20, ""),
+		resolutionSample("checkout", "cpu_usage", 35*time.Second, 30, ""),
+		resolutionSample("checkout", "cpu_usage", 50*time.Second, 40, ""),
+	}
+
+	// The second bucket, [30s, 60s), hasn't ended at 45s
+	aggregated, open := aggregateMetrics(metrics, 30*time.Second, resolutionEpoch.Add(45*time.Second))
+
+	if len(aggregated) != 1 || aggregated[0].MetricValue != 15 {
+		t.Errorf("aggregated = %v, want the first bucket only, mean 15", aggregated)
+	}
+	if len(open) != 2 || open[0] != metrics[2] || open[1] != metrics[3] {
+		t.Errorf("open = %v, want the second bucket's raw samples", open)
+	}
+
+	// A bucket ending exactly at the cutoff is complete
+	if aggregated, open := aggregateMetrics(metrics, 30*time.Second, resolutionEpoch.Add(time.Minute)); len(aggregated) != 2 || len(open) != 0 {
+		t.Errorf("at the bucket end: %d rows and %d open, want 2 and 0", len(aggregated), len(open))
+	}
+}
+
+func TestMetricBufferHoldsOpenBuckets(t *testing.T) {
+	buffer := NewMetricBuffer(nil, 10, 100, time.Minute)
+	buffer.SetWriteResolution(time.Minute)
+
+	now := time.Now()
+	if err := buffer.Add([]*Metric{{Timestamp: now, ServiceName: "checkout", MetricName: "cpu_usage", MetricValue: 1}}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	// The sample's bucket ends after now, so nothing is written (the buffer
+	// has no database to write to) and the sample stays pending
+	buffer.flush(context.Background(), now)
+
+	if stats := buffer.Stats(); stats.Pending != 1 || stats.Written != 0 || stats.Failed != 0 {
+		t.Errorf("stats = %+v, want the sample held back", stats)
+	}
+}
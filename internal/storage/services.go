@@ -0,0 +1,362 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// ServiceRecord is an entry in the services registry
+type ServiceRecord struct {
+	Name      string    `json:"name"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// GroupLabel is the metric label that assigns a service to a group
+const GroupLabel = "group"
+
+// PodMetricNames are the metrics the observer stores under the pod name
+// rather than a service name
+var PodMetricNames = []string{"pod_status", "pod_restarts", "pod_cpu_usage", "pod_memory_usage"}
+
+// IsPodMetric reports whether the metric is keyed by pod name
+func IsPodMetric(name string) bool {
+	return slices.Contains(PodMetricNames, name)
+}
+
+// touchServices upserts the registry with the newest sample time per service,
+// and its group when a sample carries the group label. It is called from the
+// metric write path so the registry never needs a scan. Pod metrics are
+// skipped, as every rollout would otherwise register its new pods.
+func (c *PostgresClient) touchServices(ctx context.Context, metrics []*Metric) {
+	latest := make(map[string]time.Time)
+	groups := make(map[string]string)
+	for _, m := range metrics {
+		if m.ServiceName == "" || IsPodMetric(m.MetricName) {
+			continue
+		}
+		if ts, ok := latest[m.ServiceName]; !ok || m.Timestamp.After(ts) {
+			latest[m.ServiceName] = m.Timestamp
+		}
+		if group := groupLabel(m.Labels); group != "" {
+			groups[m.ServiceName] = group
+		}
+	}
+	if len(latest) == 0 {
+		return
+	}
+
+	query := `
+		INSERT INTO services (service_name, first_seen, last_seen, service_group)
+		VALUES ($1, $2, $2, NULLIF($3, ''))
+		ON CONFLICT (service_name) DO UPDATE
+		SET last_seen = GREATEST(services.last_seen, EXCLUDED.last_seen),
+		    service_group = COALESCE(EXCLUDED.service_group, services.service_group)
+	`
+
+	batch := &pgx.Batch{}
+	for name, ts := range latest {
+		batch.Queue(query, name, ts, groups[name])
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// The metrics are already stored; a registry miss only delays discovery
+	if err := c.pool.SendBatch(ctx, batch).Close(); err != nil {
+		c.log(ctx).Warn("Failed to update services registry",
+			zap.Int("services", len(latest)),
+			zap.Error(err))
+	}
+}
+
+// groupLabel returns the group label of a sample, or "" when it has none
+func groupLabel(labels json.RawMessage) string {
+	// Most samples carry no group; skip decoding those
+	if !bytes.Contains(labels, []byte(`"`+GroupLabel+`"`)) {
+		return ""
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(labels, &decoded); err != nil {
+		return ""
+	}
+	group, _ := decoded[GroupLabel].(string)
+	return group
+}
+
+// GetServiceLastSeen returns the newest sample time recorded for a service,
+// or the zero time when the service isn't registered
+func (c *PostgresClient) GetServiceLastSeen(ctx context.Context, service string) (time.Time, error) {
+	query := `SELECT last_seen FROM services WHERE service_name = $1`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var lastSeen time.Time
+	if err := c.queryPool(ctx).QueryRow(ctx, query, service).Scan(&lastSeen); err != nil {
+		if err == pgx.ErrNoRows {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("failed to get service last seen: %w", err)
+	}
+	return lastSeen, nil
+}
+
+// GetServicesByGroup returns the registered services whose metrics were last
+// labelled with the group, ordered by name
+func (c *PostgresClient) GetServicesByGroup(ctx context.Context, group string) ([]string, error) {
+	query := `
+		SELECT service_name
+		FROM services
+		WHERE service_group = $1
+		ORDER BY service_name
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := c.queryPool(ctx).Query(ctx, query, group)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query services by group: %w", err)
+	}
+	defer rows.Close()
+
+	var services []string
+	for rows.Next() {
+		var service string
+		if err := rows.Scan(&service); err != nil {
+			return nil, fmt.Errorf("failed to scan service: %w", err)
+		}
+		services = append(services, service)
+	}
+
+	return services, rows.Err()
+}
+
+// GetAllServices returns registered services ordered by name. A positive
+// activeWithin restricts the result to services that reported since then.
+func (c *PostgresClient) GetAllServices(ctx context.Context, activeWithin time.Duration) ([]string, error) {
+	query := `
+		SELECT service_name
+		FROM services
+		WHERE $1::timestamptz IS NULL OR last_seen > $1
+		ORDER BY service_name
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var since *time.Time
+	if activeWithin > 0 {
+		t := time.Now().Add(-activeWithin)
+		since = &t
+	}
+
+	rows, err := c.queryPool(ctx).Query(ctx, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query services: %w", err)
+	}
+	defer rows.Close()
+
+	var services []string
+	for rows.Next() {
+		var service string
+		if err := rows.Scan(&service); err != nil {
+			return nil, fmt.Errorf("failed to scan service: %w", err)
+		}
+		services = append(services, service)
+	}
+
+	return services, rows.Err()
+}
+
+// GetActiveServicesFromMetrics scans the metrics table for services that
+// reported within the duration. It is slow on large tables and only meant
+// for backfilling the registry.
+func (c *PostgresClient) GetActiveServicesFromMetrics(ctx context.Context, duration time.Duration) ([]string, error) {
+	query := `
+		SELECT DISTINCT service_name
+		FROM metrics
+		WHERE timestamp > $1
+		ORDER BY service_name
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	since := time.Now().Add(-duration)
+	rows, err := c.queryPool(ctx).Query(ctx, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query services: %w", err)
+	}
+	defer rows.Close()
+
+	var services []string
+	for rows.Next() {
+		var service string
+		if err := rows.Scan(&service); err != nil {
+			return nil, fmt.Errorf("failed to scan service: %w", err)
+		}
+		services = append(services, service)
+	}
+
+	return services, rows.Err()
+}
+
+// GetServiceRecords returns registry entries ordered by name. A positive
+// seenWithin restricts the result to services that reported since then.
+func (c *PostgresClient) GetServiceRecords(ctx context.Context, seenWithin time.Duration) ([]*ServiceRecord, error) {
+	query := `
+		SELECT service_name, first_seen, last_seen
+		FROM services
+		WHERE $1::timestamptz IS NULL OR last_seen > $1
+		ORDER BY service_name
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var since *time.Time
+	if seenWithin > 0 {
+		t := time.Now().Add(-seenWithin)
+		since = &t
+	}
+
+	rows, err := c.queryPool(ctx).Query(ctx, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query services: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*ServiceRecord
+	for rows.Next() {
+		r := &ServiceRecord{}
+		if err := rows.Scan(&r.Name, &r.FirstSeen, &r.LastSeen); err != nil {
+			return nil, fmt.Errorf("failed to scan service: %w", err)
+		}
+		records = append(records, r)
+	}
+
+	return records, rows.Err()
+}
+
+// GetTopServicesByMetric ranks services by their mean value of any of the
+// given metrics over the duration, highest first, excluding one service
+func (c *PostgresClient) GetTopServicesByMetric(ctx context.Context, metricNames []string, duration time.Duration, limit int, exclude string) ([]string, error) {
+	query := `
+		SELECT service_name
+		FROM metrics
+		WHERE metric_name = ANY($1)
+		  AND timestamp > $2
+		  AND timestamp <= $3
+		  AND service_name <> $4
+		GROUP BY service_name
+		ORDER BY AVG(metric_value) DESC
+		LIMIT $5
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	until := AsOf(ctx)
+	rows, err := c.queryPool(ctx).Query(ctx, query, metricNames, until.Add(-duration), until, exclude, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rank services: %w", err)
+	}
+	defer rows.Close()
+
+	var services []string
+	for rows.Next() {
+		var service string
+		if err := rows.Scan(&service); err != nil {
+			return nil, fmt.Errorf("failed to scan service: %w", err)
+		}
+		services = append(services, service)
+	}
+
+	return services, rows.Err()
+}
+
+// purgeTables are the tables holding per-service data, keyed by the column
+// that names the service. Decisions written before their service_name column
+// existed name the service in their parameters, as the decision reads expect.
+// Events have no service column: the service-level ones (e.g. Anomaly) record
+// the service as the pod, and pod events name a pod of the service,
+// "<service>-<suffix>", so they're matched by prefix, skipping pods owned by
+// another registered service.
+var purgeTables = []struct {
+	table     string
+	column    string
+	podPrefix bool
+}{
+	{"metrics", "service_name", false},
+	{"events", "pod_name", true},
+	{"diagnoses", "service_name", false},
+	{"ultimate_diagnoses", "service_name", false},
+	{"decisions", "COALESCE(service_name, parameters->>'service', '')", false},
+	{"incidents", "service_name", false},
+	{"deployments", "service_name", false},
+	{"status_transitions", "service_name", false},
+	{"feature_snapshots", "service_name", false},
+	{"health_history", "service_name", false},
+	{"service_notes", "service_name", false},
+	{"services", "service_name", false},
+}
+
+// podOwnedByLongerService matches rows whose pod belongs to a registered
+// service with a longer name, such as the pods of api-gateway when reading
+// or purging api's events. A pod is owned by the longest service name it
+// starts with.
+const podOwnedByLongerService = `
+	EXISTS (
+		SELECT 1 FROM services s
+		WHERE length(s.service_name) > length($1)
+		  AND (pod_name = s.service_name OR left(pod_name, length(s.service_name) + 1) = s.service_name || '-')
+	)`
+
+// PurgeService deletes everything stored about a service in one transaction
+// and returns the rows removed per table. On any error nothing is deleted.
+func (c *PostgresClient) PurgeService(ctx context.Context, serviceName string) (map[string]int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	tx, err := c.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin purge: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }() // no-op after Commit
+
+	removed := make(map[string]int64, len(purgeTables))
+	for _, t := range purgeTables {
+		query := `DELETE FROM ` + t.table + ` WHERE ` + t.column + ` = $1`
+		args := []any{serviceName}
+		if t.podPrefix {
+			query += ` OR (` + t.column + ` LIKE $2 AND NOT` + podOwnedByLongerService + `)`
+			args = append(args, escapeLike(serviceName)+"-%")
+		}
+		result, err := tx.Exec(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to purge %s: %w", t.table, err)
+		}
+		removed[t.table] = result.RowsAffected()
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit purge: %w", err)
+	}
+	return removed, nil
+}
+
+// escapeLike escapes the LIKE wildcards in s, so purging "a_b" leaves the
+// pods of "axb" alone
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
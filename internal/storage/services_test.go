@@ -0,0 +1,320 @@
+package storage_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+)
+
+func TestBatchSaveMetricsRegistersService(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+
+	end := time.Now().Add(-time.Minute).Truncate(time.Second)
+	storagetest.Seed(t, db, storagetest.Series(service, "cpu_usage", end, 15*time.Second, 40, 42, 41))
+
+	lastSeen, err := db.GetServiceLastSeen(ctx, service)
+	if err != nil {
+		t.Fatalf("GetServiceLastSeen: %v", err)
+	}
+	if !lastSeen.Equal(end) {
+		t.Errorf("last_seen = %v, want the newest sample time %v", lastSeen, end)
+	}
+
+	services, err := db.GetAllServices(ctx, time.Hour)
+	if err != nil {
+		t.Fatalf("GetAllServices: %v", err)
+	}
+	if !slices.Contains(services, service) {
+		t.Errorf("GetAllServices = %v, want it to include %s", services, service)
+	}
+
+	// A late, older sample must not move last_seen back
+	storagetest.Seed(t, db, storagetest.Series(service, "cpu_usage", end.Add(-time.Hour), time.Second, 39))
+	if lastSeen, err = db.GetServiceLastSeen(ctx, service); err != nil {
+		t.Fatalf("GetServiceLastSeen: %v", err)
+	}
+	if !lastSeen.Equal(end) {
+		t.Errorf("last_seen after an older sample = %v, want %v", lastSeen, end)
+	}
+}
+
+func TestSaveMetricRegistersService(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+
+	ts := time.Now().Truncate(time.Second)
+	if err := db.SaveMetric(ctx, &storage.Metric{Timestamp: ts, ServiceName: service, MetricName: "memory_usage", MetricValue: 55}); err != nil {
+		t.Fatalf("SaveMetric: %v", err)
+	}
+
+	lastSeen, err := db.GetServiceLastSeen(ctx, service)
+	if err != nil {
+		t.Fatalf("GetServiceLastSeen: %v", err)
+	}
+	if !lastSeen.Equal(ts) {
+		t.Errorf("last_seen = %v, want %v", lastSeen, ts)
+	}
+}
+
+func TestPodMetricsDoNotRegisterServices(t *testing.T) {
+	db := storagetest.NewClient(t)
+	pod := storagetest.Service(t, db) + "-7d9f8c-x2k4q"
+	ctx := context.Background()
+	t.Cleanup(func() {
+		if _, err := db.PurgeService(context.Background(), pod); err != nil {
+			t.Errorf("failed to purge %s: %v", pod, err)
+		}
+	})
+
+	ts := time.Now().Truncate(time.Second)
+	if err := db.SaveMetric(ctx, &storage.Metric{Timestamp: ts, ServiceName: pod, MetricName: "pod_status", MetricValue: 1}); err != nil {
+		t.Fatalf("SaveMetric: %v", err)
+	}
+	storagetest.Seed(t, db, storagetest.Series(pod, "pod_restarts", ts, 15*time.Second, 0, 0, 1))
+
+	services, err := db.GetAllServices(ctx, 0)
+	if err != nil {
+		t.Fatalf("GetAllServices: %v", err)
+	}
+	if slices.Contains(services, pod) {
+		t.Errorf("GetAllServices includes pod %s, want pods left out of the registry", pod)
+	}
+	if lastSeen, err := db.GetServiceLastSeen(ctx, pod); err != nil || !lastSeen.IsZero() {
+		t.Errorf("GetServiceLastSeen(pod) = %v, %v; want the zero time", lastSeen, err)
+	}
+}
+
+func TestInitScriptLeavesPodsOutOfRegistry(t *testing.T) {
+	db := storagetest.NewClient(t)
+	pod := storagetest.Service(t, db) + "-7d9f8c-x2k4q"
+	ctx := context.Background()
+	t.Cleanup(func() {
+		if _, err := db.PurgeService(context.Background(), pod); err != nil {
+			t.Errorf("failed to purge %s: %v", pod, err)
+		}
+	})
+
+	script, err := os.ReadFile("../../scripts/init-db.sql")
+	if err != nil {
+		t.Fatalf("failed to read init-db.sql: %v", err)
+	}
+	conn, err := pgx.Connect(ctx, storagetest.URL(t))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer conn.Close(ctx)
+
+	// A pod registered before pod metrics were kept out of the registry
+	ts := time.Now().Truncate(time.Second)
+	storagetest.Seed(t, db, storagetest.Series(pod, "pod_cpu_usage", ts, 15*time.Second, 20, 25, 30))
+	if _, err := conn.Exec(ctx, "INSERT INTO services (service_name, first_seen, last_seen) VALUES ($1, $2, $2)", pod, ts); err != nil {
+		t.Fatalf("failed to register pod: %v", err)
+	}
+
+	// The script runs on every startup
+	if _, err := conn.Exec(ctx, string(script)); err != nil {
+		t.Fatalf("init-db.sql: %v", err)
+	}
+
+	services, err := db.GetAllServices(ctx, 0)
+	if err != nil {
+		t.Fatalf("GetAllServices: %v", err)
+	}
+	if slices.Contains(services, pod) {
+		t.Errorf("GetAllServices includes pod %s after init-db.sql, want pods left out of the registry", pod)
+	}
+}
+
+func TestGetServiceLastSeenUnknownService(t *testing.T) {
+	db := storagetest.NewClient(t)
+
+	lastSeen, err := db.GetServiceLastSeen(context.Background(), "test-never-registered")
+	if err != nil {
+		t.Fatalf("GetServiceLastSeen: %v", err)
+	}
+	if !lastSeen.IsZero() {
+		t.Errorf("last_seen = %v, want the zero time", lastSeen)
+	}
+}
+
+func TestGetServicesByGroup(t *testing.T) {
+	db := storagetest.NewClient(t)
+	labelled := storagetest.Service(t, db)
+	unlabelled := storagetest.Service(t, db)
+	ctx := context.Background()
+	group := labelled + "-group" // unique to this run
+
+	end := time.Now().Add(-time.Minute).Truncate(time.Second)
+	metrics := storagetest.Series(labelled, "cpu_usage", end, 15*time.Second, 40, 42)
+	metrics[1].Labels = json.RawMessage(`{"group":"` + group + `"}`)
+	storagetest.Seed(t, db, metrics)
+	storagetest.Seed(t, db, storagetest.Series(unlabelled, "cpu_usage", end, 15*time.Second, 40))
+
+	services, err := db.GetServicesByGroup(ctx, group)
+	if err != nil {
+		t.Fatalf("GetServicesByGroup: %v", err)
+	}
+	if !slices.Equal(services, []string{labelled}) {
+		t.Errorf("GetServicesByGroup = %v, want [%s]", services, labelled)
+	}
+
+	// A later sample without the label keeps the service in its group
+	storagetest.Seed(t, db, storagetest.Series(labelled, "cpu_usage", end.Add(time.Second), time.Second, 43))
+	if services, err = db.GetServicesByGroup(ctx, group); err != nil || !slices.Equal(services, []string{labelled}) {
+		t.Errorf("after an unlabelled sample = %v, %v; want [%s]", services, err, labelled)
+	}
+}
+
+func TestPurgeServiceRemovesPodEvents(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+	now := time.Now().Add(-time.Minute).Truncate(time.Second)
+
+	// other shares the service's prefix without being one of its pods
+	other := service + "x"
+	t.Cleanup(func() {
+		if _, err := db.PurgeService(context.Background(), other); err != nil {
+			t.Errorf("PurgeService: %v", err)
+		}
+	})
+
+	storagetest.Seed(t, db, storagetest.Series(service, "cpu_usage", now, 15*time.Second, 40, 42))
+	if err := db.SaveHealthPoint(ctx, service, storage.HealthPoint{Timestamp: now, HealthScore: 80}); err != nil {
+		t.Fatalf("SaveHealthPoint: %v", err)
+	}
+	saveEvent(t, db, storage.EventAnomaly, service, now)
+	saveEvent(t, db, storage.EventOOMKilled, service+"-7d9f-abc12", now)
+	saveEvent(t, db, storage.EventOOMKilled, other+"-7d9f-def34", now)
+	eventTypes := []string{storage.EventAnomaly, storage.EventOOMKilled}
+
+	removed, err := db.PurgeService(ctx, service)
+	if err != nil {
+		t.Fatalf("PurgeService: %v", err)
+	}
+	for table, want := range map[string]int64{"metrics": 2, "events": 2, "health_history": 1, "services": 1} {
+		if removed[table] != want {
+			t.Errorf("removed[%s] = %d, want %d", table, removed[table], want)
+		}
+	}
+
+	events, err := db.GetServiceEvents(ctx, service, eventTypes, now.Add(-time.Hour), now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetServiceEvents: %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("events left after purge: %v", eventPods(events))
+	}
+	if events, err = db.GetServiceEvents(ctx, other, eventTypes, now.Add(-time.Hour), now.Add(time.Hour)); err != nil {
+		t.Fatalf("GetServiceEvents: %v", err)
+	}
+	if got := eventPods(events); !slices.Equal(got, []string{other + "-7d9f-def34"}) {
+		t.Errorf("events of %s = %v, want its pod's event kept", other, got)
+	}
+
+	lastSeen, err := db.GetServiceLastSeen(ctx, service)
+	if err != nil {
+		t.Fatalf("GetServiceLastSeen: %v", err)
+	}
+	if !lastSeen.IsZero() {
+		t.Errorf("last_seen after purge = %v, want the service unregistered", lastSeen)
+	}
+}
+
+func TestPurgeServiceRemovesLegacyDecisions(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+	now := time.Now().Add(-time.Minute).Truncate(time.Second)
+
+	saveDecision(t, db, service, "RESTART", now, true)
+	// Written before decisions had a service_name column
+	params, _ := json.Marshal(map[string]string{"service": service})
+	legacy := &storage.Decision{
+		Timestamp:       now,
+		PatternDetected: "MEMORY_LEAK",
+		ActionType:      "RESTART",
+		Confidence:      80,
+		Reason:          "test decision",
+		Parameters:      params,
+		Executed:        true,
+	}
+	if err := db.SaveDecision(ctx, legacy); err != nil {
+		t.Fatalf("SaveDecision: %v", err)
+	}
+
+	removed, err := db.PurgeService(ctx, service)
+	if err != nil {
+		t.Fatalf("PurgeService: %v", err)
+	}
+	if removed["decisions"] != 2 {
+		t.Errorf("removed[decisions] = %d, want both the current and the legacy decision", removed["decisions"])
+	}
+}
+
+func TestPurgeServiceKeepsLongerServicesPods(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+	now := time.Now().Add(-time.Minute).Truncate(time.Second)
+
+	// gateway's pods start with "<service>-" too, like api and api-gateway
+	gateway := service + "-gateway"
+	t.Cleanup(func() {
+		if _, err := db.PurgeService(context.Background(), gateway); err != nil {
+			t.Errorf("PurgeService: %v", err)
+		}
+	})
+
+	storagetest.Seed(t, db, storagetest.Series(service, "cpu_usage", now, 15*time.Second, 40))
+	storagetest.Seed(t, db, storagetest.Series(gateway, "cpu_usage", now, 15*time.Second, 30))
+	saveEvent(t, db, storage.EventOOMKilled, service+"-7d9f-abc12", now)
+	saveEvent(t, db, storage.EventAnomaly, gateway, now)
+	saveEvent(t, db, storage.EventOOMKilled, gateway+"-5c6d-def34", now)
+	eventTypes := []string{storage.EventAnomaly, storage.EventOOMKilled}
+
+	removed, err := db.PurgeService(ctx, service)
+	if err != nil {
+		t.Fatalf("PurgeService: %v", err)
+	}
+	if removed["events"] != 1 {
+		t.Errorf("removed[events] = %d, want only %s's pod event", removed["events"], service)
+	}
+
+	events, err := db.GetServiceEvents(ctx, gateway, eventTypes, now.Add(-time.Hour), now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetServiceEvents: %v", err)
+	}
+	got := eventPods(events)
+	slices.Sort(got)
+	if want := []string{gateway, gateway + "-5c6d-def34"}; !slices.Equal(got, want) {
+		t.Errorf("events of %s after purging %s = %v, want %v", gateway, service, got, want)
+	}
+}
+
+func TestPurgeServiceEscapesWildcards(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+	now := time.Now().Add(-time.Minute).Truncate(time.Second)
+
+	// "_" would match any character in an unescaped LIKE pattern
+	saveEvent(t, db, storage.EventOOMKilled, service+"-a-7d9f", now)
+	removed, err := db.PurgeService(ctx, service+"_a")
+	if err != nil {
+		t.Fatalf("PurgeService: %v", err)
+	}
+	if removed["events"] != 0 {
+		t.Errorf("purging %s_a removed %d events, want the pods of %s-a kept", service, removed["events"], service)
+	}
+}
@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// FeatureSnapshot is a periodic record of a service's key features, kept to
+// spot baselines drifting over weeks
+type FeatureSnapshot struct {
+	ServiceName string             `json:"service_name"`
+	Timestamp   time.Time          `json:"timestamp"`
+	Features    map[string]float64 `json:"features"`
+}
+
+// SaveFeatureSnapshot records one snapshot
+func (c *PostgresClient) SaveFeatureSnapshot(ctx context.Context, s *FeatureSnapshot) error {
+	query := `
+		INSERT INTO feature_snapshots (service_name, timestamp, features)
+		VALUES ($1, $2, $3)
+	`
+
+	features, err := json.Marshal(s.Features)
+	if err != nil {
+		return fmt.Errorf("failed to marshal feature snapshot: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := c.pool.Exec(ctx, query, s.ServiceName, s.Timestamp, features); err != nil {
+		return fmt.Errorf("failed to save feature snapshot: %w", err)
+	}
+	return nil
+}
+
+// GetFeatureSnapshots returns a service's snapshots taken since the given
+// time, oldest first
+func (c *PostgresClient) GetFeatureSnapshots(ctx context.Context, serviceName string, since time.Time) ([]*FeatureSnapshot, error) {
+	query := `
+		SELECT service_name, timestamp, features
+		FROM feature_snapshots
+		WHERE service_name = $1 AND timestamp >= $2
+		ORDER BY timestamp ASC
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := c.queryPool(ctx).Query(ctx, query, serviceName, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query feature snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	return scanFeatureSnapshots(rows)
+}
+
+// GetFeatureSnapshotsBetween returns every service's snapshots taken in
+// [from, to), oldest first
+func (c *PostgresClient) GetFeatureSnapshotsBetween(ctx context.Context, from, to time.Time) ([]*FeatureSnapshot, error) {
+	query := `
+		SELECT service_name, timestamp, features
+		FROM feature_snapshots
+		WHERE timestamp >= $1 AND timestamp < $2
+		ORDER BY timestamp ASC
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	rows, err := c.queryPool(ctx).Query(ctx, query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query feature snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	return scanFeatureSnapshots(rows)
+}
+
+func scanFeatureSnapshots(rows pgx.Rows) ([]*FeatureSnapshot, error) {
+	var snapshots []*FeatureSnapshot
+	for rows.Next() {
+		s := &FeatureSnapshot{}
+		var features []byte
+		if err := rows.Scan(&s.ServiceName, &s.Timestamp, &features); err != nil {
+			return nil, fmt.Errorf("failed to scan feature snapshot: %w", err)
+		}
+		if err := json.Unmarshal(features, &s.Features); err != nil {
+			return nil, fmt.Errorf("failed to decode feature snapshot: %w", err)
+		}
+		snapshots = append(snapshots, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating feature snapshots: %w", err)
+	}
+	return snapshots, nil
+}
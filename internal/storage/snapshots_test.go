@@ -0,0 +1,42 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+)
+
+func TestFeatureSnapshotsRoundTrip(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	for i, at := range []time.Time{now.Add(-20 * 24 * time.Hour), now.Add(-2 * time.Hour), now.Add(-time.Hour)} {
+		err := db.SaveFeatureSnapshot(ctx, &storage.FeatureSnapshot{
+			ServiceName: service,
+			Timestamp:   at,
+			Features:    map[string]float64{"memory_mean": 40 + float64(i)},
+		})
+		if err != nil {
+			t.Fatalf("SaveFeatureSnapshot: %v", err)
+		}
+	}
+
+	snapshots, err := db.GetFeatureSnapshots(ctx, service, now.Add(-14*24*time.Hour))
+	if err != nil {
+		t.Fatalf("GetFeatureSnapshots: %v", err)
+	}
+	if len(snapshots) != 2 {
+		t.Fatalf("got %d snapshots, want the 2 within the period", len(snapshots))
+	}
+	if !snapshots[0].Timestamp.Before(snapshots[1].Timestamp) {
+		t.Error("snapshots are not ordered oldest first")
+	}
+	if snapshots[0].Features["memory_mean"] != 41 || snapshots[1].ServiceName != service {
+		t.Errorf("snapshots = %+v %+v, want the stored features back", snapshots[0], snapshots[1])
+	}
+}
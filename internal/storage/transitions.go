@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// StatusTransition records a change in a service's diagnosed severity
+type StatusTransition struct {
+	ID             int64     `json:"id"`
+	ServiceName    string    `json:"service_name"`
+	OldSeverity    string    `json:"old_severity"`
+	NewSeverity    string    `json:"new_severity"`
+	ProblemType    string    `json:"problem_type"`
+	HealthScore    float64   `json:"health_score"`
+	PredictionID   string    `json:"prediction_id,omitempty"`
+	TransitionedAt time.Time `json:"transitioned_at"`
+}
+
+// SaveStatusTransition inserts a transition and sets its ID
+func (c *PostgresClient) SaveStatusTransition(ctx context.Context, t *StatusTransition) error {
+	query := `
+		INSERT INTO status_transitions (
+			service_name, old_severity, new_severity, problem_type,
+			health_score, prediction_id, transitioned_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err := c.pool.QueryRow(ctx, query,
+		t.ServiceName,
+		t.OldSeverity,
+		t.NewSeverity,
+		t.ProblemType,
+		t.HealthScore,
+		t.PredictionID,
+		t.TransitionedAt,
+	).Scan(&t.ID)
+	if err != nil {
+		return fmt.Errorf("failed to save status transition: %w", err)
+	}
+	return nil
+}
+
+// GetStatusTransitions returns transitions, newest first. An empty service
+// matches every service.
+func (c *PostgresClient) GetStatusTransitions(ctx context.Context, service string, limit int) ([]*StatusTransition, error) {
+	query := `
+		SELECT id, service_name, old_severity, new_severity, problem_type,
+		       COALESCE(health_score, 0), COALESCE(prediction_id, ''), transitioned_at
+		FROM status_transitions
+		WHERE ($1 = '' OR service_name = $1)
+		ORDER BY transitioned_at DESC
+		LIMIT $2
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := c.queryPool(ctx).Query(ctx, query, service, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query status transitions: %w", err)
+	}
+	defer rows.Close()
+
+	var transitions []*StatusTransition
+	for rows.Next() {
+		var t StatusTransition
+		if err := rows.Scan(
+			&t.ID,
+			&t.ServiceName,
+			&t.OldSeverity,
+			&t.NewSeverity,
+			&t.ProblemType,
+			&t.HealthScore,
+			&t.PredictionID,
+			&t.TransitionedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan status transition: %w", err)
+		}
+		transitions = append(transitions, &t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating status transitions: %w", err)
+	}
+
+	return transitions, nil
+}
+
+// GetCurrentSeverities returns the severity each service last transitioned to
+func (c *PostgresClient) GetCurrentSeverities(ctx context.Context) (map[string]string, error) {
+	query := `
+		SELECT DISTINCT ON (service_name) service_name, new_severity
+		FROM status_transitions
+		ORDER BY service_name, transitioned_at DESC
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := c.queryPool(ctx).Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query current severities: %w", err)
+	}
+	defer rows.Close()
+
+	severities := make(map[string]string)
+	for rows.Next() {
+		var service, severity string
+		if err := rows.Scan(&service, &severity); err != nil {
+			return nil, fmt.Errorf("failed to scan current severity: %w", err)
+		}
+		severities[service] = severity
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating current severities: %w", err)
+	}
+
+	return severities, nil
+}
@@ -0,0 +1,261 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// UltimateDiagnosisRecord is the persisted form of an analyzer.UltimateDiagnosis.
+// JSON-shaped parts are kept as raw values so storage doesn't depend on the analyzer.
+type UltimateDiagnosisRecord struct {
+	PredictionID        string
+	ServiceName         string
+	Timestamp           time.Time
+	AnalysisDuration    time.Duration
+	Features            interface{}
+	PrimaryProblem      string
+	PrimaryDetected     bool
+	PrimaryConfidence   float64
+	PrimarySeverity     string
+	PrimaryEvidence     map[string]interface{}
+	AllDetections       interface{}
+	HealthScore         float64
+	StabilityIndex      float64
+	PredictabilityScore float64
+	SystemStress        float64
+	RiskLevel           string
+	ActionRequired      bool
+	PredictiveInsights  []string
+	Recommendation      string
+	DetectorVersion     string
+
+	// Diagnosis is the complete diagnosis document. On save it is marshalled
+	// as-is; on load it holds the raw JSON (json.RawMessage).
+	Diagnosis interface{}
+}
+
+// SaveUltimateDiagnosis records a diagnosis keyed by its prediction ID
+func (c *PostgresClient) SaveUltimateDiagnosis(ctx context.Context, record *UltimateDiagnosisRecord) error {
+	featuresJSON, err := json.Marshal(record.Features)
+	if err != nil {
+		return fmt.Errorf("failed to marshal features: %w", err)
+	}
+	evidenceJSON, err := json.Marshal(record.PrimaryEvidence)
+	if err != nil {
+		return fmt.Errorf("failed to marshal primary evidence: %w", err)
+	}
+	detectionsJSON, err := json.Marshal(record.AllDetections)
+	if err != nil {
+		return fmt.Errorf("failed to marshal detections: %w", err)
+	}
+	insightsJSON, err := json.Marshal(record.PredictiveInsights)
+	if err != nil {
+		return fmt.Errorf("failed to marshal predictive insights: %w", err)
+	}
+	diagnosisJSON, err := json.Marshal(record.Diagnosis)
+	if err != nil {
+		return fmt.Errorf("failed to marshal diagnosis: %w", err)
+	}
+
+	query := `
+		INSERT INTO ultimate_diagnoses (
+			service_name, timestamp, analysis_duration, features,
+			primary_problem, primary_detected, primary_confidence, primary_severity, primary_evidence,
+			all_detections, health_score, stability_index, predictability_score, system_stress,
+			risk_level, action_required, predictive_insights, recommendation, prediction_id, diagnosis,
+			detector_version
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
+		ON CONFLICT (prediction_id) DO NOTHING
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err = c.pool.Exec(ctx, query,
+		record.ServiceName,
+		record.Timestamp,
+		record.AnalysisDuration.Milliseconds(),
+		featuresJSON,
+		record.PrimaryProblem,
+		record.PrimaryDetected,
+		record.PrimaryConfidence,
+		record.PrimarySeverity,
+		evidenceJSON,
+		detectionsJSON,
+		record.HealthScore,
+		record.StabilityIndex,
+		record.PredictabilityScore,
+		record.SystemStress,
+		record.RiskLevel,
+		record.ActionRequired,
+		insightsJSON,
+		record.Recommendation,
+		record.PredictionID,
+		diagnosisJSON,
+		record.DetectorVersion,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save ultimate diagnosis: %w", err)
+	}
+
+	return nil
+}
+
+// GetUltimateDiagnosisByID loads a diagnosis by prediction ID. It returns nil, nil
+// when no diagnosis with that ID exists.
+func (c *PostgresClient) GetUltimateDiagnosisByID(ctx context.Context, predictionID string) (*UltimateDiagnosisRecord, error) {
+	query := `
+		SELECT prediction_id, service_name, timestamp, COALESCE(analysis_duration, 0),
+		       COALESCE(primary_problem, ''), COALESCE(primary_detected, false),
+		       COALESCE(primary_confidence, 0), COALESCE(primary_severity, ''),
+		       COALESCE(health_score, 0), COALESCE(stability_index, 0),
+		       COALESCE(predictability_score, 0), COALESCE(system_stress, 0),
+		       COALESCE(risk_level, ''), COALESCE(action_required, false),
+		       COALESCE(recommendation, ''), COALESCE(detector_version, ''), diagnosis
+		FROM ultimate_diagnoses
+		WHERE prediction_id = $1
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var record UltimateDiagnosisRecord
+	var durationMs int64
+	var diagnosisJSON []byte
+
+	err := c.queryPool(ctx).QueryRow(ctx, query, predictionID).Scan(
+		&record.PredictionID,
+		&record.ServiceName,
+		&record.Timestamp,
+		&durationMs,
+		&record.PrimaryProblem,
+		&record.PrimaryDetected,
+		&record.PrimaryConfidence,
+		&record.PrimarySeverity,
+		&record.HealthScore,
+		&record.StabilityIndex,
+		&record.PredictabilityScore,
+		&record.SystemStress,
+		&record.RiskLevel,
+		&record.ActionRequired,
+		&record.Recommendation,
+		&record.DetectorVersion,
+		&diagnosisJSON,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get ultimate diagnosis: %w", err)
+	}
+
+	record.AnalysisDuration = time.Duration(durationMs) * time.Millisecond
+	if len(diagnosisJSON) > 0 {
+		record.Diagnosis = json.RawMessage(diagnosisJSON)
+	}
+
+	return &record, nil
+}
+
+// DiagnosisSummary is a stored diagnosis without its features and detections
+type DiagnosisSummary struct {
+	PredictionID      string    `json:"prediction_id"`
+	ServiceName       string    `json:"service_name"`
+	Timestamp         time.Time `json:"timestamp"`
+	PrimaryProblem    string    `json:"primary_problem"`
+	PrimaryDetected   bool      `json:"primary_detected"`
+	PrimaryConfidence float64   `json:"primary_confidence"`
+	PrimarySeverity   string    `json:"primary_severity"`
+	HealthScore       float64   `json:"health_score"`
+	RiskLevel         string    `json:"risk_level"`
+	DetectorVersion   string    `json:"detector_version"` // empty for rows stored before versioning
+}
+
+// GetDiagnosisSummaries returns a service's stored diagnoses, newest first.
+// An empty version matches every detector version.
+func (c *PostgresClient) GetDiagnosisSummaries(ctx context.Context, serviceName, version string, limit int) ([]*DiagnosisSummary, error) {
+	query := `
+		SELECT prediction_id, service_name, timestamp,
+		       COALESCE(primary_problem, ''), COALESCE(primary_detected, false),
+		       COALESCE(primary_confidence, 0), COALESCE(primary_severity, ''),
+		       COALESCE(health_score, 0), COALESCE(risk_level, ''),
+		       COALESCE(detector_version, '')
+		FROM ultimate_diagnoses
+		WHERE service_name = $1
+		  AND ($2 = '' OR detector_version = $2)
+		ORDER BY timestamp DESC
+		LIMIT $3
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := c.queryPool(ctx).Query(ctx, query, serviceName, version, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query diagnoses: %w", err)
+	}
+	defer rows.Close()
+
+	return scanDiagnosisSummaries(rows)
+}
+
+// GetDiagnosisSummariesBetween returns a service's diagnoses stored in
+// [from, to], oldest first
+func (c *PostgresClient) GetDiagnosisSummariesBetween(ctx context.Context, serviceName string, from, to time.Time, limit int) ([]*DiagnosisSummary, error) {
+	query := `
+		SELECT prediction_id, service_name, timestamp,
+		       COALESCE(primary_problem, ''), COALESCE(primary_detected, false),
+		       COALESCE(primary_confidence, 0), COALESCE(primary_severity, ''),
+		       COALESCE(health_score, 0), COALESCE(risk_level, ''),
+		       COALESCE(detector_version, '')
+		FROM ultimate_diagnoses
+		WHERE service_name = $1
+		  AND timestamp >= $2 AND timestamp <= $3
+		ORDER BY timestamp ASC
+		LIMIT $4
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	rows, err := c.queryPool(ctx).Query(ctx, query, serviceName, from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query diagnoses: %w", err)
+	}
+	defer rows.Close()
+
+	return scanDiagnosisSummaries(rows)
+}
+
+func scanDiagnosisSummaries(rows pgx.Rows) ([]*DiagnosisSummary, error) {
+	var summaries []*DiagnosisSummary
+	for rows.Next() {
+		var s DiagnosisSummary
+		if err := rows.Scan(
+			&s.PredictionID,
+			&s.ServiceName,
+			&s.Timestamp,
+			&s.PrimaryProblem,
+			&s.PrimaryDetected,
+			&s.PrimaryConfidence,
+			&s.PrimarySeverity,
+			&s.HealthScore,
+			&s.RiskLevel,
+			&s.DetectorVersion,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan diagnosis: %w", err)
+		}
+		summaries = append(summaries, &s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating diagnoses: %w", err)
+	}
+
+	return summaries, nil
+}
@@ -0,0 +1,80 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage"
+	"github.com/namansh70747/AURA-Autonomous-Unified-Reliability-Automation-Platform/internal/storage/storagetest"
+)
+
+func TestGetDiagnosisSummariesByVersion(t *testing.T) {
+	db := storagetest.NewClient(t)
+	service := storagetest.Service(t, db)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	save := func(at time.Time, version string) string {
+		t.Helper()
+		record := &storage.UltimateDiagnosisRecord{
+			PredictionID:    uuid.NewString(),
+			ServiceName:     service,
+			Timestamp:       at,
+			PrimaryProblem:  "MEMORY_LEAK",
+			DetectorVersion: version,
+		}
+		if err := db.SaveUltimateDiagnosis(ctx, record); err != nil {
+			t.Fatalf("SaveUltimateDiagnosis: %v", err)
+		}
+		return record.PredictionID
+	}
+	legacy := save(now.Add(-3*time.Minute), "")
+	v1 := save(now.Add(-2*time.Minute), "1")
+	v2 := save(now.Add(-time.Minute), "2")
+
+	tests := []struct {
+		name    string
+		version string
+		limit   int
+		want    []string
+	}{
+		{"every version, newest first", "", 10, []string{v2, v1, legacy}},
+		{"one version", "1", 10, []string{v1}},
+		{"unknown version", "99", 10, nil},
+		{"limit", "", 2, []string{v2, v1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summaries, err := db.GetDiagnosisSummaries(ctx, service, tt.version, tt.limit)
+			if err != nil {
+				t.Fatalf("GetDiagnosisSummaries: %v", err)
+			}
+			var got []string
+			for _, s := range summaries {
+				got = append(got, s.PredictionID)
+				if tt.version != "" && s.DetectorVersion != tt.version {
+					t.Errorf("%s has version %q, want %q", s.PredictionID, s.DetectorVersion, tt.version)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("got %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+
+	record, err := db.GetUltimateDiagnosisByID(ctx, v2)
+	if err != nil {
+		t.Fatalf("GetUltimateDiagnosisByID: %v", err)
+	}
+	if record.DetectorVersion != "2" {
+		t.Errorf("loaded DetectorVersion = %q, want 2", record.DetectorVersion)
+	}
+}
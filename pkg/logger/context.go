@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the request ID stored in ctx, or "" if there is none
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// FromContext returns a logger annotated with the request ID in ctx so every
+// line logged while serving a request can be correlated.
+func FromContext(ctx context.Context) *zap.Logger {
+	if Log == nil {
+		return zap.NewNop()
+	}
+
+	// Undo the wrapper caller skip since callers use the returned logger directly
+	l := Log.WithOptions(zap.AddCallerSkip(-1))
+	if id := RequestID(ctx); id != "" {
+		l = l.With(zap.String("request_id", id))
+	}
+	return l
+}
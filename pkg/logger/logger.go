@@ -9,7 +9,25 @@ import (
 
 var Log *zap.Logger
 
-func Initialize(level string) error {
+// Initialize builds the global logger. format selects the encoder ("json" or
+// "console"; empty keeps the ENVIRONMENT-based default) and sampling enables
+// zap's sampler so hot debug paths can't flood the output.
+func Initialize(level, format string, sampling bool) error {
+	config := buildConfig(level, format, sampling)
+
+	var err error
+	Log, err = config.Build(
+		zap.AddCallerSkip(1),
+		zap.AddStacktrace(zapcore.ErrorLevel),
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func buildConfig(level, format string, sampling bool) zap.Config {
 	isDevelopment := os.Getenv("ENVIRONMENT") != "production"
 
 	var config zap.Config
@@ -22,6 +40,14 @@ func Initialize(level string) error {
 		config.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
 	}
 
+	switch format {
+	case "json":
+		config.Encoding = "json"
+		config.EncoderConfig.EncodeLevel = zapcore.LowercaseLevelEncoder
+	case "console":
+		config.Encoding = "console"
+	}
+
 	atomicLevel := zap.NewAtomicLevel()
 	switch level {
 	case "debug":
@@ -37,19 +63,20 @@ func Initialize(level string) error {
 	}
 	config.Level = atomicLevel
 
+	// Sample per message: the first 100 entries each second are kept, then every 100th
+	if sampling {
+		config.Sampling = &zap.SamplingConfig{
+			Initial:    100,
+			Thereafter: 100,
+		}
+	} else {
+		config.Sampling = nil
+	}
+
 	config.DisableCaller = false
 	config.DisableStacktrace = false
 
-	var err error
-	Log, err = config.Build(
-		zap.AddCallerSkip(1),
-		zap.AddStacktrace(zapcore.ErrorLevel),
-	)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return config
 }
 
 func Info(msg string, fields ...zap.Field) {
@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestBuildConfigEncoding(t *testing.T) {
+	tests := []struct {
+		name        string
+		environment string
+		format      string
+		want        string
+	}{
+		{name: "json in development", environment: "development", format: "json", want: "json"},
+		{name: "console in production", environment: "production", format: "console", want: "console"},
+		{name: "default in development", environment: "development", format: "", want: "console"},
+		{name: "default in production", environment: "production", format: "", want: "json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ENVIRONMENT", tt.environment)
+
+			config := buildConfig("info", tt.format, false)
+			if config.Encoding != tt.want {
+				t.Errorf("Encoding = %q, want %q", config.Encoding, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildConfigJSONUsesPlainLevels(t *testing.T) {
+	t.Setenv("ENVIRONMENT", "development")
+
+	config := buildConfig("info", "json", false)
+	buf, err := zapcore.NewJSONEncoder(config.EncoderConfig).EncodeEntry(zapcore.Entry{Level: zapcore.WarnLevel, Message: "m"}, nil)
+	if err != nil {
+		t.Fatalf("EncodeEntry: %v", err)
+	}
+
+	if got := buf.String(); !strings.Contains(got, `"warn"`) || strings.Contains(got, "\x1b[") {
+		t.Errorf("json entry = %s, want a plain \"warn\" level without color codes", got)
+	}
+}
+
+func TestBuildConfigSampling(t *testing.T) {
+	if config := buildConfig("info", "json", true); config.Sampling == nil {
+		t.Error("Sampling is nil with sampling enabled")
+	}
+	if config := buildConfig("info", "json", false); config.Sampling != nil {
+		t.Errorf("Sampling = %+v with sampling disabled, want nil", config.Sampling)
+	}
+}
+
+func TestBuildConfigLevel(t *testing.T) {
+	tests := map[string]zapcore.Level{
+		"debug":   zapcore.DebugLevel,
+		"warn":    zapcore.WarnLevel,
+		"error":   zapcore.ErrorLevel,
+		"unknown": zapcore.InfoLevel,
+	}
+
+	for level, want := range tests {
+		if got := buildConfig(level, "", false).Level.Level(); got != want {
+			t.Errorf("buildConfig(%q).Level = %v, want %v", level, got, want)
+		}
+	}
+}
@@ -11,6 +11,32 @@ CREATE TABLE IF NOT EXISTS metrics (
     labels JSONB,
     created_at TIMESTAMPTZ DEFAULT NOW()
 );
+-- Range of the samples averaged into a row when storage.write_resolution is
+-- set; NULL for raw samples
+ALTER TABLE metrics ADD COLUMN IF NOT EXISTS metric_min FLOAT;
+ALTER TABLE metrics ADD COLUMN IF NOT EXISTS metric_max FLOAT;
+
+-- Services registry (maintained from the metric write path)
+CREATE TABLE IF NOT EXISTS services (
+    service_name VARCHAR(100) PRIMARY KEY,
+    first_seen TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_seen TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    service_group VARCHAR(100) -- from the "group" metric label
+);
+
+ALTER TABLE services ADD COLUMN IF NOT EXISTS service_group VARCHAR(100);
+
+-- Pod metrics used to register every pod as a service; drop those entries
+DELETE FROM services s
+WHERE EXISTS (
+    SELECT 1 FROM metrics m
+    WHERE m.service_name = s.service_name
+      AND m.metric_name IN ('pod_status', 'pod_restarts', 'pod_cpu_usage', 'pod_memory_usage')
+) AND NOT EXISTS (
+    SELECT 1 FROM metrics m
+    WHERE m.service_name = s.service_name
+      AND m.metric_name NOT IN ('pod_status', 'pod_restarts', 'pod_cpu_usage', 'pod_memory_usage')
+);
 
 -- Events table (stores Kubernetes events)
 CREATE TABLE IF NOT EXISTS events (
@@ -33,9 +59,21 @@ CREATE TABLE IF NOT EXISTS decisions (
     reason TEXT,
     parameters JSONB,
     executed BOOLEAN DEFAULT FALSE,
+    executed_at TIMESTAMPTZ,
+    execution_result TEXT,
+    service_name VARCHAR(100),
+    outcome VARCHAR(20), -- recovery check: resolved, persists or worsened
+    outcome_checked_at TIMESTAMPTZ,
     created_at TIMESTAMPTZ DEFAULT NOW()
 );
 
+-- Upgrade path for databases created before execution tracking existed
+ALTER TABLE decisions ADD COLUMN IF NOT EXISTS executed_at TIMESTAMPTZ;
+ALTER TABLE decisions ADD COLUMN IF NOT EXISTS execution_result TEXT;
+ALTER TABLE decisions ADD COLUMN IF NOT EXISTS service_name VARCHAR(100);
+ALTER TABLE decisions ADD COLUMN IF NOT EXISTS outcome VARCHAR(20);
+ALTER TABLE decisions ADD COLUMN IF NOT EXISTS outcome_checked_at TIMESTAMPTZ;
+
 -- Diagnoses table (stores pattern analysis results)
 CREATE TABLE IF NOT EXISTS diagnoses (
     id SERIAL PRIMARY KEY,
@@ -84,15 +122,121 @@ CREATE TABLE IF NOT EXISTS ultimate_diagnoses (
     recommendation TEXT,
     
     -- Traceability
-    prediction_id VARCHAR(255) UNIQUE
+    prediction_id VARCHAR(255) UNIQUE,
+
+    -- Complete diagnosis document as returned by the API
+    diagnosis JSONB,
+
+    -- analyzer.DetectorVersion that produced the diagnosis
+    detector_version VARCHAR(50)
+);
+
+-- Upgrade path for databases created before the diagnosis column existed
+ALTER TABLE ultimate_diagnoses ADD COLUMN IF NOT EXISTS diagnosis JSONB;
+ALTER TABLE ultimate_diagnoses ADD COLUMN IF NOT EXISTS detector_version VARCHAR(50);
+
+-- Backtest runs (detector precision/recall over labeled cases)
+CREATE TABLE IF NOT EXISTS backtest_runs (
+    id BIGSERIAL PRIMARY KEY,
+    run_at TIMESTAMPTZ NOT NULL,
+    case_count INTEGER NOT NULL,
+    accuracy FLOAT NOT NULL,
+    macro_f1 FLOAT NOT NULL,
+    detector_version VARCHAR(50),
+    report JSONB NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+ALTER TABLE backtest_runs ADD COLUMN IF NOT EXISTS detector_version VARCHAR(50);
+
+-- Incidents (repeated diagnoses of one service+problem grouped together)
+CREATE TABLE IF NOT EXISTS incidents (
+    id VARCHAR(100) PRIMARY KEY,
+    service_name VARCHAR(100) NOT NULL,
+    problem_type VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    first_seen TIMESTAMPTZ NOT NULL,
+    last_seen TIMESTAMPTZ NOT NULL,
+    closed_at TIMESTAMPTZ,
+    occurrences INTEGER NOT NULL DEFAULT 1,
+    peak_severity VARCHAR(20) NOT NULL,
+    current_severity VARCHAR(20) NOT NULL,
+    last_prediction_id VARCHAR(255),
+    remediation_outcome VARCHAR(20), -- latest recovery check after a remediation
+    remediation_checked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS remediation_outcome VARCHAR(20);
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS remediation_checked_at TIMESTAMPTZ;
+
+-- Deployments (recorded by CI/CD so diagnoses can be lined up with releases)
+CREATE TABLE IF NOT EXISTS deployments (
+    id BIGSERIAL PRIMARY KEY,
+    service_name VARCHAR(100) NOT NULL,
+    version VARCHAR(100) NOT NULL,
+    description TEXT,
+    deployed_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Service severity transitions (edge-triggered audit trail)
+CREATE TABLE IF NOT EXISTS status_transitions (
+    id BIGSERIAL PRIMARY KEY,
+    service_name VARCHAR(100) NOT NULL,
+    old_severity VARCHAR(20) NOT NULL,
+    new_severity VARCHAR(20) NOT NULL,
+    problem_type VARCHAR(100) NOT NULL,
+    health_score FLOAT,
+    prediction_id VARCHAR(255),
+    transitioned_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Feature snapshots (periodic key features, for baseline drift over weeks)
+CREATE TABLE IF NOT EXISTS feature_snapshots (
+    id BIGSERIAL PRIMARY KEY,
+    service_name VARCHAR(100) NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    features JSONB NOT NULL
+);
+
+-- Health score of every live diagnosis, for trends and the health timeline
+CREATE TABLE IF NOT EXISTS health_history (
+    id BIGSERIAL PRIMARY KEY,
+    service_name VARCHAR(100) NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    health_score FLOAT NOT NULL
+);
+
+-- Daily reports (incident counts, MTTR, least healthy services, SLO burn)
+CREATE TABLE IF NOT EXISTS daily_reports (
+    report_date DATE PRIMARY KEY,
+    generated_at TIMESTAMPTZ NOT NULL,
+    report JSONB NOT NULL
+);
+
+-- Operator notes on a service, shown with its diagnoses until they expire
+CREATE TABLE IF NOT EXISTS service_notes (
+    id BIGSERIAL PRIMARY KEY,
+    service_name VARCHAR(100) NOT NULL,
+    note TEXT NOT NULL,
+    author VARCHAR(100),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ
 );
 
 -- Create indexes for performance
 CREATE INDEX IF NOT EXISTS idx_metrics_timestamp ON metrics(timestamp DESC);
 CREATE INDEX IF NOT EXISTS idx_metrics_service ON metrics(service_name);
 CREATE INDEX IF NOT EXISTS idx_metrics_composite ON metrics(service_name, metric_name, timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_services_last_seen ON services(last_seen DESC);
 CREATE INDEX IF NOT EXISTS idx_events_timestamp ON events(timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_events_type_timestamp ON events(event_type, timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_events_namespace_timestamp ON events(namespace, timestamp DESC, id DESC);
 CREATE INDEX IF NOT EXISTS idx_decisions_timestamp ON decisions(timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_decisions_action_type ON decisions(action_type, timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_decisions_unverified ON decisions(executed_at) WHERE executed AND outcome IS NULL;
 CREATE INDEX IF NOT EXISTS idx_diagnoses_service ON diagnoses(service_name);
 CREATE INDEX IF NOT EXISTS idx_diagnoses_timestamp ON diagnoses(timestamp DESC);
 CREATE INDEX IF NOT EXISTS idx_diagnoses_severity ON diagnoses(severity);
@@ -103,6 +247,18 @@ CREATE INDEX IF NOT EXISTS idx_ultimate_diagnoses_timestamp ON ultimate_diagnose
 CREATE INDEX IF NOT EXISTS idx_ultimate_diagnoses_risk ON ultimate_diagnoses(risk_level);
 CREATE INDEX IF NOT EXISTS idx_ultimate_diagnoses_action ON ultimate_diagnoses(action_required);
 CREATE INDEX IF NOT EXISTS idx_ultimate_diagnoses_prediction ON ultimate_diagnoses(prediction_id);
+CREATE INDEX IF NOT EXISTS idx_ultimate_diagnoses_problem ON ultimate_diagnoses(primary_problem);
+CREATE INDEX IF NOT EXISTS idx_services_group ON services(service_group);
+CREATE INDEX IF NOT EXISTS idx_ultimate_diagnoses_version ON ultimate_diagnoses(service_name, detector_version, timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_backtest_runs_run_at ON backtest_runs(run_at DESC);
+CREATE INDEX IF NOT EXISTS idx_incidents_status ON incidents(status, last_seen DESC);
+CREATE INDEX IF NOT EXISTS idx_incidents_service ON incidents(service_name, problem_type);
+CREATE INDEX IF NOT EXISTS idx_deployments_service_time ON deployments(service_name, deployed_at DESC);
+CREATE INDEX IF NOT EXISTS idx_status_transitions_service_time ON status_transitions(service_name, transitioned_at DESC);
+CREATE INDEX IF NOT EXISTS idx_feature_snapshots_service_time ON feature_snapshots(service_name, timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_health_history_service_time ON health_history(service_name, timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_health_history_time ON health_history(timestamp);
+CREATE INDEX IF NOT EXISTS idx_service_notes_service ON service_notes(service_name, created_at DESC);
 
 -- Create views for analytics
 CREATE OR REPLACE VIEW service_health_trends AS
@@ -140,10 +296,27 @@ INSERT INTO metrics (service_name, metric_name, metric_value, labels) VALUES
 ('sample-app', 'memory_usage_percent', 60.0, '{"pod": "sample-app-1"}')
 ON CONFLICT DO NOTHING;
 
+-- Backfill the registry from any metrics written before it existed, leaving
+-- out pods (storage.PodMetricNames)
+INSERT INTO services (service_name, first_seen, last_seen)
+SELECT service_name, MIN(timestamp), MAX(timestamp)
+FROM metrics
+WHERE metric_name NOT IN ('pod_status', 'pod_restarts', 'pod_cpu_usage', 'pod_memory_usage')
+GROUP BY service_name
+ON CONFLICT (service_name) DO UPDATE
+SET last_seen = GREATEST(services.last_seen, EXCLUDED.last_seen);
+
 COMMENT ON TABLE metrics IS 'Raw metrics from Prometheus';
+COMMENT ON TABLE services IS 'Registry of known services with first/last seen times';
 COMMENT ON TABLE events IS 'Kubernetes events from API';
 COMMENT ON TABLE decisions IS 'AURA autonomous decisions';
 COMMENT ON TABLE diagnoses IS 'Pattern analysis results (Phase 2)';
 COMMENT ON TABLE ultimate_diagnoses IS 'AI-level comprehensive diagnostic results (Phase 2.5)';
+COMMENT ON TABLE backtest_runs IS 'Detector backtest reports for comparing tuning runs';
+COMMENT ON TABLE incidents IS 'Repeated diagnoses of one service and problem grouped into incidents';
+COMMENT ON TABLE deployments IS 'Service deployments recorded by CI/CD';
+COMMENT ON TABLE status_transitions IS 'Changes in a service''s diagnosed severity';
+COMMENT ON TABLE feature_snapshots IS 'Periodic snapshots of key service features for drift analysis';
+COMMENT ON TABLE daily_reports IS 'Daily summaries of incidents, MTTR, service health and SLO burn';
 COMMENT ON VIEW service_health_trends IS 'Health trends over time for all services';
 COMMENT ON VIEW recent_critical_issues IS 'Recent critical/high severity issues requiring attention';